@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+
+	"serial-assistant/pkg/apikey"
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/audit"
+	"serial-assistant/pkg/share"
+)
+
+// apiKeysFile API 密钥文件名 (位于应用数据目录)
+const apiKeysFile = "apikeys.json"
+
+// auditFile 远程命令审计日志文件名，与会话抓包文件放在一起
+const auditFile = "remote-audit.jsonl"
+
+// CreatedAPIKey 新建的密钥，Secret 只在创建时返回一次
+type CreatedAPIKey struct {
+	Key    apikey.Key `json:"key"`
+	Secret string     `json:"secret"`
+}
+
+// loadAPIKeys 启动时加载 API 密钥
+func (a *App) loadAPIKeys() {
+	keys, err := apikey.Open(filepath.Join(appDataDir(), apiKeysFile))
+	if err != nil {
+		a.log("apikeys").Warn("api keys not loaded", "err", err)
+		return
+	}
+	a.apiKeys = keys
+}
+
+// authenticateKey 供共享服务器校验 API 密钥
+func (a *App) authenticateKey(secret string) (share.Principal, bool) {
+	if a.apiKeys == nil {
+		return share.Principal{}, false
+	}
+	k, ok := a.apiKeys.Authenticate(secret)
+	if !ok {
+		return share.Principal{}, false
+	}
+	return share.Principal{KeyID: k.ID, Name: k.Name, Control: k.Scope == apikey.ScopeControl}, true
+}
+
+// auditCommand 将观看者的每个请求写入审计日志
+func (a *App) auditCommand(v share.Viewer, req share.Request, err error) {
+	e := audit.Entry{Who: v.Name, Remote: v.Remote, KeyID: v.KeyID, Action: req.Type, Data: req.Data}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	a.recordAudit(e)
+}
+
+// recordAudit 将一条远程命令记录追加到审计日志；共享未开启时临时打开日志文件
+func (a *App) recordAudit(e audit.Entry) {
+	a.shareMutex.Lock()
+	defer a.shareMutex.Unlock()
+	var err error
+	if a.audit != nil {
+		err = a.audit.Record(e)
+	} else {
+		var log *audit.Log
+		if log, err = audit.Open(filepath.Join(captureDir(), auditFile)); err == nil {
+			err = log.Record(e)
+			log.Close()
+		}
+	}
+	if err != nil {
+		a.log("apikeys").Warn("audit log not written", "err", err)
+	}
+}
+
+// ListAPIKeys 返回全部 API 密钥 (不含明文)
+func (a *App) ListAPIKeys() []apikey.Key {
+	if a.apiKeys == nil {
+		return []apikey.Key{}
+	}
+	return a.apiKeys.List()
+}
+
+// CreateAPIKey 创建 API 密钥，scope 为 "read" 或 "control"
+// 远程调用方用它代替会话令牌连接共享服务器；control 密钥无需主机批准即可发送
+func (a *App) CreateAPIKey(name string, scope string) (CreatedAPIKey, error) {
+	if a.apiKeys == nil {
+		return CreatedAPIKey{}, apperr.New(apperr.CodeInternal, "api keys not loaded")
+	}
+	k, secret, err := a.apiKeys.Create(name, apikey.Scope(scope))
+	if errors.Is(err, apikey.ErrEmptyName) || errors.Is(err, apikey.ErrInvalidScope) {
+		return CreatedAPIKey{}, apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	if err != nil {
+		return CreatedAPIKey{}, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return CreatedAPIKey{Key: k, Secret: secret}, nil
+}
+
+// RevokeAPIKey 吊销 API 密钥并断开使用该密钥的连接
+func (a *App) RevokeAPIKey(id string) apperr.Result {
+	if a.apiKeys == nil {
+		return apperr.FromError(apperr.New(apperr.CodeNotFound, id))
+	}
+	if err := a.apiKeys.Revoke(id); err != nil {
+		code := apperr.CodeInternal
+		if errors.Is(err, apikey.ErrNotFound) {
+			code = apperr.CodeNotFound
+		}
+		return apperr.FromError(apperr.Wrap(code, err))
+	}
+	a.shareMutex.Lock()
+	srv := a.share
+	a.shareMutex.Unlock()
+	if srv != nil {
+		srv.DisconnectKey(id)
+	}
+	return apperr.OK()
+}
+
+// GetAuditLog 返回最近 limit 条远程命令记录 (limit <= 0 表示全部)，按时间从旧到新
+func (a *App) GetAuditLog(limit int) ([]audit.Entry, error) {
+	list, err := audit.Read(filepath.Join(captureDir(), auditFile), limit)
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return list, nil
+}
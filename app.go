@@ -2,16 +2,70 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net"
 	"os"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"serial-assistant/pkg/jlink"   // 引入刚才创建的包
+	"serial-assistant/pkg/apikey"
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/audit"
+	"serial-assistant/pkg/autologin"
+	"serial-assistant/pkg/banner"
+	"serial-assistant/pkg/bootloop"
+	"serial-assistant/pkg/bridge"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/buildhook"
+	"serial-assistant/pkg/capture"
+	"serial-assistant/pkg/console"
+	"serial-assistant/pkg/crashlog"
+	"serial-assistant/pkg/decoder"
+	"serial-assistant/pkg/deeplink"
+	"serial-assistant/pkg/diagnostics"
+	"serial-assistant/pkg/editorrpc"
+	"serial-assistant/pkg/escpos"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/filter"
+	"serial-assistant/pkg/framediff"
+	"serial-assistant/pkg/framestats"
+	"serial-assistant/pkg/fwdb"
+	"serial-assistant/pkg/gpsclock"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/identify"
+	"serial-assistant/pkg/instrument"
+	"serial-assistant/pkg/jlink" // 引入刚才创建的包
+	"serial-assistant/pkg/journal"
+	"serial-assistant/pkg/linecap"
+	"serial-assistant/pkg/notify"
+	"serial-assistant/pkg/operation"
+	"serial-assistant/pkg/payload"
+	"serial-assistant/pkg/pipeline"
+	"serial-assistant/pkg/plc"
+	"serial-assistant/pkg/ports"
+	"serial-assistant/pkg/powermeter"
+	"serial-assistant/pkg/profiler"
+	"serial-assistant/pkg/ratelimit"
+	"serial-assistant/pkg/reconnect"
+	"serial-assistant/pkg/redact"
+	"serial-assistant/pkg/retention"
+	"serial-assistant/pkg/rules"
+	"serial-assistant/pkg/sanitize"
+	"serial-assistant/pkg/scpi"
+	"serial-assistant/pkg/secrets"
+	"serial-assistant/pkg/series"
+	"serial-assistant/pkg/sessionvars"
+	"serial-assistant/pkg/share"
+	"serial-assistant/pkg/snippets"
+	"serial-assistant/pkg/timesync"
 	"serial-assistant/pkg/updater" // 引入更新模块
+	"serial-assistant/pkg/usage"
+	"serial-assistant/pkg/watchfolder"
+	"serial-assistant/pkg/wedge"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"go.bug.st/serial"
@@ -31,13 +85,23 @@ const (
 // App struct
 type App struct {
 	ctx          context.Context
+	bus          *eventbus.Bus
+	ops          *operation.Manager
+	pipelines    *pipeline.Registry   // 订阅总线的各处理阶段 (队列深度与耗时统计)
+	display      *ratelimit.Coalescer // 接收数据的界面刷新限速
+	sanitizer    *sanitize.Sanitizer  // 显示前清理 NUL、BOM 与不可打印字符
+	rxPool       *bufpool.Pool        // 读循环使用的池化接收缓冲区
 	mutex        sync.Mutex
 	connType     ConnectionType
 	isConnected  bool
 	readStopChan chan struct{}
+	writes       inflight // 已交给连接尚未返回的写入，Drain 先等待它们完成
 
 	// 串口资源
-	serialPort serial.Port
+	serialPort     serial.Port
+	serialPortName string
+	serialRS485    *ports.RS485Config // 生效的 RS-485 方向控制，Mode 为实际使用的方式 (nil 表示未开启)
+	serialOpts     SerialOptions      // 生效的串口参数，ReconfigureSerial 失败时据此恢复
 
 	// 网络资源
 	netConn     net.Conn       // 用于 TCP Client, active TCP Server conn
@@ -47,40 +111,598 @@ type App struct {
 
 	// RTT 资源
 	jlinkConn *jlink.JLinkWrapper
+
+	// 固件 ELF 中声明的 RTT 配置 (nil 表示未设置)
+	rttElfMutex sync.Mutex
+	rttElf      *jlink.ElfConfig
+
+	// 主 RTT 连接的探针选择
+	jlinkTargetMutex sync.Mutex
+	jlinkTarget      jlink.Target
+
+	// RTT 与串口的桥接 (nil 表示未启用)
+	bridgeMutex sync.Mutex
+	bridge      *bridge.Bridge
+	bridgeProbe string
+	bridgePort  string
+
+	// 调制解调器状态线采样 (lineStop 为 nil 表示未在采样)
+	lineMutex sync.Mutex
+	lineRec   *linecap.Recorder
+	lineStop  chan struct{}
+
+	// 连接期间的状态线变化通知 (modemStop 为 nil 表示未在检查)
+	modemMutex sync.Mutex
+	modemStop  chan struct{}
+
+	// 设备拔出后的自动重连 (reconnectCancel 为 nil 表示未在重连)
+	reconnectMutex  sync.Mutex
+	reconnectPolicy reconnect.Policy
+	reconnectCancel context.CancelFunc
+
+	// 本地使用统计，只保存在本机 (打开失败时为 nil，方法对 nil 安全)
+	usage *usage.Recorder
+
+	// 应用内部的结构化日志，最近的记录保存在 logRing 中 (应用内查看、写入诊断转储)
+	logRing  *diagnostics.Ring
+	logLevel slog.LevelVar
+	appLog   *slog.Logger
+
+	// 监视目录自动发送 (watchCancel 为 nil 表示未在监视)
+	watchMutex  sync.Mutex
+	watchCfg    watchfolder.Config
+	watchCancel context.CancelFunc
+
+	// 供构建脚本调用的本机接口 (hookServer 为 nil 表示未监听)
+	hookMutex  sync.Mutex
+	hookCfg    buildhook.Config
+	hookServer *buildhook.Server
+
+	// 试运行模式：跳过会改变目标状态的操作，只记录将要执行的内容
+	dryRun atomic.Bool
+
+	// 前端自动发送的停止条件 (autoSendStop 为 nil 表示未设置)
+	autoSendMutex sync.Mutex
+	autoSendStop  func() *AutomationStop
+
+	// 编辑器扩展使用的 JSON-RPC 接口 (editor 为 nil 表示未监听)
+	editorMutex sync.Mutex
+	editorCfg   editorrpc.Config
+	editor      *editorrpc.Server
+
+	// 串口插拔检测
+	portWatchMutex sync.Mutex
+	portWatcher    *ports.Watcher
+
+	// USB/串口功率计 (meterPort 为 nil 表示未打开)
+	meterMutex  sync.Mutex
+	meterPort   serial.Port
+	meterCfg    PowerMeterConfig
+	meterEnergy powermeter.Energy
+
+	// SWO PC 采样统计 (profStop 为 nil 表示未在采样)
+	profMutex  sync.Mutex
+	prof       *profiler.Profile
+	profStop   chan struct{}
+	profDone   chan struct{}
+	profStatus ProfileStatus
+
+	// J-Link 驱动日志设置及诊断面板保留的日志
+	jlinkLogMutex   sync.Mutex
+	jlinkLogEnabled bool
+	jlinkLogToFile  bool
+	jlinkLog        []jlink.LogEntry
+
+	// 与主连接并行读取的附加 RTT 探针 (按标签)
+	probesMutex sync.Mutex
+	probes      map[string]*rttProbe
+
+	// 最近接收数据的尾部缓存 (用于设备识别等需要回看接收内容的功能)
+	rxMutex sync.Mutex
+	rxTail  []byte
+	rxTotal uint64
+
+	// 最近一次设备识别结果
+	deviceFingerprint identify.Fingerprint
+
+	// 当前连接的抓包文件
+	captureMutex sync.Mutex
+	capture      *capture.Writer
+	captureID    string
+	captureMeta  capture.Meta
+	captureEnc   capture.EncryptionConfig // 新抓包文件的加密设置
+	capturePass  string                   // 本次运行输入的抓包口令，只保存在内存中
+
+	// 抓包的保留策略与加书签的会话
+	storageMutex sync.Mutex
+	retention    retention.Policy
+	bookmarks    map[string]bool
+
+	// 抓包文件的内存映射视图 (浏览/搜索)
+	viewMutex sync.Mutex
+	view      *capture.View
+	viewID    string
+	viewSize  int64
+
+	// 当前会话启用的协议解码器及连接后的协议检测
+	decoders          decoder.Set
+	detectMutex       sync.Mutex
+	detector          *decoder.Sampler
+	autoEnableDecoder bool
+	frameStats        *framestats.Collector
+	gaps              *decoder.GapDetector
+	lines             *decoder.LineAssembler
+	lineMode          atomic.Bool
+
+	// 解码帧的显示过滤 (nil 表示不过滤) 与差异模式 (nil 表示关闭)
+	filterMutex   sync.RWMutex
+	displayFilter *filter.Filter
+	differ        *framediff.Differ
+
+	// 对接收文本逐行匹配的规则引擎及其桌面通知
+	rules    *rules.Engine
+	notifier *notify.Notifier
+
+	// 发送内容片段库 (本地与 git 仓库同步) 与占位符展开
+	snippets *snippets.Library
+	payloads *payload.Expander
+
+	// 会话变量 ({{var.NAME}})，连接到其他对象 (varsTarget 变化) 时清空
+	vars       *sessionvars.Store
+	varsMutex  sync.Mutex
+	varsTarget string
+
+	// 从连接后最初的输出中提取会话标题与设备信息，提取窗口结束后记入固件版本库
+	banner        *banner.Extractor
+	firmwareMutex sync.Mutex
+	firmware      *fwdb.DB
+	firmwareTimer *time.Timer
+
+	// 崩溃输出检测及保存的崩溃报告
+	crash        *crashlog.Detector
+	crashMutex   sync.Mutex
+	crashReports *crashlog.Store
+
+	// 反复重启 (看门狗复位循环) 检测
+	bootLoop *bootloop.Detector
+
+	// 跨会话累计的健康计数 (浸泡测试据此采样) 及正在运行的浸泡测试
+	txTotal         atomic.Uint64
+	alertTotal      atomic.Uint64
+	crashTotal      atomic.Uint64
+	disconnectTotal atomic.Uint64
+	soakMutex       sync.Mutex
+	soakOp          *operation.Op
+
+	// 向设备下发主机时间的设置及定时发送
+	timeSyncMutex sync.Mutex
+	timeSync      timesync.Config
+	timeSyncOp    *operation.Op
+
+	// GPS 参考源 (NMEA + DCD 上的 PPS)，锁定后用于校正抓包时间戳
+	gpsMutex sync.Mutex
+	gpsPort  serial.Port
+	gpsName  string
+	gpsStop  chan struct{}
+	gpsClock *gpsclock.Clock
+
+	// 将接收数据模拟为键盘输入的键盘楔模式
+	wedge *wedge.Wedge
+
+	// 向 U-Boot、Zephyr、Linux 等控制台执行命令并等待提示符
+	console *console.Session
+
+	// 检测登录提示符自动登录，密码保存在系统钥匙串中
+	autoLogin *autologin.Watcher
+	secrets   secrets.Store
+
+	// SCPI 仪器会话、后台测量记录器及测量得到的时间序列
+	scpi             *scpi.Session
+	scpiMutex        sync.Mutex
+	scpiLogger       *operation.Op
+	scpiAutoIdentify bool
+	instrument       *instrument.Driver // 当前选择的电源/万用表配置，受 scpiMutex 保护
+	series           *series.Store
+
+	// ESC/POS 打印机实时状态查询
+	escpos *escpos.Prober
+
+	// PLC 串口协议主站 (Mewtocol、Host Link、MELSEC FX、Modbus ASCII)
+	plc *plc.Master
+
+	// 当前会话的报告数据 (会话结束时发送邮件)
+	reportMutex  sync.Mutex
+	reportStart  time.Time
+	reportConn   string
+	reportMeta   banner.Metadata
+	reportRxMark uint64
+	reportTx     uint64
+	reportAlerts []rules.Match
+
+	// 会话共享服务器，nil 表示未共享；远程命令的审计日志随共享打开
+	shareMutex sync.Mutex
+	share      *share.Server
+	sharePort  int
+
+	// 导出与共享时的脱敏规则，shareRedact 为共享会话收发方向的脱敏流 (未启用时为 nil)
+	redactMutex sync.Mutex
+	redactCfg   redact.Config
+	redactor    *redact.Redactor
+	shareRedact [2]*redact.Stream
+	audit       *audit.Log
+	apiKeys     *apikey.Store
+
+	// 会话状态日志及上次异常退出时留下的状态
+	journalMutex sync.Mutex
+	journal      *journal.Journal
+	recovered    *journal.State
+
+	// 通过链接或抓包文件关联收到、尚待用户确认的链接
+	deepLinkMutex    sync.Mutex
+	deepLink         *deeplink.Link // 尚未处理的链接
+	deepLinkReceiver *deeplink.Receiver
 }
 
+// rxTailSize 接收尾部缓存大小
+const rxTailSize = 4096
+
+// rxBufferSize 读循环每次读取的缓冲区大小
+const rxBufferSize = 4096
+
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{}
+	a := &App{bus: eventbus.New(), pipelines: pipeline.NewRegistry(), detector: decoder.NewSampler(detectSampleSize)}
+	a.logRing = diagnostics.NewRing(diagnostics.DefaultRingSize)
+	a.appLog = a.newAppLog()
+	a.pipelines.SetPanicHandler(a.savePanicDump)
+	a.frameStats = framestats.New()
+	a.gaps = decoder.NewGapDetector(defaultGapThreshold)
+	a.lines = decoder.NewLineAssembler(defaultLineTimeout, func(f decoder.Frame) {
+		a.bus.Publish(EventFrameDecoded, f)
+	})
+	a.notifier = notify.New(notifyInterval)
+	a.rules = a.newRulesEngine()
+	a.snippets = snippets.New()
+	a.payloads = payload.New()
+	a.vars = a.newSessionVars()
+	a.banner = banner.New()
+	a.crash = crashlog.New()
+	a.bootLoop = bootloop.New()
+	a.gpsClock = gpsclock.New()
+	a.lineRec = linecap.New(linecap.DefaultCapacity)
+	a.wedge = a.newWedge()
+	a.scpi = scpi.New(a.writeContext)
+	a.console = console.New(a.writeContext)
+	a.autoLogin = autologin.New()
+	a.secrets = secrets.System()
+	a.bookmarks = map[string]bool{}
+	a.series = series.New(series.DefaultCapacity)
+	a.escpos = escpos.NewProber(a.writeContext)
+	a.plc = plc.NewMaster(a.writeContext)
+	a.display = a.newDisplayLimiter()
+	a.sanitizer = sanitize.New()
+	a.rxPool = bufpool.New(rxBufferSize)
+	a.ops = operation.NewManager(func(p operation.Progress) {
+		a.bus.Publish(EventOperationProgress, p)
+	})
+	go a.trackRxTail(a.subscribeStage("rx-tail", rxTailQueueSize, EventSerialData))
+	go a.recordCapture(a.subscribeStage("capture", captureQueueSize, EventSerialData, EventDataSent))
+	go a.decodeFrames(a.subscribeStage("decoder", decoderQueueSize, EventSerialData))
+	go a.countFrames(a.subscribeStage("frame-stats", frameStatsQueueSize, EventFrameDecoded))
+	go a.applyRules(a.subscribeStage("rules", rulesQueueSize, EventSerialData))
+	go a.extractBanner(a.subscribeStage("banner", bannerQueueSize, EventSerialData))
+	go a.detectCrashes(a.subscribeStage("crash", crashQueueSize, EventSerialData))
+	go a.detectBootLoop(a.subscribeStage("bootloop", bootLoopQueueSize, EventSerialData))
+	go a.feedWedge(a.subscribeStage("wedge", wedgeQueueSize, EventSerialData))
+	go a.feedScpi(a.subscribeStage("scpi", scpiQueueSize, EventSerialData))
+	go a.feedConsole(a.subscribeStage("console", consoleQueueSize, EventSerialData))
+	go a.watchLogin(a.subscribeStage("auto-login", autoLoginQueueSize, EventSerialData))
+	go a.feedEscPos(a.subscribeStage("escpos", escposQueueSize, EventSerialData))
+	go a.feedPlc(a.subscribeStage("plc", plcQueueSize, EventSerialData))
+	go a.recordSeries(a.subscribeStage("series", seriesQueueSize, EventSeriesSample))
+	go a.forwardToViewers(a.subscribeStage("share", shareQueueSize, EventSerialData, EventDataSent, EventSysMsg))
+	go a.forwardToEditors(a.subscribeStage("editor", editorQueueSize, EventSerialData, EventDataSent, EventSysMsg, EventSerialError))
+	return a
 }
 
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	go a.forwardToFrontend(a.subscribeStage("frontend", frontendQueueSize))
+	a.openJournal()
+	a.listenDeepLinks()
+	a.loadRules()
+	a.loadBannerConfig()
+	a.loadFirmwareDB()
+	a.loadCrashReports()
+	a.loadBootLoopConfig()
+	a.loadTimeSync()
+	a.loadAPIKeys()
+	a.loadSnippets()
+	a.loadCaptureEncryption()
+	a.loadRedaction()
+	a.loadStorage()
+	a.loadReconnect()
+	a.loadUsage()
+	a.loadWatchFolder()
+	a.loadBuildHook()
+	a.loadEditorServer()
+	a.startPortWatch()
 }
 
-// 1. 获取串口列表
+func (a *App) shutdown(ctx context.Context) {
+	a.ops.CancelAll()
+	a.cancelReconnect()
+	a.stopWatchFolder()
+	a.stopBuildHook()
+	a.stopEditorServer()
+	a.stopPortWatch()
+	a.StopSharing()
+	a.closeDeepLinks()
+	a.CloseGpsReference()
+	a.ClosePowerMeter()
+	a.StopLineCapture()
+	a.StopRttBridge()
+	a.StopProfiler()
+	a.closeRttProbes()
+	a.closeJournal()
+	a.closeUsage()
+	a.bus.Close()
+}
+
+// 1. 获取串口列表 (按名称自然排序，包含 COM10 以上及被占用的串口)，详细信息见 ListPorts
 func (a *App) GetSerialPorts() ([]string, error) {
-	ports, err := serial.GetPortsList()
+	list, err := ports.List()
 	if err != nil {
 		return nil, err
 	}
-	if len(ports) == 0 {
-		return []string{}, nil
-	}
-	return ports, nil
+	return ports.Names(list), nil
+}
+
+// GetStandardBaudRates 返回常用波特率列表，OpenSerial 也接受范围内的任意速率
+func (a *App) GetStandardBaudRates() []int {
+	return ports.StandardBaudRates
 }
 
 // --- 连接逻辑封装 ---
 
+// SerialOptions 串口参数
+type SerialOptions struct {
+	Port        string `json:"port"`
+	BaudRate    int    `json:"baudRate"`
+	DataBits    int    `json:"dataBits"`
+	StopBits    int    `json:"stopBits"`              // 1、15 (1.5)、2
+	Parity      string `json:"parity"`                // None、Odd、Even、Mark、Space
+	FlowControl string `json:"flowControl,omitempty"` // none (默认)、rtscts
+
+	RS485 *ports.RS485Config `json:"rs485,omitempty"` // 半双工 RS-485 收发方向控制 (nil 表示不开启)
+}
+
+// validate 检查流控、波特率与 RS-485 设置；RS-485 方向控制占用 RTS，不能与硬件流控同时开启
+func (o SerialOptions) validate() error {
+	if !ports.ValidFlow(o.FlowControl) {
+		return apperr.New(apperr.CodeInvalidArgument, "flowControl: "+o.FlowControl)
+	}
+	if err := ports.ValidateBaudRate(o.BaudRate); err != nil {
+		return apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	if o.RS485 != nil {
+		if o.FlowControl == ports.FlowRTSCTS {
+			return apperr.New(apperr.CodeInvalidArgument, "rs485: conflicts with rtscts flow control")
+		}
+		if err := o.RS485.Validate(); err != nil {
+			return apperr.Wrap(apperr.CodeInvalidArgument, err)
+		}
+	}
+	return nil
+}
+
 // OpenSerial 打开串口
-func (a *App) OpenSerial(portName string, baudRate int, dataBits int, stopBits int, parityName string) string {
+func (a *App) OpenSerial(portName string, baudRate int, dataBits int, stopBits int, parityName string) apperr.Result {
+	return a.OpenSerialWithOptions(SerialOptions{
+		Port: portName, BaudRate: baudRate, DataBits: dataBits, StopBits: stopBits, Parity: parityName,
+	})
+}
+
+// OpenSerialWithOptions 按参数打开串口，可开启 RTS/CTS 硬件流控 (调制解调器等需要握手的设备)
+// 或 RS-485 方向控制 (没有自动收发切换的半双工适配器)；两者都由 RTS 控制，开启时不再在打开时拉高
+func (a *App) OpenSerialWithOptions(opts SerialOptions) apperr.Result {
+	if err := opts.validate(); err != nil {
+		return apperr.FromError(err)
+	}
+	portName, baudRate, dataBits, stopBits, parityName := opts.Port, opts.BaudRate, opts.DataBits, opts.StopBits, opts.Parity
+	rtscts := opts.FlowControl == ports.FlowRTSCTS
+
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
 	if a.isConnected {
-		return "Already connected"
+		return apperr.FromError(apperr.New(apperr.CodeAlreadyConnected, ""))
 	}
 
+	mode, custom := serialMode(baudRate, dataBits, stopBits, parityName)
+
+	port, err := a.openSerialContext(portName, mode)
+	if err != nil {
+		if apperr.CodeOf(err) == apperr.CodePermissionDenied {
+			a.publishPermissionHint(portName)
+		}
+		return apperr.FromError(err)
+	}
+
+	port.SetMode(mode)
+	if custom {
+		if err := applyCustomBaudRate(port, baudRate); err != nil {
+			port.Close()
+			return apperr.FromError(err)
+		}
+	}
+	port.SetDTR(true)
+	if rtscts {
+		if err := ports.SetFlowControl(port, true); err != nil {
+			port.Close()
+			if err == ports.ErrFlowUnsupported {
+				return apperr.FromError(apperr.Wrap(apperr.CodeUnsupported, err))
+			}
+			return apperr.FromError(apperr.Wrap(apperr.CodeOpenFailed, err))
+		}
+	} else if opts.RS485 == nil {
+		port.SetRTS(true)
+	}
+	if err := a.applyRS485Locked(port, opts.RS485); err != nil {
+		port.Close()
+		if err == ports.ErrRS485Unsupported {
+			return apperr.FromError(apperr.Wrap(apperr.CodeUnsupported, err))
+		}
+		return apperr.FromError(apperr.Wrap(apperr.CodeOpenFailed, err))
+	}
+
+	a.serialPort = port
+	a.serialPortName = portName
+	a.serialOpts = opts
+	a.connType = TypeSerial
+	a.onConnected(&journal.Connection{
+		Type: string(TypeSerial), Port: portName,
+		BaudRate: baudRate, DataBits: dataBits, StopBits: stopBits, Parity: parityName,
+		FlowControl: opts.FlowControl, RS485: opts.RS485,
+	})
+	a.startReadLoop(port) // 启动通用读取循环
+	a.startModemWatch(port)
+
+	return apperr.OK()
+}
+
+// ReconfigureSerial 不关闭串口直接修改波特率、数据位、停止位、校验、流控与 RS-485 方向控制，
+// 用于跟随运行中切换波特率的设备 (如 bootloader 切换到应用程序)；opts.Port 为空或须为当前串口
+// 某一步失败时恢复原来的设置，串口不会停留在部分生效的参数上
+func (a *App) ReconfigureSerial(opts SerialOptions) apperr.Result {
+	if err := opts.validate(); err != nil {
+		return apperr.FromError(err)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	port := a.serialPort
+	if !a.isConnected || a.connType != TypeSerial || port == nil {
+		return apperr.FromError(apperr.New(apperr.CodeNotConnected, ""))
+	}
+	if opts.Port != "" && opts.Port != a.serialPortName {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, "port: "+opts.Port))
+	}
+	opts.Port = a.serialPortName
+
+	prev := a.serialOpts
+	if err := a.configureSerialLocked(port, opts, prev); err != nil {
+		if rerr := a.configureSerialLocked(port, prev, opts); rerr != nil {
+			a.log("serial").Warn("serial settings not restored", "port", a.serialPortName, "err", rerr)
+		}
+		return apperr.FromError(err)
+	}
+	a.serialOpts = opts
+
+	if conn := a.currentConnection(); conn != nil {
+		updated := *conn
+		updated.BaudRate, updated.DataBits, updated.StopBits, updated.Parity = opts.BaudRate, opts.DataBits, opts.StopBits, opts.Parity
+		updated.FlowControl, updated.RS485 = opts.FlowControl, opts.RS485
+		a.recordConnection(&updated)
+	}
+	a.log("serial").Info("serial reconfigured", "port", a.serialPortName, "baud", opts.BaudRate,
+		"dataBits", opts.DataBits, "stopBits", opts.StopBits, "parity", opts.Parity, "flow", opts.FlowControl,
+		"rs485", a.rs485ModeLocked())
+	a.bus.Publish(EventSysMsg, i18n.T("app.serial_reconfigured", a.serialPortName, opts.BaudRate))
+	return apperr.OK()
+}
+
+// configureSerialLocked 将 opts 应用到已打开的串口，from 为串口当前的设置；调用方需持有 a.mutex
+func (a *App) configureSerialLocked(port serial.Port, opts, from SerialOptions) error {
+	rtscts := opts.FlowControl == ports.FlowRTSCTS
+	mode, custom := serialMode(opts.BaudRate, opts.DataBits, opts.StopBits, opts.Parity)
+	if custom {
+		// 先以接近的标准速率设置数据位等参数，不让运行中的串口切到 serialMode 的 9600 占位速率
+		mode.BaudRate = interimBaudRate(from.BaudRate, opts.BaudRate)
+	}
+	if err := port.SetMode(mode); err != nil {
+		return apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	if custom {
+		if err := applyCustomBaudRate(port, opts.BaudRate); err != nil {
+			return err
+		}
+	}
+	// 部分平台设置参数时会重置流控，按新设置重新应用；驱动的 RS-485 模式会覆盖流控设置，先关闭再按新设置开启
+	a.applyRS485Locked(port, nil)
+	if err := ports.SetFlowControl(port, rtscts); err != nil && (rtscts || err != ports.ErrFlowUnsupported) {
+		if err == ports.ErrFlowUnsupported {
+			return apperr.Wrap(apperr.CodeUnsupported, err)
+		}
+		return apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	if err := a.applyRS485Locked(port, opts.RS485); err != nil {
+		if err == ports.ErrRS485Unsupported {
+			return apperr.Wrap(apperr.CodeUnsupported, err)
+		}
+		return apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	if !rtscts && opts.RS485 == nil && (from.RS485 != nil || from.FlowControl == ports.FlowRTSCTS) {
+		port.SetRTS(true) // 关闭硬件流控或 RS-485 后与打开时一样拉高 RTS
+	}
+	return nil
+}
+
+// interimBaudRate 切换到非标准波特率前设置参数时使用的速率：当前速率为标准速率时保持不变，否则取最接近目标的标准速率
+func interimBaudRate(current, target int) int {
+	if ports.IsStandardBaudRate(current) {
+		return current
+	}
+	dist := func(b int) int {
+		if b > target {
+			return b - target
+		}
+		return target - b
+	}
+	best := ports.StandardBaudRates[0]
+	for _, b := range ports.StandardBaudRates {
+		if dist(b) < dist(best) {
+			best = b
+		}
+	}
+	return best
+}
+
+// applyRS485Locked 按 cfg 开启 RS-485 方向控制，cfg 为 nil 时关闭已开启的驱动模式；调用方需持有 a.mutex
+// 驱动的设置在关闭串口后仍然保留，断开连接前也需以 nil 调用
+func (a *App) applyRS485Locked(port serial.Port, cfg *ports.RS485Config) error {
+	if prev := a.serialRS485; prev != nil && prev.Mode == ports.RS485Native {
+		if err := ports.DisableRS485(port); err != nil {
+			a.log("serial").Warn("rs485 mode not disabled", "err", err)
+		}
+	}
+	a.serialRS485 = nil
+	if cfg == nil {
+		return nil
+	}
+	mode, err := ports.EnableRS485(port, *cfg)
+	if err != nil {
+		return err
+	}
+	active := *cfg
+	active.Mode = mode
+	a.serialRS485 = &active
+	a.log("serial").Info("rs485 enabled", "mode", mode)
+	return nil
+}
+
+// rs485ModeLocked 返回生效的 RS-485 方向控制方式 (未开启时为空)，调用方需持有 a.mutex
+func (a *App) rs485ModeLocked() string {
+	if a.serialRS485 == nil {
+		return ""
+	}
+	return a.serialRS485.Mode
+}
+
+// serialMode 将界面参数转换为 serial.Mode
+// 非标准波特率先以 9600 打开，custom 为 true 时需再由 applyCustomBaudRate 设置并回读确认
+func serialMode(baudRate, dataBits, stopBits int, parityName string) (mode *serial.Mode, custom bool) {
 	var parity serial.Parity
 	switch parityName {
 	case "None":
@@ -109,57 +731,140 @@ func (a *App) OpenSerial(portName string, baudRate int, dataBits int, stopBits i
 		stop = serial.OneStopBit
 	}
 
-	mode := &serial.Mode{
+	custom = !ports.IsStandardBaudRate(baudRate)
+	mode = &serial.Mode{
 		BaudRate: baudRate,
 		DataBits: dataBits,
 		Parity:   parity,
 		StopBits: stop,
 	}
+	if custom {
+		mode.BaudRate = 9600
+	}
+	return mode, custom
+}
 
-	port, err := serial.Open(portName, mode)
-	if err != nil {
-		return fmt.Sprintf("Error: %v", err)
+// applyCustomBaudRate 设置非标准波特率，驱动实际速率偏差过大时视为不支持
+func applyCustomBaudRate(port serial.Port, baud int) *apperr.Error {
+	if err := ports.SetBaudRate(port, baud); err != nil {
+		if err == ports.ErrBaudUnsupported {
+			return apperr.Wrap(apperr.CodeUnsupported, err)
+		}
+		return apperr.Wrap(apperr.CodeInvalidArgument, fmt.Errorf("baud rate %d: %w", baud, err))
+	}
+	got, err := ports.BaudRate(port)
+	if err == nil && !ports.BaudRateMatches(baud, got) {
+		return apperr.New(apperr.CodeInvalidArgument, fmt.Sprintf("baud rate %d not supported by driver (got %d)", baud, got))
 	}
+	return nil
+}
 
-	port.SetMode(mode)
-	port.SetDTR(true)
-	port.SetRTS(true)
+// openSerialContext 在可取消的操作中打开串口
+// serial.Open 本身无法中断，取消后若打开最终成功会立即关闭该串口
+func (a *App) openSerialContext(portName string, mode *serial.Mode) (serial.Port, error) {
+	op := a.startOperation("open-serial", serialOpenTimeout)
+	defer a.ops.Finish(op)
 
-	a.serialPort = port
-	a.connType = TypeSerial
-	a.startReadLoop(port) // 启动通用读取循环
+	type result struct {
+		port serial.Port
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		port, err := serial.Open(portName, mode)
+		done <- result{port, err}
+	}()
 
-	return "Success"
+	select {
+	case r := <-done:
+		if r.err != nil {
+			e := serialOpenError(r.err)
+			if e.Code == apperr.CodePortBusy {
+				a.explainPortBusy(portName, e)
+			}
+			return nil, e
+		}
+		return r.port, nil
+	case <-op.Context().Done():
+		go func() {
+			if r := <-done; r.err == nil {
+				r.port.Close()
+			}
+		}()
+		return nil, contextError(op.Context())
+	}
+}
+
+// serialOpenError 将串口库的错误转换为结构化错误
+func serialOpenError(err error) *apperr.Error {
+	var portErr *serial.PortError
+	if errors.As(err, &portErr) {
+		switch portErr.Code() {
+		case serial.PortBusy:
+			return apperr.Wrap(apperr.CodePortBusy, err)
+		case serial.PortNotFound:
+			return apperr.Wrap(apperr.CodePortNotFound, err)
+		case serial.PermissionDenied:
+			return apperr.Wrap(apperr.CodePermissionDenied, err)
+		case serial.InvalidSpeed, serial.InvalidDataBits, serial.InvalidParity, serial.InvalidStopBits, serial.InvalidSerialPort:
+			return apperr.Wrap(apperr.CodeInvalidArgument, err)
+		}
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return apperr.Wrap(apperr.CodePortNotFound, err)
+	}
+	return apperr.Wrap(apperr.CodeOpenFailed, err)
 }
 
 // OpenJLink 连接 RTT
-func (a *App) OpenJLink(chip string, speed int, iface string) string {
+func (a *App) OpenJLink(chip string, speed int, iface string) apperr.Result {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
 	if a.isConnected {
-		return "Already connected"
+		return apperr.FromError(apperr.New(apperr.CodeAlreadyConnected, ""))
 	}
 
 	// 定义日志回调函数，将日志发送到前端 RX Monitor
 	logCallback := func(message string) {
 		// 将日志消息作为字符串发送到前端
-		logData := []byte(message + "\n")
-		runtime.EventsEmit(a.ctx, "serial-data", logData)
+		a.publishRx(bufpool.Wrap([]byte(message + "\n")))
 	}
 
 	// 1. 加载驱动
 	jl, err := jlink.NewJLinkWrapper(logCallback)
 	if err != nil {
-		return err.Error()
+		return apperr.FromError(jlinkError(err))
 	}
+	a.applyJLinkTarget(jl)
+	a.applyRttElf(jl)
+	a.applyDLLLog(jl)
 
-	// 2. 连接芯片
-	err = jl.Connect(chip, speed, iface)
-	if err != nil {
-		// 连接失败需要释放资源
-		jl.Close()
-		return err.Error()
+	// 2. 连接芯片 (可通过 Cancel 取消)
+	op := a.startOperation("open-jlink", jlinkConnectTimeout)
+	defer a.ops.Finish(op)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- jl.ConnectContext(op.Context(), chip, speed, iface)
+	}()
+	select {
+	case err = <-done:
+		if err != nil {
+			// 连接失败需要释放资源
+			jl.Close()
+			if op.Context().Err() != nil {
+				return apperr.FromError(contextError(op.Context()))
+			}
+			return apperr.FromError(jlinkError(err))
+		}
+	case <-op.Context().Done():
+		// DLL 调用无法中断，等其返回后再释放资源
+		go func() {
+			<-done
+			jl.Close()
+		}()
+		return apperr.FromError(contextError(op.Context()))
 	}
 
 	a.jlinkConn = jl
@@ -167,14 +872,17 @@ func (a *App) OpenJLink(chip string, speed int, iface string) string {
 	a.isConnected = true
 	a.readStopChan = make(chan struct{})
 
+	a.onConnected(&journal.Connection{Type: string(TypeJLink), Chip: chip, Speed: speed, Interface: iface})
+
 	// 3. 启动 RTT 专用读取循环 (因为它的 API 不是 io.Reader 风格，而是轮询)
 	go a.jlinkReadLoop()
 
-	return "Success"
+	return apperr.OK()
 }
 
 // jlinkReadLoop 专用的 RTT 轮询循环
 func (a *App) jlinkReadLoop() {
+	defer a.recoverPanic("jlink-read-loop", func() { a.Close() })
 	ticker := time.NewTicker(10 * time.Millisecond) // 10ms 轮询一次
 	defer ticker.Stop()
 
@@ -203,30 +911,28 @@ func (a *App) jlinkReadLoop() {
 				consecutiveErrors++
 
 				// 检测是否是偏移量错误（STM32 复位导致）
-				errMsg := err.Error()
-				if consecutiveErrors == 1 && (strings.Contains(errMsg, "offset out of bounds") ||
-					strings.Contains(errMsg, "偏移量超出范围")) {
-					runtime.EventsEmit(a.ctx, "sys-msg", "[RTT] 检测到目标设备可能已复位，尝试重新连接...")
+				if consecutiveErrors == 1 && errors.Is(err, jlink.ErrOffsetOutOfBounds) {
+					a.bus.Publish(EventSysMsg, i18n.T("app.rtt_target_reset"))
 					// 尝试重新初始化 RTT
 					if reinitErr := jl.ReinitSoftRTT(); reinitErr == nil {
-						runtime.EventsEmit(a.ctx, "sys-msg", "[RTT] RTT 重新初始化成功")
+						a.bus.Publish(EventSysMsg, i18n.T("app.rtt_reinit_ok"))
 						consecutiveErrors = 0
 						continue
 					} else {
-						runtime.EventsEmit(a.ctx, "sys-msg", fmt.Sprintf("[RTT] RTT 重新初始化失败: %v", reinitErr))
+						a.bus.Publish(EventSysMsg, i18n.T("app.rtt_reinit_failed", reinitErr))
 					}
 				}
 
 				// 增加容错机制：只有连续多次错误才关闭连接
 				// 这样可以避免偶发错误导致断连，同时确保持续错误时能及时断开
 				if consecutiveErrors >= maxConsecutiveErrors {
-					runtime.EventsEmit(a.ctx, "serial-error", fmt.Sprintf("[RTT] 错误 (连续 %d 次): %v", consecutiveErrors, err))
+					a.bus.Publish(EventSerialError, i18n.T("app.rtt_error", consecutiveErrors, err))
 					a.Close()
 					return
 				}
 				// 首次或少量错误时，仅记录日志，继续尝试
 				if consecutiveErrors == 1 {
-					runtime.EventsEmit(a.ctx, "sys-msg", fmt.Sprintf("[RTT] 读取警告: %v", err))
+					a.bus.Publish(EventSysMsg, i18n.T("app.rtt_read_warning", err))
 				}
 				continue
 			}
@@ -235,52 +941,62 @@ func (a *App) jlinkReadLoop() {
 			consecutiveErrors = 0
 
 			if len(data) > 0 {
-				runtime.EventsEmit(a.ctx, "serial-data", data)
+				a.bridgeFromTarget("", data)
+				a.publishRx(bufpool.Wrap(data))
 			}
 		}
 	}
 }
 
 // OpenTcpClient 连接 TCP 服务端
-func (a *App) OpenTcpClient(ip string, port string) string {
+func (a *App) OpenTcpClient(ip string, port string) apperr.Result {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
 	if a.isConnected {
-		return "Already connected"
+		return apperr.FromError(apperr.New(apperr.CodeAlreadyConnected, ""))
 	}
 
+	op := a.startOperation("open-tcp", tcpConnectTimeout)
+	defer a.ops.Finish(op)
+
 	address := net.JoinHostPort(ip, port)
-	conn, err := net.DialTimeout("tcp", address, 3*time.Second)
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(op.Context(), "tcp", address)
 	if err != nil {
-		return fmt.Sprintf("Connect error: %v", err)
+		if op.Context().Err() != nil {
+			return apperr.FromError(contextError(op.Context()))
+		}
+		return apperr.FromError(apperr.Wrap(apperr.CodeConnectFailed, err))
 	}
 
 	a.netConn = conn
 	a.connType = TypeTcpClient
+	a.onConnected(&journal.Connection{Type: string(TypeTcpClient), Host: ip, Port: port})
 	a.startReadLoop(conn)
 
-	return "Success"
+	return apperr.OK()
 }
 
 // OpenTcpServer 开启 TCP 服务端
-func (a *App) OpenTcpServer(port string) string {
+func (a *App) OpenTcpServer(port string) apperr.Result {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
 	if a.isConnected {
-		return "Already connected"
+		return apperr.FromError(apperr.New(apperr.CodeAlreadyConnected, ""))
 	}
 
 	listener, err := net.Listen("tcp", ":"+port)
 	if err != nil {
-		return fmt.Sprintf("Listen error: %v", err)
+		return apperr.FromError(apperr.Wrap(apperr.CodeListenFailed, err))
 	}
 
 	a.netListener = listener
 	a.connType = TypeTcpServer
 	a.isConnected = true
 	a.readStopChan = make(chan struct{})
+	a.onConnected(&journal.Connection{Type: string(TypeTcpServer), Port: port})
 
 	go func() {
 		for {
@@ -300,19 +1016,20 @@ func (a *App) OpenTcpServer(port string) string {
 				a.netConn = conn
 				a.mutex.Unlock()
 
-				runtime.EventsEmit(a.ctx, "sys-msg", fmt.Sprintf("Client connected: %s", conn.RemoteAddr().String()))
+				a.bus.Publish(EventSysMsg, i18n.T("app.tcp_client_connected", conn.RemoteAddr().String()))
 				go a.handleTcpConnection(conn)
 			}
 		}
 	}()
 
-	return "Success"
+	return apperr.OK()
 }
 
 func (a *App) handleTcpConnection(conn net.Conn) {
-	buff := make([]byte, 4096)
+	buf := a.rxPool.Get()
+	defer func() { buf.Release() }()
 	for {
-		n, err := conn.Read(buff)
+		n, err := conn.Read(buf.B)
 		if err != nil {
 			a.mutex.Lock()
 			if a.netConn == conn {
@@ -322,26 +1039,26 @@ func (a *App) handleTcpConnection(conn net.Conn) {
 			return
 		}
 		if n > 0 {
-			dataToSend := make([]byte, n)
-			copy(dataToSend, buff[:n])
-			runtime.EventsEmit(a.ctx, "serial-data", dataToSend)
+			buf.B = buf.B[:n]
+			a.publishRx(buf)
+			buf = a.rxPool.Get()
 		}
 	}
 }
 
 // OpenUdp 开启 UDP
-func (a *App) OpenUdp(localPort string, remoteIp string, remotePort string) string {
+func (a *App) OpenUdp(localPort string, remoteIp string, remotePort string) apperr.Result {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
 	if a.isConnected {
-		return "Already connected"
+		return apperr.FromError(apperr.New(apperr.CodeAlreadyConnected, ""))
 	}
 
 	lAddrStr := ":" + localPort
 	conn, err := net.ListenPacket("udp", lAddrStr)
 	if err != nil {
-		return fmt.Sprintf("UDP Listen error: %v", err)
+		return apperr.FromError(apperr.Wrap(apperr.CodeListenFailed, err))
 	}
 
 	var rAddr net.Addr
@@ -349,7 +1066,7 @@ func (a *App) OpenUdp(localPort string, remoteIp string, remotePort string) stri
 		rAddr, err = net.ResolveUDPAddr("udp", net.JoinHostPort(remoteIp, remotePort))
 		if err != nil {
 			conn.Close()
-			return fmt.Sprintf("Remote Addr error: %v", err)
+			return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
 		}
 	}
 
@@ -358,22 +1075,26 @@ func (a *App) OpenUdp(localPort string, remoteIp string, remotePort string) stri
 	a.connType = TypeUdp
 	a.isConnected = true
 	a.readStopChan = make(chan struct{})
+	a.onConnected(&journal.Connection{Type: string(TypeUdp), LocalPort: localPort, Host: remoteIp, Port: remotePort})
 
 	go func() {
-		buff := make([]byte, 4096)
+		defer a.recoverPanic("udp-read-loop", func() { a.Close() })
+		buf := a.rxPool.Get()
+		defer func() { buf.Release() }()
 		for {
 			select {
 			case <-a.readStopChan:
 				return
 			default:
 				conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
-				n, addr, err := conn.ReadFrom(buff)
+				n, addr, err := conn.ReadFrom(buf.B)
 				if err != nil {
 					if nerr, ok := err.(net.Error); ok && nerr.Timeout() {
 						continue
 					}
 					if a.isConnected {
-						runtime.EventsEmit(a.ctx, "serial-error", err.Error())
+						a.bus.Publish(EventSerialError, err.Error())
+						a.usage.Error("READ_FAILED")
 					}
 					return
 				}
@@ -381,41 +1102,96 @@ func (a *App) OpenUdp(localPort string, remoteIp string, remotePort string) stri
 				a.mutex.Lock()
 				if a.udpRemote == nil {
 					a.udpRemote = addr
-					runtime.EventsEmit(a.ctx, "sys-msg", fmt.Sprintf("Remote set to: %s", addr.String()))
+					a.bus.Publish(EventSysMsg, i18n.T("app.udp_remote_set", addr.String()))
 				}
 				a.mutex.Unlock()
 
 				if n > 0 {
-					dataToSend := make([]byte, n)
-					copy(dataToSend, buff[:n])
-					runtime.EventsEmit(a.ctx, "serial-data", dataToSend)
+					buf.B = buf.B[:n]
+					a.publishRx(buf)
+					buf = a.rxPool.Get()
 				}
 			}
 		}
 	}()
 
-	return "Success"
+	return apperr.OK()
 }
 
 // --- 通用方法 ---
 
+// publishTx 发布已发送的数据 (抓包等订阅者据此记录发送方向)
+func (a *App) publishTx(payload []byte) {
+	buf := bufpool.Wrap(append([]byte(nil), payload...))
+	a.bus.Publish(EventDataSent, buf)
+	buf.Release()
+	a.countReportTx(len(payload))
+}
+
+// onConnected 连接建立后 (读取循环启动前) 记录会话状态并开始抓包
+func (a *App) onConnected(conn *journal.Connection) {
+	a.usage.Session(conn.Type)
+	a.recordConnection(conn)
+	a.resetSessionVars(conn)
+	a.banner.Reset(time.Now())
+	a.cancelFirmwareRecord()
+	a.crash.Reset()
+	a.bootLoop.Reset()
+	a.startCapture(conn)
+	a.beginReport(conn)
+	a.decoders.Reset()
+	a.sanitizer.Reset()
+	a.autoLogin.Reset()
+	a.resetDetector()
+	a.frameStats.Reset()
+	a.gaps.Reset()
+	a.rules.Reset()
+	a.identifyInstrument()
+	a.syncTimeOnConnect()
+}
+
+// onDisconnected 连接关闭后按配置发送会话报告、清除会话状态中的连接并结束抓包
+func (a *App) onDisconnected() {
+	a.disconnectTotal.Add(1)
+	a.flushCrash()
+	a.lines.Flush()
+	a.endReport()
+	a.recordConnection(nil)
+	a.stopCapture()
+	a.stopModemWatch()
+}
+
+// publishRx 发布接收数据并释放发布方持有的引用
+// 订阅者收到的是 *bufpool.Buffer，处理完后需调用 Release
+func (a *App) publishRx(buf *bufpool.Buffer) {
+	a.bus.Publish(EventSerialData, buf)
+	buf.Release()
+}
+
 func (a *App) startReadLoop(reader io.Reader) {
 	a.isConnected = true
 	a.readStopChan = make(chan struct{})
 
 	go func() {
-		buff := make([]byte, 4096)
+		defer a.recoverPanic("read-loop", func() { a.Close() })
+		buf := a.rxPool.Get()
+		defer func() { buf.Release() }()
 		for {
 			select {
 			case <-a.readStopChan:
 				return
 			default:
-				n, err := reader.Read(buff)
+				n, err := reader.Read(buf.B)
 				if err != nil {
 					if a.isConnected {
-						fmt.Printf("Read Error: %v\n", err)
-						runtime.EventsEmit(a.ctx, "serial-error", err.Error())
+						a.log("serial").Warn("read failed", "err", err)
+						a.bus.Publish(EventSerialError, err.Error())
+						// 设备拔出等读取错误：开启自动重连时等待串口重新出现
+						target := a.reconnectTarget()
 						a.Close()
+						if target != nil {
+							a.startReconnect(target)
+						}
 					}
 					return
 				}
@@ -423,34 +1199,35 @@ func (a *App) startReadLoop(reader io.Reader) {
 					continue
 				}
 
-				fmt.Printf("[DEBUG] Recv %d bytes\n", n)
-				dataToSend := make([]byte, n)
-				copy(dataToSend, buff[:n])
-				runtime.EventsEmit(a.ctx, "serial-data", dataToSend)
+				buf.B = buf.B[:n]
+				a.publishRx(buf)
+				buf = a.rxPool.Get()
 			}
 		}
 	}()
 }
 
 // Close 关闭连接
-func (a *App) Close() string {
+func (a *App) Close() apperr.Result {
 	a.mutex.Lock()
 	defer a.mutex.Unlock()
 
 	if !a.isConnected {
-		return "Not connected"
+		return apperr.FromError(apperr.New(apperr.CodeNotConnected, ""))
 	}
 
 	a.isConnected = false
 	if a.readStopChan != nil {
 		close(a.readStopChan)
 	}
+	a.onDisconnected()
 
 	var err error
 
 	switch a.connType {
 	case TypeSerial:
 		if a.serialPort != nil {
+			a.applyRS485Locked(a.serialPort, nil)
 			err = a.serialPort.Close()
 			a.serialPort = nil
 		}
@@ -482,50 +1259,129 @@ func (a *App) Close() string {
 	}
 
 	if err != nil {
-		return fmt.Sprintf("Error closing: %v", err)
+		return apperr.FromError(apperr.Wrap(apperr.CodeCloseFailed, err))
 	}
-	return "Success"
+	return apperr.OK()
 }
 
 // SendData 发送数据
-func (a *App) SendData(data string) string {
-	a.mutex.Lock()
-	defer a.mutex.Unlock()
+func (a *App) SendData(data string) apperr.Result {
+	op := a.startOperation("send", sendTimeout)
+	defer a.ops.Finish(op)
 
+	return apperr.FromError(a.writeContext(op.Context(), []byte(data)))
+}
+
+// writeContext 在 ctx 控制下向当前连接写入数据，返回的错误均为 *apperr.Error
+// 写入在锁外进行，卡住的写操作 (如硬件流控阻塞) 不会阻塞 Close 等其他调用
+func (a *App) writeContext(ctx context.Context, payload []byte) error {
+	if err := a.writeUnrecorded(ctx, payload); err != nil {
+		return err
+	}
+	a.publishTx(payload)
+	return nil
+}
+
+// writeUnrecorded 与 writeContext 相同，但不发布发送事件：数据不显示、不进入抓包与共享，用于发送密码
+func (a *App) writeUnrecorded(ctx context.Context, payload []byte) error {
+	a.mutex.Lock()
 	if !a.isConnected {
-		return "Error: Not connected"
+		a.mutex.Unlock()
+		return apperr.New(apperr.CodeNotConnected, "")
+	}
+	write, err := a.writerLocked(ctx)
+	if err == nil {
+		a.writes.add()
+	}
+	a.mutex.Unlock()
+	if err != nil {
+		return err
 	}
 
-	payload := []byte(data)
-	var err error
+	done := make(chan error, 1)
+	go func() {
+		defer a.writes.done()
+		done <- write(payload)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return apperr.Wrap(apperr.CodeWriteFailed, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return contextError(ctx)
+	}
+}
 
+// writerLocked 返回当前连接的写函数，调用方需持有 a.mutex
+func (a *App) writerLocked(ctx context.Context) (func([]byte) error, error) {
 	switch a.connType {
 	case TypeSerial:
-		if a.serialPort != nil {
-			_, err = a.serialPort.Write(payload)
+		if port := a.serialPort; port != nil {
+			if rs := a.serialRS485; rs != nil && rs.Mode == ports.RS485RTS {
+				cfg := *rs
+				return func(p []byte) error {
+					_, err := ports.RS485Write(port, cfg, p)
+					return err
+				}, nil
+			}
+			return func(p []byte) error {
+				_, err := port.Write(p)
+				return err
+			}, nil
 		}
 	case TypeJLink:
-		if a.jlinkConn != nil {
-			_, err = a.jlinkConn.WriteRTT(payload)
+		if jl := a.jlinkConn; jl != nil {
+			return func(p []byte) error {
+				_, err := jl.WriteRTT(p)
+				return err
+			}, nil
 		}
 	case TypeTcpClient, TypeTcpServer:
-		if a.netConn != nil {
-			_, err = a.netConn.Write(payload)
-		} else if a.connType == TypeTcpServer {
-			return "Error: No client connected"
+		if conn := a.netConn; conn != nil {
+			return func(p []byte) error {
+				if deadline, ok := ctx.Deadline(); ok {
+					conn.SetWriteDeadline(deadline)
+				}
+				_, err := conn.Write(p)
+				return err
+			}, nil
+		}
+		if a.connType == TypeTcpServer {
+			return nil, apperr.New(apperr.CodeNoClient, "")
 		}
 	case TypeUdp:
-		if a.udpConn != nil && a.udpRemote != nil {
-			_, err = a.udpConn.WriteTo(payload, a.udpRemote)
-		} else {
-			return "Error: No remote address set"
+		if conn, remote := a.udpConn, a.udpRemote; conn != nil && remote != nil {
+			return func(p []byte) error {
+				_, err := conn.WriteTo(p, remote)
+				return err
+			}, nil
 		}
+		return nil, apperr.New(apperr.CodeNoRemote, "")
 	}
+	return nil, apperr.New(apperr.CodeNotConnected, "")
+}
 
-	if err != nil {
-		return fmt.Sprintf("Send error: %v", err)
+// rxSince 返回自接收计数 mark 之后收到的数据 (受尾部缓存大小限制)
+func (a *App) rxSince(mark uint64) []byte {
+	a.rxMutex.Lock()
+	defer a.rxMutex.Unlock()
+
+	n := a.rxTotal - mark
+	if n > uint64(len(a.rxTail)) {
+		n = uint64(len(a.rxTail))
 	}
-	return "Sent"
+	out := make([]byte, n)
+	copy(out, a.rxTail[uint64(len(a.rxTail))-n:])
+	return out
+}
+
+// rxMark 返回当前接收计数，配合 rxSince 使用
+func (a *App) rxMark() uint64 {
+	a.rxMutex.Lock()
+	defer a.rxMutex.Unlock()
+	return a.rxTotal
 }
 
 // --- Update Methods ---
@@ -535,9 +1391,12 @@ func (a *App) GetVersion() string {
 	return Version
 }
 
-// CheckForUpdates checks if a new version is available
+// CheckForUpdates checks if a new version is available on the configured channel
 func (a *App) CheckForUpdates() (updater.UpdateInfo, error) {
-	info, err := updater.CheckForUpdates(Version)
+	op := a.startOperation("check-update", 0)
+	defer a.ops.Finish(op)
+
+	info, err := updater.CheckChannelContext(op.Context(), Version, a.GetUpdateChannel())
 	if err != nil {
 		return updater.UpdateInfo{}, err
 	}
@@ -545,23 +1404,37 @@ func (a *App) CheckForUpdates() (updater.UpdateInfo, error) {
 }
 
 // DownloadAndInstallUpdate downloads and installs the update
+// The signature is expected next to the download as <url>.sig
 func (a *App) DownloadAndInstallUpdate(downloadURL string) error {
-	// Download with progress reporting
-	tempFile, err := updater.DownloadUpdate(downloadURL, func(downloaded, total int64) {
-		// Emit progress event to frontend
-		progress := float64(downloaded) / float64(total) * 100
-		runtime.EventsEmit(a.ctx, "update-progress", map[string]interface{}{
-			"downloaded": downloaded,
-			"total":      total,
-			"progress":   progress,
-		})
+	return a.installUpdate(downloadURL, downloadURL+updater.SignatureExt)
+}
+
+// installUpdate downloads, verifies and installs an update, then restarts the application
+func (a *App) installUpdate(downloadURL, signatureURL string) error {
+	op := a.startOperation("update", 0)
+	defer a.ops.Finish(op)
+
+	// Download with progress reporting ("operation-progress" 事件)
+	tempFile, err := updater.DownloadUpdateContext(op.Context(), downloadURL, func(downloaded, total int64) {
+		op.Report("download", downloaded, total)
 	})
 	if err != nil {
+		op.Fail(err)
 		return fmt.Errorf("download failed: %w", err)
 	}
 
+	// Verify the signature before touching the installed binary
+	op.Report("verify", 0, 0)
+	if err := updater.VerifyDownloadContext(op.Context(), tempFile, signatureURL); err != nil {
+		os.Remove(tempFile)
+		op.Fail(err)
+		return fmt.Errorf("verification failed: %w", err)
+	}
+
 	// Install the update
+	op.Report("install", 0, 0)
 	if err := updater.InstallUpdate(tempFile); err != nil {
+		op.Fail(err)
 		return fmt.Errorf("installation failed: %w", err)
 	}
 
@@ -573,6 +1446,7 @@ func (a *App) DownloadAndInstallUpdate(downloadURL string) error {
 
 	// Schedule restart after 1 second delay
 	if err := updater.RestartApplication(1); err != nil {
+		op.Fail(err)
 		return fmt.Errorf("failed to schedule restart: %w", err)
 	}
 
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/ports"
+
+	"go.bug.st/serial"
+)
+
+func TestReconfigureSerialRollback(t *testing.T) {
+	a := NewApp()
+	port := &mockPort{}
+	connectMock(a, port)
+	prev := SerialOptions{Port: "COM1", BaudRate: 115200, DataBits: 8, StopBits: 1, Parity: "None"}
+	a.mutex.Lock()
+	a.serialPortName, a.serialOpts = prev.Port, prev
+	a.mutex.Unlock()
+
+	// mockPort 不支持非标准波特率：参数先以当前速率设置，失败后恢复原设置，不经过 9600
+	r := a.ReconfigureSerial(SerialOptions{BaudRate: 250000, DataBits: 7, StopBits: 2, Parity: "Even"})
+	if r.OK || r.Code != apperr.CodeUnsupported {
+		t.Fatalf("Expected UNSUPPORTED, got %+v", r)
+	}
+	want := []serial.Mode{
+		{BaudRate: 115200, DataBits: 7, StopBits: serial.TwoStopBits, Parity: serial.EvenParity},
+		{BaudRate: 115200, DataBits: 8, StopBits: serial.OneStopBit, Parity: serial.NoParity},
+	}
+	if len(port.modes) != len(want) || port.modes[0] != want[0] || port.modes[1] != want[1] {
+		t.Errorf("Unexpected modes %+v", port.modes)
+	}
+
+	// 流控设置失败时恢复原来的波特率
+	port.modes = nil
+	r = a.ReconfigureSerial(SerialOptions{BaudRate: 9600, DataBits: 8, StopBits: 1, Parity: "None", FlowControl: ports.FlowRTSCTS})
+	if r.OK || r.Code != apperr.CodeUnsupported {
+		t.Fatalf("Expected UNSUPPORTED, got %+v", r)
+	}
+	if len(port.modes) != 2 || port.modes[0].BaudRate != 9600 || port.modes[1].BaudRate != 115200 {
+		t.Errorf("Unexpected modes %+v", port.modes)
+	}
+	if a.serialOpts != prev {
+		t.Errorf("Failed reconfigure changed settings to %+v", a.serialOpts)
+	}
+
+	if r := a.ReconfigureSerial(SerialOptions{BaudRate: 9600, DataBits: 8, StopBits: 1, Parity: "None"}); !r.OK {
+		t.Fatalf("ReconfigureSerial = %+v", r)
+	}
+	if a.serialOpts.BaudRate != 9600 || a.serialOpts.Port != "COM1" {
+		t.Errorf("Settings not updated: %+v", a.serialOpts)
+	}
+}
+
+func TestInterimBaudRate(t *testing.T) {
+	if got := interimBaudRate(115200, 250000); got != 115200 {
+		t.Errorf("Standard current rate should be kept, got %d", got)
+	}
+	if got := interimBaudRate(250000, 74880); got != 57600 {
+		t.Errorf("Expected nearest standard rate, got %d", got)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/applog"
+	"serial-assistant/pkg/diagnostics"
+
+	"github.com/wailsapp/wails/v2/pkg/logger"
+)
+
+// newAppLog 创建应用内部日志：记入日志缓冲区 (应用内查看、写入诊断转储)，同时输出到 stderr
+// 每条日志发布 "app-log" 事件供前端实时显示
+func (a *App) newAppLog() *slog.Logger {
+	a.logLevel.Set(slog.LevelInfo)
+	text := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: &a.logLevel})
+	h := applog.New(a.logRing, text, &a.logLevel).OnRecord(func(e diagnostics.Entry) {
+		a.bus.Publish(EventAppLog, e)
+	})
+	return slog.New(h)
+}
+
+// log 返回带模块标签的日志记录器
+func (a *App) log(component string) *slog.Logger {
+	return a.appLog.With(applog.ComponentKey, component)
+}
+
+// GetAppLog 返回最近的内部日志：不低于 minLevel (debug、info、warn、error，为空时不限)，
+// 属于 component (为空时不限) 的最后 limit 条 (limit <= 0 时不限)
+func (a *App) GetAppLog(minLevel string, component string, limit int) ([]diagnostics.Entry, error) {
+	min := slog.LevelDebug
+	if minLevel != "" {
+		l, err := applog.ParseLevel(minLevel)
+		if err != nil {
+			return nil, apperr.Wrap(apperr.CodeInvalidArgument, err)
+		}
+		min = l
+	}
+	return applog.Filter(a.logRing.Entries(), min, component, limit), nil
+}
+
+// GetLogLevel 返回内部日志的记录级别
+func (a *App) GetLogLevel() string {
+	return applog.LevelName(a.logLevel.Level())
+}
+
+// SetLogLevel 设置内部日志的记录级别 (debug、info、warn、error)
+func (a *App) SetLogLevel(level string) apperr.Result {
+	l, err := applog.ParseLevel(level)
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	a.logLevel.Set(l)
+	return apperr.OK()
+}
+
+// wailsLogger 将 Wails 运行时的日志转入内部日志 (模块标签 "wails")
+type wailsLogger struct {
+	log  *slog.Logger
+	next logger.Logger
+}
+
+// logger 返回传给 Wails 的日志记录器
+func (a *App) logger() logger.Logger {
+	return &wailsLogger{log: a.log("wails"), next: logger.NewDefaultLogger()}
+}
+
+func (l *wailsLogger) Print(message string)   { l.log.Info(trimLog(message)) }
+func (l *wailsLogger) Trace(message string)   { l.log.Debug(trimLog(message)) }
+func (l *wailsLogger) Debug(message string)   { l.log.Debug(trimLog(message)) }
+func (l *wailsLogger) Info(message string)    { l.log.Info(trimLog(message)) }
+func (l *wailsLogger) Warning(message string) { l.log.Warn(trimLog(message)) }
+func (l *wailsLogger) Error(message string)   { l.log.Error(trimLog(message)) }
+
+// Fatal 记录后交给 Wails 默认日志退出进程
+func (l *wailsLogger) Fatal(message string) {
+	l.log.Error(trimLog(message))
+	l.next.Fatal(message)
+}
+
+func trimLog(message string) string {
+	return strings.TrimRight(message, "\r\n")
+}
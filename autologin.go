@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/audit"
+	"serial-assistant/pkg/autologin"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/pipeline"
+	"serial-assistant/pkg/secrets"
+)
+
+// loginAuditFile 自动登录的审计日志，与远程命令的审计日志格式相同
+const loginAuditFile = "login-audit.jsonl"
+
+// AutoLoginEvent 一次自动登录动作
+type AutoLoginEvent struct {
+	Time     time.Time        `json:"time"`
+	Action   autologin.Action `json:"action"`
+	Username string           `json:"username"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// loginSecretKey 用户密码在钥匙串中的名称
+func loginSecretKey(username string) string {
+	return "login/" + username
+}
+
+// SetAutoLogin 设置自动登录：检测到 "login:" 提示符时发送用户名，随后的 "Password:" 提示符发送钥匙串中保存的密码
+// 每次自动登录都会发布 "auto-login" 事件并写入审计日志 (不含密码)
+func (a *App) SetAutoLogin(cfg autologin.Config) apperr.Result {
+	if err := a.autoLogin.SetConfig(cfg); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	return apperr.OK()
+}
+
+// GetAutoLogin 返回自动登录设置
+func (a *App) GetAutoLogin() autologin.Config {
+	return a.autoLogin.Config()
+}
+
+// SetAutoLoginPassword 将 username 的登录密码保存到系统钥匙串，磁盘上不保存明文
+func (a *App) SetAutoLoginPassword(username, password string) apperr.Result {
+	if username == "" {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, ""))
+	}
+	return apperr.FromError(secretError(a.secrets.Set(loginSecretKey(username), password)))
+}
+
+// DeleteAutoLoginPassword 从系统钥匙串删除 username 的登录密码
+func (a *App) DeleteAutoLoginPassword(username string) apperr.Result {
+	err := a.secrets.Delete(loginSecretKey(username))
+	if errors.Is(err, secrets.ErrNotFound) {
+		return apperr.OK()
+	}
+	return apperr.FromError(secretError(err))
+}
+
+// watchLogin 在接收数据中检测登录提示符并自动回应
+func (a *App) watchLogin(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		buf, ok := ev.Payload.(*bufpool.Buffer)
+		if !ok {
+			return
+		}
+		action := a.autoLogin.Feed(ev.Time, buf.B)
+		buf.Release()
+		if action != autologin.None {
+			a.respondLogin(action)
+		}
+	})
+}
+
+// respondLogin 发送用户名或密码，并记录事件与审计日志
+func (a *App) respondLogin(action autologin.Action) {
+	cfg := a.autoLogin.Config()
+	ev := AutoLoginEvent{Time: time.Now(), Action: action, Username: cfg.Username}
+	var msg string
+	var err error
+	switch action {
+	case autologin.SendUsername:
+		err = a.sendLogin(cfg.Username+cfg.LineEnding, false)
+		msg = i18n.T("app.auto_login_username", cfg.Username)
+	case autologin.SendPassword:
+		var password string
+		password, err = a.secrets.Get(loginSecretKey(cfg.Username))
+		if err == nil {
+			err = a.sendLogin(password+cfg.LineEnding, true)
+			msg = i18n.T("app.auto_login_password", cfg.Username)
+		} else if errors.Is(err, secrets.ErrNotFound) {
+			msg = i18n.T("app.auto_login_no_password", cfg.Username)
+		}
+	case autologin.Suppressed:
+		msg = i18n.T("app.auto_login_suppressed")
+	}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	if msg != "" {
+		a.bus.Publish(EventSysMsg, msg)
+	}
+	a.bus.Publish(EventAutoLogin, ev)
+	a.auditLogin(ev)
+}
+
+// sendLogin 发送用户名或密码；secret 为 true 时不发布发送事件，密码不会出现在界面、抓包与共享中
+func (a *App) sendLogin(s string, secret bool) error {
+	op := a.startOperation("auto-login", sendTimeout)
+	defer a.ops.Finish(op)
+	if secret {
+		return a.writeUnrecorded(op.Context(), []byte(s))
+	}
+	return a.writeContext(op.Context(), []byte(s))
+}
+
+// auditLogin 将自动登录动作追加到审计日志
+func (a *App) auditLogin(ev AutoLoginEvent) {
+	log, err := audit.Open(filepath.Join(captureDir(), loginAuditFile))
+	if err == nil {
+		err = log.Record(audit.Entry{Time: ev.Time, Who: ev.Username, Action: "auto-login:" + string(ev.Action), Error: ev.Error})
+		log.Close()
+	}
+	if err != nil {
+		a.log("autologin").Warn("login audit log not written", "err", err)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/rules"
+)
+
+// 自动化的停止原因
+const (
+	stopPattern = "pattern" // 接收文本中出现指定内容
+	stopAlert   = "alert"   // 规则触发
+	stopReboot  = "reboot"  // 设备重启
+)
+
+// StopConditions 自动化的停止条件，设备异常时安全地中止自动发送、定时发送序列与命令批处理
+// 条件由规则引擎在接收数据上逐行判断
+type StopConditions struct {
+	Patterns []string `json:"patterns,omitempty"` // 接收文本中出现任一内容即停止
+	Regex    bool     `json:"regex,omitempty"`    // Patterns 为正则表达式
+	Alert    bool     `json:"alert,omitempty"`    // 任一规则触发 (告警) 即停止
+	Reboot   bool     `json:"reboot,omitempty"`   // 出现启动信息即停止，使用启动循环检测的表达式
+}
+
+// AutomationStop 自动化因停止条件中止，随 EventAutomationStopped 发送
+type AutomationStop struct {
+	Kind   string    `json:"kind"`           // auto-send、schedule、command-batch
+	Reason string    `json:"reason"`         // pattern、alert、reboot
+	Rule   string    `json:"rule,omitempty"` // 触发的规则名 (reason 为 alert 时)
+	Line   string    `json:"line"`
+	Time   time.Time `json:"time"`
+}
+
+// err 返回给自动化调用方的错误
+func (s *AutomationStop) err() *apperr.Error {
+	return apperr.New(apperr.CodeCanceled, s.Reason+": "+s.Line)
+}
+
+// stopCondition 转换为规则引擎的停止条件，重启使用启动循环检测当前的表达式
+func (a *App) stopCondition(s StopConditions) rules.Condition {
+	c := rules.Condition{Alert: s.Alert}
+	for _, p := range s.Patterns {
+		c.Patterns = append(c.Patterns, rules.Rule{ID: stopPattern, Name: p, Pattern: p, Regex: s.Regex})
+	}
+	if s.Reboot {
+		for _, p := range a.bootLoop.Config().Patterns {
+			c.Patterns = append(c.Patterns, rules.Rule{ID: stopReboot, Name: p, Pattern: p, Regex: true, CaseSensitive: true})
+		}
+	}
+	return c
+}
+
+// watchStop 在自动化运行期间监视停止条件 (s 为 nil 表示不监视)，满足时调用 stop 并发布 EventAutomationStopped
+// 返回的函数注销监视，并返回停止原因 (未触发时为 nil)
+func (a *App) watchStop(kind string, s *StopConditions, stop func()) (func() *AutomationStop, error) {
+	if s == nil || len(s.Patterns) == 0 && !s.Alert && !s.Reboot {
+		return func() *AutomationStop { return nil }, nil
+	}
+	var mu sync.Mutex
+	var stopped *AutomationStop
+	cancel, err := a.rules.Watch(a.stopCondition(*s), func(m rules.Match, alert bool) {
+		ev := AutomationStop{Kind: kind, Reason: m.RuleID, Line: m.Line, Time: m.Time}
+		if alert {
+			ev.Reason, ev.Rule = stopAlert, m.RuleName
+		}
+		mu.Lock()
+		stopped = &ev
+		mu.Unlock()
+		stop()
+		a.log("automation").Info("automation stopped", "kind", kind, "reason", ev.Reason, "rule", ev.Rule, "line", ev.Line)
+		a.bus.Publish(EventAutomationStopped, ev)
+		a.bus.Publish(EventSysMsg, i18n.T("app.automation_stopped", kind, ev.Line))
+	})
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	return func() *AutomationStop {
+		cancel()
+		mu.Lock()
+		defer mu.Unlock()
+		return stopped
+	}, nil
+}
+
+// ArmAutoSendStop 为前端的自动发送设置停止条件：条件满足时发送 "automation-stopped" 事件 (kind 为 auto-send)，
+// 前端据此停止自动发送。再次调用替换原条件，条件满足后自动解除
+func (a *App) ArmAutoSendStop(stop StopConditions) apperr.Result {
+	a.DisarmAutoSendStop()
+	done, err := a.watchStop("auto-send", &stop, func() {})
+	if err != nil {
+		return apperr.FromError(err)
+	}
+	a.autoSendMutex.Lock()
+	a.autoSendStop = done
+	a.autoSendMutex.Unlock()
+	return apperr.OK()
+}
+
+// DisarmAutoSendStop 解除自动发送的停止条件，用户关闭自动发送时调用
+func (a *App) DisarmAutoSendStop() {
+	a.autoSendMutex.Lock()
+	defer a.autoSendMutex.Unlock()
+	if a.autoSendStop != nil {
+		a.autoSendStop()
+		a.autoSendStop = nil
+	}
+}
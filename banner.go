@@ -0,0 +1,84 @@
+package main
+
+import (
+	"path/filepath"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/banner"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/pipeline"
+)
+
+// bannerFile 开机信息提取配置文件名 (位于应用数据目录)
+const bannerFile = "banner.json"
+
+// extractBanner 从连接后最初的接收数据中提取会话标题与设备信息
+func (a *App) extractBanner(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		buf, ok := ev.Payload.(*bufpool.Buffer)
+		if !ok {
+			return
+		}
+		m, changed := a.banner.Feed(ev.Time, buf.B)
+		buf.Release()
+		if changed {
+			a.applySessionMetadata(m)
+		}
+	})
+}
+
+// applySessionMetadata 将提取结果写入抓包文件头与会话报告，并发布 "session-metadata" 事件
+func (a *App) applySessionMetadata(m banner.Metadata) {
+	a.captureMutex.Lock()
+	if a.capture != nil {
+		a.captureMeta.Title = m.Title
+		a.captureMeta.Device = m.Fields
+		if err := a.capture.UpdateMeta(a.captureMeta); err != nil {
+			a.log("banner").Warn("capture metadata not updated", "err", err)
+		}
+	}
+	a.captureMutex.Unlock()
+
+	a.reportMutex.Lock()
+	a.reportMeta = m
+	a.reportMutex.Unlock()
+
+	a.bus.Publish(EventSessionMetadata, m)
+	if m.Fields[banner.FieldFirmware] != "" {
+		a.scheduleFirmwareRecord()
+	}
+}
+
+// loadBannerConfig 启动时加载保存的提取配置
+func (a *App) loadBannerConfig() {
+	cfg, err := banner.Load(filepath.Join(appDataDir(), bannerFile))
+	if err == nil {
+		err = a.banner.SetConfig(cfg)
+	}
+	if err != nil {
+		a.log("banner").Warn("banner config not loaded", "err", err)
+	}
+}
+
+// GetBannerConfig 返回开机信息提取配置
+func (a *App) GetBannerConfig() banner.Config {
+	return a.banner.Config()
+}
+
+// SetBannerConfig 设置并保存开机信息提取配置：连接后 windowMs 内的每一行按规则提取字段，
+// 标题模板中的 {字段名} 替换为提取的值
+func (a *App) SetBannerConfig(cfg banner.Config) apperr.Result {
+	if err := a.banner.SetConfig(cfg); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	if err := banner.Save(filepath.Join(appDataDir(), bannerFile), cfg); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// GetSessionMetadata 返回当前会话已提取的标题与设备信息
+func (a *App) GetSessionMetadata() banner.Metadata {
+	return a.banner.Metadata()
+}
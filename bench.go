@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/instrument"
+	"serial-assistant/pkg/scpi"
+)
+
+// ListInstrumentProfiles 返回内置的电源与万用表配置
+func (a *App) ListInstrumentProfiles() []instrument.Profile {
+	return instrument.Builtins
+}
+
+// SelectInstrument 选择仪器配置；name 为空时查询 *IDN? 自动匹配
+func (a *App) SelectInstrument(name string) (instrument.Profile, error) {
+	var (
+		p   instrument.Profile
+		err error
+	)
+	if name == "" {
+		op := a.startOperation("scpi", scpiQueryTimeout)
+		defer a.ops.Finish(op)
+		id, qerr := a.scpi.Identify(op.Context())
+		if qerr != nil {
+			return p, scpiError(op.Context(), qerr)
+		}
+		if p, err = instrument.Match(id); err != nil {
+			return p, apperr.Wrap(apperr.CodeUnsupported, err)
+		}
+	} else if p, err = instrument.Find(name); err != nil {
+		return p, apperr.Wrap(apperr.CodeNotFound, err)
+	}
+	a.scpiMutex.Lock()
+	a.instrument = instrument.NewDriver(p, a.scpi)
+	a.scpiMutex.Unlock()
+	return p, nil
+}
+
+// benchError 将驱动错误转换为结构化错误
+func benchError(ctx context.Context, err error) *apperr.Error {
+	switch {
+	case errors.Is(err, instrument.ErrChannel), errors.Is(err, instrument.ErrFunction):
+		return apperr.Wrap(apperr.CodeInvalidArgument, err)
+	case errors.Is(err, instrument.ErrWrongKind):
+		return apperr.Wrap(apperr.CodeUnsupported, err)
+	}
+	return scpiError(ctx, err)
+}
+
+// withInstrument 在一次 SCPI 操作内对已选择的仪器执行 fn
+func (a *App) withInstrument(fn func(ctx context.Context, d *instrument.Driver) error) *apperr.Error {
+	a.scpiMutex.Lock()
+	d := a.instrument
+	a.scpiMutex.Unlock()
+	if d == nil {
+		return apperr.New(apperr.CodeInvalidArgument, "no instrument selected")
+	}
+	op := a.startOperation("scpi", 4*scpiQueryTimeout)
+	defer a.ops.Finish(op)
+	if err := fn(op.Context(), d); err != nil {
+		return benchError(op.Context(), err)
+	}
+	return nil
+}
+
+// PsuSetVoltage 设置电源通道 (从 1 开始) 的输出电压 (V)
+func (a *App) PsuSetVoltage(channel int, volts float64) apperr.Result {
+	return apperr.FromError(a.withInstrument(func(ctx context.Context, d *instrument.Driver) error {
+		return d.SetVoltage(ctx, channel, volts)
+	}))
+}
+
+// PsuSetCurrent 设置电源通道的限流 (A)
+func (a *App) PsuSetCurrent(channel int, amps float64) apperr.Result {
+	return apperr.FromError(a.withInstrument(func(ctx context.Context, d *instrument.Driver) error {
+		return d.SetCurrent(ctx, channel, amps)
+	}))
+}
+
+// PsuSetOutput 打开或关闭电源通道的输出
+func (a *App) PsuSetOutput(channel int, on bool) apperr.Result {
+	return apperr.FromError(a.withInstrument(func(ctx context.Context, d *instrument.Driver) error {
+		return d.SetOutput(ctx, channel, on)
+	}))
+}
+
+// PsuMeasure 读取电源通道的电压与电流，读数同时写入 "psu.chN.*" 序列
+func (a *App) PsuMeasure(channel int) (instrument.Reading, error) {
+	var r instrument.Reading
+	if e := a.withInstrument(func(ctx context.Context, d *instrument.Driver) error {
+		var err error
+		r, err = d.Measure(ctx, channel)
+		return err
+	}); e != nil {
+		return r, e
+	}
+	name := fmt.Sprintf("psu.ch%d", channel)
+	a.publishSample(name+".voltage", r.Voltage, "V")
+	a.publishSample(name+".current", r.Current, "A")
+	a.publishSample(name+".power", r.Power, "W")
+	return r, nil
+}
+
+// DmmConfigure 设置万用表的测量功能 (dcv、acv、dci、aci、res、fres、freq、cont、diode)
+func (a *App) DmmConfigure(function string) apperr.Result {
+	return apperr.FromError(a.withInstrument(func(ctx context.Context, d *instrument.Driver) error {
+		return d.Configure(ctx, function)
+	}))
+}
+
+// DmmRead 触发一次万用表测量，读数同时写入 "dmm.reading" 序列
+func (a *App) DmmRead() (float64, error) {
+	var v float64
+	if e := a.withInstrument(func(ctx context.Context, d *instrument.Driver) error {
+		var err error
+		v, err = d.Read(ctx)
+		return err
+	}); e != nil {
+		return 0, e
+	}
+	a.publishSample("dmm.reading", v, "")
+	return v, nil
+}
+
+// StartInstrumentLogger 按间隔记录已选择仪器的全部读数 (电源各通道电压电流或万用表读数)
+func (a *App) StartInstrumentLogger(intervalMs int) apperr.Result {
+	a.scpiMutex.Lock()
+	d := a.instrument
+	a.scpiMutex.Unlock()
+	if d == nil {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, "no instrument selected"))
+	}
+	return a.StartScpiLogger(scpi.LoggerConfig{
+		Measurements: d.Measurements(d.Profile.Kind),
+		IntervalMs:   intervalMs,
+		PollErrors:   true,
+	})
+}
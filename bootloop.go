@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/bootloop"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/crashlog"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/pipeline"
+)
+
+// bootLoopFile 启动循环检测配置文件名 (位于应用数据目录)
+const bootLoopFile = "bootloop.json"
+
+// bootLoopTrigger 启动循环生成的崩溃报告的触发名
+const bootLoopTrigger = "boot-loop"
+
+// detectBootLoop 统计接收数据中启动信息出现的频率
+func (a *App) detectBootLoop(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		buf, ok := ev.Payload.(*bufpool.Buffer)
+		if !ok {
+			return
+		}
+		alert := a.bootLoop.Feed(ev.Time, buf.B)
+		buf.Release()
+		if alert != nil {
+			a.onBootLoop(*alert)
+		}
+	})
+}
+
+// onBootLoop 发布 "boot-loop" 事件并发送桌面通知，按配置停止定时发送序列、保存崩溃报告
+func (a *App) onBootLoop(alert bootloop.Alert) {
+	a.bus.Publish(EventBootLoop, alert)
+
+	cfg := a.bootLoop.Config()
+	if cfg.StopSequence {
+		a.ops.CancelKind("schedule")
+	}
+	if cfg.CrashReport {
+		lines := strings.Split(strings.TrimRight(string(a.rxSince(0)), "\r\n"), "\n")
+		for i, l := range lines {
+			lines[i] = strings.TrimRight(l, "\r")
+		}
+		a.saveCrash(crashlog.Crash{Time: alert.Time, Trigger: bootLoopTrigger, Line: alert.Line, Lines: lines})
+	}
+
+	body := i18n.T("bootloop.body", alert.Boots, alert.WindowMs/1000, float64(alert.MeanIntervalMs)/1000, alert.Line)
+	go func() {
+		if _, err := a.notifier.Notify(context.Background(), i18n.T("bootloop.title"), body); err != nil {
+			a.log("bootloop").Warn("boot loop notification failed", "err", err)
+		}
+	}()
+}
+
+// loadBootLoopConfig 启动时加载启动循环检测配置
+func (a *App) loadBootLoopConfig() {
+	cfg, err := bootloop.Load(filepath.Join(appDataDir(), bootLoopFile))
+	if err == nil {
+		err = a.bootLoop.SetConfig(cfg)
+	}
+	if err != nil {
+		a.log("bootloop").Warn("boot loop config not loaded", "err", err)
+	}
+}
+
+// GetBootLoopConfig 返回启动循环检测配置
+func (a *App) GetBootLoopConfig() bootloop.Config {
+	return a.bootLoop.Config()
+}
+
+// SetBootLoopConfig 设置并保存启动循环检测配置：windowMs 内启动信息出现 threshold 次即告警
+func (a *App) SetBootLoopConfig(cfg bootloop.Config) apperr.Result {
+	if err := a.bootLoop.SetConfig(cfg); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	if err := bootloop.Save(filepath.Join(appDataDir(), bootLoopFile), cfg); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
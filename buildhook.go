@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/audit"
+	"serial-assistant/pkg/buildhook"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/journal"
+	"serial-assistant/pkg/secrets"
+	"serial-assistant/pkg/watchfolder"
+)
+
+// buildHookFile 构建钩子配置文件名 (位于应用数据目录)
+const buildHookFile = "buildhook.json"
+
+// buildHookTokenKey 构建钩子令牌在钥匙串中的名称
+const buildHookTokenKey = "buildhook/token"
+
+// BuildHookStatus 构建钩子配置及监听地址 (未监听时为空)
+type BuildHookStatus struct {
+	buildhook.Config
+	Addr string `json:"addr,omitempty"`
+}
+
+// loadBuildHook 读取构建钩子配置并从钥匙串取出令牌，启用时开始监听
+// 旧版本保存在配置文件中的令牌会迁移到钥匙串，迁移成功后从文件中删除；启用但没有令牌时生成新令牌
+func (a *App) loadBuildHook() {
+	path := filepath.Join(appDataDir(), buildHookFile)
+	c, err := buildhook.Load(path)
+	if err != nil {
+		a.log("buildhook").Warn("build hook config not loaded", "err", err)
+	}
+	switch {
+	case c.Token != "":
+		if a.secrets.Set(buildHookTokenKey, c.Token) == nil {
+			stored := c
+			stored.Token = ""
+			buildhook.Save(path, stored)
+		}
+	default:
+		token, err := a.secrets.Get(buildHookTokenKey)
+		if token == "" && c.Enabled {
+			token = buildhook.NewToken()
+			err = a.secrets.Set(buildHookTokenKey, token)
+		}
+		if err != nil && !errors.Is(err, secrets.ErrNotFound) {
+			a.log("buildhook").Warn("build hook token unavailable", "err", err)
+		}
+		c.Token = token
+	}
+	a.hookMutex.Lock()
+	a.hookCfg = c
+	a.hookMutex.Unlock()
+	if c.Enabled {
+		if err := a.startBuildHook(c); err != nil {
+			a.log("buildhook").Warn("build hook not started", "addr", c.Addr(), "err", err)
+		}
+	}
+}
+
+// GetBuildHook 返回构建钩子配置及监听地址
+func (a *App) GetBuildHook() BuildHookStatus {
+	a.hookMutex.Lock()
+	defer a.hookMutex.Unlock()
+	st := BuildHookStatus{Config: a.hookCfg}
+	if a.hookServer != nil {
+		st.Addr = a.hookServer.Addr()
+	}
+	return st
+}
+
+// SetBuildHook 设置并保存构建钩子：启用后在 127.0.0.1 上监听，构建脚本可在编译后 POST
+// /flash?file=<绝对路径> 将固件发送到当前连接，或 /run?snippet=<片段 ID> 依次发送片段组成的测试序列
+// 令牌为空时自动生成，保存在系统钥匙串中
+func (a *App) SetBuildHook(c buildhook.Config) apperr.Result {
+	if err := c.Validate(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	if c.Token == "" {
+		c.Token = buildhook.NewToken()
+	}
+	if err := a.secrets.Set(buildHookTokenKey, c.Token); err != nil {
+		return apperr.FromError(secretError(err))
+	}
+	a.stopBuildHook()
+	if c.Enabled {
+		if err := a.startBuildHook(c); err != nil {
+			return apperr.FromError(apperr.Wrap(apperr.CodeListenFailed, err))
+		}
+	}
+	stored := c
+	stored.Token = ""
+	if err := buildhook.Save(filepath.Join(appDataDir(), buildHookFile), stored); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	a.hookMutex.Lock()
+	a.hookCfg = c
+	a.hookMutex.Unlock()
+	return apperr.OK()
+}
+
+// startBuildHook 开始监听构建钩子请求
+func (a *App) startBuildHook(c buildhook.Config) error {
+	srv, err := buildhook.Start(c, buildhook.Actions{
+		Flash: func(ctx context.Context, path string) (int64, error) {
+			return a.hookFlash(ctx, c, path)
+		},
+		Run:        a.hookRun,
+		Status:     a.hookStatus,
+		StatusCode: hookStatusCode,
+		Done:       a.hookDone,
+		Audit:      a.auditHook,
+	})
+	if err != nil {
+		return err
+	}
+	a.hookMutex.Lock()
+	a.hookServer = srv
+	a.hookMutex.Unlock()
+	a.log("buildhook").Info("build hook listening", "addr", srv.Addr())
+	return nil
+}
+
+// stopBuildHook 停止监听，进行中的动作随之取消
+func (a *App) stopBuildHook() {
+	a.hookMutex.Lock()
+	defer a.hookMutex.Unlock()
+	if a.hookServer != nil {
+		a.hookServer.Close()
+		a.hookServer = nil
+	}
+}
+
+// hookFlash 将固件文件原样发送到当前连接，进度通过 "operation-progress" 事件通知
+func (a *App) hookFlash(ctx context.Context, c buildhook.Config, path string) (int64, error) {
+	if a.dryRun.Load() {
+		st, err := os.Stat(path)
+		if err != nil {
+			return 0, apperr.Wrap(apperr.CodeNotFound, err)
+		}
+		a.skipDryRun("flash", filepath.Base(path), fmt.Sprintf("%d bytes", st.Size()))
+		return st.Size(), nil
+	}
+	a.bus.Publish(EventSysMsg, i18n.T("app.hook_flash", filepath.Base(path)))
+	op := a.startOperation("flash", 0)
+	defer a.ops.Finish(op)
+	stop := context.AfterFunc(ctx, func() { a.ops.Cancel(op.ID) })
+	defer stop()
+
+	var sent int64
+	err := watchfolder.SendFile(op.Context(), path, c.ChunkSize, time.Duration(c.ChunkDelayMs)*time.Millisecond, func(b []byte) error {
+		return a.writeContext(op.Context(), b)
+	}, func(done, total int64) {
+		sent = done
+		op.Report("flash", done, total)
+	})
+	if err != nil {
+		op.Fail(err)
+		if op.Context().Err() != nil {
+			return sent, contextError(op.Context())
+		}
+	}
+	return sent, err
+}
+
+// hookRun 依次展开并发送片段
+func (a *App) hookRun(ctx context.Context, ids []string) error {
+	op := a.startOperation("test-sequence", 0)
+	defer a.ops.Finish(op)
+	stop := context.AfterFunc(ctx, func() { a.ops.Cancel(op.ID) })
+	defer stop()
+
+	for i, id := range ids {
+		s, err := a.snippets.Get(id)
+		if err != nil {
+			return apperr.Wrap(apperr.CodeNotFound, err)
+		}
+		b, err := a.payloads.Expand(s.Payload, s.Hex, frameSpec(s))
+		if err != nil {
+			return apperr.Wrap(apperr.CodeInvalidArgument, err)
+		}
+		if err := a.writeContext(op.Context(), b); err != nil {
+			op.Fail(err)
+			return err
+		}
+		op.Report("send", int64(i+1), int64(len(ids)))
+	}
+	return nil
+}
+
+// hookStatus 返回 /status 的内容：当前连接 (未连接时为 null) 与是否处于试运行模式
+func (a *App) hookStatus() any {
+	return struct {
+		Connection *journal.Connection `json:"connection"`
+		DryRun     bool                `json:"dryRun"`
+	}{a.currentConnection(), a.GetDryRun()}
+}
+
+// hookDone 记录构建钩子动作的结果并在系统消息中提示
+func (a *App) hookDone(r buildhook.Result) {
+	a.bus.Publish(EventBuildHook, r)
+	if r.OK {
+		a.log("buildhook").Info("build hook action done", "action", r.Action, "target", r.Target, "bytes", r.Bytes, "ms", r.DurationMs)
+		a.bus.Publish(EventSysMsg, i18n.T("app.hook_done", r.Action, r.Target))
+		return
+	}
+	a.log("buildhook").Warn("build hook action failed", "action", r.Action, "target", r.Target, "err", r.Error)
+	a.bus.Publish(EventSysMsg, i18n.T("app.hook_failed", r.Action, r.Target, r.Error))
+}
+
+// auditHook 将构建钩子的每个请求 (包括被拒绝的请求) 写入审计日志
+func (a *App) auditHook(r buildhook.Request) {
+	e := audit.Entry{Who: "build-hook", Remote: r.Remote, Action: "build-hook:" + r.Method + " " + r.Path, Data: []byte(r.Target)}
+	if r.Status >= http.StatusBadRequest {
+		e.Error = fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status))
+	}
+	a.recordAudit(e)
+}
+
+// hookStatusCode 将结构化错误映射为构建钩子的 HTTP 状态码
+func hookStatusCode(err error) int {
+	switch apperr.CodeOf(err) {
+	case apperr.CodeInvalidArgument:
+		return http.StatusBadRequest
+	case apperr.CodeNotFound:
+		return http.StatusNotFound
+	case apperr.CodeNotConnected:
+		return http.StatusServiceUnavailable
+	case apperr.CodeTimeout:
+		return http.StatusGatewayTimeout
+	case apperr.CodeCanceled:
+		return http.StatusRequestTimeout
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/capture"
+	"serial-assistant/pkg/journal"
+	"serial-assistant/pkg/pipeline"
+)
+
+// captureFlushInterval 抓包缓冲写入磁盘的间隔
+const captureFlushInterval = time.Second
+
+// captureDir 抓包文件目录
+func captureDir() string {
+	return filepath.Join(appDataDir(), "captures")
+}
+
+// connectionTarget 返回连接对象的简短描述 (串口名、主机:端口或芯片型号)
+func connectionTarget(conn *journal.Connection) string {
+	switch ConnectionType(conn.Type) {
+	case TypeTcpClient, TypeUdp:
+		return conn.Host + ":" + conn.Port
+	case TypeJLink:
+		return conn.Chip
+	}
+	return conn.Port
+}
+
+// startCapture 为新建立的连接开始抓包，失败时只记录日志，不影响连接
+func (a *App) startCapture(conn *journal.Connection) {
+	target := connectionTarget(conn)
+	started := time.Now()
+	id := started.Format("20060102-150405.000") + "-" + strings.ToLower(conn.Type)
+	if err := os.MkdirAll(captureDir(), 0o755); err != nil {
+		a.log("capture").Warn("capture disabled", "err", err)
+		return
+	}
+	meta := capture.Meta{
+		Started:    started,
+		Connection: conn.Type,
+		Target:     target,
+	}
+	w, err := a.createCapture(filepath.Join(captureDir(), id+capture.Ext), meta)
+	if err != nil {
+		a.log("capture").Warn("capture disabled", "err", err)
+		return
+	}
+
+	a.captureMutex.Lock()
+	a.capture = w
+	a.captureID = id
+	a.captureMeta = meta
+	a.captureMutex.Unlock()
+	go a.cleanupStorage()
+}
+
+// stopCapture 结束当前抓包
+func (a *App) stopCapture() {
+	a.captureMutex.Lock()
+	defer a.captureMutex.Unlock()
+	if a.capture != nil {
+		a.capture.Close()
+		a.capture = nil
+		a.captureID = ""
+	}
+}
+
+// flushCapture 将当前抓包的缓冲写入磁盘
+func (a *App) flushCapture() {
+	a.captureMutex.Lock()
+	defer a.captureMutex.Unlock()
+	if a.capture != nil {
+		a.capture.Flush()
+	}
+}
+
+// recordCapture 将收发数据写入当前抓包文件
+func (a *App) recordCapture(stage *pipeline.Stage) {
+	ticker := time.NewTicker(captureFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case ev, ok := <-stage.C():
+			if !ok {
+				a.stopCapture()
+				return
+			}
+			buf, ok := ev.Payload.(*bufpool.Buffer)
+			if !ok {
+				continue
+			}
+			done := stage.Begin()
+			dir := capture.RX
+			if ev.Topic == EventDataSent {
+				dir = capture.TX
+			}
+			stage.Guard(func() {
+				a.captureMutex.Lock()
+				defer a.captureMutex.Unlock()
+				if a.capture != nil {
+					a.capture.Write(a.gpsClock.Apply(ev.Time), dir, buf.B)
+				}
+			})
+			buf.Release()
+			done()
+		case <-ticker.C:
+			a.flushCapture()
+		}
+	}
+}
+
+// captureInfo 按 ID 查找抓包文件
+func (a *App) captureInfo(id string) (capture.Info, *apperr.Error) {
+	if id == "" || filepath.Base(id) != id {
+		return capture.Info{}, apperr.New(apperr.CodeInvalidArgument, id)
+	}
+	list, err := capture.ListWith(captureDir(), a.capturePassphrases)
+	if err != nil {
+		return capture.Info{}, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	for _, info := range list {
+		if info.ID == id {
+			return info, nil
+		}
+	}
+	return capture.Info{}, apperr.New(apperr.CodeNotFound, id)
+}
+
+// ListCaptures 列出已保存的抓包，按时间从新到旧排序
+func (a *App) ListCaptures() ([]capture.Info, error) {
+	list, err := capture.ListWith(captureDir(), a.capturePassphrases)
+	if err != nil {
+		return nil, err
+	}
+	a.captureMutex.Lock()
+	active := a.captureID
+	a.captureMutex.Unlock()
+	for i := range list {
+		list[i].Active = list[i].ID == active
+		list[i].Bookmarked = a.isBookmarked(list[i].ID)
+	}
+	return list, nil
+}
+
+// ExportCapture 将抓包流式导出为 csv / hex / pcap / raw 文件，进度通过 "operation-progress" 事件通知
+// 启用脱敏规则时导出内容按行遮盖 (见 SetRedaction)
+// 导出文件旁另存 <文件名>.manifest.json，记录来源抓包、会话标题与设备信息
+func (a *App) ExportCapture(id string, format string, destPath string) apperr.Result {
+	info, e := a.captureInfo(id)
+	if e != nil {
+		return apperr.FromError(e)
+	}
+	a.flushCapture()
+
+	r, err := capture.OpenWith(info.Path, a.capturePassphrases)
+	if err != nil {
+		return apperr.FromError(captureOpenError(err))
+	}
+	defer r.Close()
+	red := a.activeRedactor()
+	if red != nil {
+		r.SetFilter(newRedactFilter(red))
+	}
+
+	out, err := os.Create(destPath)
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+
+	op := a.startOperation("export", 0)
+	defer a.ops.Finish(op)
+	err = capture.Export(op.Context(), r, capture.Format(format), out, func(done, total int64) {
+		op.Report("export", done, total)
+	})
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = capture.WriteManifest(destPath, capture.Manifest{
+			Capture:  id,
+			File:     filepath.Base(destPath),
+			Format:   capture.Format(format),
+			Exported: time.Now(),
+			Meta:     r.Meta(),
+			Redacted: red != nil,
+		})
+	}
+	if err != nil {
+		os.Remove(destPath)
+		os.Remove(capture.ManifestPath(destPath))
+		op.Fail(err)
+		switch {
+		case op.Context().Err() != nil:
+			return apperr.FromError(contextError(op.Context()))
+		case err == capture.ErrUnknownFormat:
+			return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, fmt.Errorf("%w: %s", err, format)))
+		}
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// DeleteCapture 删除抓包文件，正在写入的抓包不能删除
+func (a *App) DeleteCapture(id string) apperr.Result {
+	info, e := a.captureInfo(id)
+	if e != nil {
+		return apperr.FromError(e)
+	}
+	a.captureMutex.Lock()
+	active := a.captureID == id
+	a.captureMutex.Unlock()
+	if active {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, "capture in use: "+id))
+	}
+	a.closeCaptureView(id)
+	if err := os.Remove(info.Path); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.FromError(a.setBookmark(id, false))
+}
+
+// captureView 返回抓包的内存映射视图，同一时间只缓存一个
+// 正在写入的抓包文件变大后会重新映射
+func (a *App) captureView(id string) (*capture.View, error) {
+	info, e := a.captureInfo(id)
+	if e != nil {
+		return nil, e
+	}
+	a.flushCapture()
+	if st, err := os.Stat(info.Path); err == nil {
+		info.Size = st.Size()
+	}
+
+	if a.view != nil && a.viewID == id && a.viewSize == info.Size {
+		return a.view, nil
+	}
+	if a.view != nil {
+		a.view.Close()
+		a.view = nil
+	}
+	v, err := capture.OpenViewWith(info.Path, a.capturePassphrases)
+	if err != nil {
+		return nil, captureOpenError(err)
+	}
+	a.view, a.viewID, a.viewSize = v, id, info.Size
+	return v, nil
+}
+
+// closeCaptureView 关闭指定抓包的视图 (Windows 下映射中的文件无法删除)
+func (a *App) closeCaptureView(id string) {
+	a.viewMutex.Lock()
+	defer a.viewMutex.Unlock()
+	if a.view != nil && a.viewID == id {
+		a.view.Close()
+		a.view = nil
+	}
+}
+
+// GetCaptureLineCount 返回抓包中接收数据的行数
+func (a *App) GetCaptureLineCount(id string) (int, error) {
+	a.viewMutex.Lock()
+	defer a.viewMutex.Unlock()
+	v, err := a.captureView(id)
+	if err != nil {
+		return 0, err
+	}
+	return v.Lines(), nil
+}
+
+// ReadCaptureLines 读取抓包中从 start 开始的 count 行接收数据，用于滚动浏览大文件
+func (a *App) ReadCaptureLines(id string, start int, count int) ([]string, error) {
+	a.viewMutex.Lock()
+	defer a.viewMutex.Unlock()
+	v, err := a.captureView(id)
+	if err != nil {
+		return nil, err
+	}
+	return v.ReadLines(start, count), nil
+}
+
+// SearchCapture 在抓包的接收数据中搜索文本，返回匹配的行号 (maxResults <= 0 表示不限)
+func (a *App) SearchCapture(id string, text string, maxResults int) ([]int, error) {
+	a.viewMutex.Lock()
+	defer a.viewMutex.Unlock()
+	v, err := a.captureView(id)
+	if err != nil {
+		return nil, err
+	}
+
+	op := a.startOperation("search", 0)
+	defer a.ops.Finish(op)
+	matches, err := v.Search(op.Context(), []byte(text), maxResults)
+	if err != nil {
+		return nil, contextError(op.Context())
+	}
+	return matches, nil
+}
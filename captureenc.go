@@ -0,0 +1,151 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"path/filepath"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/capture"
+	"serial-assistant/pkg/secrets"
+)
+
+// captureEncryptionFile 抓包加密设置文件名 (位于应用数据目录)
+const captureEncryptionFile = "capture-encryption.json"
+
+// captureKeySecret 钥匙串模式下抓包密钥在钥匙串中的名称
+const captureKeySecret = "capture/key"
+
+// CaptureEncryptionStatus 抓包加密设置及本次运行的解锁状态
+type CaptureEncryptionStatus struct {
+	Mode     capture.EncryptionMode `json:"mode"`
+	Unlocked bool                   `json:"unlocked"` // 本次运行已输入口令
+}
+
+// loadCaptureEncryption 读取抓包加密设置
+func (a *App) loadCaptureEncryption() {
+	cfg, err := capture.LoadEncryption(filepath.Join(appDataDir(), captureEncryptionFile))
+	if err != nil {
+		a.log("capture").Warn("capture encryption config not loaded", "err", err)
+		return
+	}
+	a.captureMutex.Lock()
+	a.captureEnc = cfg
+	a.captureMutex.Unlock()
+}
+
+// captureKey 取出钥匙串中的抓包密钥，create 为 true 且不存在时生成一个
+func (a *App) captureKey(create bool) (string, error) {
+	key, err := a.secrets.Get(captureKeySecret)
+	if !create || !errors.Is(err, secrets.ErrNotFound) {
+		return key, err
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	key = base64.StdEncoding.EncodeToString(raw)
+	return key, a.secrets.Set(captureKeySecret, key)
+}
+
+// capturePassphrases 返回解密抓包时依次尝试的口令：本次运行输入的口令、钥匙串中的密钥
+func (a *App) capturePassphrases() ([]string, error) {
+	var list []string
+	a.captureMutex.Lock()
+	if a.capturePass != "" {
+		list = append(list, a.capturePass)
+	}
+	a.captureMutex.Unlock()
+	if key, err := a.captureKey(false); err == nil {
+		list = append(list, key)
+	}
+	return list, nil
+}
+
+// createCapture 按加密设置创建抓包文件，需要加密但没有可用的口令时不写明文，返回错误
+func (a *App) createCapture(path string, meta capture.Meta) (*capture.Writer, error) {
+	a.captureMutex.Lock()
+	mode, pass := a.captureEnc.Mode, a.capturePass
+	a.captureMutex.Unlock()
+	switch mode {
+	case capture.EncryptionPassphrase:
+		return capture.CreateEncrypted(path, meta, pass)
+	case capture.EncryptionKeychain:
+		key, err := a.captureKey(true)
+		if err != nil {
+			return nil, err
+		}
+		return capture.CreateEncrypted(path, meta, key)
+	}
+	return capture.Create(path, meta)
+}
+
+// captureOpenError 将抓包打开错误转换为结构化错误
+func captureOpenError(err error) error {
+	if errors.Is(err, capture.ErrEncrypted) || errors.Is(err, capture.ErrWrongPassphrase) {
+		return apperr.Wrap(apperr.CodePermissionDenied, err)
+	}
+	return apperr.Wrap(apperr.CodeInternal, err)
+}
+
+// GetCaptureEncryption 返回抓包加密设置
+func (a *App) GetCaptureEncryption() CaptureEncryptionStatus {
+	a.captureMutex.Lock()
+	defer a.captureMutex.Unlock()
+	return CaptureEncryptionStatus{Mode: a.captureEnc.Mode, Unlocked: a.capturePass != ""}
+}
+
+// SetCaptureEncryption 设置新抓包文件的加密方式 (AES-256-GCM)，从下一次连接开始生效
+// mode 为 "passphrase" 时 passphrase 必填，口令只保存在内存中，重启后需调用 UnlockCaptures；
+// mode 为 "keychain" 时生成随机密钥保存在系统钥匙串；已有的加密抓包仍可用原口令或密钥读取
+func (a *App) SetCaptureEncryption(mode string, passphrase string) apperr.Result {
+	cfg := capture.EncryptionConfig{Mode: capture.EncryptionMode(mode)}
+	if err := cfg.Validate(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	switch cfg.Mode {
+	case capture.EncryptionPassphrase:
+		if passphrase == "" {
+			return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, "passphrase"))
+		}
+	case capture.EncryptionKeychain:
+		if _, err := a.captureKey(true); err != nil {
+			return apperr.FromError(secretError(err))
+		}
+	}
+	if err := capture.SaveEncryption(filepath.Join(appDataDir(), captureEncryptionFile), cfg); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	a.captureMutex.Lock()
+	a.captureEnc = cfg
+	if passphrase != "" {
+		a.capturePass = passphrase
+	}
+	a.captureMutex.Unlock()
+	return apperr.OK()
+}
+
+// UnlockCaptures 输入本次运行用于读写加密抓包的口令 (浏览、搜索、导出时透明解密)
+func (a *App) UnlockCaptures(passphrase string) apperr.Result {
+	if passphrase == "" {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, "passphrase"))
+	}
+	a.captureMutex.Lock()
+	a.capturePass = passphrase
+	a.captureMutex.Unlock()
+	return apperr.OK()
+}
+
+// LockCaptures 清除内存中的口令并关闭已解密的抓包视图
+func (a *App) LockCaptures() {
+	a.captureMutex.Lock()
+	a.capturePass = ""
+	a.captureMutex.Unlock()
+	a.viewMutex.Lock()
+	if a.view != nil {
+		a.view.Close()
+		a.view = nil
+	}
+	a.viewMutex.Unlock()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/console"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/pipeline"
+)
+
+// defaultCommandTimeout 等待提示符的默认最长时间
+const defaultCommandTimeout = 5 * time.Second
+
+// feedConsole 将接收数据交给控制台会话查找提示符
+func (a *App) feedConsole(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		buf, ok := ev.Payload.(*bufpool.Buffer)
+		if !ok {
+			return
+		}
+		a.console.Feed(buf.B)
+		buf.Release()
+	})
+}
+
+// consoleError 将命令执行错误转换为结构化错误
+func consoleError(ctx context.Context, err error) *apperr.Error {
+	var e *apperr.Error
+	switch {
+	case errors.As(err, &e):
+		return e
+	case ctx.Err() != nil:
+		return contextError(ctx)
+	case errors.Is(err, context.DeadlineExceeded):
+		return apperr.Wrap(apperr.CodeTimeout, err)
+	case errors.Is(err, console.ErrTooLong):
+		return apperr.Wrap(apperr.CodeProbeFailed, err)
+	}
+	return apperr.Wrap(apperr.CodeInvalidArgument, err)
+}
+
+// ExecuteCommand 展开命令中的会话变量 ({{var.NAME}}) 后发送并返回直到下一个提示符之前的输出 (不含命令回显)，用于脚本化操作 U-Boot、Zephyr shell、Linux 控制台
+// promptRegex 为提示符的正则表达式，空时匹配以 "# "、"$ "、"> " 结尾的常见提示符；timeoutMs 为 0 时等待 5 秒，可通过 Cancel 取消
+func (a *App) ExecuteCommand(cmd string, promptRegex string, timeoutMs int) (console.Result, error) {
+	timeout := time.Duration(timeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultCommandTimeout
+	}
+	cmd, err := a.expandVars(cmd)
+	if err != nil {
+		return console.Result{}, err
+	}
+	op := a.startOperation("command", timeout)
+	defer a.ops.Finish(op)
+	r, err := a.console.Execute(op.Context(), cmd, promptRegex)
+	if err != nil {
+		return r, consoleError(op.Context(), err)
+	}
+	return r, nil
+}
+
+// CommandBatch 批量执行的命令及默认设置
+type CommandBatch struct {
+	Commands        []console.Step  `json:"commands"`
+	Prompt          string          `json:"prompt"`           // 各命令默认的提示符正则，空时匹配常见提示符
+	TimeoutMs       int             `json:"timeoutMs"`        // 各命令默认的超时，0 为 5 秒
+	ContinueOnError bool            `json:"continueOnError"`  // 某条命令失败后是否继续执行后续命令
+	StopOn          *StopConditions `json:"stopOn,omitempty"` // 满足时中止批处理 (nil 表示不监视)
+}
+
+// RunCommandBatch 依次执行多条命令，返回每条命令的输出、耗时与匹配到的提示符，用于设备配置与测试流程
+// 进度通过 "operation-progress" 事件通知，可通过 Cancel 取消，满足 StopOn 时自动中止；出错时仍返回已执行命令的结果
+func (a *App) RunCommandBatch(batch CommandBatch) ([]console.Result, error) {
+	if len(batch.Commands) == 0 {
+		return nil, apperr.New(apperr.CodeInvalidArgument, "")
+	}
+	timeout := time.Duration(batch.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = defaultCommandTimeout
+	}
+	steps := make([]console.Step, len(batch.Commands))
+	for i, step := range batch.Commands {
+		cmd, err := a.expandVars(step.Command)
+		if err != nil {
+			return nil, err
+		}
+		step.Command = cmd
+		steps[i] = step
+	}
+	op := a.startOperation("command-batch", 0)
+	defer a.ops.Finish(op)
+	watched, err := a.watchStop("command-batch", batch.StopOn, func() { a.ops.Cancel(op.ID) })
+	if err != nil {
+		return nil, err
+	}
+	total := int64(len(batch.Commands))
+	results, err := a.console.Batch(op.Context(), steps, batch.Prompt, timeout, batch.ContinueOnError, func(done int, _ console.Result) {
+		op.Report("command-batch", int64(done), total)
+	})
+	if stopped := watched(); stopped != nil && err != nil {
+		return results, stopped.err()
+	}
+	if err != nil {
+		return results, consoleError(op.Context(), err)
+	}
+	return results, nil
+}
+
+// SetCommandLineEnding 设置 ExecuteCommand 在命令后追加的行结束符 (如 "\r")，为空时使用 "\n"
+func (a *App) SetCommandLineEnding(ending string) {
+	a.console.SetLineEnding(ending)
+}
+
+// GetCommandLineEnding 返回 ExecuteCommand 使用的行结束符
+func (a *App) GetCommandLineEnding() string {
+	return a.console.LineEnding()
+}
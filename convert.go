@@ -0,0 +1,20 @@
+package main
+
+import (
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/convert"
+)
+
+// InterpretBytes 将选中的字节 (十六进制字符串) 按各种数值类型、字节序、ASCII、BCD 与 Unix 时间戳解释，
+// 供界面的 "解释为" 面板使用
+func (a *App) InterpretBytes(hexData string) ([]convert.Value, error) {
+	data, err := convert.ParseHex(hexData)
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	values, err := convert.Interpret(data)
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	return values, nil
+}
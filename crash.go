@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/crashlog"
+	"serial-assistant/pkg/pipeline"
+)
+
+// 崩溃报告的配置文件与目录 (位于应用数据目录)
+const (
+	crashConfigFile = "crash.json"
+	crashDirName    = "crashes"
+)
+
+// crashTickInterval 检查崩溃输出是否已停止的间隔
+const crashTickInterval = 250 * time.Millisecond
+
+// loadCrashReports 启动时加载崩溃检测配置与报告索引
+func (a *App) loadCrashReports() {
+	cfg, err := crashlog.LoadConfig(filepath.Join(appDataDir(), crashConfigFile))
+	if err == nil {
+		err = a.crash.SetConfig(cfg)
+	}
+	if err != nil {
+		a.log("crash").Warn("crash detection config not loaded", "err", err)
+	}
+	store, err := crashlog.Open(filepath.Join(appDataDir(), crashDirName))
+	if err != nil {
+		a.log("crash").Warn("crash reports not loaded", "err", err)
+		return
+	}
+	a.crashMutex.Lock()
+	a.crashReports = store
+	a.crashMutex.Unlock()
+}
+
+// detectCrashes 在接收数据中检测崩溃输出，收集完成后保存为崩溃报告
+func (a *App) detectCrashes(stage *pipeline.Stage) {
+	ticker := time.NewTicker(crashTickInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case ev, ok := <-stage.C():
+			if !ok {
+				return
+			}
+			buf, ok := ev.Payload.(*bufpool.Buffer)
+			if !ok {
+				continue
+			}
+			done := stage.Begin()
+			stage.Guard(func() {
+				for _, c := range a.crash.Feed(ev.Time, buf.B) {
+					a.saveCrash(c)
+				}
+			})
+			buf.Release()
+			done()
+		case now := <-ticker.C:
+			if c := a.crash.Tick(now); c != nil {
+				a.saveCrash(*c)
+			}
+		}
+	}
+}
+
+// flushCrash 连接断开时保存正在收集的崩溃
+func (a *App) flushCrash() {
+	if c := a.crash.Flush(); c != nil {
+		a.saveCrash(*c)
+	}
+}
+
+// saveCrash 附上连接、抓包与设备信息保存崩溃报告，并发布 "crash-detected" 事件
+func (a *App) saveCrash(c crashlog.Crash) {
+	a.crashMutex.Lock()
+	store := a.crashReports
+	a.crashMutex.Unlock()
+	if store == nil {
+		return
+	}
+
+	var r crashlog.Report
+	if conn := a.currentConnection(); conn != nil {
+		r.Connection = conn.Type + " " + connectionTarget(conn)
+	}
+	a.captureMutex.Lock()
+	r.Capture = a.captureID
+	a.captureMutex.Unlock()
+	m := a.banner.Metadata()
+	r.Title = m.Title
+	r.Device = m.Fields
+
+	r, err := store.Save(c, r)
+	if err != nil {
+		a.log("crash").Warn("crash report not saved", "err", err)
+		return
+	}
+	a.crashTotal.Add(1)
+	a.bus.Publish(EventCrashDetected, r)
+}
+
+// crashStore 返回崩溃报告库，加载失败时返回错误
+func (a *App) crashStore() (*crashlog.Store, error) {
+	a.crashMutex.Lock()
+	defer a.crashMutex.Unlock()
+	if a.crashReports == nil {
+		return nil, apperr.New(apperr.CodeInternal, "crash reports not loaded")
+	}
+	return a.crashReports, nil
+}
+
+// GetCrashConfig 返回崩溃检测配置
+func (a *App) GetCrashConfig() crashlog.Config {
+	return a.crash.Config()
+}
+
+// SetCrashConfig 设置并保存崩溃检测配置：命中特征的行连同之前 before 行、之后最多 after 行
+// (或直到输出静默 quietMs) 一起保存为崩溃报告
+func (a *App) SetCrashConfig(cfg crashlog.Config) apperr.Result {
+	if err := a.crash.SetConfig(cfg); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	if err := crashlog.SaveConfig(filepath.Join(appDataDir(), crashConfigFile), cfg); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// ListCrashReports 列出保存的崩溃报告，从新到旧排序
+func (a *App) ListCrashReports() ([]crashlog.Report, error) {
+	store, err := a.crashStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.List(), nil
+}
+
+// ReadCrashReport 返回崩溃报告的正文
+func (a *App) ReadCrashReport(id string) (string, error) {
+	store, err := a.crashStore()
+	if err != nil {
+		return "", err
+	}
+	text, err := store.Read(id)
+	switch {
+	case errors.Is(err, crashlog.ErrNotFound):
+		return "", apperr.New(apperr.CodeNotFound, id)
+	case err != nil:
+		return "", apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return text, nil
+}
+
+// DeleteCrashReport 删除崩溃报告
+func (a *App) DeleteCrashReport(id string) apperr.Result {
+	store, err := a.crashStore()
+	if err != nil {
+		return apperr.FromError(err)
+	}
+	if err := store.Delete(id); err != nil {
+		if errors.Is(err, crashlog.ErrNotFound) {
+			return apperr.FromError(apperr.New(apperr.CodeNotFound, id))
+		}
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/decoder"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/framestats"
+	"serial-assistant/pkg/packetdef"
+	"serial-assistant/pkg/pipeline"
+	"serial-assistant/pkg/scale"
+)
+
+// detectSampleSize 连接后用于协议检测的样本大小
+const detectSampleSize = 512
+
+// defaultGapThreshold 接收停顿超过该时长时在帧流中插入间隔标记
+const defaultGapThreshold = time.Second
+
+// defaultLineTimeout 行模式下部分行的默认空闲超时
+const defaultLineTimeout = 200 * time.Millisecond
+
+// decodeFrames 将接收数据交给启用的解码器，解出的帧以 "frame-decoded" 事件发布
+// barcode 解码器的扫码结果 (已去除重复读取) 另外以 "scan" 事件发布
+// 连接后的前 detectSampleSize 字节同时用于协议检测
+func (a *App) decodeFrames(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		buf, ok := ev.Payload.(*bufpool.Buffer)
+		if !ok {
+			return
+		}
+		if gap, ok := a.gaps.Check(ev.Time); ok {
+			a.bus.Publish(EventFrameDecoded, gap)
+		}
+		a.sampleProtocol(buf.B)
+		if a.lineMode.Load() {
+			a.lines.Feed(ev.Time, buf.B)
+		}
+		a.decoders.Decode(ev.Time, buf.B, func(f decoder.Frame) {
+			a.bus.Publish(EventFrameDecoded, f)
+			if scan, ok := decoder.AsScan(f); ok {
+				a.bus.Publish(EventScan, scan)
+			}
+		})
+		buf.Release()
+	})
+}
+
+// sampleProtocol 采满样本后发布 "protocol-detected" 事件，开启自动启用时启用检测到的解码器
+func (a *App) sampleProtocol(data []byte) {
+	a.detectMutex.Lock()
+	det, ok := a.detector.Add(data)
+	autoEnable := a.autoEnableDecoder
+	a.detectMutex.Unlock()
+	if !ok {
+		return
+	}
+	a.bus.Publish(EventProtocolDetected, det)
+	if autoEnable && det.Protocol != "" {
+		a.decoders.Enable(det.Protocol)
+	}
+}
+
+// resetDetector 新连接建立后重新采样
+func (a *App) resetDetector() {
+	a.detectMutex.Lock()
+	a.detector.Reset()
+	a.detectMutex.Unlock()
+}
+
+// SetAutoDetect 设置检测到协议后是否自动启用对应的解码器 (否则只发布建议)
+func (a *App) SetAutoDetect(autoEnable bool) apperr.Result {
+	a.detectMutex.Lock()
+	a.autoEnableDecoder = autoEnable
+	a.detectMutex.Unlock()
+	return apperr.OK()
+}
+
+// DetectProtocol 立即用最近接收的数据检测协议
+func (a *App) DetectProtocol() decoder.Detection {
+	return decoder.Detect(a.rxSince(0))
+}
+
+// ListDecoders 返回可用的协议解码器 (内置、插件及自定义帧定义)
+func (a *App) ListDecoders() []decoder.Info {
+	return decoder.List()
+}
+
+// GetEnabledDecoders 返回当前会话启用的解码器
+func (a *App) GetEnabledDecoders() []string {
+	return a.decoders.Names()
+}
+
+// EnableDecoder 为当前会话启用解码器
+func (a *App) EnableDecoder(name string) apperr.Result {
+	if err := a.decoders.Enable(name); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeNotFound, err))
+	}
+	return apperr.OK()
+}
+
+// DisableDecoder 为当前会话停用解码器
+func (a *App) DisableDecoder(name string) apperr.Result {
+	a.decoders.Disable(name)
+	return apperr.OK()
+}
+
+// LoadDecoderPlugin 加载 Go 插件形式的第三方解码器 (仅 Linux/macOS)
+func (a *App) LoadDecoderPlugin(path string) apperr.Result {
+	info, err := decoder.LoadPlugin(path)
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	a.log("decoder").Info("decoder plugin loaded", "name", info.Name, "path", path)
+	return apperr.OK()
+}
+
+// LoadPacketDefinitions 从 JSON 文件加载自定义帧定义并注册为解码器
+func (a *App) LoadPacketDefinitions(path string) apperr.Result {
+	defs, err := packetdef.Load(path)
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	for _, d := range defs {
+		if err := packetdef.Register(d); err != nil {
+			return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+		}
+	}
+	return apperr.OK()
+}
+
+// LoadScaleProfiles 从 JSON 文件加载电子秤格式配置并注册为解码器
+// 内置 scale_ad、scale_toledo、scale_sics，自定义配置可用正则表达式描述连续输出格式
+func (a *App) LoadScaleProfiles(path string) apperr.Result {
+	profiles, err := scale.Load(path)
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	for _, p := range profiles {
+		if err := scale.Register(p); err != nil {
+			return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+		}
+	}
+	return apperr.OK()
+}
+
+// SetGapThreshold 设置接收停顿标记的阈值 (毫秒)，0 表示不标记
+func (a *App) SetGapThreshold(ms int) apperr.Result {
+	if ms < 0 {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, ""))
+	}
+	a.gaps.SetThreshold(time.Duration(ms) * time.Millisecond)
+	return apperr.OK()
+}
+
+// GetGapThreshold 返回接收停顿标记的阈值 (毫秒)
+func (a *App) GetGapThreshold() int {
+	return int(a.gaps.Threshold().Milliseconds())
+}
+
+// LineModeConfig 行模式设置
+type LineModeConfig struct {
+	Enabled   bool `json:"enabled"`
+	TimeoutMs int  `json:"timeoutMs"` // 部分行的空闲超时，0 表示只在换行时输出
+}
+
+// SetLineMode 开启或关闭行模式：接收数据按换行组装为 "line" 帧，没有换行的提示符等部分行
+// 在空闲 timeoutMs 后也会输出并标记为不完整 (line.incomplete)，其后的剩余部分标记为续行 (line.continued)
+func (a *App) SetLineMode(cfg LineModeConfig) apperr.Result {
+	if cfg.TimeoutMs < 0 {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, ""))
+	}
+	a.lines.SetTimeout(time.Duration(cfg.TimeoutMs) * time.Millisecond)
+	if !cfg.Enabled && a.lineMode.Load() {
+		a.lines.Flush()
+	}
+	a.lineMode.Store(cfg.Enabled)
+	return apperr.OK()
+}
+
+// GetLineMode 返回行模式设置
+func (a *App) GetLineMode() LineModeConfig {
+	return LineModeConfig{Enabled: a.lineMode.Load(), TimeoutMs: int(a.lines.Timeout().Milliseconds())}
+}
+
+// countFrames 统计解码帧的类型与会话
+func (a *App) countFrames(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		if f, ok := ev.Payload.(decoder.Frame); ok {
+			a.frameStats.Add(&f)
+		}
+	})
+}
+
+// GetFrameStats 返回当前连接各帧类型的数量、字节数与错误率
+func (a *App) GetFrameStats() []framestats.TypeStats {
+	return a.frameStats.Types()
+}
+
+// GetConversations 返回按从站地址/节点划分的通信统计
+func (a *App) GetConversations() []framestats.Conversation {
+	return a.frameStats.Conversations()
+}
+
+// ResetFrameStats 清零帧统计
+func (a *App) ResetFrameStats() {
+	a.frameStats.Reset()
+}
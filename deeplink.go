@@ -0,0 +1,185 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/capture"
+	"serial-assistant/pkg/deeplink"
+	"serial-assistant/pkg/i18n"
+)
+
+// handoffFile 记录正在运行的实例接收链接的地址
+const handoffFile = "deeplink.json"
+
+func handoffPath() string {
+	return filepath.Join(appDataDir(), handoffFile)
+}
+
+// forwardLaunchLink 启动参数中带有链接或抓包文件时，尝试交给已运行的实例处理
+// 返回 true 表示已转交，当前进程应直接退出；否则链接留待本实例启动后处理
+func (a *App) forwardLaunchLink(args []string) bool {
+	l, ok := deeplink.FromArgs(args)
+	if !ok {
+		return false
+	}
+	if deeplink.Forward(handoffPath(), l) == nil {
+		return true
+	}
+	a.deepLinkMutex.Lock()
+	a.deepLink = &l
+	a.deepLinkMutex.Unlock()
+	return false
+}
+
+// listenDeepLinks 接收其他实例转交的链接
+func (a *App) listenDeepLinks() {
+	r, err := deeplink.Listen(handoffPath(), a.setDeepLink)
+	if err != nil {
+		return
+	}
+	a.deepLinkMutex.Lock()
+	a.deepLinkReceiver = r
+	a.deepLinkMutex.Unlock()
+}
+
+// closeDeepLinks 停止接收转交的链接
+func (a *App) closeDeepLinks() {
+	a.deepLinkMutex.Lock()
+	r := a.deepLinkReceiver
+	a.deepLinkReceiver = nil
+	a.deepLinkMutex.Unlock()
+	if r != nil {
+		r.Close()
+	}
+}
+
+// setDeepLink 记录待处理的链接并发布 "deep-link" 事件，由用户确认后调用 OpenDeepLink
+func (a *App) setDeepLink(l deeplink.Link) {
+	a.deepLinkMutex.Lock()
+	a.deepLink = &l
+	a.deepLinkMutex.Unlock()
+	a.bus.Publish(EventDeepLink, l)
+}
+
+// openURL macOS 通过系统事件传入的 URL
+func (a *App) openURL(raw string) {
+	l, err := deeplink.Parse(raw)
+	if err != nil {
+		a.bus.Publish(EventSysMsg, i18n.T("app.deeplink_invalid", err))
+		return
+	}
+	a.setDeepLink(l)
+}
+
+// openFile macOS 通过文件关联传入的抓包文件
+func (a *App) openFile(path string) {
+	if l, ok := deeplink.FromArgs([]string{path}); ok {
+		a.setDeepLink(l)
+	}
+}
+
+// GetPendingDeepLink 返回启动时或运行中收到的尚未处理的链接，没有时返回 nil
+func (a *App) GetPendingDeepLink() *deeplink.Link {
+	a.deepLinkMutex.Lock()
+	defer a.deepLinkMutex.Unlock()
+	return a.deepLink
+}
+
+// OpenDeepLink 处理待处理的链接：连接链接中的目标，或导入抓包文件并发布 "capture-opened" 事件
+func (a *App) OpenDeepLink() apperr.Result {
+	a.deepLinkMutex.Lock()
+	l := a.deepLink
+	a.deepLink = nil
+	a.deepLinkMutex.Unlock()
+	switch {
+	case l == nil:
+		return apperr.FromError(apperr.New(apperr.CodeNotFound, ""))
+	case l.Connection != nil:
+		return a.openConnection(l.Connection)
+	}
+	id, err := a.ImportCapture(l.Capture)
+	if err != nil {
+		return apperr.FromError(err)
+	}
+	a.bus.Publish(EventCaptureOpened, id)
+	return apperr.OK()
+}
+
+// DiscardDeepLink 放弃待处理的链接
+func (a *App) DiscardDeepLink() {
+	a.deepLinkMutex.Lock()
+	a.deepLink = nil
+	a.deepLinkMutex.Unlock()
+}
+
+// GetSessionLink 返回打开当前连接的链接，可贴到 wiki 或工单中
+func (a *App) GetSessionLink() (string, error) {
+	c := a.currentConnection()
+	if c == nil {
+		return "", apperr.New(apperr.CodeNotConnected, "")
+	}
+	return deeplink.Format(*c), nil
+}
+
+// RegisterURLScheme 为当前用户注册 serialassistant:// 协议与抓包文件关联 (Windows、Linux)
+// macOS 由应用包的 Info.plist 注册
+func (a *App) RegisterURLScheme() apperr.Result {
+	exe, err := os.Executable()
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	if err := deeplink.Register(exe); err != nil {
+		if errors.Is(err, deeplink.ErrBundled) {
+			return apperr.FromError(apperr.Wrap(apperr.CodeUnsupported, err))
+		}
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// ImportCapture 将抓包文件复制到抓包目录 (已在目录中时直接使用)，返回可用于 ReadCaptureLines 等的 ID
+func (a *App) ImportCapture(path string) (string, error) {
+	if filepath.Ext(path) != capture.Ext {
+		return "", apperr.New(apperr.CodeInvalidArgument, path)
+	}
+	dir := captureDir()
+	if abs, err := filepath.Abs(path); err == nil && filepath.Dir(abs) == dir {
+		return strings.TrimSuffix(filepath.Base(abs), capture.Ext), nil
+	}
+	src, err := os.Open(path)
+	if err != nil {
+		return "", apperr.Wrap(apperr.CodeNotFound, err)
+	}
+	defer src.Close()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", apperr.Wrap(apperr.CodeInternal, err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(path), capture.Ext)
+	id := base
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(dir, id+capture.Ext)); os.IsNotExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	dst, err := os.OpenFile(filepath.Join(dir, id+capture.Ext), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", apperr.Wrap(apperr.CodeInternal, err)
+	}
+	_, err = io.Copy(dst, src)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst.Name())
+		return "", apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return id, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime/debug"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/diagnostics"
+	"serial-assistant/pkg/i18n"
+)
+
+// diagnosticsDir 自动保存的诊断转储目录
+func diagnosticsDir() string {
+	return filepath.Join(appDataDir(), "diagnostics")
+}
+
+// CaptureDiagnostics 将 goroutine 栈、堆 profile 与最近的内部日志打包为 zip 写入 destPath，供附在问题报告中
+func (a *App) CaptureDiagnostics(destPath string) apperr.Result {
+	meta := diagnostics.NewMeta("manual", Version)
+	if err := diagnostics.WriteFile(destPath, meta, a.logRing.Entries()); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	return apperr.OK()
+}
+
+// ListDiagnosticDumps 列出后台从 panic 恢复时自动保存的诊断转储，最新的在前
+func (a *App) ListDiagnosticDumps() ([]diagnostics.DumpInfo, error) {
+	list, err := diagnostics.List(diagnosticsDir())
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return list, nil
+}
+
+// recoverPanic 须以 defer 调用：恢复后台 goroutine 中的 panic，保存诊断转储后执行 after
+func (a *App) recoverPanic(where string, after func()) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	a.savePanicDump(where, v, debug.Stack())
+	if after != nil {
+		after()
+	}
+}
+
+// savePanicDump 记录 panic 并自动保存诊断转储，在系统消息中给出转储位置
+func (a *App) savePanicDump(where string, v any, stack []byte) {
+	a.log("diagnostics").Error("panic recovered", "where", where, "panic", fmt.Sprint(v))
+	a.usage.Error("PANIC")
+	meta := diagnostics.NewMeta(where, Version)
+	meta.Panic, meta.Stack = fmt.Sprint(v), string(stack)
+	path, err := diagnostics.Save(diagnosticsDir(), meta, a.logRing.Entries())
+	if err != nil {
+		path = err.Error()
+	}
+	a.bus.Publish(EventSysMsg, i18n.T("app.backend_panic", where, path))
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/decoder"
+	"serial-assistant/pkg/filter"
+	"serial-assistant/pkg/framediff"
+	"serial-assistant/pkg/ratelimit"
+	"serial-assistant/pkg/sanitize"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// 前端显示限速的默认值：每秒最多 30 次更新，每次最多 256KB
+const (
+	defaultDisplayRate     = 30
+	defaultDisplayMaxBytes = 256 * 1024
+)
+
+// newDisplayLimiter 创建接收数据的显示限速器，合并后以 "serial-data" 事件发送给前端
+func (a *App) newDisplayLimiter() *ratelimit.Coalescer {
+	return ratelimit.New(defaultDisplayRate, defaultDisplayMaxBytes, func(data []byte) {
+		runtime.EventsEmit(a.ctx, string(EventSerialData), data)
+	})
+}
+
+// SetDisplayRateLimit 设置接收数据刷新到界面的频率上限
+// maxUpdatesPerSecond 为 0 表示不限速；maxBytesPerUpdate 为 0 表示不限制，超出部分只丢弃显示，不影响记录
+func (a *App) SetDisplayRateLimit(maxUpdatesPerSecond int, maxBytesPerUpdate int) apperr.Result {
+	if maxUpdatesPerSecond < 0 || maxBytesPerUpdate < 0 {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, ""))
+	}
+	a.display.SetRate(maxUpdatesPerSecond, maxBytesPerUpdate)
+	return apperr.OK()
+}
+
+// GetDisplayStats 返回显示限速的统计信息，包括因限速未显示的字节数
+func (a *App) GetDisplayStats() ratelimit.Stats {
+	return a.display.Stats()
+}
+
+// ResetDisplayStats 清零显示限速统计
+func (a *App) ResetDisplayStats() {
+	a.display.ResetStats()
+}
+
+// SetRxSanitize 设置接收数据在界面解码显示前的清理：删除 NUL、删除 UTF-8 BOM、将不可打印字符替换为占位符
+// 只影响显示，抓包与解码器仍收到原始字节；用于启动 ROM 输出乱码导致界面错乱的设备
+func (a *App) SetRxSanitize(opts sanitize.Options) apperr.Result {
+	a.sanitizer.SetOptions(opts)
+	return apperr.OK()
+}
+
+// GetRxSanitize 返回当前的接收数据清理选项
+func (a *App) GetRxSanitize() sanitize.Options {
+	return a.sanitizer.Options()
+}
+
+// GetRxSanitizeStats 返回已删除的 NUL、BOM 及已替换的字符数
+func (a *App) GetRxSanitizeStats() sanitize.Stats {
+	return a.sanitizer.Stats()
+}
+
+// ResetRxSanitizeStats 清零接收数据清理计数
+func (a *App) ResetRxSanitizeStats() {
+	a.sanitizer.ResetStats()
+}
+
+// SetDisplayFilter 设置解码帧的显示过滤表达式 (如 modbus.func == 3 && modbus.addr > 100)
+// 空表达式显示所有帧；过滤只影响发送给界面的帧，不影响抓包与统计
+func (a *App) SetDisplayFilter(expr string) apperr.Result {
+	f, err := filter.Compile(expr)
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	a.filterMutex.Lock()
+	a.displayFilter = f
+	a.filterMutex.Unlock()
+	return apperr.OK()
+}
+
+// GetDisplayFilter 返回当前的显示过滤表达式
+func (a *App) GetDisplayFilter() string {
+	a.filterMutex.RLock()
+	defer a.filterMutex.RUnlock()
+	return a.displayFilter.String()
+}
+
+// displayFrame 对发往界面的解码帧应用显示过滤与差异模式，返回 false 表示不显示
+func (a *App) displayFrame(f decoder.Frame) (decoder.Frame, bool) {
+	a.filterMutex.RLock()
+	defer a.filterMutex.RUnlock()
+	if !a.displayFilter.Match(&f) {
+		return f, false
+	}
+	if a.differ != nil {
+		return a.differ.Apply(f)
+	}
+	return f, true
+}
+
+// SetDiffMode 开启或关闭差异模式：重复出现的同类帧只显示与上一帧相比变化的字节，完全相同的帧不显示
+func (a *App) SetDiffMode(enabled bool) apperr.Result {
+	a.filterMutex.Lock()
+	defer a.filterMutex.Unlock()
+	switch {
+	case enabled && a.differ == nil:
+		a.differ = framediff.New()
+	case !enabled:
+		a.differ = nil
+	}
+	return apperr.OK()
+}
+
+// GetDiffSuppressed 返回差异模式下因与上一帧完全相同而未显示的帧数
+func (a *App) GetDiffSuppressed() uint64 {
+	a.filterMutex.RLock()
+	defer a.filterMutex.RUnlock()
+	if a.differ == nil {
+		return 0
+	}
+	return a.differ.Repeated()
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"time"
+
+	"serial-assistant/pkg/i18n"
+)
+
+// DryRunEntry 试运行模式下跳过的一次操作，随 EventDryRun 发送
+type DryRunEntry struct {
+	Operation string    `json:"operation"` // flash、plc-write、memory-write、memory-revert、probe-power、usb-reset
+	Target    string    `json:"target"`
+	Detail    string    `json:"detail,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// GetDryRun 返回是否处于试运行模式
+func (a *App) GetDryRun() bool {
+	return a.dryRun.Load()
+}
+
+// SetDryRun 开启或关闭试运行模式：刷写、PLC 与内存写入、探针供电及 USB 复位等会改变目标状态的操作
+// 只记录将要执行的内容而不实际执行，用于在真实硬件上运行前检查批处理脚本与构建钩子。
+// 普通的收发不受影响；设置不保存，重启后恢复为关闭
+func (a *App) SetDryRun(enabled bool) {
+	if a.dryRun.Swap(enabled) != enabled {
+		a.log("dry-run").Info("dry-run mode changed", "enabled", enabled)
+	}
+}
+
+// skipDryRun 试运行模式下记录将要执行的操作并返回 true，调用方应跳过实际操作
+func (a *App) skipDryRun(operation, target, detail string) bool {
+	if !a.dryRun.Load() {
+		return false
+	}
+	a.log("dry-run").Info("operation skipped", "operation", operation, "target", target, "detail", detail)
+	a.bus.Publish(EventDryRun, DryRunEntry{Operation: operation, Target: target, Detail: detail, Time: time.Now()})
+	a.bus.Publish(EventSysMsg, i18n.T("app.dry_run", operation, target, detail))
+	return true
+}
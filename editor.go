@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/convert"
+	"serial-assistant/pkg/editorrpc"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/journal"
+	"serial-assistant/pkg/pipeline"
+	"serial-assistant/pkg/secrets"
+	"serial-assistant/pkg/share"
+)
+
+// editorFile 编辑器接口配置文件名 (位于应用数据目录)
+const editorFile = "editor.json"
+
+// editorTokenKey 编辑器接口令牌在钥匙串中的名称
+const editorTokenKey = "editor/token"
+
+// EditorServerStatus 编辑器接口配置、地址 (未监听时为空) 及已连接的编辑器
+type EditorServerStatus struct {
+	editorrpc.Config
+	URL     string             `json:"url,omitempty"` // 含令牌的 WebSocket 地址，填入编辑器扩展
+	Clients []editorrpc.Client `json:"clients"`
+}
+
+// EditorOpenParams "session.open" 的参数：type 为 serial 时按串口参数打开，为 tcp-client 时连接 host:port
+type EditorOpenParams struct {
+	Type string `json:"type"`
+	SerialOptions
+	Host string `json:"host,omitempty"`
+}
+
+// EditorSendParams "session.send" 的参数
+type EditorSendParams struct {
+	Data string `json:"data"`
+	Hex  bool   `json:"hex"` // Data 为十六进制字符串
+}
+
+// EditorOutput "output" 通知的内容
+type EditorOutput struct {
+	Dir  string    `json:"dir"` // rx 或 tx
+	Time time.Time `json:"time"`
+	Data []byte    `json:"data"` // JSON 中为 base64
+	Text string    `json:"text"`
+}
+
+// loadEditorServer 读取编辑器接口配置并从钥匙串取出令牌，启用时开始监听
+// 旧版本保存在配置文件中的令牌会迁移到钥匙串，迁移成功后从文件中删除
+func (a *App) loadEditorServer() {
+	path := filepath.Join(appDataDir(), editorFile)
+	c, err := editorrpc.Load(path)
+	if err != nil {
+		a.log("editor").Warn("editor server config not loaded", "err", err)
+	}
+	if c.Token != "" {
+		if a.secrets.Set(editorTokenKey, c.Token) == nil {
+			stored := c
+			stored.Token = ""
+			editorrpc.Save(path, stored)
+		}
+	} else {
+		token, err := a.secrets.Get(editorTokenKey)
+		if err != nil && !errors.Is(err, secrets.ErrNotFound) {
+			a.log("editor").Warn("editor token unavailable", "err", err)
+		}
+		c.Token = token
+	}
+	a.editorMutex.Lock()
+	a.editorCfg = c
+	a.editorMutex.Unlock()
+	if c.Enabled && c.Token != "" {
+		if err := a.startEditorServer(c); err != nil {
+			a.log("editor").Warn("editor server not started", "addr", c.Addr(), "err", err)
+		}
+	}
+}
+
+// GetEditorServer 返回编辑器接口的配置、连接地址及已连接的编辑器
+func (a *App) GetEditorServer() EditorServerStatus {
+	a.editorMutex.Lock()
+	defer a.editorMutex.Unlock()
+	st := EditorServerStatus{Config: a.editorCfg, Clients: []editorrpc.Client{}}
+	if a.editor != nil {
+		st.URL = "ws://" + a.editor.Addr() + "/rpc?token=" + a.editorCfg.Token
+		st.Clients = a.editor.Clients()
+	}
+	return st
+}
+
+// SetEditorServer 设置并保存编辑器接口：启用后在 127.0.0.1 上提供 JSON-RPC 2.0 (WebSocket)，
+// 供编辑器扩展打开会话、发送命令并接收输出；令牌为空时自动生成，保存在系统钥匙串中
+func (a *App) SetEditorServer(c editorrpc.Config) (EditorServerStatus, error) {
+	if err := c.Validate(); err != nil {
+		return EditorServerStatus{}, apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	if c.Enabled && c.Token == "" {
+		c.Token = editorrpc.NewToken()
+	}
+	if c.Token != "" {
+		if err := a.secrets.Set(editorTokenKey, c.Token); err != nil {
+			return EditorServerStatus{}, secretError(err)
+		}
+	}
+	a.stopEditorServer()
+	if c.Enabled {
+		if err := a.startEditorServer(c); err != nil {
+			return EditorServerStatus{}, apperr.Wrap(apperr.CodeListenFailed, err)
+		}
+	}
+	stored := c
+	stored.Token = ""
+	if err := editorrpc.Save(filepath.Join(appDataDir(), editorFile), stored); err != nil {
+		return EditorServerStatus{}, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	a.editorMutex.Lock()
+	a.editorCfg = c
+	a.editorMutex.Unlock()
+	return a.GetEditorServer(), nil
+}
+
+// startEditorServer 开始监听编辑器连接
+func (a *App) startEditorServer(c editorrpc.Config) error {
+	srv := editorrpc.New(c.Token, a.editorMethods(), editorError)
+	if err := srv.Start(c.Addr()); err != nil {
+		return err
+	}
+	a.editorMutex.Lock()
+	a.editor = srv
+	a.editorMutex.Unlock()
+	a.log("editor").Info("editor server listening", "addr", srv.Addr())
+	return nil
+}
+
+// stopEditorServer 停止监听并断开所有编辑器
+func (a *App) stopEditorServer() {
+	a.editorMutex.Lock()
+	defer a.editorMutex.Unlock()
+	if a.editor != nil {
+		a.editor.Close()
+		a.editor = nil
+	}
+}
+
+// editorMethods 编辑器可调用的方法
+func (a *App) editorMethods() map[string]editorrpc.Method {
+	return map[string]editorrpc.Method{
+		"ports.list": func(context.Context, json.RawMessage) (any, error) {
+			return a.ListPorts()
+		},
+		"session.status": func(context.Context, json.RawMessage) (any, error) {
+			return struct {
+				Connection *journal.Connection `json:"connection"`
+			}{a.currentConnection()}, nil
+		},
+		"session.open": func(ctx context.Context, params json.RawMessage) (_ any, err error) {
+			defer func() { a.auditEditor(ctx, "session.open", params, err) }()
+			var p EditorOpenParams
+			if err := editorrpc.Params(params, &p); err != nil {
+				return nil, err
+			}
+			switch ConnectionType(p.Type) {
+			case "", TypeSerial:
+				return nil, resultError(a.OpenSerialWithOptions(p.SerialOptions))
+			case TypeTcpClient:
+				return nil, resultError(a.OpenTcpClient(p.Host, p.Port))
+			}
+			return nil, apperr.New(apperr.CodeInvalidArgument, "type: "+p.Type)
+		},
+		"session.reconfigure": func(ctx context.Context, params json.RawMessage) (_ any, err error) {
+			defer func() { a.auditEditor(ctx, "session.reconfigure", params, err) }()
+			var p SerialOptions
+			if err := editorrpc.Params(params, &p); err != nil {
+				return nil, err
+			}
+			return nil, resultError(a.ReconfigureSerial(p))
+		},
+		"session.close": func(ctx context.Context, params json.RawMessage) (_ any, err error) {
+			defer func() { a.auditEditor(ctx, "session.close", params, err) }()
+			return nil, resultError(a.Close())
+		},
+		"session.send": func(ctx context.Context, params json.RawMessage) (_ any, err error) {
+			var data []byte
+			defer func() { a.auditEditor(ctx, "session.send", data, err) }()
+			var p EditorSendParams
+			if err := editorrpc.Params(params, &p); err != nil {
+				return nil, err
+			}
+			data = []byte(p.Data)
+			if p.Hex {
+				if data, err = convert.ParseHex(p.Data); err != nil {
+					return nil, apperr.Wrap(apperr.CodeInvalidArgument, err)
+				}
+			}
+			op := a.startOperation("send", sendTimeout)
+			defer a.ops.Finish(op)
+			stop := context.AfterFunc(ctx, func() { a.ops.Cancel(op.ID) })
+			defer stop()
+			return nil, a.writeContext(op.Context(), data)
+		},
+	}
+}
+
+// auditEditor 将编辑器改变会话状态与发送数据的请求写入审计日志，与共享观看者的命令记在一起
+func (a *App) auditEditor(ctx context.Context, method string, data []byte, err error) {
+	c, _ := editorrpc.ClientFrom(ctx)
+	a.auditCommand(share.Viewer{ID: c.ID, Name: "editor", Remote: c.Remote}, share.Request{Type: "editor:" + method, Data: data}, err)
+}
+
+// resultError 将绑定方法的结果转换回结构化错误，成功时返回 nil
+func resultError(r apperr.Result) error {
+	if r.OK {
+		return nil
+	}
+	return &apperr.Error{Code: r.Code, Message: r.Message, Details: r.Details, Recoverable: r.Recoverable}
+}
+
+// editorError 将结构化错误转换为 JSON-RPC 错误，data 中带有应用的错误码
+func editorError(err error) *editorrpc.Error {
+	var e *apperr.Error
+	if !errors.As(err, &e) {
+		return nil
+	}
+	return &editorrpc.Error{Code: editorrpc.CodeServerError, Message: e.Error(), Data: struct {
+		Code        apperr.Code `json:"code"`
+		Details     string      `json:"details,omitempty"`
+		Recoverable bool        `json:"recoverable"`
+	}{e.Code, e.Details, e.Recoverable}}
+}
+
+// forwardToEditors 将收发数据、系统消息与读取错误推送给订阅了通知的编辑器
+func (a *App) forwardToEditors(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		a.editorMutex.Lock()
+		srv := a.editor
+		a.editorMutex.Unlock()
+		active := srv != nil && srv.Subscribers() > 0
+
+		switch p := ev.Payload.(type) {
+		case *bufpool.Buffer:
+			if active {
+				dir := "rx"
+				if ev.Topic == EventDataSent {
+					dir = "tx"
+				}
+				data := append([]byte(nil), p.B...)
+				srv.Notify("output", EditorOutput{Dir: dir, Time: ev.Time, Data: data, Text: string(data)})
+			}
+			p.Release()
+		case string:
+			if active {
+				method := "system"
+				if ev.Topic == EventSerialError {
+					method = "error"
+				}
+				srv.Notify(method, struct {
+					Time time.Time `json:"time"`
+					Text string    `json:"text"`
+				}{ev.Time, p})
+			}
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/powermeter"
+	"serial-assistant/pkg/series"
+
+	"go.bug.st/serial"
+)
+
+// 功率计读数发布到的时间序列
+const (
+	seriesCurrent = "power.current"
+	seriesVoltage = "power.voltage"
+	seriesPower   = "power.power"
+)
+
+// PowerMeterConfig 功率计设置
+type PowerMeterConfig struct {
+	Kind      powermeter.Kind `json:"kind"`
+	Port      string          `json:"port"`
+	BaudRate  int             `json:"baudRate"`  // 仅 INA226 转接板使用，0 为 115200
+	SourceMv  int             `json:"sourceMv"`  // PPK2：> 0 时以源表模式供电，否则为电流表模式
+	VoltageMv int             `json:"voltageMv"` // 读数不含电压时用于计算功率与能量的电压
+	AverageMs int             `json:"averageMs"` // 绘图序列的平均窗口，0 为 10ms (INA226 为逐条读数)
+}
+
+// PowerMeterStatus 功率计状态及累计的电荷与能量
+type PowerMeterStatus struct {
+	Open   bool             `json:"open"`
+	Config PowerMeterConfig `json:"config"`
+	powermeter.Energy
+}
+
+// OpenPowerMeter 打开功率计，电流、电压与功率作为 "power.*" 时间序列与控制台输出一同按主机时间记录
+// 支持 Nordic PPK2 与经串口输出读数的 INA226/INA219 转接板；Joulescope JS110 需要原生 USB 访问，暂不支持
+func (a *App) OpenPowerMeter(cfg PowerMeterConfig) apperr.Result {
+	if cfg.Port == "" || (cfg.Kind != powermeter.KindPPK2 && cfg.Kind != powermeter.KindINA226) {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, string(cfg.Kind)))
+	}
+	a.meterMutex.Lock()
+	defer a.meterMutex.Unlock()
+	if a.meterPort != nil {
+		return apperr.FromError(apperr.New(apperr.CodeAlreadyConnected, a.meterCfg.Port))
+	}
+	baud := cfg.BaudRate
+	if cfg.Kind == powermeter.KindPPK2 || baud <= 0 {
+		baud = 115200
+	}
+	port, err := a.openSerialContext(cfg.Port, &serial.Mode{BaudRate: baud, DataBits: 8, Parity: serial.NoParity, StopBits: serial.OneStopBit})
+	if err != nil {
+		return apperr.FromError(err)
+	}
+
+	avg := &powermeter.Averager{Window: time.Duration(cfg.AverageMs) * time.Millisecond}
+	switch cfg.Kind {
+	case powermeter.KindPPK2:
+		if cfg.AverageMs <= 0 {
+			avg.Window = 10 * time.Millisecond
+		}
+		port.SetReadTimeout(100 * time.Millisecond)
+		cal, err := powermeter.PPK2Start(port, cfg.SourceMv)
+		if err != nil {
+			port.Close()
+			return apperr.FromError(apperr.Wrap(apperr.CodeProbeFailed, err))
+		}
+		if cfg.SourceMv > 0 {
+			cfg.VoltageMv = cal.VDD
+		}
+		go a.readPPK2(port, cal, cfg, avg)
+	case powermeter.KindINA226:
+		go a.readINA(port, cfg, avg)
+	}
+	a.meterPort, a.meterCfg = port, cfg
+	a.meterEnergy = powermeter.Energy{}
+	return apperr.OK()
+}
+
+// ClosePowerMeter 停止测量并关闭功率计，累计值保留到下次打开
+func (a *App) ClosePowerMeter() apperr.Result {
+	a.meterMutex.Lock()
+	defer a.meterMutex.Unlock()
+	if a.meterPort == nil {
+		return apperr.OK()
+	}
+	if a.meterCfg.Kind == powermeter.KindPPK2 {
+		powermeter.PPK2Stop(a.meterPort)
+	}
+	err := a.meterPort.Close()
+	a.meterPort = nil
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeCloseFailed, err))
+	}
+	return apperr.OK()
+}
+
+// GetPowerMeterStatus 返回功率计状态、最新读数及自打开或清零以来的电荷 (C) 与能量 (J)
+func (a *App) GetPowerMeterStatus() (PowerMeterStatus, error) {
+	a.meterMutex.Lock()
+	defer a.meterMutex.Unlock()
+	return PowerMeterStatus{Open: a.meterPort != nil, Config: a.meterCfg, Energy: a.meterEnergy}, nil
+}
+
+// ResetPowerMeterEnergy 清零累计的电荷与能量，用于测量单个测试步骤的功耗
+func (a *App) ResetPowerMeterEnergy() apperr.Result {
+	a.meterMutex.Lock()
+	a.meterEnergy = powermeter.Energy{}
+	a.meterMutex.Unlock()
+	return apperr.OK()
+}
+
+// readPPK2 读取 PPK2 的采样流，按采样率倒推每个采样的主机时间
+func (a *App) readPPK2(port serial.Port, cal powermeter.PPK2Calibration, cfg PowerMeterConfig, avg *powermeter.Averager) {
+	dec := powermeter.PPK2Decoder{Cal: cal}
+	buf := make([]byte, 64*1024)
+	volts := float64(cfg.VoltageMv) / 1000
+	var err error
+	for {
+		var n int
+		n, err = port.Read(buf)
+		if err != nil {
+			break
+		}
+		if n == 0 {
+			if a.meterClosed(port) {
+				return
+			}
+			continue
+		}
+		currents := dec.Decode(buf[:n])
+		ts := powermeter.PPK2Timestamps(time.Now(), len(currents))
+		for i, c := range currents {
+			a.meterReading(powermeter.Reading{Time: ts(i), Current: c, Voltage: volts}, avg)
+		}
+	}
+	a.meterStopped(port, err)
+}
+
+// readINA 逐行读取 INA226/INA219 转接板的读数，无法解析的行 (启动信息等) 直接忽略
+func (a *App) readINA(port serial.Port, cfg PowerMeterConfig, avg *powermeter.Averager) {
+	scanner := bufio.NewScanner(port)
+	for scanner.Scan() {
+		r, ok := powermeter.ParseINALine(scanner.Text())
+		if !ok {
+			continue
+		}
+		r.Time = time.Now()
+		if r.Voltage == 0 {
+			r.Voltage = float64(cfg.VoltageMv) / 1000
+		}
+		a.meterReading(r, avg)
+	}
+	a.meterStopped(port, scanner.Err())
+}
+
+// meterReading 累计一个读数，并把平均后的读数发布为时间序列
+func (a *App) meterReading(r powermeter.Reading, avg *powermeter.Averager) {
+	out, ok := avg.Add(r)
+	if !ok {
+		return
+	}
+	a.meterMutex.Lock()
+	a.meterEnergy.Add(out)
+	a.meterMutex.Unlock()
+
+	a.bus.Publish(EventSeriesSample, series.Sample{Series: seriesCurrent, Time: out.Time, Value: out.Current, Unit: "A"})
+	if out.Voltage != 0 {
+		a.bus.Publish(EventSeriesSample, series.Sample{Series: seriesVoltage, Time: out.Time, Value: out.Voltage, Unit: "V"})
+		a.bus.Publish(EventSeriesSample, series.Sample{Series: seriesPower, Time: out.Time, Value: out.Power(), Unit: "W"})
+	}
+}
+
+// meterClosed port 是否已被 ClosePowerMeter 关闭
+func (a *App) meterClosed(port serial.Port) bool {
+	a.meterMutex.Lock()
+	defer a.meterMutex.Unlock()
+	return a.meterPort != port
+}
+
+// meterStopped 读取结束；不是主动关闭时记录原因并释放端口
+func (a *App) meterStopped(port serial.Port, err error) {
+	if !a.meterClosed(port) {
+		a.log("energy").Warn("power meter stopped", "err", err)
+		a.ClosePowerMeter()
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/hex"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/escpos"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/pipeline"
+)
+
+// escposStatusTimeout 等待单个 DLE EOT 应答的最长时间
+const escposStatusTimeout = 500 * time.Millisecond
+
+// EscPosJob 一次打印测试的内容，各项为空时跳过
+type EscPosJob struct {
+	Text      string `json:"text"`
+	Align     int    `json:"align"` // 0 左，1 中，2 右
+	Bold      bool   `json:"bold"`
+	Width     int    `json:"width"`  // 字符放大倍数 1~8
+	Height    int    `json:"height"` // 字符放大倍数 1~8
+	QR        string `json:"qr"`
+	ImagePath string `json:"imagePath"` // PNG 或 JPEG
+	Threshold int    `json:"threshold"` // 图像二值化阈值，0 表示 128
+	Feed      int    `json:"feed"`      // 结尾走纸行数
+	Cut       bool   `json:"cut"`
+}
+
+// feedEscPos 将接收数据交给打印机状态查询
+func (a *App) feedEscPos(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		buf, ok := ev.Payload.(*bufpool.Buffer)
+		if !ok {
+			return
+		}
+		a.escpos.Feed(buf.B)
+		buf.Release()
+	})
+}
+
+// renderEscPos 将打印任务转换为 ESC/POS 命令
+func renderEscPos(job EscPosJob) ([]byte, *apperr.Error) {
+	b := escpos.NewBuilder().Align(byte(job.Align)).Bold(job.Bold).Size(max(job.Width, 1), max(job.Height, 1))
+	if job.Text != "" {
+		b.Line(job.Text)
+	}
+	b.Bold(false).Size(1, 1)
+	if job.QR != "" {
+		b.QR(job.QR, 6, 'M').Feed(1)
+	}
+	if job.ImagePath != "" {
+		f, err := os.Open(job.ImagePath)
+		if err != nil {
+			return nil, apperr.Wrap(apperr.CodeNotFound, err)
+		}
+		img, _, err := image.Decode(f)
+		f.Close()
+		if err != nil {
+			return nil, apperr.Wrap(apperr.CodeInvalidArgument, err)
+		}
+		threshold := job.Threshold
+		if threshold <= 0 || threshold > 255 {
+			threshold = 128
+		}
+		b.Image(img, uint8(threshold))
+	}
+	b.Align(escpos.AlignLeft).Feed(job.Feed)
+	if job.Cut {
+		b.Cut(false)
+	}
+	return b.Bytes(), nil
+}
+
+// RenderEscPos 返回打印任务对应的命令 (十六进制)，用于发送前预览
+func (a *App) RenderEscPos(job EscPosJob) (string, error) {
+	data, e := renderEscPos(job)
+	if e != nil {
+		return "", e
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// PrintEscPos 将打印任务转换为 ESC/POS 命令并发送
+func (a *App) PrintEscPos(job EscPosJob) apperr.Result {
+	data, e := renderEscPos(job)
+	if e != nil {
+		return apperr.FromError(e)
+	}
+	op := a.startOperation("send", sendTimeout)
+	defer a.ops.Finish(op)
+	return apperr.FromError(a.writeContext(op.Context(), data))
+}
+
+// EscPosStatus 发送 DLE EOT 1~4 查询打印机的实时状态
+func (a *App) EscPosStatus() (escpos.Status, error) {
+	op := a.startOperation("escpos-status", 4*escposStatusTimeout)
+	defer a.ops.Finish(op)
+	s, err := a.escpos.Status(op.Context(), escposStatusTimeout)
+	if err != nil {
+		return s, scpiError(op.Context(), err)
+	}
+	return s, nil
+}
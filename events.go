@@ -0,0 +1,121 @@
+package main
+
+import (
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/decoder"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/pipeline"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// 事件主题，同时也是转发到前端的 Wails 事件名
+// EventSerialData 的负载为 *bufpool.Buffer，订阅者处理完后必须 Release
+const (
+	EventSerialData           eventbus.Topic = "serial-data"
+	EventSerialError          eventbus.Topic = "serial-error"
+	EventSysMsg               eventbus.Topic = "sys-msg"
+	EventOperationProgress    eventbus.Topic = "operation-progress"
+	EventDeviceIdentified     eventbus.Topic = "device-identified"
+	EventDataSent             eventbus.Topic = "data-sent" // 负载为 *bufpool.Buffer，不转发到前端
+	EventFrameDecoded         eventbus.Topic = "frame-decoded"
+	EventProtocolDetected     eventbus.Topic = "protocol-detected"
+	EventControlRequested     eventbus.Topic = "control-requested"
+	EventScan                 eventbus.Topic = "scan"                  // 负载为 decoder.Scan
+	EventSeriesSample         eventbus.Topic = "series-sample"         // 负载为 series.Sample
+	EventInstrumentIdentified eventbus.Topic = "instrument-identified" // 负载为 scpi.Identity
+	EventDeepLink             eventbus.Topic = "deep-link"             // 负载为 deeplink.Link
+	EventCaptureOpened        eventbus.Topic = "capture-opened"        // 负载为抓包 ID
+	EventSessionMetadata      eventbus.Topic = "session-metadata"      // 负载为 banner.Metadata
+	EventFirmwareChanged      eventbus.Topic = "firmware-changed"      // 负载为 fwdb.Change
+	EventCrashDetected        eventbus.Topic = "crash-detected"        // 负载为 crashlog.Report
+	EventBootLoop             eventbus.Topic = "boot-loop"             // 负载为 bootloop.Alert
+	EventSoakSnapshot         eventbus.Topic = "soak-snapshot"         // 负载为 soak.Snapshot
+	EventSoakFinished         eventbus.Topic = "soak-finished"         // 负载为 soak.Summary
+	EventJLinkLog             eventbus.Topic = "jlink-log"             // 负载为 jlink.LogEntry
+	EventAutoLogin            eventbus.Topic = "auto-login"            // 负载为 AutoLoginEvent
+	EventModemStatus          eventbus.Topic = "serial-modem-status"   // 负载为 ModemStatus
+	EventReconnect            eventbus.Topic = "serial-reconnect"      // 负载为 ReconnectStatus
+	EventPortAdded            eventbus.Topic = "port-added"            // 负载为 ports.Port
+	EventPortRemoved          eventbus.Topic = "port-removed"          // 负载为 ports.Port
+	EventAppLog               eventbus.Topic = "app-log"               // 负载为 diagnostics.Entry
+	EventWatchFolder          eventbus.Topic = "watch-folder"          // 负载为 WatchFolderEvent
+	EventBuildHook            eventbus.Topic = "build-hook"            // 负载为 buildhook.Result
+	EventSessionVars          eventbus.Topic = "session-vars"          // 负载为 []sessionvars.Var
+	EventAutomationStopped    eventbus.Topic = "automation-stopped"    // 负载为 AutomationStop
+	EventDryRun               eventbus.Topic = "dry-run"               // 负载为 DryRunEntry
+)
+
+// mainSession 当前连接所属的会话名
+const mainSession = "main"
+
+// 订阅队列长度
+const (
+	frontendQueueSize   = 4096
+	rxTailQueueSize     = 1024
+	captureQueueSize    = 4096
+	decoderQueueSize    = 4096
+	frameStatsQueueSize = 4096
+	rulesQueueSize      = 4096
+	shareQueueSize      = 4096
+	editorQueueSize     = 4096
+	wedgeQueueSize      = 1024
+	bannerQueueSize     = 1024
+	crashQueueSize      = 4096
+	bootLoopQueueSize   = 1024
+	scpiQueueSize       = 1024
+	consoleQueueSize    = 1024
+	autoLoginQueueSize  = 1024
+	escposQueueSize     = 1024
+	plcQueueSize        = 1024
+	seriesQueueSize     = 4096
+)
+
+// forwardToFrontend 将总线上的事件转发给 Wails 前端
+// 接收数据经显示限速器合并后再发送，避免高速数据源拖慢界面
+func (a *App) forwardToFrontend(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		if buf, ok := ev.Payload.(*bufpool.Buffer); ok {
+			if ev.Topic == EventSerialData {
+				a.display.Add(a.sanitizer.Apply(buf.B))
+			}
+			buf.Release()
+			return
+		}
+		if f, ok := ev.Payload.(decoder.Frame); ok {
+			if f, ok = a.displayFrame(f); ok {
+				runtime.EventsEmit(a.ctx, string(ev.Topic), f)
+			}
+			return
+		}
+		runtime.EventsEmit(a.ctx, string(ev.Topic), ev.Payload)
+	})
+}
+
+// trackRxTail 维护接收尾部缓存
+func (a *App) trackRxTail(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		buf, ok := ev.Payload.(*bufpool.Buffer)
+		if !ok {
+			return
+		}
+		a.rxMutex.Lock()
+		a.rxTail = append(a.rxTail, buf.B...)
+		if len(a.rxTail) > rxTailSize {
+			a.rxTail = append(a.rxTail[:0], a.rxTail[len(a.rxTail)-rxTailSize:]...)
+		}
+		a.rxTotal += uint64(len(buf.B))
+		a.rxMutex.Unlock()
+		buf.Release()
+	})
+}
+
+// subscribeStage 订阅总线并登记为主会话的一个处理阶段
+func (a *App) subscribeStage(name string, buffer int, topics ...eventbus.Topic) *pipeline.Stage {
+	return a.pipelines.Add(mainSession, name, a.bus.Subscribe(buffer, topics...))
+}
+
+// GetPipelineStats 返回各处理阶段的队列深度、丢弃数及处理耗时，用于定位处理慢的阶段
+func (a *App) GetPipelineStats() []pipeline.StageStats {
+	return a.pipelines.Stats()
+}
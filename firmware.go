@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/banner"
+	"serial-assistant/pkg/fwdb"
+)
+
+// firmwareFile 设备固件版本库文件名 (位于应用数据目录)
+const firmwareFile = "firmware.json"
+
+// loadFirmwareDB 启动时加载固件版本库
+func (a *App) loadFirmwareDB() {
+	db, err := fwdb.Open(filepath.Join(appDataDir(), firmwareFile))
+	if err != nil {
+		a.log("firmware").Warn("firmware history not loaded", "err", err)
+		return
+	}
+	a.firmwareMutex.Lock()
+	a.firmware = db
+	a.firmwareMutex.Unlock()
+}
+
+// scheduleFirmwareRecord 提取到固件版本后，等提取窗口结束 (设备名、序列号等也已输出) 再记录
+func (a *App) scheduleFirmwareRecord() {
+	a.firmwareMutex.Lock()
+	defer a.firmwareMutex.Unlock()
+	if a.firmwareTimer == nil {
+		a.firmwareTimer = time.AfterFunc(a.banner.Remaining(time.Now()), a.recordFirmware)
+	}
+}
+
+// cancelFirmwareRecord 新连接建立时取消上一会话尚未执行的记录
+func (a *App) cancelFirmwareRecord() {
+	a.firmwareMutex.Lock()
+	defer a.firmwareMutex.Unlock()
+	if a.firmwareTimer != nil {
+		a.firmwareTimer.Stop()
+		a.firmwareTimer = nil
+	}
+}
+
+// recordFirmware 将当前会话的设备与固件版本记入版本库，新设备或版本变化时发布 "firmware-changed" 事件
+func (a *App) recordFirmware() {
+	a.firmwareMutex.Lock()
+	db := a.firmware
+	a.firmwareMutex.Unlock()
+	conn := a.currentConnection()
+	m := a.banner.Metadata()
+	if db == nil || conn == nil || m.Fields[banner.FieldFirmware] == "" {
+		return
+	}
+
+	o := fwdb.Observation{
+		Serial:   m.Fields[banner.FieldSerial],
+		Name:     m.Fields[banner.FieldDevice],
+		Port:     connectionTarget(conn),
+		Firmware: m.Fields[banner.FieldFirmware],
+		Time:     time.Now(),
+	}
+	if ConnectionType(conn.Type) == TypeSerial {
+		if p := usbPortDetails(conn.Port); p != nil && p.SerialNumber != "" {
+			o.USB = p.VID + ":" + p.PID + ":" + p.SerialNumber
+		}
+	}
+	ch, err := db.Observe(o)
+	if err != nil {
+		a.log("firmware").Warn("firmware history not updated", "err", err)
+		return
+	}
+	if ch.New || ch.Previous != "" {
+		a.bus.Publish(EventFirmwareChanged, ch)
+	}
+}
+
+// firmwareDB 返回固件版本库，加载失败时返回错误
+func (a *App) firmwareDB() (*fwdb.DB, error) {
+	a.firmwareMutex.Lock()
+	defer a.firmwareMutex.Unlock()
+	if a.firmware == nil {
+		return nil, apperr.New(apperr.CodeInternal, "firmware history not loaded")
+	}
+	return a.firmware, nil
+}
+
+// ListDeviceFirmware 列出见过的设备及其当前固件版本，按最近出现从新到旧排序
+// 设备按自报序列号、USB 序列号、设备名、端口的优先级区分
+func (a *App) ListDeviceFirmware() ([]fwdb.Device, error) {
+	db, err := a.firmwareDB()
+	if err != nil {
+		return nil, err
+	}
+	return db.List(), nil
+}
+
+// GetFirmwareHistory 返回设备见过的全部固件版本及首次/最近出现时间
+func (a *App) GetFirmwareHistory(id string) (fwdb.Device, error) {
+	db, err := a.firmwareDB()
+	if err != nil {
+		return fwdb.Device{}, err
+	}
+	d, err := db.Get(id)
+	if errors.Is(err, fwdb.ErrNotFound) {
+		return d, apperr.New(apperr.CodeNotFound, id)
+	}
+	return d, err
+}
+
+// DeleteFirmwareHistory 删除设备及其版本历史
+func (a *App) DeleteFirmwareHistory(id string) apperr.Result {
+	db, err := a.firmwareDB()
+	if err != nil {
+		return apperr.FromError(err)
+	}
+	if err := db.Delete(id); err != nil {
+		if errors.Is(err, fwdb.ErrNotFound) {
+			return apperr.FromError(apperr.New(apperr.CodeNotFound, id))
+		}
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+
+	"go.bug.st/serial"
+)
+
+// drainTimeout 等待发送缓冲区清空的最长时间 (硬件流控被对端阻塞时 Drain 可能一直不返回)
+const drainTimeout = 10 * time.Second
+
+// inflight 统计进行中的写入；写入在后台 goroutine 中进行，超时或取消后仍可能在向驱动写数据
+type inflight struct {
+	mu   sync.Mutex
+	n    int
+	idle chan struct{} // n 降为 0 时关闭，有等待者时才创建
+}
+
+func (f *inflight) add() {
+	f.mu.Lock()
+	f.n++
+	f.mu.Unlock()
+}
+
+func (f *inflight) done() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.n--
+	if f.n == 0 && f.idle != nil {
+		close(f.idle)
+		f.idle = nil
+	}
+}
+
+// wait 等待当前进行中的写入全部返回，ctx 结束时返回 ctx 的错误
+func (f *inflight) wait(ctx context.Context) error {
+	f.mu.Lock()
+	if f.n == 0 {
+		f.mu.Unlock()
+		return nil
+	}
+	if f.idle == nil {
+		f.idle = make(chan struct{})
+	}
+	idle := f.idle
+	f.mu.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// serialPortForBuffers 返回当前串口；未连接或连接不是串口时返回错误
+func (a *App) serialPortForBuffers() (serial.Port, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if !a.isConnected {
+		return nil, apperr.New(apperr.CodeNotConnected, "")
+	}
+	if a.connType != TypeSerial || a.serialPort == nil {
+		return nil, apperr.New(apperr.CodeUnsupported, string(a.connType))
+	}
+	return a.serialPort, nil
+}
+
+// FlushInput 丢弃驱动接收缓冲区中尚未读取的数据，用于在请求/应答事务前清除残留的应答
+func (a *App) FlushInput() apperr.Result {
+	port, err := a.serialPortForBuffers()
+	if err != nil {
+		return apperr.FromError(err)
+	}
+	if err := port.ResetInputBuffer(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// FlushOutput 丢弃驱动发送缓冲区中尚未发出的数据
+func (a *App) FlushOutput() apperr.Result {
+	port, err := a.serialPortForBuffers()
+	if err != nil {
+		return apperr.FromError(err)
+	}
+	if err := port.ResetOutputBuffer(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// Drain 等待已写入的数据全部发出后返回，用于在切换 DTR/RTS 或 RS-485 方向前确认发送完毕
+// 先等待进行中的写入返回，再等待驱动发送缓冲区清空；最长等待 drainTimeout，可通过 Cancel 取消
+func (a *App) Drain() apperr.Result {
+	port, err := a.serialPortForBuffers()
+	if err != nil {
+		return apperr.FromError(err)
+	}
+	op := a.startOperation("drain", drainTimeout)
+	defer a.ops.Finish(op)
+	if a.writes.wait(op.Context()) != nil {
+		return apperr.FromError(contextError(op.Context()))
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- port.Drain()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return apperr.FromError(apperr.Wrap(apperr.CodeWriteFailed, err))
+		}
+		return apperr.OK()
+	case <-op.Context().Done():
+		return apperr.FromError(contextError(op.Context()))
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+
+	"go.bug.st/serial"
+)
+
+// mockPort 记录缓冲区操作与 SetMode 参数的串口，Write 在 block 非 nil 时等待其关闭
+type mockPort struct {
+	serial.Port
+	mu      sync.Mutex
+	calls   []string
+	modes   []serial.Mode
+	writing chan struct{} // Write 开始时关闭
+	block   chan struct{}
+}
+
+func (p *mockPort) record(call string) {
+	p.mu.Lock()
+	p.calls = append(p.calls, call)
+	p.mu.Unlock()
+}
+
+func (p *mockPort) Calls() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.calls...)
+}
+
+func (p *mockPort) Write(b []byte) (int, error) {
+	if p.writing != nil {
+		close(p.writing)
+	}
+	if p.block != nil {
+		<-p.block
+	}
+	p.record("write")
+	return len(b), nil
+}
+
+func (p *mockPort) SetMode(m *serial.Mode) error {
+	p.mu.Lock()
+	p.modes = append(p.modes, *m)
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *mockPort) SetRTS(bool) error        { return nil }
+func (p *mockPort) Drain() error             { p.record("drain"); return nil }
+func (p *mockPort) ResetInputBuffer() error  { p.record("reset-input"); return nil }
+func (p *mockPort) ResetOutputBuffer() error { p.record("reset-output"); return nil }
+
+func connectMock(a *App, port serial.Port) {
+	a.mutex.Lock()
+	a.isConnected, a.connType, a.serialPort = true, TypeSerial, port
+	a.mutex.Unlock()
+}
+
+func TestFlushBuffers(t *testing.T) {
+	a := NewApp()
+	if r := a.FlushInput(); r.OK || r.Code != apperr.CodeNotConnected {
+		t.Errorf("Expected NOT_CONNECTED, got %+v", r)
+	}
+	port := &mockPort{}
+	connectMock(a, port)
+	if r := a.FlushInput(); !r.OK {
+		t.Fatalf("FlushInput = %+v", r)
+	}
+	if r := a.FlushOutput(); !r.OK {
+		t.Fatalf("FlushOutput = %+v", r)
+	}
+	if r := a.Drain(); !r.OK {
+		t.Fatalf("Drain = %+v", r)
+	}
+	if calls := port.Calls(); len(calls) != 3 || calls[0] != "reset-input" || calls[1] != "reset-output" || calls[2] != "drain" {
+		t.Errorf("Unexpected port calls %v", calls)
+	}
+
+	a.mutex.Lock()
+	a.connType = TypeTcpClient
+	a.mutex.Unlock()
+	if r := a.FlushInput(); r.OK || r.Code != apperr.CodeUnsupported {
+		t.Errorf("Expected UNSUPPORTED for a non-serial connection, got %+v", r)
+	}
+}
+
+func TestDrainWaitsForWrites(t *testing.T) {
+	a := NewApp()
+	port := &mockPort{writing: make(chan struct{}), block: make(chan struct{})}
+	connectMock(a, port)
+
+	go a.writeContext(context.Background(), []byte("hello"))
+	<-port.writing
+	drained := make(chan apperr.Result)
+	go func() { drained <- a.Drain() }()
+
+	select {
+	case r := <-drained:
+		t.Fatalf("Drain returned while a write was in flight: %+v", r)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(port.block)
+	select {
+	case r := <-drained:
+		if !r.OK {
+			t.Fatalf("Drain = %+v", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Drain did not return after the write finished")
+	}
+	if calls := port.Calls(); len(calls) != 2 || calls[0] != "write" || calls[1] != "drain" {
+		t.Errorf("Drain should follow the write, got %v", calls)
+	}
+}
@@ -1,27 +1,558 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
+import {main} from '../models';
+import {apperr} from '../models';
 import {updater} from '../models';
+import {setup} from '../models';
+import {decoder} from '../models';
+import {permissions} from '../models';
+import {escpos} from '../models';
+import {console} from '../models';
+import {diagnostics} from '../models';
+import {audit} from '../models';
+import {autologin} from '../models';
+import {reconnect} from '../models';
+import {banner} from '../models';
+import {bootloop} from '../models';
+import {framestats} from '../models';
+import {profiler} from '../models';
+import {crashlog} from '../models';
+import {ratelimit} from '../models';
+import {driverhealth} from '../models';
+import {mailreport} from '../models';
+import {fwdb} from '../models';
+import {jlink} from '../models';
+import {linecap} from '../models';
+import {operation} from '../models';
+import {deeplink} from '../models';
+import {pipeline} from '../models';
+import {ports} from '../models';
+import {journal} from '../models';
+import {redact} from '../models';
+import {rules} from '../models';
+import {sanitize} from '../models';
+import {series} from '../models';
+import {sessionvars} from '../models';
+import {snippets} from '../models';
+import {soak} from '../models';
+import {retention} from '../models';
+import {timesync} from '../models';
+import {usage} from '../models';
+import {watchfolder} from '../models';
+import {wedge} from '../models';
+import {identify} from '../models';
+import {convert} from '../models';
+import {apikey} from '../models';
+import {capture} from '../models';
+import {instrument} from '../models';
+import {txsched} from '../models';
+import {scpi} from '../models';
+import {buildhook} from '../models';
+import {editorrpc} from '../models';
+import {loopback} from '../models';
+
+export function ArmAutoSendStop(arg1:main.StopConditions):Promise<apperr.Result>;
+
+export function Cancel(arg1:string):Promise<apperr.Result>;
+
+export function CancelReconnect():Promise<apperr.Result>;
+
+export function CaptureDiagnostics(arg1:string):Promise<apperr.Result>;
 
 export function CheckForUpdates():Promise<updater.UpdateInfo>;
 
-export function Close():Promise<string>;
+export function CleanupStorage():Promise<main.CleanupResult>;
+
+export function ClearJLinkLog():Promise<apperr.Result>;
+
+export function ClearRttElf():Promise<apperr.Result>;
+
+export function ClearSeries(arg1:string):Promise<void>;
+
+export function ClearSessionVars():Promise<apperr.Result>;
+
+export function Close():Promise<apperr.Result>;
+
+export function CloseGpsReference():Promise<apperr.Result>;
+
+export function ClosePowerMeter():Promise<apperr.Result>;
+
+export function CloseRttProbe(arg1:string):Promise<apperr.Result>;
+
+export function CompleteSetup(arg1:setup.Settings):Promise<apperr.Result>;
+
+export function CreateAPIKey(arg1:string,arg2:string):Promise<main.CreatedAPIKey>;
+
+export function DeleteAutoLoginPassword(arg1:string):Promise<apperr.Result>;
+
+export function DeleteCapture(arg1:string):Promise<apperr.Result>;
+
+export function DeleteCrashReport(arg1:string):Promise<apperr.Result>;
+
+export function DeleteFirmwareHistory(arg1:string):Promise<apperr.Result>;
+
+export function DeleteSecret(arg1:string):Promise<apperr.Result>;
+
+export function DeleteSessionVar(arg1:string):Promise<apperr.Result>;
+
+export function DetectProtocol():Promise<decoder.Detection>;
+
+export function DetectSetupDevices():Promise<Array<setup.Device>>;
+
+export function DiagnosePortAccess(arg1:string):Promise<permissions.Report>;
+
+export function DisableDecoder(arg1:string):Promise<apperr.Result>;
+
+export function DisarmAutoSendStop():Promise<void>;
+
+export function DiscardDeepLink():Promise<void>;
+
+export function DiscardRecoverableSession():Promise<void>;
+
+export function DmmConfigure(arg1:string):Promise<apperr.Result>;
+
+export function DmmRead():Promise<number>;
 
 export function DownloadAndInstallUpdate(arg1:string):Promise<void>;
 
+export function Drain():Promise<apperr.Result>;
+
+export function EnableDecoder(arg1:string):Promise<apperr.Result>;
+
+export function EscPosStatus():Promise<escpos.Status>;
+
+export function ExecuteCommand(arg1:string,arg2:string,arg3:number):Promise<console.Result>;
+
+export function ExportCapture(arg1:string,arg2:string,arg3:string):Promise<apperr.Result>;
+
+export function ExportCoverage(arg1:string):Promise<apperr.Result>;
+
+export function ExportPythonClient(arg1:string):Promise<string>;
+
+export function ExportUsageStats(arg1:string):Promise<apperr.Result>;
+
+export function FlushInput():Promise<apperr.Result>;
+
+export function FlushOutput():Promise<apperr.Result>;
+
+export function GetAppLog(arg1:string,arg2:string,arg3:number):Promise<Array<diagnostics.Entry>>;
+
+export function GetAuditLog(arg1:number):Promise<Array<audit.Entry>>;
+
+export function GetAutoLogin():Promise<autologin.Config>;
+
+export function GetAutoReconnect():Promise<reconnect.Policy>;
+
+export function GetBannerConfig():Promise<banner.Config>;
+
+export function GetBootLoopConfig():Promise<bootloop.Config>;
+
+export function GetBuildHook():Promise<main.BuildHookStatus>;
+
+export function GetCaptureEncryption():Promise<main.CaptureEncryptionStatus>;
+
+export function GetCaptureLineCount(arg1:string):Promise<number>;
+
+export function GetCommandLineEnding():Promise<string>;
+
+export function GetConversations():Promise<Array<framestats.Conversation>>;
+
+export function GetCoverage():Promise<profiler.Coverage>;
+
+export function GetCrashConfig():Promise<crashlog.Config>;
+
+export function GetDiffSuppressed():Promise<number>;
+
+export function GetDisplayFilter():Promise<string>;
+
+export function GetDisplayStats():Promise<ratelimit.Stats>;
+
+export function GetDriverHealth(arg1:string):Promise<driverhealth.Report>;
+
+export function GetDryRun():Promise<boolean>;
+
+export function GetEditorServer():Promise<main.EditorServerStatus>;
+
+export function GetEmailConfig():Promise<mailreport.Config>;
+
+export function GetEnabledDecoders():Promise<Array<string>>;
+
+export function GetFirmwareHistory(arg1:string):Promise<fwdb.Device>;
+
+export function GetFrameStats():Promise<Array<framestats.TypeStats>>;
+
+export function GetGapThreshold():Promise<number>;
+
+export function GetGpsClockStatus():Promise<main.GpsClockStatus>;
+
+export function GetJLinkLog():Promise<Array<jlink.LogEntry>>;
+
+export function GetJLinkLogConfig():Promise<main.JLinkLogConfig>;
+
+export function GetJLinkTarget():Promise<jlink.Target>;
+
+export function GetLanguage():Promise<string>;
+
+export function GetLineCaptureStatus():Promise<main.LineCaptureStatus>;
+
+export function GetLineEdges(arg1:number):Promise<Array<linecap.Edge>>;
+
+export function GetLineMode():Promise<main.LineModeConfig>;
+
+export function GetLogLevel():Promise<string>;
+
+export function GetMemoryWrites(arg1:string):Promise<Array<main.MemoryWrite>>;
+
+export function GetModemStatus():Promise<linecap.Levels>;
+
+export function GetOperations():Promise<Array<operation.Info>>;
+
+export function GetPendingDeepLink():Promise<deeplink.Link>;
+
+export function GetPipelineStats():Promise<Array<pipeline.StageStats>>;
+
+export function GetPortHolders(arg1:string):Promise<Array<ports.Holder>>;
+
+export function GetPortableStatus():Promise<main.PortableStatus>;
+
+export function GetPowerMeterStatus():Promise<main.PowerMeterStatus>;
+
+export function GetProbeVTref(arg1:string):Promise<number>;
+
+export function GetProfile(arg1:number):Promise<main.ProfileStatus>;
+
+export function GetRecoverableSession():Promise<journal.State>;
+
+export function GetRedaction():Promise<redact.Config>;
+
+export function GetRttBridgeStatus():Promise<main.RttBridgeStatus>;
+
+export function GetRttLayout():Promise<main.RttLayoutStatus>;
+
+export function GetRules():Promise<Array<rules.Rule>>;
+
+export function GetRxSanitize():Promise<sanitize.Options>;
+
+export function GetRxSanitizeStats():Promise<sanitize.Stats>;
+
 export function GetSerialPorts():Promise<Array<string>>;
 
+export function GetSeries(arg1:string,arg2:number):Promise<Array<series.Sample>>;
+
+export function GetSessionLink():Promise<string>;
+
+export function GetSessionMetadata():Promise<banner.Metadata>;
+
+export function GetSessionVars():Promise<Array<sessionvars.Var>>;
+
+export function GetSetupState():Promise<setup.State>;
+
+export function GetShareStatus():Promise<main.ShareInfo>;
+
+export function GetSnippetRepo():Promise<string>;
+
+export function GetSnippetTags():Promise<Array<string>>;
+
+export function GetSnippets():Promise<Array<snippets.Snippet>>;
+
+export function GetSoakReport(arg1:string):Promise<soak.Summary>;
+
+export function GetStandardBaudRates():Promise<Array<number>>;
+
+export function GetStoragePolicy():Promise<retention.Policy>;
+
+export function GetStorageUsage():Promise<retention.Usage>;
+
+export function GetTimeSyncConfig():Promise<timesync.Config>;
+
+export function GetTimeSyncPresets():Promise<Array<timesync.Preset>>;
+
+export function GetUpdateChannel():Promise<string>;
+
+export function GetUsageStats():Promise<usage.Stats>;
+
 export function GetVersion():Promise<string>;
 
-export function OpenJLink(arg1:string,arg2:number,arg3:string):Promise<string>;
+export function GetWatchFolder():Promise<watchfolder.Config>;
+
+export function GetWedgeConfig():Promise<wedge.Config>;
+
+export function GrantControl(arg1:string):Promise<apperr.Result>;
+
+export function HasSecret(arg1:string):Promise<boolean>;
+
+export function IdentifyDevice(arg1:string,arg2:boolean):Promise<identify.Fingerprint>;
+
+export function ImportCapture(arg1:string):Promise<string>;
+
+export function InsertSnippet(arg1:string):Promise<snippets.Snippet>;
+
+export function InstallUdevRule(arg1:string):Promise<apperr.Result>;
+
+export function InterpretBytes(arg1:string):Promise<Array<convert.Value>>;
+
+export function ListAPIKeys():Promise<Array<apikey.Key>>;
+
+export function ListCaptures():Promise<Array<capture.Info>>;
+
+export function ListCrashReports():Promise<Array<crashlog.Report>>;
+
+export function ListDecoders():Promise<Array<decoder.Info>>;
+
+export function ListDeviceFirmware():Promise<Array<fwdb.Device>>;
+
+export function ListDiagnosticDumps():Promise<Array<diagnostics.DumpInfo>>;
+
+export function ListInstrumentProfiles():Promise<Array<instrument.Profile>>;
 
-export function OpenSerial(arg1:string,arg2:number,arg3:number,arg4:number,arg5:string):Promise<string>;
+export function ListPlcProtocols():Promise<Array<main.PlcProtocol>>;
 
-export function OpenTcpClient(arg1:string,arg2:string):Promise<string>;
+export function ListPorts():Promise<Array<ports.Port>>;
 
-export function OpenTcpServer(arg1:string):Promise<string>;
+export function ListRttProbes():Promise<Array<main.RttProbeInfo>>;
 
-export function OpenUdp(arg1:string,arg2:string,arg3:string):Promise<string>;
+export function ListSeries():Promise<Array<series.Info>>;
+
+export function ListSoakReports():Promise<Array<soak.Summary>>;
+
+export function LoadDecoderPlugin(arg1:string):Promise<apperr.Result>;
+
+export function LoadPacketDefinitions(arg1:string):Promise<apperr.Result>;
+
+export function LoadScaleProfiles(arg1:string):Promise<apperr.Result>;
+
+export function LockCaptures():Promise<void>;
+
+export function OpenDeepLink():Promise<apperr.Result>;
+
+export function OpenGpsReference(arg1:string,arg2:number):Promise<apperr.Result>;
+
+export function OpenJLink(arg1:string,arg2:number,arg3:string):Promise<apperr.Result>;
+
+export function OpenPowerMeter(arg1:main.PowerMeterConfig):Promise<apperr.Result>;
+
+export function OpenRttProbe(arg1:string,arg2:jlink.Target,arg3:string,arg4:number,arg5:string):Promise<apperr.Result>;
+
+export function OpenSerial(arg1:string,arg2:number,arg3:number,arg4:number,arg5:string):Promise<apperr.Result>;
+
+export function OpenSerialWithOptions(arg1:main.SerialOptions):Promise<apperr.Result>;
+
+export function OpenTcpClient(arg1:string,arg2:string):Promise<apperr.Result>;
+
+export function OpenTcpServer(arg1:string):Promise<apperr.Result>;
+
+export function OpenUdp(arg1:string,arg2:string,arg3:string):Promise<apperr.Result>;
+
+export function PlcRead(arg1:main.PlcRequest):Promise<Array<number>>;
+
+export function PlcWrite(arg1:main.PlcRequest,arg2:Array<number>):Promise<apperr.Result>;
+
+export function PowerCycleProbe(arg1:string,arg2:number):Promise<apperr.Result>;
+
+export function PreviewPayload(arg1:string,arg2:boolean):Promise<main.PayloadPreview>;
+
+export function PreviewRedaction(arg1:Array<redact.Rule>,arg2:string):Promise<string>;
+
+export function PreviewSnippet(arg1:string):Promise<main.PayloadPreview>;
+
+export function PreviewTimeSync(arg1:timesync.Config):Promise<main.PayloadPreview>;
+
+export function PrintEscPos(arg1:main.EscPosJob):Promise<apperr.Result>;
+
+export function PsuMeasure(arg1:number):Promise<instrument.Reading>;
+
+export function PsuSetCurrent(arg1:number,arg2:number):Promise<apperr.Result>;
+
+export function PsuSetOutput(arg1:number,arg2:boolean):Promise<apperr.Result>;
+
+export function PsuSetVoltage(arg1:number,arg2:number):Promise<apperr.Result>;
 
 export function QuitApp():Promise<void>;
 
-export function SendData(arg1:string):Promise<string>;
+export function ReadCaptureLines(arg1:string,arg2:number,arg3:number):Promise<Array<string>>;
+
+export function ReadCrashReport(arg1:string):Promise<string>;
+
+export function ReadMemory(arg1:string,arg2:number,arg3:number):Promise<string>;
+
+export function ReconfigureSerial(arg1:main.SerialOptions):Promise<apperr.Result>;
+
+export function RegisterURLScheme():Promise<apperr.Result>;
+
+export function RenderEscPos(arg1:main.EscPosJob):Promise<string>;
+
+export function ResetDisplayStats():Promise<void>;
+
+export function ResetFrameStats():Promise<void>;
+
+export function ResetPayloadCounter():Promise<void>;
+
+export function ResetPowerMeterEnergy():Promise<apperr.Result>;
+
+export function ResetRxSanitizeStats():Promise<void>;
+
+export function ResetSetup():Promise<apperr.Result>;
+
+export function ResetUsageStats():Promise<apperr.Result>;
+
+export function ResetUsbDevice(arg1:string):Promise<apperr.Result>;
+
+export function ResumeSession():Promise<apperr.Result>;
+
+export function RevertWrites(arg1:string):Promise<apperr.Result>;
+
+export function RevokeAPIKey(arg1:string):Promise<apperr.Result>;
+
+export function RevokeControl():Promise<apperr.Result>;
+
+export function RunCommandBatch(arg1:main.CommandBatch):Promise<Array<console.Result>>;
+
+export function SaveSessionState(arg1:journal.Session):Promise<apperr.Result>;
+
+export function SaveSnippets(arg1:Array<snippets.Snippet>):Promise<apperr.Result>;
+
+export function ScheduleTransmission(arg1:Array<main.ScheduledFrame>):Promise<Array<txsched.Result>>;
+
+export function ScheduleTransmissionUntil(arg1:Array<main.ScheduledFrame>,arg2:main.StopConditions):Promise<Array<txsched.Result>>;
+
+export function ScpiCommand(arg1:string):Promise<apperr.Result>;
+
+export function ScpiErrors():Promise<Array<scpi.Error>>;
+
+export function ScpiIdentify():Promise<scpi.Identity>;
+
+export function ScpiQuery(arg1:string):Promise<string>;
+
+export function SearchCapture(arg1:string,arg2:string,arg3:number):Promise<Array<number>>;
+
+export function SearchSnippets(arg1:string,arg2:Array<string>):Promise<Array<snippets.Snippet>>;
+
+export function SelectInstrument(arg1:string):Promise<instrument.Profile>;
+
+export function SendData(arg1:string):Promise<apperr.Result>;
+
+export function SendPayload(arg1:string,arg2:boolean):Promise<apperr.Result>;
+
+export function SendSessionReport():Promise<apperr.Result>;
+
+export function SendSnippet(arg1:string):Promise<apperr.Result>;
+
+export function SetAutoDetect(arg1:boolean):Promise<apperr.Result>;
+
+export function SetAutoLogin(arg1:autologin.Config):Promise<apperr.Result>;
+
+export function SetAutoLoginPassword(arg1:string,arg2:string):Promise<apperr.Result>;
+
+export function SetAutoReconnect(arg1:reconnect.Policy):Promise<apperr.Result>;
+
+export function SetBannerConfig(arg1:banner.Config):Promise<apperr.Result>;
+
+export function SetBootLoopConfig(arg1:bootloop.Config):Promise<apperr.Result>;
+
+export function SetBuildHook(arg1:buildhook.Config):Promise<apperr.Result>;
+
+export function SetCaptureBookmark(arg1:string,arg2:boolean):Promise<apperr.Result>;
+
+export function SetCaptureEncryption(arg1:string,arg2:string):Promise<apperr.Result>;
+
+export function SetCommandLineEnding(arg1:string):Promise<void>;
+
+export function SetCrashConfig(arg1:crashlog.Config):Promise<apperr.Result>;
+
+export function SetDiffMode(arg1:boolean):Promise<apperr.Result>;
+
+export function SetDisplayFilter(arg1:string):Promise<apperr.Result>;
+
+export function SetDisplayRateLimit(arg1:number,arg2:number):Promise<apperr.Result>;
+
+export function SetDryRun(arg1:boolean):Promise<void>;
+
+export function SetEditorServer(arg1:editorrpc.Config):Promise<main.EditorServerStatus>;
+
+export function SetEmailConfig(arg1:mailreport.Config):Promise<apperr.Result>;
+
+export function SetGapThreshold(arg1:number):Promise<apperr.Result>;
+
+export function SetJLinkLogConfig(arg1:main.JLinkLogConfig):Promise<apperr.Result>;
+
+export function SetJLinkTarget(arg1:jlink.Target):Promise<apperr.Result>;
+
+export function SetLanguage(arg1:string):Promise<apperr.Result>;
+
+export function SetLineMode(arg1:main.LineModeConfig):Promise<apperr.Result>;
+
+export function SetLogLevel(arg1:string):Promise<apperr.Result>;
+
+export function SetPortableMode(arg1:boolean):Promise<apperr.Result>;
+
+export function SetProbePower(arg1:string,arg2:boolean,arg3:boolean):Promise<apperr.Result>;
+
+export function SetRedaction(arg1:redact.Config):Promise<apperr.Result>;
+
+export function SetRttElf(arg1:string):Promise<jlink.ElfConfig>;
+
+export function SetRules(arg1:Array<rules.Rule>):Promise<apperr.Result>;
+
+export function SetRxSanitize(arg1:sanitize.Options):Promise<apperr.Result>;
+
+export function SetScpiAutoIdentify(arg1:boolean):Promise<void>;
+
+export function SetScpiTerminators(arg1:string,arg2:string):Promise<void>;
+
+export function SetSecret(arg1:string,arg2:string):Promise<apperr.Result>;
+
+export function SetSessionVar(arg1:string,arg2:string):Promise<apperr.Result>;
+
+export function SetStoragePolicy(arg1:retention.Policy):Promise<apperr.Result>;
+
+export function SetTimeSyncConfig(arg1:timesync.Config):Promise<apperr.Result>;
+
+export function SetUpdateChannel(arg1:string):Promise<apperr.Result>;
+
+export function SetUsageStatsEnabled(arg1:boolean):Promise<apperr.Result>;
+
+export function SetWatchFolder(arg1:watchfolder.Config):Promise<apperr.Result>;
+
+export function SetWedgeConfig(arg1:wedge.Config):Promise<apperr.Result>;
+
+export function StartInstrumentLogger(arg1:number):Promise<apperr.Result>;
+
+export function StartLineCapture(arg1:number):Promise<apperr.Result>;
+
+export function StartNotebookSession(arg1:number,arg2:boolean):Promise<main.NotebookSession>;
+
+export function StartProfiler(arg1:main.ProfilerConfig):Promise<apperr.Result>;
+
+export function StartRttBridge(arg1:string,arg2:string,arg3:number):Promise<apperr.Result>;
+
+export function StartScpiLogger(arg1:scpi.LoggerConfig):Promise<apperr.Result>;
+
+export function StartSharing(arg1:number):Promise<main.ShareInfo>;
+
+export function StartSoakTest(arg1:main.SoakConfig):Promise<apperr.Result>;
+
+export function StopLineCapture():Promise<apperr.Result>;
+
+export function StopProfiler():Promise<apperr.Result>;
+
+export function StopRttBridge():Promise<apperr.Result>;
+
+export function StopScpiLogger():Promise<apperr.Result>;
+
+export function StopSharing():Promise<apperr.Result>;
+
+export function StopSoakTest():Promise<apperr.Result>;
+
+export function SyncSnippets(arg1:string):Promise<apperr.Result>;
+
+export function SyncTime():Promise<apperr.Result>;
+
+export function TestLoopback(arg1:loopback.Config):Promise<loopback.Report>;
+
+export function TestSetupPort(arg1:setup.Settings):Promise<setup.Report>;
+
+export function UnlockCaptures(arg1:string):Promise<apperr.Result>;
+
+export function UpdateNow():Promise<apperr.Result>;
+
+export function WriteMemory(arg1:string,arg2:number,arg3:string):Promise<apperr.Result>;
@@ -2,34 +2,578 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function ArmAutoSendStop(arg1) {
+  return window['go']['main']['App']['ArmAutoSendStop'](arg1);
+}
+
+export function Cancel(arg1) {
+  return window['go']['main']['App']['Cancel'](arg1);
+}
+
+export function CancelReconnect() {
+  return window['go']['main']['App']['CancelReconnect']();
+}
+
+export function CaptureDiagnostics(arg1) {
+  return window['go']['main']['App']['CaptureDiagnostics'](arg1);
+}
+
 export function CheckForUpdates() {
   return window['go']['main']['App']['CheckForUpdates']();
 }
 
+export function CleanupStorage() {
+  return window['go']['main']['App']['CleanupStorage']();
+}
+
+export function ClearJLinkLog() {
+  return window['go']['main']['App']['ClearJLinkLog']();
+}
+
+export function ClearRttElf() {
+  return window['go']['main']['App']['ClearRttElf']();
+}
+
+export function ClearSeries(arg1) {
+  return window['go']['main']['App']['ClearSeries'](arg1);
+}
+
+export function ClearSessionVars() {
+  return window['go']['main']['App']['ClearSessionVars']();
+}
+
 export function Close() {
   return window['go']['main']['App']['Close']();
 }
 
+export function CloseGpsReference() {
+  return window['go']['main']['App']['CloseGpsReference']();
+}
+
+export function ClosePowerMeter() {
+  return window['go']['main']['App']['ClosePowerMeter']();
+}
+
+export function CloseRttProbe(arg1) {
+  return window['go']['main']['App']['CloseRttProbe'](arg1);
+}
+
+export function CompleteSetup(arg1) {
+  return window['go']['main']['App']['CompleteSetup'](arg1);
+}
+
+export function CreateAPIKey(arg1, arg2) {
+  return window['go']['main']['App']['CreateAPIKey'](arg1, arg2);
+}
+
+export function DeleteAutoLoginPassword(arg1) {
+  return window['go']['main']['App']['DeleteAutoLoginPassword'](arg1);
+}
+
+export function DeleteCapture(arg1) {
+  return window['go']['main']['App']['DeleteCapture'](arg1);
+}
+
+export function DeleteCrashReport(arg1) {
+  return window['go']['main']['App']['DeleteCrashReport'](arg1);
+}
+
+export function DeleteFirmwareHistory(arg1) {
+  return window['go']['main']['App']['DeleteFirmwareHistory'](arg1);
+}
+
+export function DeleteSecret(arg1) {
+  return window['go']['main']['App']['DeleteSecret'](arg1);
+}
+
+export function DeleteSessionVar(arg1) {
+  return window['go']['main']['App']['DeleteSessionVar'](arg1);
+}
+
+export function DetectProtocol() {
+  return window['go']['main']['App']['DetectProtocol']();
+}
+
+export function DetectSetupDevices() {
+  return window['go']['main']['App']['DetectSetupDevices']();
+}
+
+export function DiagnosePortAccess(arg1) {
+  return window['go']['main']['App']['DiagnosePortAccess'](arg1);
+}
+
+export function DisableDecoder(arg1) {
+  return window['go']['main']['App']['DisableDecoder'](arg1);
+}
+
+export function DisarmAutoSendStop() {
+  return window['go']['main']['App']['DisarmAutoSendStop']();
+}
+
+export function DiscardDeepLink() {
+  return window['go']['main']['App']['DiscardDeepLink']();
+}
+
+export function DiscardRecoverableSession() {
+  return window['go']['main']['App']['DiscardRecoverableSession']();
+}
+
+export function DmmConfigure(arg1) {
+  return window['go']['main']['App']['DmmConfigure'](arg1);
+}
+
+export function DmmRead() {
+  return window['go']['main']['App']['DmmRead']();
+}
+
 export function DownloadAndInstallUpdate(arg1) {
   return window['go']['main']['App']['DownloadAndInstallUpdate'](arg1);
 }
 
+export function Drain() {
+  return window['go']['main']['App']['Drain']();
+}
+
+export function EnableDecoder(arg1) {
+  return window['go']['main']['App']['EnableDecoder'](arg1);
+}
+
+export function EscPosStatus() {
+  return window['go']['main']['App']['EscPosStatus']();
+}
+
+export function ExecuteCommand(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExecuteCommand'](arg1, arg2, arg3);
+}
+
+export function ExportCapture(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportCapture'](arg1, arg2, arg3);
+}
+
+export function ExportCoverage(arg1) {
+  return window['go']['main']['App']['ExportCoverage'](arg1);
+}
+
+export function ExportPythonClient(arg1) {
+  return window['go']['main']['App']['ExportPythonClient'](arg1);
+}
+
+export function ExportUsageStats(arg1) {
+  return window['go']['main']['App']['ExportUsageStats'](arg1);
+}
+
+export function FlushInput() {
+  return window['go']['main']['App']['FlushInput']();
+}
+
+export function FlushOutput() {
+  return window['go']['main']['App']['FlushOutput']();
+}
+
+export function GetAppLog(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetAppLog'](arg1, arg2, arg3);
+}
+
+export function GetAuditLog(arg1) {
+  return window['go']['main']['App']['GetAuditLog'](arg1);
+}
+
+export function GetAutoLogin() {
+  return window['go']['main']['App']['GetAutoLogin']();
+}
+
+export function GetAutoReconnect() {
+  return window['go']['main']['App']['GetAutoReconnect']();
+}
+
+export function GetBannerConfig() {
+  return window['go']['main']['App']['GetBannerConfig']();
+}
+
+export function GetBootLoopConfig() {
+  return window['go']['main']['App']['GetBootLoopConfig']();
+}
+
+export function GetBuildHook() {
+  return window['go']['main']['App']['GetBuildHook']();
+}
+
+export function GetCaptureEncryption() {
+  return window['go']['main']['App']['GetCaptureEncryption']();
+}
+
+export function GetCaptureLineCount(arg1) {
+  return window['go']['main']['App']['GetCaptureLineCount'](arg1);
+}
+
+export function GetCommandLineEnding() {
+  return window['go']['main']['App']['GetCommandLineEnding']();
+}
+
+export function GetConversations() {
+  return window['go']['main']['App']['GetConversations']();
+}
+
+export function GetCoverage() {
+  return window['go']['main']['App']['GetCoverage']();
+}
+
+export function GetCrashConfig() {
+  return window['go']['main']['App']['GetCrashConfig']();
+}
+
+export function GetDiffSuppressed() {
+  return window['go']['main']['App']['GetDiffSuppressed']();
+}
+
+export function GetDisplayFilter() {
+  return window['go']['main']['App']['GetDisplayFilter']();
+}
+
+export function GetDisplayStats() {
+  return window['go']['main']['App']['GetDisplayStats']();
+}
+
+export function GetDriverHealth(arg1) {
+  return window['go']['main']['App']['GetDriverHealth'](arg1);
+}
+
+export function GetDryRun() {
+  return window['go']['main']['App']['GetDryRun']();
+}
+
+export function GetEditorServer() {
+  return window['go']['main']['App']['GetEditorServer']();
+}
+
+export function GetEmailConfig() {
+  return window['go']['main']['App']['GetEmailConfig']();
+}
+
+export function GetEnabledDecoders() {
+  return window['go']['main']['App']['GetEnabledDecoders']();
+}
+
+export function GetFirmwareHistory(arg1) {
+  return window['go']['main']['App']['GetFirmwareHistory'](arg1);
+}
+
+export function GetFrameStats() {
+  return window['go']['main']['App']['GetFrameStats']();
+}
+
+export function GetGapThreshold() {
+  return window['go']['main']['App']['GetGapThreshold']();
+}
+
+export function GetGpsClockStatus() {
+  return window['go']['main']['App']['GetGpsClockStatus']();
+}
+
+export function GetJLinkLog() {
+  return window['go']['main']['App']['GetJLinkLog']();
+}
+
+export function GetJLinkLogConfig() {
+  return window['go']['main']['App']['GetJLinkLogConfig']();
+}
+
+export function GetJLinkTarget() {
+  return window['go']['main']['App']['GetJLinkTarget']();
+}
+
+export function GetLanguage() {
+  return window['go']['main']['App']['GetLanguage']();
+}
+
+export function GetLineCaptureStatus() {
+  return window['go']['main']['App']['GetLineCaptureStatus']();
+}
+
+export function GetLineEdges(arg1) {
+  return window['go']['main']['App']['GetLineEdges'](arg1);
+}
+
+export function GetLineMode() {
+  return window['go']['main']['App']['GetLineMode']();
+}
+
+export function GetLogLevel() {
+  return window['go']['main']['App']['GetLogLevel']();
+}
+
+export function GetMemoryWrites(arg1) {
+  return window['go']['main']['App']['GetMemoryWrites'](arg1);
+}
+
+export function GetModemStatus() {
+  return window['go']['main']['App']['GetModemStatus']();
+}
+
+export function GetOperations() {
+  return window['go']['main']['App']['GetOperations']();
+}
+
+export function GetPendingDeepLink() {
+  return window['go']['main']['App']['GetPendingDeepLink']();
+}
+
+export function GetPipelineStats() {
+  return window['go']['main']['App']['GetPipelineStats']();
+}
+
+export function GetPortHolders(arg1) {
+  return window['go']['main']['App']['GetPortHolders'](arg1);
+}
+
+export function GetPortableStatus() {
+  return window['go']['main']['App']['GetPortableStatus']();
+}
+
+export function GetPowerMeterStatus() {
+  return window['go']['main']['App']['GetPowerMeterStatus']();
+}
+
+export function GetProbeVTref(arg1) {
+  return window['go']['main']['App']['GetProbeVTref'](arg1);
+}
+
+export function GetProfile(arg1) {
+  return window['go']['main']['App']['GetProfile'](arg1);
+}
+
+export function GetRecoverableSession() {
+  return window['go']['main']['App']['GetRecoverableSession']();
+}
+
+export function GetRedaction() {
+  return window['go']['main']['App']['GetRedaction']();
+}
+
+export function GetRttBridgeStatus() {
+  return window['go']['main']['App']['GetRttBridgeStatus']();
+}
+
+export function GetRttLayout() {
+  return window['go']['main']['App']['GetRttLayout']();
+}
+
+export function GetRules() {
+  return window['go']['main']['App']['GetRules']();
+}
+
+export function GetRxSanitize() {
+  return window['go']['main']['App']['GetRxSanitize']();
+}
+
+export function GetRxSanitizeStats() {
+  return window['go']['main']['App']['GetRxSanitizeStats']();
+}
+
 export function GetSerialPorts() {
   return window['go']['main']['App']['GetSerialPorts']();
 }
 
+export function GetSeries(arg1, arg2) {
+  return window['go']['main']['App']['GetSeries'](arg1, arg2);
+}
+
+export function GetSessionLink() {
+  return window['go']['main']['App']['GetSessionLink']();
+}
+
+export function GetSessionMetadata() {
+  return window['go']['main']['App']['GetSessionMetadata']();
+}
+
+export function GetSessionVars() {
+  return window['go']['main']['App']['GetSessionVars']();
+}
+
+export function GetSetupState() {
+  return window['go']['main']['App']['GetSetupState']();
+}
+
+export function GetShareStatus() {
+  return window['go']['main']['App']['GetShareStatus']();
+}
+
+export function GetSnippetRepo() {
+  return window['go']['main']['App']['GetSnippetRepo']();
+}
+
+export function GetSnippetTags() {
+  return window['go']['main']['App']['GetSnippetTags']();
+}
+
+export function GetSnippets() {
+  return window['go']['main']['App']['GetSnippets']();
+}
+
+export function GetSoakReport(arg1) {
+  return window['go']['main']['App']['GetSoakReport'](arg1);
+}
+
+export function GetStandardBaudRates() {
+  return window['go']['main']['App']['GetStandardBaudRates']();
+}
+
+export function GetStoragePolicy() {
+  return window['go']['main']['App']['GetStoragePolicy']();
+}
+
+export function GetStorageUsage() {
+  return window['go']['main']['App']['GetStorageUsage']();
+}
+
+export function GetTimeSyncConfig() {
+  return window['go']['main']['App']['GetTimeSyncConfig']();
+}
+
+export function GetTimeSyncPresets() {
+  return window['go']['main']['App']['GetTimeSyncPresets']();
+}
+
+export function GetUpdateChannel() {
+  return window['go']['main']['App']['GetUpdateChannel']();
+}
+
+export function GetUsageStats() {
+  return window['go']['main']['App']['GetUsageStats']();
+}
+
 export function GetVersion() {
   return window['go']['main']['App']['GetVersion']();
 }
 
+export function GetWatchFolder() {
+  return window['go']['main']['App']['GetWatchFolder']();
+}
+
+export function GetWedgeConfig() {
+  return window['go']['main']['App']['GetWedgeConfig']();
+}
+
+export function GrantControl(arg1) {
+  return window['go']['main']['App']['GrantControl'](arg1);
+}
+
+export function HasSecret(arg1) {
+  return window['go']['main']['App']['HasSecret'](arg1);
+}
+
+export function IdentifyDevice(arg1, arg2) {
+  return window['go']['main']['App']['IdentifyDevice'](arg1, arg2);
+}
+
+export function ImportCapture(arg1) {
+  return window['go']['main']['App']['ImportCapture'](arg1);
+}
+
+export function InsertSnippet(arg1) {
+  return window['go']['main']['App']['InsertSnippet'](arg1);
+}
+
+export function InstallUdevRule(arg1) {
+  return window['go']['main']['App']['InstallUdevRule'](arg1);
+}
+
+export function InterpretBytes(arg1) {
+  return window['go']['main']['App']['InterpretBytes'](arg1);
+}
+
+export function ListAPIKeys() {
+  return window['go']['main']['App']['ListAPIKeys']();
+}
+
+export function ListCaptures() {
+  return window['go']['main']['App']['ListCaptures']();
+}
+
+export function ListCrashReports() {
+  return window['go']['main']['App']['ListCrashReports']();
+}
+
+export function ListDecoders() {
+  return window['go']['main']['App']['ListDecoders']();
+}
+
+export function ListDeviceFirmware() {
+  return window['go']['main']['App']['ListDeviceFirmware']();
+}
+
+export function ListDiagnosticDumps() {
+  return window['go']['main']['App']['ListDiagnosticDumps']();
+}
+
+export function ListInstrumentProfiles() {
+  return window['go']['main']['App']['ListInstrumentProfiles']();
+}
+
+export function ListPlcProtocols() {
+  return window['go']['main']['App']['ListPlcProtocols']();
+}
+
+export function ListPorts() {
+  return window['go']['main']['App']['ListPorts']();
+}
+
+export function ListRttProbes() {
+  return window['go']['main']['App']['ListRttProbes']();
+}
+
+export function ListSeries() {
+  return window['go']['main']['App']['ListSeries']();
+}
+
+export function ListSoakReports() {
+  return window['go']['main']['App']['ListSoakReports']();
+}
+
+export function LoadDecoderPlugin(arg1) {
+  return window['go']['main']['App']['LoadDecoderPlugin'](arg1);
+}
+
+export function LoadPacketDefinitions(arg1) {
+  return window['go']['main']['App']['LoadPacketDefinitions'](arg1);
+}
+
+export function LoadScaleProfiles(arg1) {
+  return window['go']['main']['App']['LoadScaleProfiles'](arg1);
+}
+
+export function LockCaptures() {
+  return window['go']['main']['App']['LockCaptures']();
+}
+
+export function OpenDeepLink() {
+  return window['go']['main']['App']['OpenDeepLink']();
+}
+
+export function OpenGpsReference(arg1, arg2) {
+  return window['go']['main']['App']['OpenGpsReference'](arg1, arg2);
+}
+
 export function OpenJLink(arg1, arg2, arg3) {
   return window['go']['main']['App']['OpenJLink'](arg1, arg2, arg3);
 }
 
+export function OpenPowerMeter(arg1) {
+  return window['go']['main']['App']['OpenPowerMeter'](arg1);
+}
+
+export function OpenRttProbe(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['OpenRttProbe'](arg1, arg2, arg3, arg4, arg5);
+}
+
 export function OpenSerial(arg1, arg2, arg3, arg4, arg5) {
   return window['go']['main']['App']['OpenSerial'](arg1, arg2, arg3, arg4, arg5);
 }
 
+export function OpenSerialWithOptions(arg1) {
+  return window['go']['main']['App']['OpenSerialWithOptions'](arg1);
+}
+
 export function OpenTcpClient(arg1, arg2) {
   return window['go']['main']['App']['OpenTcpClient'](arg1, arg2);
 }
@@ -42,10 +586,430 @@ export function OpenUdp(arg1, arg2, arg3) {
   return window['go']['main']['App']['OpenUdp'](arg1, arg2, arg3);
 }
 
+export function PlcRead(arg1) {
+  return window['go']['main']['App']['PlcRead'](arg1);
+}
+
+export function PlcWrite(arg1, arg2) {
+  return window['go']['main']['App']['PlcWrite'](arg1, arg2);
+}
+
+export function PowerCycleProbe(arg1, arg2) {
+  return window['go']['main']['App']['PowerCycleProbe'](arg1, arg2);
+}
+
+export function PreviewPayload(arg1, arg2) {
+  return window['go']['main']['App']['PreviewPayload'](arg1, arg2);
+}
+
+export function PreviewRedaction(arg1, arg2) {
+  return window['go']['main']['App']['PreviewRedaction'](arg1, arg2);
+}
+
+export function PreviewSnippet(arg1) {
+  return window['go']['main']['App']['PreviewSnippet'](arg1);
+}
+
+export function PreviewTimeSync(arg1) {
+  return window['go']['main']['App']['PreviewTimeSync'](arg1);
+}
+
+export function PrintEscPos(arg1) {
+  return window['go']['main']['App']['PrintEscPos'](arg1);
+}
+
+export function PsuMeasure(arg1) {
+  return window['go']['main']['App']['PsuMeasure'](arg1);
+}
+
+export function PsuSetCurrent(arg1, arg2) {
+  return window['go']['main']['App']['PsuSetCurrent'](arg1, arg2);
+}
+
+export function PsuSetOutput(arg1, arg2) {
+  return window['go']['main']['App']['PsuSetOutput'](arg1, arg2);
+}
+
+export function PsuSetVoltage(arg1, arg2) {
+  return window['go']['main']['App']['PsuSetVoltage'](arg1, arg2);
+}
+
 export function QuitApp() {
   return window['go']['main']['App']['QuitApp']();
 }
 
+export function ReadCaptureLines(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ReadCaptureLines'](arg1, arg2, arg3);
+}
+
+export function ReadCrashReport(arg1) {
+  return window['go']['main']['App']['ReadCrashReport'](arg1);
+}
+
+export function ReadMemory(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ReadMemory'](arg1, arg2, arg3);
+}
+
+export function ReconfigureSerial(arg1) {
+  return window['go']['main']['App']['ReconfigureSerial'](arg1);
+}
+
+export function RegisterURLScheme() {
+  return window['go']['main']['App']['RegisterURLScheme']();
+}
+
+export function RenderEscPos(arg1) {
+  return window['go']['main']['App']['RenderEscPos'](arg1);
+}
+
+export function ResetDisplayStats() {
+  return window['go']['main']['App']['ResetDisplayStats']();
+}
+
+export function ResetFrameStats() {
+  return window['go']['main']['App']['ResetFrameStats']();
+}
+
+export function ResetPayloadCounter() {
+  return window['go']['main']['App']['ResetPayloadCounter']();
+}
+
+export function ResetPowerMeterEnergy() {
+  return window['go']['main']['App']['ResetPowerMeterEnergy']();
+}
+
+export function ResetRxSanitizeStats() {
+  return window['go']['main']['App']['ResetRxSanitizeStats']();
+}
+
+export function ResetSetup() {
+  return window['go']['main']['App']['ResetSetup']();
+}
+
+export function ResetUsageStats() {
+  return window['go']['main']['App']['ResetUsageStats']();
+}
+
+export function ResetUsbDevice(arg1) {
+  return window['go']['main']['App']['ResetUsbDevice'](arg1);
+}
+
+export function ResumeSession() {
+  return window['go']['main']['App']['ResumeSession']();
+}
+
+export function RevertWrites(arg1) {
+  return window['go']['main']['App']['RevertWrites'](arg1);
+}
+
+export function RevokeAPIKey(arg1) {
+  return window['go']['main']['App']['RevokeAPIKey'](arg1);
+}
+
+export function RevokeControl() {
+  return window['go']['main']['App']['RevokeControl']();
+}
+
+export function RunCommandBatch(arg1) {
+  return window['go']['main']['App']['RunCommandBatch'](arg1);
+}
+
+export function SaveSessionState(arg1) {
+  return window['go']['main']['App']['SaveSessionState'](arg1);
+}
+
+export function SaveSnippets(arg1) {
+  return window['go']['main']['App']['SaveSnippets'](arg1);
+}
+
+export function ScheduleTransmission(arg1) {
+  return window['go']['main']['App']['ScheduleTransmission'](arg1);
+}
+
+export function ScheduleTransmissionUntil(arg1, arg2) {
+  return window['go']['main']['App']['ScheduleTransmissionUntil'](arg1, arg2);
+}
+
+export function ScpiCommand(arg1) {
+  return window['go']['main']['App']['ScpiCommand'](arg1);
+}
+
+export function ScpiErrors() {
+  return window['go']['main']['App']['ScpiErrors']();
+}
+
+export function ScpiIdentify() {
+  return window['go']['main']['App']['ScpiIdentify']();
+}
+
+export function ScpiQuery(arg1) {
+  return window['go']['main']['App']['ScpiQuery'](arg1);
+}
+
+export function SearchCapture(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SearchCapture'](arg1, arg2, arg3);
+}
+
+export function SearchSnippets(arg1, arg2) {
+  return window['go']['main']['App']['SearchSnippets'](arg1, arg2);
+}
+
+export function SelectInstrument(arg1) {
+  return window['go']['main']['App']['SelectInstrument'](arg1);
+}
+
 export function SendData(arg1) {
   return window['go']['main']['App']['SendData'](arg1);
 }
+
+export function SendPayload(arg1, arg2) {
+  return window['go']['main']['App']['SendPayload'](arg1, arg2);
+}
+
+export function SendSessionReport() {
+  return window['go']['main']['App']['SendSessionReport']();
+}
+
+export function SendSnippet(arg1) {
+  return window['go']['main']['App']['SendSnippet'](arg1);
+}
+
+export function SetAutoDetect(arg1) {
+  return window['go']['main']['App']['SetAutoDetect'](arg1);
+}
+
+export function SetAutoLogin(arg1) {
+  return window['go']['main']['App']['SetAutoLogin'](arg1);
+}
+
+export function SetAutoLoginPassword(arg1, arg2) {
+  return window['go']['main']['App']['SetAutoLoginPassword'](arg1, arg2);
+}
+
+export function SetAutoReconnect(arg1) {
+  return window['go']['main']['App']['SetAutoReconnect'](arg1);
+}
+
+export function SetBannerConfig(arg1) {
+  return window['go']['main']['App']['SetBannerConfig'](arg1);
+}
+
+export function SetBootLoopConfig(arg1) {
+  return window['go']['main']['App']['SetBootLoopConfig'](arg1);
+}
+
+export function SetBuildHook(arg1) {
+  return window['go']['main']['App']['SetBuildHook'](arg1);
+}
+
+export function SetCaptureBookmark(arg1, arg2) {
+  return window['go']['main']['App']['SetCaptureBookmark'](arg1, arg2);
+}
+
+export function SetCaptureEncryption(arg1, arg2) {
+  return window['go']['main']['App']['SetCaptureEncryption'](arg1, arg2);
+}
+
+export function SetCommandLineEnding(arg1) {
+  return window['go']['main']['App']['SetCommandLineEnding'](arg1);
+}
+
+export function SetCrashConfig(arg1) {
+  return window['go']['main']['App']['SetCrashConfig'](arg1);
+}
+
+export function SetDiffMode(arg1) {
+  return window['go']['main']['App']['SetDiffMode'](arg1);
+}
+
+export function SetDisplayFilter(arg1) {
+  return window['go']['main']['App']['SetDisplayFilter'](arg1);
+}
+
+export function SetDisplayRateLimit(arg1, arg2) {
+  return window['go']['main']['App']['SetDisplayRateLimit'](arg1, arg2);
+}
+
+export function SetDryRun(arg1) {
+  return window['go']['main']['App']['SetDryRun'](arg1);
+}
+
+export function SetEditorServer(arg1) {
+  return window['go']['main']['App']['SetEditorServer'](arg1);
+}
+
+export function SetEmailConfig(arg1) {
+  return window['go']['main']['App']['SetEmailConfig'](arg1);
+}
+
+export function SetGapThreshold(arg1) {
+  return window['go']['main']['App']['SetGapThreshold'](arg1);
+}
+
+export function SetJLinkLogConfig(arg1) {
+  return window['go']['main']['App']['SetJLinkLogConfig'](arg1);
+}
+
+export function SetJLinkTarget(arg1) {
+  return window['go']['main']['App']['SetJLinkTarget'](arg1);
+}
+
+export function SetLanguage(arg1) {
+  return window['go']['main']['App']['SetLanguage'](arg1);
+}
+
+export function SetLineMode(arg1) {
+  return window['go']['main']['App']['SetLineMode'](arg1);
+}
+
+export function SetLogLevel(arg1) {
+  return window['go']['main']['App']['SetLogLevel'](arg1);
+}
+
+export function SetPortableMode(arg1) {
+  return window['go']['main']['App']['SetPortableMode'](arg1);
+}
+
+export function SetProbePower(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetProbePower'](arg1, arg2, arg3);
+}
+
+export function SetRedaction(arg1) {
+  return window['go']['main']['App']['SetRedaction'](arg1);
+}
+
+export function SetRttElf(arg1) {
+  return window['go']['main']['App']['SetRttElf'](arg1);
+}
+
+export function SetRules(arg1) {
+  return window['go']['main']['App']['SetRules'](arg1);
+}
+
+export function SetRxSanitize(arg1) {
+  return window['go']['main']['App']['SetRxSanitize'](arg1);
+}
+
+export function SetScpiAutoIdentify(arg1) {
+  return window['go']['main']['App']['SetScpiAutoIdentify'](arg1);
+}
+
+export function SetScpiTerminators(arg1, arg2) {
+  return window['go']['main']['App']['SetScpiTerminators'](arg1, arg2);
+}
+
+export function SetSecret(arg1, arg2) {
+  return window['go']['main']['App']['SetSecret'](arg1, arg2);
+}
+
+export function SetSessionVar(arg1, arg2) {
+  return window['go']['main']['App']['SetSessionVar'](arg1, arg2);
+}
+
+export function SetStoragePolicy(arg1) {
+  return window['go']['main']['App']['SetStoragePolicy'](arg1);
+}
+
+export function SetTimeSyncConfig(arg1) {
+  return window['go']['main']['App']['SetTimeSyncConfig'](arg1);
+}
+
+export function SetUpdateChannel(arg1) {
+  return window['go']['main']['App']['SetUpdateChannel'](arg1);
+}
+
+export function SetUsageStatsEnabled(arg1) {
+  return window['go']['main']['App']['SetUsageStatsEnabled'](arg1);
+}
+
+export function SetWatchFolder(arg1) {
+  return window['go']['main']['App']['SetWatchFolder'](arg1);
+}
+
+export function SetWedgeConfig(arg1) {
+  return window['go']['main']['App']['SetWedgeConfig'](arg1);
+}
+
+export function StartInstrumentLogger(arg1) {
+  return window['go']['main']['App']['StartInstrumentLogger'](arg1);
+}
+
+export function StartLineCapture(arg1) {
+  return window['go']['main']['App']['StartLineCapture'](arg1);
+}
+
+export function StartNotebookSession(arg1, arg2) {
+  return window['go']['main']['App']['StartNotebookSession'](arg1, arg2);
+}
+
+export function StartProfiler(arg1) {
+  return window['go']['main']['App']['StartProfiler'](arg1);
+}
+
+export function StartRttBridge(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StartRttBridge'](arg1, arg2, arg3);
+}
+
+export function StartScpiLogger(arg1) {
+  return window['go']['main']['App']['StartScpiLogger'](arg1);
+}
+
+export function StartSharing(arg1) {
+  return window['go']['main']['App']['StartSharing'](arg1);
+}
+
+export function StartSoakTest(arg1) {
+  return window['go']['main']['App']['StartSoakTest'](arg1);
+}
+
+export function StopLineCapture() {
+  return window['go']['main']['App']['StopLineCapture']();
+}
+
+export function StopProfiler() {
+  return window['go']['main']['App']['StopProfiler']();
+}
+
+export function StopRttBridge() {
+  return window['go']['main']['App']['StopRttBridge']();
+}
+
+export function StopScpiLogger() {
+  return window['go']['main']['App']['StopScpiLogger']();
+}
+
+export function StopSharing() {
+  return window['go']['main']['App']['StopSharing']();
+}
+
+export function StopSoakTest() {
+  return window['go']['main']['App']['StopSoakTest']();
+}
+
+export function SyncSnippets(arg1) {
+  return window['go']['main']['App']['SyncSnippets'](arg1);
+}
+
+export function SyncTime() {
+  return window['go']['main']['App']['SyncTime']();
+}
+
+export function TestLoopback(arg1) {
+  return window['go']['main']['App']['TestLoopback'](arg1);
+}
+
+export function TestSetupPort(arg1) {
+  return window['go']['main']['App']['TestSetupPort'](arg1);
+}
+
+export function UnlockCaptures(arg1) {
+  return window['go']['main']['App']['UnlockCaptures'](arg1);
+}
+
+export function UpdateNow() {
+  return window['go']['main']['App']['UpdateNow']();
+}
+
+export function WriteMemory(arg1, arg2, arg3) {
+  return window['go']['main']['App']['WriteMemory'](arg1, arg2, arg3);
+}
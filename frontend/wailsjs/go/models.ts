@@ -1,3 +1,3636 @@
+export namespace apikey {
+	
+	export class Key {
+	    id: string;
+	    name: string;
+	    scope: string;
+	    hint: string;
+	    hash: string;
+	    // Go type: time
+	    created: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Key(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.scope = source["scope"];
+	        this.hint = source["hint"];
+	        this.hash = source["hash"];
+	        this.created = this.convertValues(source["created"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace apperr {
+	
+	export class Result {
+	    ok: boolean;
+	    code: string;
+	    message: string;
+	    details?: string;
+	    recoverable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.ok = source["ok"];
+	        this.code = source["code"];
+	        this.message = source["message"];
+	        this.details = source["details"];
+	        this.recoverable = source["recoverable"];
+	    }
+	}
+
+}
+
+export namespace audit {
+	
+	export class Entry {
+	    // Go type: time
+	    time: any;
+	    who: string;
+	    remote: string;
+	    keyId?: string;
+	    action: string;
+	    data?: number[];
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.who = source["who"];
+	        this.remote = source["remote"];
+	        this.keyId = source["keyId"];
+	        this.action = source["action"];
+	        this.data = source["data"];
+	        this.error = source["error"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace autologin {
+	
+	export class Config {
+	    enabled: boolean;
+	    username: string;
+	    loginPattern: string;
+	    passwordPattern: string;
+	    lineEnding: string;
+	    maxAttempts: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.username = source["username"];
+	        this.loginPattern = source["loginPattern"];
+	        this.passwordPattern = source["passwordPattern"];
+	        this.lineEnding = source["lineEnding"];
+	        this.maxAttempts = source["maxAttempts"];
+	    }
+	}
+
+}
+
+export namespace banner {
+	
+	export class Rule {
+	    field: string;
+	    pattern: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Rule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.field = source["field"];
+	        this.pattern = source["pattern"];
+	    }
+	}
+	export class Config {
+	    enabled: boolean;
+	    windowMs?: number;
+	    rules: Rule[];
+	    title?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.windowMs = source["windowMs"];
+	        this.rules = this.convertValues(source["rules"], Rule);
+	        this.title = source["title"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Metadata {
+	    title?: string;
+	    fields?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Metadata(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.title = source["title"];
+	        this.fields = source["fields"];
+	    }
+	}
+
+}
+
+export namespace bootloop {
+	
+	export class Config {
+	    enabled: boolean;
+	    patterns: string[];
+	    threshold?: number;
+	    windowMs?: number;
+	    stopSequence: boolean;
+	    crashReport: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.patterns = source["patterns"];
+	        this.threshold = source["threshold"];
+	        this.windowMs = source["windowMs"];
+	        this.stopSequence = source["stopSequence"];
+	        this.crashReport = source["crashReport"];
+	    }
+	}
+
+}
+
+export namespace bridge {
+	
+	export class Stats {
+	    toPort: number;
+	    toTarget: number;
+	    dropped: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Stats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.toPort = source["toPort"];
+	        this.toTarget = source["toTarget"];
+	        this.dropped = source["dropped"];
+	    }
+	}
+
+}
+
+export namespace buildhook {
+	
+	export class Config {
+	    enabled: boolean;
+	    port: number;
+	    token?: string;
+	    chunkSize?: number;
+	    chunkDelayMs?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
+	        this.token = source["token"];
+	        this.chunkSize = source["chunkSize"];
+	        this.chunkDelayMs = source["chunkDelayMs"];
+	    }
+	}
+
+}
+
+export namespace capture {
+	
+	export class Meta {
+	    // Go type: time
+	    started: any;
+	    connection: string;
+	    target: string;
+	    title?: string;
+	    device?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Meta(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.started = this.convertValues(source["started"], null);
+	        this.connection = source["connection"];
+	        this.target = source["target"];
+	        this.title = source["title"];
+	        this.device = source["device"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Info {
+	    id: string;
+	    path: string;
+	    size: number;
+	    meta: Meta;
+	    active: boolean;
+	    bookmarked: boolean;
+	    encrypted: boolean;
+	    locked: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Info(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.meta = this.convertValues(source["meta"], Meta);
+	        this.active = source["active"];
+	        this.bookmarked = source["bookmarked"];
+	        this.encrypted = source["encrypted"];
+	        this.locked = source["locked"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace console {
+	
+	export class Result {
+	    command: string;
+	    output: string;
+	    prompt: string;
+	    durationMs: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.command = source["command"];
+	        this.output = source["output"];
+	        this.prompt = source["prompt"];
+	        this.durationMs = source["durationMs"];
+	        this.error = source["error"];
+	    }
+	}
+	export class Step {
+	    command: string;
+	    prompt: string;
+	    timeoutMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Step(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.command = source["command"];
+	        this.prompt = source["prompt"];
+	        this.timeoutMs = source["timeoutMs"];
+	    }
+	}
+
+}
+
+export namespace convert {
+	
+	export class Value {
+	    type: string;
+	    endian?: string;
+	    value: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Value(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.endian = source["endian"];
+	        this.value = source["value"];
+	    }
+	}
+
+}
+
+export namespace crashlog {
+	
+	export class Pattern {
+	    name: string;
+	    pattern: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Pattern(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.pattern = source["pattern"];
+	    }
+	}
+	export class Config {
+	    enabled: boolean;
+	    patterns: Pattern[];
+	    before?: number;
+	    after?: number;
+	    quietMs?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.patterns = this.convertValues(source["patterns"], Pattern);
+	        this.before = source["before"];
+	        this.after = source["after"];
+	        this.quietMs = source["quietMs"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Report {
+	    id: string;
+	    // Go type: time
+	    time: any;
+	    trigger: string;
+	    line: string;
+	    connection?: string;
+	    capture?: string;
+	    title?: string;
+	    device?: Record<string, string>;
+	    lines: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.time = this.convertValues(source["time"], null);
+	        this.trigger = source["trigger"];
+	        this.line = source["line"];
+	        this.connection = source["connection"];
+	        this.capture = source["capture"];
+	        this.title = source["title"];
+	        this.device = source["device"];
+	        this.lines = source["lines"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace decoder {
+	
+	export class Score {
+	    protocol: string;
+	    confidence: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Score(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.protocol = source["protocol"];
+	        this.confidence = source["confidence"];
+	    }
+	}
+	export class Detection {
+	    protocol: string;
+	    confidence: number;
+	    scores: Score[];
+	    sampled: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Detection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.protocol = source["protocol"];
+	        this.confidence = source["confidence"];
+	        this.scores = this.convertValues(source["scores"], Score);
+	        this.sampled = source["sampled"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Info {
+	    name: string;
+	    description: string;
+	    source: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Info(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.source = source["source"];
+	    }
+	}
+
+}
+
+export namespace deeplink {
+	
+	export class Link {
+	    connection?: journal.Connection;
+	    capture?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Link(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.connection = this.convertValues(source["connection"], journal.Connection);
+	        this.capture = source["capture"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace diagnostics {
+	
+	export class DumpInfo {
+	    name: string;
+	    path: string;
+	    size: number;
+	    // Go type: time
+	    time: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new DumpInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.time = this.convertValues(source["time"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Entry {
+	    // Go type: time
+	    time: any;
+	    level: string;
+	    component?: string;
+	    message: string;
+	    attrs?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Entry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.level = source["level"];
+	        this.component = source["component"];
+	        this.message = source["message"];
+	        this.attrs = source["attrs"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace driverhealth {
+	
+	export class Driver {
+	    name: string;
+	    version?: string;
+	    provider?: string;
+	    date?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Driver(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.version = source["version"];
+	        this.provider = source["provider"];
+	        this.date = source["date"];
+	    }
+	}
+	export class Issue {
+	    code: string;
+	    severity: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Issue(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.severity = source["severity"];
+	        this.message = source["message"];
+	    }
+	}
+	export class Report {
+	    port: string;
+	    vid?: string;
+	    pid?: string;
+	    chip: string;
+	    driver: Driver;
+	    latencyTimerMs: number;
+	    issues: Issue[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.port = source["port"];
+	        this.vid = source["vid"];
+	        this.pid = source["pid"];
+	        this.chip = source["chip"];
+	        this.driver = this.convertValues(source["driver"], Driver);
+	        this.latencyTimerMs = source["latencyTimerMs"];
+	        this.issues = this.convertValues(source["issues"], Issue);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace editorrpc {
+	
+	export class Client {
+	    id: string;
+	    remote: string;
+	    // Go type: time
+	    connected: any;
+	    subscribed: boolean;
+	    dropped: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Client(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.remote = source["remote"];
+	        this.connected = this.convertValues(source["connected"], null);
+	        this.subscribed = source["subscribed"];
+	        this.dropped = source["dropped"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Config {
+	    enabled: boolean;
+	    port: number;
+	    token?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
+	        this.token = source["token"];
+	    }
+	}
+
+}
+
+export namespace escpos {
+	
+	export class Status {
+	    online: boolean;
+	    drawerOpen: boolean;
+	    coverOpen: boolean;
+	    feedButton: boolean;
+	    paperOut: boolean;
+	    paperNearEnd: boolean;
+	    error: boolean;
+	    cutterError: boolean;
+	    unrecoverable: boolean;
+	    autoRecoverable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Status(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.online = source["online"];
+	        this.drawerOpen = source["drawerOpen"];
+	        this.coverOpen = source["coverOpen"];
+	        this.feedButton = source["feedButton"];
+	        this.paperOut = source["paperOut"];
+	        this.paperNearEnd = source["paperNearEnd"];
+	        this.error = source["error"];
+	        this.cutterError = source["cutterError"];
+	        this.unrecoverable = source["unrecoverable"];
+	        this.autoRecoverable = source["autoRecoverable"];
+	    }
+	}
+
+}
+
+export namespace framestats {
+	
+	export class Conversation {
+	    protocol: string;
+	    peer: string;
+	    count: number;
+	    bytes: number;
+	    errors: number;
+	    errorRate: number;
+	    // Go type: time
+	    firstSeen: any;
+	    // Go type: time
+	    lastSeen: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Conversation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.protocol = source["protocol"];
+	        this.peer = source["peer"];
+	        this.count = source["count"];
+	        this.bytes = source["bytes"];
+	        this.errors = source["errors"];
+	        this.errorRate = source["errorRate"];
+	        this.firstSeen = this.convertValues(source["firstSeen"], null);
+	        this.lastSeen = this.convertValues(source["lastSeen"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class TypeStats {
+	    protocol: string;
+	    type: string;
+	    count: number;
+	    bytes: number;
+	    errors: number;
+	    errorRate: number;
+	    // Go type: time
+	    firstSeen: any;
+	    // Go type: time
+	    lastSeen: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new TypeStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.protocol = source["protocol"];
+	        this.type = source["type"];
+	        this.count = source["count"];
+	        this.bytes = source["bytes"];
+	        this.errors = source["errors"];
+	        this.errorRate = source["errorRate"];
+	        this.firstSeen = this.convertValues(source["firstSeen"], null);
+	        this.lastSeen = this.convertValues(source["lastSeen"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace fwdb {
+	
+	export class Version {
+	    version: string;
+	    // Go type: time
+	    firstSeen: any;
+	    // Go type: time
+	    lastSeen: any;
+	    sessions: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Version(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.firstSeen = this.convertValues(source["firstSeen"], null);
+	        this.lastSeen = this.convertValues(source["lastSeen"], null);
+	        this.sessions = source["sessions"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Device {
+	    id: string;
+	    name?: string;
+	    port?: string;
+	    // Go type: time
+	    lastSeen: any;
+	    current: string;
+	    versions: Version[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Device(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.port = source["port"];
+	        this.lastSeen = this.convertValues(source["lastSeen"], null);
+	        this.current = source["current"];
+	        this.versions = this.convertValues(source["versions"], Version);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace identify {
+	
+	export class Fingerprint {
+	    vendor: string;
+	    model: string;
+	    profile: string;
+	    confidence: number;
+	    sources: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Fingerprint(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.vendor = source["vendor"];
+	        this.model = source["model"];
+	        this.profile = source["profile"];
+	        this.confidence = source["confidence"];
+	        this.sources = source["sources"];
+	    }
+	}
+
+}
+
+export namespace instrument {
+	
+	export class Profile {
+	    name: string;
+	    kind: string;
+	    match: string;
+	    channels?: number;
+	    setVoltage?: string;
+	    setCurrent?: string;
+	    output?: string;
+	    measVoltage?: string;
+	    measCurrent?: string;
+	    functions?: Record<string, string>;
+	    read?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Profile(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.kind = source["kind"];
+	        this.match = source["match"];
+	        this.channels = source["channels"];
+	        this.setVoltage = source["setVoltage"];
+	        this.setCurrent = source["setCurrent"];
+	        this.output = source["output"];
+	        this.measVoltage = source["measVoltage"];
+	        this.measCurrent = source["measCurrent"];
+	        this.functions = source["functions"];
+	        this.read = source["read"];
+	    }
+	}
+	export class Reading {
+	    channel: number;
+	    voltage: number;
+	    current: number;
+	    power: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Reading(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.channel = source["channel"];
+	        this.voltage = source["voltage"];
+	        this.current = source["current"];
+	        this.power = source["power"];
+	    }
+	}
+
+}
+
+export namespace jlink {
+	
+	export class ElfConfig {
+	    path: string;
+	    addr: number;
+	    size: number;
+	    up: number;
+	    down: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ElfConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.addr = source["addr"];
+	        this.size = source["size"];
+	        this.up = source["up"];
+	        this.down = source["down"];
+	    }
+	}
+	export class Layout {
+	    addr: number;
+	    maxUp: number;
+	    maxDown: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Layout(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.addr = source["addr"];
+	        this.maxUp = source["maxUp"];
+	        this.maxDown = source["maxDown"];
+	    }
+	}
+	export class LogEntry {
+	    // Go type: time
+	    time: any;
+	    level: string;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new LogEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.level = source["level"];
+	        this.message = source["message"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Target {
+	    mode: string;
+	    serial: number;
+	    host?: string;
+	    port?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Target(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.serial = source["serial"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	    }
+	}
+
+}
+
+export namespace journal {
+	
+	export class AutoSend {
+	    enabled: boolean;
+	    payload: string;
+	    hex: boolean;
+	    intervalMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AutoSend(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.payload = source["payload"];
+	        this.hex = source["hex"];
+	        this.intervalMs = source["intervalMs"];
+	    }
+	}
+	export class Connection {
+	    type: string;
+	    port?: string;
+	    baudRate?: number;
+	    dataBits?: number;
+	    stopBits?: number;
+	    parity?: string;
+	    flowControl?: string;
+	    rs485?: ports.RS485Config;
+	    host?: string;
+	    localPort?: string;
+	    chip?: string;
+	    speed?: number;
+	    interface?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Connection(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.port = source["port"];
+	        this.baudRate = source["baudRate"];
+	        this.dataBits = source["dataBits"];
+	        this.stopBits = source["stopBits"];
+	        this.parity = source["parity"];
+	        this.flowControl = source["flowControl"];
+	        this.rs485 = this.convertValues(source["rs485"], ports.RS485Config);
+	        this.host = source["host"];
+	        this.localPort = source["localPort"];
+	        this.chip = source["chip"];
+	        this.speed = source["speed"];
+	        this.interface = source["interface"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Session {
+	    logging: boolean;
+	    logPath?: string;
+	    autoSend?: AutoSend;
+	    pending?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Session(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.logging = source["logging"];
+	        this.logPath = source["logPath"];
+	        this.autoSend = this.convertValues(source["autoSend"], AutoSend);
+	        this.pending = source["pending"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class State {
+	    // Go type: time
+	    started: any;
+	    // Go type: time
+	    updated: any;
+	    clean: boolean;
+	    connection?: Connection;
+	    session: Session;
+	
+	    static createFrom(source: any = {}) {
+	        return new State(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.started = this.convertValues(source["started"], null);
+	        this.updated = this.convertValues(source["updated"], null);
+	        this.clean = source["clean"];
+	        this.connection = this.convertValues(source["connection"], Connection);
+	        this.session = this.convertValues(source["session"], Session);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace linecap {
+	
+	export class Edge {
+	    // Go type: time
+	    time: any;
+	    line: string;
+	    level: boolean;
+	    initial?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Edge(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.line = source["line"];
+	        this.level = source["level"];
+	        this.initial = source["initial"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Levels {
+	    cts: boolean;
+	    dsr: boolean;
+	    dcd: boolean;
+	    ri: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Levels(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.cts = source["cts"];
+	        this.dsr = source["dsr"];
+	        this.dcd = source["dcd"];
+	        this.ri = source["ri"];
+	    }
+	}
+
+}
+
+export namespace loopback {
+	
+	export class Config {
+	    pattern: string;
+	    length?: number;
+	    timeoutMs?: number;
+	    seed?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pattern = source["pattern"];
+	        this.length = source["length"];
+	        this.timeoutMs = source["timeoutMs"];
+	        this.seed = source["seed"];
+	    }
+	}
+	export class Report {
+	    passed: boolean;
+	    sent: number;
+	    received: number;
+	    byteErrors: number;
+	    bitErrors: number;
+	    missing: number;
+	    extra: number;
+	    firstError: number;
+	    elapsedUs: number;
+	    timedOut: boolean;
+	    bytesPerSec: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.passed = source["passed"];
+	        this.sent = source["sent"];
+	        this.received = source["received"];
+	        this.byteErrors = source["byteErrors"];
+	        this.bitErrors = source["bitErrors"];
+	        this.missing = source["missing"];
+	        this.extra = source["extra"];
+	        this.firstError = source["firstError"];
+	        this.elapsedUs = source["elapsedUs"];
+	        this.timedOut = source["timedOut"];
+	        this.bytesPerSec = source["bytesPerSec"];
+	    }
+	}
+
+}
+
+export namespace mailreport {
+	
+	export class Config {
+	    enabled: boolean;
+	    host: string;
+	    port: number;
+	    security: string;
+	    username?: string;
+	    password?: string;
+	    passwordSet: boolean;
+	    from: string;
+	    to: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.host = source["host"];
+	        this.port = source["port"];
+	        this.security = source["security"];
+	        this.username = source["username"];
+	        this.password = source["password"];
+	        this.passwordSet = source["passwordSet"];
+	        this.from = source["from"];
+	        this.to = source["to"];
+	    }
+	}
+
+}
+
+export namespace main {
+	
+	export class BuildHookStatus {
+	    enabled: boolean;
+	    port: number;
+	    token?: string;
+	    chunkSize?: number;
+	    chunkDelayMs?: number;
+	    addr?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BuildHookStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
+	        this.token = source["token"];
+	        this.chunkSize = source["chunkSize"];
+	        this.chunkDelayMs = source["chunkDelayMs"];
+	        this.addr = source["addr"];
+	    }
+	}
+	export class CaptureEncryptionStatus {
+	    mode: string;
+	    unlocked: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CaptureEncryptionStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.unlocked = source["unlocked"];
+	    }
+	}
+	export class CleanupResult {
+	    removed: retention.Removal[];
+	    freed: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CleanupResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.removed = this.convertValues(source["removed"], retention.Removal);
+	        this.freed = source["freed"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StopConditions {
+	    patterns?: string[];
+	    regex?: boolean;
+	    alert?: boolean;
+	    reboot?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new StopConditions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.patterns = source["patterns"];
+	        this.regex = source["regex"];
+	        this.alert = source["alert"];
+	        this.reboot = source["reboot"];
+	    }
+	}
+	export class CommandBatch {
+	    commands: console.Step[];
+	    prompt: string;
+	    timeoutMs: number;
+	    continueOnError: boolean;
+	    stopOn?: StopConditions;
+	
+	    static createFrom(source: any = {}) {
+	        return new CommandBatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.commands = this.convertValues(source["commands"], console.Step);
+	        this.prompt = source["prompt"];
+	        this.timeoutMs = source["timeoutMs"];
+	        this.continueOnError = source["continueOnError"];
+	        this.stopOn = this.convertValues(source["stopOn"], StopConditions);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CreatedAPIKey {
+	    key: apikey.Key;
+	    secret: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new CreatedAPIKey(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = this.convertValues(source["key"], apikey.Key);
+	        this.secret = source["secret"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EditorServerStatus {
+	    enabled: boolean;
+	    port: number;
+	    token?: string;
+	    url?: string;
+	    clients: editorrpc.Client[];
+	
+	    static createFrom(source: any = {}) {
+	        return new EditorServerStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.port = source["port"];
+	        this.token = source["token"];
+	        this.url = source["url"];
+	        this.clients = this.convertValues(source["clients"], editorrpc.Client);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class EscPosJob {
+	    text: string;
+	    align: number;
+	    bold: boolean;
+	    width: number;
+	    height: number;
+	    qr: string;
+	    imagePath: string;
+	    threshold: number;
+	    feed: number;
+	    cut: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new EscPosJob(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.align = source["align"];
+	        this.bold = source["bold"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.qr = source["qr"];
+	        this.imagePath = source["imagePath"];
+	        this.threshold = source["threshold"];
+	        this.feed = source["feed"];
+	        this.cut = source["cut"];
+	    }
+	}
+	export class GpsClockStatus {
+	    port: string;
+	    locked: boolean;
+	    source: string;
+	    offsetNs: number;
+	    jitterNs: number;
+	    samples: number;
+	    pulses: number;
+	    // Go type: time
+	    lastFix: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new GpsClockStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.port = source["port"];
+	        this.locked = source["locked"];
+	        this.source = source["source"];
+	        this.offsetNs = source["offsetNs"];
+	        this.jitterNs = source["jitterNs"];
+	        this.samples = source["samples"];
+	        this.pulses = source["pulses"];
+	        this.lastFix = this.convertValues(source["lastFix"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class JLinkLogConfig {
+	    enabled: boolean;
+	    toFile: boolean;
+	    file: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new JLinkLogConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.toFile = source["toFile"];
+	        this.file = source["file"];
+	    }
+	}
+	export class LineCaptureStatus {
+	    running: boolean;
+	    levels: linecap.Levels;
+	    samples: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LineCaptureStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.levels = this.convertValues(source["levels"], linecap.Levels);
+	        this.samples = source["samples"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LineModeConfig {
+	    enabled: boolean;
+	    timeoutMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LineModeConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.timeoutMs = source["timeoutMs"];
+	    }
+	}
+	export class MemoryWrite {
+	    seq: number;
+	    // Go type: time
+	    time: any;
+	    address: number;
+	    old: string;
+	    new: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MemoryWrite(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.seq = source["seq"];
+	        this.time = this.convertValues(source["time"], null);
+	        this.address = source["address"];
+	        this.old = source["old"];
+	        this.new = source["new"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class NotebookSession {
+	    url: string;
+	    token: string;
+	    clientUrl: string;
+	    snippet: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new NotebookSession(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.url = source["url"];
+	        this.token = source["token"];
+	        this.clientUrl = source["clientUrl"];
+	        this.snippet = source["snippet"];
+	    }
+	}
+	export class PayloadPreview {
+	    hex: string;
+	    text: string;
+	    bytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PayloadPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hex = source["hex"];
+	        this.text = source["text"];
+	        this.bytes = source["bytes"];
+	    }
+	}
+	export class PlcProtocol {
+	    name: string;
+	    areas: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PlcProtocol(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.areas = source["areas"];
+	    }
+	}
+	export class PlcRequest {
+	    protocol: string;
+	    station: number;
+	    area: string;
+	    address: number;
+	    count: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PlcRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.protocol = source["protocol"];
+	        this.station = source["station"];
+	        this.area = source["area"];
+	        this.address = source["address"];
+	        this.count = source["count"];
+	    }
+	}
+	export class PortableStatus {
+	    portable: boolean;
+	    dataDir: string;
+	    portableDir: string;
+	    installedDir: string;
+	    forced: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new PortableStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.portable = source["portable"];
+	        this.dataDir = source["dataDir"];
+	        this.portableDir = source["portableDir"];
+	        this.installedDir = source["installedDir"];
+	        this.forced = source["forced"];
+	    }
+	}
+	export class PowerMeterConfig {
+	    kind: string;
+	    port: string;
+	    baudRate: number;
+	    sourceMv: number;
+	    voltageMv: number;
+	    averageMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PowerMeterConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.kind = source["kind"];
+	        this.port = source["port"];
+	        this.baudRate = source["baudRate"];
+	        this.sourceMv = source["sourceMv"];
+	        this.voltageMv = source["voltageMv"];
+	        this.averageMs = source["averageMs"];
+	    }
+	}
+	export class PowerMeterStatus {
+	    open: boolean;
+	    config: PowerMeterConfig;
+	    charge: number;
+	    energy: number;
+	    samples: number;
+	    // Go type: powermeter
+	    last: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new PowerMeterStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.open = source["open"];
+	        this.config = this.convertValues(source["config"], PowerMeterConfig);
+	        this.charge = source["charge"];
+	        this.energy = source["energy"];
+	        this.samples = source["samples"];
+	        this.last = this.convertValues(source["last"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProfileStatus {
+	    running: boolean;
+	    probe: string;
+	    interval: number;
+	    elf: string;
+	    symbols: number;
+	    samples: number;
+	    sleep: number;
+	    unknown: number;
+	    overflows: number;
+	    top: profiler.Entry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ProfileStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.probe = source["probe"];
+	        this.interval = source["interval"];
+	        this.elf = source["elf"];
+	        this.symbols = source["symbols"];
+	        this.samples = source["samples"];
+	        this.sleep = source["sleep"];
+	        this.unknown = source["unknown"];
+	        this.overflows = source["overflows"];
+	        this.top = this.convertValues(source["top"], profiler.Entry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ProfilerConfig {
+	    probe: string;
+	    cpuHz: number;
+	    swoHz: number;
+	    interval: number;
+	    durationMs: number;
+	    elf: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ProfilerConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.probe = source["probe"];
+	        this.cpuHz = source["cpuHz"];
+	        this.swoHz = source["swoHz"];
+	        this.interval = source["interval"];
+	        this.durationMs = source["durationMs"];
+	        this.elf = source["elf"];
+	    }
+	}
+	export class RttBridgeStatus {
+	    active: boolean;
+	    probe: string;
+	    port: string;
+	    stats: bridge.Stats;
+	
+	    static createFrom(source: any = {}) {
+	        return new RttBridgeStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.active = source["active"];
+	        this.probe = source["probe"];
+	        this.port = source["port"];
+	        this.stats = this.convertValues(source["stats"], bridge.Stats);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RttLayoutStatus {
+	    soft: boolean;
+	    background: boolean;
+	    layout: jlink.Layout;
+	    elf?: jlink.ElfConfig;
+	    mismatch: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new RttLayoutStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.soft = source["soft"];
+	        this.background = source["background"];
+	        this.layout = this.convertValues(source["layout"], jlink.Layout);
+	        this.elf = this.convertValues(source["elf"], jlink.ElfConfig);
+	        this.mismatch = source["mismatch"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class RttProbeInfo {
+	    label: string;
+	    target: jlink.Target;
+	    chip: string;
+	    speed: number;
+	    interface: string;
+	    // Go type: time
+	    opened: any;
+	    backgroundAccess: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new RttProbeInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.target = this.convertValues(source["target"], jlink.Target);
+	        this.chip = source["chip"];
+	        this.speed = source["speed"];
+	        this.interface = source["interface"];
+	        this.opened = this.convertValues(source["opened"], null);
+	        this.backgroundAccess = source["backgroundAccess"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScheduledFrame {
+	    offsetUs: number;
+	    data: string;
+	    hex: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScheduledFrame(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.offsetUs = source["offsetUs"];
+	        this.data = source["data"];
+	        this.hex = source["hex"];
+	    }
+	}
+	export class SerialOptions {
+	    port: string;
+	    baudRate: number;
+	    dataBits: number;
+	    stopBits: number;
+	    parity: string;
+	    flowControl?: string;
+	    rs485?: ports.RS485Config;
+	
+	    static createFrom(source: any = {}) {
+	        return new SerialOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.port = source["port"];
+	        this.baudRate = source["baudRate"];
+	        this.dataBits = source["dataBits"];
+	        this.stopBits = source["stopBits"];
+	        this.parity = source["parity"];
+	        this.flowControl = source["flowControl"];
+	        this.rs485 = this.convertValues(source["rs485"], ports.RS485Config);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ShareInfo {
+	    running: boolean;
+	    urls: string[];
+	    token: string;
+	    viewers: share.Viewer[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ShareInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.running = source["running"];
+	        this.urls = source["urls"];
+	        this.token = source["token"];
+	        this.viewers = this.convertValues(source["viewers"], share.Viewer);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SoakConfig {
+	    name: string;
+	    durationMin: number;
+	    intervalSec: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SoakConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.durationMin = source["durationMin"];
+	        this.intervalSec = source["intervalSec"];
+	    }
+	}
+
+}
+
+export namespace operation {
+	
+	export class Info {
+	    id: string;
+	    kind: string;
+	    // Go type: time
+	    started: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Info(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.started = this.convertValues(source["started"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace payload {
+	
+	export class FrameSpec {
+	    bodyStart?: number;
+	    lenWidth?: number;
+	    lenAdjust?: number;
+	    endian?: string;
+	    checksum?: string;
+	    sumFrom?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new FrameSpec(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.bodyStart = source["bodyStart"];
+	        this.lenWidth = source["lenWidth"];
+	        this.lenAdjust = source["lenAdjust"];
+	        this.endian = source["endian"];
+	        this.checksum = source["checksum"];
+	        this.sumFrom = source["sumFrom"];
+	    }
+	}
+
+}
+
+export namespace permissions {
+	
+	export class Report {
+	    port: string;
+	    exists: boolean;
+	    accessible: boolean;
+	    group?: string;
+	    userInGroup: boolean;
+	    needsRelogin: boolean;
+	    commands?: string[];
+	    udevRule?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.port = source["port"];
+	        this.exists = source["exists"];
+	        this.accessible = source["accessible"];
+	        this.group = source["group"];
+	        this.userInGroup = source["userInGroup"];
+	        this.needsRelogin = source["needsRelogin"];
+	        this.commands = source["commands"];
+	        this.udevRule = source["udevRule"];
+	    }
+	}
+
+}
+
+export namespace pipeline {
+	
+	export class StageStats {
+	    session: string;
+	    name: string;
+	    queueLen: number;
+	    queueCap: number;
+	    dropped: number;
+	    processed: number;
+	    busyMs: number;
+	    busy: number;
+	    avgMicros: number;
+	    panics: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new StageStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.session = source["session"];
+	        this.name = source["name"];
+	        this.queueLen = source["queueLen"];
+	        this.queueCap = source["queueCap"];
+	        this.dropped = source["dropped"];
+	        this.processed = source["processed"];
+	        this.busyMs = source["busyMs"];
+	        this.busy = source["busy"];
+	        this.avgMicros = source["avgMicros"];
+	        this.panics = source["panics"];
+	    }
+	}
+
+}
+
+export namespace ports {
+	
+	export class Holder {
+	    pid: number;
+	    name: string;
+	    command?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Holder(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pid = source["pid"];
+	        this.name = source["name"];
+	        this.command = source["command"];
+	    }
+	}
+	export class Port {
+	    name: string;
+	    path: string;
+	    friendlyName: string;
+	    isUsb: boolean;
+	    vid?: string;
+	    pid?: string;
+	    serialNumber?: string;
+	    manufacturer?: string;
+	    product?: string;
+	    label: string;
+	    locationId?: string;
+	    kind?: string;
+	    recommended: boolean;
+	    alternative?: string;
+	    busy: boolean;
+	    hidden: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Port(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.friendlyName = source["friendlyName"];
+	        this.isUsb = source["isUsb"];
+	        this.vid = source["vid"];
+	        this.pid = source["pid"];
+	        this.serialNumber = source["serialNumber"];
+	        this.manufacturer = source["manufacturer"];
+	        this.product = source["product"];
+	        this.label = source["label"];
+	        this.locationId = source["locationId"];
+	        this.kind = source["kind"];
+	        this.recommended = source["recommended"];
+	        this.alternative = source["alternative"];
+	        this.busy = source["busy"];
+	        this.hidden = source["hidden"];
+	    }
+	}
+	export class RS485Config {
+	    mode: string;
+	    activeLow: boolean;
+	    delayBeforeUs: number;
+	    delayAfterUs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new RS485Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mode = source["mode"];
+	        this.activeLow = source["activeLow"];
+	        this.delayBeforeUs = source["delayBeforeUs"];
+	        this.delayAfterUs = source["delayAfterUs"];
+	    }
+	}
+
+}
+
+export namespace profiler {
+	
+	export class FuncCoverage {
+	    name: string;
+	    addr: number;
+	    samples: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new FuncCoverage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.addr = source["addr"];
+	        this.samples = source["samples"];
+	    }
+	}
+	export class Coverage {
+	    functions: number;
+	    executed: number;
+	    percent: number;
+	    samples: number;
+	    list: FuncCoverage[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Coverage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.functions = source["functions"];
+	        this.executed = source["executed"];
+	        this.percent = source["percent"];
+	        this.samples = source["samples"];
+	        this.list = this.convertValues(source["list"], FuncCoverage);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace ratelimit {
+	
+	export class Stats {
+	    maxUpdatesPerSecond: number;
+	    maxBytesPerUpdate: number;
+	    updates: number;
+	    emittedBytes: number;
+	    droppedBytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Stats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxUpdatesPerSecond = source["maxUpdatesPerSecond"];
+	        this.maxBytesPerUpdate = source["maxBytesPerUpdate"];
+	        this.updates = source["updates"];
+	        this.emittedBytes = source["emittedBytes"];
+	        this.droppedBytes = source["droppedBytes"];
+	    }
+	}
+
+}
+
+export namespace reconnect {
+	
+	export class Policy {
+	    enabled: boolean;
+	    intervalMs: number;
+	    timeoutSec: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Policy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.intervalMs = source["intervalMs"];
+	        this.timeoutSec = source["timeoutSec"];
+	    }
+	}
+
+}
+
+export namespace redact {
+	
+	export class Rule {
+	    name: string;
+	    pattern: string;
+	    mask: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Rule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.pattern = source["pattern"];
+	        this.mask = source["mask"];
+	    }
+	}
+	export class Config {
+	    enabled: boolean;
+	    rules: Rule[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.rules = this.convertValues(source["rules"], Rule);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace retention {
+	
+	export class Policy {
+	    maxAgeDays: number;
+	    quotaMb: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Policy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxAgeDays = source["maxAgeDays"];
+	        this.quotaMb = source["quotaMb"];
+	    }
+	}
+	export class Removal {
+	    id: string;
+	    path: string;
+	    size: number;
+	    // Go type: time
+	    modified: any;
+	    protected: boolean;
+	    active: boolean;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Removal(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.modified = this.convertValues(source["modified"], null);
+	        this.protected = source["protected"];
+	        this.active = source["active"];
+	        this.reason = source["reason"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Usage {
+	    files: number;
+	    bytes: number;
+	    protectedFiles: number;
+	    protectedBytes: number;
+	    quotaBytes: number;
+	    // Go type: time
+	    oldest: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Usage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.files = source["files"];
+	        this.bytes = source["bytes"];
+	        this.protectedFiles = source["protectedFiles"];
+	        this.protectedBytes = source["protectedBytes"];
+	        this.quotaBytes = source["quotaBytes"];
+	        this.oldest = this.convertValues(source["oldest"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace rules {
+	
+	export class Action {
+	    type: string;
+	    params?: Record<string, string>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Action(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.params = source["params"];
+	    }
+	}
+	export class Rule {
+	    id: string;
+	    name: string;
+	    pattern: string;
+	    regex: boolean;
+	    caseSensitive: boolean;
+	    enabled: boolean;
+	    cooldownMs?: number;
+	    actions: Action[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Rule(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.pattern = source["pattern"];
+	        this.regex = source["regex"];
+	        this.caseSensitive = source["caseSensitive"];
+	        this.enabled = source["enabled"];
+	        this.cooldownMs = source["cooldownMs"];
+	        this.actions = this.convertValues(source["actions"], Action);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace sanitize {
+	
+	export class Options {
+	    stripNul: boolean;
+	    stripBom: boolean;
+	    replaceNonPrintable: boolean;
+	    placeholder: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Options(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stripNul = source["stripNul"];
+	        this.stripBom = source["stripBom"];
+	        this.replaceNonPrintable = source["replaceNonPrintable"];
+	        this.placeholder = source["placeholder"];
+	    }
+	}
+	export class Stats {
+	    nuls: number;
+	    boms: number;
+	    replaced: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Stats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.nuls = source["nuls"];
+	        this.boms = source["boms"];
+	        this.replaced = source["replaced"];
+	    }
+	}
+
+}
+
+export namespace scpi {
+	
+	export class Error {
+	    code: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Error(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.message = source["message"];
+	    }
+	}
+	export class Identity {
+	    manufacturer: string;
+	    model: string;
+	    serial: string;
+	    firmware: string;
+	    raw: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Identity(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.manufacturer = source["manufacturer"];
+	        this.model = source["model"];
+	        this.serial = source["serial"];
+	        this.firmware = source["firmware"];
+	        this.raw = source["raw"];
+	    }
+	}
+	export class Measurement {
+	    name: string;
+	    query: string;
+	    unit?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Measurement(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.query = source["query"];
+	        this.unit = source["unit"];
+	    }
+	}
+	export class LoggerConfig {
+	    measurements: Measurement[];
+	    intervalMs: number;
+	    pollErrors: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new LoggerConfig(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.measurements = this.convertValues(source["measurements"], Measurement);
+	        this.intervalMs = source["intervalMs"];
+	        this.pollErrors = source["pollErrors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace series {
+	
+	export class Info {
+	    name: string;
+	    unit?: string;
+	    count: number;
+	    // Go type: time
+	    first: any;
+	    // Go type: time
+	    last: any;
+	    value: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Info(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.unit = source["unit"];
+	        this.count = source["count"];
+	        this.first = this.convertValues(source["first"], null);
+	        this.last = this.convertValues(source["last"], null);
+	        this.value = source["value"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Sample {
+	    series: string;
+	    // Go type: time
+	    time: any;
+	    value: number;
+	    unit?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Sample(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.series = source["series"];
+	        this.time = this.convertValues(source["time"], null);
+	        this.value = source["value"];
+	        this.unit = source["unit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace sessionvars {
+	
+	export class Var {
+	    name: string;
+	    value: string;
+	    source: string;
+	    // Go type: time
+	    updated: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Var(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.value = source["value"];
+	        this.source = source["source"];
+	        this.updated = this.convertValues(source["updated"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace setup {
+	
+	export class Check {
+	    id: string;
+	    status: string;
+	    message: string;
+	    hints?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Check(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.status = source["status"];
+	        this.message = source["message"];
+	        this.hints = source["hints"];
+	    }
+	}
+	export class Settings {
+	    port: string;
+	    profile?: string;
+	    baudRate: number;
+	    dataBits: number;
+	    stopBits: number;
+	    parity: string;
+	    flowControl?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.port = source["port"];
+	        this.profile = source["profile"];
+	        this.baudRate = source["baudRate"];
+	        this.dataBits = source["dataBits"];
+	        this.stopBits = source["stopBits"];
+	        this.parity = source["parity"];
+	        this.flowControl = source["flowControl"];
+	    }
+	}
+	export class Device {
+	    port: ports.Port;
+	    fingerprint: identify.Fingerprint;
+	    suggested: Settings;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Device(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.port = this.convertValues(source["port"], ports.Port);
+	        this.fingerprint = this.convertValues(source["fingerprint"], identify.Fingerprint);
+	        this.suggested = this.convertValues(source["suggested"], Settings);
+	        this.score = source["score"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Report {
+	    settings: Settings;
+	    checks: Check[];
+	    ready: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Report(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.settings = this.convertValues(source["settings"], Settings);
+	        this.checks = this.convertValues(source["checks"], Check);
+	        this.ready = source["ready"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class State {
+	    completed: boolean;
+	    // Go type: time
+	    completedAt?: any;
+	    settings: Settings;
+	
+	    static createFrom(source: any = {}) {
+	        return new State(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.completed = source["completed"];
+	        this.completedAt = this.convertValues(source["completedAt"], null);
+	        this.settings = this.convertValues(source["settings"], Settings);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace share {
+	
+	export class Viewer {
+	    id: string;
+	    remote: string;
+	    // Go type: time
+	    connected: any;
+	    dropped: number;
+	    name?: string;
+	    requested: boolean;
+	    control: boolean;
+	    keyId?: string;
+	    readOnly: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Viewer(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.remote = source["remote"];
+	        this.connected = this.convertValues(source["connected"], null);
+	        this.dropped = source["dropped"];
+	        this.name = source["name"];
+	        this.requested = source["requested"];
+	        this.control = source["control"];
+	        this.keyId = source["keyId"];
+	        this.readOnly = source["readOnly"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace snippets {
+	
+	export class Snippet {
+	    id: string;
+	    name: string;
+	    description?: string;
+	    payload: string;
+	    hex: boolean;
+	    tags?: string[];
+	    source: string;
+	    frame?: payload.FrameSpec;
+	
+	    static createFrom(source: any = {}) {
+	        return new Snippet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.payload = source["payload"];
+	        this.hex = source["hex"];
+	        this.tags = source["tags"];
+	        this.source = source["source"];
+	        this.frame = this.convertValues(source["frame"], payload.FrameSpec);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace soak {
+	
+	export class Counters {
+	    rxBytes: number;
+	    txBytes: number;
+	    frames: number;
+	    frameErrors: number;
+	    reboots: number;
+	    crashes: number;
+	    alerts: number;
+	    disconnects: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Counters(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.rxBytes = source["rxBytes"];
+	        this.txBytes = source["txBytes"];
+	        this.frames = source["frames"];
+	        this.frameErrors = source["frameErrors"];
+	        this.reboots = source["reboots"];
+	        this.crashes = source["crashes"];
+	        this.alerts = source["alerts"];
+	        this.disconnects = source["disconnects"];
+	    }
+	}
+	export class Snapshot {
+	    // Go type: time
+	    time: any;
+	    elapsedSec: number;
+	    delta: Counters;
+	    rxRate: number;
+	    txRate: number;
+	    connected: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Snapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.time = this.convertValues(source["time"], null);
+	        this.elapsedSec = source["elapsedSec"];
+	        this.delta = this.convertValues(source["delta"], Counters);
+	        this.rxRate = source["rxRate"];
+	        this.txRate = source["txRate"];
+	        this.connected = source["connected"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Summary {
+	    id: string;
+	    name?: string;
+	    // Go type: time
+	    started: any;
+	    // Go type: time
+	    ended: any;
+	    plannedSec: number;
+	    completed: boolean;
+	    totals: Counters;
+	    rxRateMin: number;
+	    rxRateAvg: number;
+	    rxRateMax: number;
+	    stalls: number;
+	    offlineMs: number;
+	    healthy: boolean;
+	    snapshots?: Snapshot[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Summary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.started = this.convertValues(source["started"], null);
+	        this.ended = this.convertValues(source["ended"], null);
+	        this.plannedSec = source["plannedSec"];
+	        this.completed = source["completed"];
+	        this.totals = this.convertValues(source["totals"], Counters);
+	        this.rxRateMin = source["rxRateMin"];
+	        this.rxRateAvg = source["rxRateAvg"];
+	        this.rxRateMax = source["rxRateMax"];
+	        this.stalls = source["stalls"];
+	        this.offlineMs = source["offlineMs"];
+	        this.healthy = source["healthy"];
+	        this.snapshots = this.convertValues(source["snapshots"], Snapshot);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace timesync {
+	
+	export class Config {
+	    onConnect: boolean;
+	    intervalSec: number;
+	    preset: string;
+	    template?: string;
+	    hex?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.onConnect = source["onConnect"];
+	        this.intervalSec = source["intervalSec"];
+	        this.preset = source["preset"];
+	        this.template = source["template"];
+	        this.hex = source["hex"];
+	    }
+	}
+	export class Preset {
+	    name: string;
+	    description: string;
+	    template: string;
+	    hex: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new Preset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.template = source["template"];
+	        this.hex = source["hex"];
+	    }
+	}
+
+}
+
+export namespace txsched {
+	
+	export class Result {
+	    index: number;
+	    plannedUs: number;
+	    actualUs: number;
+	    lateUs: number;
+	    error?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Result(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.index = source["index"];
+	        this.plannedUs = source["plannedUs"];
+	        this.actualUs = source["actualUs"];
+	        this.lateUs = source["lateUs"];
+	        this.error = source["error"];
+	    }
+	}
+
+}
+
 export namespace updater {
 	
 	export class UpdateInfo {
@@ -7,6 +3640,9 @@ export namespace updater {
 	    releaseNotes: string;
 	    downloadUrl: string;
 	    assetSize: number;
+	    signatureUrl?: string;
+	    channel: string;
+	    prerelease: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new UpdateInfo(source);
@@ -20,6 +3656,109 @@ export namespace updater {
 	        this.releaseNotes = source["releaseNotes"];
 	        this.downloadUrl = source["downloadUrl"];
 	        this.assetSize = source["assetSize"];
+	        this.signatureUrl = source["signatureUrl"];
+	        this.channel = source["channel"];
+	        this.prerelease = source["prerelease"];
+	    }
+	}
+
+}
+
+export namespace usage {
+	
+	export class Stats {
+	    disabled: boolean;
+	    // Go type: time
+	    since: any;
+	    launches: number;
+	    sessions: Record<string, number>;
+	    features: Record<string, number>;
+	    errors: Record<string, number>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Stats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.disabled = source["disabled"];
+	        this.since = this.convertValues(source["since"], null);
+	        this.launches = source["launches"];
+	        this.sessions = source["sessions"];
+	        this.features = source["features"];
+	        this.errors = source["errors"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace watchfolder {
+	
+	export class Config {
+	    enabled: boolean;
+	    dir: string;
+	    pattern: string;
+	    protocol: string;
+	    chunkSize: number;
+	    chunkDelayMs: number;
+	    settleMs: number;
+	    intervalMs: number;
+	    after: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.dir = source["dir"];
+	        this.pattern = source["pattern"];
+	        this.protocol = source["protocol"];
+	        this.chunkSize = source["chunkSize"];
+	        this.chunkDelayMs = source["chunkDelayMs"];
+	        this.settleMs = source["settleMs"];
+	        this.intervalMs = source["intervalMs"];
+	        this.after = source["after"];
+	    }
+	}
+
+}
+
+export namespace wedge {
+	
+	export class Config {
+	    enabled: boolean;
+	    filter?: string;
+	    suffix?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new Config(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.filter = source["filter"];
+	        this.suffix = source["suffix"];
 	    }
 	}
 
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/gpsclock"
+
+	"go.bug.st/serial"
+)
+
+// gpsPollInterval DCD 线的采样间隔，PPS 的时间精度受限于该间隔与系统调度
+const gpsPollInterval = time.Millisecond
+
+// OpenGpsReference 打开输出 NMEA 的 GPS 串口作为时间参考
+// PPS 秒脉冲需接到 DCD 线；没有 PPS 时只按语句到达时间粗略校正
+// 锁定后当前会话抓包中的时间戳按 GPS 时间记录，便于对齐多台设备的抓包
+func (a *App) OpenGpsReference(portName string, baudRate int) apperr.Result {
+	a.gpsMutex.Lock()
+	defer a.gpsMutex.Unlock()
+	if a.gpsPort != nil {
+		return apperr.FromError(apperr.New(apperr.CodeAlreadyConnected, a.gpsName))
+	}
+	a.mutex.Lock()
+	busy := a.isConnected && a.connType == TypeSerial && a.serialPortName == portName
+	a.mutex.Unlock()
+	if busy {
+		return apperr.FromError(apperr.New(apperr.CodePortBusy, portName))
+	}
+	if baudRate <= 0 {
+		baudRate = 9600
+	}
+	port, err := a.openSerialContext(portName, &serial.Mode{BaudRate: baudRate, DataBits: 8, Parity: serial.NoParity, StopBits: serial.OneStopBit})
+	if err != nil {
+		return apperr.FromError(err)
+	}
+	a.gpsClock.Reset()
+	a.gpsPort = port
+	a.gpsName = portName
+	a.gpsStop = make(chan struct{})
+	go a.readGps(port)
+	go a.pollPulse(port, a.gpsStop)
+	return apperr.OK()
+}
+
+// CloseGpsReference 关闭 GPS 参考源，之后的时间戳不再校正
+func (a *App) CloseGpsReference() apperr.Result {
+	a.gpsMutex.Lock()
+	defer a.gpsMutex.Unlock()
+	if a.gpsPort == nil {
+		return apperr.OK()
+	}
+	close(a.gpsStop)
+	err := a.gpsPort.Close()
+	a.gpsPort = nil
+	a.gpsName = ""
+	a.gpsStop = nil
+	a.gpsClock.Reset()
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeCloseFailed, err))
+	}
+	return apperr.OK()
+}
+
+// GpsClockStatus GPS 参考源状态
+type GpsClockStatus struct {
+	Port string `json:"port"` // 为空表示未打开
+	gpsclock.Status
+}
+
+// GetGpsClockStatus 返回 GPS 参考源的锁定状态与当前偏差
+func (a *App) GetGpsClockStatus() (GpsClockStatus, error) {
+	a.gpsMutex.Lock()
+	name := a.gpsName
+	a.gpsMutex.Unlock()
+	return GpsClockStatus{Port: name, Status: a.gpsClock.Status()}, nil
+}
+
+// readGps 逐行读取 NMEA 语句，记录每行到达的主机时间
+func (a *App) readGps(port serial.Port) {
+	scanner := bufio.NewScanner(port)
+	for scanner.Scan() {
+		a.gpsClock.Sentence(time.Now(), scanner.Text())
+	}
+	a.gpsMutex.Lock()
+	closed := a.gpsPort != port
+	a.gpsMutex.Unlock()
+	if !closed {
+		a.log("gps").Warn("gps reference stopped", "err", scanner.Err())
+		a.CloseGpsReference()
+	}
+}
+
+// pollPulse 轮询 DCD 线，上升沿即为 PPS 秒脉冲
+func (a *App) pollPulse(port serial.Port, stop <-chan struct{}) {
+	ticker := time.NewTicker(gpsPollInterval)
+	defer ticker.Stop()
+	var high bool
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			bits, err := port.GetModemStatusBits()
+			if err != nil {
+				continue
+			}
+			if bits.DCD && !high {
+				a.gpsClock.Pulse(time.Now())
+			}
+			high = bits.DCD
+		}
+	}
+}
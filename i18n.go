@@ -0,0 +1,19 @@
+package main
+
+import (
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/i18n"
+)
+
+// SetLanguage 设置后端生成消息 (错误提示、系统消息) 的语言，如 "zh"、"en-US"
+func (a *App) SetLanguage(lang string) apperr.Result {
+	if err := i18n.SetLanguage(lang); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	return apperr.OK()
+}
+
+// GetLanguage 返回后端消息当前使用的语言
+func (a *App) GetLanguage() string {
+	return string(i18n.Language())
+}
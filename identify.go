@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/identify"
+	"serial-assistant/pkg/jlink"
+
+	"go.bug.st/serial/enumerator"
+)
+
+// atProbeTimeout 等待 AT 指令应答的最长时间
+const atProbeTimeout = 500 * time.Millisecond
+
+// IdentifyDevice 综合 USB 描述符、启动信息、AT 指令应答及探针读取的芯片 ID 识别设备，
+// 识别出匹配的配置时自动选中并应用该配置的行模式与解码器设置，然后发送 "device-identified" 事件。
+// probeAT 为 true 时会向已打开的连接发送 AT / ATI 指令，对非 AT 设备请勿开启
+func (a *App) IdentifyDevice(portName string, probeAT bool) (identify.Fingerprint, error) {
+	var ev identify.Evidence
+
+	// 1. USB 描述符
+	if p := usbPortDetails(portName); p != nil {
+		ev.VID = p.VID
+		ev.PID = p.PID
+		ev.SerialNumber = p.SerialNumber
+		ev.Product = p.Product
+	}
+
+	// 2. 启动信息：取接收尾部缓存中的内容
+	ev.Banner = string(a.rxSince(0))
+
+	a.mutex.Lock()
+	connected := a.isConnected
+	connType := a.connType
+	a.mutex.Unlock()
+
+	// 3. AT 指令
+	if probeAT && connected && connType != TypeJLink {
+		var resp strings.Builder
+		for _, cmd := range []string{"AT\r\n", "ATI\r\n"} {
+			out := a.probeCommand([]byte(cmd), atProbeTimeout)
+			if len(out) == 0 {
+				break
+			}
+			resp.Write(out)
+		}
+		ev.ATResponse = resp.String()
+	}
+
+	// 4. 探针读取芯片 ID：持锁读取，避免与断开或重连同时访问 DLL
+	if connected && connType == TypeJLink {
+		a.withProbe("", func(jl *jlink.JLinkWrapper) error {
+			id, err := jl.ReadChipID()
+			if err == nil {
+				ev.ChipID = id
+			}
+			return err
+		})
+	}
+
+	fp := identify.Identify(ev)
+
+	a.mutex.Lock()
+	a.deviceFingerprint = fp
+	a.mutex.Unlock()
+
+	if fp.Profile != "" {
+		a.applyDeviceProfile(fp.Profile)
+		a.bus.Publish(EventDeviceIdentified, fp)
+	}
+	return fp, nil
+}
+
+// applyDeviceProfile 将识别出的配置的行模式与解码器设置应用到当前会话
+func (a *App) applyDeviceProfile(profile string) {
+	p, ok := identify.PresetFor(profile)
+	if !ok {
+		return
+	}
+	a.SetLineMode(LineModeConfig{Enabled: p.LineMode, TimeoutMs: p.LineTimeoutMs})
+	for _, name := range p.Decoders {
+		if err := a.decoders.Enable(name); err != nil {
+			a.log("identify").Warn("profile decoder not enabled", "profile", profile, "decoder", name, "err", err)
+		}
+	}
+	a.log("identify").Info("device profile applied", "profile", profile)
+	a.bus.Publish(EventSysMsg, i18n.T("app.profile_applied", profile))
+}
+
+// probeCommand 发送一条指令并收集应答，收到 OK/ERROR 或超时后返回
+func (a *App) probeCommand(cmd []byte, timeout time.Duration) []byte {
+	mark := a.rxMark()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := a.writeContext(ctx, cmd); err != nil {
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+		out := a.rxSince(mark)
+		if bytes.Contains(out, []byte("OK\r\n")) || bytes.Contains(out, []byte("ERROR")) {
+			return out
+		}
+	}
+	return a.rxSince(mark)
+}
+
+// usbPortDetails 返回串口对应的 USB 描述符，非 USB 设备或枚举失败时返回 nil
+func usbPortDetails(portName string) *enumerator.PortDetails {
+	if portName == "" {
+		return nil
+	}
+	ports, err := enumerator.GetDetailedPortsList()
+	if err != nil {
+		return nil
+	}
+	for _, p := range ports {
+		if p.Name == portName && p.IsUSB {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestApplyDeviceProfile(t *testing.T) {
+	a := NewApp()
+	a.applyDeviceProfile("stm32") // 没有预设，不改变设置
+	if a.GetLineMode().Enabled {
+		t.Error("stm32 should not change line mode")
+	}
+	a.applyDeviceProfile("ublox")
+	if !a.GetLineMode().Enabled {
+		t.Error("ublox should enable line mode")
+	}
+	if !slices.Contains(a.GetEnabledDecoders(), "nmea") {
+		t.Errorf("ublox should enable the nmea decoder, got %v", a.GetEnabledDecoders())
+	}
+	a.applyDeviceProfile("uboot")
+	if lm := a.GetLineMode(); !lm.Enabled || lm.TimeoutMs != 200 {
+		t.Errorf("uboot should enable line mode with a prompt timeout, got %+v", lm)
+	}
+}
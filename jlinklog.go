@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/jlink"
+)
+
+// jlinkLogFile 驱动日志文件名 (位于应用数据目录)
+const jlinkLogFile = "jlink.log"
+
+// jlinkLogSize 诊断面板保留的驱动日志条数
+const jlinkLogSize = 2000
+
+// JLinkLogConfig 驱动日志设置
+type JLinkLogConfig struct {
+	Enabled bool   `json:"enabled"`
+	ToFile  bool   `json:"toFile"` // 同时写入驱动自己的日志文件
+	File    string `json:"file"`   // 日志文件路径 (只读)
+}
+
+// GetJLinkLogConfig 返回驱动日志设置
+func (a *App) GetJLinkLogConfig() JLinkLogConfig {
+	a.jlinkLogMutex.Lock()
+	defer a.jlinkLogMutex.Unlock()
+	return JLinkLogConfig{Enabled: a.jlinkLogEnabled, ToFile: a.jlinkLogToFile, File: filepath.Join(appDataDir(), jlinkLogFile)}
+}
+
+// SetJLinkLogConfig 启用或关闭 J-Link 驱动日志，用于排查 "J-Link Commander 正常而这里连不上" 的问题
+// 驱动输出的日志、警告与错误 (含 API 调用记录) 进入诊断面板；下次连接时生效
+func (a *App) SetJLinkLogConfig(cfg JLinkLogConfig) apperr.Result {
+	a.jlinkLogMutex.Lock()
+	defer a.jlinkLogMutex.Unlock()
+	a.jlinkLogEnabled = cfg.Enabled
+	a.jlinkLogToFile = cfg.Enabled && cfg.ToFile
+	if !cfg.Enabled {
+		jlink.DisableDLLLog()
+	}
+	return apperr.OK()
+}
+
+// GetJLinkLog 返回诊断面板中保留的驱动日志
+func (a *App) GetJLinkLog() ([]jlink.LogEntry, error) {
+	a.jlinkLogMutex.Lock()
+	defer a.jlinkLogMutex.Unlock()
+	return append([]jlink.LogEntry(nil), a.jlinkLog...), nil
+}
+
+// ClearJLinkLog 清空诊断面板中的驱动日志，并删除驱动日志文件
+func (a *App) ClearJLinkLog() apperr.Result {
+	a.jlinkLogMutex.Lock()
+	a.jlinkLog = nil
+	a.jlinkLogMutex.Unlock()
+	err := os.Remove(filepath.Join(appDataDir(), jlinkLogFile))
+	if err != nil && !os.IsNotExist(err) {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// applyDLLLog 按设置为新建的驱动实例启用日志
+func (a *App) applyDLLLog(jl *jlink.JLinkWrapper) {
+	a.jlinkLogMutex.Lock()
+	defer a.jlinkLogMutex.Unlock()
+	if !a.jlinkLogEnabled {
+		return
+	}
+	cfg := &jlink.DLLLog{Handler: a.recordDLLLog}
+	if a.jlinkLogToFile {
+		cfg.File = filepath.Join(appDataDir(), jlinkLogFile)
+	}
+	jl.SetDLLLog(cfg)
+}
+
+// recordDLLLog 保存一条驱动日志并通知前端 (在驱动的回调中调用)
+func (a *App) recordDLLLog(e jlink.LogEntry) {
+	a.jlinkLogMutex.Lock()
+	a.jlinkLog = append(a.jlinkLog, e)
+	if len(a.jlinkLog) > jlinkLogSize {
+		a.jlinkLog = append(a.jlinkLog[:0], a.jlinkLog[len(a.jlinkLog)-jlinkLogSize:]...)
+	}
+	a.jlinkLogMutex.Unlock()
+	a.bus.Publish(EventJLinkLog, e)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/jlink"
+)
+
+// GetJLinkTarget 返回主 RTT 连接的探针选择
+func (a *App) GetJLinkTarget() jlink.Target {
+	a.jlinkTargetMutex.Lock()
+	defer a.jlinkTargetMutex.Unlock()
+	return a.jlinkTarget
+}
+
+// SetJLinkTarget 设置主 RTT 连接使用的探针 (USB 序列号或远程服务器隧道)，下次连接时生效
+func (a *App) SetJLinkTarget(t jlink.Target) apperr.Result {
+	if err := t.Validate(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	a.jlinkTargetMutex.Lock()
+	a.jlinkTarget = t
+	a.jlinkTargetMutex.Unlock()
+	return apperr.OK()
+}
+
+// applyJLinkTarget 将探针选择交给主连接新建的驱动实例
+func (a *App) applyJLinkTarget(jl *jlink.JLinkWrapper) {
+	a.jlinkTargetMutex.Lock()
+	defer a.jlinkTargetMutex.Unlock()
+	jl.SetTarget(a.jlinkTarget)
+}
+
+// jlinkError 将驱动加载与连接的错误转换为结构化错误
+// 探针被其他程序占用时提示改用远程服务器隧道共用探针
+func jlinkError(err error) *apperr.Error {
+	switch {
+	case errors.Is(err, jlink.ErrProbeInUse):
+		e := apperr.Wrap(apperr.CodePortBusy, err)
+		e.Details = i18n.T("app.jlink_in_use", err)
+		return e
+	case errors.Is(err, jlink.ErrLibraryNotFound):
+		e := apperr.Wrap(apperr.CodeNotFound, err)
+		e.Details = i18n.T("app.jlink_not_installed", err)
+		return e
+	}
+	return apperr.Wrap(apperr.CodeProbeFailed, err)
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"path/filepath"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/journal"
+)
+
+// journalFile 会话状态日志文件名
+const journalFile = "session.json"
+
+// openJournal 开始记录会话状态，并保留上次异常退出时的状态供恢复
+func (a *App) openJournal() {
+	j, prev, err := journal.Open(filepath.Join(appDataDir(), journalFile))
+	if err != nil {
+		a.log("journal").Warn("session journal unavailable", "err", err)
+		return
+	}
+	a.journalMutex.Lock()
+	a.journal = j
+	a.recovered = prev
+	a.journalMutex.Unlock()
+}
+
+// closeJournal 正常退出时标记日志，下次启动不再提示恢复
+func (a *App) closeJournal() {
+	a.journalMutex.Lock()
+	j := a.journal
+	a.journalMutex.Unlock()
+	if j != nil {
+		j.Close()
+	}
+}
+
+// recordSession 更新会话状态日志，日志不可用时忽略
+func (a *App) recordSession(fn func(*journal.State)) error {
+	a.journalMutex.Lock()
+	j := a.journal
+	a.journalMutex.Unlock()
+	if j == nil {
+		return nil
+	}
+	return j.Update(fn)
+}
+
+// recordConnection 记录当前连接参数，conn 为 nil 表示已主动断开
+func (a *App) recordConnection(conn *journal.Connection) {
+	a.recordSession(func(s *journal.State) {
+		s.Connection = conn
+	})
+}
+
+// currentConnection 返回当前连接的参数，未连接时返回 nil
+func (a *App) currentConnection() *journal.Connection {
+	a.journalMutex.Lock()
+	j := a.journal
+	a.journalMutex.Unlock()
+	if j == nil {
+		return nil
+	}
+	return j.State().Connection
+}
+
+// SaveSessionState 由前端上报日志、自动发送配置及未发出的数据，崩溃后据此恢复
+func (a *App) SaveSessionState(session journal.Session) apperr.Result {
+	err := a.recordSession(func(s *journal.State) {
+		s.Session = session
+	})
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// GetRecoverableSession 返回上次异常退出时的会话状态，没有时返回 nil
+func (a *App) GetRecoverableSession() *journal.State {
+	a.journalMutex.Lock()
+	defer a.journalMutex.Unlock()
+	return a.recovered
+}
+
+// ResumeSession 按上次异常退出时的参数重新建立连接，并沿用其会话状态
+// 日志与自动发送由前端根据 GetRecoverableSession 的结果恢复
+func (a *App) ResumeSession() apperr.Result {
+	a.journalMutex.Lock()
+	prev := a.recovered
+	a.recovered = nil
+	a.journalMutex.Unlock()
+	if prev == nil {
+		return apperr.FromError(apperr.New(apperr.CodeNotFound, ""))
+	}
+
+	a.recordSession(func(s *journal.State) {
+		s.Session = prev.Session
+	})
+
+	if prev.Connection == nil {
+		return apperr.OK()
+	}
+	return a.openConnection(prev.Connection)
+}
+
+// openConnection 按记录的参数建立连接 (会话恢复与链接打开共用)
+func (a *App) openConnection(c *journal.Connection) apperr.Result {
+	switch ConnectionType(c.Type) {
+	case TypeSerial:
+		return a.OpenSerialWithOptions(SerialOptions{
+			Port: c.Port, BaudRate: c.BaudRate, DataBits: c.DataBits, StopBits: c.StopBits, Parity: c.Parity,
+			FlowControl: c.FlowControl, RS485: c.RS485,
+		})
+	case TypeTcpClient:
+		return a.OpenTcpClient(c.Host, c.Port)
+	case TypeTcpServer:
+		return a.OpenTcpServer(c.Port)
+	case TypeUdp:
+		return a.OpenUdp(c.LocalPort, c.Host, c.Port)
+	case TypeJLink:
+		return a.OpenJLink(c.Chip, c.Speed, c.Interface)
+	}
+	return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, c.Type))
+}
+
+// DiscardRecoverableSession 放弃恢复上次的会话
+func (a *App) DiscardRecoverableSession() {
+	a.journalMutex.Lock()
+	a.recovered = nil
+	a.journalMutex.Unlock()
+}
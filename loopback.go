@@ -0,0 +1,51 @@
+package main
+
+import (
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/loopback"
+)
+
+// loopbackPoll 等待回环数据时检查接收缓存的间隔
+const loopbackPoll = 10 * time.Millisecond
+
+// TestLoopback 发送测试图案并检查能否在超时内原样收回，报告错误、丢失与多余的字节，
+// 用于在怀疑固件之前先确认线缆与转换器 (须将 TX 与 RX 短接，或对端设备为回环模式)
+// 可通过 Cancel 取消；测试数据与普通收发一样显示与记录
+func (a *App) TestLoopback(cfg loopback.Config) (loopback.Report, error) {
+	if err := cfg.Validate(); err != nil {
+		return loopback.Report{}, apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	data := cfg.Data()
+
+	op := a.startOperation("loopback", 0)
+	defer a.ops.Finish(op)
+	ctx := op.Context()
+	mark := a.rxMark()
+	start := time.Now()
+	if err := a.writeContext(ctx, data); err != nil {
+		return loopback.Report{}, err
+	}
+
+	deadline := start.Add(cfg.Timeout())
+	ticker := time.NewTicker(loopbackPoll)
+	defer ticker.Stop()
+	for {
+		got := a.rxSince(mark)
+		now := time.Now()
+		if len(got) >= len(data) || !now.Before(deadline) {
+			r := loopback.Compare(data, got)
+			r.Finish(now.Sub(start), len(got) < len(data))
+			a.log("loopback").Info("loopback test finished", "passed", r.Passed, "sent", r.Sent,
+				"received", r.Received, "byteErrors", r.ByteErrors, "missing", r.Missing)
+			return r, nil
+		}
+		op.Report("loopback", int64(len(got)), int64(len(data)))
+		select {
+		case <-ctx.Done():
+			return loopback.Report{}, contextError(ctx)
+		case <-ticker.C:
+		}
+	}
+}
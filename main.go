@@ -2,10 +2,12 @@ package main
 
 import (
 	"embed"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
+	"github.com/wailsapp/wails/v2/pkg/options/mac"
 )
 
 // Version is the current application version
@@ -17,6 +19,10 @@ var assets embed.FS
 func main() {
 	// Create an instance of the app structure
 	app := NewApp()
+	// 由链接或抓包文件启动且已有实例在运行时，交给该实例处理
+	if app.forwardLaunchLink(os.Args[1:]) {
+		return
+	}
 
 	// Create application with options
 	err := wails.Run(&options.App{
@@ -27,7 +33,13 @@ func main() {
 			Assets: assets,
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
+		Logger:           app.logger(),
 		OnStartup:        app.startup,
+		OnShutdown:       app.shutdown,
+		Mac: &mac.Options{
+			OnUrlOpen:  app.openURL,
+			OnFileOpen: app.openFile,
+		},
 		Bind: []interface{}{
 			app,
 		},
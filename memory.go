@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/convert"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/jlink"
+)
+
+// MemoryWrite 一次经探针的内存写入，Old 与 New 为大写十六进制
+type MemoryWrite struct {
+	Seq     int       `json:"seq"`
+	Time    time.Time `json:"time"`
+	Address uint32    `json:"address"`
+	Old     string    `json:"old"` // 写入前的原始内容
+	New     string    `json:"new"`
+}
+
+// memoryError 将探针内存访问的错误转换为结构化错误
+func memoryError(err error) *apperr.Error {
+	if errors.Is(err, jlink.ErrMemoryRange) {
+		return apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	return apperr.Wrap(apperr.CodeProbeFailed, err)
+}
+
+// ReadMemory 经探针 (label 为空表示主 RTT 连接) 读取目标内存，返回大写十六进制
+func (a *App) ReadMemory(label string, address uint32, length int) (string, error) {
+	var data []byte
+	err := a.withProbe(label, func(jl *jlink.JLinkWrapper) error {
+		b, err := jl.ReadMemory(address, length)
+		if err != nil {
+			return memoryError(err)
+		}
+		data = b
+		return nil
+	})
+	return strings.ToUpper(hex.EncodeToString(data)), err
+}
+
+// WriteMemory 经探针写入目标内存或外设寄存器 (data 为十六进制)，写入前的内容记入探针本次连接的撤销记录，
+// 可用 RevertWrites 恢复；试运行模式下只记录将要写入的内容
+func (a *App) WriteMemory(label string, address uint32, data string) apperr.Result {
+	b, err := convert.ParseHex(data)
+	if err != nil || len(b) == 0 {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, data))
+	}
+	target := label
+	if target == "" {
+		target = mainSession
+	}
+	if a.skipDryRun("memory-write", target, fmt.Sprintf("0x%08X = %X", address, b)) {
+		return apperr.OK()
+	}
+	return apperr.FromError(a.withProbe(label, func(jl *jlink.JLinkWrapper) error {
+		if err := jl.WriteMemory(address, b); err != nil {
+			return memoryError(err)
+		}
+		a.log("memory").Info("memory written", "probe", target, "addr", fmt.Sprintf("0x%08X", address), "bytes", len(b))
+		return nil
+	}))
+}
+
+// GetMemoryWrites 返回探针本次连接以来尚未撤销的内存写入，从早到晚排列
+func (a *App) GetMemoryWrites(label string) ([]MemoryWrite, error) {
+	var list []MemoryWrite
+	err := a.withProbe(label, func(jl *jlink.JLinkWrapper) error {
+		for _, w := range jl.Writes() {
+			list = append(list, MemoryWrite{
+				Seq: w.Seq, Time: w.Time, Address: w.Addr,
+				Old: strings.ToUpper(hex.EncodeToString(w.Old)), New: strings.ToUpper(hex.EncodeToString(w.New)),
+			})
+		}
+		return nil
+	})
+	return list, err
+}
+
+// RevertWrites 从最近一次开始依次写回探针本次连接以来所有内存写入前的内容，用于在试验外设寄存器后恢复目标
+// 某次写回失败时停止，未恢复的记录保留以便重试
+func (a *App) RevertWrites(label string) apperr.Result {
+	target := label
+	if target == "" {
+		target = mainSession
+	}
+	if a.dryRun.Load() {
+		var n int
+		a.withProbe(label, func(jl *jlink.JLinkWrapper) error {
+			n = len(jl.Writes())
+			return nil
+		})
+		a.skipDryRun("memory-revert", target, fmt.Sprintf("%d writes", n))
+		return apperr.OK()
+	}
+	return apperr.FromError(a.withProbe(label, func(jl *jlink.JLinkWrapper) error {
+		n, err := jl.RevertWrites()
+		if n > 0 {
+			a.log("memory").Info("memory writes reverted", "count", n)
+			a.bus.Publish(EventSysMsg, i18n.T("app.writes_reverted", n))
+		}
+		if err != nil {
+			return memoryError(err)
+		}
+		return nil
+	}))
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/linecap"
+	"serial-assistant/pkg/series"
+
+	"go.bug.st/serial"
+)
+
+// defaultLinePoll 状态线的默认采样间隔
+const defaultLinePoll = time.Millisecond
+
+// modemPollInterval 串口连接期间检查状态线变化 ("serial-modem-status" 事件) 的间隔
+const modemPollInterval = 50 * time.Millisecond
+
+// ModemStatus "serial-modem-status" 事件的负载
+type ModemStatus struct {
+	Time    time.Time      `json:"time"`
+	Levels  linecap.Levels `json:"levels"`
+	Changed []string       `json:"changed"` // 变化的状态线；连接后的第一次通知为空
+}
+
+// LineCaptureStatus 状态线采样状态
+type LineCaptureStatus struct {
+	Running bool           `json:"running"`
+	Levels  linecap.Levels `json:"levels"`
+	Samples uint64         `json:"samples"`
+}
+
+// StartLineCapture 周期性采样当前串口的 CTS/DSR/DCD/RI，跳变记录为 "line.<名称>" 时间序列 (0/1 阶梯波形)
+// 用于没有逻辑分析仪时排查低速握手；时间精度受 intervalMs (0 为 1ms) 与系统调度限制
+func (a *App) StartLineCapture(intervalMs int) apperr.Result {
+	a.mutex.Lock()
+	port := a.serialPort
+	connected := a.isConnected && a.connType == TypeSerial && port != nil
+	a.mutex.Unlock()
+	if !connected {
+		return apperr.FromError(apperr.New(apperr.CodeNotConnected, ""))
+	}
+
+	a.lineMutex.Lock()
+	defer a.lineMutex.Unlock()
+	if a.lineStop != nil {
+		return apperr.OK()
+	}
+	interval := time.Duration(intervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = defaultLinePoll
+	}
+	a.lineRec.Reset()
+	a.lineStop = make(chan struct{})
+	go a.pollLines(port, interval, a.lineStop)
+	return apperr.OK()
+}
+
+// StopLineCapture 停止采样，已记录的跳变保留到下一次开始
+func (a *App) StopLineCapture() apperr.Result {
+	a.lineMutex.Lock()
+	defer a.lineMutex.Unlock()
+	if a.lineStop != nil {
+		close(a.lineStop)
+		a.lineStop = nil
+	}
+	return apperr.OK()
+}
+
+// GetLineEdges 返回晚于 sinceMs (Unix 毫秒，0 表示全部) 的状态线跳变
+func (a *App) GetLineEdges(sinceMs int64) []linecap.Edge {
+	var since time.Time
+	if sinceMs > 0 {
+		since = time.UnixMilli(sinceMs)
+	}
+	return a.lineRec.Edges(since)
+}
+
+// GetLineCaptureStatus 返回采样状态与各线当前电平
+func (a *App) GetLineCaptureStatus() (LineCaptureStatus, error) {
+	a.lineMutex.Lock()
+	running := a.lineStop != nil
+	a.lineMutex.Unlock()
+	levels, samples := a.lineRec.Status()
+	return LineCaptureStatus{Running: running, Levels: levels, Samples: samples}, nil
+}
+
+// pollLines 采样状态线直到停止或串口关闭
+func (a *App) pollLines(port serial.Port, interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		bits, err := port.GetModemStatusBits()
+		if err != nil {
+			// 串口已断开
+			a.lineMutex.Lock()
+			if a.lineStop == stop {
+				close(stop)
+				a.lineStop = nil
+			}
+			a.lineMutex.Unlock()
+			return
+		}
+		now := time.Now()
+		edges := a.lineRec.Sample(now, linecap.Levels{CTS: bits.CTS, DSR: bits.DSR, DCD: bits.DCD, RI: bits.RI})
+		for _, e := range edges {
+			for _, v := range e.Values() {
+				a.bus.Publish(EventSeriesSample, series.Sample{Series: "line." + e.Line, Time: e.Time, Value: v})
+			}
+		}
+	}
+}
+
+// GetModemStatus 读取当前串口的 CTS/DSR/RI/DCD 电平
+func (a *App) GetModemStatus() (linecap.Levels, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if !a.isConnected || a.connType != TypeSerial || a.serialPort == nil {
+		return linecap.Levels{}, apperr.New(apperr.CodeNotConnected, "")
+	}
+	bits, err := a.serialPort.GetModemStatusBits()
+	if err != nil {
+		return linecap.Levels{}, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return linecap.Levels{CTS: bits.CTS, DSR: bits.DSR, DCD: bits.DCD, RI: bits.RI}, nil
+}
+
+// startModemWatch 串口连接后开始检查状态线，电平变化时发布 "serial-modem-status" 事件
+func (a *App) startModemWatch(port serial.Port) {
+	a.modemMutex.Lock()
+	defer a.modemMutex.Unlock()
+	if a.modemStop != nil {
+		close(a.modemStop)
+	}
+	a.modemStop = make(chan struct{})
+	go a.watchModem(port, a.modemStop)
+}
+
+// stopModemWatch 停止检查状态线
+func (a *App) stopModemWatch() {
+	a.modemMutex.Lock()
+	defer a.modemMutex.Unlock()
+	if a.modemStop != nil {
+		close(a.modemStop)
+		a.modemStop = nil
+	}
+}
+
+// watchModem 轮询状态线直到停止或串口关闭，首次读取及之后每次变化都发布事件
+func (a *App) watchModem(port serial.Port, stop chan struct{}) {
+	ticker := time.NewTicker(modemPollInterval)
+	defer ticker.Stop()
+	var last linecap.Levels
+	first := true
+	for {
+		bits, err := port.GetModemStatusBits()
+		if err != nil {
+			return
+		}
+		cur := linecap.Levels{CTS: bits.CTS, DSR: bits.DSR, DCD: bits.DCD, RI: bits.RI}
+		if changed := linecap.Changed(last, cur); first || len(changed) > 0 {
+			if first {
+				changed = []string{}
+			}
+			a.bus.Publish(EventModemStatus, ModemStatus{Time: time.Now(), Levels: cur, Changed: changed})
+			first = false
+		}
+		last = cur
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/share"
+)
+
+// notebookTokenTTL 一次性令牌未使用时的有效期
+const notebookTokenTTL = 10 * time.Minute
+
+// pythonClientFile 导出的 Python 客户端文件名
+const pythonClientFile = "serial_assistant.py"
+
+// NotebookSession 供 Jupyter 连接的一次性访问信息
+type NotebookSession struct {
+	URL       string `json:"url"`       // WebSocket 地址
+	Token     string `json:"token"`     // 一次性令牌，只能建立一次连接
+	ClientURL string `json:"clientUrl"` // Python 客户端的下载地址
+	Snippet   string `json:"snippet"`   // 可直接粘贴到 notebook 中的代码
+}
+
+// StartNotebookSession 启动会话共享 (未运行时) 并生成一次性令牌，供 notebook 中的 Python 客户端拉取实时数据
+// control 为 true 时该连接直接拥有发送权限，否则只读；主机可随时用 RevokeControl 收回
+func (a *App) StartNotebookSession(port int, control bool) (NotebookSession, error) {
+	if _, err := a.StartSharing(port); err != nil {
+		return NotebookSession{}, err
+	}
+	a.shareMutex.Lock()
+	srv, actual := a.share, a.sharePort
+	a.shareMutex.Unlock()
+	if srv == nil {
+		return NotebookSession{}, apperr.New(apperr.CodeListenFailed, "sharing stopped")
+	}
+	token := srv.IssueOneTimeToken(share.Principal{Name: "notebook", Control: control, ReadOnly: !control}, notebookTokenTTL)
+	base := fmt.Sprintf("localhost:%d", actual)
+	s := NotebookSession{
+		URL:       "ws://" + base + "/ws",
+		Token:     token,
+		ClientURL: "http://" + base + "/" + pythonClientFile,
+	}
+	s.Snippet = fmt.Sprintf("import urllib.request\n"+
+		"urllib.request.urlretrieve(%q, %q)\n"+
+		"from serial_assistant import Client\n"+
+		"client = Client(%q, %q)\n", s.ClientURL, pythonClientFile, s.URL, s.Token)
+	return s, nil
+}
+
+// ExportPythonClient 将 Python 客户端写入 dir (为空时写入捕获目录)，返回文件路径
+func (a *App) ExportPythonClient(dir string) (string, error) {
+	if dir == "" {
+		dir = captureDir()
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", apperr.Wrap(apperr.CodeInternal, err)
+	}
+	path := filepath.Join(dir, pythonClientFile)
+	if err := os.WriteFile(path, share.PythonClient, 0o644); err != nil {
+		return "", apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/operation"
+)
+
+// 各类阻塞操作的默认超时
+const (
+	serialOpenTimeout   = 10 * time.Second // 蓝牙等虚拟串口在设备离线时可能长时间卡在打开阶段
+	tcpConnectTimeout   = 3 * time.Second
+	jlinkConnectTimeout = 30 * time.Second
+	sendTimeout         = 5 * time.Second
+)
+
+// startOperation 登记一个可通过 Cancel 取消的操作，并按 kind 计入本地使用统计
+func (a *App) startOperation(kind string, timeout time.Duration) *operation.Op {
+	a.usage.Feature(kind)
+	return a.ops.Start(context.Background(), kind, timeout)
+}
+
+// contextError 将 ctx 的结束原因转换为结构化错误
+func contextError(ctx context.Context) *apperr.Error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return apperr.Wrap(apperr.CodeTimeout, ctx.Err())
+	}
+	return apperr.Wrap(apperr.CodeCanceled, ctx.Err())
+}
+
+// GetOperations 返回进行中的可取消操作
+func (a *App) GetOperations() []operation.Info {
+	return a.ops.List()
+}
+
+// Cancel 取消指定的操作 (如卡在打开阶段的串口、长时间的下载)
+func (a *App) Cancel(operationID string) apperr.Result {
+	if !a.ops.Cancel(operationID) {
+		return apperr.FromError(apperr.New(apperr.CodeNotFound, operationID))
+	}
+	return apperr.OK()
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"serial-assistant/pkg/portable"
+)
+
+// appName 配置目录名
+const appName = "serial-mate"
+
+// portableDirName 便携模式下程序旁的数据目录名
+const portableDirName = appName + "-data"
+
+var (
+	dataDirOnce sync.Once
+	dataDir     string
+	portableOn  bool
+)
+
+// appDataDir 返回应用数据目录 (会话日志等)
+// 便携模式下为程序旁的数据目录，否则为用户配置目录，不可用时退回临时目录
+func appDataDir() string {
+	dataDirOnce.Do(func() {
+		if dir, ok := portableDataDir(); ok {
+			dataDir, portableOn = dir, true
+			return
+		}
+		dataDir = installedDataDir()
+	})
+	return dataDir
+}
+
+// installedDataDir 返回非便携模式下的数据目录
+func installedDataDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, appName)
+}
+
+// portableDataDir 返回程序旁的数据目录以及是否启用便携模式
+func portableDataDir() (string, bool) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", false
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return portable.Detect(exe, os.Args[1:], portableDirName)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/convert"
+	"serial-assistant/pkg/payload"
+	"serial-assistant/pkg/snippets"
+)
+
+// PayloadPreview 占位符展开后的发送内容
+type PayloadPreview struct {
+	Hex   string `json:"hex"`
+	Text  string `json:"text"` // 不可打印字符以 \xHH 转义
+	Bytes int    `json:"bytes"`
+}
+
+// SendPayload 展开 {{timestamp}}、{{counter}}、{{crc16(...)}}、{{random(a,b)}}、{{env.VAR}}、{{var.NAME}} 等占位符后发送
+// isHex 为 true 时占位符之外的内容按十六进制解析，并按默认设置计算 {len}、{sum} 等帧字段
+func (a *App) SendPayload(data string, isHex bool) apperr.Result {
+	return a.sendExpanded(data, isHex, payload.FrameSpec{})
+}
+
+// PreviewPayload 预览展开结果，不推进计数器
+func (a *App) PreviewPayload(data string, isHex bool) (PayloadPreview, error) {
+	return a.previewExpanded(data, isHex, payload.FrameSpec{})
+}
+
+// SendSnippet 按片段自己的帧字段设置展开并发送片段
+func (a *App) SendSnippet(id string) apperr.Result {
+	s, err := a.snippets.Get(id)
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeNotFound, err))
+	}
+	return a.sendExpanded(s.Payload, s.Hex, frameSpec(s))
+}
+
+// PreviewSnippet 预览片段展开后的内容，不推进计数器
+func (a *App) PreviewSnippet(id string) (PayloadPreview, error) {
+	s, err := a.snippets.Get(id)
+	if err != nil {
+		return PayloadPreview{}, apperr.Wrap(apperr.CodeNotFound, err)
+	}
+	return a.previewExpanded(s.Payload, s.Hex, frameSpec(s))
+}
+
+func frameSpec(s snippets.Snippet) payload.FrameSpec {
+	if s.Frame == nil {
+		return payload.FrameSpec{}
+	}
+	return *s.Frame
+}
+
+func (a *App) sendExpanded(data string, isHex bool, spec payload.FrameSpec) apperr.Result {
+	b, err := a.payloads.Expand(data, isHex, spec)
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	op := a.startOperation("send", sendTimeout)
+	defer a.ops.Finish(op)
+	return apperr.FromError(a.writeContext(op.Context(), b))
+}
+
+func (a *App) previewExpanded(data string, isHex bool, spec payload.FrameSpec) (PayloadPreview, error) {
+	b, err := a.payloads.Preview(data, isHex, spec)
+	if err != nil {
+		return PayloadPreview{}, apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	return PayloadPreview{
+		Hex:   strings.ToUpper(hex.EncodeToString(b)),
+		Text:  convert.ASCII(b),
+		Bytes: len(b),
+	}, nil
+}
+
+// ResetPayloadCounter 将 {{counter}} 归零
+func (a *App) ResetPayloadCounter() {
+	a.payloads.ResetCounter()
+}
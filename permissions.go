@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/permissions"
+)
+
+// udevInstallTimeout 安装 udev 规则的超时 (包含用户输入密码的时间)
+const udevInstallTimeout = 2 * time.Minute
+
+// DiagnosePortAccess 诊断串口的访问权限 (Linux/macOS)，给出所需的用户组及建议命令
+func (a *App) DiagnosePortAccess(portName string) permissions.Report {
+	r := permissions.Diagnose(portName)
+	if !r.Accessible {
+		if p := usbPortDetails(portName); p != nil {
+			r.UdevRule, _ = permissions.UdevRule(p.VID, p.PID, r.Group)
+		}
+	}
+	return r
+}
+
+// InstallUdevRule 为串口对应的 USB 设备安装 udev 规则 (仅 Linux，需要管理员认证)
+func (a *App) InstallUdevRule(portName string) apperr.Result {
+	p := usbPortDetails(portName)
+	if p == nil {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, portName))
+	}
+	group := permissions.Diagnose(portName).Group
+
+	op := a.startOperation("install-udev", udevInstallTimeout)
+	defer a.ops.Finish(op)
+	if err := permissions.InstallUdevRule(op.Context(), p.VID, p.PID, group); err != nil {
+		if op.Context().Err() != nil {
+			return apperr.FromError(contextError(op.Context()))
+		}
+		return apperr.FromError(apperr.Wrap(apperr.CodePermissionDenied, err))
+	}
+	return apperr.OK()
+}
+
+// publishPermissionHint 打开串口因权限不足失败时，在系统消息中给出建议命令
+func (a *App) publishPermissionHint(portName string) {
+	r := permissions.Diagnose(portName)
+	if len(r.Commands) == 0 {
+		return
+	}
+	a.bus.Publish(EventSysMsg, i18n.T("app.permission_hint", portName, strings.Join(r.Commands, "\n  ")))
+}
@@ -0,0 +1,161 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scope 密钥的权限范围
+type Scope string
+
+const (
+	ScopeRead    Scope = "read"    // 只能查看收发数据
+	ScopeControl Scope = "control" // 无需主机批准即可发送数据
+)
+
+// secretPrefix 密钥明文的前缀，便于在配置与日志中辨认
+const secretPrefix = "sm_"
+
+// Key 一个 API 密钥，磁盘上只保存明文的 SHA-256 摘要
+type Key struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Scope   Scope     `json:"scope"`
+	Hint    string    `json:"hint"` // 明文的前几位
+	Hash    string    `json:"hash"`
+	Created time.Time `json:"created"`
+}
+
+var (
+	ErrInvalidScope = errors.New("apikey: invalid scope")
+	ErrEmptyName    = errors.New("apikey: name is required")
+	ErrNotFound     = errors.New("apikey: no such key")
+)
+
+// Store 持久化到 JSON 文件的密钥集合
+type Store struct {
+	path string
+	mu   sync.RWMutex
+	keys []Key
+}
+
+// Open 加载 path 处的密钥，文件不存在时为空集合
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.keys); err != nil {
+		return nil, fmt.Errorf("apikey: %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// List 返回全部密钥 (不含明文)
+func (s *Store) List() []Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Key{}, s.keys...)
+}
+
+// Create 生成新密钥并保存，明文只在此时返回一次
+func (s *Store) Create(name string, scope Scope) (Key, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Key{}, "", ErrEmptyName
+	}
+	if scope != ScopeRead && scope != ScopeControl {
+		return Key{}, "", fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+	}
+	secret := secretPrefix + randomHex(24)
+	k := Key{
+		ID:      randomHex(4),
+		Name:    name,
+		Scope:   scope,
+		Hint:    secret[:len(secretPrefix)+4],
+		Hash:    digest(secret),
+		Created: time.Now(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := append(append([]Key{}, s.keys...), k)
+	if err := s.saveLocked(keys); err != nil {
+		return Key{}, "", err
+	}
+	s.keys = keys
+	return k, secret, nil
+}
+
+// Revoke 删除密钥，已用该密钥建立的连接需由调用方断开
+func (s *Store) Revoke(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, k := range s.keys {
+		if k.ID == id {
+			keys := append(append([]Key{}, s.keys[:i]...), s.keys[i+1:]...)
+			if err := s.saveLocked(keys); err != nil {
+				return err
+			}
+			s.keys = keys
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// Authenticate 查找与明文匹配的密钥
+func (s *Store) Authenticate(secret string) (Key, bool) {
+	if !strings.HasPrefix(secret, secretPrefix) {
+		return Key{}, false
+	}
+	h := digest(secret)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, k := range s.keys {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(k.Hash)) == 1 {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+func (s *Store) saveLocked(keys []Key) error {
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func digest(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,56 @@
+package apikey
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateAuthenticateRevoke(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	k, secret, err := s.Create("ci", ScopeControl)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if !strings.HasPrefix(secret, k.Hint) || strings.Contains(k.Hash, secret) {
+		t.Errorf("Unexpected key %+v for secret %q", k, secret)
+	}
+
+	// 重新加载后仍可认证
+	s, _ = Open(path)
+	got, ok := s.Authenticate(secret)
+	if !ok || got.ID != k.ID || got.Scope != ScopeControl {
+		t.Errorf("Expected key %s, got %+v ok=%v", k.ID, got, ok)
+	}
+	if _, ok := s.Authenticate(secret + "x"); ok {
+		t.Error("Wrong secret should not authenticate")
+	}
+
+	if err := s.Revoke(k.ID); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if _, ok := s.Authenticate(secret); ok {
+		t.Error("Revoked key should not authenticate")
+	}
+	if err := s.Revoke(k.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCreateValidates(t *testing.T) {
+	s, _ := Open(filepath.Join(t.TempDir(), "keys.json"))
+	if _, _, err := s.Create(" ", ScopeRead); !errors.Is(err, ErrEmptyName) {
+		t.Errorf("Expected ErrEmptyName, got %v", err)
+	}
+	if _, _, err := s.Create("x", "admin"); !errors.Is(err, ErrInvalidScope) {
+		t.Errorf("Expected ErrInvalidScope, got %v", err)
+	}
+	if len(s.List()) != 0 {
+		t.Error("Failed creates should not add keys")
+	}
+}
@@ -0,0 +1,159 @@
+package apperr
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"serial-assistant/pkg/i18n"
+)
+
+// Code 错误码，前端与 API 客户端据此进行程序化处理
+type Code string
+
+const (
+	CodeOK               Code = "OK"
+	CodeInternal         Code = "INTERNAL"
+	CodeInvalidArgument  Code = "INVALID_ARGUMENT"
+	CodeAlreadyConnected Code = "ALREADY_CONNECTED"
+	CodeNotConnected     Code = "NOT_CONNECTED"
+	CodePortNotFound     Code = "PORT_NOT_FOUND"
+	CodePortBusy         Code = "PORT_BUSY"
+	CodePermissionDenied Code = "PERMISSION_DENIED"
+	CodeOpenFailed       Code = "OPEN_FAILED"
+	CodeConnectFailed    Code = "CONNECT_FAILED"
+	CodeListenFailed     Code = "LISTEN_FAILED"
+	CodeProbeFailed      Code = "PROBE_FAILED"
+	CodeNoClient         Code = "NO_CLIENT"
+	CodeNoRemote         Code = "NO_REMOTE"
+	CodeWriteFailed      Code = "WRITE_FAILED"
+	CodeCloseFailed      Code = "CLOSE_FAILED"
+	CodeCanceled         Code = "CANCELED"
+	CodeTimeout          Code = "TIMEOUT"
+	CodeNotFound         Code = "NOT_FOUND"
+	CodeUnsupported      Code = "UNSUPPORTED"
+)
+
+// recoverable 错误码是否可恢复
+// 可恢复表示用户无需修改配置，稍后重试或等待外部条件变化即可能成功
+var recoverable = map[Code]bool{
+	CodeOK:               true,
+	CodeAlreadyConnected: true,
+	CodeNotConnected:     true,
+	CodePortNotFound:     true,
+	CodePortBusy:         true,
+	CodeConnectFailed:    true,
+	CodeProbeFailed:      true,
+	CodeNoClient:         true,
+	CodeNoRemote:         true,
+	CodeWriteFailed:      true,
+	CodeCanceled:         true,
+	CodeTimeout:          true,
+}
+
+// message 按当前语言返回错误码的提示，未知错误码按内部错误处理
+func message(code Code) string {
+	key := "error." + string(code)
+	if msg := i18n.T(key); msg != key {
+		return msg
+	}
+	return i18n.T("error." + string(CodeInternal))
+}
+
+// Error 结构化错误
+type Error struct {
+	Code        Code
+	Message     string
+	Details     string
+	Recoverable bool
+	cause       error
+}
+
+// New 创建指定错误码的错误，details 为附加说明 (可为空)
+// Message 按创建时的语言 (i18n) 生成
+func New(code Code, details string) *Error {
+	return &Error{
+		Code:        code,
+		Message:     message(code),
+		Details:     details,
+		Recoverable: recoverable[code],
+	}
+}
+
+// Wrap 以指定错误码包装底层错误，底层错误信息作为 Details
+func Wrap(code Code, err error) *Error {
+	if err == nil {
+		return nil
+	}
+	e := New(code, err.Error())
+	e.cause = err
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.Details == "" {
+		return e.Message
+	}
+	return e.Message + ": " + e.Details
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Result 绑定方法返回给前端的结构化结果
+type Result struct {
+	OK          bool   `json:"ok"`
+	Code        Code   `json:"code"`
+	Message     string `json:"message"`
+	Details     string `json:"details,omitempty"`
+	Recoverable bool   `json:"recoverable"`
+}
+
+// OK 返回成功结果
+func OK() Result {
+	return Result{OK: true, Code: CodeOK, Message: message(CodeOK), Recoverable: true}
+}
+
+// observer FromError 返回失败结果时的回调
+var observer atomic.Pointer[func(Code)]
+
+// Observe 设置 FromError 返回失败结果时的回调 (用于本地使用统计)，nil 取消
+func Observe(fn func(Code)) {
+	if fn == nil {
+		observer.Store(nil)
+		return
+	}
+	observer.Store(&fn)
+}
+
+// FromError 将错误转换为结果，nil 视为成功；非 *Error 的错误按内部错误处理
+func FromError(err error) Result {
+	if err == nil {
+		return OK()
+	}
+	var e *Error
+	if !errors.As(err, &e) {
+		e = Wrap(CodeInternal, err)
+	}
+	if fn := observer.Load(); fn != nil {
+		(*fn)(e.Code)
+	}
+	return Result{
+		Code:        e.Code,
+		Message:     e.Message,
+		Details:     e.Details,
+		Recoverable: e.Recoverable,
+	}
+}
+
+// CodeOf 返回错误的错误码，nil 返回 CodeOK
+func CodeOf(err error) Code {
+	if err == nil {
+		return CodeOK
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeInternal
+}
@@ -0,0 +1,78 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNewUsesCodeDefaults(t *testing.T) {
+	e := New(CodePortBusy, "COM3")
+	if e.Message == "" || !e.Recoverable {
+		t.Errorf("Unexpected defaults: %+v", e)
+	}
+	if e.Error() != e.Message+": COM3" {
+		t.Errorf("Unexpected error string: %q", e.Error())
+	}
+}
+
+func TestUnknownCodeFallsBackToInternalMessage(t *testing.T) {
+	e := New("SOMETHING_ELSE", "")
+	if e.Message != New(CodeInternal, "").Message {
+		t.Errorf("Expected internal message, got %q", e.Message)
+	}
+}
+
+func TestWrapKeepsCause(t *testing.T) {
+	cause := errors.New("dial tcp: refused")
+	e := Wrap(CodeConnectFailed, cause)
+	if !errors.Is(e, cause) {
+		t.Error("Wrapped error should unwrap to its cause")
+	}
+	if e.Details != cause.Error() {
+		t.Errorf("Expected details from cause, got %q", e.Details)
+	}
+	if Wrap(CodeConnectFailed, nil) != nil {
+		t.Error("Wrap(nil) should return nil")
+	}
+}
+
+func TestFromError(t *testing.T) {
+	if r := FromError(nil); !r.OK || r.Code != CodeOK {
+		t.Errorf("Expected OK result, got %+v", r)
+	}
+
+	wrapped := fmt.Errorf("open: %w", New(CodeNotConnected, ""))
+	if r := FromError(wrapped); r.OK || r.Code != CodeNotConnected {
+		t.Errorf("Expected NOT_CONNECTED result, got %+v", r)
+	}
+
+	if r := FromError(errors.New("boom")); r.Code != CodeInternal || r.Details != "boom" {
+		t.Errorf("Expected INTERNAL result, got %+v", r)
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	if CodeOf(nil) != CodeOK {
+		t.Error("CodeOf(nil) should be OK")
+	}
+	if CodeOf(New(CodeNoRemote, "")) != CodeNoRemote {
+		t.Error("CodeOf should return the error code")
+	}
+	if CodeOf(errors.New("x")) != CodeInternal {
+		t.Error("CodeOf should treat plain errors as internal")
+	}
+}
+
+func TestObserve(t *testing.T) {
+	var got []Code
+	Observe(func(c Code) { got = append(got, c) })
+	defer Observe(nil)
+
+	FromError(nil)
+	FromError(New(CodePortBusy, ""))
+	FromError(errors.New("x"))
+	if len(got) != 2 || got[0] != CodePortBusy || got[1] != CodeInternal {
+		t.Errorf("Unexpected observed codes %v", got)
+	}
+}
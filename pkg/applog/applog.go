@@ -0,0 +1,155 @@
+// Package applog 应用自身的结构化日志：基于 log/slog，按级别过滤并带模块标签，
+// 记录保存在内存环形缓冲区中，可在应用内查看并写入诊断转储
+package applog
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"serial-assistant/pkg/diagnostics"
+)
+
+// ComponentKey 模块标签的属性名
+const ComponentKey = "component"
+
+// Handler 将日志写入 diagnostics.Ring 的 slog.Handler，并可同时转发给另一个 Handler (如输出到终端)
+type Handler struct {
+	ring     *diagnostics.Ring
+	next     slog.Handler
+	level    slog.Leveler
+	onRecord func(diagnostics.Entry)
+
+	component string
+	attrs     []slog.Attr
+	group     string
+}
+
+// New 创建 Handler，低于 level 的日志被忽略；next 为 nil 时只写入缓冲区
+func New(ring *diagnostics.Ring, next slog.Handler, level slog.Leveler) *Handler {
+	return &Handler{ring: ring, next: next, level: level}
+}
+
+// OnRecord 设置每条日志写入缓冲区后的回调 (如推送给前端实时显示)
+func (h *Handler) OnRecord(fn func(diagnostics.Entry)) *Handler {
+	h.onRecord = fn
+	return h
+}
+
+// Enabled 实现 slog.Handler
+func (h *Handler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.level.Level()
+}
+
+// Handle 实现 slog.Handler
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	e := diagnostics.Entry{
+		Time: r.Time, Level: LevelName(r.Level), Component: h.component, Message: r.Message,
+	}
+	add := func(a slog.Attr) {
+		if a.Key == ComponentKey {
+			e.Component = a.Value.String()
+			return
+		}
+		if e.Attrs == nil {
+			e.Attrs = map[string]string{}
+		}
+		flatten(e.Attrs, h.group, a)
+	}
+	for _, a := range h.attrs {
+		add(a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		add(a)
+		return true
+	})
+	h.ring.AddEntry(e)
+	if h.onRecord != nil {
+		h.onRecord(e)
+	}
+	if h.next != nil && h.next.Enabled(ctx, r.Level) {
+		return h.next.Handle(ctx, r)
+	}
+	return nil
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = append(append([]slog.Attr(nil), h.attrs...), attrs...)
+	for _, a := range attrs {
+		if a.Key == ComponentKey && h.group == "" {
+			c.component = a.Value.String()
+		}
+	}
+	if h.next != nil {
+		c.next = h.next.WithAttrs(attrs)
+	}
+	return &c
+}
+
+// WithGroup 实现 slog.Handler
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	if h.group != "" {
+		name = h.group + "." + name
+	}
+	c.group = name
+	if h.next != nil {
+		c.next = h.next.WithGroup(name)
+	}
+	return &c
+}
+
+// flatten 将属性 (含分组) 展开为 "group.key" -> 值
+func flatten(m map[string]string, prefix string, a slog.Attr) {
+	key := a.Key
+	if prefix != "" {
+		key = prefix + "." + key
+	}
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		for _, g := range v.Group() {
+			flatten(m, key, g)
+		}
+		return
+	}
+	m[key] = v.String()
+}
+
+// LevelName 返回小写的级别名称：debug、info、warn、error
+func LevelName(l slog.Level) string {
+	return strings.ToLower(l.String())
+}
+
+// ParseLevel 解析级别名称 (debug、info、warn、error，不区分大小写)
+func ParseLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return l, fmt.Errorf("applog: unknown level %q", s)
+	}
+	return l, nil
+}
+
+// Filter 返回不低于 min 级别、属于 component (为空时不限) 的最近 limit 条日志 (limit <= 0 时不限)
+func Filter(entries []diagnostics.Entry, min slog.Level, component string, limit int) []diagnostics.Entry {
+	out := []diagnostics.Entry{}
+	for _, e := range entries {
+		l, err := ParseLevel(e.Level)
+		if err != nil {
+			l = slog.LevelInfo
+		}
+		if l < min || (component != "" && e.Component != component) {
+			continue
+		}
+		out = append(out, e)
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+package applog
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"serial-assistant/pkg/diagnostics"
+)
+
+func TestHandler(t *testing.T) {
+	ring := diagnostics.NewRing(10)
+	var out bytes.Buffer
+	var level slog.LevelVar
+	var live []diagnostics.Entry
+	h := New(ring, slog.NewTextHandler(&out, nil), &level).OnRecord(func(e diagnostics.Entry) { live = append(live, e) })
+	log := slog.New(h)
+
+	serial := log.With(ComponentKey, "serial", "port", "COM3")
+	serial.Debug("ignored")
+	serial.Warn("read failed", "err", errors.New("device removed"))
+	log.WithGroup("req").Info("done", "id", 7)
+
+	entries := ring.Entries()
+	if len(entries) != 2 || len(live) != 2 {
+		t.Fatalf("Expected 2 entries, got %+v", entries)
+	}
+	e := entries[0]
+	if e.Level != "warn" || e.Component != "serial" || e.Message != "read failed" ||
+		e.Attrs["port"] != "COM3" || e.Attrs["err"] != "device removed" {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+	if _, ok := e.Attrs[ComponentKey]; ok {
+		t.Error("Component should not be repeated in attrs")
+	}
+	if entries[1].Attrs["req.id"] != "7" {
+		t.Errorf("Expected grouped attribute, got %+v", entries[1].Attrs)
+	}
+	if !strings.Contains(out.String(), "component=serial") {
+		t.Errorf("Records should be forwarded, got %q", out.String())
+	}
+
+	level.Set(slog.LevelDebug)
+	serial.Debug("now visible")
+	if n := len(ring.Entries()); n != 3 {
+		t.Errorf("Lowering the level should enable debug, got %d entries", n)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for s, want := range map[string]slog.Level{"debug": slog.LevelDebug, "INFO": slog.LevelInfo, "warn": slog.LevelWarn, "error": slog.LevelError} {
+		if l, err := ParseLevel(s); err != nil || l != want {
+			t.Errorf("ParseLevel(%q) = %v, %v", s, l, err)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	entries := []diagnostics.Entry{
+		{Level: "debug", Component: "serial", Message: "1"},
+		{Level: "info", Component: "capture", Message: "2"},
+		{Level: "warn", Component: "serial", Message: "3"},
+		{Level: "error", Component: "serial", Message: "4"},
+	}
+	if got := Filter(entries, slog.LevelInfo, "serial", 0); len(got) != 2 || got[0].Message != "3" {
+		t.Errorf("Unexpected filter result %+v", got)
+	}
+	if got := Filter(entries, slog.LevelDebug, "", 2); len(got) != 2 || got[0].Message != "3" {
+		t.Errorf("Limit should keep the newest entries, got %+v", got)
+	}
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry 一条远程命令记录：谁在何时做了什么
+type Entry struct {
+	Time   time.Time `json:"time"`
+	Who    string    `json:"who"`             // 观看者名称或 API 密钥名称
+	Remote string    `json:"remote"`          // 对端地址
+	KeyID  string    `json:"keyId,omitempty"` // 为空表示使用会话令牌
+	Action string    `json:"action"`
+	Data   []byte    `json:"data,omitempty"` // 发送的数据，JSON 中为 base64
+	Error  string    `json:"error,omitempty"`
+}
+
+// Log 追加写入的审计日志，每行一条 JSON 记录
+type Log struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// Open 以追加方式打开 path 处的审计日志
+func Open(path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &Log{f: f}, nil
+}
+
+// Record 写入一条记录，Time 为零时取当前时间
+func (l *Log) Record(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.f.Write(append(line, '\n'))
+	return err
+}
+
+// Close 关闭日志文件
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Close()
+}
+
+// Read 读取 path 处最近的 limit 条记录 (limit <= 0 表示全部)，按时间从旧到新
+// 无法解析的行 (如写入时崩溃留下的半行) 被跳过；文件不存在时返回空列表
+func Read(path string, limit int) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	list := []Entry{}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var e Entry
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			continue
+		}
+		list = append(list, e)
+		if limit > 0 && len(list) > 2*limit {
+			list = append(list[:0], list[len(list)-limit:]...)
+		}
+	}
+	if limit > 0 && len(list) > limit {
+		list = list[len(list)-limit:]
+	}
+	return list, sc.Err()
+}
@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for _, action := range []string{"request-control", "send", "release-control"} {
+		if err := l.Record(Entry{Who: "alice", Remote: "10.0.0.2:5000", Action: action, Data: []byte("AT")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.Close()
+
+	// 追加到已有文件
+	l, _ = Open(path)
+	l.Record(Entry{Who: "ci", KeyID: "k1", Action: "send", Error: "not connected"})
+	l.Close()
+
+	all, err := Read(path, 0)
+	if err != nil || len(all) != 4 {
+		t.Fatalf("Expected 4 entries, got %d (%v)", len(all), err)
+	}
+	if all[0].Time.IsZero() || string(all[1].Data) != "AT" {
+		t.Errorf("Unexpected entry %+v", all[1])
+	}
+	last, _ := Read(path, 2)
+	if len(last) != 2 || last[1].KeyID != "k1" || last[0].Action != "release-control" {
+		t.Errorf("Expected the last two entries, got %+v", last)
+	}
+}
+
+func TestReadSkipsTruncatedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	os.WriteFile(path, []byte(`{"who":"a","action":"send"}`+"\n"+`{"who":"b","act`), 0o600)
+	list, err := Read(path, 0)
+	if err != nil || len(list) != 1 || list[0].Who != "a" {
+		t.Errorf("Expected one entry, got %+v (%v)", list, err)
+	}
+	if list, _ := Read(filepath.Join(t.TempDir(), "missing"), 10); len(list) != 0 {
+		t.Error("Missing file should read as empty")
+	}
+}
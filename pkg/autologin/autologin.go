@@ -0,0 +1,153 @@
+package autologin
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"serial-assistant/pkg/console"
+)
+
+// 默认的提示符：位于输出末尾的 "login:" 与 "Password:" (不区分大小写)
+const (
+	DefaultLoginPattern    = `(?i)login: ?$`
+	DefaultPasswordPattern = `(?i)password: ?$`
+)
+
+// DefaultMaxAttempts 每分钟最多自动发送用户名的次数，防止密码错误时反复登录
+const DefaultMaxAttempts = 3
+
+// passwordWindow 发送用户名后等待密码提示符的时间，超过后出现的密码提示符不再回应
+const passwordWindow = 30 * time.Second
+
+// tailSize 用于匹配提示符的接收尾部长度
+const tailSize = 256
+
+// Config 自动登录设置，密码不在此保存，由调用方从钥匙串读取
+type Config struct {
+	Enabled         bool   `json:"enabled"`
+	Username        string `json:"username"`
+	LoginPattern    string `json:"loginPattern"`    // 空为 DefaultLoginPattern
+	PasswordPattern string `json:"passwordPattern"` // 空为 DefaultPasswordPattern
+	LineEnding      string `json:"lineEnding"`      // 空为 "\n"
+	MaxAttempts     int    `json:"maxAttempts"`     // 每分钟最多登录次数，0 为 DefaultMaxAttempts
+}
+
+// Action 检测到提示符后应执行的动作
+type Action string
+
+const (
+	None         Action = ""
+	SendUsername Action = "username"
+	SendPassword Action = "password"
+	Suppressed   Action = "suppressed" // 检测到登录提示符但超过次数限制
+)
+
+// Watcher 在接收数据中检测登录与密码提示符
+// 只有在刚发送过用户名后出现的密码提示符才会回应，避免把密码发给任意询问密码的程序
+type Watcher struct {
+	mu       sync.Mutex
+	cfg      Config
+	login    *regexp.Regexp
+	password *regexp.Regexp
+	tail     []byte
+	sentAt   time.Time   // 最近一次发送用户名的时间
+	attempts []time.Time // 最近一分钟内发送用户名的时间
+}
+
+// New 创建检测器，默认关闭
+func New() *Watcher {
+	w := &Watcher{}
+	w.SetConfig(Config{})
+	return w
+}
+
+// SetConfig 修改设置，提示符正则无效时返回错误且不修改
+func (w *Watcher) SetConfig(cfg Config) error {
+	if cfg.LoginPattern == "" {
+		cfg.LoginPattern = DefaultLoginPattern
+	}
+	if cfg.PasswordPattern == "" {
+		cfg.PasswordPattern = DefaultPasswordPattern
+	}
+	if cfg.LineEnding == "" {
+		cfg.LineEnding = "\n"
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.Enabled && cfg.Username == "" {
+		return fmt.Errorf("username is required")
+	}
+	login, err := regexp.Compile(cfg.LoginPattern)
+	if err != nil {
+		return err
+	}
+	password, err := regexp.Compile(cfg.PasswordPattern)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.cfg, w.login, w.password = cfg, login, password
+	w.tail = w.tail[:0]
+	w.sentAt = time.Time{}
+	w.attempts = nil
+	return nil
+}
+
+// Config 返回当前设置
+func (w *Watcher) Config() Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.cfg
+}
+
+// Reset 清除接收尾部与登录状态 (新连接开始时调用)
+func (w *Watcher) Reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.tail = w.tail[:0]
+	w.sentAt = time.Time{}
+}
+
+// Feed 处理在 t 时刻收到的数据，返回应执行的动作
+func (w *Watcher) Feed(t time.Time, data []byte) Action {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.cfg.Enabled {
+		return None
+	}
+	w.tail = append(w.tail, data...)
+	if over := len(w.tail) - tailSize; over > 0 {
+		w.tail = append(w.tail[:0], w.tail[over:]...)
+	}
+	text := console.StripANSI(string(w.tail))
+
+	switch {
+	case w.password.MatchString(text):
+		w.tail = w.tail[:0]
+		if w.sentAt.IsZero() || t.Sub(w.sentAt) > passwordWindow {
+			return None
+		}
+		w.sentAt = time.Time{}
+		return SendPassword
+	case w.login.MatchString(text):
+		w.tail = w.tail[:0]
+		recent := w.attempts[:0]
+		for _, a := range w.attempts {
+			if t.Sub(a) < time.Minute {
+				recent = append(recent, a)
+			}
+		}
+		w.attempts = recent
+		if len(w.attempts) >= w.cfg.MaxAttempts {
+			return Suppressed
+		}
+		w.attempts = append(w.attempts, t)
+		w.sentAt = t
+		return SendUsername
+	}
+	return None
+}
@@ -0,0 +1,59 @@
+package autologin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginSequence(t *testing.T) {
+	w := New()
+	t0 := time.Unix(1000, 0)
+	if a := w.Feed(t0, []byte("buildroot login: ")); a != None {
+		t.Errorf("Disabled watcher returned %q", a)
+	}
+	if err := w.SetConfig(Config{Enabled: true}); err == nil {
+		t.Error("Enabling without a username should fail")
+	}
+	if err := w.SetConfig(Config{Enabled: true, Username: "root", LoginPattern: "("}); err == nil {
+		t.Error("Invalid pattern should fail")
+	}
+	if err := w.SetConfig(Config{Enabled: true, Username: "root"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 没有发送用户名时出现的密码提示符不回应
+	if a := w.Feed(t0, []byte("[sudo] password: ")); a != None {
+		t.Errorf("Unsolicited password prompt returned %q", a)
+	}
+	if a := w.Feed(t0, []byte("Welcome\r\n\x1b[1mbuildroot lo")); a != None {
+		t.Errorf("Partial prompt returned %q", a)
+	}
+	if a := w.Feed(t0, []byte("gin: \x1b[0m")); a != SendUsername {
+		t.Errorf("Login prompt returned %q", a)
+	}
+	if a := w.Feed(t0.Add(time.Second), []byte("root\r\nPassword: ")); a != SendPassword {
+		t.Errorf("Password prompt returned %q", a)
+	}
+	if a := w.Feed(t0.Add(2*time.Second), []byte("\r\nPassword: ")); a != None {
+		t.Errorf("Second password prompt returned %q", a)
+	}
+}
+
+func TestAttemptLimit(t *testing.T) {
+	w := New()
+	w.SetConfig(Config{Enabled: true, Username: "admin", MaxAttempts: 2})
+	t0 := time.Unix(1000, 0)
+	want := []Action{SendUsername, SendUsername, Suppressed}
+	for i, a := range want {
+		if got := w.Feed(t0.Add(time.Duration(i)*time.Second), []byte("Login incorrect\nlogin: ")); got != a {
+			t.Errorf("Attempt %d: got %q, want %q", i, got, a)
+		}
+	}
+	if got := w.Feed(t0.Add(2*time.Minute), []byte("login: ")); got != SendUsername {
+		t.Errorf("Attempts should be allowed again after a minute, got %q", got)
+	}
+	// 等待密码提示符超时
+	if got := w.Feed(t0.Add(3*time.Minute), []byte("Password: ")); got != None {
+		t.Errorf("Late password prompt returned %q", got)
+	}
+}
@@ -0,0 +1,299 @@
+package banner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 限制
+const (
+	defaultWindow = 5 * time.Second
+	maxLineLength = 512
+	maxValueLen   = 128
+)
+
+// 内置字段名
+const (
+	FieldDevice   = "device"
+	FieldFirmware = "firmware"
+	FieldSerial   = "serial"
+)
+
+// Rule 从开机信息中提取一个字段：有捕获组时取第一个捕获组，否则取整个匹配
+type Rule struct {
+	Field   string `json:"field"`
+	Pattern string `json:"pattern"`
+}
+
+// Config 开机信息提取配置
+type Config struct {
+	Enabled  bool   `json:"enabled"`
+	WindowMs int    `json:"windowMs,omitempty"` // 连接后参与提取的时长，0 表示默认 5 秒
+	Rules    []Rule `json:"rules"`
+	Title    string `json:"title,omitempty"` // 标题模板，{字段名} 替换为提取的值；为空时使用 "{device} {firmware}"
+}
+
+// DefaultConfig 返回默认配置，规则覆盖常见的 RTOS / bootloader 开机信息
+func DefaultConfig() Config {
+	return Config{
+		Enabled: true,
+		Rules: []Rule{
+			{Field: FieldFirmware, Pattern: `(?i)\b(?:firmware|fw|version|ver|build)\b\s*(?:version)?\s*[:=]?\s*(v?\d+\.\d+(?:\.\d+)*[\w.+-]*)`},
+			{Field: FieldDevice, Pattern: `(?i)\b(?:device|board|model|product|machine)\b\s*(?:name)?\s*[:=]\s*(\S.*)`},
+			{Field: FieldDevice, Pattern: `(?i)^\s*(?:welcome to|hello from)\s+(.+?)[\s!.]*$`},
+			{Field: "chip", Pattern: `(?i)\b(?:chip|cpu|soc)\b\s*[:=]\s*(\S.*)`},
+			{Field: FieldSerial, Pattern: `(?i)\b(?:serial(?:\s*number)?|s/n|sn)\b\s*[:=]\s*([\w-]+)`},
+		},
+	}
+}
+
+// Metadata 提取结果
+type Metadata struct {
+	Title  string            `json:"title,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Empty 没有提取到任何内容
+func (m Metadata) Empty() bool {
+	return m.Title == "" && len(m.Fields) == 0
+}
+
+type compiled struct {
+	field string
+	re    *regexp.Regexp
+}
+
+// Extractor 在连接建立后的一段时间内按行匹配规则，提取会话标题与设备信息
+type Extractor struct {
+	mu     sync.Mutex
+	cfg    Config
+	rules  []compiled
+	window time.Duration
+
+	start     time.Time
+	line      []byte
+	firstLine string
+	meta      Metadata
+}
+
+// New 创建使用默认配置的提取器
+func New() *Extractor {
+	e := &Extractor{}
+	if err := e.SetConfig(DefaultConfig()); err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// SetConfig 更新配置，规则无效时返回错误
+func (e *Extractor) SetConfig(cfg Config) error {
+	if cfg.WindowMs < 0 {
+		return fmt.Errorf("banner: invalid window %d ms", cfg.WindowMs)
+	}
+	rules := make([]compiled, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		if r.Field == "" {
+			return errors.New("banner: rule without field")
+		}
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return fmt.Errorf("banner: %s: %w", r.Field, err)
+		}
+		rules = append(rules, compiled{r.Field, re})
+	}
+	window := defaultWindow
+	if cfg.WindowMs > 0 {
+		window = time.Duration(cfg.WindowMs) * time.Millisecond
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cfg = cfg
+	e.rules = rules
+	e.window = window
+	return nil
+}
+
+// Config 返回当前配置
+func (e *Extractor) Config() Config {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.cfg
+}
+
+// Reset 在新连接建立时调用，从 now 开始新的提取窗口
+func (e *Extractor) Reset(now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.start = now
+	e.line = e.line[:0]
+	e.firstLine = ""
+	e.meta = Metadata{}
+}
+
+// Remaining 返回 now 时提取窗口的剩余时长，窗口已结束或尚未 Reset 时返回 0
+func (e *Extractor) Remaining(now time.Time) time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.start.IsZero() {
+		return 0
+	}
+	if d := e.window - now.Sub(e.start); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Metadata 返回目前提取到的结果
+func (e *Extractor) Metadata() Metadata {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.meta.clone()
+}
+
+// Feed 处理 t 时刻收到的数据，提取结果发生变化时返回新结果与 true
+// 未启用、尚未 Reset 或已超出提取窗口时忽略
+func (e *Extractor) Feed(t time.Time, data []byte) (Metadata, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.cfg.Enabled || e.start.IsZero() || t.Sub(e.start) > e.window {
+		return Metadata{}, false
+	}
+	changed := false
+	for _, c := range data {
+		switch {
+		case c == '\r' || c == '\n':
+			if e.lineLocked() {
+				changed = true
+			}
+		case c < 0x20 && c != '\t' && c != 0x1b, c == 0x7f:
+			// 丢弃控制字符，ESC 留到整行处理时与颜色等转义序列一起去掉
+		case len(e.line) < maxLineLength:
+			e.line = append(e.line, c)
+		}
+	}
+	if !changed {
+		return Metadata{}, false
+	}
+	return e.meta.clone(), true
+}
+
+// lineLocked 处理一整行，结果变化时返回 true
+func (e *Extractor) lineLocked() bool {
+	line := strings.TrimSpace(stripANSI(string(e.line)))
+	e.line = e.line[:0]
+	if line == "" {
+		return false
+	}
+	before := len(e.meta.Fields)
+	if e.firstLine == "" {
+		e.firstLine = truncate(line)
+	}
+	for _, r := range e.rules {
+		if _, ok := e.meta.Fields[r.field]; ok {
+			continue
+		}
+		m := r.re.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		v := m[0]
+		if len(m) > 1 {
+			v = m[1]
+		}
+		if v = truncate(strings.TrimSpace(v)); v == "" {
+			continue
+		}
+		if e.meta.Fields == nil {
+			e.meta.Fields = make(map[string]string)
+		}
+		e.meta.Fields[r.field] = v
+	}
+	title := e.titleLocked()
+	if title == e.meta.Title && len(e.meta.Fields) == before {
+		return false
+	}
+	e.meta.Title = title
+	return true
+}
+
+// titleLocked 按模板生成标题，模板中的字段都未提取到时使用第一行输出
+func (e *Extractor) titleLocked() string {
+	tmpl := e.cfg.Title
+	if tmpl == "" {
+		tmpl = "{" + FieldDevice + "} {" + FieldFirmware + "}"
+	}
+	found := false
+	title := placeholder.ReplaceAllStringFunc(tmpl, func(s string) string {
+		v, ok := e.meta.Fields[s[1:len(s)-1]]
+		found = found || ok
+		return v
+	})
+	if !found {
+		return e.firstLine
+	}
+	return strings.Join(strings.Fields(title), " ")
+}
+
+func (m Metadata) clone() Metadata {
+	c := Metadata{Title: m.Title}
+	if len(m.Fields) > 0 {
+		c.Fields = make(map[string]string, len(m.Fields))
+		for k, v := range m.Fields {
+			c.Fields[k] = v
+		}
+	}
+	return c
+}
+
+var (
+	placeholder = regexp.MustCompile(`\{[\w.-]+\}`)
+	ansi        = regexp.MustCompile(`\x1b(?:\[[0-9;?]*[A-Za-z])?`)
+)
+
+// stripANSI 去掉颜色等 ANSI 转义序列
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	return ansi.ReplaceAllString(s, "")
+}
+
+func truncate(s string) string {
+	if len(s) > maxValueLen {
+		return s[:maxValueLen]
+	}
+	return s
+}
+
+// Load 读取配置文件，文件不存在时返回默认配置
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultConfig(), nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+	var c Config
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+// Save 保存配置
+func Save(path string, c Config) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,96 @@
+package banner
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDefaultRules(t *testing.T) {
+	e := New()
+	start := time.Now()
+	e.Reset(start)
+
+	if _, changed := e.Feed(start, []byte("\x1b[32m*** Booting Zephyr OS ***\x1b[0m\r\n")); !changed {
+		t.Fatal("First line should set a fallback title")
+	}
+	if m := e.Metadata(); m.Title != "*** Booting Zephyr OS ***" {
+		t.Errorf("Unexpected fallback title %q", m.Title)
+	}
+
+	m, changed := e.Feed(start.Add(time.Second), []byte("Board: nRF52840-DK\r\nFirmware version: v1.4.2-rc1\r\nS/N: A1B2C3\r\n"))
+	if !changed {
+		t.Fatal("Expected metadata change")
+	}
+	if m.Fields[FieldDevice] != "nRF52840-DK" || m.Fields[FieldFirmware] != "v1.4.2-rc1" || m.Fields["serial"] != "A1B2C3" {
+		t.Errorf("Unexpected fields %v", m.Fields)
+	}
+	if m.Title != "nRF52840-DK v1.4.2-rc1" {
+		t.Errorf("Unexpected title %q", m.Title)
+	}
+
+	// 已提取的字段不被后续输出覆盖
+	if _, changed := e.Feed(start.Add(2*time.Second), []byte("version 9.9\n")); changed {
+		t.Error("Later matches should not replace extracted fields")
+	}
+}
+
+func TestWindow(t *testing.T) {
+	e := New()
+	if _, changed := e.Feed(time.Now(), []byte("version 1.0\n")); changed {
+		t.Error("Extractor should ignore data before Reset")
+	}
+	start := time.Now()
+	e.Reset(start)
+	if d := e.Remaining(start.Add(time.Second)); d != 4*time.Second {
+		t.Errorf("Unexpected remaining window %v", d)
+	}
+	if _, changed := e.Feed(start.Add(6*time.Second), []byte("version 1.0\n")); changed {
+		t.Error("Data after the window should be ignored")
+	}
+	if !e.Metadata().Empty() {
+		t.Errorf("Expected empty metadata, got %+v", e.Metadata())
+	}
+}
+
+func TestCustomConfig(t *testing.T) {
+	e := New()
+	if err := e.SetConfig(Config{Enabled: true, Rules: []Rule{{Field: "x", Pattern: "("}}}); err == nil {
+		t.Error("Invalid pattern should be rejected")
+	}
+	err := e.SetConfig(Config{
+		Enabled:  true,
+		WindowMs: 100,
+		Title:    "{app} build {build}",
+		Rules: []Rule{
+			{Field: "app", Pattern: `^APP=(\w+)`},
+			{Field: "build", Pattern: `^BUILD=(\d+)`},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	e.Reset(start)
+	// 跨多次到达的行
+	e.Feed(start, []byte("APP=gate"))
+	m, _ := e.Feed(start, []byte("way\nBUILD=42\n"))
+	if m.Title != "gateway build 42" {
+		t.Errorf("Unexpected title %q", m.Title)
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "banner.json")
+	c, err := Load(path)
+	if err != nil || !c.Enabled || len(c.Rules) == 0 {
+		t.Fatalf("Missing file should load defaults, got %+v %v", c, err)
+	}
+	c.WindowMs = 2000
+	if err := Save(path, c); err != nil {
+		t.Fatal(err)
+	}
+	if c, _ = Load(path); c.WindowMs != 2000 {
+		t.Errorf("Config not saved: %+v", c)
+	}
+}
@@ -0,0 +1,231 @@
+package bootloop
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 默认值与限制
+const (
+	DefaultThreshold = 3                      // 窗口内启动次数达到该值视为启动循环
+	DefaultWindow    = time.Minute            // 统计启动次数的时间窗口
+	bootDebounce     = 500 * time.Millisecond // 一次启动打印的多行启动信息只计一次
+	maxLineLength    = 1024
+)
+
+// DefaultPatterns 常见的启动信息：ESP 复位原因、Zephyr、U-Boot、Linux 内核及通用的复位原因输出
+func DefaultPatterns() []string {
+	return []string{
+		`rst:0x[0-9a-fA-F]+ \(`,
+		`^ets \w{3}\s+\d+ \d{4}`,
+		`\*\*\* Booting Zephyr OS`,
+		`^U-Boot (?:SPL )?\d{4}\.\d+`,
+		`^\[\s*0\.000000\] Linux version`,
+		`(?i)\b(?:reset|reboot) (?:cause|reason)\s*[:=]`,
+		`(?i)^\s*(?:bootloader|boot rom|rom boot) (?:v|version|start)`,
+	}
+}
+
+// Config 启动循环检测配置
+type Config struct {
+	Enabled      bool     `json:"enabled"`
+	Patterns     []string `json:"patterns"`            // 启动信息的正则表达式，命中任意一条计为一次启动
+	Threshold    int      `json:"threshold,omitempty"` // 0 表示 DefaultThreshold
+	WindowMs     int      `json:"windowMs,omitempty"`  // 0 表示 DefaultWindow
+	StopSequence bool     `json:"stopSequence"`        // 检测到时停止正在执行的定时发送序列
+	CrashReport  bool     `json:"crashReport"`         // 检测到时将最近的输出保存为崩溃报告
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	return Config{Enabled: true, Patterns: DefaultPatterns(), CrashReport: true}
+}
+
+// Alert 一次启动循环告警
+type Alert struct {
+	Time           time.Time `json:"time"`
+	Boots          int       `json:"boots"`          // 窗口内的启动次数
+	WindowMs       int64     `json:"windowMs"`       // 统计窗口
+	MeanIntervalMs int64     `json:"meanIntervalMs"` // 相邻两次启动的平均间隔
+	PerMinute      float64   `json:"perMinute"`      // 按平均间隔折算的每分钟重启次数
+	Line           string    `json:"line"`           // 最近一次启动信息
+}
+
+// Detector 统计启动信息出现的频率，短时间内反复启动时告警
+// 同一次启动循环在一个窗口内只告警一次
+type Detector struct {
+	mu        sync.Mutex
+	cfg       Config
+	patterns  []*regexp.Regexp
+	threshold int
+	window    time.Duration
+
+	line    []byte
+	boots   []time.Time
+	alerted time.Time
+	total   uint64 // 累计启动次数，Reset 不清零
+}
+
+// New 创建使用默认配置的检测器
+func New() *Detector {
+	d := &Detector{}
+	if err := d.SetConfig(DefaultConfig()); err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// SetConfig 更新配置，表达式无效时返回错误
+func (d *Detector) SetConfig(cfg Config) error {
+	if cfg.Threshold < 0 || cfg.Threshold == 1 || cfg.WindowMs < 0 {
+		return fmt.Errorf("bootloop: invalid threshold %d or window %d ms", cfg.Threshold, cfg.WindowMs)
+	}
+	patterns := make([]*regexp.Regexp, 0, len(cfg.Patterns))
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("bootloop: %w", err)
+		}
+		patterns = append(patterns, re)
+	}
+	threshold := DefaultThreshold
+	if cfg.Threshold > 0 {
+		threshold = cfg.Threshold
+	}
+	window := DefaultWindow
+	if cfg.WindowMs > 0 {
+		window = time.Duration(cfg.WindowMs) * time.Millisecond
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cfg = cfg
+	d.patterns = patterns
+	d.threshold = threshold
+	d.window = window
+	return nil
+}
+
+// Config 返回当前配置
+func (d *Detector) Config() Config {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.cfg
+}
+
+// Reset 清空启动记录，在新连接建立时调用
+func (d *Detector) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.line = d.line[:0]
+	d.boots = nil
+	d.alerted = time.Time{}
+}
+
+// Boots 返回检测器创建以来识别到的启动次数
+func (d *Detector) Boots() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.total
+}
+
+// Feed 处理 t 时刻收到的数据，检测到启动循环时返回告警
+func (d *Detector) Feed(t time.Time, data []byte) *Alert {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.cfg.Enabled {
+		return nil
+	}
+	var alert *Alert
+	d.line = append(d.line, data...)
+	for {
+		i := bytes.IndexByte(d.line, '\n')
+		if i < 0 {
+			if len(d.line) < maxLineLength {
+				break
+			}
+			i = len(d.line) - 1
+		}
+		line := strings.TrimRight(string(d.line[:i+1]), "\r\n")
+		d.line = d.line[:copy(d.line, d.line[i+1:])]
+		if a := d.lineLocked(t, line); a != nil {
+			alert = a
+		}
+	}
+	return alert
+}
+
+func (d *Detector) lineLocked(t time.Time, line string) *Alert {
+	matched := false
+	for _, re := range d.patterns {
+		if re.MatchString(line) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return nil
+	}
+	if n := len(d.boots); n > 0 && t.Sub(d.boots[n-1]) < bootDebounce {
+		return nil
+	}
+
+	d.boots = append(d.boots, t)
+	d.total++
+	i := 0
+	for i < len(d.boots) && t.Sub(d.boots[i]) > d.window {
+		i++
+	}
+	d.boots = d.boots[i:]
+	if len(d.boots) < d.threshold || (!d.alerted.IsZero() && t.Sub(d.alerted) < d.window) {
+		return nil
+	}
+	d.alerted = t
+
+	span := t.Sub(d.boots[0])
+	mean := span / time.Duration(len(d.boots)-1)
+	a := &Alert{
+		Time:           t,
+		Boots:          len(d.boots),
+		WindowMs:       d.window.Milliseconds(),
+		MeanIntervalMs: mean.Milliseconds(),
+		Line:           line,
+	}
+	if mean > 0 {
+		a.PerMinute = float64(time.Minute) / float64(mean)
+	}
+	return a
+}
+
+// Load 读取配置文件，文件不存在时返回默认配置
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultConfig(), nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+	var c Config
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+// Save 保存配置
+func Save(path string, c Config) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
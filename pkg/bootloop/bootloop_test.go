@@ -0,0 +1,74 @@
+package bootloop
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const espBoot = "ets Jun  8 2016 00:22:57\r\n\r\nrst:0xc (SW_CPU_RESET),boot:0x13 (SPI_FAST_FLASH_BOOT)\r\nconfigsip: 0\r\n"
+
+func TestBootLoop(t *testing.T) {
+	d := New()
+	t0 := time.Now()
+	var alert *Alert
+	for i := 0; i < 3; i++ {
+		alert = d.Feed(t0.Add(time.Duration(i)*5*time.Second), []byte(espBoot+"app running\r\n"))
+		if i < 2 && alert != nil {
+			t.Fatalf("Unexpected alert after %d boots: %+v", i+1, alert)
+		}
+	}
+	if alert == nil {
+		t.Fatal("Expected boot loop alert")
+	}
+	if alert.Boots != 3 || alert.MeanIntervalMs != 5000 || alert.PerMinute != 12 {
+		t.Errorf("Unexpected alert %+v", alert)
+	}
+
+	// 同一窗口内不重复告警
+	if a := d.Feed(t0.Add(15*time.Second), []byte(espBoot)); a != nil {
+		t.Errorf("Alert should not repeat within the window: %+v", a)
+	}
+	if a := d.Feed(t0.Add(70*time.Second), []byte(espBoot)); a == nil {
+		t.Error("Alert should repeat once the window has passed and the loop continues")
+	}
+	d.Reset()
+	if d.Boots() != 5 {
+		t.Errorf("Expected 5 boots in total, got %d", d.Boots())
+	}
+}
+
+func TestSlowRebootsAreNotALoop(t *testing.T) {
+	d := New()
+	t0 := time.Now()
+	for i := 0; i < 5; i++ {
+		if a := d.Feed(t0.Add(time.Duration(i)*45*time.Second), []byte("*** Booting Zephyr OS build v3.5.0 ***\n")); a != nil {
+			t.Fatalf("Reboots 45s apart should not alert: %+v", a)
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	d := New()
+	if err := d.SetConfig(Config{Threshold: 1}); err == nil {
+		t.Error("Threshold 1 should be rejected")
+	}
+	if err := d.SetConfig(Config{Patterns: []string{"("}}); err == nil {
+		t.Error("Invalid pattern should be rejected")
+	}
+	d.SetConfig(Config{Enabled: true, Patterns: []string{"^BOOT$"}, Threshold: 2, WindowMs: 1000})
+	t0 := time.Now()
+	d.Feed(t0, []byte("BOOT\n"))
+	if a := d.Feed(t0.Add(800*time.Millisecond), []byte("BOOT\n")); a == nil || a.Boots != 2 {
+		t.Errorf("Expected alert with custom config, got %+v", a)
+	}
+
+	path := filepath.Join(t.TempDir(), "bootloop.json")
+	if c, err := Load(path); err != nil || !c.Enabled || !c.CrashReport {
+		t.Errorf("Missing file should load defaults, got %+v %v", c, err)
+	}
+	Save(path, Config{Threshold: 4})
+	if c, _ := Load(path); c.Threshold != 4 {
+		t.Errorf("Config not saved: %+v", c)
+	}
+}
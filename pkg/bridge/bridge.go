@@ -0,0 +1,136 @@
+package bridge
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// queueSize 发往端口的数据块队列长度，端口写入跟不上时丢弃新数据而不阻塞 RTT 轮询
+const queueSize = 256
+
+// retryInterval 目标的下行缓冲区已满时重试写入的间隔
+const retryInterval = 5 * time.Millisecond
+
+// Stats 转发统计
+type Stats struct {
+	ToPort   uint64 `json:"toPort"`   // 从目标转发到端口的字节数
+	ToTarget uint64 `json:"toTarget"` // 从端口转发到目标的字节数
+	Dropped  uint64 `json:"dropped"`  // 端口写入跟不上而丢弃的字节数
+}
+
+// Bridge 在 RTT 通道与串口之间双向转发数据，只会说串口的工具可借此访问仅有 RTT 的目标
+type Bridge struct {
+	port     io.ReadWriteCloser
+	toTarget func([]byte) (int, error)
+	queue    chan []byte
+	done     chan struct{}
+	once     sync.Once
+	wg       sync.WaitGroup
+
+	toPort, toTargetN, dropped atomic.Uint64
+}
+
+// New 创建桥接并开始转发；toTarget 将端口收到的数据写入目标，可能只写入一部分
+func New(port io.ReadWriteCloser, toTarget func([]byte) (int, error)) *Bridge {
+	b := &Bridge{
+		port:     port,
+		toTarget: toTarget,
+		queue:    make(chan []byte, queueSize),
+		done:     make(chan struct{}),
+	}
+	b.wg.Add(2)
+	go b.writePort()
+	go b.readPort()
+	return b
+}
+
+// FromTarget 将目标输出的数据交给端口 (不阻塞调用方)，data 会被复制
+func (b *Bridge) FromTarget(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	select {
+	case <-b.done:
+		return
+	default:
+	}
+	select {
+	case b.queue <- append([]byte(nil), data...):
+	default:
+		b.dropped.Add(uint64(len(data)))
+	}
+}
+
+// writePort 依次将队列中的数据写入端口
+func (b *Bridge) writePort() {
+	defer b.wg.Done()
+	for {
+		select {
+		case <-b.done:
+			return
+		case data := <-b.queue:
+			n, err := b.port.Write(data)
+			b.toPort.Add(uint64(n))
+			if err != nil {
+				b.Close()
+				return
+			}
+		}
+	}
+}
+
+// readPort 读取端口数据并写入目标，目标缓冲区满时等待其读走后继续写入剩余部分
+func (b *Bridge) readPort() {
+	defer b.wg.Done()
+	buf := make([]byte, 4096)
+	for {
+		n, err := b.port.Read(buf)
+		for data := buf[:n]; len(data) > 0; {
+			w, werr := b.toTarget(data)
+			if werr != nil {
+				b.Close()
+				return
+			}
+			b.toTargetN.Add(uint64(w))
+			data = data[w:]
+			if len(data) > 0 {
+				select {
+				case <-b.done:
+					return
+				case <-time.After(retryInterval):
+				}
+			}
+		}
+		if err != nil {
+			b.Close()
+			return
+		}
+	}
+}
+
+// Done 桥接结束 (被关闭或端口出错) 时关闭
+func (b *Bridge) Done() <-chan struct{} {
+	return b.done
+}
+
+// Close 停止转发并关闭端口
+func (b *Bridge) Close() error {
+	var err error
+	b.once.Do(func() {
+		close(b.done)
+		err = b.port.Close()
+	})
+	return err
+}
+
+// Wait 等待转发协程退出
+func (b *Bridge) Wait() {
+	b.wg.Wait()
+}
+
+// Stats 返回转发统计
+func (b *Bridge) Stats() Stats {
+	return Stats{ToPort: b.toPort.Load(), ToTarget: b.toTargetN.Load(), Dropped: b.dropped.Load()}
+}
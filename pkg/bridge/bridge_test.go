@@ -0,0 +1,83 @@
+package bridge
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowTarget 每次只接受 2 字节，模拟下行缓冲区很小的目标
+type slowTarget struct {
+	mu  sync.Mutex
+	got bytes.Buffer
+}
+
+func (t *slowTarget) write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(p) > 2 {
+		p = p[:2]
+	}
+	return t.got.Write(p)
+}
+
+func (t *slowTarget) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.got.String()
+}
+
+func TestBridgeForwardsBothWays(t *testing.T) {
+	app, tool := net.Pipe()
+	target := &slowTarget{}
+	b := New(app, target.write)
+	defer b.Close()
+
+	b.FromTarget([]byte("boot ok\n"))
+	buf := make([]byte, 8)
+	tool.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := io.ReadFull(tool, buf); err != nil || string(buf) != "boot ok\n" {
+		t.Fatalf("Tool read %q, %v", buf, err)
+	}
+
+	tool.Write([]byte("help\r\n"))
+	deadline := time.Now().Add(time.Second)
+	for target.String() != "help\r\n" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if target.String() != "help\r\n" {
+		t.Errorf("Target received %q", target.String())
+	}
+	if s := b.Stats(); s.ToPort != 8 || s.ToTarget != 6 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+}
+
+func TestBridgeStopsOnTargetError(t *testing.T) {
+	app, tool := net.Pipe()
+	b := New(app, func([]byte) (int, error) { return 0, errors.New("probe gone") })
+	tool.Write([]byte("x"))
+	select {
+	case <-b.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Bridge should stop when the target write fails")
+	}
+	b.Wait()
+	b.FromTarget([]byte("ignored")) // 关闭后调用不应阻塞或出错
+}
+
+func TestBridgeDropsWhenPortStalls(t *testing.T) {
+	app, _ := net.Pipe() // 没有人读取，端口写入一直阻塞
+	b := New(app, func(p []byte) (int, error) { return len(p), nil })
+	defer b.Close()
+	for i := 0; i < queueSize+10; i++ {
+		b.FromTarget([]byte("x"))
+	}
+	if b.Stats().Dropped == 0 {
+		t.Error("Expected data to be dropped when the port stalls")
+	}
+}
@@ -0,0 +1,87 @@
+package bufpool
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Buffer 带引用计数的缓冲区
+// 在事件总线上共享时，每个订阅者持有一个引用，处理完后调用 Release；
+// 最后一个引用释放后缓冲区回到池中复用。B 在释放后不可再访问
+type Buffer struct {
+	B []byte
+
+	refs atomic.Int32
+	pool *Pool
+}
+
+// Wrap 用已有的切片构造缓冲区 (不属于任何池，Release 不回收)
+func Wrap(b []byte) *Buffer {
+	buf := &Buffer{B: b}
+	buf.refs.Store(1)
+	return buf
+}
+
+// Retain 增加一个引用
+func (b *Buffer) Retain() {
+	b.refs.Add(1)
+}
+
+// Release 释放一个引用，引用归零时放回池中
+func (b *Buffer) Release() {
+	n := b.refs.Add(-1)
+	if n > 0 {
+		return
+	}
+	if n < 0 {
+		panic("bufpool: release of free buffer")
+	}
+	if b.pool != nil {
+		b.pool.put(b)
+	}
+}
+
+// Stats 池的使用统计
+type Stats struct {
+	Gets   uint64 `json:"gets"`
+	Allocs uint64 `json:"allocs"` // 池中无可用缓冲区时新分配的次数
+}
+
+// Pool 固定大小缓冲区的对象池
+type Pool struct {
+	size   int
+	pool   sync.Pool
+	gets   atomic.Uint64
+	allocs atomic.Uint64
+}
+
+// New 创建缓冲区大小为 size 的池
+func New(size int) *Pool {
+	p := &Pool{size: size}
+	p.pool.New = func() interface{} {
+		p.allocs.Add(1)
+		return &Buffer{B: make([]byte, size), pool: p}
+	}
+	return p
+}
+
+// Get 取出一个长度为 size、引用计数为 1 的缓冲区
+func (p *Pool) Get() *Buffer {
+	p.gets.Add(1)
+	b := p.pool.Get().(*Buffer)
+	b.B = b.B[:p.size]
+	b.refs.Store(1)
+	return b
+}
+
+func (p *Pool) put(b *Buffer) {
+	if cap(b.B) < p.size {
+		return
+	}
+	p.pool.Put(b)
+}
+
+// Stats 返回统计信息
+func (p *Pool) Stats() Stats {
+	return Stats{Gets: p.gets.Load(), Allocs: p.allocs.Load()}
+}
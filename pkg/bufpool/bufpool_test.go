@@ -0,0 +1,97 @@
+package bufpool
+
+import (
+	"sync"
+	"testing"
+
+	"serial-assistant/pkg/eventbus"
+)
+
+func TestReuseAfterRelease(t *testing.T) {
+	p := New(64)
+	b := p.Get()
+	if len(b.B) != 64 {
+		t.Fatalf("Expected 64 byte buffer, got %d", len(b.B))
+	}
+	b.B = b.B[:10]
+	b.Retain()
+	b.Release()
+	b.Release()
+
+	// sync.Pool 不保证一定复用，这里只检查取回的缓冲区状态正确
+	b = p.Get()
+	if len(b.B) != 64 {
+		t.Errorf("Reused buffer should be reset to full size, got %d", len(b.B))
+	}
+	if s := p.Stats(); s.Gets != 2 || s.Allocs < 1 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+}
+
+func TestDoubleReleasePanics(t *testing.T) {
+	b := Wrap([]byte("x"))
+	b.Release()
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic on double release")
+		}
+	}()
+	b.Release()
+}
+
+// 以下基准模拟 3 Mbaud 串口的接收路径：读循环每次读取一块数据，
+// 通过事件总线交给两个订阅者 (界面与日志)。对比每块分配新切片与使用池化缓冲区的分配次数
+const (
+	benchChunk       = 4096
+	benchSubscribers = 2
+)
+
+func runPipeline(b *testing.B, publish func(bus *eventbus.Bus, src []byte), consume func(ev eventbus.Event, sink []byte)) {
+	bus := eventbus.New()
+	var wg sync.WaitGroup
+	for i := 0; i < benchSubscribers; i++ {
+		sub := bus.Subscribe(1024)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sink := make([]byte, benchChunk)
+			for ev := range sub.C {
+				consume(ev, sink)
+			}
+		}()
+	}
+
+	src := make([]byte, benchChunk)
+	b.SetBytes(benchChunk)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		publish(bus, src)
+	}
+	bus.Close()
+	wg.Wait()
+}
+
+func BenchmarkRxCopyPerChunk(b *testing.B) {
+	runPipeline(b, func(bus *eventbus.Bus, src []byte) {
+		data := make([]byte, len(src))
+		copy(data, src)
+		bus.Publish("serial-data", data)
+	}, func(ev eventbus.Event, sink []byte) {
+		copy(sink, ev.Payload.([]byte))
+	})
+}
+
+func BenchmarkRxPooled(b *testing.B) {
+	pool := New(benchChunk)
+	runPipeline(b, func(bus *eventbus.Bus, src []byte) {
+		buf := pool.Get()
+		copy(buf.B, src) // 模拟 Read 直接写入池化缓冲区
+		bus.Publish("serial-data", buf)
+		buf.Release()
+	}, func(ev eventbus.Event, sink []byte) {
+		buf := ev.Payload.(*Buffer)
+		copy(sink, buf.B)
+		buf.Release()
+	})
+}
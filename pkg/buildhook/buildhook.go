@@ -0,0 +1,351 @@
+// Package buildhook 供构建脚本调用的本机 HTTP 接口：编译完成后触发刷写固件或运行测试序列，例如
+//
+//	curl -f -X POST -H "Authorization: Bearer $TOKEN" "http://127.0.0.1:47321/flash?file=$PWD/build/app.bin"
+//	curl -f -X POST -H "Authorization: Bearer $TOKEN" "http://127.0.0.1:47321/run?snippet=local/selftest"
+//
+// 请求在动作完成后才返回，失败时状态码非 2xx，curl -f 可据此使构建失败
+//
+// 每个请求都须携带令牌；/flash 与 /run 只接受 POST，带 Origin 头 (来自浏览器) 或 Host 不是本机的请求
+// 一律拒绝，防止网页通过跨站请求或 DNS 重绑定触发动作
+package buildhook
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultPort 默认监听端口 (只监听 127.0.0.1)
+const DefaultPort = 47321
+
+// Config 构建钩子配置
+type Config struct {
+	Enabled      bool   `json:"enabled"`
+	Port         int    `json:"port"`                // 0 为 DefaultPort
+	Token        string `json:"token,omitempty"`     // 请求须携带 ?token= 或 "Authorization: Bearer" 头，为空时拒绝所有请求
+	ChunkSize    int    `json:"chunkSize,omitempty"` // 刷写时每次写入的字节数，0 为默认值
+	ChunkDelayMs int    `json:"chunkDelayMs,omitempty"`
+}
+
+// Validate 检查配置是否有效
+func (c Config) Validate() error {
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("buildhook: port %d out of range", c.Port)
+	}
+	if c.ChunkSize < 0 || c.ChunkDelayMs < 0 {
+		return errors.New("buildhook: negative chunk size or delay")
+	}
+	return nil
+}
+
+// Addr 返回监听地址
+func (c Config) Addr() string {
+	port := c.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+}
+
+// NewToken 生成随机访问令牌
+func NewToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// 动作名称，即请求路径
+const (
+	ActionFlash = "flash"
+	ActionRun   = "run"
+)
+
+// Result 一次请求的结果，以 JSON 返回给调用方
+type Result struct {
+	Action     string `json:"action"`
+	Target     string `json:"target"` // 固件文件或以逗号分隔的片段 ID
+	OK         bool   `json:"ok"`
+	Bytes      int64  `json:"bytes,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Actions 由应用实现的动作，ctx 在调用方断开时取消
+type Actions struct {
+	// Flash 将固件文件 (绝对路径) 发送到当前连接，返回发送的字节数
+	Flash func(ctx context.Context, path string) (int64, error)
+	// Run 依次发送片段组成的测试序列
+	Run func(ctx context.Context, snippets []string) error
+	// Status 返回 /status 的内容，可为 nil
+	Status func() any
+	// StatusCode 将动作返回的错误映射为 HTTP 状态码，为 nil 时一律返回 500
+	StatusCode func(err error) int
+	// Done 每个动作结束后调用 (用于日志与通知)，可为 nil
+	Done func(Result)
+	// Audit 每个请求 (包括被拒绝的请求) 处理完后调用，可为 nil
+	Audit func(Request)
+}
+
+// Request 一次请求的审计信息，不含令牌
+type Request struct {
+	Remote string `json:"remote"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Target string `json:"target,omitempty"` // file 或 snippet 参数
+	Status int    `json:"status"`
+}
+
+var (
+	// ErrBusy 已有动作在进行
+	ErrBusy = errors.New("buildhook: another action is in progress")
+	// ErrNoToken 未设置令牌时不开始监听
+	ErrNoToken = errors.New("buildhook: token required")
+)
+
+// Server 构建钩子 HTTP 服务器，同一时间只执行一个动作
+type Server struct {
+	token   string
+	actions Actions
+	busy    atomic.Bool
+	srv     *http.Server
+	ln      net.Listener
+}
+
+// New 创建服务器，不开始监听 (测试中可直接使用 Handler)
+func New(c Config, actions Actions) *Server {
+	s := &Server{token: c.Token, actions: actions}
+	s.srv = &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	return s
+}
+
+// Start 在 127.0.0.1 上开始监听，未设置令牌时返回 ErrNoToken
+func Start(c Config, actions Actions) (*Server, error) {
+	if c.Token == "" {
+		return nil, ErrNoToken
+	}
+	s := New(c, actions)
+	ln, err := net.Listen("tcp", c.Addr())
+	if err != nil {
+		return nil, err
+	}
+	s.ln = ln
+	go s.srv.Serve(ln)
+	return s, nil
+}
+
+// Addr 返回实际监听的地址
+func (s *Server) Addr() string {
+	if s.ln == nil {
+		return ""
+	}
+	return s.ln.Addr().String()
+}
+
+// Close 停止监听，进行中的动作随请求一起取消
+func (s *Server) Close() error {
+	return s.srv.Close()
+}
+
+// Handler 返回处理 /flash、/run、/status 的 http.Handler
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/flash", s.authorized(s.handleFlash, http.MethodPost))
+	mux.HandleFunc("/run", s.authorized(s.handleRun, http.MethodPost))
+	mux.HandleFunc("/status", s.authorized(func(w http.ResponseWriter, r *http.Request) {
+		var v any = struct{}{}
+		if s.actions.Status != nil {
+			v = s.actions.Status()
+		}
+		writeJSON(w, http.StatusOK, v)
+	}, http.MethodGet, http.MethodPost))
+	return s.audited(mux)
+}
+
+// audited 在每个请求处理完后调用 Actions.Audit
+func (s *Server) audited(h http.Handler) http.Handler {
+	if s.actions.Audit == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+		q := r.URL.Query()
+		target := q.Get("file")
+		if target == "" {
+			target = strings.Join(q["snippet"], ",")
+		}
+		s.actions.Audit(Request{Remote: r.RemoteAddr, Method: r.Method, Path: r.URL.Path, Target: target, Status: rec.status})
+	})
+}
+
+// statusRecorder 记下回复的状态码
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// authorized 校验请求来源、请求方法及令牌
+func (s *Server) authorized(h http.HandlerFunc, methods ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Origin") != "" || !loopbackHost(r.Host) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		allowed := false
+		for _, m := range methods {
+			allowed = allowed || r.Method == m
+		}
+		if !allowed {
+			w.Header().Set("Allow", strings.Join(methods, ", "))
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		got := r.URL.Query().Get("token")
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			got = bearer
+		}
+		if s.token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// loopbackHost Host 头是否指向本机 (127.0.0.1、::1 或 localhost)，用于拒绝 DNS 重绑定
+func loopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *Server) handleFlash(w http.ResponseWriter, r *http.Request) {
+	file := r.URL.Query().Get("file")
+	res := Result{Action: ActionFlash, Target: file}
+	switch {
+	case file == "" || !filepath.IsAbs(file):
+		s.reply(w, res, http.StatusBadRequest, errors.New("file must be an absolute path"))
+		return
+	case s.actions.Flash == nil:
+		s.reply(w, res, http.StatusNotImplemented, errors.New("flash not supported"))
+		return
+	}
+	if st, err := os.Stat(file); err != nil || !st.Mode().IsRegular() {
+		s.reply(w, res, http.StatusNotFound, fmt.Errorf("file not found: %s", file))
+		return
+	}
+	s.do(w, r, res, func(ctx context.Context) (int64, error) {
+		return s.actions.Flash(ctx, file)
+	})
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	var ids []string
+	for _, v := range r.URL.Query()["snippet"] {
+		for _, id := range strings.Split(v, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	res := Result{Action: ActionRun, Target: strings.Join(ids, ",")}
+	switch {
+	case len(ids) == 0:
+		s.reply(w, res, http.StatusBadRequest, errors.New("no snippet given"))
+		return
+	case s.actions.Run == nil:
+		s.reply(w, res, http.StatusNotImplemented, errors.New("run not supported"))
+		return
+	}
+	s.do(w, r, res, func(ctx context.Context) (int64, error) {
+		return 0, s.actions.Run(ctx, ids)
+	})
+}
+
+// do 执行动作并回复结果，已有动作在进行时返回 409
+func (s *Server) do(w http.ResponseWriter, r *http.Request, res Result, fn func(ctx context.Context) (int64, error)) {
+	if !s.busy.CompareAndSwap(false, true) {
+		s.reply(w, res, http.StatusConflict, ErrBusy)
+		return
+	}
+	defer s.busy.Store(false)
+
+	start := time.Now()
+	n, err := fn(r.Context())
+	res.Bytes = n
+	res.DurationMs = time.Since(start).Milliseconds()
+	status := http.StatusOK
+	if err != nil {
+		status = http.StatusInternalServerError
+		if s.actions.StatusCode != nil {
+			status = s.actions.StatusCode(err)
+		}
+	}
+	s.reply(w, res, status, err)
+	if s.actions.Done != nil {
+		s.actions.Done(res)
+	}
+}
+
+func (s *Server) reply(w http.ResponseWriter, res Result, status int, err error) {
+	res.OK = err == nil
+	if err != nil {
+		res.Error = err.Error()
+	}
+	writeJSON(w, status, res)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Load 读取配置，文件不存在时返回默认配置 (关闭)
+func Load(path string) (Config, error) {
+	var c Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+// Save 保存配置
+func Save(path string, c Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,211 @@
+package buildhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testToken = "s3cret"
+
+// post 带令牌向本机地址发送请求，header 为额外的名称/值对
+func post(t *testing.T, h http.Handler, target string, header ...string) (int, Result) {
+	t.Helper()
+	return request(t, h, http.MethodPost, target, append([]string{"Authorization", "Bearer " + testToken}, header...)...)
+}
+
+func request(t *testing.T, h http.Handler, method, target string, header ...string) (int, Result) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, nil)
+	req.Host = "127.0.0.1:47321"
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var res Result
+	json.Unmarshal(rec.Body.Bytes(), &res)
+	return rec.Code, res
+}
+
+func TestFlash(t *testing.T) {
+	fw := filepath.Join(t.TempDir(), "app.bin")
+	os.WriteFile(fw, []byte("firmware"), 0o644)
+	var flashed string
+	var done []Result
+	errOffline := errors.New("not connected")
+	offline := false
+	s := New(Config{Token: testToken}, Actions{
+		Flash: func(ctx context.Context, path string) (int64, error) {
+			if offline {
+				return 0, errOffline
+			}
+			flashed = path
+			return 8, nil
+		},
+		StatusCode: func(err error) int {
+			if errors.Is(err, errOffline) {
+				return http.StatusServiceUnavailable
+			}
+			return http.StatusInternalServerError
+		},
+		Done: func(r Result) { done = append(done, r) },
+	})
+	h := s.Handler()
+
+	code, res := post(t, h, "/flash?file="+url.QueryEscape(fw))
+	if code != http.StatusOK || !res.OK || res.Bytes != 8 || flashed != fw {
+		t.Fatalf("Unexpected result %d %+v", code, res)
+	}
+	offline = true
+	if code, res := post(t, h, "/flash?file="+url.QueryEscape(fw)); code != http.StatusServiceUnavailable || res.OK || res.Error == "" {
+		t.Errorf("Expected mapped error, got %d %+v", code, res)
+	}
+	if len(done) != 2 {
+		t.Errorf("Done should be called for each action, got %d", len(done))
+	}
+	if code, _ := post(t, h, "/flash?file=app.bin"); code != http.StatusBadRequest {
+		t.Errorf("Relative path should be rejected, got %d", code)
+	}
+	if code, _ := post(t, h, "/flash?file="+url.QueryEscape(fw+".missing")); code != http.StatusNotFound {
+		t.Errorf("Missing file should be 404, got %d", code)
+	}
+}
+
+func TestRun(t *testing.T) {
+	var ran []string
+	h := New(Config{Token: testToken}, Actions{Run: func(ctx context.Context, ids []string) error {
+		ran = ids
+		return nil
+	}}).Handler()
+	code, res := post(t, h, "/run?snippet=local/reset,local/selftest&snippet=local/report")
+	if code != http.StatusOK || len(ran) != 3 || ran[2] != "local/report" || res.Target != "local/reset,local/selftest,local/report" {
+		t.Errorf("Unexpected run %d %+v %v", code, res, ran)
+	}
+	if code, _ := post(t, h, "/run"); code != http.StatusBadRequest {
+		t.Errorf("Missing snippet should be 400, got %d", code)
+	}
+	if code, _ := post(t, h, "/flash?file=/x"); code != http.StatusNotImplemented {
+		t.Errorf("Unset action should be 501, got %d", code)
+	}
+}
+
+func TestBusy(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	h := New(Config{Token: testToken}, Actions{Run: func(ctx context.Context, ids []string) error {
+		close(started)
+		<-release
+		return nil
+	}}).Handler()
+	go post(t, h, "/run?snippet=a")
+	<-started
+	if code, _ := post(t, h, "/run?snippet=b"); code != http.StatusConflict {
+		t.Errorf("Concurrent action should be 409, got %d", code)
+	}
+	close(release)
+}
+
+func TestToken(t *testing.T) {
+	h := New(Config{Token: testToken}, Actions{Status: func() any { return "idle" }}).Handler()
+	if code, _ := request(t, h, http.MethodGet, "/status"); code != http.StatusUnauthorized {
+		t.Errorf("Missing token should be 401, got %d", code)
+	}
+	if code, _ := request(t, h, http.MethodGet, "/status?token="+testToken); code != http.StatusOK {
+		t.Errorf("Query token should be accepted, got %d", code)
+	}
+	if code, _ := request(t, h, http.MethodGet, "/status", "Authorization", "Bearer "+testToken); code != http.StatusOK {
+		t.Errorf("Bearer token should be accepted, got %d", code)
+	}
+	h = New(Config{}, Actions{Status: func() any { return "idle" }}).Handler()
+	if code, _ := request(t, h, http.MethodGet, "/status?token="); code != http.StatusUnauthorized {
+		t.Errorf("Empty server token should reject all requests, got %d", code)
+	}
+}
+
+func TestRequestChecks(t *testing.T) {
+	ran := false
+	var audited []Request
+	h := New(Config{Token: testToken}, Actions{
+		Run: func(ctx context.Context, ids []string) error {
+			ran = true
+			return nil
+		},
+		Audit: func(r Request) { audited = append(audited, r) },
+	}).Handler()
+	if code, _ := request(t, h, http.MethodGet, "/run?snippet=a&token="+testToken); code != http.StatusMethodNotAllowed {
+		t.Errorf("GET /run should be 405, got %d", code)
+	}
+	if code, _ := post(t, h, "/run?snippet=a", "Origin", "http://evil.example"); code != http.StatusForbidden {
+		t.Errorf("Browser origin should be 403, got %d", code)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/run?snippet=a", nil)
+	req.Host = "evil.example:47321"
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Rebound host should be 403, got %d", rec.Code)
+	}
+	if ran {
+		t.Error("Rejected requests must not run the action")
+	}
+	for _, host := range []string{"localhost:47321", "[::1]:47321", "127.0.0.1"} {
+		if !loopbackHost(host) {
+			t.Errorf("%s should be loopback", host)
+		}
+	}
+	if code, _ := post(t, h, "/run?snippet=a"); code != http.StatusOK || !ran {
+		t.Errorf("Valid request should run, got %d", code)
+	}
+	if len(audited) != 4 {
+		t.Fatalf("Every request should be audited, got %d", len(audited))
+	}
+	if a := audited[1]; a.Status != http.StatusForbidden || a.Path != "/run" || a.Target != "a" {
+		t.Errorf("Unexpected audit entry %+v", a)
+	}
+	if a := audited[3]; a.Status != http.StatusOK || a.Method != http.MethodPost {
+		t.Errorf("Unexpected audit entry %+v", a)
+	}
+}
+
+func TestStart(t *testing.T) {
+	if _, err := Start(Config{Port: 0}, Actions{}); !errors.Is(err, ErrNoToken) {
+		t.Errorf("Start without token should fail, got %v", err)
+	}
+	s, err := Start(Config{Port: 0, Token: testToken}, Actions{})
+	if err != nil {
+		t.Skip("default port unavailable:", err)
+	}
+	defer s.Close()
+	resp, err := http.Get("http://" + s.Addr() + "/status?token=" + testToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Status = %d", resp.StatusCode)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	if (Config{}).Addr() != "127.0.0.1:47321" {
+		t.Errorf("Addr = %s", Config{}.Addr())
+	}
+	if err := (Config{Port: 70000}).Validate(); err == nil {
+		t.Error("Expected port error")
+	}
+	path := filepath.Join(t.TempDir(), "hook.json")
+	want := Config{Enabled: true, Port: 5000, Token: "t"}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Load(path); err != nil || got != want {
+		t.Errorf("Load = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,543 @@
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"serial-assistant/pkg/cryptfile"
+)
+
+// 抓包文件格式：
+//
+//	magic "SMCAP1\n"
+//	uint32 元数据长度 + 元数据 (JSON)
+//	记录 * N: int64 时间 (UnixNano) | uint8 方向 | uint32 长度 | 数据
+//
+// 整数均为小端序。记录只追加，崩溃时最后一条记录可能不完整，读取时按文件结束处理
+// 元数据以空白填充到 metaReserve 字节，便于写入过程中原地更新 (如开机信息中提取的标题)
+const magic = "SMCAP1\n"
+
+// 加密抓包文件格式：
+//
+//	magic "SMCAPE\n" | 盐 (cryptfile.SaltSize) | uint32 密封元数据长度 + 密封元数据 | 加密流
+//
+// 元数据同样填充到 metaReserve 后用 cryptfile.SealBlock 密封，长度固定，可原地更新；
+// 记录部分与明文格式相同，经 cryptfile 分块加密。加密文件不能内存映射，视图会整体解密到内存
+const encMagic = "SMCAPE\n"
+
+// metaReserve 元数据预留的字节数
+const metaReserve = 4096
+
+// recordHeaderSize 每条记录头部的字节数
+const recordHeaderSize = 8 + 1 + 4
+
+// maxRecordSize 单条记录的最大长度，超出视为文件损坏
+const maxRecordSize = 16 << 20
+
+// Direction 数据方向
+type Direction uint8
+
+const (
+	RX Direction = 0
+	TX Direction = 1
+)
+
+func (d Direction) String() string {
+	if d == TX {
+		return "TX"
+	}
+	return "RX"
+}
+
+// Meta 抓包文件的元数据
+type Meta struct {
+	Started    time.Time         `json:"started"`
+	Connection string            `json:"connection"`       // 连接类型，如 SERIAL
+	Target     string            `json:"target"`           // 串口名、地址或芯片型号
+	Title      string            `json:"title,omitempty"`  // 会话标题
+	Device     map[string]string `json:"device,omitempty"` // 设备信息，如固件版本、设备名
+}
+
+// Record 一条收发记录
+type Record struct {
+	Time time.Time
+	Dir  Direction
+	Data []byte
+}
+
+// ErrCorrupt 文件头无效
+var ErrCorrupt = errors.New("capture: invalid file")
+
+// ErrMetaTooLarge 更新后的元数据超出预留空间
+var ErrMetaTooLarge = errors.New("capture: metadata too large")
+
+// ErrEncrypted 文件已加密但没有提供口令
+var ErrEncrypted = errors.New("capture: file is encrypted")
+
+// ErrWrongPassphrase 口令错误或加密文件被篡改
+var ErrWrongPassphrase = errors.New("capture: wrong passphrase")
+
+// Passphrase 按需提供加密文件的候选口令，只在遇到加密文件时调用，依次尝试直到解开元数据
+type Passphrase func() ([]string, error)
+
+// Writer 追加写入抓包文件，非并发安全
+type Writer struct {
+	f        *os.File
+	w        *bufio.Writer
+	enc      *cryptfile.Writer // 加密文件的记录流，明文文件为 nil
+	key      []byte
+	hdr      [recordHeaderSize]byte
+	metaOff  int64
+	metaSize int
+}
+
+// Create 创建抓包文件并写入元数据
+func Create(path string, meta Meta) (*Writer, error) {
+	return create(path, meta, "")
+}
+
+// CreateEncrypted 创建加密的抓包文件，密钥由 passphrase 和随机盐派生
+func CreateEncrypted(path string, meta Meta, passphrase string) (*Writer, error) {
+	if passphrase == "" {
+		return nil, ErrEncrypted
+	}
+	return create(path, meta, passphrase)
+}
+
+func create(path string, meta Meta, passphrase string) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	w := &Writer{f: f, w: bufio.NewWriterSize(f, 64*1024)}
+	if passphrase == "" {
+		err = w.writeHeader(meta)
+	} else {
+		err = w.writeEncryptedHeader(meta, passphrase)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(path)
+		return nil, err
+	}
+	return w, nil
+}
+
+// padMeta 序列化元数据并以空白填充到 size 字节 (size 为 0 时填充到 metaReserve)
+func padMeta(meta Meta, size int) ([]byte, error) {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		size = max(len(data), metaReserve)
+	}
+	if len(data) > size {
+		return nil, ErrMetaTooLarge
+	}
+	return append(data, bytes.Repeat([]byte{' '}, size-len(data))...), nil
+}
+
+func (w *Writer) writeHeader(meta Meta) error {
+	data, err := padMeta(meta, 0)
+	if err != nil {
+		return err
+	}
+	w.metaSize = len(data)
+	w.metaOff = int64(len(magic) + 4)
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(len(data)))
+	if _, err := w.w.WriteString(magic); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(n[:]); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(data); err != nil {
+		return err
+	}
+	return w.w.Flush()
+}
+
+func (w *Writer) writeEncryptedHeader(meta Meta, passphrase string) error {
+	salt, err := cryptfile.NewSalt()
+	if err != nil {
+		return err
+	}
+	if w.key, err = cryptfile.DeriveKey(passphrase, salt); err != nil {
+		return err
+	}
+	data, err := padMeta(meta, 0)
+	if err != nil {
+		return err
+	}
+	sealed, err := cryptfile.SealBlock(w.key, data)
+	if err != nil {
+		return err
+	}
+	w.metaSize = len(data)
+	w.metaOff = int64(len(encMagic) + len(salt) + 4)
+	var n [4]byte
+	binary.LittleEndian.PutUint32(n[:], uint32(len(sealed)))
+	for _, b := range [][]byte{[]byte(encMagic), salt, n[:], sealed} {
+		if _, err := w.f.Write(b); err != nil {
+			return err
+		}
+	}
+	if w.enc, err = cryptfile.NewWriter(w.f, w.key); err != nil {
+		return err
+	}
+	w.w.Reset(w.enc)
+	return nil
+}
+
+// UpdateMeta 原地替换元数据，超出创建时预留的空间时返回 ErrMetaTooLarge
+func (w *Writer) UpdateMeta(meta Meta) error {
+	data, err := padMeta(meta, w.metaSize)
+	if err != nil {
+		return err
+	}
+	if w.key != nil {
+		if data, err = cryptfile.SealBlock(w.key, data); err != nil {
+			return err
+		}
+	}
+	_, err = w.f.WriteAt(data, w.metaOff)
+	return err
+}
+
+// Write 追加一条记录
+func (w *Writer) Write(t time.Time, dir Direction, data []byte) error {
+	if len(data) > maxRecordSize {
+		return fmt.Errorf("capture: record too large (%d bytes)", len(data))
+	}
+	binary.LittleEndian.PutUint64(w.hdr[0:8], uint64(t.UnixNano()))
+	w.hdr[8] = byte(dir)
+	binary.LittleEndian.PutUint32(w.hdr[9:13], uint32(len(data)))
+	if _, err := w.w.Write(w.hdr[:]); err != nil {
+		return err
+	}
+	_, err := w.w.Write(data)
+	return err
+}
+
+// Flush 将缓冲的记录写入磁盘
+func (w *Writer) Flush() error {
+	if err := w.w.Flush(); err != nil || w.enc == nil {
+		return err
+	}
+	return w.enc.Flush()
+}
+
+// Close 写入剩余数据并关闭文件
+func (w *Writer) Close() error {
+	err := w.Flush()
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Reader 顺序读取抓包文件，内存占用与文件大小无关
+type Reader struct {
+	f         *os.File
+	r         *bufio.Reader
+	meta      Meta
+	size      int64
+	offset    int64
+	buf       []byte
+	encrypted bool
+	filter    Filter
+	last      time.Time // 最后一条记录的时间，用于过滤器结束时输出的剩余数据
+	flushed   int       // 结束时已取出剩余数据的方向数
+}
+
+// Filter 改写读取到的记录数据 (如导出时脱敏)，可跨记录缓冲；
+// Write 返回可以输出的数据 (可为空)，文件结束时按方向调用 Flush 取出剩余数据
+type Filter interface {
+	Write(dir Direction, data []byte) []byte
+	Flush(dir Direction) []byte
+}
+
+// Open 打开抓包文件并读取元数据，加密文件返回 ErrEncrypted
+func Open(path string) (*Reader, error) {
+	return OpenWith(path, nil)
+}
+
+// OpenWith 打开抓包文件，遇到加密文件时通过 pass 获取口令并透明解密
+func OpenWith(path string, pass Passphrase) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r := &Reader{f: f, r: bufio.NewReaderSize(f, 256*1024), size: st.Size()}
+	if err := r.readHeader(pass); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reader) readHeader(pass Passphrase) error {
+	head := make([]byte, len(magic)+4)
+	if _, err := io.ReadFull(r.r, head[:len(magic)]); err != nil {
+		return ErrCorrupt
+	}
+	switch string(head[:len(magic)]) {
+	case magic:
+	case encMagic:
+		return r.readEncryptedHeader(pass)
+	default:
+		return ErrCorrupt
+	}
+	if _, err := io.ReadFull(r.r, head[len(magic):]); err != nil {
+		return ErrCorrupt
+	}
+	n := binary.LittleEndian.Uint32(head[len(magic):])
+	if n > maxRecordSize {
+		return ErrCorrupt
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return ErrCorrupt
+	}
+	if err := json.Unmarshal(data, &r.meta); err != nil {
+		return ErrCorrupt
+	}
+	r.offset = int64(len(head)) + int64(n)
+	return nil
+}
+
+func (r *Reader) readEncryptedHeader(pass Passphrase) error {
+	r.encrypted = true
+	head := make([]byte, cryptfile.SaltSize+4)
+	if _, err := io.ReadFull(r.r, head); err != nil {
+		return ErrCorrupt
+	}
+	salt := head[:cryptfile.SaltSize]
+	n := binary.LittleEndian.Uint32(head[cryptfile.SaltSize:])
+	if n > maxRecordSize {
+		return ErrCorrupt
+	}
+	sealed := make([]byte, n)
+	if _, err := io.ReadFull(r.r, sealed); err != nil {
+		return ErrCorrupt
+	}
+	if pass == nil {
+		return ErrEncrypted
+	}
+	candidates, err := pass()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrEncrypted, err)
+	}
+	if len(candidates) == 0 {
+		return ErrEncrypted
+	}
+	var key, data []byte
+	for _, passphrase := range candidates {
+		if key, err = cryptfile.DeriveKey(passphrase, salt); err != nil {
+			return err
+		}
+		if data, err = cryptfile.OpenBlock(key, sealed); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return ErrWrongPassphrase
+	}
+	if err := json.Unmarshal(data, &r.meta); err != nil {
+		return ErrCorrupt
+	}
+	dec, err := cryptfile.NewReader(r.r, key)
+	if err != nil {
+		return ErrCorrupt
+	}
+	r.r = bufio.NewReaderSize(dec, 256*1024)
+	r.offset = int64(len(encMagic)+len(head)) + int64(n)
+	return nil
+}
+
+// Encrypted 文件是否加密
+func (r *Reader) Encrypted() bool {
+	return r.encrypted
+}
+
+// Meta 返回元数据
+func (r *Reader) Meta() Meta {
+	return r.meta
+}
+
+// Size 返回打开时的文件大小
+func (r *Reader) Size() int64 {
+	return r.size
+}
+
+// Offset 返回已读取的字节数 (用于进度，加密文件按明文计算，略小于文件大小)
+func (r *Reader) Offset() int64 {
+	return r.offset
+}
+
+// SetFilter 设置记录过滤器，之后 Next 返回过滤后的数据并跳过过滤后为空的记录
+func (r *Reader) SetFilter(f Filter) {
+	r.filter = f
+}
+
+// Next 读取下一条记录，文件结束 (包括末尾不完整的记录) 时返回 io.EOF
+// 返回的 Data 在下一次调用 Next 前有效
+func (r *Reader) Next() (Record, error) {
+	if r.filter == nil {
+		return r.next()
+	}
+	for {
+		rec, err := r.next()
+		if err == io.EOF {
+			for r.flushed < 2 {
+				dir := Direction(r.flushed)
+				r.flushed++
+				if data := r.filter.Flush(dir); len(data) > 0 {
+					return Record{Time: r.last, Dir: dir, Data: data}, nil
+				}
+			}
+		}
+		if err != nil {
+			return rec, err
+		}
+		r.last = rec.Time
+		if rec.Data = r.filter.Write(rec.Dir, rec.Data); len(rec.Data) > 0 {
+			return rec, nil
+		}
+	}
+}
+
+// next 读取文件中的下一条记录
+func (r *Reader) next() (Record, error) {
+	var hdr [recordHeaderSize]byte
+	if _, err := io.ReadFull(r.r, hdr[:]); err != nil {
+		return Record{}, eof(err)
+	}
+	n := binary.LittleEndian.Uint32(hdr[9:13])
+	if n > maxRecordSize {
+		return Record{}, ErrCorrupt
+	}
+	if cap(r.buf) < int(n) {
+		r.buf = make([]byte, n)
+	}
+	data := r.buf[:n]
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return Record{}, eof(err)
+	}
+	r.offset += recordHeaderSize + int64(n)
+	return Record{
+		Time: time.Unix(0, int64(binary.LittleEndian.Uint64(hdr[0:8]))),
+		Dir:  Direction(hdr[8]),
+		Data: data,
+	}, nil
+}
+
+// eof 将截断的记录视为文件结束，认证失败的加密块视为口令错误
+func eof(err error) error {
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return io.EOF
+	case errors.Is(err, cryptfile.ErrWrongKey):
+		return ErrWrongPassphrase
+	}
+	return err
+}
+
+// Close 关闭文件
+func (r *Reader) Close() error {
+	return r.f.Close()
+}
+
+// Ext 抓包文件扩展名
+const Ext = ".smcap"
+
+// Info 目录中一个抓包文件的描述
+type Info struct {
+	ID         string `json:"id"` // 文件名 (不含扩展名)
+	Path       string `json:"path"`
+	Size       int64  `json:"size"`
+	Meta       Meta   `json:"meta"`
+	Active     bool   `json:"active"`     // 正在写入
+	Bookmarked bool   `json:"bookmarked"` // 已加书签，不会被自动清理
+	Encrypted  bool   `json:"encrypted"`  // 已加密
+	Locked     bool   `json:"locked"`     // 已加密且无法解密，Meta 只有按修改时间填写的开始时间
+}
+
+// List 列出目录中的抓包文件，按开始时间从新到旧排序；目录不存在时返回空列表
+func List(dir string) ([]Info, error) {
+	return ListWith(dir, nil)
+}
+
+// ListWith 同 List，加密文件通过 pass 获取口令读取元数据
+func ListWith(dir string, pass Passphrase) ([]Info, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return []Info{}, nil
+		}
+		return nil, err
+	}
+	if pass != nil {
+		pass = once(pass)
+	}
+	list := []Info{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || filepath.Ext(name) != Ext {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		id := strings.TrimSuffix(name, Ext)
+		r, err := OpenWith(path, pass)
+		if err != nil {
+			if st, serr := e.Info(); serr == nil && isLocked(err) {
+				list = append(list, Info{ID: id, Path: path, Size: st.Size(), Meta: Meta{Started: st.ModTime()}, Encrypted: true, Locked: true})
+			}
+			continue
+		}
+		list = append(list, Info{ID: id, Path: path, Size: r.Size(), Meta: r.Meta(), Encrypted: r.Encrypted()})
+		r.Close()
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Meta.Started.After(list[j].Meta.Started)
+	})
+	return list, nil
+}
+
+// once 只调用一次 pass，避免逐个文件查询钥匙串
+func once(pass Passphrase) Passphrase {
+	var (
+		done bool
+		list []string
+		err  error
+	)
+	return func() ([]string, error) {
+		if !done {
+			list, err = pass()
+			done = true
+		}
+		return list, err
+	}
+}
+
+// isLocked 打开失败是否因为无法解密 (没有口令、取口令失败或口令错误)
+func isLocked(err error) bool {
+	return errors.Is(err, ErrEncrypted) || errors.Is(err, ErrWrongPassphrase)
+}
@@ -0,0 +1,362 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeSample(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "sample.smcap")
+	w, err := Create(path, Meta{Connection: "SERIAL", Target: "COM3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	w.Write(base, TX, []byte("AT\r\n"))
+	w.Write(base.Add(time.Millisecond), RX, []byte("OK\r\n"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRoundTrip(t *testing.T) {
+	r, err := Open(writeSample(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if r.Meta().Target != "COM3" {
+		t.Errorf("Unexpected meta %+v", r.Meta())
+	}
+
+	rec, err := r.Next()
+	if err != nil || rec.Dir != TX || string(rec.Data) != "AT\r\n" {
+		t.Fatalf("Unexpected first record %+v, %v", rec, err)
+	}
+	rec, err = r.Next()
+	if err != nil || rec.Dir != RX || string(rec.Data) != "OK\r\n" {
+		t.Fatalf("Unexpected second record %+v, %v", rec, err)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Expected EOF, got %v", err)
+	}
+	if r.Offset() != r.Size() {
+		t.Errorf("Offset %d should reach size %d", r.Offset(), r.Size())
+	}
+}
+
+func TestUpdateMeta(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meta.smcap")
+	w, err := Create(path, Meta{Connection: "SERIAL", Target: "COM3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(time.Now(), RX, []byte("Firmware v1.2\r\n"))
+	if err := w.UpdateMeta(Meta{Connection: "SERIAL", Target: "COM3", Title: "Gateway v1.2", Device: map[string]string{"firmware": "v1.2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.UpdateMeta(Meta{Title: strings.Repeat("x", metaReserve)}); err != ErrMetaTooLarge {
+		t.Errorf("Expected ErrMetaTooLarge, got %v", err)
+	}
+	w.Close()
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if m := r.Meta(); m.Title != "Gateway v1.2" || m.Device["firmware"] != "v1.2" || m.Target != "COM3" {
+		t.Errorf("Unexpected meta %+v", m)
+	}
+	if rec, err := r.Next(); err != nil || string(rec.Data) != "Firmware v1.2\r\n" {
+		t.Errorf("Records should be intact, got %+v, %v", rec, err)
+	}
+}
+
+func TestManifest(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.csv")
+	m := Manifest{Capture: "x", File: "out.csv", Format: FormatCSV, Meta: Meta{Title: "Gateway"}}
+	if err := WriteManifest(out, m); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(ManifestPath(out))
+	if err != nil || !strings.Contains(string(data), `"title": "Gateway"`) {
+		t.Errorf("Unexpected manifest %s, %v", data, err)
+	}
+}
+
+func TestTruncatedRecordIsEOF(t *testing.T) {
+	path := writeSample(t)
+	st, _ := os.Stat(path)
+	os.Truncate(path, st.Size()-2)
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	r.Next()
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Expected EOF for a truncated record, got %v", err)
+	}
+}
+
+func TestOpenRejectsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "x")
+	os.WriteFile(path, []byte("hello world"), 0o644)
+	if _, err := Open(path); err != ErrCorrupt {
+		t.Errorf("Expected ErrCorrupt, got %v", err)
+	}
+}
+
+func export(t *testing.T, format Format) []byte {
+	t.Helper()
+	r, err := Open(writeSample(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var out bytes.Buffer
+	var last int64
+	err = Export(context.Background(), r, format, &out, func(done, total int64) {
+		last = done
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last != r.Size() {
+		t.Errorf("Progress should end at %d, got %d", r.Size(), last)
+	}
+	return out.Bytes()
+}
+
+func TestExportCSV(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(string(export(t, FormatCSV))), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %q", lines)
+	}
+	if lines[1] != `2024-01-02T03:04:05Z,TX,4,41540d0a,"AT\x0D\x0A"` {
+		t.Errorf("Unexpected row %q", lines[1])
+	}
+}
+
+func TestExportHexAndRaw(t *testing.T) {
+	if out := string(export(t, FormatHex)); !strings.Contains(out, "RX 4 bytes") || !strings.Contains(out, "4f 4b 0d 0a") {
+		t.Errorf("Unexpected hex dump %q", out)
+	}
+	if out := string(export(t, FormatRaw)); out != "OK\r\n" {
+		t.Errorf("Raw export should only contain RX data, got %q", out)
+	}
+}
+
+func TestExportPcap(t *testing.T) {
+	out := export(t, FormatPcap)
+	if binary.LittleEndian.Uint32(out[0:4]) != 0xa1b2c3d4 || binary.LittleEndian.Uint32(out[20:24]) != pcapLinkType {
+		t.Fatal("Invalid pcap header")
+	}
+	// 第一个包: 16 字节包头 + 1 字节方向 + 4 字节数据
+	pkt := out[24:]
+	if binary.LittleEndian.Uint32(pkt[8:12]) != 5 || pkt[16] != byte(TX) || string(pkt[17:21]) != "AT\r\n" {
+		t.Errorf("Unexpected first packet % x", pkt[:21])
+	}
+	if len(out) != 24+2*(16+5) {
+		t.Errorf("Unexpected pcap size %d", len(out))
+	}
+}
+
+func TestExportCanceled(t *testing.T) {
+	r, _ := Open(writeSample(t))
+	defer r.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Export(ctx, r, FormatCSV, io.Discard, nil); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	r, _ := Open(writeSample(t))
+	defer r.Close()
+	if err := Export(context.Background(), r, "xml", io.Discard, nil); err != ErrUnknownFormat {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	for i, target := range []string{"COM1", "COM2"} {
+		w, err := Create(filepath.Join(dir, target+Ext), Meta{Started: time.Unix(int64(i), 0), Target: target})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Close()
+	}
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o644)
+
+	list, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != "COM2" || list[1].Meta.Target != "COM1" {
+		t.Errorf("Unexpected list %+v", list)
+	}
+
+	if list, err := List(filepath.Join(dir, "missing")); err != nil || len(list) != 0 {
+		t.Errorf("Missing directory should give an empty list, got %v, %v", list, err)
+	}
+}
+
+func passphrase(s string) Passphrase {
+	return func() ([]string, error) { return []string{s}, nil }
+}
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret"+Ext)
+	w, err := CreateEncrypted(path, Meta{Connection: "SERIAL", Target: "COM3"}, "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(time.Unix(1, 0), RX, []byte("token=abc\n"))
+	w.Flush()
+	if err := w.UpdateMeta(Meta{Connection: "SERIAL", Target: "COM3", Title: "Gateway"}); err != nil {
+		t.Fatal(err)
+	}
+	w.Write(time.Unix(2, 0), TX, []byte("AT\r\n"))
+	w.Close()
+
+	raw, _ := os.ReadFile(path)
+	if bytes.Contains(raw, []byte("token")) || bytes.Contains(raw, []byte("COM3")) {
+		t.Error("Plaintext leaked into the encrypted capture")
+	}
+	if _, err := Open(path); err != ErrEncrypted {
+		t.Errorf("Expected ErrEncrypted, got %v", err)
+	}
+	if _, err := OpenWith(path, passphrase("wrong")); err != ErrWrongPassphrase {
+		t.Errorf("Expected ErrWrongPassphrase, got %v", err)
+	}
+
+	r, err := OpenWith(path, func() ([]string, error) { return []string{"wrong", "hunter2"}, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if !r.Encrypted() || r.Meta().Title != "Gateway" {
+		t.Errorf("Unexpected meta %+v", r.Meta())
+	}
+	for _, want := range []string{"token=abc\n", "AT\r\n"} {
+		if rec, err := r.Next(); err != nil || string(rec.Data) != want {
+			t.Errorf("Expected %q, got %+v, %v", want, rec, err)
+		}
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Expected EOF, got %v", err)
+	}
+
+	list, _ := List(dir)
+	if len(list) != 1 || !list[0].Encrypted || !list[0].Locked || list[0].Meta.Target != "" {
+		t.Errorf("Without a passphrase the capture should be listed as locked, got %+v", list)
+	}
+	list, _ = ListWith(dir, passphrase("hunter2"))
+	if len(list) != 1 || !list[0].Encrypted || list[0].Locked || list[0].Meta.Title != "Gateway" {
+		t.Errorf("Unexpected list %+v", list)
+	}
+}
+
+func TestEncryptedTruncatedIsEOF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret"+Ext)
+	w, _ := CreateEncrypted(path, Meta{}, "hunter2")
+	w.Write(time.Unix(1, 0), RX, []byte("first"))
+	w.Flush()
+	w.Write(time.Unix(2, 0), RX, []byte("second"))
+	w.Close()
+	st, _ := os.Stat(path)
+	os.Truncate(path, st.Size()-2)
+
+	r, err := OpenWith(path, passphrase("hunter2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if rec, err := r.Next(); err != nil || string(rec.Data) != "first" {
+		t.Errorf("Unexpected record %+v, %v", rec, err)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Expected EOF for a truncated chunk, got %v", err)
+	}
+}
+
+func TestEncryptionConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "enc.json")
+	if c, err := LoadEncryption(path); err != nil || c.Mode != EncryptionOff {
+		t.Errorf("Missing file should disable encryption, got %+v, %v", c, err)
+	}
+	if err := SaveEncryption(path, EncryptionConfig{Mode: EncryptionKeychain}); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := LoadEncryption(path); err != nil || c.Mode != EncryptionKeychain {
+		t.Errorf("Unexpected config %+v, %v", c, err)
+	}
+	if err := (EncryptionConfig{Mode: "rot13"}).Validate(); err == nil {
+		t.Error("Unknown mode should be rejected")
+	}
+}
+
+// upperLines 按行缓冲并转为大写的测试过滤器
+type upperLines struct {
+	pending [2][]byte
+}
+
+func (u *upperLines) Write(dir Direction, data []byte) []byte {
+	u.pending[dir] = append(u.pending[dir], data...)
+	i := bytes.LastIndexByte(u.pending[dir], '\n')
+	if i < 0 {
+		return nil
+	}
+	out := bytes.ToUpper(u.pending[dir][:i+1])
+	u.pending[dir] = u.pending[dir][i+1:]
+	return out
+}
+
+func (u *upperLines) Flush(dir Direction) []byte {
+	return bytes.ToUpper(u.pending[dir])
+}
+
+func TestReaderFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "filter"+Ext)
+	w, _ := Create(path, Meta{})
+	w.Write(time.Unix(1, 0), RX, []byte("pass"))
+	w.Write(time.Unix(2, 0), RX, []byte("word\nlog"))
+	w.Write(time.Unix(3, 0), TX, []byte("at"))
+	w.Close()
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	r.SetFilter(&upperLines{})
+	var got []string
+	for {
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, rec.Dir.String()+":"+string(rec.Data))
+	}
+	if strings.Join(got, "|") != "RX:PASSWORD\n|RX:LOG|TX:AT" {
+		t.Errorf("Unexpected records %q", got)
+	}
+}
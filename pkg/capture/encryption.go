@@ -0,0 +1,60 @@
+package capture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// EncryptionMode 新抓包文件的加密方式
+type EncryptionMode string
+
+const (
+	EncryptionOff        EncryptionMode = "off"        // 不加密
+	EncryptionPassphrase EncryptionMode = "passphrase" // 用户口令，只保存在内存中，每次启动后需重新输入
+	EncryptionKeychain   EncryptionMode = "keychain"   // 随机密钥，保存在系统钥匙串
+)
+
+// EncryptionConfig 抓包加密设置
+type EncryptionConfig struct {
+	Mode EncryptionMode `json:"mode"`
+}
+
+// Validate 检查设置
+func (c EncryptionConfig) Validate() error {
+	switch c.Mode {
+	case EncryptionOff, EncryptionPassphrase, EncryptionKeychain:
+		return nil
+	}
+	return fmt.Errorf("capture: unknown encryption mode %q", c.Mode)
+}
+
+// LoadEncryption 读取加密设置，文件不存在时返回不加密
+func LoadEncryption(path string) (EncryptionConfig, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return EncryptionConfig{Mode: EncryptionOff}, nil
+	}
+	if err != nil {
+		return EncryptionConfig{}, err
+	}
+	var c EncryptionConfig
+	if err := json.Unmarshal(data, &c); err != nil {
+		return EncryptionConfig{}, err
+	}
+	return c, c.Validate()
+}
+
+// SaveEncryption 保存加密设置
+func SaveEncryption(path string, c EncryptionConfig) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,169 @@
+package capture
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format 导出格式
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatHex  Format = "hex"
+	FormatPcap Format = "pcap"
+	FormatRaw  Format = "raw" // 仅接收方向的原始字节
+)
+
+// ErrUnknownFormat 不支持的导出格式
+var ErrUnknownFormat = errors.New("capture: unknown export format")
+
+// pcap 链路类型 LINKTYPE_USER0；每个包前有 1 字节伪头部表示方向 (0 = RX, 1 = TX)
+const pcapLinkType = 147
+
+// Export 将抓包逐条流式导出到 w，内存占用与抓包大小无关
+// progress 在导出过程中以 (已读字节, 总字节) 回调，可为 nil
+func Export(ctx context.Context, r *Reader, format Format, w io.Writer, progress func(done, total int64)) error {
+	var write func(Record) error
+	bw := bufio.NewWriterSize(w, 256*1024)
+
+	switch format {
+	case FormatCSV:
+		bw.WriteString("time,direction,length,hex,text\n")
+		write = func(rec Record) error {
+			bw.WriteString(rec.Time.Format(time.RFC3339Nano))
+			bw.WriteByte(',')
+			bw.WriteString(rec.Dir.String())
+			bw.WriteByte(',')
+			bw.WriteString(strconv.Itoa(len(rec.Data)))
+			bw.WriteByte(',')
+			hexWriter := hex.NewEncoder(bw)
+			hexWriter.Write(rec.Data)
+			bw.WriteByte(',')
+			bw.WriteString(csvQuote(rec.Data))
+			return bw.WriteByte('\n')
+		}
+	case FormatHex:
+		write = func(rec Record) error {
+			fmt.Fprintf(bw, "[%s] %s %d bytes\n", rec.Time.Format("2006-01-02 15:04:05.000000"), rec.Dir, len(rec.Data))
+			d := hex.Dumper(bw)
+			d.Write(rec.Data)
+			d.Close()
+			return bw.WriteByte('\n')
+		}
+	case FormatPcap:
+		writePcapHeader(bw)
+		var hdr [16]byte
+		write = func(rec Record) error {
+			n := uint32(len(rec.Data) + 1)
+			binary.LittleEndian.PutUint32(hdr[0:4], uint32(rec.Time.Unix()))
+			binary.LittleEndian.PutUint32(hdr[4:8], uint32(rec.Time.Nanosecond()/1000))
+			binary.LittleEndian.PutUint32(hdr[8:12], n)
+			binary.LittleEndian.PutUint32(hdr[12:16], n)
+			bw.Write(hdr[:])
+			bw.WriteByte(byte(rec.Dir))
+			_, err := bw.Write(rec.Data)
+			return err
+		}
+	case FormatRaw:
+		write = func(rec Record) error {
+			if rec.Dir != RX {
+				return nil
+			}
+			_, err := bw.Write(rec.Data)
+			return err
+		}
+	default:
+		return ErrUnknownFormat
+	}
+
+	for i := 0; ; i++ {
+		// 每 1024 条检查一次取消并上报进度
+		if i%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if progress != nil {
+				progress(r.Offset(), r.Size())
+			}
+		}
+		rec, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := write(rec); err != nil {
+			return err
+		}
+	}
+	if progress != nil {
+		progress(r.Size(), r.Size())
+	}
+	return bw.Flush()
+}
+
+// Manifest 导出文件的说明，与导出文件一同保存，记录来源抓包与会话信息
+type Manifest struct {
+	Capture  string    `json:"capture"` // 抓包 ID
+	File     string    `json:"file"`    // 导出文件名
+	Format   Format    `json:"format"`
+	Exported time.Time `json:"exported"`
+	Meta     Meta      `json:"meta"`
+	Redacted bool      `json:"redacted,omitempty"` // 导出时已按规则遮盖敏感内容
+}
+
+// ManifestPath 返回导出文件对应的说明文件路径
+func ManifestPath(exportPath string) string {
+	return exportPath + ".manifest.json"
+}
+
+// WriteManifest 将说明写入 ManifestPath(exportPath)
+func WriteManifest(exportPath string, m Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ManifestPath(exportPath), data, 0o644)
+}
+
+// writePcapHeader 写入 pcap 全局头 (微秒时间戳)
+func writePcapHeader(w io.Writer) {
+	var h [24]byte
+	binary.LittleEndian.PutUint32(h[0:4], 0xa1b2c3d4)
+	binary.LittleEndian.PutUint16(h[4:6], 2)
+	binary.LittleEndian.PutUint16(h[6:8], 4)
+	binary.LittleEndian.PutUint32(h[16:20], maxRecordSize+1)
+	binary.LittleEndian.PutUint32(h[20:24], pcapLinkType)
+	w.Write(h[:])
+}
+
+// csvQuote 将数据按文本输出，不可打印字符转义为 \xHH
+func csvQuote(data []byte) string {
+	var b strings.Builder
+	b.Grow(len(data) + 2)
+	b.WriteByte('"')
+	for _, c := range data {
+		switch {
+		case c == '"':
+			b.WriteString(`""`)
+		case c >= 0x20 && c < 0x7f:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, `\x%02X`, c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
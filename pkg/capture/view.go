@@ -0,0 +1,246 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"time"
+)
+
+// lineStride 行索引的稀疏间隔：每 lineStride 行记录一个检查点，
+// 2 GB 的日志索引只占几 MB，定位任意一行最多向后扫描 lineStride-1 行
+const lineStride = 64
+
+// checkpoint 行起点：所在记录头的文件偏移及在记录数据中的偏移
+type checkpoint struct {
+	record int64
+	offset uint32
+}
+
+// View 内存映射的抓包视图，用于快速滚动和搜索接收方向的文本行
+// 打开时只扫描一遍数据建立稀疏行索引，数据本身不复制到内存
+type View struct {
+	data   []byte // 整个文件的映射；加密文件为解密后的记录
+	mapped bool
+	meta   Meta
+	start  int64 // 第一条记录的偏移
+	end    int64 // 最后一条完整记录的结尾
+	lines  int
+	points []checkpoint
+}
+
+// OpenView 映射抓包文件并建立行索引
+func OpenView(path string) (*View, error) {
+	return OpenViewWith(path, nil)
+}
+
+// OpenViewWith 同 OpenView，加密文件通过 pass 获取口令，解密后的记录整体读入内存
+func OpenViewWith(path string, pass Passphrase) (*View, error) {
+	r, err := OpenWith(path, pass)
+	if err != nil {
+		return nil, err
+	}
+	if r.Encrypted() {
+		defer r.Close()
+		data, err := io.ReadAll(r.r)
+		if err = eof(err); err != nil && err != io.EOF {
+			return nil, err
+		}
+		v := &View{meta: r.Meta(), data: data}
+		v.buildIndex()
+		return v, nil
+	}
+	meta, start, size := r.Meta(), r.Offset(), r.Size()
+	r.Close()
+
+	v := &View{meta: meta, start: start, end: start}
+	if size > start {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		v.data, err = mmapFile(f, size)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		v.mapped = true
+	}
+	v.buildIndex()
+	return v, nil
+}
+
+// Meta 返回元数据
+func (v *View) Meta() Meta {
+	return v.meta
+}
+
+// Lines 返回接收数据的行数 (最后一行没有换行符时也计入)
+func (v *View) Lines() int {
+	return v.lines
+}
+
+// Close 解除映射
+func (v *View) Close() error {
+	if !v.mapped {
+		v.data = nil
+		return nil
+	}
+	v.mapped = false
+	err := munmap(v.data)
+	v.data = nil
+	return err
+}
+
+// record 解析 off 处的记录，返回数据及下一条记录的偏移；越界或不完整时 ok 为 false
+func (v *View) record(off int64) (t time.Time, dir Direction, data []byte, next int64, ok bool) {
+	if off+recordHeaderSize > int64(len(v.data)) {
+		return
+	}
+	hdr := v.data[off : off+recordHeaderSize]
+	n := int64(binary.LittleEndian.Uint32(hdr[9:13]))
+	next = off + recordHeaderSize + n
+	if n > maxRecordSize || next > int64(len(v.data)) {
+		return
+	}
+	t = time.Unix(0, int64(binary.LittleEndian.Uint64(hdr[0:8])))
+	return t, Direction(hdr[8]), v.data[off+recordHeaderSize : next], next, true
+}
+
+// buildIndex 扫描所有接收记录，每 lineStride 行记录一个检查点
+func (v *View) buildIndex() {
+	atLineStart := true
+	for off := v.start; ; {
+		_, dir, data, next, ok := v.record(off)
+		if !ok {
+			break
+		}
+		v.end = next
+		if dir == RX {
+			for i := 0; i < len(data); {
+				if atLineStart {
+					if v.lines%lineStride == 0 {
+						v.points = append(v.points, checkpoint{record: off, offset: uint32(i)})
+					}
+					v.lines++
+					atLineStart = false
+				}
+				j := bytes.IndexByte(data[i:], '\n')
+				if j < 0 {
+					break
+				}
+				i += j + 1
+				atLineStart = true
+			}
+		}
+		off = next
+	}
+}
+
+// cursor 从某个位置开始顺序读取接收数据
+type cursor struct {
+	v      *View
+	record int64
+	data   []byte // 当前记录中尚未读取的数据
+	buf    []byte // 拼接跨记录行的缓冲区，循环复用
+}
+
+func (v *View) cursorAt(p checkpoint) *cursor {
+	c := &cursor{v: v, record: p.record}
+	if _, _, data, _, ok := v.record(p.record); ok {
+		c.data = data[p.offset:]
+	}
+	return c
+}
+
+// advance 跳到下一条接收记录，没有更多数据时返回 false
+func (c *cursor) advance() bool {
+	_, _, _, next, ok := c.v.record(c.record)
+	for ok {
+		var dir Direction
+		var data []byte
+		c.record = next
+		_, dir, data, next, ok = c.v.record(c.record)
+		if ok && dir == RX {
+			c.data = data
+			return true
+		}
+	}
+	c.data = nil
+	return false
+}
+
+// line 读取一行 (不含换行符)，返回值在下一次调用前有效
+// 行完全位于一条记录内时直接返回映射中的切片，跨记录的行拼接到 c.buf 中
+func (c *cursor) line() ([]byte, bool) {
+	c.buf = c.buf[:0]
+	for {
+		if len(c.data) == 0 && !c.advance() {
+			return c.buf, len(c.buf) > 0
+		}
+		i := bytes.IndexByte(c.data, '\n')
+		if i >= 0 {
+			seg := c.data[:i]
+			c.data = c.data[i+1:]
+			if len(c.buf) == 0 {
+				return seg, true
+			}
+			c.buf = append(c.buf, seg...)
+			return c.buf, true
+		}
+		c.buf = append(c.buf, c.data...)
+		c.data = nil
+	}
+}
+
+// ReadLines 读取从 start 开始的最多 count 行
+func (v *View) ReadLines(start, count int) []string {
+	if start < 0 || start >= v.lines || count <= 0 {
+		return []string{}
+	}
+	if start+count > v.lines {
+		count = v.lines - start
+	}
+	c := v.cursorAt(v.points[start/lineStride])
+	for i := start - start%lineStride; i < start; i++ {
+		c.line()
+	}
+	lines := make([]string, 0, count)
+	for len(lines) < count {
+		line, ok := c.line()
+		if !ok {
+			break
+		}
+		lines = append(lines, string(bytes.TrimSuffix(line, []byte{'\r'})))
+	}
+	return lines
+}
+
+// Search 返回包含 needle 的行号，最多 max 个 (max <= 0 表示不限)
+func (v *View) Search(ctx context.Context, needle []byte, max int) ([]int, error) {
+	matches := []int{}
+	if len(needle) == 0 || v.lines == 0 {
+		return matches, nil
+	}
+	c := v.cursorAt(v.points[0])
+	for n := 0; ; n++ {
+		if n%4096 == 0 {
+			if err := ctx.Err(); err != nil {
+				return matches, err
+			}
+		}
+		line, ok := c.line()
+		if !ok {
+			break
+		}
+		if bytes.Contains(line, needle) {
+			matches = append(matches, n)
+			if max > 0 && len(matches) >= max {
+				break
+			}
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,149 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeLines(t *testing.T, chunks ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "view"+Ext)
+	w, err := Create(path, Meta{Target: "COM1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, c := range chunks {
+		w.Write(now, RX, []byte(c))
+		// 发送方向的数据不属于接收行
+		w.Write(now, TX, []byte("ignored\n"))
+	}
+	w.Close()
+	return path
+}
+
+func TestViewLinesAcrossRecords(t *testing.T) {
+	v, err := OpenView(writeLines(t, "first\r\nsec", "ond\nthi", "rd\n", "tail"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+
+	if v.Lines() != 4 {
+		t.Fatalf("Expected 4 lines, got %d", v.Lines())
+	}
+	want := []string{"first", "second", "third", "tail"}
+	if got := v.ReadLines(0, 10); !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadLines = %q, expected %q", got, want)
+	}
+	if got := v.ReadLines(2, 1); !reflect.DeepEqual(got, []string{"third"}) {
+		t.Errorf("ReadLines(2, 1) = %q", got)
+	}
+	if got := v.ReadLines(9, 1); len(got) != 0 {
+		t.Errorf("Out of range read should be empty, got %q", got)
+	}
+}
+
+func TestViewIndexBeyondStride(t *testing.T) {
+	var chunks []string
+	for i := 0; i < lineStride*3+5; i++ {
+		chunks = append(chunks, fmt.Sprintf("line %d\n", i))
+	}
+	v, err := OpenView(writeLines(t, chunks...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+
+	if v.Lines() != len(chunks) || len(v.points) != 4 {
+		t.Fatalf("Unexpected index: %d lines, %d checkpoints", v.Lines(), len(v.points))
+	}
+	start := lineStride*2 + 7
+	got := v.ReadLines(start, 2)
+	if len(got) != 2 || got[0] != fmt.Sprintf("line %d", start) {
+		t.Errorf("Unexpected lines %q", got)
+	}
+
+	matches, err := v.Search(context.Background(), []byte("line 13"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(matches, []int{13, 130, 131, 132, 133, 134, 135, 136, 137, 138, 139}) {
+		t.Errorf("Unexpected matches %v", matches)
+	}
+	if matches, _ := v.Search(context.Background(), []byte("line"), 3); len(matches) != 3 {
+		t.Errorf("Expected search to stop at 3 matches, got %d", len(matches))
+	}
+}
+
+func TestViewSearchAcrossRecords(t *testing.T) {
+	v, err := OpenView(writeLines(t, "ERR", "OR here\nok\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	matches, _ := v.Search(context.Background(), []byte("ERROR"), 0)
+	if !reflect.DeepEqual(matches, []int{0}) {
+		t.Errorf("Expected match spanning records, got %v", matches)
+	}
+}
+
+func TestViewEmptyCapture(t *testing.T) {
+	v, err := OpenView(writeLines(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if v.Lines() != 0 || len(v.ReadLines(0, 5)) != 0 {
+		t.Error("Empty capture should have no lines")
+	}
+}
+
+func BenchmarkOpenView(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "bench"+Ext)
+	w, _ := Create(path, Meta{})
+	chunk := []byte("2024-01-01 00:00:00.000 [INFO] sensor value=1234 status=ok\n")
+	for i := 0; i < 200000; i++ {
+		w.Write(time.Now(), RX, chunk)
+	}
+	w.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v, err := OpenView(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		v.Close()
+	}
+}
+
+func TestViewEncrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "view"+Ext)
+	w, err := CreateEncrypted(path, Meta{Target: "COM1"}, "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(time.Now(), RX, []byte("first\nsec"))
+	w.Write(time.Now(), RX, []byte("ond\n"))
+	w.Close()
+
+	if _, err := OpenView(path); err != ErrEncrypted {
+		t.Errorf("Expected ErrEncrypted, got %v", err)
+	}
+	v, err := OpenViewWith(path, passphrase("hunter2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer v.Close()
+	if got := v.ReadLines(0, 10); !reflect.DeepEqual(got, []string{"first", "second"}) {
+		t.Errorf("ReadLines = %q", got)
+	}
+	if m, _ := v.Search(context.Background(), []byte("cond"), 0); !reflect.DeepEqual(m, []int{1}) {
+		t.Errorf("Search = %v", m)
+	}
+}
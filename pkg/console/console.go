@@ -0,0 +1,225 @@
+package console
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPrompt 未指定提示符时匹配的常见 shell 提示符：U-Boot "=> "、Zephyr "uart:~$ "、
+// Linux "root@host:~# " / "$ " 等以 "#"、"$"、">" 加空格结尾且位于输出末尾的提示符
+const DefaultPrompt = `[#$>] $`
+
+// DefaultLineEnding 命令后追加的行结束符
+const DefaultLineEnding = "\n"
+
+// maxOutput 单条命令输出的长度上限
+const maxOutput = 1 << 20
+
+// ErrTooLong 等到提示符之前输出超过上限
+var ErrTooLong = errors.New("console: output too long")
+
+// ansiEscape 终端颜色等 ANSI 转义序列 (Zephyr shell 的提示符带颜色)
+var ansiEscape = regexp.MustCompile(`\x1b(\[[0-9;?]*[ -/]*[@-~]|[@-Z\\-_])`)
+
+// StripANSI 删除 ANSI 转义序列
+func StripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+// Result 一条命令的执行结果
+type Result struct {
+	Command    string `json:"command"`
+	Output     string `json:"output"`     // 命令回显之后、提示符之前的输出
+	Prompt     string `json:"prompt"`     // 匹配到的提示符
+	DurationMs int64  `json:"durationMs"` // 从发送到收到提示符的时间
+	Error      string `json:"error,omitempty"`
+}
+
+// Session 在控制台连接上执行命令并等待提示符；命令按顺序执行，没有命令在等待时收到的数据被忽略
+type Session struct {
+	write func(ctx context.Context, p []byte) error
+
+	mu     sync.Mutex // 串行化命令
+	bufMu  sync.Mutex
+	active bool
+	buf    strings.Builder
+	prompt *regexp.Regexp
+	notify chan struct{}
+
+	lineEnding string
+}
+
+// New 创建会话，write 向设备写入数据
+func New(write func(ctx context.Context, p []byte) error) *Session {
+	return &Session{write: write, notify: make(chan struct{}, 1), lineEnding: DefaultLineEnding}
+}
+
+// SetLineEnding 设置命令后追加的行结束符，为空时使用 "\n"
+func (s *Session) SetLineEnding(ending string) {
+	if ending == "" {
+		ending = DefaultLineEnding
+	}
+	s.bufMu.Lock()
+	defer s.bufMu.Unlock()
+	s.lineEnding = ending
+}
+
+// LineEnding 返回命令后追加的行结束符
+func (s *Session) LineEnding() string {
+	s.bufMu.Lock()
+	defer s.bufMu.Unlock()
+	return s.lineEnding
+}
+
+// Feed 处理接收到的数据
+func (s *Session) Feed(data []byte) {
+	s.bufMu.Lock()
+	defer s.bufMu.Unlock()
+	if !s.active {
+		return
+	}
+	s.buf.Write(data)
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Execute 发送命令并返回直到下一个提示符之间的输出；prompt 为空时使用 DefaultPrompt
+func (s *Session) Execute(ctx context.Context, cmd, prompt string) (Result, error) {
+	if prompt == "" {
+		prompt = DefaultPrompt
+	}
+	re, err := regexp.Compile(prompt)
+	if err != nil {
+		return Result{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.bufMu.Lock()
+	s.active = true
+	s.buf.Reset()
+	s.prompt = re
+	ending := s.lineEnding
+	s.bufMu.Unlock()
+	defer s.stop()
+
+	start := time.Now()
+	if err := s.write(ctx, []byte(cmd+ending)); err != nil {
+		return Result{}, err
+	}
+	for {
+		if r, ok, err := s.check(cmd); err != nil || ok {
+			r.DurationMs = time.Since(start).Milliseconds()
+			return r, err
+		}
+		select {
+		case <-s.notify:
+		case <-ctx.Done():
+			return Result{}, ctx.Err()
+		}
+	}
+}
+
+// check 在已收到的输出中查找提示符
+func (s *Session) check(cmd string) (Result, bool, error) {
+	s.bufMu.Lock()
+	text := s.buf.String()
+	re := s.prompt
+	s.bufMu.Unlock()
+	if len(text) > maxOutput {
+		return Result{}, false, ErrTooLong
+	}
+
+	clean := strings.ReplaceAll(StripANSI(text), "\r\n", "\n")
+	body := stripEcho(clean, cmd)
+	loc := re.FindStringIndex(body)
+	if loc == nil {
+		return Result{}, false, nil
+	}
+	// 提示符所在行的前缀 (如 "root@host:~") 属于提示符而不是输出
+	line := strings.LastIndexByte(body[:loc[0]], '\n') + 1
+	out := strings.TrimRight(body[:line], "\n")
+	prompt := body[line:loc[1]]
+	return Result{Command: cmd, Output: out, Prompt: prompt}, true, nil
+}
+
+// stripEcho 删除开头的命令回显行
+func stripEcho(text, cmd string) string {
+	if cmd == "" {
+		return strings.TrimLeft(text, "\r\n")
+	}
+	if i := strings.Index(text, cmd); i >= 0 && !strings.Contains(text[:i], "\n") {
+		rest := text[i+len(cmd):]
+		if j := strings.IndexByte(rest, '\n'); j >= 0 {
+			return rest[j+1:]
+		}
+		return "" // 回显还未结束
+	}
+	return text
+}
+
+// stop 结束等待并丢弃未处理的数据
+func (s *Session) stop() {
+	s.bufMu.Lock()
+	defer s.bufMu.Unlock()
+	s.active = false
+	s.buf.Reset()
+	s.prompt = nil
+	select {
+	case <-s.notify:
+	default:
+	}
+}
+
+// Step 批量执行中的一条命令
+type Step struct {
+	Command   string `json:"command"`
+	Prompt    string `json:"prompt"`    // 空时使用批量的提示符
+	TimeoutMs int    `json:"timeoutMs"` // 0 时使用批量的超时
+}
+
+// Batch 依次执行多条命令，每条命令有各自的超时；返回已执行命令的结果
+// continueOnError 为 false 时遇到第一个错误即停止，错误同时记录在对应结果的 Error 中；ctx 被取消时总是停止
+// progress 在每条命令完成后调用 (可为 nil)
+func (s *Session) Batch(ctx context.Context, steps []Step, prompt string, timeout time.Duration, continueOnError bool, progress func(done int, r Result)) ([]Result, error) {
+	results := make([]Result, 0, len(steps))
+	var firstErr error
+	for i, step := range steps {
+		p := step.Prompt
+		if p == "" {
+			p = prompt
+		}
+		t := timeout
+		if step.TimeoutMs > 0 {
+			t = time.Duration(step.TimeoutMs) * time.Millisecond
+		}
+		stepCtx, cancel := context.WithTimeout(ctx, t)
+		start := time.Now()
+		r, err := s.Execute(stepCtx, step.Command, p)
+		cancel()
+		if err != nil {
+			r = Result{Command: step.Command, DurationMs: time.Since(start).Milliseconds(), Error: err.Error()}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		results = append(results, r)
+		if progress != nil {
+			progress(i+1, r)
+		}
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		if err != nil && !continueOnError {
+			break
+		}
+	}
+	return results, firstErr
+}
@@ -0,0 +1,112 @@
+package console
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeShell 收到命令后回显并按 replies 分块应答
+type fakeShell struct {
+	s       *Session
+	replies map[string][]string
+	sent    []string
+}
+
+func (f *fakeShell) write(_ context.Context, p []byte) error {
+	cmd := strings.TrimRight(string(p), "\r\n")
+	f.sent = append(f.sent, string(p))
+	go func() {
+		for _, chunk := range f.replies[cmd] {
+			f.s.Feed([]byte(chunk))
+		}
+	}()
+	return nil
+}
+
+func newShell(replies map[string][]string) *fakeShell {
+	f := &fakeShell{replies: replies}
+	f.s = New(f.write)
+	return f
+}
+
+func TestExecuteUBoot(t *testing.T) {
+	f := newShell(map[string][]string{
+		"printenv bootdelay": {"printenv bootd", "elay\r\nbootdelay=3\r\n", "=", "> "},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	r, err := f.s.Execute(ctx, "printenv bootdelay", "=> $")
+	if err != nil || r.Output != "bootdelay=3" || r.Prompt != "=> " {
+		t.Fatalf("Execute = %+v, %v", r, err)
+	}
+	if f.sent[0] != "printenv bootdelay\n" {
+		t.Errorf("Sent %q", f.sent[0])
+	}
+}
+
+func TestExecuteDefaultPromptWithColors(t *testing.T) {
+	f := newShell(map[string][]string{
+		"kernel version": {"kernel version\r\nZephyr version 3.6.0\r\n\x1b[1;32muart:~$ \x1b[m"},
+		"uname -r":       {"uname -r\r\n6.1.0\r\nroot@imx8:~# "},
+	})
+	f.s.SetLineEnding("\r")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	r, err := f.s.Execute(ctx, "kernel version", `uart:~\$ $`)
+	if err != nil || r.Output != "Zephyr version 3.6.0" || r.Prompt != "uart:~$ " {
+		t.Fatalf("Zephyr: %+v, %v", r, err)
+	}
+	r, err = f.s.Execute(ctx, "uname -r", "")
+	if err != nil || r.Output != "6.1.0" || r.Prompt != "root@imx8:~# " {
+		t.Fatalf("Linux: %+v, %v", r, err)
+	}
+	if f.sent[1] != "uname -r\r" {
+		t.Errorf("Sent %q", f.sent[1])
+	}
+}
+
+func TestExecuteTimeoutAndBadPrompt(t *testing.T) {
+	f := newShell(map[string][]string{"reset": {"reset\r\nresetting ..."}})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := f.s.Execute(ctx, "reset", ""); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected timeout, got %v", err)
+	}
+	if _, err := f.s.Execute(context.Background(), "x", "("); err == nil {
+		t.Error("Invalid prompt should fail")
+	}
+	// 没有命令等待时收到的数据被忽略
+	f.s.Feed([]byte("=> "))
+	f.replies["version"] = []string{"version\nU-Boot 2024.01\n=> "}
+	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Second)
+	defer cancel2()
+	if r, err := f.s.Execute(ctx2, "version", "=> $"); err != nil || r.Output != "U-Boot 2024.01" {
+		t.Errorf("Execute = %+v, %v", r, err)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	f := newShell(map[string][]string{
+		"mmc dev 0": {"mmc dev 0\nswitch to partitions #0, OK\n=> "},
+		"mmc info":  {"mmc info\nDevice: FSL_SDHC\n"}, // 没有提示符，超时
+		"version":   {"version\nU-Boot 2024.01\n=> "},
+	})
+	steps := []Step{{Command: "mmc dev 0"}, {Command: "mmc info", TimeoutMs: 30}, {Command: "version"}}
+
+	var done []int
+	results, err := f.s.Batch(context.Background(), steps, "=> $", time.Second, false, func(n int, r Result) { done = append(done, n) })
+	if !errors.Is(err, context.DeadlineExceeded) || len(results) != 2 || results[1].Error == "" {
+		t.Fatalf("Batch = %+v, %v", results, err)
+	}
+	if results[0].Output != "switch to partitions #0, OK" || results[0].Prompt != "=> " || len(done) != 2 {
+		t.Errorf("Unexpected first result %+v (progress %v)", results[0], done)
+	}
+
+	results, err = f.s.Batch(context.Background(), steps, "=> $", time.Second, true, nil)
+	if err == nil || len(results) != 3 || results[2].Output != "U-Boot 2024.01" || results[2].Error != "" {
+		t.Errorf("continueOnError: %+v, %v", results, err)
+	}
+}
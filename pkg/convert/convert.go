@@ -0,0 +1,147 @@
+package convert
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Value 一种解释方式下的结果
+type Value struct {
+	Type   string `json:"type"`             // u8..u64、i8..i64、f32、f64、ascii、bcd、unix、unix_ms
+	Endian string `json:"endian,omitempty"` // little 或 big，单字节及与字节序无关的类型为空
+	Value  string `json:"value"`
+}
+
+// maxASCII ASCII 解释最多显示的字节数
+const maxASCII = 256
+
+// ParseHex 解析十六进制字符串，允许空格、冒号、逗号分隔以及 0x 前缀
+func ParseHex(s string) ([]byte, error) {
+	var b strings.Builder
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ':' || r == ',' || r == '\t' || r == '\n' || r == '\r'
+	}) {
+		field = strings.TrimPrefix(strings.TrimPrefix(field, "0x"), "0X")
+		if len(field)%2 == 1 {
+			field = "0" + field
+		}
+		b.WriteString(field)
+	}
+	data, err := hex.DecodeString(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("convert: invalid hex: %w", err)
+	}
+	return data, nil
+}
+
+// ErrEmpty 没有可解释的数据
+var ErrEmpty = errors.New("convert: no data")
+
+// Interpret 以所有适用的方式解释 data，数值类型只取开头的字节
+func Interpret(data []byte) ([]Value, error) {
+	if len(data) == 0 {
+		return nil, ErrEmpty
+	}
+	var out []Value
+	out = append(out,
+		Value{Type: "u8", Value: strconv.FormatUint(uint64(data[0]), 10)},
+		Value{Type: "i8", Value: strconv.FormatInt(int64(int8(data[0])), 10)},
+	)
+	orders := []struct {
+		name  string
+		order binary.ByteOrder
+	}{
+		{"little", binary.LittleEndian},
+		{"big", binary.BigEndian},
+	}
+	for _, o := range orders {
+		if len(data) >= 2 {
+			v := o.order.Uint16(data)
+			out = append(out,
+				Value{Type: "u16", Endian: o.name, Value: strconv.FormatUint(uint64(v), 10)},
+				Value{Type: "i16", Endian: o.name, Value: strconv.FormatInt(int64(int16(v)), 10)},
+			)
+		}
+		if len(data) >= 4 {
+			v := o.order.Uint32(data)
+			out = append(out,
+				Value{Type: "u32", Endian: o.name, Value: strconv.FormatUint(uint64(v), 10)},
+				Value{Type: "i32", Endian: o.name, Value: strconv.FormatInt(int64(int32(v)), 10)},
+				Value{Type: "f32", Endian: o.name, Value: strconv.FormatFloat(float64(math.Float32frombits(v)), 'g', -1, 32)},
+				Value{Type: "unix", Endian: o.name, Value: formatTime(time.Unix(int64(v), 0))},
+			)
+		}
+		if len(data) >= 8 {
+			v := o.order.Uint64(data)
+			out = append(out,
+				Value{Type: "u64", Endian: o.name, Value: strconv.FormatUint(v, 10)},
+				Value{Type: "i64", Endian: o.name, Value: strconv.FormatInt(int64(v), 10)},
+				Value{Type: "f64", Endian: o.name, Value: strconv.FormatFloat(math.Float64frombits(v), 'g', -1, 64)},
+			)
+			if ms := int64(v); ms >= 0 && ms < 1<<53 {
+				out = append(out, Value{Type: "unix_ms", Endian: o.name, Value: formatTime(time.UnixMilli(ms))})
+			}
+		}
+	}
+	out = append(out, Value{Type: "ascii", Value: ASCII(data)})
+	if s, ok := BCD(data); ok {
+		out = append(out, Value{Type: "bcd", Value: s})
+	}
+	return out, nil
+}
+
+// ASCII 将数据显示为文本，不可打印字符以 \xHH 转义
+func ASCII(data []byte) string {
+	truncated := len(data) > maxASCII
+	if truncated {
+		data = data[:maxASCII]
+	}
+	var b strings.Builder
+	for _, c := range data {
+		switch {
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case c == '\\':
+			b.WriteString(`\\`)
+		case c >= 0x20 && c < 0x7f:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, `\x%02X`, c)
+		}
+	}
+	if truncated {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// BCD 按压缩 BCD (每字节两位十进制数，高半字节在前) 解释，存在非法半字节时返回 false
+func BCD(data []byte) (string, bool) {
+	if len(data) > 16 {
+		return "", false
+	}
+	var b strings.Builder
+	for _, c := range data {
+		hi, lo := c>>4, c&0x0F
+		if hi > 9 || lo > 9 {
+			return "", false
+		}
+		b.WriteByte('0' + hi)
+		b.WriteByte('0' + lo)
+	}
+	return b.String(), true
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,69 @@
+package convert
+
+import "testing"
+
+func find(values []Value, typ, endian string) (string, bool) {
+	for _, v := range values {
+		if v.Type == typ && v.Endian == endian {
+			return v.Value, true
+		}
+	}
+	return "", false
+}
+
+func TestParseHex(t *testing.T) {
+	for _, s := range []string{"01 02 0A ff", "01:02:0a:FF", "0x01,0x02,0x0a,0xff", "01020aff", "1 2 a ff"} {
+		b, err := ParseHex(s)
+		if err != nil || len(b) != 4 || b[2] != 0x0A || b[3] != 0xFF {
+			t.Errorf("ParseHex(%q) = % X, %v", s, b, err)
+		}
+	}
+	if _, err := ParseHex("zz"); err == nil {
+		t.Error("Expected error for invalid hex")
+	}
+}
+
+func TestInterpret(t *testing.T) {
+	data := []byte{0x00, 0x00, 0x80, 0x3F, 0x12, 0x34, 0x56, 0x78}
+	values, err := Interpret(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		typ, endian, want string
+	}{
+		{"u8", "", "0"},
+		{"u16", "big", "0"},
+		{"u32", "little", "1065353216"},
+		{"f32", "little", "1"},
+		{"i16", "little", "0"},
+		{"u64", "big", "141008376714872"},
+		{"unix", "big", "1970-01-01T09:07:11Z"},
+		{"bcd", "", ""},
+	}
+	for _, c := range cases {
+		got, ok := find(values, c.typ, c.endian)
+		if c.typ == "bcd" {
+			if ok {
+				t.Errorf("0x3F is not valid BCD, got %q", got)
+			}
+			continue
+		}
+		if !ok || got != c.want {
+			t.Errorf("%s/%s = %q, want %q", c.typ, c.endian, got, c.want)
+		}
+	}
+
+	if _, err := Interpret(nil); err != ErrEmpty {
+		t.Errorf("Expected ErrEmpty, got %v", err)
+	}
+}
+
+func TestASCIIAndBCD(t *testing.T) {
+	if s := ASCII([]byte("OK\r\n\x00")); s != `OK\r\n\x00` {
+		t.Errorf("Unexpected ASCII %q", s)
+	}
+	if s, ok := BCD([]byte{0x20, 0x26, 0x10, 0x15}); !ok || s != "20261015" {
+		t.Errorf("Unexpected BCD %q %v", s, ok)
+	}
+}
@@ -0,0 +1,254 @@
+package crashlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 默认值与限制
+const (
+	DefaultBefore = 100             // 触发行之前保留的行数
+	DefaultAfter  = 200             // 触发行之后最多收集的行数
+	DefaultQuiet  = 2 * time.Second // 触发后无新输出多久视为转储结束
+	maxLineLength = 1024
+)
+
+// Pattern 崩溃特征
+type Pattern struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+// DefaultPatterns 常见 RTOS、Linux 内核、Go/Rust/C 运行时与 ESP-IDF 的崩溃输出
+func DefaultPatterns() []Pattern {
+	return []Pattern{
+		{Name: "panic", Pattern: `(?i)\b(?:kernel panic|panic(?:ked)?\b[:!]|fatal error:)`},
+		{Name: "assert", Pattern: `(?i)\bassert(?:ion)?\b.{0,160}?\bfailed\b`},
+		{Name: "hardfault", Pattern: `(?i)\b(?:hard ?fault|memmanage ?fault|bus ?fault|usage ?fault)`},
+		{Name: "esp-exception", Pattern: `Guru Meditation Error|abort\(\) was called|Backtrace: ?0x`},
+		{Name: "oops", Pattern: `\bOops(?: -|:)|\bUnable to handle kernel\b|\bcall trace:`},
+		{Name: "stack-dump", Pattern: `(?i)\b(?:stack (?:dump|trace|overflow)|traceback \(most recent call last\)|segmentation fault)`},
+	}
+}
+
+// Config 崩溃检测配置
+type Config struct {
+	Enabled  bool      `json:"enabled"`
+	Patterns []Pattern `json:"patterns"`
+	Before   int       `json:"before,omitempty"`  // 0 表示 DefaultBefore
+	After    int       `json:"after,omitempty"`   // 0 表示 DefaultAfter
+	QuietMs  int       `json:"quietMs,omitempty"` // 0 表示 DefaultQuiet
+}
+
+// DefaultConfig 返回默认配置
+func DefaultConfig() Config {
+	return Config{Enabled: true, Patterns: DefaultPatterns()}
+}
+
+// LoadConfig 读取配置文件，文件不存在时返回默认配置
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultConfig(), nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+	var c Config
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+// SaveConfig 保存配置
+func SaveConfig(path string, c Config) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Crash 检测到的一次崩溃及其上下文
+type Crash struct {
+	Time    time.Time
+	Trigger string   // 命中的特征名
+	Line    string   // 触发行
+	Lines   []string // 触发行前后的输出，包含触发行
+}
+
+type compiled struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// Detector 逐行检测崩溃输出，命中后继续收集后续输出，直到达到行数上限或输出停止
+type Detector struct {
+	mu       sync.Mutex
+	cfg      Config
+	patterns []compiled
+	before   int
+	after    int
+	quiet    time.Duration
+
+	line    []byte
+	history []string // 最近 before 行
+	current *Crash   // 正在收集的崩溃
+	pending int      // current 还需收集的行数
+	last    time.Time
+}
+
+// New 创建使用默认配置的检测器
+func New() *Detector {
+	d := &Detector{}
+	if err := d.SetConfig(DefaultConfig()); err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// SetConfig 更新配置，特征无效时返回错误；正在收集的崩溃会被丢弃
+func (d *Detector) SetConfig(cfg Config) error {
+	if cfg.Before < 0 || cfg.After < 0 || cfg.QuietMs < 0 {
+		return errors.New("crashlog: negative limits")
+	}
+	patterns := make([]compiled, 0, len(cfg.Patterns))
+	for _, p := range cfg.Patterns {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return fmt.Errorf("crashlog: %s: %w", p.Name, err)
+		}
+		patterns = append(patterns, compiled{p.Name, re})
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cfg = cfg
+	d.patterns = patterns
+	d.before = orDefault(cfg.Before, DefaultBefore)
+	d.after = orDefault(cfg.After, DefaultAfter)
+	d.quiet = time.Duration(orDefault(cfg.QuietMs, int(DefaultQuiet/time.Millisecond))) * time.Millisecond
+	d.current = nil
+	return nil
+}
+
+// Config 返回当前配置
+func (d *Detector) Config() Config {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.cfg
+}
+
+// Reset 清空回看缓存，在新连接建立时调用
+func (d *Detector) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.line = d.line[:0]
+	d.history = nil
+	d.current = nil
+}
+
+// Feed 处理 t 时刻收到的数据，返回收集完成的崩溃
+func (d *Detector) Feed(t time.Time, data []byte) []Crash {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.cfg.Enabled {
+		return nil
+	}
+	var done []Crash
+	if c := d.expireLocked(t); c != nil {
+		done = append(done, *c)
+	}
+	d.line = append(d.line, data...)
+	for {
+		i := bytes.IndexByte(d.line, '\n')
+		if i < 0 {
+			if len(d.line) < maxLineLength {
+				break
+			}
+			i = len(d.line) - 1
+		}
+		line := strings.TrimRight(string(d.line[:i+1]), "\r\n")
+		d.line = d.line[:copy(d.line, d.line[i+1:])]
+		if c := d.lineLocked(t, line); c != nil {
+			done = append(done, *c)
+		}
+	}
+	return done
+}
+
+// Tick 定期调用，输出停止超过静默时间时结束正在收集的崩溃
+func (d *Detector) Tick(now time.Time) *Crash {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.expireLocked(now)
+}
+
+// Flush 立即结束正在收集的崩溃 (如连接断开时)，包括尚未换行的最后一行
+func (d *Detector) Flush() *Crash {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c := d.current
+	if c != nil && len(d.line) > 0 {
+		c.Lines = append(c.Lines, strings.TrimRight(string(d.line), "\r\n"))
+		d.line = d.line[:0]
+	}
+	d.current = nil
+	return c
+}
+
+func (d *Detector) expireLocked(now time.Time) *Crash {
+	if d.current == nil || now.Sub(d.last) < d.quiet {
+		return nil
+	}
+	c := d.current
+	d.current = nil
+	return c
+}
+
+// lineLocked 处理一整行，崩溃收集完成时返回该崩溃
+func (d *Detector) lineLocked(t time.Time, line string) *Crash {
+	d.last = t
+	if c := d.current; c != nil {
+		// 收集期间再次命中 (如 panic 之后的 backtrace) 仍属于同一次崩溃
+		c.Lines = append(c.Lines, line)
+		if d.pending--; d.pending <= 0 {
+			d.current = nil
+			return c
+		}
+		return nil
+	}
+
+	for _, p := range d.patterns {
+		if p.re.MatchString(line) {
+			lines := make([]string, 0, len(d.history)+1+d.after)
+			lines = append(append(lines, d.history...), line)
+			d.history = nil
+			d.current = &Crash{Time: t, Trigger: p.name, Line: line, Lines: lines}
+			d.pending = d.after
+			return nil
+		}
+	}
+
+	d.history = append(d.history, line)
+	if len(d.history) > d.before {
+		d.history = d.history[len(d.history)-d.before:]
+	}
+	return nil
+}
+
+func orDefault(v, def int) int {
+	if v == 0 {
+		return def
+	}
+	return v
+}
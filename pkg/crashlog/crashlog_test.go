@@ -0,0 +1,129 @@
+package crashlog
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultPatterns(t *testing.T) {
+	d := New()
+	for _, line := range []string{
+		"panic: runtime error: index out of range",
+		"Kernel panic - not syncing: VFS",
+		"assertion \"len > 0\" failed: file main.c",
+		"ASSERT(p != NULL) failed at foo.c:12",
+		"[ERR] HardFault_Handler",
+		"Guru Meditation Error: Core  0 panic'ed (LoadProhibited)",
+		"Backtrace: 0x400d1234:0x3ffb1230",
+		"Traceback (most recent call last):",
+	} {
+		d.Reset()
+		d.Feed(time.Now(), []byte(line+"\n"))
+		if c := d.Flush(); c == nil {
+			t.Errorf("Expected crash for %q", line)
+		}
+	}
+	d.Reset()
+	d.Feed(time.Now(), []byte("temperature 23.5 ok\nno panic here\n"))
+	if c := d.Flush(); c != nil {
+		t.Errorf("Unexpected crash %+v", c)
+	}
+}
+
+func TestContextAndQuiet(t *testing.T) {
+	d := New()
+	d.SetConfig(Config{Enabled: true, Patterns: DefaultPatterns(), Before: 2, After: 10, QuietMs: 500})
+	t0 := time.Now()
+	d.Feed(t0, []byte("boot\nline 1\nline 2\npanic: oops\r\ngoroutine 1"))
+	if done := d.Feed(t0.Add(100*time.Millisecond), []byte(" [running]:\nmain.main()\n")); len(done) != 0 {
+		t.Fatalf("Crash should still be collecting, got %+v", done)
+	}
+	if c := d.Tick(t0.Add(200 * time.Millisecond)); c != nil {
+		t.Fatal("Crash should not end before the quiet period")
+	}
+	c := d.Tick(t0.Add(time.Second))
+	if c == nil {
+		t.Fatal("Expected crash after the quiet period")
+	}
+	want := []string{"line 1", "line 2", "panic: oops", "goroutine 1 [running]:", "main.main()"}
+	if strings.Join(c.Lines, "|") != strings.Join(want, "|") || c.Trigger != "panic" || c.Line != "panic: oops" {
+		t.Errorf("Unexpected crash %+v", c)
+	}
+}
+
+func TestAfterLimit(t *testing.T) {
+	d := New()
+	d.SetConfig(Config{Enabled: true, Patterns: DefaultPatterns(), After: 3})
+	var in strings.Builder
+	in.WriteString("HardFault\n")
+	for i := 0; i < 5; i++ {
+		fmt.Fprintf(&in, "r%d\n", i)
+	}
+	done := d.Feed(time.Now(), []byte(in.String()))
+	if len(done) != 1 || len(done[0].Lines) != 4 {
+		t.Fatalf("Expected one crash with 4 lines, got %+v", done)
+	}
+}
+
+func TestInvalidPattern(t *testing.T) {
+	if err := New().SetConfig(Config{Patterns: []Pattern{{Name: "x", Pattern: "("}}}); err == nil {
+		t.Error("Invalid pattern should be rejected")
+	}
+}
+
+func TestConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "crash.json")
+	c, err := LoadConfig(path)
+	if err != nil || !c.Enabled || len(c.Patterns) == 0 {
+		t.Fatalf("Missing file should load defaults, got %+v %v", c, err)
+	}
+	c.After = 50
+	if err := SaveConfig(path, c); err != nil {
+		t.Fatal(err)
+	}
+	if c, _ = LoadConfig(path); c.After != 50 {
+		t.Errorf("Config not saved: %+v", c)
+	}
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t0 := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	c := Crash{Time: t0, Trigger: "panic", Line: "panic: oops", Lines: []string{"boot", "panic: oops"}}
+	r, err := s.Save(c, Report{Connection: "SERIAL COM3", Title: "Gateway v1.2", Device: map[string]string{"firmware": "v1.2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r2, _ := s.Save(c, Report{})
+	if r.ID == r2.ID {
+		t.Error("Reports at the same time should get distinct IDs")
+	}
+
+	s, _ = Open(dir)
+	if list := s.List(); len(list) != 2 || list[0].Lines != 2 {
+		t.Errorf("Unexpected index %+v", list)
+	}
+	text, err := s.Read(r.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Trigger:    panic", "Session:    Gateway v1.2", "firmware:  v1.2", "boot\npanic: oops\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Report missing %q:\n%s", want, text)
+		}
+	}
+
+	if err := s.Delete(r.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Read(r.ID); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
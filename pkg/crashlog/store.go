@@ -0,0 +1,188 @@
+package crashlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 崩溃报告目录中的文件
+const (
+	indexFile = "index.json"
+	reportExt = ".log"
+)
+
+// Report 一份崩溃报告的索引信息，报告正文保存在 <ID>.log 中
+type Report struct {
+	ID         string            `json:"id"`
+	Time       time.Time         `json:"time"`
+	Trigger    string            `json:"trigger"`
+	Line       string            `json:"line"`
+	Connection string            `json:"connection,omitempty"` // 连接类型与目标，如 "SERIAL COM3"
+	Capture    string            `json:"capture,omitempty"`    // 同一会话的抓包 ID
+	Title      string            `json:"title,omitempty"`      // 会话标题
+	Device     map[string]string `json:"device,omitempty"`     // 设备信息，如固件版本
+	Lines      int               `json:"lines"`
+}
+
+// ErrNotFound 报告不存在
+var ErrNotFound = errors.New("crashlog: no such report")
+
+// Store 崩溃报告目录及其索引
+type Store struct {
+	dir     string
+	mu      sync.Mutex
+	reports []Report
+}
+
+// Open 打开报告目录，目录或索引不存在时为空
+func Open(dir string) (*Store, error) {
+	s := &Store{dir: dir}
+	data, err := os.ReadFile(filepath.Join(dir, indexFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.reports); err != nil {
+		return nil, fmt.Errorf("crashlog: %s: %w", indexFile, err)
+	}
+	return s, nil
+}
+
+// Save 将崩溃写成报告文件并加入索引；r 提供报告的会话信息，ID、时间与触发信息由 c 填写
+func (s *Store) Save(c Crash, r Report) (Report, error) {
+	r.Time = c.Time
+	r.Trigger = c.Trigger
+	r.Line = c.Line
+	r.Lines = len(c.Lines)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return Report{}, err
+	}
+	base := c.Time.Format("20060102-150405.000") + "-" + c.Trigger
+	r.ID = base
+	for i := 2; s.indexLocked(r.ID) >= 0; i++ {
+		r.ID = fmt.Sprintf("%s-%d", base, i)
+	}
+	if err := os.WriteFile(s.path(r.ID), []byte(Format(r, c.Lines)), 0o644); err != nil {
+		return Report{}, err
+	}
+	reports := append(append([]Report{}, s.reports...), r)
+	if err := s.saveLocked(reports); err != nil {
+		os.Remove(s.path(r.ID))
+		return Report{}, err
+	}
+	s.reports = reports
+	return r, nil
+}
+
+// List 返回全部报告，从新到旧排序
+func (s *Store) List() []Report {
+	s.mu.Lock()
+	list := append([]Report{}, s.reports...)
+	s.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Time.After(list[j].Time)
+	})
+	return list
+}
+
+// Read 返回报告正文
+func (s *Store) Read(id string) (string, error) {
+	s.mu.Lock()
+	found := s.indexLocked(id) >= 0
+	s.mu.Unlock()
+	if !found {
+		return "", ErrNotFound
+	}
+	data, err := os.ReadFile(s.path(id))
+	return string(data), err
+}
+
+// Delete 删除报告及其文件
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.indexLocked(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	reports := append(append([]Report{}, s.reports[:i]...), s.reports[i+1:]...)
+	if err := s.saveLocked(reports); err != nil {
+		return err
+	}
+	s.reports = reports
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *Store) indexLocked(id string) int {
+	for i, r := range s.reports {
+		if r.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+reportExt)
+}
+
+func (s *Store) saveLocked(reports []Report) error {
+	data, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(s.dir, indexFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, indexFile))
+}
+
+// Format 生成报告正文：会话与设备信息的文件头，之后是崩溃前后的输出
+func Format(r Report, lines []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Crash report %s\n", r.ID)
+	fmt.Fprintf(&b, "Time:       %s\n", r.Time.Format(time.RFC3339Nano))
+	fmt.Fprintf(&b, "Trigger:    %s\n", r.Trigger)
+	if r.Title != "" {
+		fmt.Fprintf(&b, "Session:    %s\n", r.Title)
+	}
+	if r.Connection != "" {
+		fmt.Fprintf(&b, "Connection: %s\n", r.Connection)
+	}
+	if r.Capture != "" {
+		fmt.Fprintf(&b, "Capture:    %s\n", r.Capture)
+	}
+	keys := make([]string, 0, len(r.Device))
+	for k := range r.Device {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		b.WriteString("Device:\n")
+	}
+	for _, k := range keys {
+		fmt.Fprintf(&b, "  %-10s %s\n", k+":", r.Device[k])
+	}
+	b.WriteString("\n")
+	for _, l := range lines {
+		b.WriteString(l)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
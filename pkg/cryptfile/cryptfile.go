@@ -0,0 +1,260 @@
+// Package cryptfile 分块 AES-256-GCM 加密的追加写入流
+//
+// 流格式：8 字节随机 nonce 前缀，随后是若干块 uint32 密文长度 (小端序) | 密文
+// 每块的 nonce 为前缀 + 块序号，块被调换或删除时解密失败；
+// 最后一块不完整 (写入时崩溃) 按流结束处理，与抓包文件的约定一致
+package cryptfile
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// SaltSize 口令派生密钥使用的盐长度
+const SaltSize = 16
+
+// KeySize 密钥长度 (AES-256)
+const KeySize = 32
+
+// ChunkSize 每块明文的最大长度
+const ChunkSize = 64 << 10
+
+// prefixSize 流 nonce 前缀长度，余下 4 字节为块序号
+const prefixSize = 8
+
+// BlockOverhead SealBlock 相对明文增加的字节数 (nonce + 认证标签)
+const BlockOverhead = 12 + 16
+
+// scrypt 参数，派生一次约 50~100 ms
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// ErrWrongKey 口令错误或数据被篡改
+var ErrWrongKey = errors.New("cryptfile: wrong passphrase or corrupted data")
+
+// NewSalt 生成随机盐
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// keyCacheSize 派生密钥缓存的最大条目数，超出时清空
+const keyCacheSize = 256
+
+var (
+	keyMutex sync.Mutex
+	keyCache = map[[sha256.Size]byte][]byte{}
+)
+
+// DeriveKey 用 scrypt 从口令和盐派生密钥
+// 结果按 (口令, 盐) 缓存，列出大量加密文件时不必逐个重新派生
+func DeriveKey(passphrase string, salt []byte) ([]byte, error) {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte{0})
+	h.Write([]byte(passphrase))
+	var id [sha256.Size]byte
+	h.Sum(id[:0])
+
+	keyMutex.Lock()
+	key, ok := keyCache[id]
+	keyMutex.Unlock()
+	if ok {
+		return key, nil
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, KeySize)
+	if err != nil {
+		return nil, err
+	}
+	keyMutex.Lock()
+	if len(keyCache) >= keyCacheSize {
+		clear(keyCache)
+	}
+	keyCache[id] = key
+	keyMutex.Unlock()
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// SealBlock 用随机 nonce 加密一个独立的数据块，结果为 nonce | 密文
+func SealBlock(key, plaintext []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(out); err != nil {
+		return nil, err
+	}
+	return aead.Seal(out, out, plaintext, nil), nil
+}
+
+// OpenBlock 解密 SealBlock 的结果
+func OpenBlock(key, block []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(block) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrWrongKey
+	}
+	n := aead.NonceSize()
+	plain, err := aead.Open(nil, block[:n], block[n:], nil)
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return plain, nil
+}
+
+// Writer 加密写入流，明文攒满 ChunkSize 或调用 Flush 时写出一块，非并发安全
+type Writer struct {
+	w     io.Writer
+	aead  cipher.AEAD
+	nonce [12]byte
+	seq   uint32
+	buf   []byte
+	out   []byte
+}
+
+// NewWriter 写入 nonce 前缀并返回加密写入流，关闭时不关闭 w
+func NewWriter(w io.Writer, key []byte) (*Writer, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	cw := &Writer{w: w, aead: aead, buf: make([]byte, 0, ChunkSize)}
+	if _, err := rand.Read(cw.nonce[:prefixSize]); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(cw.nonce[:prefixSize]); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+// Write 缓冲明文，满一块时加密写出
+func (w *Writer) Write(p []byte) (int, error) {
+	n := 0
+	for len(p) > 0 {
+		k := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+k]
+		p = p[k:]
+		n += k
+		if len(w.buf) == cap(w.buf) {
+			if err := w.Flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+// Flush 将缓冲的明文加密为一块写出，没有缓冲数据时不写
+func (w *Writer) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	binary.LittleEndian.PutUint32(w.nonce[prefixSize:], w.seq)
+	w.out = append(w.out[:0], 0, 0, 0, 0)
+	w.out = w.aead.Seal(w.out, w.nonce[:], w.buf, nil)
+	binary.LittleEndian.PutUint32(w.out[:4], uint32(len(w.out)-4))
+	if _, err := w.w.Write(w.out); err != nil {
+		return err
+	}
+	w.seq++
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// Close 写出剩余数据
+func (w *Writer) Close() error {
+	return w.Flush()
+}
+
+// Reader 解密读取流
+type Reader struct {
+	r     io.Reader
+	aead  cipher.AEAD
+	nonce [12]byte
+	seq   uint32
+	block []byte
+	plain []byte
+	err   error
+}
+
+// NewReader 读取 nonce 前缀并返回解密读取流
+func NewReader(r io.Reader, key []byte) (*Reader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	cr := &Reader{r: r, aead: aead}
+	if _, err := io.ReadFull(r, cr.nonce[:prefixSize]); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// Read 实现 io.Reader，块认证失败时返回 ErrWrongKey，末尾不完整的块返回 io.ErrUnexpectedEOF
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.plain) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.err = r.next()
+	}
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	return n, nil
+}
+
+// next 读取并解密下一块
+func (r *Reader) next() error {
+	var hdr [4]byte
+	if _, err := io.ReadFull(r.r, hdr[:]); err != nil {
+		return err
+	}
+	n := binary.LittleEndian.Uint32(hdr[:])
+	if n < uint32(r.aead.Overhead()) || n > ChunkSize+uint32(r.aead.Overhead()) {
+		return ErrWrongKey
+	}
+	if cap(r.block) < int(n) {
+		r.block = make([]byte, n)
+	}
+	block := r.block[:n]
+	if _, err := io.ReadFull(r.r, block); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	binary.LittleEndian.PutUint32(r.nonce[prefixSize:], r.seq)
+	plain, err := r.aead.Open(block[:0], r.nonce[:], block, nil)
+	if err != nil {
+		return ErrWrongKey
+	}
+	r.seq++
+	r.plain = plain
+	return nil
+}
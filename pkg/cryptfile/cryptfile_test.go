@@ -0,0 +1,101 @@
+package cryptfile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func testKey(t *testing.T) []byte {
+	t.Helper()
+	key, err := DeriveKey("correct horse", []byte("0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestDeriveKeyCached(t *testing.T) {
+	a := testKey(t)
+	b := testKey(t)
+	if len(a) != KeySize || !bytes.Equal(a, b) {
+		t.Fatalf("Unexpected keys %x %x", a, b)
+	}
+	c, _ := DeriveKey("correct horse", []byte("fedcba9876543210"))
+	if bytes.Equal(a, c) {
+		t.Error("Different salt should give a different key")
+	}
+}
+
+func TestStreamRoundTrip(t *testing.T) {
+	key := testKey(t)
+	var file bytes.Buffer
+	w, err := NewWriter(&file, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("0123456789"), ChunkSize/5)
+	w.Write(data[:100])
+	w.Flush()
+	w.Write(data[100:])
+	w.Close()
+	if bytes.Contains(file.Bytes(), []byte("0123456789")) {
+		t.Error("Plaintext leaked into the stream")
+	}
+
+	r, err := NewReader(bytes.NewReader(file.Bytes()), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(r)
+	if err != nil || !bytes.Equal(got, data) {
+		t.Fatalf("Round trip failed: %d bytes, %v", len(got), err)
+	}
+}
+
+func TestStreamTruncatedAndTampered(t *testing.T) {
+	key := testKey(t)
+	var file bytes.Buffer
+	w, _ := NewWriter(&file, key)
+	w.Write([]byte("first"))
+	w.Flush()
+	w.Write([]byte("second"))
+	w.Flush()
+
+	b := file.Bytes()
+	r, _ := NewReader(bytes.NewReader(b[:len(b)-3]), key)
+	got, err := io.ReadAll(r)
+	if string(got) != "first" || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Truncated: got %q, %v", got, err)
+	}
+
+	tampered := bytes.Clone(b)
+	tampered[prefixSize+6] ^= 1
+	r, _ = NewReader(bytes.NewReader(tampered), key)
+	if _, err := io.ReadAll(r); err != ErrWrongKey {
+		t.Errorf("Tampered: expected ErrWrongKey, got %v", err)
+	}
+
+	other, _ := DeriveKey("wrong", []byte("0123456789abcdef"))
+	r, _ = NewReader(bytes.NewReader(b), other)
+	if _, err := io.ReadAll(r); err != ErrWrongKey {
+		t.Errorf("Wrong key: expected ErrWrongKey, got %v", err)
+	}
+}
+
+func TestBlock(t *testing.T) {
+	key := testKey(t)
+	sealed, err := SealBlock(key, []byte("meta"))
+	if err != nil || len(sealed) != len("meta")+BlockOverhead {
+		t.Fatalf("Unexpected block %x, %v", sealed, err)
+	}
+	plain, err := OpenBlock(key, sealed)
+	if err != nil || string(plain) != "meta" {
+		t.Errorf("Unexpected plaintext %q, %v", plain, err)
+	}
+	sealed[len(sealed)-1] ^= 1
+	if _, err := OpenBlock(key, sealed); err != ErrWrongKey {
+		t.Errorf("Expected ErrWrongKey, got %v", err)
+	}
+}
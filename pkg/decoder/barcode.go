@@ -0,0 +1,201 @@
+package decoder
+
+import (
+	"bytes"
+	"time"
+)
+
+func init() {
+	Register(Info{Name: "barcode", Description: "Barcode/QR scanner output (AIM symbology identifiers)", Source: "builtin"}, func() Decoder { return &barcodeDecoder{} })
+}
+
+// BarcodeDedupWindow 同一条码在该时间内再次读到时视为重复读取
+const BarcodeDedupWindow = time.Second
+
+// maxBarcodeLength 超过该长度仍未遇到结束符时丢弃 (二维码内容可能较长)
+const maxBarcodeLength = 8192
+
+// aimSymbologies AIM 符号标识符 ("]" + 代码字符 + 修饰符) 的代码字符对应的码制
+var aimSymbologies = map[byte]string{
+	'A': "Code 39",
+	'C': "Code 128",
+	'E': "EAN/UPC",
+	'F': "Codabar",
+	'G': "Code 93",
+	'I': "Interleaved 2 of 5",
+	'L': "PDF417",
+	'Q': "QR Code",
+	'd': "Data Matrix",
+	'e': "GS1 DataBar",
+	'z': "Aztec",
+	'X': "Other",
+}
+
+// Scan 一次扫码结果 (由 barcode 解码器的帧得到)
+type Scan struct {
+	Time      time.Time `json:"time"`
+	Symbology string    `json:"symbology"`     // 码制，无法判断时为空
+	AIM       string    `json:"aim,omitempty"` // 原始 AIM 标识符，如 "]E0"
+	Data      string    `json:"data"`
+}
+
+// AsScan 从 barcode 帧取出扫码结果，重复读取或非 barcode 帧返回 false
+func AsScan(f Frame) (Scan, bool) {
+	if f.Protocol != "barcode" {
+		return Scan{}, false
+	}
+	if dup, _ := f.Get("barcode.duplicate"); dup == true {
+		return Scan{}, false
+	}
+	s := Scan{Time: f.Time}
+	if v, ok := f.Get("barcode.symbology"); ok {
+		s.Symbology, _ = v.(string)
+	}
+	if v, ok := f.Get("barcode.aim"); ok {
+		s.AIM, _ = v.(string)
+	}
+	if v, ok := f.Get("barcode.data"); ok {
+		s.Data, _ = v.(string)
+	}
+	return s, true
+}
+
+// barcodeDecoder 扫码枪输出：可选的 STX 前缀，以 CR、LF、Tab 或 ETX 结尾
+type barcodeDecoder struct {
+	buf      []byte
+	last     string
+	lastTime time.Time
+}
+
+func (d *barcodeDecoder) Name() string { return "barcode" }
+
+// Detect 带 AIM 标识符或校验位正确的 EAN/UPC 的行占多数时认为是扫码数据
+func (d *barcodeDecoder) Detect(sample []byte) float64 {
+	lines, matched := 0, 0
+	for _, raw := range bytes.FieldsFunc(sample, isBarcodeTerminator) {
+		line := bytes.TrimPrefix(raw, []byte{0x02})
+		if len(line) == 0 {
+			continue
+		}
+		lines++
+		for _, c := range line {
+			if c < 0x20 && c != 0x1d || c == 0x7f {
+				return 0
+			}
+		}
+		if aim, _ := splitAIM(line); aim != "" || guessSymbology(string(line)) != "" {
+			matched++
+		}
+	}
+	if lines == 0 || bytes.IndexAny(sample, "\r\n\t\x03") < 0 {
+		return 0
+	}
+	return 0.8 * float64(matched) / float64(lines)
+}
+
+func isBarcodeTerminator(r rune) bool {
+	return r == '\r' || r == '\n' || r == '\t' || r == 0x03
+}
+
+func (d *barcodeDecoder) Decode(t time.Time, data []byte, emit func(Frame)) {
+	for _, c := range data {
+		if isBarcodeTerminator(rune(c)) {
+			if len(d.buf) > 0 {
+				d.emit(t, d.buf, emit)
+				d.buf = d.buf[:0]
+			}
+			continue
+		}
+		if c == 0x02 && len(d.buf) == 0 {
+			continue
+		}
+		if len(d.buf) < maxBarcodeLength {
+			d.buf = append(d.buf, c)
+		}
+	}
+}
+
+func (d *barcodeDecoder) emit(t time.Time, raw []byte, emit func(Frame)) {
+	aim, body := splitAIM(raw)
+	data := string(body)
+	symbology := ""
+	if aim != "" {
+		symbology = aimSymbologies[aim[1]]
+		if aim == "]C1" {
+			symbology = "GS1-128"
+		}
+	} else {
+		symbology = guessSymbology(data)
+	}
+
+	duplicate := data == d.last && t.Sub(d.lastTime) < BarcodeDedupWindow
+	d.last, d.lastTime = data, t
+
+	summary := data
+	if symbology != "" {
+		summary = symbology + ": " + data
+	}
+	if duplicate {
+		summary += " (duplicate)"
+	}
+	emit(Frame{
+		Protocol: "barcode",
+		Time:     t,
+		Raw:      clone(raw),
+		Summary:  summary,
+		Fields: []Field{
+			{Name: "barcode.symbology", Value: symbology},
+			{Name: "barcode.aim", Value: aim},
+			{Name: "barcode.data", Value: data},
+			{Name: "barcode.duplicate", Value: duplicate},
+		},
+	})
+}
+
+// splitAIM 拆出开头的 AIM 符号标识符
+func splitAIM(line []byte) (string, []byte) {
+	if len(line) >= 3 && line[0] == ']' {
+		if _, ok := aimSymbologies[line[1]]; ok {
+			return string(line[:3]), line[3:]
+		}
+	}
+	return "", line
+}
+
+// guessSymbology 没有 AIM 标识符时按长度与校验位识别 EAN/UPC
+func guessSymbology(data string) string {
+	if !validGTIN(data) {
+		return ""
+	}
+	switch len(data) {
+	case 8:
+		return "EAN-8"
+	case 12:
+		return "UPC-A"
+	case 13:
+		return "EAN-13"
+	case 14:
+		return "ITF-14"
+	}
+	return ""
+}
+
+// validGTIN 检查 GTIN (EAN/UPC) 的校验位
+func validGTIN(s string) bool {
+	if len(s) != 8 && len(s) != 12 && len(s) != 13 && len(s) != 14 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		c := s[len(s)-1-i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		n := int(c - '0')
+		if i%2 == 1 {
+			n *= 3
+		}
+		sum += n
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,169 @@
+package decoder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Field 帧中解析出的一个字段，Name 采用 "协议.字段" 形式 (如 modbus.func)
+// Value 为 int64、float64、string 或 bool
+type Field struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// Frame 解码出的一帧
+type Frame struct {
+	Protocol string    `json:"protocol"`
+	Time     time.Time `json:"time"`
+	Raw      []byte    `json:"raw"`
+	Summary  string    `json:"summary"`
+	Fields   []Field   `json:"fields"`
+	Error    string    `json:"error,omitempty"` // 校验失败等，帧仍然输出
+}
+
+// Get 返回指定名称的字段值
+func (f *Frame) Get(name string) (interface{}, bool) {
+	for _, field := range f.Fields {
+		if field.Name == name {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Decoder 协议解码器。每个会话使用独立的实例，实例可以在多次 Decode 之间保存状态 (如未完成的帧)
+type Decoder interface {
+	// Name 协议名，同时作为字段名前缀
+	Name() string
+	// Detect 判断样本数据属于该协议的可信度 (0~1)，不应修改实例状态
+	Detect(sample []byte) float64
+	// Decode 处理一段接收数据，t 为数据到达时间；每解出一帧调用一次 emit
+	Decode(t time.Time, data []byte, emit func(Frame))
+}
+
+// Factory 创建解码器实例
+type Factory func() Decoder
+
+// Info 已注册解码器的描述
+type Info struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Source      string `json:"source"` // builtin、plugin 或 packetdef
+}
+
+var (
+	mu        sync.RWMutex
+	factories = map[string]Factory{}
+	infos     = map[string]Info{}
+)
+
+// ErrUnknown 未注册的解码器
+var ErrUnknown = errors.New("decoder: unknown decoder")
+
+// Register 注册解码器，同名的解码器会被替换
+func Register(info Info, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	factories[info.Name] = factory
+	infos[info.Name] = info
+}
+
+// New 创建指定名称的解码器实例
+func New(name string) (Decoder, error) {
+	mu.RLock()
+	factory, ok := factories[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknown, name)
+	}
+	return factory(), nil
+}
+
+// List 返回已注册的解码器，按名称排序
+func List() []Info {
+	mu.RLock()
+	defer mu.RUnlock()
+	list := make([]Info, 0, len(infos))
+	for _, info := range infos {
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Set 一个会话启用的解码器集合
+type Set struct {
+	mu       sync.Mutex
+	decoders []Decoder
+}
+
+// Enable 启用解码器，已启用时不重复创建
+func (s *Set) Enable(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range s.decoders {
+		if d.Name() == name {
+			return nil
+		}
+	}
+	d, err := New(name)
+	if err != nil {
+		return err
+	}
+	s.decoders = append(s.decoders, d)
+	return nil
+}
+
+// Disable 停用解码器
+func (s *Set) Disable(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, d := range s.decoders {
+		if d.Name() == name {
+			s.decoders = append(s.decoders[:i], s.decoders[i+1:]...)
+			return
+		}
+	}
+}
+
+// Names 返回已启用的解码器名称
+func (s *Set) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, len(s.decoders))
+	for i, d := range s.decoders {
+		names[i] = d.Name()
+	}
+	return names
+}
+
+// Reset 丢弃所有解码器的状态 (如重新连接后)，保持启用的集合不变
+func (s *Set) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, d := range s.decoders {
+		if fresh, err := New(d.Name()); err == nil {
+			s.decoders[i] = fresh
+		}
+	}
+}
+
+// Decode 将数据交给所有启用的解码器
+func (s *Set) Decode(t time.Time, data []byte, emit func(Frame)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range s.decoders {
+		d.Decode(t, data, emit)
+	}
+}
+
+// clone 复制数据，帧中的 Raw 不能引用调用方的缓冲区
+func clone(b []byte) []byte {
+	return append([]byte(nil), b...)
+}
@@ -0,0 +1,389 @@
+package decoder
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// collect 解码数据分片并收集输出的帧
+func collect(d Decoder, chunks ...[]byte) []Frame {
+	var frames []Frame
+	t := time.Unix(0, 0)
+	for _, c := range chunks {
+		d.Decode(t, c, func(f Frame) { frames = append(frames, f) })
+	}
+	return frames
+}
+
+func field(t *testing.T, f Frame, name string) interface{} {
+	t.Helper()
+	v, ok := f.Get(name)
+	if !ok {
+		t.Fatalf("Frame %+v has no field %s", f, name)
+	}
+	return v
+}
+
+func TestBuiltinsRegistered(t *testing.T) {
+	names := map[string]bool{}
+	for _, info := range List() {
+		names[info.Name] = true
+	}
+	for _, want := range []string{"text", "nmea", "modbus", "slip", "mavlink", "barcode", "dnp3", "iec101", "modbus_ascii"} {
+		if !names[want] {
+			t.Errorf("Builtin decoder %s not registered", want)
+		}
+	}
+	if _, err := New("nope"); !errors.Is(err, ErrUnknown) {
+		t.Errorf("Expected ErrUnknown, got %v", err)
+	}
+}
+
+func TestSetEnableDisable(t *testing.T) {
+	var s Set
+	if err := s.Enable("text"); err != nil {
+		t.Fatal(err)
+	}
+	s.Enable("text")
+	s.Enable("nmea")
+	if names := s.Names(); len(names) != 2 {
+		t.Errorf("Expected 2 decoders, got %v", names)
+	}
+	s.Disable("text")
+	var frames []Frame
+	s.Decode(time.Now(), []byte("$GPGGA,,,,,,0,,,,,,,,*66\r\n"), func(f Frame) { frames = append(frames, f) })
+	if len(frames) != 1 || frames[0].Protocol != "nmea" {
+		t.Errorf("Expected one NMEA frame, got %+v", frames)
+	}
+}
+
+func TestTextSplitsAcrossChunks(t *testing.T) {
+	frames := collect(&textDecoder{}, []byte("hel"), []byte("lo\r\nwor"), []byte("ld\n"))
+	if len(frames) != 2 || frames[0].Summary != "hello" || frames[1].Summary != "world" {
+		t.Errorf("Unexpected frames: %+v", frames)
+	}
+}
+
+func TestNMEA(t *testing.T) {
+	line := "$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47\r\n"
+	frames := collect(&nmeaDecoder{}, []byte(line[:10]), []byte(line[10:]))
+	if len(frames) != 1 {
+		t.Fatalf("Expected one frame, got %d", len(frames))
+	}
+	f := frames[0]
+	if f.Error != "" || field(t, f, "nmea.type") != "GGA" || field(t, f, "nmea.satellites") != int64(8) {
+		t.Errorf("Unexpected frame: %+v", f)
+	}
+	if lat := field(t, f, "nmea.lat").(float64); lat < 48.1172 || lat > 48.1174 {
+		t.Errorf("Unexpected latitude %v", lat)
+	}
+
+	bad := collect(&nmeaDecoder{}, []byte("$GPGGA,123519*00\n"))
+	if len(bad) != 1 || bad[0].Error == "" {
+		t.Errorf("Expected checksum error, got %+v", bad)
+	}
+}
+
+// modbusFrame 为 PDU 加上 CRC
+func modbusFrame(b ...byte) []byte {
+	crc := modbusCRC(b)
+	return append(b, byte(crc), byte(crc>>8))
+}
+
+func TestModbus(t *testing.T) {
+	req := modbusFrame(0x01, 0x03, 0x00, 0x6B, 0x00, 0x03)
+	if req[6] != 0x74 || req[7] != 0x17 {
+		t.Fatalf("Unexpected CRC % X", req[6:])
+	}
+	resp := modbusFrame(0x01, 0x03, 0x06, 0x02, 0x2B, 0x00, 0x00, 0x00, 0x64)
+	exc := modbusFrame(0x01, 0x83, 0x02)
+
+	stream := append(append(append([]byte{}, req...), resp...), exc...)
+	frames := collect(&modbusDecoder{}, stream[:5], stream[5:])
+	if len(frames) != 3 {
+		t.Fatalf("Expected 3 frames, got %+v", frames)
+	}
+	if field(t, frames[0], "modbus.start") != int64(0x6B) || field(t, frames[0], "modbus.quantity") != int64(3) {
+		t.Errorf("Unexpected request: %+v", frames[0])
+	}
+	if field(t, frames[1], "modbus.byte_count") != int64(6) {
+		t.Errorf("Unexpected response: %+v", frames[1])
+	}
+	if field(t, frames[2], "modbus.exception") != int64(2) || field(t, frames[2], "modbus.func") != int64(3) {
+		t.Errorf("Unexpected exception: %+v", frames[2])
+	}
+}
+
+func TestModbusASCII(t *testing.T) {
+	req := ModbusASCIIEncode([]byte{0x01, 0x03, 0x00, 0x6B, 0x00, 0x03})
+	if string(req) != ":0103006B00038E\r\n" {
+		t.Fatalf("Unexpected encoding %q", req)
+	}
+	bad := []byte(":0103006B000300\r\n")
+	frames := collect(&modbusASCIIDecoder{}, []byte("noise"), req[:6], req[6:], bad)
+	if len(frames) != 2 {
+		t.Fatalf("Expected 2 frames, got %+v", frames)
+	}
+	if field(t, frames[0], "modbus_ascii.start") != int64(0x6B) || frames[0].Error != "" {
+		t.Errorf("Unexpected request: %+v", frames[0])
+	}
+	if frames[1].Error == "" {
+		t.Errorf("Expected an LRC error, got %+v", frames[1])
+	}
+}
+
+func TestModbusGapFlushesGarbage(t *testing.T) {
+	d := &modbusDecoder{}
+	var frames []Frame
+	emit := func(f Frame) { frames = append(frames, f) }
+	t0 := time.Unix(0, 0)
+	d.Decode(t0, []byte{0x01, 0x03, 0x00}, emit)
+	d.Decode(t0.Add(time.Second), modbusFrame(0x01, 0x06, 0x00, 0x01, 0x00, 0x03), emit)
+	if len(frames) != 2 || frames[0].Error == "" || frames[1].Error != "" {
+		t.Errorf("Expected garbage then a valid frame, got %+v", frames)
+	}
+}
+
+func TestSLIP(t *testing.T) {
+	frames := collect(&slipDecoder{}, []byte{slipEnd, 0x01, slipEsc, slipEscEnd, 0x02}, []byte{slipEnd, slipEnd})
+	if len(frames) != 1 || field(t, frames[0], "slip.payload") != "01c002" {
+		t.Errorf("Unexpected frames: %+v", frames)
+	}
+}
+
+func TestMAVLink(t *testing.T) {
+	v1 := []byte{mavlinkV1Magic, 2, 7, 1, 1, 0, 0xAA, 0xBB, 0, 0}
+	v2 := []byte{mavlinkV2Magic, 1, 0, 0, 8, 1, 1, 0x21, 0x01, 0x00, 0xCC, 0, 0}
+	frames := collect(&mavlinkDecoder{}, []byte{0x55}, v1, v2[:4], v2[4:])
+	if len(frames) != 2 {
+		t.Fatalf("Expected 2 frames, got %+v", frames)
+	}
+	if field(t, frames[0], "mavlink.seq") != int64(7) || field(t, frames[1], "mavlink.msgid") != int64(0x121) {
+		t.Errorf("Unexpected frames: %+v", frames)
+	}
+}
+
+// dnp3Frame 组装 DNP3 链路帧，为头部和每 16 字节用户数据追加 CRC
+func dnp3Frame(ctrl byte, dest, src uint16, user ...byte) []byte {
+	withCRC := func(b []byte) []byte {
+		crc := dnp3CRC(b)
+		return append(b, byte(crc), byte(crc>>8))
+	}
+	frame := withCRC([]byte{0x05, 0x64, byte(5 + len(user)), ctrl, byte(dest), byte(dest >> 8), byte(src), byte(src >> 8)})
+	for i := 0; i < len(user); i += dnp3BlockLen {
+		frame = append(frame, withCRC(clone(user[i:min(i+dnp3BlockLen, len(user))]))...)
+	}
+	return frame
+}
+
+func TestDNP3(t *testing.T) {
+	reset := dnp3Frame(0xC0, 1, 1024)
+	if reset[8] != 0xE9 || reset[9] != 0x21 {
+		t.Fatalf("Unexpected header CRC % X", reset[8:])
+	}
+	// 读 Class 0 请求与带 20 字节用户数据 (两个数据块) 的响应
+	read := dnp3Frame(0xC4, 1, 4, 0xC0, 0xC1, 0x01, 0x3C, 0x01, 0x06)
+	resp := dnp3Frame(0x44, 4, 1, append([]byte{0xC0, 0xC1, 0x81, 0x00, 0x00}, make([]byte, 15)...)...)
+	stream := append(append(append([]byte{0xFF}, reset...), read...), resp...)
+	frames := collect(&dnp3Decoder{}, stream[:7], stream[7:30], stream[30:])
+	if len(frames) != 3 {
+		t.Fatalf("Expected 3 frames, got %+v", frames)
+	}
+	if field(t, frames[0], "dnp3.func_name") != "RESET_LINK_STATES" || field(t, frames[0], "dnp3.src") != int64(1024) {
+		t.Errorf("Unexpected reset frame: %+v", frames[0])
+	}
+	if field(t, frames[1], "dnp3.app.func_name") != "READ" || frames[1].Error != "" {
+		t.Errorf("Unexpected read frame: %+v", frames[1])
+	}
+	if field(t, frames[2], "dnp3.app.func") != int64(129) || field(t, frames[2], "dnp3.app.iin") != int64(0) {
+		t.Errorf("Unexpected response frame: %+v", frames[2])
+	}
+
+	bad := dnp3Frame(0x44, 4, 1, 0xC0, 0xC1, 0x81, 0x00, 0x00)
+	bad[len(bad)-1] ^= 0xFF
+	frames = collect(&dnp3Decoder{}, bad)
+	if len(frames) != 1 || frames[0].Error == "" {
+		t.Errorf("Expected a data block CRC error, got %+v", frames)
+	}
+}
+
+// iec101Frame 组装 IEC 101 可变帧
+func iec101Frame(body ...byte) []byte {
+	frame := append([]byte{0x68, byte(len(body)), byte(len(body)), 0x68}, body...)
+	return append(frame, sum8(body), 0x16)
+}
+
+func TestIEC101(t *testing.T) {
+	// 2 字节链路地址的请求链路状态，随后是总召唤激活
+	status := []byte{0x10, 0x49, 0x01, 0x00, 0x4A, 0x16}
+	gi := iec101Frame(0x73, 0x01, 0x00, 100, 0x01, 0x06, 0x01, 0x00, 0x00, 0x14)
+	frames := collect(&iec101Decoder{addrLen: 1}, append(status, 0xE5), gi[:3], gi[3:])
+	if len(frames) != 3 {
+		t.Fatalf("Expected 3 frames, got %+v", frames)
+	}
+	if field(t, frames[0], "iec101.func_name") != "REQUEST_LINK_STATUS" || field(t, frames[0], "iec101.addr") != int64(1) {
+		t.Errorf("Unexpected fixed frame: %+v", frames[0])
+	}
+	if field(t, frames[1], "iec101.format") != "single" {
+		t.Errorf("Unexpected single character frame: %+v", frames[1])
+	}
+	if field(t, frames[2], "iec101.type_name") != "C_IC_NA_1" || field(t, frames[2], "iec101.cot_name") != "activation" ||
+		field(t, frames[2], "iec101.addr") != int64(1) || field(t, frames[2], "iec101.fcb") != true {
+		t.Errorf("Unexpected variable frame: %+v", frames[2])
+	}
+}
+
+func TestBarcode(t *testing.T) {
+	d := &barcodeDecoder{}
+	frames := collect(d, []byte("\x02]E040063813"), []byte("33931\x03]C1(01)09501101530003\r\n4006381333931\r\n"))
+	if len(frames) != 3 {
+		t.Fatalf("Expected 3 frames, got %+v", frames)
+	}
+	if field(t, frames[0], "barcode.symbology") != "EAN/UPC" || field(t, frames[0], "barcode.data") != "4006381333931" {
+		t.Errorf("Unexpected AIM frame %+v", frames[0])
+	}
+	if field(t, frames[1], "barcode.symbology") != "GS1-128" || field(t, frames[1], "barcode.aim") != "]C1" {
+		t.Errorf("Unexpected GS1 frame %+v", frames[1])
+	}
+	// 没有 AIM 标识符时按校验位识别
+	if field(t, frames[2], "barcode.symbology") != "EAN-13" {
+		t.Errorf("Expected EAN-13 guess, got %+v", frames[2])
+	}
+
+	// 一秒内的重复读取被标记，扫码结果只有一次
+	var scans []Scan
+	at := time.Unix(100, 0)
+	for _, dt := range []time.Duration{0, 200 * time.Millisecond, 2 * time.Second} {
+		d.Decode(at.Add(dt), []byte("ABC-1\r"), func(f Frame) {
+			if s, ok := AsScan(f); ok {
+				scans = append(scans, s)
+			}
+		})
+	}
+	if len(scans) != 2 || scans[0].Data != "ABC-1" || scans[0].Symbology != "" {
+		t.Errorf("Expected the double read to be dropped, got %+v", scans)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	nmea := []byte("0,M,,*47\r\n$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47\r\n$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47\r\n$GP")
+	var modbus []byte
+	for i := 0; i < 4; i++ {
+		modbus = append(modbus, modbusFrame(0x01, 0x03, 0x00, byte(i), 0x00, 0x01)...)
+	}
+	cases := []struct {
+		sample []byte
+		want   string
+	}{
+		{nmea, "nmea"},
+		{modbus, "modbus"},
+		{[]byte("boot ok\r\nversion 1.2\r\n"), "text"},
+		{[]byte("]E04006381333931\r\n]Q1https://example.com\r\n"), "barcode"},
+	}
+	for _, c := range cases {
+		best, score := "", 0.0
+		for _, info := range List() {
+			d, _ := New(info.Name)
+			if s := d.Detect(c.sample); s > score {
+				best, score = info.Name, s
+			}
+		}
+		if best != c.want {
+			t.Errorf("Expected %s to win, got %s (%.2f)", c.want, best, score)
+		}
+	}
+}
+
+func TestSamplerDetectsOnce(t *testing.T) {
+	s := NewSampler(32)
+	line := []byte("$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47\r\n")
+	if _, ok := s.Add(line[:10]); ok {
+		t.Fatal("Sampler should wait for a full sample")
+	}
+	det, ok := s.Add(line[10:])
+	if !ok || det.Sampled != 32 {
+		t.Fatalf("Expected detection after 32 bytes, got %+v %v", det, ok)
+	}
+	if _, ok := s.Add(line); ok {
+		t.Error("Sampler should only detect once")
+	}
+	s.Reset()
+	if _, ok := s.Add(make([]byte, 40)); !ok {
+		t.Error("Sampler should detect again after Reset")
+	}
+}
+
+func TestDetectEmpty(t *testing.T) {
+	if det := Detect([]byte{0x00, 0x01, 0x02}); det.Protocol != "" {
+		t.Errorf("Expected no suggestion for noise, got %+v", det)
+	}
+}
+
+func TestGapDetector(t *testing.T) {
+	g := NewGapDetector(500 * time.Millisecond)
+	t0 := time.Unix(0, 0)
+	if _, ok := g.Check(t0); ok {
+		t.Error("First data should not produce a gap")
+	}
+	if _, ok := g.Check(t0.Add(100 * time.Millisecond)); ok {
+		t.Error("Short pause should not produce a gap")
+	}
+	f, ok := g.Check(t0.Add(1600 * time.Millisecond))
+	if !ok || field(t, f, "gap.duration_ms") != int64(1500) {
+		t.Errorf("Expected a 1500ms gap, got %+v", f)
+	}
+	g.Reset()
+	if _, ok := g.Check(t0.Add(time.Hour)); ok {
+		t.Error("Gap should not span a reset")
+	}
+	g.SetThreshold(0)
+	if _, ok := g.Check(t0.Add(2 * time.Hour)); ok {
+		t.Error("Zero threshold should disable detection")
+	}
+}
+
+func TestLineAssembler(t *testing.T) {
+	frames := make(chan Frame, 10)
+	l := NewLineAssembler(20*time.Millisecond, func(f Frame) { frames <- f })
+	next := func() Frame {
+		t.Helper()
+		select {
+		case f := <-frames:
+			return f
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for a line")
+			return Frame{}
+		}
+	}
+
+	t0 := time.Unix(100, 0)
+	l.Feed(t0, []byte("boot ok\r\nlog"))
+	l.Feed(t0.Add(time.Millisecond), []byte("in: "))
+	f := next()
+	if f.Summary != "boot ok" || field(t, f, "line.incomplete") != false || !f.Time.Equal(t0) {
+		t.Errorf("Unexpected first line %+v", f)
+	}
+	// 提示符没有换行，超时后作为不完整的行输出，时间为第一个字节的到达时间
+	f = next()
+	if f.Summary != "login: " || field(t, f, "line.incomplete") != true || !f.Time.Equal(t0) {
+		t.Errorf("Unexpected partial line %+v", f)
+	}
+	l.Feed(t0.Add(time.Second), []byte("root\n"))
+	f = next()
+	if f.Summary != "root" || field(t, f, "line.incomplete") != false || field(t, f, "line.continued") != true {
+		t.Errorf("Unexpected continuation %+v", f)
+	}
+
+	l.SetTimeout(0)
+	l.Feed(t0, []byte("# "))
+	select {
+	case f := <-frames:
+		t.Fatalf("Partial line should wait without a timeout, got %+v", f)
+	case <-time.After(50 * time.Millisecond):
+	}
+	l.Flush()
+	if f = next(); f.Summary != "# " || field(t, f, "line.incomplete") != true {
+		t.Errorf("Flush should emit the partial line, got %+v", f)
+	}
+}
@@ -0,0 +1,77 @@
+package decoder
+
+import "sort"
+
+// Score 某个解码器对样本的可信度
+type Score struct {
+	Protocol   string  `json:"protocol"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Detection 协议检测结果，Scores 按可信度从高到低排列
+type Detection struct {
+	Protocol   string  `json:"protocol"` // 可信度最高的协议，低于 MinConfidence 时为空
+	Confidence float64 `json:"confidence"`
+	Scores     []Score `json:"scores"`
+	Sampled    int     `json:"sampled"` // 参与检测的字节数
+}
+
+// MinConfidence 低于该可信度的结果不作为建议
+const MinConfidence = 0.5
+
+// Detect 用所有已注册的解码器评估样本
+func Detect(sample []byte) Detection {
+	det := Detection{Sampled: len(sample)}
+	for _, info := range List() {
+		d, err := New(info.Name)
+		if err != nil {
+			continue
+		}
+		if c := d.Detect(sample); c > 0 {
+			det.Scores = append(det.Scores, Score{Protocol: info.Name, Confidence: c})
+		}
+	}
+	sort.SliceStable(det.Scores, func(i, j int) bool {
+		return det.Scores[i].Confidence > det.Scores[j].Confidence
+	})
+	if len(det.Scores) > 0 && det.Scores[0].Confidence >= MinConfidence {
+		det.Protocol = det.Scores[0].Protocol
+		det.Confidence = det.Scores[0].Confidence
+	}
+	return det
+}
+
+// Sampler 累积连接开始后的接收数据，达到样本大小时检测一次
+type Sampler struct {
+	size int
+	buf  []byte
+	done bool
+}
+
+// NewSampler 创建样本大小为 size 字节的采样器
+func NewSampler(size int) *Sampler {
+	return &Sampler{size: size, buf: make([]byte, 0, size)}
+}
+
+// Add 追加数据；样本刚好采满时返回检测结果与 true，之后的数据被忽略
+func (s *Sampler) Add(data []byte) (Detection, bool) {
+	if s.done {
+		return Detection{}, false
+	}
+	n := s.size - len(s.buf)
+	if n > len(data) {
+		n = len(data)
+	}
+	s.buf = append(s.buf, data[:n]...)
+	if len(s.buf) < s.size {
+		return Detection{}, false
+	}
+	s.done = true
+	return Detect(s.buf), true
+}
+
+// Reset 丢弃已采集的数据，重新开始采样
+func (s *Sampler) Reset() {
+	s.buf = s.buf[:0]
+	s.done = false
+}
@@ -0,0 +1,187 @@
+package decoder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+func init() {
+	Register(Info{Name: "dnp3", Description: "DNP3 link layer (serial)", Source: "builtin"}, func() Decoder { return &dnp3Decoder{} })
+}
+
+const (
+	// dnp3HeaderLen 起始字、长度、控制字、目的与源地址加头部 CRC
+	dnp3HeaderLen = 10
+	// dnp3BlockLen 用户数据每 16 字节附带一个 CRC
+	dnp3BlockLen = 16
+	// dnp3MaxFrame LEN 最大为 255 时的帧长度
+	dnp3MaxFrame = dnp3HeaderLen + 250 + 2*16
+)
+
+// dnp3LinkFuncs 链路层功能码，按 PRM 位区分主站与从站方向
+var dnp3LinkFuncs = [2]map[byte]string{
+	{0: "ACK", 1: "NACK", 11: "LINK_STATUS", 15: "NOT_SUPPORTED"},
+	{0: "RESET_LINK_STATES", 2: "TEST_LINK_STATES", 3: "CONFIRMED_USER_DATA", 4: "UNCONFIRMED_USER_DATA", 9: "REQUEST_LINK_STATUS"},
+}
+
+// dnp3AppFuncs 应用层功能码
+var dnp3AppFuncs = map[byte]string{
+	0: "CONFIRM", 1: "READ", 2: "WRITE", 3: "SELECT", 4: "OPERATE", 5: "DIRECT_OPERATE",
+	6: "DIRECT_OPERATE_NR", 7: "IMMED_FREEZE", 8: "IMMED_FREEZE_NR", 9: "FREEZE_CLEAR",
+	13: "COLD_RESTART", 14: "WARM_RESTART", 20: "ENABLE_UNSOLICITED", 21: "DISABLE_UNSOLICITED",
+	22: "ASSIGN_CLASS", 23: "DELAY_MEASURE", 24: "RECORD_CURRENT_TIME",
+	129: "RESPONSE", 130: "UNSOLICITED_RESPONSE",
+}
+
+// dnp3Decoder DNP3 链路层解码器
+// 帧以 0x05 0x64 开头，头部与每 16 字节用户数据各带一个 CRC；头部 CRC 错误时按字节重新同步
+type dnp3Decoder struct {
+	buf []byte
+}
+
+func (d *dnp3Decoder) Name() string { return "dnp3" }
+
+// Detect 按头部 CRC 正确的帧覆盖的字节比例计算可信度
+func (d *dnp3Decoder) Detect(sample []byte) float64 {
+	matched := 0
+	for i := 0; i+dnp3HeaderLen <= len(sample); {
+		if n := dnp3FrameLength(sample[i:]); n > 0 && i+n <= len(sample) {
+			matched += n
+			i += n
+			continue
+		}
+		i++
+	}
+	if len(sample) == 0 {
+		return 0
+	}
+	return float64(matched) / float64(len(sample))
+}
+
+func (d *dnp3Decoder) Decode(t time.Time, data []byte, emit func(Frame)) {
+	d.buf = append(d.buf, data...)
+	for len(d.buf) >= 2 {
+		if d.buf[0] != 0x05 || d.buf[1] != 0x64 {
+			d.buf = d.buf[:copy(d.buf, d.buf[1:])]
+			continue
+		}
+		if len(d.buf) < dnp3HeaderLen {
+			return
+		}
+		n := dnp3FrameLength(d.buf)
+		if n == 0 {
+			d.buf = d.buf[:copy(d.buf, d.buf[1:])]
+			continue
+		}
+		if n > len(d.buf) {
+			return
+		}
+		emit(parseDNP3(t, clone(d.buf[:n])))
+		d.buf = d.buf[:copy(d.buf, d.buf[n:])]
+	}
+}
+
+// dnp3FrameLength 头部有效时返回整帧长度，否则返回 0
+func dnp3FrameLength(b []byte) int {
+	if len(b) < dnp3HeaderLen || b[0] != 0x05 || b[1] != 0x64 || b[2] < 5 || !dnp3CRCValid(b[:dnp3HeaderLen]) {
+		return 0
+	}
+	user := int(b[2]) - 5
+	return dnp3HeaderLen + user + 2*((user+dnp3BlockLen-1)/dnp3BlockLen)
+}
+
+// dnp3CRC DNP3 CRC-16 (多项式 0x3D65 反序 0xA6BC，初值 0，结果取反)
+func dnp3CRC(b []byte) uint16 {
+	var crc uint16
+	for _, c := range b {
+		crc ^= uint16(c)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA6BC
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return ^crc
+}
+
+// dnp3CRCValid 校验块末尾的 CRC (低字节在前)
+func dnp3CRCValid(block []byte) bool {
+	n := len(block)
+	return n > 2 && dnp3CRC(block[:n-2]) == uint16(block[n-2])|uint16(block[n-1])<<8
+}
+
+func parseDNP3(t time.Time, raw []byte) Frame {
+	ctrl := raw[3]
+	dest := int64(raw[4]) | int64(raw[5])<<8
+	src := int64(raw[6]) | int64(raw[7])<<8
+	dir, prm := ctrl&0x80 != 0, ctrl&0x40 != 0
+	fn := ctrl & 0x0F
+	name := dnp3LinkFuncs[boolIndex(prm)][fn]
+	if name == "" {
+		name = fmt.Sprintf("FUNC_%d", fn)
+	}
+	f := Frame{
+		Protocol: "dnp3",
+		Time:     t,
+		Raw:      raw,
+		Fields: []Field{
+			{Name: "dnp3.dir", Value: dir},
+			{Name: "dnp3.prm", Value: prm},
+			{Name: "dnp3.func", Value: int64(fn)},
+			{Name: "dnp3.func_name", Value: name},
+			{Name: "dnp3.dest", Value: dest},
+			{Name: "dnp3.src", Value: src},
+		},
+	}
+	f.Summary = fmt.Sprintf("%d -> %d %s", src, dest, name)
+
+	// 去掉各块的 CRC 得到用户数据
+	var user []byte
+	for i := dnp3HeaderLen; i < len(raw); i += dnp3BlockLen + 2 {
+		end := min(i+dnp3BlockLen+2, len(raw))
+		if !dnp3CRCValid(raw[i:end]) {
+			f.Error = fmt.Sprintf("CRC mismatch in data block at offset %d", i)
+		}
+		user = append(user, raw[i:end-2]...)
+	}
+	if len(user) == 0 {
+		return f
+	}
+	th := user[0]
+	fir, fin := th&0x40 != 0, th&0x80 != 0
+	f.Fields = append(f.Fields,
+		Field{Name: "dnp3.transport.fir", Value: fir},
+		Field{Name: "dnp3.transport.fin", Value: fin},
+		Field{Name: "dnp3.transport.seq", Value: int64(th & 0x3F)},
+	)
+	// 应用层头部只出现在分段的第一段
+	if fir && len(user) >= 3 {
+		afn := user[2]
+		aname := dnp3AppFuncs[afn]
+		if aname == "" {
+			aname = fmt.Sprintf("APP_%d", afn)
+		}
+		f.Fields = append(f.Fields,
+			Field{Name: "dnp3.app.seq", Value: int64(user[1] & 0x0F)},
+			Field{Name: "dnp3.app.func", Value: int64(afn)},
+			Field{Name: "dnp3.app.func_name", Value: aname},
+		)
+		f.Summary += " " + aname
+		// 响应在功能码之后带 2 字节内部指示 (IIN)
+		if (afn == 129 || afn == 130) && len(user) >= 5 {
+			f.Fields = append(f.Fields, Field{Name: "dnp3.app.iin", Value: int64(user[3])<<8 | int64(user[4])})
+		}
+	}
+	f.Fields = append(f.Fields, Field{Name: "dnp3.data", Value: hex.EncodeToString(user)})
+	return f
+}
+
+func boolIndex(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
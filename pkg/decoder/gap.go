@@ -0,0 +1,67 @@
+package decoder
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// GapProtocol 间隔标记帧的协议名
+const GapProtocol = "gap"
+
+// GapDetector 检测接收数据之间超过阈值的停顿，可并发使用
+type GapDetector struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	last      time.Time
+}
+
+// NewGapDetector 创建检测器，threshold 为 0 表示不检测
+func NewGapDetector(threshold time.Duration) *GapDetector {
+	return &GapDetector{threshold: threshold}
+}
+
+// SetThreshold 修改阈值，0 表示不检测
+func (g *GapDetector) SetThreshold(threshold time.Duration) {
+	g.mu.Lock()
+	g.threshold = threshold
+	g.mu.Unlock()
+}
+
+// Threshold 返回当前阈值
+func (g *GapDetector) Threshold() time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.threshold
+}
+
+// Check 记录一次在 t 时刻到达的数据；与上次数据的间隔超过阈值时返回标记帧
+func (g *GapDetector) Check(t time.Time) (Frame, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	last := g.last
+	g.last = t
+	if g.threshold <= 0 || last.IsZero() {
+		return Frame{}, false
+	}
+	d := t.Sub(last)
+	if d < g.threshold {
+		return Frame{}, false
+	}
+	return Frame{
+		Protocol: GapProtocol,
+		Time:     t,
+		Summary:  fmt.Sprintf("no data for %s", d.Round(time.Millisecond)),
+		Fields: []Field{
+			{Name: "gap.duration_ms", Value: d.Milliseconds()},
+			{Name: "gap.since", Value: last.Format(time.RFC3339Nano)},
+		},
+	}, true
+}
+
+// Reset 忘记上次数据的时间 (如重新连接后)，下一次数据不会产生标记
+func (g *GapDetector) Reset() {
+	g.mu.Lock()
+	g.last = time.Time{}
+	g.mu.Unlock()
+}
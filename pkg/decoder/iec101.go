@@ -0,0 +1,208 @@
+package decoder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+func init() {
+	Register(Info{Name: "iec101", Description: "IEC 60870-5-101 FT1.2 link layer", Source: "builtin"}, func() Decoder { return &iec101Decoder{addrLen: 1} })
+}
+
+const (
+	iec101Ack      = 0xE5
+	iec101Fixed    = 0x10
+	iec101Variable = 0x68
+	iec101End      = 0x16
+)
+
+// iec101LinkFuncs 链路层功能码，按 PRM 位区分启动站与从动站
+var iec101LinkFuncs = [2]map[byte]string{
+	{0: "ACK", 1: "NACK", 8: "USER_DATA", 9: "NACK_NO_DATA", 11: "LINK_STATUS", 14: "LINK_NOT_FUNCTIONING", 15: "LINK_NOT_IMPLEMENTED"},
+	{0: "RESET_LINK", 1: "RESET_PROCESS", 2: "TEST_LINK", 3: "USER_DATA_CONFIRMED", 4: "USER_DATA_UNCONFIRMED",
+		8: "REQUEST_ACCESS_DEMAND", 9: "REQUEST_LINK_STATUS", 10: "REQUEST_CLASS_1", 11: "REQUEST_CLASS_2"},
+}
+
+// iec101Types 常用的 ASDU 类型标识
+var iec101Types = map[byte]string{
+	1: "M_SP_NA_1", 3: "M_DP_NA_1", 5: "M_ST_NA_1", 7: "M_BO_NA_1", 9: "M_ME_NA_1", 11: "M_ME_NB_1",
+	13: "M_ME_NC_1", 15: "M_IT_NA_1", 21: "M_ME_ND_1", 30: "M_SP_TB_1", 31: "M_DP_TB_1",
+	34: "M_ME_TD_1", 35: "M_ME_TE_1", 36: "M_ME_TF_1", 45: "C_SC_NA_1", 46: "C_DC_NA_1",
+	47: "C_RC_NA_1", 48: "C_SE_NA_1", 49: "C_SE_NB_1", 50: "C_SE_NC_1", 70: "M_EI_NA_1",
+	100: "C_IC_NA_1", 101: "C_CI_NA_1", 102: "C_RD_NA_1", 103: "C_CS_NA_1", 104: "C_TS_NA_1",
+	105: "C_RP_NA_1", 106: "C_CD_NA_1",
+}
+
+// iec101Causes 传送原因
+var iec101Causes = map[byte]string{
+	1: "periodic", 2: "background", 3: "spontaneous", 4: "initialized", 5: "request",
+	6: "activation", 7: "activation_con", 8: "deactivation", 9: "deactivation_con",
+	10: "activation_term", 11: "return_remote", 12: "return_local", 20: "interrogated",
+	37: "counter_interrogated", 44: "unknown_type", 45: "unknown_cause", 46: "unknown_asdu_addr",
+	47: "unknown_ioa",
+}
+
+// iec101Decoder IEC 60870-5-101 FT1.2 帧解码器
+// 链路地址可为 1 或 2 字节：按固定帧的校验推断，可变帧沿用最近一次推断的结果
+type iec101Decoder struct {
+	buf     []byte
+	addrLen int
+}
+
+func (d *iec101Decoder) Name() string { return "iec101" }
+
+// Detect 按校验通过的帧覆盖的字节比例计算可信度，单字节确认不计入
+func (d *iec101Decoder) Detect(sample []byte) float64 {
+	matched := 0
+	for i := 0; i < len(sample); {
+		if n, _ := iec101FrameLength(sample[i:]); n > 1 {
+			matched += n
+			i += n
+			continue
+		}
+		i++
+	}
+	if len(sample) == 0 {
+		return 0
+	}
+	return float64(matched) / float64(len(sample))
+}
+
+func (d *iec101Decoder) Decode(t time.Time, data []byte, emit func(Frame)) {
+	d.buf = append(d.buf, data...)
+	for len(d.buf) > 0 {
+		n, addrLen := iec101FrameLength(d.buf)
+		switch {
+		case n < 0:
+			return
+		case n == 0:
+			d.buf = d.buf[:copy(d.buf, d.buf[1:])]
+			continue
+		}
+		if addrLen > 0 {
+			d.addrLen = addrLen
+		}
+		emit(parseIEC101(t, clone(d.buf[:n]), d.addrLen))
+		d.buf = d.buf[:copy(d.buf, d.buf[n:])]
+	}
+}
+
+// iec101FrameLength 返回 b 开头的有效帧长度，固定帧同时返回链路地址长度
+// 0 表示不是有效帧，-1 表示可能是有效帧但数据不足
+func iec101FrameLength(b []byte) (int, int) {
+	switch b[0] {
+	case iec101Ack:
+		return 1, 0
+	case iec101Fixed:
+		for addrLen := 1; addrLen <= 2; addrLen++ {
+			n := addrLen + 4
+			if len(b) < n {
+				return -1, 0
+			}
+			if b[n-1] == iec101End && b[n-2] == sum8(b[1:n-2]) {
+				return n, addrLen
+			}
+		}
+	case iec101Variable:
+		if len(b) < 4 {
+			return -1, 0
+		}
+		l := int(b[1])
+		if b[2] != b[1] || b[3] != iec101Variable || l < 2 {
+			return 0, 0
+		}
+		n := l + 6
+		if len(b) < n {
+			return -1, 0
+		}
+		if b[n-1] == iec101End && b[n-2] == sum8(b[4:n-2]) {
+			return n, 0
+		}
+	}
+	return 0, 0
+}
+
+func sum8(b []byte) byte {
+	var s byte
+	for _, c := range b {
+		s += c
+	}
+	return s
+}
+
+func parseIEC101(t time.Time, raw []byte, addrLen int) Frame {
+	f := Frame{Protocol: "iec101", Time: t, Raw: raw}
+	if raw[0] == iec101Ack {
+		f.Summary = "ACK (E5)"
+		f.Fields = []Field{{Name: "iec101.format", Value: "single"}}
+		return f
+	}
+
+	var body []byte
+	format := "fixed"
+	if raw[0] == iec101Fixed {
+		body = raw[1 : len(raw)-2]
+		addrLen = len(body) - 1
+	} else {
+		format = "variable"
+		body = raw[4 : len(raw)-2]
+	}
+	ctrl := body[0]
+	prm := ctrl&0x40 != 0
+	fn := ctrl & 0x0F
+	name := iec101LinkFuncs[boolIndex(prm)][fn]
+	if name == "" {
+		name = fmt.Sprintf("FUNC_%d", fn)
+	}
+	f.Fields = []Field{
+		{Name: "iec101.format", Value: format},
+		{Name: "iec101.prm", Value: prm},
+		{Name: "iec101.func", Value: int64(fn)},
+		{Name: "iec101.func_name", Value: name},
+	}
+	// 启动站方向为 FCB/FCV，从动站方向为 ACD/DFC
+	if prm {
+		f.Fields = append(f.Fields, Field{Name: "iec101.fcb", Value: ctrl&0x20 != 0}, Field{Name: "iec101.fcv", Value: ctrl&0x10 != 0})
+	} else {
+		f.Fields = append(f.Fields, Field{Name: "iec101.acd", Value: ctrl&0x20 != 0}, Field{Name: "iec101.dfc", Value: ctrl&0x10 != 0})
+	}
+	if len(body) < 1+addrLen {
+		f.Summary = name
+		f.Error = "frame too short for link address"
+		return f
+	}
+	var addr int64
+	for i := 0; i < addrLen; i++ {
+		addr |= int64(body[1+i]) << (8 * i)
+	}
+	f.Fields = append(f.Fields, Field{Name: "iec101.addr", Value: addr})
+	f.Summary = fmt.Sprintf("link %d %s", addr, name)
+
+	asdu := body[1+addrLen:]
+	if len(asdu) < 3 {
+		return f
+	}
+	typ, vsq, cot := asdu[0], asdu[1], asdu[2]
+	typeName := iec101Types[typ]
+	if typeName == "" {
+		typeName = fmt.Sprintf("TYPE_%d", typ)
+	}
+	cause := iec101Causes[cot&0x3F]
+	if cause == "" {
+		cause = fmt.Sprintf("cause_%d", cot&0x3F)
+	}
+	f.Fields = append(f.Fields,
+		Field{Name: "iec101.type", Value: int64(typ)},
+		Field{Name: "iec101.type_name", Value: typeName},
+		Field{Name: "iec101.sq", Value: vsq&0x80 != 0},
+		Field{Name: "iec101.count", Value: int64(vsq & 0x7F)},
+		Field{Name: "iec101.cot", Value: int64(cot & 0x3F)},
+		Field{Name: "iec101.cot_name", Value: cause},
+		Field{Name: "iec101.negative", Value: cot&0x40 != 0},
+		Field{Name: "iec101.test", Value: cot&0x80 != 0},
+		Field{Name: "iec101.asdu", Value: hex.EncodeToString(asdu)},
+	)
+	f.Summary += fmt.Sprintf(" %s %s x%d", typeName, cause, vsq&0x7F)
+	return f
+}
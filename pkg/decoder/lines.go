@@ -0,0 +1,127 @@
+package decoder
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// LineProtocol 行模式输出的帧的协议名
+const LineProtocol = "line"
+
+// LineAssembler 按换行组装接收数据；不带换行的提示符等部分行在空闲超时后也会输出，
+// 并标记为不完整，其后到达的剩余部分标记为续行。可并发使用
+type LineAssembler struct {
+	mu        sync.Mutex
+	timeout   time.Duration
+	emit      func(Frame)
+	buf       []byte
+	start     time.Time // 当前缓冲行第一个字节的到达时间
+	continued bool      // 当前缓冲行的前半部分已因超时输出
+	timer     *time.Timer
+	gen       uint64 // 每次缓冲变化递增，使过期的定时器失效
+}
+
+// NewLineAssembler 创建组装器，timeout 为 0 表示部分行只在换行或超长时输出
+func NewLineAssembler(timeout time.Duration, emit func(Frame)) *LineAssembler {
+	return &LineAssembler{timeout: timeout, emit: emit}
+}
+
+// SetTimeout 修改部分行的空闲超时
+func (l *LineAssembler) SetTimeout(timeout time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.timeout = timeout
+}
+
+// Timeout 返回当前的空闲超时
+func (l *LineAssembler) Timeout() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.timeout
+}
+
+// Feed 加入在 t 时刻到达的数据，输出其中的完整行，剩余部分在空闲超时后输出
+func (l *LineAssembler) Feed(t time.Time, data []byte) {
+	l.mu.Lock()
+	var out []Frame
+	for len(data) > 0 {
+		if len(l.buf) == 0 {
+			l.start = t
+		}
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			l.buf = append(l.buf, data...)
+			break
+		}
+		l.buf = append(l.buf, data[:i+1]...)
+		data = data[i+1:]
+		out = append(out, l.take(false))
+	}
+	if len(l.buf) >= maxLineLength {
+		out = append(out, l.take(true))
+	}
+	l.gen++
+	if len(l.buf) > 0 && l.timeout > 0 {
+		l.arm(l.gen)
+	}
+	l.mu.Unlock()
+
+	for _, f := range out {
+		l.emit(f)
+	}
+}
+
+// Flush 立即输出缓冲中的部分行 (连接关闭时调用)
+func (l *LineAssembler) Flush() {
+	l.mu.Lock()
+	l.gen++
+	var f Frame
+	ok := len(l.buf) > 0
+	if ok {
+		f = l.take(true)
+	}
+	l.continued = false
+	l.mu.Unlock()
+	if ok {
+		l.emit(f)
+	}
+}
+
+// arm 在超时后输出部分行，期间有新数据到达则作废
+func (l *LineAssembler) arm(gen uint64) {
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+	l.timer = time.AfterFunc(l.timeout, func() {
+		l.mu.Lock()
+		if l.gen != gen || len(l.buf) == 0 {
+			l.mu.Unlock()
+			return
+		}
+		f := l.take(true)
+		l.mu.Unlock()
+		l.emit(f)
+	})
+}
+
+// take 取出缓冲内容组成一帧，调用方需持有锁
+func (l *LineAssembler) take(incomplete bool) Frame {
+	raw := clone(l.buf)
+	l.buf = l.buf[:0]
+	line := string(bytes.TrimRight(raw, "\r\n"))
+	f := Frame{
+		Protocol: LineProtocol,
+		Time:     l.start,
+		Raw:      raw,
+		Summary:  line,
+		Fields: []Field{
+			{Name: "line.text", Value: line},
+			{Name: "line.length", Value: int64(len(line))},
+			{Name: "line.incomplete", Value: incomplete},
+			{Name: "line.continued", Value: l.continued},
+		},
+	}
+	l.continued = incomplete
+	return f
+}
@@ -0,0 +1,130 @@
+package decoder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+func init() {
+	Register(Info{Name: "mavlink", Description: "MAVLink v1/v2 framing", Source: "builtin"}, func() Decoder { return &mavlinkDecoder{} })
+}
+
+const (
+	mavlinkV1Magic = 0xFE
+	mavlinkV2Magic = 0xFD
+	// mavlinkSigned v2 incompat_flags 中表示带签名的位
+	mavlinkSigned = 0x01
+	// mavlinkSignatureLen 签名长度
+	mavlinkSignatureLen = 13
+)
+
+// mavlinkDecoder MAVLink 帧解码器
+// 只解析帧头，校验 CRC 需要各消息的 CRC_EXTRA，留给方言定义完成
+type mavlinkDecoder struct {
+	buf []byte
+}
+
+func (d *mavlinkDecoder) Name() string { return "mavlink" }
+
+// Detect 统计能首尾相接的帧数
+func (d *mavlinkDecoder) Detect(sample []byte) float64 {
+	chained := 0
+	for i := 0; i < len(sample); {
+		n := mavlinkFrameLength(sample[i:])
+		if n <= 0 || i+n > len(sample) {
+			if chained > 0 {
+				break
+			}
+			i++
+			continue
+		}
+		i += n
+		chained++
+		// 下一帧必须紧接着出现
+		if i < len(sample) && sample[i] != mavlinkV1Magic && sample[i] != mavlinkV2Magic {
+			chained = 0
+		}
+	}
+	switch {
+	case chained >= 3:
+		return 0.9
+	case chained == 2:
+		return 0.6
+	}
+	return 0
+}
+
+func (d *mavlinkDecoder) Decode(t time.Time, data []byte, emit func(Frame)) {
+	d.buf = append(d.buf, data...)
+	for len(d.buf) > 0 {
+		n := mavlinkFrameLength(d.buf)
+		if n == 0 {
+			// 跳到下一个可能的帧头
+			i := 1
+			for i < len(d.buf) && d.buf[i] != mavlinkV1Magic && d.buf[i] != mavlinkV2Magic {
+				i++
+			}
+			d.buf = d.buf[:copy(d.buf, d.buf[i:])]
+			continue
+		}
+		if n < 0 || n > len(d.buf) {
+			return
+		}
+		emit(parseMAVLink(t, clone(d.buf[:n])))
+		d.buf = d.buf[:copy(d.buf, d.buf[n:])]
+	}
+}
+
+// mavlinkFrameLength 返回 b 开头的帧长度，0 表示不是帧头，-1 表示帧头不完整
+func mavlinkFrameLength(b []byte) int {
+	if len(b) == 0 {
+		return -1
+	}
+	switch b[0] {
+	case mavlinkV1Magic:
+		if len(b) < 2 {
+			return -1
+		}
+		return 8 + int(b[1])
+	case mavlinkV2Magic:
+		if len(b) < 3 {
+			return -1
+		}
+		n := 12 + int(b[1])
+		if b[2]&mavlinkSigned != 0 {
+			n += mavlinkSignatureLen
+		}
+		return n
+	}
+	return 0
+}
+
+func parseMAVLink(t time.Time, raw []byte) Frame {
+	var version, seq, sys, comp, msgID int64
+	var payload []byte
+	if raw[0] == mavlinkV1Magic {
+		version = 1
+		seq, sys, comp, msgID = int64(raw[2]), int64(raw[3]), int64(raw[4]), int64(raw[5])
+		payload = raw[6 : 6+int(raw[1])]
+	} else {
+		version = 2
+		seq, sys, comp = int64(raw[4]), int64(raw[5]), int64(raw[6])
+		msgID = int64(raw[7]) | int64(raw[8])<<8 | int64(raw[9])<<16
+		payload = raw[10 : 10+int(raw[1])]
+	}
+	return Frame{
+		Protocol: "mavlink",
+		Time:     t,
+		Raw:      raw,
+		Summary:  fmt.Sprintf("v%d sys %d comp %d msg %d seq %d", version, sys, comp, msgID, seq),
+		Fields: []Field{
+			{Name: "mavlink.version", Value: version},
+			{Name: "mavlink.seq", Value: seq},
+			{Name: "mavlink.sysid", Value: sys},
+			{Name: "mavlink.compid", Value: comp},
+			{Name: "mavlink.msgid", Value: msgID},
+			{Name: "mavlink.payload", Value: hex.EncodeToString(payload)},
+		},
+	}
+}
@@ -0,0 +1,199 @@
+package decoder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+func init() {
+	Register(Info{Name: "modbus", Description: "Modbus RTU", Source: "builtin"}, func() Decoder { return &modbusDecoder{} })
+}
+
+const (
+	// modbusMaxFrame RTU 帧的最大长度
+	modbusMaxFrame = 256
+	// modbusGap 两段数据间隔超过该值时认为旧数据不属于同一帧
+	modbusGap = 50 * time.Millisecond
+)
+
+// modbusDecoder Modbus RTU 解码器
+// 串口读取无法可靠保留 3.5 字符的帧间隔，因此按功能码推算候选长度并用 CRC 确认帧边界
+type modbusDecoder struct {
+	buf  []byte
+	last time.Time
+}
+
+func (d *modbusDecoder) Name() string { return "modbus" }
+
+// Detect 按 CRC 连续匹配的帧覆盖的字节比例计算可信度
+func (d *modbusDecoder) Detect(sample []byte) float64 {
+	matched := 0
+	for i := 0; i < len(sample); {
+		if n := modbusFrameLength(sample[i:]); n > 0 {
+			matched += n
+			i += n
+			continue
+		}
+		i++
+	}
+	if len(sample) == 0 || matched == 0 {
+		return 0
+	}
+	return float64(matched) / float64(len(sample))
+}
+
+func (d *modbusDecoder) Decode(t time.Time, data []byte, emit func(Frame)) {
+	if !d.last.IsZero() && t.Sub(d.last) > modbusGap && len(d.buf) > 0 {
+		d.emitGarbage(d.last, emit)
+	}
+	d.last = t
+	d.buf = append(d.buf, data...)
+	for len(d.buf) >= 4 {
+		n := modbusFrameLength(d.buf)
+		if n < 0 {
+			// 数据不足，等待后续数据
+			return
+		}
+		if n == 0 {
+			if len(d.buf) < modbusMaxFrame {
+				return
+			}
+			// 缓冲区已满仍找不到有效帧，丢弃一个字节重新同步
+			d.buf = d.buf[:copy(d.buf, d.buf[1:])]
+			continue
+		}
+		emit(parseModbus(t, clone(d.buf[:n])))
+		d.buf = d.buf[:copy(d.buf, d.buf[n:])]
+	}
+}
+
+// emitGarbage 将无法成帧的残留数据作为错误帧输出
+func (d *modbusDecoder) emitGarbage(t time.Time, emit func(Frame)) {
+	emit(Frame{
+		Protocol: "modbus",
+		Time:     t,
+		Raw:      clone(d.buf),
+		Summary:  "incomplete frame",
+		Error:    "incomplete frame or CRC mismatch",
+	})
+	d.buf = d.buf[:0]
+}
+
+// modbusFrameLength 返回 b 开头的有效帧长度
+// 0 表示不是有效帧，-1 表示可能是有效帧但数据不足
+func modbusFrameLength(b []byte) int {
+	if len(b) < 2 || b[0] > 247 {
+		return 0
+	}
+	short := false
+	for _, n := range modbusCandidates(b) {
+		if n > modbusMaxFrame {
+			continue
+		}
+		if n > len(b) {
+			short = true
+			continue
+		}
+		if modbusCRCValid(b[:n]) {
+			return n
+		}
+	}
+	if short {
+		return -1
+	}
+	return 0
+}
+
+// modbusCandidates 按功能码推算可能的帧长度 (请求与响应格式不同，都要尝试)
+func modbusCandidates(b []byte) []int {
+	fn := b[1]
+	if fn&0x80 != 0 {
+		return []int{5}
+	}
+	byteCount := func(i int) int {
+		if i < len(b) {
+			return int(b[i])
+		}
+		return modbusMaxFrame
+	}
+	switch fn {
+	case 0x01, 0x02, 0x03, 0x04:
+		return []int{8, 5 + byteCount(2)}
+	case 0x05, 0x06:
+		return []int{8}
+	case 0x0F, 0x10:
+		return []int{8, 9 + byteCount(6)}
+	case 0x17:
+		return []int{5 + byteCount(2), 13 + byteCount(10)}
+	}
+	return nil
+}
+
+// modbusCRC Modbus CRC-16 (多项式 0xA001，初值 0xFFFF)
+func modbusCRC(b []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, c := range b {
+		crc ^= uint16(c)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+// modbusCRCValid 校验帧末尾的 CRC (低字节在前)
+func modbusCRCValid(frame []byte) bool {
+	n := len(frame)
+	if n < 4 {
+		return false
+	}
+	return modbusCRC(frame[:n-2]) == uint16(frame[n-2])|uint16(frame[n-1])<<8
+}
+
+func parseModbus(t time.Time, raw []byte) Frame {
+	f := Frame{Protocol: "modbus", Time: t, Raw: raw}
+	describeModbus(&f, "modbus", raw[0], raw[1], raw[2:len(raw)-2])
+	return f
+}
+
+// describeModbus 按 PDU 填充字段与摘要，RTU 与 ASCII 共用，prefix 为字段名前缀
+func describeModbus(f *Frame, prefix string, addr, fn byte, pdu []byte) {
+	f.Fields = []Field{
+		{Name: prefix + ".addr", Value: int64(addr)},
+		{Name: prefix + ".func", Value: int64(fn & 0x7F)},
+	}
+	u16 := func(i int) int64 { return int64(pdu[i])<<8 | int64(pdu[i+1]) }
+
+	switch {
+	case fn&0x80 != 0 && len(pdu) >= 1:
+		f.Fields = append(f.Fields, Field{Name: prefix + ".exception", Value: int64(pdu[0])})
+		f.Summary = fmt.Sprintf("slave %d func %d exception %d", addr, fn&0x7F, pdu[0])
+	case len(pdu) == 4:
+		// 读请求、单写请求/响应以及多写响应的 PDU 都是 地址+数量/值
+		f.Fields = append(f.Fields,
+			Field{Name: prefix + ".start", Value: u16(0)},
+			Field{Name: prefix + ".quantity", Value: u16(2)},
+		)
+		f.Summary = fmt.Sprintf("slave %d func %d start %d count/value %d", addr, fn, u16(0), u16(2))
+	case len(pdu) >= 1 && int(pdu[0]) == len(pdu)-1:
+		f.Fields = append(f.Fields,
+			Field{Name: prefix + ".byte_count", Value: int64(pdu[0])},
+			Field{Name: prefix + ".data", Value: hex.EncodeToString(pdu[1:])},
+		)
+		f.Summary = fmt.Sprintf("slave %d func %d response %d bytes", addr, fn, pdu[0])
+	case len(pdu) >= 5:
+		f.Fields = append(f.Fields,
+			Field{Name: prefix + ".start", Value: u16(0)},
+			Field{Name: prefix + ".quantity", Value: u16(2)},
+			Field{Name: prefix + ".data", Value: hex.EncodeToString(pdu[5:])},
+		)
+		f.Summary = fmt.Sprintf("slave %d func %d write %d at %d", addr, fn, u16(2), u16(0))
+	default:
+		f.Summary = fmt.Sprintf("slave %d func %d", addr, fn)
+	}
+}
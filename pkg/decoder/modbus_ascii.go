@@ -0,0 +1,122 @@
+package decoder
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+func init() {
+	Register(Info{Name: "modbus_ascii", Description: "Modbus ASCII", Source: "builtin"}, func() Decoder { return &modbusASCIIDecoder{} })
+}
+
+// modbusASCIIMaxLine ASCII 帧的最大长度 (冒号 + 2×(1+1+252+1) 个十六进制字符 + CRLF)
+const modbusASCIIMaxLine = 513
+
+// modbusASCIIDecoder Modbus ASCII 解码器
+// 帧以 ':' 开始、CRLF 结束，地址、功能码、数据与 LRC 均以两位十六进制字符表示
+type modbusASCIIDecoder struct {
+	buf []byte
+}
+
+func (d *modbusASCIIDecoder) Name() string { return "modbus_ascii" }
+
+// Detect 按 LRC 正确的帧覆盖的字节比例计算可信度
+func (d *modbusASCIIDecoder) Detect(sample []byte) float64 {
+	matched := 0
+	for rest := sample; ; {
+		start := bytes.IndexByte(rest, ':')
+		if start < 0 {
+			break
+		}
+		end := bytes.Index(rest[start:], []byte("\r\n"))
+		if end < 0 {
+			break
+		}
+		if _, err := ModbusASCIIDecode(rest[start : start+end+2]); err == nil {
+			matched += end + 2
+		}
+		rest = rest[start+end+2:]
+	}
+	if len(sample) == 0 {
+		return 0
+	}
+	return float64(matched) / float64(len(sample))
+}
+
+func (d *modbusASCIIDecoder) Decode(t time.Time, data []byte, emit func(Frame)) {
+	d.buf = append(d.buf, data...)
+	for {
+		start := bytes.IndexByte(d.buf, ':')
+		if start < 0 {
+			d.buf = d.buf[:0]
+			return
+		}
+		d.buf = d.buf[:copy(d.buf, d.buf[start:])]
+		end := bytes.Index(d.buf, []byte("\r\n"))
+		if end < 0 {
+			if len(d.buf) > modbusASCIIMaxLine {
+				// 超长仍没有结束符，丢弃冒号重新同步
+				d.buf = d.buf[:copy(d.buf, d.buf[1:])]
+				continue
+			}
+			return
+		}
+		emit(parseModbusASCII(t, clone(d.buf[:end+2])))
+		d.buf = d.buf[:copy(d.buf, d.buf[end+2:])]
+	}
+}
+
+// ModbusASCIIEncode 将地址、功能码与数据编码为 ASCII 帧，自动追加 LRC 与 CRLF
+func ModbusASCIIEncode(adu []byte) []byte {
+	out := make([]byte, 0, 2*len(adu)+5)
+	out = append(out, ':')
+	out = append(out, bytes.ToUpper([]byte(hex.EncodeToString(adu)))...)
+	out = append(out, bytes.ToUpper([]byte(hex.EncodeToString([]byte{modbusLRC(adu)})))...)
+	return append(out, '\r', '\n')
+}
+
+// ModbusASCIIDecode 校验 ASCII 帧并返回去掉 LRC 的地址、功能码与数据
+func ModbusASCIIDecode(frame []byte) ([]byte, error) {
+	if len(frame) < 9 || frame[0] != ':' || !bytes.HasSuffix(frame, []byte("\r\n")) {
+		return nil, fmt.Errorf("malformed frame")
+	}
+	body := frame[1 : len(frame)-2]
+	if len(body)%2 != 0 {
+		return nil, fmt.Errorf("odd number of hex characters")
+	}
+	bin := make([]byte, len(body)/2)
+	if _, err := hex.Decode(bin, body); err != nil {
+		return nil, fmt.Errorf("invalid hex: %w", err)
+	}
+	adu, lrc := bin[:len(bin)-1], bin[len(bin)-1]
+	if want := modbusLRC(adu); lrc != want {
+		return adu, fmt.Errorf("LRC mismatch: got %02X, want %02X", lrc, want)
+	}
+	return adu, nil
+}
+
+// modbusLRC 所有字节之和的二进制补码
+func modbusLRC(b []byte) byte {
+	var sum byte
+	for _, c := range b {
+		sum += c
+	}
+	return -sum
+}
+
+func parseModbusASCII(t time.Time, raw []byte) Frame {
+	f := Frame{Protocol: "modbus_ascii", Time: t, Raw: raw}
+	adu, err := ModbusASCIIDecode(raw)
+	if len(adu) < 2 {
+		f.Summary = "malformed frame"
+		f.Error = err.Error()
+		return f
+	}
+	describeModbus(&f, "modbus_ascii", adu[0], adu[1], adu[2:])
+	if err != nil {
+		f.Error = err.Error()
+	}
+	return f
+}
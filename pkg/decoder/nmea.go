@@ -0,0 +1,172 @@
+package decoder
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(Info{Name: "nmea", Description: "NMEA 0183 sentences (GPS/GNSS)", Source: "builtin"}, func() Decoder { return &nmeaDecoder{} })
+}
+
+// nmeaDecoder NMEA 0183 语句，如 $GPGGA,...*hh
+type nmeaDecoder struct {
+	buf []byte
+}
+
+func (d *nmeaDecoder) Name() string { return "nmea" }
+
+// Detect 统计以 $ 或 ! 开头且校验和正确的行的比例
+func (d *nmeaDecoder) Detect(sample []byte) float64 {
+	lines := bytes.Split(sample, []byte{'\n'})
+	total, valid := 0, 0
+	for i, line := range lines {
+		line = bytes.TrimRight(line, "\r")
+		// 首尾两行可能不完整
+		if len(line) == 0 || i == 0 && len(lines) > 1 || i == len(lines)-1 {
+			continue
+		}
+		total++
+		if _, ok := nmeaChecksum(string(line)); ok {
+			valid++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(valid) / float64(total)
+}
+
+func (d *nmeaDecoder) Decode(t time.Time, data []byte, emit func(Frame)) {
+	d.buf = append(d.buf, data...)
+	for {
+		i := bytes.IndexByte(d.buf, '\n')
+		if i < 0 {
+			if len(d.buf) > maxLineLength {
+				d.buf = d.buf[:0]
+			}
+			return
+		}
+		line := strings.TrimRight(string(d.buf[:i]), "\r")
+		raw := clone(d.buf[:i+1])
+		d.buf = d.buf[:copy(d.buf, d.buf[i+1:])]
+		if f, ok := parseNMEA(t, line, raw); ok {
+			emit(f)
+		}
+	}
+}
+
+// nmeaChecksum 校验语句，返回去掉起始符和校验和的主体
+func nmeaChecksum(line string) (string, bool) {
+	if len(line) < 7 || line[0] != '$' && line[0] != '!' {
+		return "", false
+	}
+	star := strings.LastIndexByte(line, '*')
+	if star < 0 || star+3 != len(line) {
+		return "", false
+	}
+	want, err := strconv.ParseUint(line[star+1:], 16, 8)
+	if err != nil {
+		return "", false
+	}
+	var sum byte
+	for i := 1; i < star; i++ {
+		sum ^= line[i]
+	}
+	return line[1:star], sum == byte(want)
+}
+
+// parseNMEA 解析一行语句；不是 NMEA 格式的行返回 false
+func parseNMEA(t time.Time, line string, raw []byte) (Frame, bool) {
+	start := strings.IndexAny(line, "$!")
+	if start < 0 {
+		return Frame{}, false
+	}
+	line = line[start:]
+	body, ok := nmeaChecksum(line)
+	if body == "" {
+		// 没有校验和的语句也接受，但标记错误
+		body = strings.TrimLeft(line, "$!")
+		if i := strings.IndexByte(body, '*'); i >= 0 {
+			body = body[:i]
+		}
+	}
+	parts := strings.Split(body, ",")
+	if len(parts[0]) < 3 {
+		return Frame{}, false
+	}
+	talker, typ := parts[0][:len(parts[0])-3], parts[0][len(parts[0])-3:]
+
+	f := Frame{
+		Protocol: "nmea",
+		Time:     t,
+		Raw:      raw,
+		Summary:  line,
+		Fields: []Field{
+			{Name: "nmea.talker", Value: talker},
+			{Name: "nmea.type", Value: typ},
+			{Name: "nmea.valid", Value: ok},
+		},
+	}
+	if !ok {
+		f.Error = "checksum mismatch"
+	}
+
+	field := func(i int) string {
+		if i < len(parts) {
+			return parts[i]
+		}
+		return ""
+	}
+	switch typ {
+	case "GGA":
+		f.Fields = append(f.Fields, Field{Name: "nmea.utc", Value: field(1)})
+		f.addPosition(field(2), field(3), field(4), field(5))
+		if q, err := strconv.ParseInt(field(6), 10, 64); err == nil {
+			f.Fields = append(f.Fields, Field{Name: "nmea.fix", Value: q})
+		}
+		if n, err := strconv.ParseInt(field(7), 10, 64); err == nil {
+			f.Fields = append(f.Fields, Field{Name: "nmea.satellites", Value: n})
+		}
+	case "RMC":
+		f.Fields = append(f.Fields,
+			Field{Name: "nmea.utc", Value: field(1)},
+			Field{Name: "nmea.status", Value: field(2)},
+			Field{Name: "nmea.date", Value: field(9)},
+		)
+		f.addPosition(field(3), field(4), field(5), field(6))
+	}
+	return f, true
+}
+
+// addPosition 将 ddmm.mmmm 格式的经纬度转换为十进制度
+func (f *Frame) addPosition(lat, ns, lon, ew string) {
+	la, ok1 := nmeaDegrees(lat, 2)
+	lo, ok2 := nmeaDegrees(lon, 3)
+	if !ok1 || !ok2 {
+		return
+	}
+	if ns == "S" {
+		la = -la
+	}
+	if ew == "W" {
+		lo = -lo
+	}
+	f.Fields = append(f.Fields, Field{Name: "nmea.lat", Value: la}, Field{Name: "nmea.lon", Value: lo})
+	f.Summary = fmt.Sprintf("%s (%.6f, %.6f)", f.Summary, la, lo)
+}
+
+func nmeaDegrees(v string, degDigits int) (float64, bool) {
+	if len(v) < degDigits+2 {
+		return 0, false
+	}
+	deg, err1 := strconv.ParseFloat(v[:degDigits], 64)
+	min, err2 := strconv.ParseFloat(v[degDigits:], 64)
+	if err1 != nil || err2 != nil {
+		return 0, false
+	}
+	return deg + min/60, true
+}
@@ -0,0 +1,29 @@
+package decoder
+
+import (
+	"fmt"
+	"plugin"
+)
+
+// PluginSymbol 第三方解码器插件需要导出的构造函数名，类型为 func() decoder.Decoder
+const PluginSymbol = "NewDecoder"
+
+// LoadPlugin 加载以 go build -buildmode=plugin 构建的解码器并注册
+// Go 插件仅支持 Linux 与 macOS，且必须与主程序使用相同版本的依赖构建
+func LoadPlugin(path string) (Info, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return Info{}, err
+	}
+	sym, err := p.Lookup(PluginSymbol)
+	if err != nil {
+		return Info{}, err
+	}
+	factory, ok := sym.(func() Decoder)
+	if !ok {
+		return Info{}, fmt.Errorf("decoder: %s in %s has type %T, want func() decoder.Decoder", PluginSymbol, path, sym)
+	}
+	info := Info{Name: factory().Name(), Description: path, Source: "plugin"}
+	Register(info, factory)
+	return info, nil
+}
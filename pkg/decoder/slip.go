@@ -0,0 +1,99 @@
+package decoder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+func init() {
+	Register(Info{Name: "slip", Description: "SLIP framing (RFC 1055)", Source: "builtin"}, func() Decoder { return &slipDecoder{} })
+}
+
+const (
+	slipEnd    = 0xC0
+	slipEsc    = 0xDB
+	slipEscEnd = 0xDC
+	slipEscEsc = 0xDD
+)
+
+// slipDecoder 以 0xC0 分隔的 SLIP 帧
+type slipDecoder struct {
+	raw []byte
+}
+
+func (d *slipDecoder) Name() string { return "slip" }
+
+// Detect 要求至少两个非空帧，且转义序列全部合法
+func (d *slipDecoder) Detect(sample []byte) float64 {
+	frames, bad := 0, 0
+	start := -1
+	for i, c := range sample {
+		switch c {
+		case slipEnd:
+			if start >= 0 && i-start > 1 {
+				frames++
+			}
+			start = i
+		case slipEsc:
+			if i+1 < len(sample) && sample[i+1] != slipEscEnd && sample[i+1] != slipEscEsc {
+				bad++
+			}
+		}
+	}
+	if frames < 2 || bad > 0 {
+		return 0
+	}
+	if frames >= 5 {
+		return 0.8
+	}
+	return 0.5
+}
+
+func (d *slipDecoder) Decode(t time.Time, data []byte, emit func(Frame)) {
+	for _, c := range data {
+		if c != slipEnd {
+			d.raw = append(d.raw, c)
+			if len(d.raw) > maxLineLength {
+				d.raw = d.raw[:0]
+			}
+			continue
+		}
+		// 起始的 END 用于冲掉线路噪声，空帧不输出
+		if len(d.raw) > 0 {
+			emit(parseSLIP(t, clone(d.raw)))
+			d.raw = d.raw[:0]
+		}
+	}
+}
+
+func parseSLIP(t time.Time, raw []byte) Frame {
+	payload := make([]byte, 0, len(raw))
+	var errMsg string
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != slipEsc {
+			payload = append(payload, raw[i])
+			continue
+		}
+		i++
+		switch {
+		case i < len(raw) && raw[i] == slipEscEnd:
+			payload = append(payload, slipEnd)
+		case i < len(raw) && raw[i] == slipEscEsc:
+			payload = append(payload, slipEsc)
+		default:
+			errMsg = "invalid escape sequence"
+		}
+	}
+	return Frame{
+		Protocol: "slip",
+		Time:     t,
+		Raw:      raw,
+		Summary:  fmt.Sprintf("%d bytes", len(payload)),
+		Fields: []Field{
+			{Name: "slip.length", Value: int64(len(payload))},
+			{Name: "slip.payload", Value: hex.EncodeToString(payload)},
+		},
+		Error: errMsg,
+	}
+}
@@ -0,0 +1,73 @@
+package decoder
+
+import (
+	"bytes"
+	"time"
+	"unicode/utf8"
+)
+
+func init() {
+	Register(Info{Name: "text", Description: "Plain text lines", Source: "builtin"}, func() Decoder { return &textDecoder{} })
+}
+
+// maxLineLength 超过该长度仍未遇到换行时强制输出
+const maxLineLength = 4096
+
+// textDecoder 按行切分的纯文本
+type textDecoder struct {
+	buf []byte
+}
+
+func (d *textDecoder) Name() string { return "text" }
+
+// Detect 可打印字符占比高且包含换行时认为是文本
+func (d *textDecoder) Detect(sample []byte) float64 {
+	if len(sample) == 0 || !utf8.Valid(sample) {
+		return 0
+	}
+	printable := 0
+	for _, c := range sample {
+		if c >= 0x20 && c < 0x7f || c == '\r' || c == '\n' || c == '\t' || c >= 0x80 {
+			printable++
+		}
+	}
+	ratio := float64(printable) / float64(len(sample))
+	if ratio < 0.9 {
+		return 0
+	}
+	// 文本是兜底的协议，可信度低于有明确结构的协议
+	if bytes.IndexByte(sample, '\n') < 0 {
+		return ratio * 0.3
+	}
+	return ratio * 0.5
+}
+
+func (d *textDecoder) Decode(t time.Time, data []byte, emit func(Frame)) {
+	d.buf = append(d.buf, data...)
+	for {
+		i := bytes.IndexByte(d.buf, '\n')
+		if i < 0 {
+			if len(d.buf) >= maxLineLength {
+				d.emit(t, d.buf, emit)
+				d.buf = d.buf[:0]
+			}
+			return
+		}
+		d.emit(t, d.buf[:i+1], emit)
+		d.buf = d.buf[:copy(d.buf, d.buf[i+1:])]
+	}
+}
+
+func (d *textDecoder) emit(t time.Time, raw []byte, emit func(Frame)) {
+	line := string(bytes.TrimRight(raw, "\r\n"))
+	emit(Frame{
+		Protocol: "text",
+		Time:     t,
+		Raw:      clone(raw),
+		Summary:  line,
+		Fields: []Field{
+			{Name: "text.line", Value: line},
+			{Name: "text.length", Value: int64(len(line))},
+		},
+	})
+}
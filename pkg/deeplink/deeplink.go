@@ -0,0 +1,225 @@
+package deeplink
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"serial-assistant/pkg/capture"
+	"serial-assistant/pkg/journal"
+)
+
+// Scheme 自定义 URL 协议名
+const Scheme = "serialassistant"
+
+// 链接动作，对应 URL 的 host 部分
+const (
+	ActionOpen    = "open"    // serialassistant://open?port=COM3&baud=115200
+	ActionCapture = "capture" // serialassistant://capture?path=/path/to/file.smcap
+)
+
+// Link 链接或关联文件描述的会话，Connection 与 Capture 二选一
+type Link struct {
+	Connection *journal.Connection `json:"connection,omitempty"`
+	Capture    string              `json:"capture,omitempty"` // 抓包文件路径
+}
+
+// ErrInvalid 无法识别的链接
+var ErrInvalid = errors.New("deeplink: invalid link")
+
+// ErrBundled 协议与文件关联由应用包注册 (macOS 的 Info.plist，见 wails.json 的 info)
+var ErrBundled = errors.New("deeplink: registered by the application bundle")
+
+// typeNames 链接中的连接类型到 journal.Connection.Type 的映射
+var typeNames = map[string]string{
+	"serial":     "SERIAL",
+	"tcp":        "TCP_CLIENT",
+	"tcp-server": "TCP_SERVER",
+	"udp":        "UDP",
+	"jlink":      "JLINK",
+}
+
+// parityNames 链接中的校验位到 OpenSerial 参数的映射
+var parityNames = map[string]string{"none": "None", "odd": "Odd", "even": "Even", "mark": "Mark", "space": "Space"}
+
+// Parse 解析 serialassistant:// 链接
+// open 的参数：type (serial、tcp、tcp-server、udp、jlink，默认 serial)，
+// 串口为 port、baud、data、stop (1、1.5、2)、parity、flow (none、rtscts)，网络为 host、port、local，J-Link 为 chip、speed、interface
+func Parse(raw string) (Link, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Link{}, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if !strings.EqualFold(u.Scheme, Scheme) {
+		return Link{}, fmt.Errorf("%w: scheme %q", ErrInvalid, u.Scheme)
+	}
+	// 浏览器可能在 host 后补 "/"，也可能把动作放在 opaque 部分 (serialassistant:open?...)
+	action := u.Host
+	if action == "" {
+		action = strings.Trim(u.Opaque+u.Path, "/")
+	}
+	q := u.Query()
+	switch strings.ToLower(action) {
+	case ActionCapture:
+		path := q.Get("path")
+		if path == "" || filepath.Ext(path) != capture.Ext {
+			return Link{}, fmt.Errorf("%w: capture path %q", ErrInvalid, path)
+		}
+		return Link{Capture: path}, nil
+	case ActionOpen:
+		c, err := parseConnection(q)
+		if err != nil {
+			return Link{}, err
+		}
+		return Link{Connection: c}, nil
+	}
+	return Link{}, fmt.Errorf("%w: action %q", ErrInvalid, action)
+}
+
+func parseConnection(q url.Values) (*journal.Connection, error) {
+	kind := strings.ToLower(q.Get("type"))
+	if kind == "" {
+		kind = "serial"
+	}
+	typ, ok := typeNames[kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: type %q", ErrInvalid, kind)
+	}
+	c := &journal.Connection{Type: typ, Port: q.Get("port"), Host: q.Get("host"), LocalPort: q.Get("local")}
+	number := func(key string, def int) (int, error) {
+		s := q.Get(key)
+		if s == "" {
+			return def, nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%w: %s=%q", ErrInvalid, key, s)
+		}
+		return n, nil
+	}
+	var err error
+	switch kind {
+	case "serial":
+		if c.Port == "" {
+			return nil, fmt.Errorf("%w: missing port", ErrInvalid)
+		}
+		if c.BaudRate, err = number("baud", 115200); err != nil {
+			return nil, err
+		}
+		if c.DataBits, err = number("data", 8); err != nil {
+			return nil, err
+		}
+		switch q.Get("stop") {
+		case "", "1":
+			c.StopBits = 1
+		case "1.5", "15":
+			c.StopBits = 15
+		case "2":
+			c.StopBits = 2
+		default:
+			return nil, fmt.Errorf("%w: stop=%q", ErrInvalid, q.Get("stop"))
+		}
+		parity := strings.ToLower(q.Get("parity"))
+		if parity == "" {
+			parity = "none"
+		}
+		if c.Parity, ok = parityNames[parity]; !ok {
+			return nil, fmt.Errorf("%w: parity=%q", ErrInvalid, parity)
+		}
+		switch flow := strings.ToLower(q.Get("flow")); flow {
+		case "", "none":
+		case "rtscts":
+			c.FlowControl = flow
+		default:
+			return nil, fmt.Errorf("%w: flow=%q", ErrInvalid, flow)
+		}
+	case "tcp", "udp":
+		if c.Host == "" || c.Port == "" {
+			return nil, fmt.Errorf("%w: missing host or port", ErrInvalid)
+		}
+	case "tcp-server":
+		if c.Port == "" {
+			return nil, fmt.Errorf("%w: missing port", ErrInvalid)
+		}
+	case "jlink":
+		c.Chip, c.Interface, c.Port = q.Get("chip"), q.Get("interface"), ""
+		if c.Chip == "" {
+			return nil, fmt.Errorf("%w: missing chip", ErrInvalid)
+		}
+		if c.Speed, err = number("speed", 4000); err != nil {
+			return nil, err
+		}
+		if c.Interface == "" {
+			c.Interface = "SWD"
+		}
+	}
+	return c, nil
+}
+
+// Format 生成打开指定连接的链接，可贴到 wiki 或工单中
+func Format(c journal.Connection) string {
+	q := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	for kind, typ := range typeNames {
+		if typ == c.Type && kind != "serial" {
+			q.Set("type", kind)
+		}
+	}
+	switch c.Type {
+	case "SERIAL":
+		set("port", c.Port)
+		q.Set("baud", strconv.Itoa(c.BaudRate))
+		if c.DataBits != 0 && c.DataBits != 8 {
+			q.Set("data", strconv.Itoa(c.DataBits))
+		}
+		switch c.StopBits {
+		case 15:
+			q.Set("stop", "1.5")
+		case 2:
+			q.Set("stop", "2")
+		}
+		if p := strings.ToLower(c.Parity); p != "" && p != "none" {
+			q.Set("parity", p)
+		}
+		if c.FlowControl != "" && c.FlowControl != "none" {
+			q.Set("flow", c.FlowControl)
+		}
+	case "JLINK":
+		set("chip", c.Chip)
+		set("interface", c.Interface)
+		if c.Speed > 0 {
+			q.Set("speed", strconv.Itoa(c.Speed))
+		}
+	default:
+		set("host", c.Host)
+		set("port", c.Port)
+		set("local", c.LocalPort)
+	}
+	return Scheme + "://" + ActionOpen + "?" + q.Encode()
+}
+
+// FromArgs 在命令行参数中查找链接或抓包文件 (由操作系统按协议或文件关联启动时传入)
+func FromArgs(args []string) (Link, bool) {
+	for _, arg := range args {
+		if strings.HasPrefix(strings.ToLower(arg), Scheme+":") {
+			if l, err := Parse(arg); err == nil {
+				return l, true
+			}
+			continue
+		}
+		if filepath.Ext(arg) == capture.Ext {
+			if abs, err := filepath.Abs(arg); err == nil {
+				arg = abs
+			}
+			return Link{Capture: arg}, true
+		}
+	}
+	return Link{}, false
+}
@@ -0,0 +1,107 @@
+package deeplink
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/journal"
+)
+
+func TestParseSerial(t *testing.T) {
+	l, err := Parse("serialassistant://open/?port=COM3&baud=9600&parity=even&stop=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := l.Connection
+	if c == nil || c.Type != "SERIAL" || c.Port != "COM3" || c.BaudRate != 9600 || c.DataBits != 8 || c.StopBits != 2 || c.Parity != "Even" {
+		t.Errorf("Unexpected connection %+v", c)
+	}
+
+	l, _ = Parse("serialassistant:open?port=/dev/ttyUSB0")
+	if l.Connection == nil || l.Connection.BaudRate != 115200 || l.Connection.Parity != "None" {
+		t.Errorf("Expected defaults for an opaque link, got %+v", l.Connection)
+	}
+}
+
+func TestParseOthers(t *testing.T) {
+	l, err := Parse("serialassistant://open?type=tcp&host=10.0.0.5&port=502")
+	if err != nil || l.Connection.Type != "TCP_CLIENT" || l.Connection.Host != "10.0.0.5" {
+		t.Errorf("Unexpected TCP link %+v (%v)", l.Connection, err)
+	}
+	l, err = Parse("serialassistant://capture?path=/tmp/a.smcap")
+	if err != nil || l.Capture != "/tmp/a.smcap" {
+		t.Errorf("Unexpected capture link %+v (%v)", l, err)
+	}
+	for _, bad := range []string{
+		"http://open?port=COM3",
+		"serialassistant://open",
+		"serialassistant://open?port=COM3&baud=fast",
+		"serialassistant://open?port=COM3&parity=maybe",
+		"serialassistant://open?port=COM3&flow=dsrdtr",
+		"serialassistant://capture?path=/etc/passwd",
+		"serialassistant://format?disk=c",
+	} {
+		if _, err := Parse(bad); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Expected ErrInvalid for %s, got %v", bad, err)
+		}
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	for _, c := range []journal.Connection{
+		{Type: "SERIAL", Port: "COM7", BaudRate: 57600, DataBits: 7, StopBits: 15, Parity: "Odd"},
+		{Type: "SERIAL", Port: "COM1", BaudRate: 115200, DataBits: 8, StopBits: 1, Parity: "None", FlowControl: "rtscts"},
+		{Type: "UDP", Host: "192.168.1.2", Port: "9000", LocalPort: "9001"},
+		{Type: "JLINK", Chip: "STM32F407VG", Speed: 4000, Interface: "SWD"},
+	} {
+		link := Format(c)
+		l, err := Parse(link)
+		if err != nil || *l.Connection != c {
+			t.Errorf("%s parsed to %+v (%v), want %+v", link, l.Connection, err, c)
+		}
+	}
+}
+
+func TestFromArgs(t *testing.T) {
+	if l, ok := FromArgs([]string{"--verbose", "SerialAssistant://open?port=COM1"}); !ok || l.Connection.Port != "COM1" {
+		t.Errorf("Expected a link from the arguments, got %+v", l)
+	}
+	if l, ok := FromArgs([]string{"/data/run.smcap"}); !ok || l.Capture != "/data/run.smcap" {
+		t.Errorf("Expected a capture file, got %+v", l)
+	}
+	if _, ok := FromArgs([]string{"notes.txt"}); ok {
+		t.Error("Unrelated arguments should be ignored")
+	}
+}
+
+func TestHandoff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "handoff.json")
+	if err := Forward(path, Link{}); !errors.Is(err, ErrNoInstance) {
+		t.Errorf("Expected ErrNoInstance without a receiver, got %v", err)
+	}
+
+	got := make(chan Link, 1)
+	r, err := Listen(path, func(l Link) { got <- l })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Forward(path, Link{Capture: "/tmp/x.smcap"}); err != nil {
+		t.Fatalf("Forward failed: %v", err)
+	}
+	select {
+	case l := <-got:
+		if l.Capture != "/tmp/x.smcap" {
+			t.Errorf("Unexpected link %+v", l)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Link was not delivered")
+	}
+
+	r.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Handoff file should be removed on close, got %v", err)
+	}
+}
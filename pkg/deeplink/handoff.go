@@ -0,0 +1,118 @@
+package deeplink
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// handoffTimeout 转交链接时连接与应答的超时时间
+const handoffTimeout = 2 * time.Second
+
+// ErrNoInstance 没有正在运行的实例接收链接
+var ErrNoInstance = errors.New("deeplink: no running instance")
+
+// handoffInfo 写入交接文件的监听地址与密钥，文件只对当前用户可读
+type handoffInfo struct {
+	Addr   string `json:"addr"`
+	Secret string `json:"secret"`
+}
+
+type handoffRequest struct {
+	Secret string `json:"secret"`
+	Link   Link   `json:"link"`
+}
+
+// Receiver 在本机回环地址上接收其他实例转交的链接
+// 操作系统按协议或文件关联启动新进程时，新进程把链接交给已运行的实例后退出，避免打开第二个窗口
+type Receiver struct {
+	path string
+	ln   net.Listener
+	info handoffInfo
+}
+
+// Listen 开始接收链接并把监听地址写入 path，每收到一个链接调用一次 handle
+func Listen(path string, handle func(Link)) (*Receiver, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	secret := make([]byte, 16)
+	rand.Read(secret)
+	r := &Receiver{path: path, ln: ln, info: handoffInfo{Addr: ln.Addr().String(), Secret: hex.EncodeToString(secret)}}
+	data, _ := json.Marshal(r.info)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	go r.serve(handle)
+	return r, nil
+}
+
+func (r *Receiver) serve(handle func(Link)) {
+	for {
+		conn, err := r.ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(handoffTimeout))
+			var req handoffRequest
+			if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+				return
+			}
+			if subtle.ConstantTimeCompare([]byte(req.Secret), []byte(r.info.Secret)) != 1 {
+				return
+			}
+			conn.Write([]byte("ok\n"))
+			handle(req.Link)
+		}()
+	}
+}
+
+// Close 停止接收；交接文件仍指向本实例时将其删除
+func (r *Receiver) Close() error {
+	if data, err := os.ReadFile(r.path); err == nil && strings.Contains(string(data), r.info.Secret) {
+		os.Remove(r.path)
+	}
+	return r.ln.Close()
+}
+
+// Forward 把链接转交给 path 记录的正在运行的实例
+func Forward(path string, l Link) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ErrNoInstance
+	}
+	var info handoffInfo
+	if json.Unmarshal(data, &info) != nil {
+		return ErrNoInstance
+	}
+	conn, err := net.DialTimeout("tcp", info.Addr, handoffTimeout)
+	if err != nil {
+		return ErrNoInstance
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(handoffTimeout))
+	if err := json.NewEncoder(conn).Encode(handoffRequest{Secret: info.Secret, Link: l}); err != nil {
+		return err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || reply != "ok\n" {
+		return ErrNoInstance
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build darwin
+
+package deeplink
+
+// Register macOS 上由系统根据应用包的 Info.plist 注册，无需在运行时处理
+func Register(string) error {
+	return ErrBundled
+}
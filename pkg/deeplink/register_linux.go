@@ -0,0 +1,70 @@
+//go:build !windows && !darwin
+
+package deeplink
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"serial-assistant/pkg/capture"
+)
+
+// 桌面文件与 MIME 类型
+const (
+	desktopFile = "serial-assistant-handler.desktop"
+	captureMime = "application/x-serial-assistant-capture"
+)
+
+// Register 为当前用户注册 URL 协议与抓包文件关联 (XDG)，exe 为程序的绝对路径
+func Register(exe string) error {
+	data := os.Getenv("XDG_DATA_HOME")
+	if data == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		data = filepath.Join(home, ".local", "share")
+	}
+
+	desktop := fmt.Sprintf("[Desktop Entry]\nType=Application\nName=Serial Assistant\nExec=%s %%u\nNoDisplay=true\nMimeType=x-scheme-handler/%s;%s;\n",
+		quoteExec(exe), Scheme, captureMime)
+	mime := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<mime-info xmlns="http://www.freedesktop.org/standards/shared-mime-info">
+  <mime-type type="%s">
+    <comment>Serial Assistant capture</comment>
+    <glob pattern="*%s"/>
+  </mime-type>
+</mime-info>
+`, captureMime, capture.Ext)
+
+	files := []struct{ path, content string }{
+		{filepath.Join(data, "applications", desktopFile), desktop},
+		{filepath.Join(data, "mime", "packages", "serial-assistant.xml"), mime},
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(f.path, []byte(f.content), 0o644); err != nil {
+			return err
+		}
+	}
+	// MIME 数据库与桌面数据库的刷新失败不影响协议注册
+	exec.Command("update-mime-database", filepath.Join(data, "mime")).Run()
+	exec.Command("update-desktop-database", filepath.Join(data, "applications")).Run()
+	for _, typ := range []string{"x-scheme-handler/" + Scheme, captureMime} {
+		if out, err := exec.Command("xdg-mime", "default", desktopFile, typ).CombinedOutput(); err != nil {
+			return fmt.Errorf("xdg-mime: %w: %s", err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+// quoteExec 按桌面文件规范为 Exec 中的路径加引号
+func quoteExec(path string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", "$", `\$`)
+	return `"` + r.Replace(path) + `"`
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package deeplink
+
+import (
+	"fmt"
+
+	"serial-assistant/pkg/capture"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// captureProgID 抓包文件关联使用的 ProgID
+const captureProgID = "SerialAssistant.Capture"
+
+// Register 为当前用户注册 URL 协议与抓包文件关联，exe 为程序的绝对路径
+func Register(exe string) error {
+	command := fmt.Sprintf(`"%s" "%%1"`, exe)
+	entries := []struct{ path, name, value string }{
+		{`Software\Classes\` + Scheme, "", "URL:Serial Assistant"},
+		{`Software\Classes\` + Scheme, "URL Protocol", ""},
+		{`Software\Classes\` + Scheme + `\shell\open\command`, "", command},
+		{`Software\Classes\` + capture.Ext, "", captureProgID},
+		{`Software\Classes\` + captureProgID, "", "Serial Assistant capture"},
+		{`Software\Classes\` + captureProgID + `\shell\open\command`, "", command},
+	}
+	for _, e := range entries {
+		k, _, err := registry.CreateKey(registry.CURRENT_USER, e.path, registry.SET_VALUE)
+		if err != nil {
+			return fmt.Errorf("registry %s: %w", e.path, err)
+		}
+		err = k.SetStringValue(e.name, e.value)
+		k.Close()
+		if err != nil {
+			return fmt.Errorf("registry %s: %w", e.path, err)
+		}
+	}
+	return nil
+}
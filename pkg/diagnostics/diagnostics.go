@@ -0,0 +1,240 @@
+// Package diagnostics 后端诊断转储：goroutine 栈、堆 profile 与最近的内部日志打包为 zip，
+// 用于附在问题报告中；后台 goroutine 从 panic 中恢复时也会自动保存一份
+package diagnostics
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 默认值与限制
+const (
+	DefaultRingSize = 2000 // 保留的内部日志条数
+	MaxDumps        = 10   // 自动保存的转储最多保留的个数
+	Ext             = ".zip"
+	dumpPrefix      = "dump-"
+)
+
+// Entry 一条内部日志
+type Entry struct {
+	Time      time.Time         `json:"time"`
+	Level     string            `json:"level"`
+	Component string            `json:"component,omitempty"` // 产生日志的模块，如 serial、capture
+	Message   string            `json:"message"`
+	Attrs     map[string]string `json:"attrs,omitempty"`
+}
+
+// String 以单行文本格式化日志
+func (e Entry) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-7s", e.Time.Format("2006-01-02 15:04:05.000"), strings.ToUpper(e.Level))
+	if e.Component != "" {
+		b.WriteString(" [" + e.Component + "]")
+	}
+	b.WriteString(" " + e.Message)
+	keys := make([]string, 0, len(e.Attrs))
+	for k := range e.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%q", k, e.Attrs[k])
+	}
+	return b.String()
+}
+
+// Ring 最近内部日志的环形缓冲区，并发安全
+type Ring struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+// NewRing 创建容量为 size 的缓冲区
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		size = DefaultRingSize
+	}
+	return &Ring{entries: make([]Entry, size)}
+}
+
+// Add 追加一条日志，缓冲区满时覆盖最旧的一条
+func (r *Ring) Add(level, msg string) {
+	r.AddEntry(Entry{Time: time.Now(), Level: level, Message: msg})
+}
+
+// AddEntry 追加一条完整的日志
+func (r *Ring) AddEntry(e Entry) {
+	r.mu.Lock()
+	r.entries[r.next] = e
+	r.next++
+	if r.next == len(r.entries) {
+		r.next, r.full = 0, true
+	}
+	r.mu.Unlock()
+}
+
+// Entries 按时间顺序返回缓冲区中的日志
+func (r *Ring) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]Entry(nil), r.entries[:r.next]...)
+	}
+	out := make([]Entry, 0, len(r.entries))
+	out = append(out, r.entries[r.next:]...)
+	return append(out, r.entries[:r.next]...)
+}
+
+// Meta 转储的说明信息，写入 meta.json
+type Meta struct {
+	Time       time.Time `json:"time"`
+	Reason     string    `json:"reason"` // manual，或发生 panic 的 goroutine 名称
+	Version    string    `json:"version"`
+	OS         string    `json:"os"`
+	Arch       string    `json:"arch"`
+	GoVersion  string    `json:"goVersion"`
+	Goroutines int       `json:"goroutines"`
+	HeapAlloc  uint64    `json:"heapAlloc"`
+	Panic      string    `json:"panic,omitempty"`
+	Stack      string    `json:"stack,omitempty"` // 发生 panic 的 goroutine 栈
+}
+
+// NewMeta 填写版本与运行时信息
+func NewMeta(reason, version string) Meta {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	return Meta{
+		Time: time.Now(), Reason: reason, Version: version,
+		OS: runtime.GOOS, Arch: runtime.GOARCH, GoVersion: runtime.Version(),
+		Goroutines: runtime.NumGoroutine(), HeapAlloc: ms.HeapAlloc,
+	}
+}
+
+// Write 将转储写入 w：meta.json、goroutines.txt、heap.pprof、log.txt，有 panic 时另含 panic.txt
+func Write(w io.Writer, meta Meta, logs []Entry) error {
+	zw := zip.NewWriter(w)
+	add := func(name string, fn func(io.Writer) error) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: meta.Time})
+		if err != nil {
+			return err
+		}
+		return fn(f)
+	}
+	err := add("meta.json", func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(meta)
+	})
+	if err == nil && meta.Panic != "" {
+		err = add("panic.txt", func(w io.Writer) error {
+			_, err := fmt.Fprintf(w, "panic: %s\n\n%s", meta.Panic, meta.Stack)
+			return err
+		})
+	}
+	if err == nil {
+		err = add("goroutines.txt", func(w io.Writer) error {
+			return pprof.Lookup("goroutine").WriteTo(w, 2)
+		})
+	}
+	if err == nil {
+		err = add("heap.pprof", func(w io.Writer) error {
+			runtime.GC()
+			return pprof.Lookup("heap").WriteTo(w, 0)
+		})
+	}
+	if err == nil {
+		err = add("log.txt", func(w io.Writer) error {
+			for _, e := range logs {
+				if _, err := fmt.Fprintln(w, e.String()); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// WriteFile 将转储写入 path
+func WriteFile(path string, meta Meta, logs []Entry) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = Write(f, meta, logs)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
+
+// Save 在 dir 中保存一份转储并删除超出 MaxDumps 的旧转储，返回文件路径
+func Save(dir string, meta Meta, logs []Entry) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, dumpPrefix+meta.Time.Format("20060102-150405.000")+Ext)
+	if err := WriteFile(path, meta, logs); err != nil {
+		return "", err
+	}
+	list, err := List(dir)
+	if err != nil {
+		return path, nil
+	}
+	for _, d := range list[min(len(list), MaxDumps):] {
+		os.Remove(d.Path)
+	}
+	return path, nil
+}
+
+// DumpInfo 已保存的转储
+type DumpInfo struct {
+	Name string    `json:"name"`
+	Path string    `json:"path"`
+	Size int64     `json:"size"`
+	Time time.Time `json:"time"`
+}
+
+// List 列出 dir 中自动保存的转储，最新的在前
+func List(dir string) ([]DumpInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []DumpInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	list := []DumpInfo{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, dumpPrefix) || filepath.Ext(name) != Ext {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		list = append(list, DumpInfo{Name: name, Path: filepath.Join(dir, name), Size: info.Size(), Time: info.ModTime()})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name > list[j].Name })
+	return list, nil
+}
@@ -0,0 +1,84 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRing(t *testing.T) {
+	r := NewRing(3)
+	r.Add("info", "a")
+	r.Add("info", "b")
+	if got := r.Entries(); len(got) != 2 || got[0].Message != "a" {
+		t.Errorf("Unexpected entries %+v", got)
+	}
+	r.Add("warning", "c")
+	r.Add("error", "d")
+	got := r.Entries()
+	if len(got) != 3 || got[0].Message != "b" || got[2].Message != "d" {
+		t.Errorf("Ring should keep the newest entries in order, got %+v", got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	meta := NewMeta("read-loop", "v1.0.0")
+	meta.Panic, meta.Stack = "boom", "goroutine 1 [running]:"
+	var buf bytes.Buffer
+	entry := Entry{Time: time.Now(), Level: "error", Component: "serial", Message: "read failed", Attrs: map[string]string{"port": "COM3"}}
+	if err := Write(&buf, meta, []Entry{entry}); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, _ := f.Open()
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+	for _, name := range []string{"meta.json", "panic.txt", "goroutines.txt", "heap.pprof", "log.txt"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("Missing %s", name)
+		}
+	}
+	if !strings.Contains(files["goroutines.txt"], "TestWrite") {
+		t.Error("Goroutine dump should contain the test goroutine")
+	}
+	if !strings.Contains(files["log.txt"], `ERROR   [serial] read failed port="COM3"`) {
+		t.Errorf("Unexpected log %q", files["log.txt"])
+	}
+	if !strings.Contains(files["meta.json"], `"reason": "read-loop"`) {
+		t.Errorf("Unexpected meta %s", files["meta.json"])
+	}
+}
+
+func TestSavePrunes(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "other.zip"), nil, 0o644)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < MaxDumps+2; i++ {
+		meta := Meta{Time: start.Add(time.Duration(i) * time.Second), Reason: "test"}
+		if _, err := Save(dir, meta, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != MaxDumps || !strings.Contains(list[0].Name, "20260101-000011") {
+		t.Errorf("Expected newest %d dumps, got %d, first %s", MaxDumps, len(list), list[0].Name)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other.zip")); err != nil {
+		t.Error("Unrelated files should be kept")
+	}
+}
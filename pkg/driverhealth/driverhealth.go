@@ -0,0 +1,179 @@
+package driverhealth
+
+import (
+	"strconv"
+	"strings"
+
+	"serial-assistant/pkg/i18n"
+)
+
+// Severity 问题严重程度
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Issue 诊断发现的问题
+type Issue struct {
+	Code     string   `json:"code"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// Driver 串口使用的驱动信息，取不到的字段为空
+type Driver struct {
+	Name     string `json:"name"` // Linux 内核模块名，或 Windows 驱动服务名
+	Version  string `json:"version,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	Date     string `json:"date,omitempty"`
+}
+
+// Report 驱动健康诊断报告
+type Report struct {
+	Port   string `json:"port"`
+	VID    string `json:"vid,omitempty"`
+	PID    string `json:"pid,omitempty"`
+	Chip   string `json:"chip"`
+	Driver Driver `json:"driver"`
+
+	// FTDI 等芯片的延迟定时器 (毫秒)，-1 表示不适用或未知
+	LatencyTimerMs int `json:"latencyTimerMs"`
+
+	Issues []Issue `json:"issues"`
+}
+
+// chips 常见 USB 转串口芯片，键为 "VID:PID" (大写)
+var chips = map[string]string{
+	"1A86:7523": "CH340",
+	"1A86:5523": "CH341",
+	"1A86:7522": "CH340K",
+	"1A86:55D3": "CH343",
+	"1A86:55D4": "CH9102",
+	"10C4:EA60": "CP210x",
+	"10C4:EA70": "CP2105",
+	"10C4:EA71": "CP2108",
+	"0403:6001": "FT232R",
+	"0403:6010": "FT2232",
+	"0403:6011": "FT4232",
+	"0403:6014": "FT232H",
+	"0403:6015": "FT-X",
+	"0403:0000": "FTDI (PID 0000)",
+	"067B:2303": "PL2303",
+	"067B:23A3": "PL2303GC",
+	"2341:0043": "Arduino Uno (ATmega16U2)",
+	"0483:5740": "STM32 Virtual COM Port",
+	"303A:1001": "ESP32 USB-JTAG/Serial",
+}
+
+// ChipType 根据 VID/PID 推断转换芯片型号，未知时返回空字符串
+func ChipType(vid, pid string) string {
+	return chips[strings.ToUpper(vid)+":"+strings.ToUpper(pid)]
+}
+
+// defaultFTDILatency FTDI 驱动默认的延迟定时器
+const defaultFTDILatency = 16
+
+// Diagnose 收集串口的芯片型号、驱动版本及延迟定时器并检查已知问题
+func Diagnose(port, vid, pid string) Report {
+	r := Report{
+		Port:           port,
+		VID:            strings.ToUpper(vid),
+		PID:            strings.ToUpper(pid),
+		Chip:           ChipType(vid, pid),
+		LatencyTimerMs: -1,
+	}
+	r.Driver, r.LatencyTimerMs = driverInfo(port)
+	r.Issues = check(&r)
+	return r
+}
+
+// check 检查已知的问题驱动与配置
+func check(r *Report) []Issue {
+	issues := []Issue{}
+	add := func(code string, severity Severity, args ...interface{}) {
+		issues = append(issues, Issue{Code: code, Severity: severity, Message: i18n.T("driver."+code, args...)})
+	}
+
+	if r.Driver.Name == "" {
+		add("no_driver", SeverityError)
+	}
+
+	switch {
+	case r.VID == "0403" && r.PID == "0000":
+		// FTDI 2.11.00 驱动会将仿冒芯片的 PID 改写为 0000
+		add("ftdi_bricked", SeverityError)
+	case r.VID == "0403":
+		switch {
+		case versionPrefix(r.Driver.Version, "2.11.0"):
+			add("ftdi_bricking_driver", SeverityError, r.Driver.Version)
+		case versionPrefix(r.Driver.Version, "2.12.0"):
+			add("ftdi_nongenuine_driver", SeverityWarning, r.Driver.Version)
+		}
+		if r.LatencyTimerMs > 2 {
+			add("ftdi_latency", SeverityInfo, r.LatencyTimerMs)
+		}
+	case r.VID == "067B" && r.PID == "2303":
+		// 3.4.62 起的 Prolific 驱动不再支持停产 (多为仿冒) 的 PL2303HXA/XA，设备管理器显示代码 10
+		if compareVersion(r.Driver.Version, "3.4.62") >= 0 {
+			add("prolific_eol_driver", SeverityWarning, r.Driver.Version)
+		}
+	}
+	return issues
+}
+
+// versionPrefix 判断版本号是否属于 prefix 系列 (2.11.0 匹配 2.11.0.0、2.11.00)
+func versionPrefix(version, prefix string) bool {
+	if version == "" {
+		return false
+	}
+	v, p := parseVersion(version), parseVersion(prefix)
+	if len(v) < len(p) {
+		return false
+	}
+	for i := range p {
+		if v[i] != p[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersion 比较点分版本号，a 为空时视为最小
+func compareVersion(a, b string) int {
+	if a == "" {
+		return -1
+	}
+	va, vb := parseVersion(a), parseVersion(b)
+	for i := 0; i < len(va) || i < len(vb); i++ {
+		var x, y int
+		if i < len(va) {
+			x = va[i]
+		}
+		if i < len(vb) {
+			y = vb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func parseVersion(v string) []int {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(v), "v"), ".")
+	nums := make([]int, 0, len(parts))
+	for _, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			break
+		}
+		nums = append(nums, n)
+	}
+	return nums
+}
@@ -0,0 +1,42 @@
+//go:build linux
+
+package driverhealth
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// sysfsRoot sysfs 挂载点
+const sysfsRoot = "/sys"
+
+// driverInfo 从 sysfs 读取 tty 绑定的内核驱动、模块版本及 FTDI 延迟定时器
+func driverInfo(port string) (Driver, int) {
+	return sysfsDriverInfo(sysfsRoot, filepath.Base(port))
+}
+
+func sysfsDriverInfo(root, tty string) (Driver, int) {
+	var d Driver
+	latency := -1
+
+	device := filepath.Join(root, "class", "tty", tty, "device")
+	if target, err := filepath.EvalSymlinks(filepath.Join(device, "driver")); err == nil {
+		d.Name = filepath.Base(target)
+		// 内核自带的驱动通常没有独立版本号，退回内核版本
+		if v, err := os.ReadFile(filepath.Join(root, "module", d.Name, "version")); err == nil {
+			d.Version = strings.TrimSpace(string(v))
+		} else if v, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+			d.Version = strings.TrimSpace(string(v))
+		}
+		d.Provider = "Linux"
+	}
+
+	if v, err := os.ReadFile(filepath.Join(device, "latency_timer")); err == nil {
+		if n, err := strconv.Atoi(strings.TrimSpace(string(v))); err == nil {
+			latency = n
+		}
+	}
+	return d, latency
+}
@@ -0,0 +1,35 @@
+package driverhealth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSysfsDriverInfo(t *testing.T) {
+	root := t.TempDir()
+	device := filepath.Join(root, "devices", "usb1", "1-1", "1-1:1.0", "ttyUSB0")
+	driver := filepath.Join(root, "bus", "usb-serial", "drivers", "ftdi_sio")
+	os.MkdirAll(device, 0o755)
+	os.MkdirAll(driver, 0o755)
+	os.Symlink(driver, filepath.Join(device, "driver"))
+	os.WriteFile(filepath.Join(device, "latency_timer"), []byte("16\n"), 0o644)
+	os.MkdirAll(filepath.Join(root, "module", "ftdi_sio"), 0o755)
+	os.WriteFile(filepath.Join(root, "module", "ftdi_sio", "version"), []byte("1.6.0\n"), 0o644)
+
+	tty := filepath.Join(root, "class", "tty", "ttyUSB0")
+	os.MkdirAll(tty, 0o755)
+	os.Symlink(device, filepath.Join(tty, "device"))
+
+	d, latency := sysfsDriverInfo(root, "ttyUSB0")
+	if d.Name != "ftdi_sio" || d.Version != "1.6.0" {
+		t.Errorf("Unexpected driver %+v", d)
+	}
+	if latency != 16 {
+		t.Errorf("Expected latency 16, got %d", latency)
+	}
+
+	if d, latency := sysfsDriverInfo(root, "ttyS9"); d.Name != "" || latency != -1 {
+		t.Errorf("Missing tty should have no driver: %+v %d", d, latency)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !windows
+
+package driverhealth
+
+// driverInfo macOS 等平台暂不读取驱动信息，驱动名留空时不报告 no_driver
+func driverInfo(port string) (Driver, int) {
+	return Driver{Name: "system"}, -1
+}
@@ -0,0 +1,67 @@
+package driverhealth
+
+import "testing"
+
+func TestChipType(t *testing.T) {
+	if c := ChipType("1a86", "7523"); c != "CH340" {
+		t.Errorf("Expected CH340, got %q", c)
+	}
+	if c := ChipType("FFFF", "0001"); c != "" {
+		t.Errorf("Expected unknown chip, got %q", c)
+	}
+}
+
+func TestCompareVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"3.4.62.293", "3.4.62", 1},
+		{"3.3.2.102", "3.4.62", -1},
+		{"3.4.62", "3.4.62", 0},
+		{"", "1.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersion(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersion(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+	if !versionPrefix("2.11.0.0", "2.11.0") || versionPrefix("2.12.28.0", "2.11.0") {
+		t.Error("versionPrefix mismatch")
+	}
+}
+
+func hasIssue(r *Report, code string) bool {
+	for _, i := range check(r) {
+		if i.Code == code {
+			return i.Message != ""
+		}
+	}
+	return false
+}
+
+func TestCheck(t *testing.T) {
+	r := &Report{VID: "0403", PID: "6001", Driver: Driver{Name: "FTDIBUS", Version: "2.11.0.0"}, LatencyTimerMs: 16}
+	if !hasIssue(r, "ftdi_bricking_driver") || !hasIssue(r, "ftdi_latency") {
+		t.Errorf("Expected FTDI issues, got %+v", check(r))
+	}
+
+	r = &Report{VID: "0403", PID: "0000", Driver: Driver{Name: "ftdi_sio"}}
+	if !hasIssue(r, "ftdi_bricked") {
+		t.Error("Expected bricked FTDI issue")
+	}
+
+	r = &Report{VID: "067B", PID: "2303", Driver: Driver{Name: "Ser2pl", Version: "3.8.39.0"}}
+	if !hasIssue(r, "prolific_eol_driver") {
+		t.Error("Expected Prolific EOL driver issue")
+	}
+
+	r = &Report{VID: "1A86", PID: "7523", Driver: Driver{Name: "ch341"}, LatencyTimerMs: -1}
+	if issues := check(r); len(issues) != 0 {
+		t.Errorf("Expected no issues, got %+v", issues)
+	}
+
+	if !hasIssue(&Report{}, "no_driver") {
+		t.Error("Expected missing driver issue")
+	}
+}
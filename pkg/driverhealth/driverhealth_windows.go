@@ -0,0 +1,49 @@
+//go:build windows
+
+package driverhealth
+
+import (
+	"serial-assistant/pkg/ports"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// driverInfo 从注册表读取串口设备实例的驱动版本及 FTDI 延迟定时器
+// Enum 下的实例通过 Driver 值 ({类 GUID}\\NNNN) 指向 Control\\Class 中的驱动信息
+func driverInfo(port string) (Driver, int) {
+	var d Driver
+	latency := -1
+
+	instance := ports.InstanceID(port)
+	if instance == "" {
+		return d, latency
+	}
+	enum := `SYSTEM\CurrentControlSet\Enum\` + instance
+	d.Name = stringValue(enum, "Service")
+	if class := stringValue(enum, "Driver"); class != "" {
+		path := `SYSTEM\CurrentControlSet\Control\Class\` + class
+		d.Version = stringValue(path, "DriverVersion")
+		d.Provider = stringValue(path, "ProviderName")
+		d.Date = stringValue(path, "DriverDate")
+	}
+	if key, err := registry.OpenKey(registry.LOCAL_MACHINE, enum+`\Device Parameters`, registry.QUERY_VALUE); err == nil {
+		if v, _, err := key.GetIntegerValue("LatencyTimer"); err == nil {
+			latency = int(v)
+		}
+		key.Close()
+	}
+	return d, latency
+}
+
+func stringValue(path, name string) string {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE)
+	if err != nil {
+		return ""
+	}
+	defer key.Close()
+	v, _, err := key.GetStringValue(name)
+	if err != nil {
+		return ""
+	}
+	return v
+}
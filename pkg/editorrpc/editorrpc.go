@@ -0,0 +1,438 @@
+// Package editorrpc 供编辑器扩展 (如 VS Code) 使用的 JSON-RPC 2.0 接口：通过本机 WebSocket
+// 打开会话、发送命令并以通知的形式接收输出，应用继续负责硬件连接
+//
+// 连接 ws://127.0.0.1:<端口>/rpc?token=<令牌> 后发送请求，如
+//
+//	{"jsonrpc":"2.0","id":1,"method":"subscribe"}
+//	{"jsonrpc":"2.0","id":2,"method":"session.send","params":{"data":"help\r\n"}}
+//
+// 订阅后服务器推送 {"jsonrpc":"2.0","method":"output","params":{...}} 等通知
+package editorrpc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultPort 默认监听端口 (只监听 127.0.0.1)
+const DefaultPort = 47322
+
+// Config 编辑器接口配置
+type Config struct {
+	Enabled bool   `json:"enabled"`
+	Port    int    `json:"port"`            // 0 为 DefaultPort
+	Token   string `json:"token,omitempty"` // 访问令牌，启用时为空则自动生成；应用将其保存在钥匙串而非配置文件中
+}
+
+// Validate 检查配置是否有效
+func (c Config) Validate() error {
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("editorrpc: port %d out of range", c.Port)
+	}
+	return nil
+}
+
+// Addr 返回监听地址
+func (c Config) Addr() string {
+	port := c.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+}
+
+// NewToken 生成随机访问令牌
+func NewToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// JSON-RPC 2.0 预定义的错误码
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	// CodeServerError 应用返回的错误，Data 中带有应用的错误码
+	CodeServerError = -32000
+)
+
+// Error JSON-RPC 错误对象，也可由方法直接返回
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// InvalidParams 返回参数错误
+func InvalidParams(err error) *Error {
+	return &Error{Code: CodeInvalidParams, Message: err.Error()}
+}
+
+// request JSON-RPC 请求或通知 (无 id)
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// message 发给客户端的响应或通知
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  any             `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Method 一个 RPC 方法，params 为请求中的原始参数 (可能为空)
+// ctx 在客户端断开或服务器关闭时取消
+type Method func(ctx context.Context, params json.RawMessage) (any, error)
+
+// 内置方法：开始或停止接收通知
+const (
+	MethodSubscribe   = "subscribe"
+	MethodUnsubscribe = "unsubscribe"
+)
+
+// 限制
+const (
+	clientQueueSize = 1024
+	writeTimeout    = 10 * time.Second
+)
+
+// Client 一个已连接的编辑器
+type Client struct {
+	ID         string    `json:"id"`
+	Remote     string    `json:"remote"`
+	Connected  time.Time `json:"connected"`
+	Subscribed bool      `json:"subscribed"`
+	Dropped    uint64    `json:"dropped"` // 因处理慢而丢弃的通知数
+}
+
+type client struct {
+	Client
+	subscribed atomic.Bool
+	dropped    atomic.Uint64
+	send       chan message
+	done       chan struct{} // writeLoop 退出时关闭
+	conn       *websocket.Conn
+}
+
+// queue 将响应放入发送队列；写循环已退出 (写入失败) 或服务器关闭时返回 false，不会一直阻塞
+func (c *client) queue(ctx context.Context, m message) bool {
+	select {
+	case c.send <- m:
+		return true
+	case <-c.done:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// clientKey 方法的 ctx 中保存调用方信息的键
+type clientKey struct{}
+
+// ClientFrom 返回发起调用的客户端，供方法记录审计日志
+func ClientFrom(ctx context.Context) (Client, bool) {
+	c, ok := ctx.Value(clientKey{}).(Client)
+	return c, ok
+}
+
+// Server 编辑器 JSON-RPC 服务器
+type Server struct {
+	token     string
+	methods   map[string]Method
+	errorFunc func(error) *Error
+	upgrader  websocket.Upgrader
+
+	mu      sync.Mutex
+	clients map[string]*client
+	nextID  int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	srv    *http.Server
+	ln     net.Listener
+}
+
+// New 创建服务器；errorFunc 将方法返回的错误转换为 JSON-RPC 错误，为 nil 时按内部错误返回
+func New(token string, methods map[string]Method, errorFunc func(error) *Error) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		token: token, methods: methods, errorFunc: errorFunc,
+		clients: map[string]*client{}, ctx: ctx, cancel: cancel,
+	}
+	// 只监听本机且需要令牌，允许编辑器内的任意来源连接
+	s.upgrader.CheckOrigin = func(*http.Request) bool { return true }
+	s.srv = &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	return s
+}
+
+// Start 在 127.0.0.1 上开始监听
+func (s *Server) Start(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	s.ln = ln
+	go s.srv.Serve(ln)
+	return nil
+}
+
+// Addr 返回实际监听的地址
+func (s *Server) Addr() string {
+	if s.ln == nil {
+		return ""
+	}
+	return s.ln.Addr().String()
+}
+
+// Close 关闭服务器并断开所有客户端
+func (s *Server) Close() error {
+	s.cancel()
+	s.mu.Lock()
+	for _, c := range s.clients {
+		c.conn.Close()
+	}
+	s.mu.Unlock()
+	return s.srv.Close()
+}
+
+// Handler 返回处理 /rpc 的 http.Handler
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rpc", s.serveWS)
+	return mux
+}
+
+// Clients 返回已连接的客户端
+func (s *Server) Clients() []Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]Client, 0, len(s.clients))
+	for _, c := range s.clients {
+		v := c.Client
+		v.Subscribed = c.subscribed.Load()
+		v.Dropped = c.dropped.Load()
+		list = append(list, v)
+	}
+	return list
+}
+
+// Notify 向已订阅的客户端推送通知；客户端的队列已满时丢弃
+func (s *Server) Notify(method string, params any) {
+	m := message{JSONRPC: "2.0", Method: method, Params: params}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.clients {
+		if !c.subscribed.Load() {
+			continue
+		}
+		select {
+		case c.send <- m:
+		default:
+			c.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribers 返回已订阅通知的客户端数，为 0 时调用方可跳过准备通知内容
+func (s *Server) Subscribers() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, c := range s.clients {
+		if c.subscribed.Load() {
+			n++
+		}
+	}
+	return n
+}
+
+// requestToken 返回查询参数 token 或 Authorization: Bearer 中的令牌
+func requestToken(r *http.Request) string {
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return bearer
+	}
+	return r.URL.Query().Get("token")
+}
+
+func (s *Server) serveWS(w http.ResponseWriter, r *http.Request) {
+	if subtle.ConstantTimeCompare([]byte(requestToken(r)), []byte(s.token)) != 1 {
+		http.Error(w, "invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	s.nextID++
+	c := &client{
+		Client: Client{ID: strconv.Itoa(s.nextID), Remote: r.RemoteAddr, Connected: time.Now()},
+		send:   make(chan message, clientQueueSize),
+		done:   make(chan struct{}),
+		conn:   conn,
+	}
+	s.clients[c.ID] = c
+	s.mu.Unlock()
+
+	go s.writeLoop(c)
+	s.readLoop(c)
+}
+
+// readLoop 依次处理客户端的请求直到断开
+func (s *Server) readLoop(c *client) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer func() {
+		cancel()
+		s.mu.Lock()
+		delete(s.clients, c.ID)
+		s.mu.Unlock()
+		close(c.send)
+	}()
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if resp, ok := s.handle(ctx, c, data); ok && !c.queue(ctx, resp) {
+			return
+		}
+	}
+}
+
+// writeLoop 将响应与通知写入连接
+func (s *Server) writeLoop(c *client) {
+	defer close(c.done)
+	defer c.conn.Close()
+	for m := range c.send {
+		c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := c.conn.WriteJSON(m); err != nil {
+			return
+		}
+	}
+}
+
+// handle 处理一个请求；通知 (无 id) 不回复，ok 为 false
+func (s *Server) handle(ctx context.Context, c *client, data []byte) (resp message, ok bool) {
+	resp = message{JSONRPC: "2.0", ID: json.RawMessage("null")}
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		resp.Error = &Error{Code: CodeParseError, Message: "parse error"}
+		return resp, true
+	}
+	notification := len(req.ID) == 0
+	if !notification {
+		resp.ID = req.ID
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &Error{Code: CodeInvalidRequest, Message: "invalid request"}
+		return resp, true
+	}
+
+	result, err := s.call(ctx, c, req)
+	if err != nil {
+		resp.Error = s.toError(err)
+	} else {
+		if result == nil {
+			result = struct{}{}
+		}
+		resp.Result = result
+	}
+	return resp, !notification
+}
+
+// call 调用内置方法或注册的方法
+func (s *Server) call(ctx context.Context, c *client, req request) (any, error) {
+	switch req.Method {
+	case MethodSubscribe:
+		c.subscribed.Store(true)
+		return nil, nil
+	case MethodUnsubscribe:
+		c.subscribed.Store(false)
+		return nil, nil
+	}
+	m, ok := s.methods[req.Method]
+	if !ok {
+		return nil, &Error{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+	return m(context.WithValue(ctx, clientKey{}, c.Client), req.Params)
+}
+
+// toError 将方法返回的错误转换为 JSON-RPC 错误
+func (s *Server) toError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	if s.errorFunc != nil {
+		if e := s.errorFunc(err); e != nil {
+			return e
+		}
+	}
+	return &Error{Code: CodeInternalError, Message: err.Error()}
+}
+
+// Params 解析方法参数到 v，参数为空时保持 v 不变
+func Params(params json.RawMessage, v any) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return InvalidParams(err)
+	}
+	return nil
+}
+
+// Load 读取配置，文件不存在时返回默认配置 (关闭)
+func Load(path string) (Config, error) {
+	var c Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+// Save 保存配置
+func Save(path string, c Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
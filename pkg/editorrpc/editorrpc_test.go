@@ -0,0 +1,179 @@
+package editorrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type reply struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+var errOffline = errors.New("offline")
+
+func startTest(t *testing.T) (*Server, *websocket.Conn) {
+	t.Helper()
+	s := New("tok", map[string]Method{
+		"echo": func(ctx context.Context, params json.RawMessage) (any, error) {
+			var p struct {
+				Text string `json:"text"`
+			}
+			if err := Params(params, &p); err != nil {
+				return nil, err
+			}
+			return p, nil
+		},
+		"fail": func(context.Context, json.RawMessage) (any, error) { return nil, errOffline },
+		"whoami": func(ctx context.Context, _ json.RawMessage) (any, error) {
+			c, ok := ClientFrom(ctx)
+			if !ok {
+				return nil, errOffline
+			}
+			return c.ID, nil
+		},
+	}, func(err error) *Error {
+		if errors.Is(err, errOffline) {
+			return &Error{Code: CodeServerError, Message: err.Error(), Data: "NOT_CONNECTED"}
+		}
+		return nil
+	})
+	hs := httptest.NewServer(s.Handler())
+	t.Cleanup(hs.Close)
+	t.Cleanup(func() { s.Close() })
+
+	url := "ws" + strings.TrimPrefix(hs.URL, "http") + "/rpc"
+	if _, _, err := websocket.DefaultDialer.Dial(url, nil); err == nil {
+		t.Fatal("Expected connection without token to be rejected")
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?token=tok", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return s, conn
+}
+
+func call(t *testing.T, conn *websocket.Conn, req string) reply {
+	t.Helper()
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(req)); err != nil {
+		t.Fatal(err)
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var r reply
+	if err := conn.ReadJSON(&r); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestCall(t *testing.T) {
+	_, conn := startTest(t)
+
+	r := call(t, conn, `{"jsonrpc":"2.0","id":1,"method":"echo","params":{"text":"hi"}}`)
+	if string(r.ID) != "1" || r.Error != nil || string(r.Result) != `{"text":"hi"}` {
+		t.Errorf("Unexpected echo reply %+v", r)
+	}
+	r = call(t, conn, `{"jsonrpc":"2.0","id":"a","method":"fail"}`)
+	if r.Error == nil || r.Error.Code != CodeServerError || r.Error.Data != "NOT_CONNECTED" {
+		t.Errorf("Expected mapped error, got %+v", r)
+	}
+	r = call(t, conn, `{"jsonrpc":"2.0","id":2,"method":"nope"}`)
+	if r.Error == nil || r.Error.Code != CodeMethodNotFound {
+		t.Errorf("Expected method not found, got %+v", r)
+	}
+	r = call(t, conn, `{"jsonrpc":"2.0","id":3,"method":"echo","params":[1]}`)
+	if r.Error == nil || r.Error.Code != CodeInvalidParams {
+		t.Errorf("Expected invalid params, got %+v", r)
+	}
+	r = call(t, conn, `{not json`)
+	if r.Error == nil || r.Error.Code != CodeParseError || string(r.ID) != "null" {
+		t.Errorf("Expected parse error, got %+v", r)
+	}
+	r = call(t, conn, `{"id":4,"method":"echo"}`)
+	if r.Error == nil || r.Error.Code != CodeInvalidRequest {
+		t.Errorf("Expected invalid request, got %+v", r)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	s, conn := startTest(t)
+
+	// 通知形式的请求 (无 id) 不回复，未订阅时不推送
+	conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","method":"echo"}`))
+	s.Notify("output", "dropped")
+	if r := call(t, conn, `{"jsonrpc":"2.0","id":1,"method":"subscribe"}`); string(r.ID) != "1" || r.Error != nil {
+		t.Fatalf("Unexpected subscribe reply %+v", r)
+	}
+	if n := s.Subscribers(); n != 1 {
+		t.Fatalf("Subscribers = %d", n)
+	}
+	s.Notify("output", map[string]string{"text": "boot"})
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var r reply
+	if err := conn.ReadJSON(&r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Method != "output" || string(r.Params) != `{"text":"boot"}` || len(r.ID) != 0 {
+		t.Errorf("Unexpected notification %+v", r)
+	}
+	if list := s.Clients(); len(list) != 1 || !list[0].Subscribed {
+		t.Errorf("Unexpected clients %+v", list)
+	}
+}
+
+func TestClientFrom(t *testing.T) {
+	_, conn := startTest(t)
+	if r := call(t, conn, `{"jsonrpc":"2.0","id":1,"method":"whoami"}`); r.Error != nil || string(r.Result) != `"1"` {
+		t.Errorf("Unexpected caller %+v", r)
+	}
+}
+
+func TestQueueAfterWriteLoopExit(t *testing.T) {
+	c := &client{send: make(chan message, 1), done: make(chan struct{})}
+	if !c.queue(context.Background(), message{}) {
+		t.Fatal("Queue with room should accept")
+	}
+	close(c.done) // 写循环已退出，队列不再被取走
+	result := make(chan bool)
+	go func() { result <- c.queue(context.Background(), message{}) }()
+	select {
+	case ok := <-result:
+		if ok {
+			t.Error("Full queue after writeLoop exit should be rejected")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queue blocked after writeLoop exited")
+	}
+}
+
+func TestConfig(t *testing.T) {
+	if (Config{}).Addr() != "127.0.0.1:47322" {
+		t.Errorf("Addr = %s", Config{}.Addr())
+	}
+	if err := (Config{Port: -1}).Validate(); err == nil {
+		t.Error("Expected port error")
+	}
+	if a, b := NewToken(), NewToken(); len(a) != 32 || a == b {
+		t.Errorf("Unexpected tokens %q %q", a, b)
+	}
+	path := filepath.Join(t.TempDir(), "editor.json")
+	want := Config{Enabled: true, Port: 5000, Token: "t"}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Load(path); err != nil || got != want {
+		t.Errorf("Load = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,170 @@
+package escpos
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+)
+
+// 控制字符
+const (
+	esc = 0x1b
+	gs  = 0x1d
+	dle = 0x10
+	eot = 0x04
+	lf  = 0x0a
+)
+
+// 对齐方式
+const (
+	AlignLeft   = 0
+	AlignCenter = 1
+	AlignRight  = 2
+)
+
+// Builder 逐步拼装 ESC/POS 打印命令
+// 文本按原样写入，编码需与打印机当前代码页 (CodePage) 一致
+type Builder struct {
+	buf bytes.Buffer
+}
+
+// NewBuilder 创建以 ESC @ (初始化打印机) 开头的命令序列
+func NewBuilder() *Builder {
+	b := &Builder{}
+	return b.Init()
+}
+
+// Init 初始化打印机，清除之前的格式设置
+func (b *Builder) Init() *Builder {
+	b.buf.Write([]byte{esc, '@'})
+	return b
+}
+
+// Raw 追加原始字节
+func (b *Builder) Raw(p []byte) *Builder {
+	b.buf.Write(p)
+	return b
+}
+
+// Text 追加文本，不换行
+func (b *Builder) Text(s string) *Builder {
+	b.buf.WriteString(s)
+	return b
+}
+
+// Line 追加文本并换行
+func (b *Builder) Line(s string) *Builder {
+	b.buf.WriteString(s)
+	b.buf.WriteByte(lf)
+	return b
+}
+
+// CodePage 选择字符代码页 (ESC t n)
+func (b *Builder) CodePage(n byte) *Builder {
+	b.buf.Write([]byte{esc, 't', n})
+	return b
+}
+
+// Bold 开关加粗 (ESC E n)
+func (b *Builder) Bold(on bool) *Builder {
+	b.buf.Write([]byte{esc, 'E', flag(on)})
+	return b
+}
+
+// Underline 设置下划线，0 关闭，1 细线，2 粗线 (ESC - n)
+func (b *Builder) Underline(n byte) *Builder {
+	if n > 2 {
+		n = 2
+	}
+	b.buf.Write([]byte{esc, '-', n})
+	return b
+}
+
+// Align 设置对齐方式 (ESC a n)
+func (b *Builder) Align(n byte) *Builder {
+	if n > AlignRight {
+		n = AlignLeft
+	}
+	b.buf.Write([]byte{esc, 'a', n})
+	return b
+}
+
+// Size 设置字符放大倍数，宽高均为 1~8 (GS ! n)
+func (b *Builder) Size(width, height int) *Builder {
+	b.buf.Write([]byte{gs, '!', byte(clamp(width, 1, 8)-1)<<4 | byte(clamp(height, 1, 8)-1)})
+	return b
+}
+
+// Feed 走纸 n 行 (ESC d n)
+func (b *Builder) Feed(n int) *Builder {
+	b.buf.Write([]byte{esc, 'd', byte(clamp(n, 0, 255))})
+	return b
+}
+
+// Cut 走纸到切刀位置并切纸，partial 为半切 (GS V m n)
+func (b *Builder) Cut(partial bool) *Builder {
+	m := byte('A')
+	if partial {
+		m = 'B'
+	}
+	b.buf.Write([]byte{gs, 'V', m, 0})
+	return b
+}
+
+// QR 打印二维码 (GS ( k，模型 2)，size 为模块大小 1~16，纠错等级 L/M/Q/H
+func (b *Builder) QR(data string, size int, level byte) *Builder {
+	levels := map[byte]byte{'L': 48, 'M': 49, 'Q': 50, 'H': 51}
+	lv, ok := levels[level]
+	if !ok {
+		lv = 49
+	}
+	fn := func(cn, fn byte, params ...byte) {
+		n := len(params) + 2
+		b.buf.Write([]byte{gs, '(', 'k', byte(n), byte(n >> 8), cn, fn})
+		b.buf.Write(params)
+	}
+	fn('1', 'A', '2', 0)
+	fn('1', 'C', byte(clamp(size, 1, 16)))
+	fn('1', 'E', lv)
+	fn('1', 'P', append([]byte{'0'}, data...)...)
+	fn('1', 'Q', '0')
+	return b
+}
+
+// Image 以光栅位图 (GS v 0) 打印图像，亮度低于 threshold (0~255) 的像素打印为黑点
+// 宽度不是 8 的倍数时右侧补白
+func (b *Builder) Image(img image.Image, threshold uint8) *Builder {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	rowBytes := (w + 7) / 8
+	b.buf.Write([]byte{gs, 'v', '0', 0, byte(rowBytes), byte(rowBytes >> 8), byte(h), byte(h >> 8)})
+	row := make([]byte, rowBytes)
+	for y := 0; y < h; y++ {
+		clear(row)
+		for x := 0; x < w; x++ {
+			c := color.GrayModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray)
+			_, _, _, alpha := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			if alpha > 0x7fff && c.Y < threshold {
+				row[x/8] |= 0x80 >> (x % 8)
+			}
+		}
+		b.buf.Write(row)
+	}
+	return b
+}
+
+// Bytes 返回拼装好的命令
+func (b *Builder) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+func flag(on bool) byte {
+	if on {
+		return 1
+	}
+	return 0
+}
+
+func clamp(v, lo, hi int) int {
+	return min(max(v, lo), hi)
+}
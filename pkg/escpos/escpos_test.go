@@ -0,0 +1,101 @@
+package escpos
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/decoder"
+)
+
+func TestBuilder(t *testing.T) {
+	got := NewBuilder().Align(AlignCenter).Bold(true).Size(2, 3).Line("HI").Feed(2).Cut(true).Bytes()
+	want := []byte{0x1b, '@', 0x1b, 'a', 1, 0x1b, 'E', 1, 0x1d, '!', 0x12, 'H', 'I', '\n', 0x1b, 'd', 2, 0x1d, 'V', 'B', 0}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Unexpected commands\n got % x\nwant % x", got, want)
+	}
+
+	qr := (&Builder{}).QR("ab", 4, 'H').Bytes()
+	store := []byte{0x1d, '(', 'k', 5, 0, '1', 'P', '0', 'a', 'b'}
+	if !bytes.Contains(qr, store) {
+		t.Errorf("QR store command missing: % x", qr)
+	}
+}
+
+func TestImage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 10, 2))
+	for x := 0; x < 10; x++ {
+		img.SetGray(x, 0, color.Gray{Y: 255})
+		img.SetGray(x, 1, color.Gray{Y: 255})
+	}
+	img.SetGray(0, 0, color.Gray{Y: 0})
+	img.SetGray(9, 1, color.Gray{Y: 10})
+	got := (&Builder{}).Image(img, 128).Bytes()
+	want := []byte{0x1d, 'v', '0', 0, 2, 0, 2, 0, 0x80, 0x00, 0x00, 0x40}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Unexpected raster\n got % x\nwant % x", got, want)
+	}
+}
+
+func TestRealtimeStatus(t *testing.T) {
+	var s Status
+	// 脱机，盖子打开，缺纸
+	ParseRealtime(&s, 1, 0x1a)
+	ParseRealtime(&s, 2, 0x36)
+	ParseRealtime(&s, 3, 0x12)
+	ParseRealtime(&s, 4, 0x7e)
+	if s.Online || !s.CoverOpen || !s.PaperOut || !s.PaperNearEnd || s.CutterError {
+		t.Errorf("Unexpected status %+v", s)
+	}
+	if err := ParseRealtime(&s, 1, 0xff); err == nil {
+		t.Error("Expected an error for a byte with wrong fixed bits")
+	}
+}
+
+func TestProber(t *testing.T) {
+	replies := map[byte]byte{1: 0x16, 2: 0x12, 3: 0x12, 4: 0x12}
+	var p *Prober
+	p = NewProber(func(_ context.Context, b []byte) error {
+		go p.Feed([]byte{'x', replies[b[2]]})
+		return nil
+	})
+	s, err := p.Status(context.Background(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Online || !s.DrawerOpen || len(s.Problems()) != 0 {
+		t.Errorf("Unexpected status %+v", s)
+	}
+
+	silent := NewProber(func(context.Context, []byte) error { return nil })
+	if _, err := silent.Status(context.Background(), 20*time.Millisecond); err == nil {
+		t.Error("Expected a timeout without replies")
+	}
+}
+
+func TestStatusDecoder(t *testing.T) {
+	d, err := decoder.New("escpos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var frames []decoder.Frame
+	emit := func(f decoder.Frame) { frames = append(frames, f) }
+	// ASB 分两段到达：盖子打开，纸将尽
+	d.Decode(time.Now(), []byte{0x30, 0x00}, emit)
+	d.Decode(time.Now(), []byte{0x03, 0x00, 0x12}, emit)
+	if len(frames) != 2 {
+		t.Fatalf("Expected 2 frames, got %+v", frames)
+	}
+	if v, _ := frames[0].Get("escpos.cover_open"); v != true {
+		t.Errorf("Expected cover open, got %+v", frames[0])
+	}
+	if v, _ := frames[0].Get("escpos.paper_near_end"); v != true {
+		t.Errorf("Expected paper near end, got %+v", frames[0])
+	}
+	if v, _ := frames[1].Get("escpos.kind"); v != "realtime" {
+		t.Errorf("Expected a real-time frame, got %+v", frames[1])
+	}
+}
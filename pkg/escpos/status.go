@@ -0,0 +1,253 @@
+package escpos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"serial-assistant/pkg/decoder"
+)
+
+func init() {
+	decoder.Register(decoder.Info{Name: "escpos", Description: "ESC/POS printer status (real-time status and automatic status back)", Source: "builtin"}, func() decoder.Decoder { return &statusDecoder{} })
+}
+
+// Status 打印机状态，未查询到的项保持零值
+type Status struct {
+	Online          bool `json:"online"`
+	DrawerOpen      bool `json:"drawerOpen"` // 钱箱接口第 3 脚为高电平
+	CoverOpen       bool `json:"coverOpen"`
+	FeedButton      bool `json:"feedButton"` // 正在按走纸键走纸
+	PaperOut        bool `json:"paperOut"`
+	PaperNearEnd    bool `json:"paperNearEnd"`
+	Error           bool `json:"error"`
+	CutterError     bool `json:"cutterError"`
+	Unrecoverable   bool `json:"unrecoverable"`
+	AutoRecoverable bool `json:"autoRecoverable"`
+}
+
+// Problems 返回需要处理的异常，无异常时为空
+func (s Status) Problems() []string {
+	var list []string
+	for _, p := range []struct {
+		set  bool
+		name string
+	}{
+		{!s.Online, "offline"}, {s.CoverOpen, "cover open"}, {s.PaperOut, "paper out"},
+		{s.PaperNearEnd, "paper near end"}, {s.CutterError, "cutter error"},
+		{s.Unrecoverable, "unrecoverable error"}, {s.AutoRecoverable, "auto-recoverable error"},
+	} {
+		if p.set {
+			list = append(list, p.name)
+		}
+	}
+	return list
+}
+
+// ErrBadStatus 状态字节的固定位不符合 ESC/POS 规范
+var ErrBadStatus = errors.New("escpos: invalid status byte")
+
+// IsRealtime 判断字节是否为实时状态应答 (固定位 0xx1xx10)
+func IsRealtime(b byte) bool {
+	return b&0x93 == 0x12
+}
+
+// ParseRealtime 将 DLE EOT n 的单字节应答合并到 s
+// n: 1 打印机状态，2 脱机原因，3 错误原因，4 纸卷传感器
+func ParseRealtime(s *Status, n int, b byte) error {
+	if !IsRealtime(b) {
+		return fmt.Errorf("%w: %#02x", ErrBadStatus, b)
+	}
+	bit := func(i uint) bool { return b&(1<<i) != 0 }
+	switch n {
+	case 1:
+		s.DrawerOpen = bit(2)
+		s.Online = !bit(3)
+	case 2:
+		s.CoverOpen = bit(2)
+		s.FeedButton = bit(3)
+		s.PaperOut = bit(5)
+		s.Error = bit(6)
+	case 3:
+		s.CutterError = bit(3)
+		s.Unrecoverable = bit(5)
+		s.AutoRecoverable = bit(6)
+	case 4:
+		s.PaperNearEnd = b&0x0c == 0x0c
+		s.PaperOut = b&0x60 == 0x60
+	default:
+		return fmt.Errorf("escpos: invalid status request %d", n)
+	}
+	return nil
+}
+
+// IsASB 判断 4 字节是否为自动状态返回 (GS a 启用)
+func IsASB(p []byte) bool {
+	if len(p) < 4 || p[0]&0x93 != 0x10 {
+		return false
+	}
+	for _, b := range p[1:4] {
+		if b&0x90 != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseASB 解析自动状态返回的 4 字节
+func ParseASB(p []byte) (Status, error) {
+	if !IsASB(p) {
+		return Status{}, ErrBadStatus
+	}
+	return Status{
+		DrawerOpen:      p[0]&0x04 != 0,
+		Online:          p[0]&0x08 == 0,
+		CoverOpen:       p[0]&0x20 != 0,
+		FeedButton:      p[0]&0x40 != 0,
+		CutterError:     p[1]&0x08 != 0,
+		Unrecoverable:   p[1]&0x20 != 0,
+		AutoRecoverable: p[1]&0x40 != 0,
+		Error:           p[1]&0x6c != 0,
+		PaperNearEnd:    p[2]&0x03 != 0,
+		PaperOut:        p[2]&0x0c != 0,
+	}, nil
+}
+
+// Prober 发送 DLE EOT 查询并等待单字节应答
+// 接收数据需要通过 Feed 交给 Prober，没有等待中的查询时数据被丢弃
+type Prober struct {
+	write func(ctx context.Context, p []byte) error
+	mu    sync.Mutex // 串行化查询
+	ch    chan byte
+}
+
+// NewProber 创建查询器，write 发送命令到打印机
+func NewProber(write func(ctx context.Context, p []byte) error) *Prober {
+	return &Prober{write: write, ch: make(chan byte, 16)}
+}
+
+// Feed 处理一段接收数据，只保留实时状态字节
+func (p *Prober) Feed(data []byte) {
+	for _, b := range data {
+		if !IsRealtime(b) {
+			continue
+		}
+		select {
+		case p.ch <- b:
+		default:
+		}
+	}
+}
+
+// Status 依次查询四类实时状态，timeout 为单次查询的等待时间
+func (p *Prober) Status(ctx context.Context, timeout time.Duration) (Status, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var s Status
+	for n := 1; n <= 4; n++ {
+		// 丢弃上一次查询之后残留的字节
+		for len(p.ch) > 0 {
+			<-p.ch
+		}
+		if err := p.write(ctx, []byte{dle, eot, byte(n)}); err != nil {
+			return s, err
+		}
+		t := time.NewTimer(timeout)
+		select {
+		case b := <-p.ch:
+			t.Stop()
+			ParseRealtime(&s, n, b)
+		case <-t.C:
+			return s, fmt.Errorf("escpos: no reply to DLE EOT %d", n)
+		case <-ctx.Done():
+			t.Stop()
+			return s, ctx.Err()
+		}
+	}
+	return s, nil
+}
+
+// statusDecoder 解码自动状态返回；单独的实时状态字节无法得知对应的查询，仅原样标出
+type statusDecoder struct {
+	pending []byte
+}
+
+func (d *statusDecoder) Name() string { return "escpos" }
+
+func (d *statusDecoder) Detect(sample []byte) float64 {
+	if len(sample) == 0 {
+		return 0
+	}
+	matched := 0
+	for i := 0; i < len(sample); {
+		switch {
+		case IsASB(sample[i:]):
+			matched += 4
+			i += 4
+		case IsRealtime(sample[i]):
+			matched++
+			i++
+		default:
+			i++
+		}
+	}
+	return float64(matched) / float64(len(sample)) * 0.6
+}
+
+func (d *statusDecoder) Decode(t time.Time, data []byte, emit func(decoder.Frame)) {
+	d.pending = append(d.pending, data...)
+	i := 0
+	for i < len(d.pending) {
+		rest := d.pending[i:]
+		switch {
+		case rest[0]&0x93 == 0x10 && len(rest) < 4:
+			// ASB 首字节，等待剩余字节
+			d.pending = append(d.pending[:0], rest...)
+			return
+		case IsASB(rest):
+			s, _ := ParseASB(rest)
+			emit(asbFrame(t, rest[:4], s))
+			i += 4
+		case IsRealtime(rest[0]):
+			emit(decoder.Frame{
+				Protocol: "escpos",
+				Time:     t,
+				Raw:      []byte{rest[0]},
+				Summary:  fmt.Sprintf("real-time status %#02x", rest[0]),
+				Fields: []decoder.Field{
+					{Name: "escpos.kind", Value: "realtime"},
+					{Name: "escpos.byte", Value: int64(rest[0])},
+				},
+			})
+			i++
+		default:
+			i++
+		}
+	}
+	d.pending = d.pending[:0]
+}
+
+func asbFrame(t time.Time, raw []byte, s Status) decoder.Frame {
+	summary := "ok"
+	if p := s.Problems(); len(p) > 0 {
+		summary = strings.Join(p, ", ")
+	}
+	return decoder.Frame{
+		Protocol: "escpos",
+		Time:     t,
+		Raw:      append([]byte(nil), raw...),
+		Summary:  "ASB " + summary,
+		Fields: []decoder.Field{
+			{Name: "escpos.kind", Value: "asb"},
+			{Name: "escpos.online", Value: s.Online},
+			{Name: "escpos.cover_open", Value: s.CoverOpen},
+			{Name: "escpos.paper_out", Value: s.PaperOut},
+			{Name: "escpos.paper_near_end", Value: s.PaperNearEnd},
+			{Name: "escpos.drawer_open", Value: s.DrawerOpen},
+			{Name: "escpos.error", Value: s.Error},
+		},
+	}
+}
@@ -0,0 +1,144 @@
+package eventbus
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Topic 事件主题
+type Topic string
+
+// Event 总线上传递的事件
+// Payload 会被所有订阅者共享，订阅者只能读取，不能修改 (例如 []byte 数据)
+type Event struct {
+	Topic   Topic
+	Payload interface{}
+	Time    time.Time
+}
+
+// Shared 需要引用计数的负载 (如池化缓冲区)
+// 每投递给一个订阅者 Retain 一次，订阅者处理完后负责 Release；因队列已满被丢弃时不会 Retain
+type Shared interface {
+	Retain()
+	Release()
+}
+
+// Bus 进程内发布/订阅事件总线
+// 发布方与订阅方互不感知：前端转发、日志、脚本等各自订阅所需主题即可
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// Subscription 一个订阅，通过 C 接收事件
+type Subscription struct {
+	C <-chan Event
+
+	ch      chan Event
+	topics  map[Topic]bool // 为 nil 时接收所有主题
+	bus     *Bus
+	dropped atomic.Uint64
+	once    sync.Once
+}
+
+// New 创建事件总线
+func New() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscribe 订阅指定主题 (不指定则订阅全部主题)
+// buffer 为订阅队列长度，队列满时新事件会被丢弃并计入 Dropped
+func (b *Bus) Subscribe(buffer int, topics ...Topic) *Subscription {
+	if buffer < 1 {
+		buffer = 1
+	}
+	ch := make(chan Event, buffer)
+	s := &Subscription{C: ch, ch: ch, bus: b}
+	if len(topics) > 0 {
+		s.topics = make(map[Topic]bool, len(topics))
+		for _, t := range topics {
+			s.topics[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return s
+	}
+	b.subs[s] = struct{}{}
+	return s
+}
+
+// Publish 发布事件，不会阻塞发布方
+// 负载实现 Shared 时，发布方在 Publish 返回后仍持有自己的引用，需自行 Release
+func (b *Bus) Publish(topic Topic, payload interface{}) {
+	ev := Event{Topic: topic, Payload: payload, Time: time.Now()}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+	shared, _ := payload.(Shared)
+	for s := range b.subs {
+		if s.topics != nil && !s.topics[topic] {
+			continue
+		}
+		if shared != nil {
+			shared.Retain()
+		}
+		select {
+		case s.ch <- ev:
+		default:
+			s.dropped.Add(1)
+			if shared != nil {
+				shared.Release()
+			}
+		}
+	}
+}
+
+// Close 关闭总线，所有订阅的通道都会被关闭
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for s := range b.subs {
+		delete(b.subs, s)
+		close(s.ch)
+	}
+}
+
+// Close 取消订阅并关闭通道，可重复调用
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		defer s.bus.mu.Unlock()
+		if _, ok := s.bus.subs[s]; ok {
+			delete(s.bus.subs, s)
+			close(s.ch)
+		}
+	})
+}
+
+// Len 返回队列中待处理的事件数
+func (s *Subscription) Len() int {
+	return len(s.ch)
+}
+
+// Cap 返回队列长度
+func (s *Subscription) Cap() int {
+	return cap(s.ch)
+}
+
+// Dropped 返回因队列已满而丢弃的事件数
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
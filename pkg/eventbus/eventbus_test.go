@@ -0,0 +1,107 @@
+package eventbus
+
+import (
+	"testing"
+	"time"
+)
+
+func receive(t *testing.T, s *Subscription) Event {
+	t.Helper()
+	select {
+	case ev := <-s.C:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return Event{}
+}
+
+func TestPublishSubscribe(t *testing.T) {
+	b := New()
+	all := b.Subscribe(8)
+	data := b.Subscribe(8, "data")
+
+	b.Publish("data", []byte{1, 2, 3})
+	b.Publish("msg", "hello")
+
+	if ev := receive(t, all); ev.Topic != "data" {
+		t.Errorf("Expected data event first, got %s", ev.Topic)
+	}
+	if ev := receive(t, all); ev.Topic != "msg" || ev.Payload.(string) != "hello" {
+		t.Errorf("Unexpected event: %+v", ev)
+	}
+
+	ev := receive(t, data)
+	if ev.Topic != "data" || len(ev.Payload.([]byte)) != 3 {
+		t.Errorf("Unexpected event: %+v", ev)
+	}
+	select {
+	case ev := <-data.C:
+		t.Errorf("Topic filter leaked event: %+v", ev)
+	default:
+	}
+}
+
+func TestSlowSubscriberDropsWithoutBlocking(t *testing.T) {
+	b := New()
+	s := b.Subscribe(2)
+
+	for i := 0; i < 5; i++ {
+		b.Publish("data", i)
+	}
+	if s.Dropped() != 3 {
+		t.Errorf("Expected 3 dropped events, got %d", s.Dropped())
+	}
+}
+
+func TestCloseSubscription(t *testing.T) {
+	b := New()
+	s := b.Subscribe(1)
+	s.Close()
+	s.Close()
+
+	b.Publish("data", 1)
+	if _, ok := <-s.C; ok {
+		t.Error("Expected closed channel after unsubscribe")
+	}
+}
+
+func TestCloseBus(t *testing.T) {
+	b := New()
+	s := b.Subscribe(1)
+	b.Close()
+
+	if _, ok := <-s.C; ok {
+		t.Error("Expected subscription channel closed with bus")
+	}
+	// 关闭后的订阅与发布都不应 panic
+	late := b.Subscribe(1)
+	b.Publish("data", 1)
+	s.Close()
+	if _, ok := <-late.C; ok {
+		t.Error("Expected subscription on closed bus to be closed")
+	}
+}
+
+type counted struct{ refs int }
+
+func (c *counted) Retain()  { c.refs++ }
+func (c *counted) Release() { c.refs-- }
+
+func TestSharedPayloadRetainedPerDelivery(t *testing.T) {
+	b := New()
+	a := b.Subscribe(1)
+	full := b.Subscribe(1)
+	b.Subscribe(1, "other")
+	b.Publish("data", "filler")
+	<-a.C
+
+	p := &counted{refs: 1}
+	b.Publish("data", p)
+	if p.refs != 2 {
+		t.Errorf("Expected one retained reference for the delivered subscriber, got %d", p.refs-1)
+	}
+	if full.Dropped() != 1 {
+		t.Errorf("Expected the full subscriber to drop the event, got %d", full.Dropped())
+	}
+}
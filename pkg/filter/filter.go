@@ -0,0 +1,338 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"serial-assistant/pkg/decoder"
+)
+
+// Filter 编译后的显示过滤表达式
+//
+// 语法与 Wireshark 的显示过滤器类似：
+//
+//	modbus.func == 3 && modbus.addr > 100
+//	nmea and not nmea.valid
+//	text.line contains "ERROR" || text.line matches "^panic"
+//
+// 单独的协议名 (如 modbus) 匹配该协议的帧，单独的字段名匹配包含该字段的帧；
+// 帧中不存在的字段参与比较时结果为假。此外可使用 frame.protocol、frame.len 与 frame.error
+type Filter struct {
+	src  string
+	root node
+}
+
+// Compile 编译表达式，空表达式匹配所有帧
+func Compile(expr string) (*Filter, error) {
+	f := &Filter{src: strings.TrimSpace(expr)}
+	if f.src == "" {
+		return f, nil
+	}
+	tokens, err := lex(f.src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("filter: unexpected %q at offset %d", t.text, t.pos)
+	}
+	f.root = root
+	return f, nil
+}
+
+// String 返回原始表达式
+func (f *Filter) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.src
+}
+
+// Match 判断帧是否满足表达式
+func (f *Filter) Match(frame *decoder.Frame) bool {
+	if f == nil || f.root == nil {
+		return true
+	}
+	return f.root.eval(frame)
+}
+
+type node interface {
+	eval(f *decoder.Frame) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(f *decoder.Frame) bool { return n.left.eval(f) && n.right.eval(f) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(f *decoder.Frame) bool { return n.left.eval(f) || n.right.eval(f) }
+
+type notNode struct{ inner node }
+
+func (n notNode) eval(f *decoder.Frame) bool { return !n.inner.eval(f) }
+
+// existsNode 单独的协议名或字段名；布尔字段按其值判断
+type existsNode struct{ name string }
+
+func (n existsNode) eval(f *decoder.Frame) bool {
+	if n.name == f.Protocol {
+		return true
+	}
+	v, ok := lookup(f, n.name)
+	if b, isBool := v.(bool); ok && isBool {
+		return b
+	}
+	return ok
+}
+
+type compareNode struct {
+	field string
+	op    string
+	value interface{}    // int64、float64、string 或 bool
+	re    *regexp.Regexp // matches
+}
+
+func (n compareNode) eval(f *decoder.Frame) bool {
+	v, ok := lookup(f, n.field)
+	if !ok {
+		return false
+	}
+	switch n.op {
+	case "contains":
+		s, ok := v.(string)
+		return ok && strings.Contains(s, n.value.(string))
+	case "matches":
+		s, ok := v.(string)
+		return ok && n.re.MatchString(s)
+	}
+	c, ok := compare(v, n.value)
+	if !ok {
+		return false
+	}
+	switch n.op {
+	case "==":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// lookup 读取字段值，frame.* 为帧本身的属性
+func lookup(f *decoder.Frame, name string) (interface{}, bool) {
+	switch name {
+	case "frame.protocol":
+		return f.Protocol, true
+	case "frame.len":
+		return int64(len(f.Raw)), true
+	case "frame.error":
+		return f.Error, true
+	}
+	return f.Get(name)
+}
+
+// compare 比较两个值，类型不兼容时返回 false
+func compare(a, b interface{}) (int, bool) {
+	switch x := a.(type) {
+	case string:
+		y, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(x, y), true
+	case bool:
+		y, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		if x == y {
+			return 0, true
+		}
+		if !x {
+			return -1, true
+		}
+		return 1, true
+	}
+	if x, ok := a.(int64); ok {
+		if y, ok := b.(int64); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	x, ok1 := toFloat(a)
+	y, ok2 := toFloat(b)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	switch {
+	case x < y:
+		return -1, true
+	case x > y:
+		return 1, true
+	}
+	return 0, true
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case int64:
+		return float64(x), true
+	case float64:
+		return x, true
+	}
+	return 0, false
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if r := p.next(); r.kind != tokRParen {
+			return nil, fmt.Errorf("filter: expected ) at offset %d", r.pos)
+		}
+		return inner, nil
+	case tokIdent:
+		if p.peek().kind != tokOp {
+			return existsNode{t.text}, nil
+		}
+		op := p.next()
+		return p.parseComparison(t.text, op)
+	case tokEOF:
+		return nil, fmt.Errorf("filter: unexpected end of expression")
+	}
+	return nil, fmt.Errorf("filter: unexpected %q at offset %d", t.text, t.pos)
+}
+
+func (p *parser) parseComparison(field string, op token) (node, error) {
+	t := p.next()
+	n := compareNode{field: field, op: op.text}
+	switch t.kind {
+	case tokString:
+		n.value = t.text
+	case tokNumber:
+		v, err := parseNumber(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("filter: invalid number %q at offset %d", t.text, t.pos)
+		}
+		n.value = v
+	case tokIdent:
+		// true/false，其余未加引号的单词按字符串处理
+		switch strings.ToLower(t.text) {
+		case "true":
+			n.value = true
+		case "false":
+			n.value = false
+		default:
+			n.value = t.text
+		}
+	default:
+		return nil, fmt.Errorf("filter: expected value after %s at offset %d", op.text, t.pos)
+	}
+
+	switch n.op {
+	case "contains", "matches":
+		s, ok := n.value.(string)
+		if !ok {
+			return nil, fmt.Errorf("filter: %s requires a string at offset %d", n.op, t.pos)
+		}
+		if n.op == "matches" {
+			re, err := regexp.Compile(s)
+			if err != nil {
+				return nil, fmt.Errorf("filter: %v", err)
+			}
+			n.re = re
+		}
+	}
+	return n, nil
+}
+
+// parseNumber 解析十进制、0x 十六进制或浮点数
+func parseNumber(s string) (interface{}, error) {
+	if i, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return i, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
@@ -0,0 +1,86 @@
+package filter
+
+import (
+	"testing"
+
+	"serial-assistant/pkg/decoder"
+)
+
+var modbusFrame = decoder.Frame{
+	Protocol: "modbus",
+	Raw:      make([]byte, 8),
+	Fields: []decoder.Field{
+		{Name: "modbus.addr", Value: int64(120)},
+		{Name: "modbus.func", Value: int64(3)},
+	},
+}
+
+var textFrame = decoder.Frame{
+	Protocol: "text",
+	Fields: []decoder.Field{
+		{Name: "text.line", Value: "E (123) wifi: ERROR timeout"},
+	},
+}
+
+var nmeaFrame = decoder.Frame{
+	Protocol: "nmea",
+	Error:    "checksum mismatch",
+	Fields: []decoder.Field{
+		{Name: "nmea.valid", Value: false},
+		{Name: "nmea.lat", Value: 48.1173},
+	},
+}
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		expr  string
+		frame decoder.Frame
+		want  bool
+	}{
+		{"modbus.func == 3 && modbus.addr > 100", modbusFrame, true},
+		{"modbus.func == 3 && modbus.addr > 200", modbusFrame, false},
+		{"modbus.func eq 0x03 or modbus.addr lt 0", modbusFrame, true},
+		{"modbus", modbusFrame, true},
+		{"modbus", textFrame, false},
+		{"!modbus", textFrame, true},
+		{"modbus.func != 3", textFrame, false},
+		{`text.line contains "ERROR"`, textFrame, true},
+		{`text.line matches "^E \\(\\d+\\)"`, textFrame, true},
+		{"text.line ~ '^W'", textFrame, false},
+		{"nmea and not nmea.valid", nmeaFrame, true},
+		{"nmea.valid == false", nmeaFrame, true},
+		{"nmea.lat > 48 && nmea.lat < 48.2", nmeaFrame, true},
+		{`frame.error != ""`, nmeaFrame, true},
+		{"frame.len >= 8", modbusFrame, true},
+		{"(modbus || nmea) && frame.protocol == text", textFrame, false},
+		{"modbus.func contains \"3\"", modbusFrame, false},
+		{"", textFrame, true},
+	}
+	for _, c := range cases {
+		f, err := Compile(c.expr)
+		if err != nil {
+			t.Errorf("Compile(%q) failed: %v", c.expr, err)
+			continue
+		}
+		if got := f.Match(&c.frame); got != c.want {
+			t.Errorf("%q on %s: got %v, want %v", c.expr, c.frame.Protocol, got, c.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, expr := range []string{
+		"modbus.func ==",
+		"(modbus",
+		"modbus &&",
+		"a == 1 b",
+		`text.line contains 3`,
+		`text.line matches "("`,
+		`text.line == "open`,
+		"a # 1",
+	} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Expected Compile(%q) to fail", expr)
+		}
+	}
+}
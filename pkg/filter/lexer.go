@@ -0,0 +1,149 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp     // == != < <= > >= contains matches
+	tokAnd    // && and
+	tokOr     // || or
+	tokNot    // ! not
+	tokLParen // (
+	tokRParen // )
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// keywords 作为单词出现的运算符
+var keywords = map[string]tokenKind{
+	"and":      tokAnd,
+	"or":       tokOr,
+	"not":      tokNot,
+	"contains": tokOp,
+	"matches":  tokOp,
+	"eq":       tokOp,
+	"ne":       tokOp,
+	"gt":       tokOp,
+	"ge":       tokOp,
+	"lt":       tokOp,
+	"le":       tokOp,
+}
+
+// wordOps 单词形式的比较运算符对应的符号
+var wordOps = map[string]string{
+	"eq": "==", "ne": "!=", "gt": ">", "ge": ">=", "lt": "<", "le": "<=",
+}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case strings.HasPrefix(src[i:], "&&"):
+			tokens = append(tokens, token{tokAnd, "&&", i})
+			i += 2
+		case strings.HasPrefix(src[i:], "||"):
+			tokens = append(tokens, token{tokOr, "||", i})
+			i += 2
+		case strings.HasPrefix(src[i:], "=="), strings.HasPrefix(src[i:], "!="),
+			strings.HasPrefix(src[i:], "<="), strings.HasPrefix(src[i:], ">="):
+			tokens = append(tokens, token{tokOp, src[i : i+2], i})
+			i += 2
+		case c == '<' || c == '>':
+			tokens = append(tokens, token{tokOp, src[i : i+1], i})
+			i++
+		case c == '~':
+			tokens = append(tokens, token{tokOp, "matches", i})
+			i++
+		case c == '!':
+			tokens = append(tokens, token{tokNot, "!", i})
+			i++
+		case c == '"' || c == '\'':
+			s, n, err := lexString(src[i:])
+			if err != nil {
+				return nil, fmt.Errorf("filter: %v at offset %d", err, i)
+			}
+			tokens = append(tokens, token{tokString, s, i})
+			i += n
+		case c == '-' || c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(src) && isWordByte(src[j]) {
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, src[i:j], i})
+			i = j
+		case isWordByte(c):
+			j := i
+			for j < len(src) && (isWordByte(src[j]) || src[j] == '.') {
+				j++
+			}
+			word := src[i:j]
+			if kind, ok := keywords[strings.ToLower(word)]; ok {
+				text := strings.ToLower(word)
+				if op, ok := wordOps[text]; ok {
+					text = op
+				}
+				tokens = append(tokens, token{kind, text, i})
+			} else {
+				tokens = append(tokens, token{tokIdent, word, i})
+			}
+			i = j
+		default:
+			return nil, fmt.Errorf("filter: unexpected character %q at offset %d", c, i)
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(src)}), nil
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '.' || c < 0x80 && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)))
+}
+
+// lexString 读取带引号的字符串，支持 \" \\ \n \t 转义；返回内容与消耗的字节数
+func lexString(src string) (string, int, error) {
+	quote := src[0]
+	var b strings.Builder
+	for i := 1; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\\' && i+1 < len(src):
+			i++
+			switch src[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(src[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
@@ -0,0 +1,116 @@
+package framediff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"serial-assistant/pkg/decoder"
+)
+
+// maxListed 摘要中最多列出的变化字节数
+const maxListed = 8
+
+// Change 一个字节的变化
+type Change struct {
+	Offset int  `json:"offset"`
+	Old    byte `json:"old"`
+	New    byte `json:"new"`
+}
+
+// Differ 将重复出现的帧与同类的上一帧逐字节比较，可并发使用
+// 同类指协议、长度以及前两个字段 (通常是地址与类型) 都相同
+type Differ struct {
+	mu       sync.Mutex
+	prev     map[string][]byte
+	repeated uint64
+}
+
+// New 创建比较器
+func New() *Differ {
+	return &Differ{prev: map[string][]byte{}}
+}
+
+// Compare 返回与同类上一帧相比变化的字节，first 表示该类帧第一次出现
+func (d *Differ) Compare(f *decoder.Frame) (changes []Change, first bool) {
+	k := key(f)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	prev, ok := d.prev[k]
+	if !ok {
+		d.prev[k] = append([]byte(nil), f.Raw...)
+		return nil, true
+	}
+	for i := range f.Raw {
+		if f.Raw[i] != prev[i] {
+			changes = append(changes, Change{Offset: i, Old: prev[i], New: f.Raw[i]})
+		}
+	}
+	copy(prev, f.Raw)
+	if len(changes) == 0 {
+		d.repeated++
+	}
+	return changes, false
+}
+
+// Apply 将帧转换为差异帧：首次出现的帧原样返回 (附加 diff.first)；
+// 有变化的帧只在摘要中列出变化的偏移与值；完全相同的帧返回 false，不应显示
+// 没有原始数据的帧 (如间隔标记) 原样返回
+func (d *Differ) Apply(f decoder.Frame) (decoder.Frame, bool) {
+	if len(f.Raw) == 0 {
+		return f, true
+	}
+	changes, first := d.Compare(&f)
+	if first {
+		f.Fields = append(f.Fields, decoder.Field{Name: "diff.first", Value: true})
+		return f, true
+	}
+	if len(changes) == 0 {
+		return f, false
+	}
+	offsets := make([]string, len(changes))
+	listed := make([]string, 0, maxListed)
+	for i, c := range changes {
+		offsets[i] = strconv.Itoa(c.Offset)
+		if i < maxListed {
+			listed = append(listed, fmt.Sprintf("@%d %02X→%02X", c.Offset, c.Old, c.New))
+		}
+	}
+	summary := strings.Join(listed, " ")
+	if len(changes) > maxListed {
+		summary += fmt.Sprintf(" (+%d more)", len(changes)-maxListed)
+	}
+	fields := make([]decoder.Field, 0, len(f.Fields)+2)
+	fields = append(fields, f.Fields...)
+	f.Fields = append(fields,
+		decoder.Field{Name: "diff.changes", Value: int64(len(changes))},
+		decoder.Field{Name: "diff.offsets", Value: strings.Join(offsets, ",")},
+	)
+	f.Summary = summary
+	return f, true
+}
+
+// Repeated 返回被省略的完全相同的帧数
+func (d *Differ) Repeated() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.repeated
+}
+
+// Reset 忘记所有已见过的帧
+func (d *Differ) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.prev = map[string][]byte{}
+	d.repeated = 0
+}
+
+func key(f *decoder.Frame) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s/%d", f.Protocol, len(f.Raw))
+	for i := 0; i < len(f.Fields) && i < 2; i++ {
+		fmt.Fprintf(&b, "/%v", f.Fields[i].Value)
+	}
+	return b.String()
+}
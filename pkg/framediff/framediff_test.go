@@ -0,0 +1,68 @@
+package framediff
+
+import (
+	"testing"
+
+	"serial-assistant/pkg/decoder"
+)
+
+func status(addr int64, raw ...byte) decoder.Frame {
+	return decoder.Frame{
+		Protocol: "sensor",
+		Raw:      raw,
+		Summary:  "status",
+		Fields:   []decoder.Field{{Name: "sensor.addr", Value: addr}},
+	}
+}
+
+func TestApply(t *testing.T) {
+	d := New()
+	f, ok := d.Apply(status(1, 0xAA, 0x10, 0x20, 0x30))
+	if v, _ := f.Get("diff.first"); !ok || v != true {
+		t.Fatalf("First frame should pass through, got %+v", f)
+	}
+	if _, ok := d.Apply(status(1, 0xAA, 0x10, 0x20, 0x30)); ok {
+		t.Error("Identical frame should be suppressed")
+	}
+	// 不同地址的帧单独比较
+	if f, _ := d.Apply(status(2, 0xAA, 0x11, 0x20, 0x30)); f.Summary != "status" {
+		t.Errorf("Frame from another peer should be new, got %+v", f)
+	}
+
+	f, ok = d.Apply(status(1, 0xAA, 0x10, 0x21, 0x31))
+	if !ok || f.Summary != "@2 20→21 @3 30→31" {
+		t.Errorf("Unexpected diff summary %q", f.Summary)
+	}
+	if v, _ := f.Get("diff.offsets"); v != "2,3" {
+		t.Errorf("Unexpected offsets %v", v)
+	}
+	if d.Repeated() != 1 {
+		t.Errorf("Expected 1 repeated frame, got %d", d.Repeated())
+	}
+
+	gap := decoder.Frame{Protocol: decoder.GapProtocol}
+	if _, ok := d.Apply(gap); !ok {
+		t.Error("Frames without raw data should pass through")
+	}
+
+	d.Reset()
+	if f, _ := d.Apply(status(1, 0xAA, 0x10, 0x21, 0x31)); f.Summary != "status" {
+		t.Error("Reset should forget previous frames")
+	}
+}
+
+func TestLongDiffIsTruncated(t *testing.T) {
+	d := New()
+	d.Apply(status(1, make([]byte, 12)...))
+	raw := make([]byte, 12)
+	for i := range raw {
+		raw[i] = 1
+	}
+	f, _ := d.Apply(status(1, raw...))
+	if v, _ := f.Get("diff.changes"); v != int64(12) {
+		t.Errorf("Expected 12 changes, got %v", v)
+	}
+	if want := " (+4 more)"; f.Summary[len(f.Summary)-len(want):] != want {
+		t.Errorf("Expected truncated summary, got %q", f.Summary)
+	}
+}
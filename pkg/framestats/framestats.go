@@ -0,0 +1,155 @@
+package framestats
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"serial-assistant/pkg/decoder"
+)
+
+// typeFields 各协议区分帧类型的字段
+var typeFields = map[string]string{
+	"modbus":  "modbus.func",
+	"nmea":    "nmea.type",
+	"mavlink": "mavlink.msgid",
+}
+
+// peerFields 各协议区分会话 (从站地址、系统 ID 等) 的字段
+var peerFields = map[string]string{
+	"modbus":  "modbus.addr",
+	"nmea":    "nmea.talker",
+	"mavlink": "mavlink.sysid",
+}
+
+// Counter 一组帧的计数
+type Counter struct {
+	Count     uint64    `json:"count"`
+	Bytes     uint64    `json:"bytes"`
+	Errors    uint64    `json:"errors"`
+	ErrorRate float64   `json:"errorRate"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+func (c *Counter) add(f *decoder.Frame) {
+	if c.Count == 0 {
+		c.FirstSeen = f.Time
+	}
+	c.Count++
+	c.Bytes += uint64(len(f.Raw))
+	if f.Error != "" {
+		c.Errors++
+	}
+	c.ErrorRate = float64(c.Errors) / float64(c.Count)
+	c.LastSeen = f.Time
+}
+
+// TypeStats 某协议中一种帧类型的统计
+type TypeStats struct {
+	Protocol string `json:"protocol"`
+	Type     string `json:"type"`
+	Counter
+}
+
+// Conversation 与某个从站/节点之间的通信统计
+type Conversation struct {
+	Protocol string `json:"protocol"`
+	Peer     string `json:"peer"`
+	Counter
+}
+
+type key struct {
+	protocol, name string
+}
+
+// Collector 累计解码帧的分类统计，可并发使用
+type Collector struct {
+	mu    sync.Mutex
+	types map[key]*Counter
+	peers map[key]*Counter
+}
+
+// New 创建统计器
+func New() *Collector {
+	return &Collector{types: map[key]*Counter{}, peers: map[key]*Counter{}}
+}
+
+// Add 统计一帧
+func (c *Collector) Add(f *decoder.Frame) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counter(c.types, key{f.Protocol, fieldString(f, typeFields[f.Protocol])}).add(f)
+	if name, ok := peerFields[f.Protocol]; ok {
+		if _, present := f.Get(name); present {
+			counter(c.peers, key{f.Protocol, fieldString(f, name)}).add(f)
+		}
+	}
+}
+
+// Types 返回各帧类型的统计，按协议和类型排序
+func (c *Collector) Types() []TypeStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := make([]TypeStats, 0, len(c.types))
+	for k, v := range c.types {
+		list = append(list, TypeStats{Protocol: k.protocol, Type: k.name, Counter: *v})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Protocol != list[j].Protocol {
+			return list[i].Protocol < list[j].Protocol
+		}
+		return list[i].Type < list[j].Type
+	})
+	return list
+}
+
+// Conversations 返回各会话的统计，按帧数从多到少排序
+func (c *Collector) Conversations() []Conversation {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	list := make([]Conversation, 0, len(c.peers))
+	for k, v := range c.peers {
+		list = append(list, Conversation{Protocol: k.protocol, Peer: k.name, Counter: *v})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		if list[i].Protocol != list[j].Protocol {
+			return list[i].Protocol < list[j].Protocol
+		}
+		return list[i].Peer < list[j].Peer
+	})
+	return list
+}
+
+// Reset 清空统计
+func (c *Collector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.types = map[key]*Counter{}
+	c.peers = map[key]*Counter{}
+}
+
+func counter(m map[key]*Counter, k key) *Counter {
+	c, ok := m[k]
+	if !ok {
+		c = &Counter{}
+		m[k] = c
+	}
+	return c
+}
+
+// fieldString 将字段值格式化为分类名，字段不存在时返回空字符串
+func fieldString(f *decoder.Frame, name string) string {
+	if name == "" {
+		return ""
+	}
+	v, ok := f.Get(name)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,59 @@
+package framestats
+
+import (
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/decoder"
+)
+
+func modbus(addr, fn int64, errMsg string, at time.Time) *decoder.Frame {
+	return &decoder.Frame{
+		Protocol: "modbus",
+		Time:     at,
+		Raw:      make([]byte, 8),
+		Error:    errMsg,
+		Fields: []decoder.Field{
+			{Name: "modbus.addr", Value: addr},
+			{Name: "modbus.func", Value: fn},
+		},
+	}
+}
+
+func TestTypesAndConversations(t *testing.T) {
+	c := New()
+	t0 := time.Unix(100, 0)
+	c.Add(modbus(1, 3, "", t0))
+	c.Add(modbus(1, 3, "", t0.Add(time.Second)))
+	c.Add(modbus(2, 6, "crc", t0.Add(2*time.Second)))
+	c.Add(modbus(1, 3, "crc", t0.Add(3*time.Second)))
+	c.Add(&decoder.Frame{Protocol: "text", Raw: []byte("hi\n")})
+
+	types := c.Types()
+	if len(types) != 3 {
+		t.Fatalf("Expected 3 frame types, got %+v", types)
+	}
+	if types[0].Type != "3" || types[0].Count != 3 || types[0].Bytes != 24 || types[0].Errors != 1 {
+		t.Errorf("Unexpected stats for func 3: %+v", types[0])
+	}
+	if types[2].Protocol != "text" || types[2].Type != "" {
+		t.Errorf("Expected text without a type, got %+v", types[2])
+	}
+
+	convs := c.Conversations()
+	if len(convs) != 2 {
+		t.Fatalf("Expected 2 conversations, got %+v", convs)
+	}
+	first := convs[0]
+	if first.Peer != "1" || first.Count != 3 || first.ErrorRate < 0.33 || first.ErrorRate > 0.34 {
+		t.Errorf("Unexpected conversation: %+v", first)
+	}
+	if !first.FirstSeen.Equal(t0) || !first.LastSeen.Equal(t0.Add(3*time.Second)) {
+		t.Errorf("Unexpected first/last seen: %+v", first)
+	}
+
+	c.Reset()
+	if len(c.Types()) != 0 || len(c.Conversations()) != 0 {
+		t.Error("Reset should clear all stats")
+	}
+}
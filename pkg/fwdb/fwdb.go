@@ -0,0 +1,231 @@
+package fwdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxVersions 每台设备保留的版本记录数，超出时丢弃最早见到的版本
+const maxVersions = 50
+
+// Observation 一次会话中观察到的设备与固件版本
+type Observation struct {
+	Serial   string    // 设备自报的序列号
+	USB      string    // USB 标识，如 "1A86:7523:5&1A2B"
+	Name     string    // 设备名
+	Port     string    // 串口名或连接地址
+	Firmware string    // 固件版本
+	Time     time.Time // 观察时间
+}
+
+// Key 返回设备标识：依次使用设备序列号、USB 序列号、设备名与端口，都没有时返回空
+func (o Observation) Key() string {
+	switch {
+	case o.Serial != "":
+		return "sn:" + o.Serial
+	case o.USB != "":
+		return "usb:" + o.USB
+	case o.Name != "":
+		return "name:" + o.Name
+	case o.Port != "":
+		return "port:" + o.Port
+	}
+	return ""
+}
+
+// Version 一个固件版本的观察记录
+type Version struct {
+	Version   string    `json:"version"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Sessions  int       `json:"sessions"`
+}
+
+// Device 一台设备及其固件版本历史 (按首次出现从早到晚排序)
+type Device struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name,omitempty"`
+	Port     string    `json:"port,omitempty"` // 最近一次连接使用的端口
+	LastSeen time.Time `json:"lastSeen"`
+	Current  string    `json:"current"` // 最近一次观察到的版本
+	Versions []Version `json:"versions"`
+}
+
+// Change 观察结果相对于历史的变化
+type Change struct {
+	Device   Device `json:"device"`
+	New      bool   `json:"new"`      // 首次见到该设备
+	Previous string `json:"previous"` // 版本发生变化时为之前的版本
+}
+
+var (
+	ErrNoIdentity = errors.New("fwdb: observation has no device identity")
+	ErrNoVersion  = errors.New("fwdb: observation has no firmware version")
+	ErrNotFound   = errors.New("fwdb: no such device")
+)
+
+// DB 持久化到 JSON 文件的设备固件版本库
+type DB struct {
+	path    string
+	mu      sync.RWMutex
+	devices map[string]*Device
+}
+
+// Open 加载 path 处的版本库，文件不存在时为空
+func Open(path string) (*DB, error) {
+	db := &DB{path: path, devices: make(map[string]*Device)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return db, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []*Device
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("fwdb: %s: %w", path, err)
+	}
+	for _, d := range list {
+		db.devices[d.ID] = d
+	}
+	return db, nil
+}
+
+// Observe 记录一次观察并保存
+func (db *DB) Observe(o Observation) (Change, error) {
+	key := o.Key()
+	if key == "" {
+		return Change{}, ErrNoIdentity
+	}
+	o.Firmware = strings.TrimSpace(o.Firmware)
+	if o.Firmware == "" {
+		return Change{}, ErrNoVersion
+	}
+	if o.Time.IsZero() {
+		o.Time = time.Now()
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var ch Change
+	d := db.devices[key]
+	if d == nil {
+		d = &Device{ID: key}
+		ch.New = true
+	} else {
+		d = d.clone()
+		if d.Current != o.Firmware {
+			ch.Previous = d.Current
+		}
+	}
+	if o.Name != "" {
+		d.Name = o.Name
+	}
+	if o.Port != "" {
+		d.Port = o.Port
+	}
+	d.LastSeen = o.Time
+	d.Current = o.Firmware
+
+	found := false
+	for i := range d.Versions {
+		if v := &d.Versions[i]; v.Version == o.Firmware {
+			v.LastSeen = o.Time
+			v.Sessions++
+			found = true
+		}
+	}
+	if !found {
+		d.Versions = append(d.Versions, Version{Version: o.Firmware, FirstSeen: o.Time, LastSeen: o.Time, Sessions: 1})
+		if len(d.Versions) > maxVersions {
+			d.Versions = d.Versions[len(d.Versions)-maxVersions:]
+		}
+	}
+
+	old := db.devices[key]
+	db.devices[key] = d
+	if err := db.saveLocked(); err != nil {
+		if old == nil {
+			delete(db.devices, key)
+		} else {
+			db.devices[key] = old
+		}
+		return Change{}, err
+	}
+	ch.Device = *d.clone()
+	return ch, nil
+}
+
+// List 返回全部设备，按最近出现从新到旧排序
+func (db *DB) List() []Device {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	list := make([]Device, 0, len(db.devices))
+	for _, d := range db.devices {
+		list = append(list, *d.clone())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].LastSeen.After(list[j].LastSeen)
+	})
+	return list
+}
+
+// Get 返回指定设备
+func (db *DB) Get(id string) (Device, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	d, ok := db.devices[id]
+	if !ok {
+		return Device{}, ErrNotFound
+	}
+	return *d.clone(), nil
+}
+
+// Delete 删除设备及其历史
+func (db *DB) Delete(id string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	d, ok := db.devices[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(db.devices, id)
+	if err := db.saveLocked(); err != nil {
+		db.devices[id] = d
+		return err
+	}
+	return nil
+}
+
+func (db *DB) saveLocked() error {
+	list := make([]*Device, 0, len(db.devices))
+	for _, d := range db.devices {
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(db.path), 0o755); err != nil {
+		return err
+	}
+	tmp := db.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, db.path)
+}
+
+func (d *Device) clone() *Device {
+	c := *d
+	c.Versions = append([]Version(nil), d.Versions...)
+	return &c
+}
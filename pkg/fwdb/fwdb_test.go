@@ -0,0 +1,87 @@
+package fwdb
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestKeyPriority(t *testing.T) {
+	cases := []struct {
+		o    Observation
+		want string
+	}{
+		{Observation{Serial: "A1", USB: "1A86:7523:X", Name: "gw", Port: "COM3"}, "sn:A1"},
+		{Observation{USB: "1A86:7523:X", Name: "gw", Port: "COM3"}, "usb:1A86:7523:X"},
+		{Observation{Name: "gw", Port: "COM3"}, "name:gw"},
+		{Observation{Port: "COM3"}, "port:COM3"},
+		{Observation{}, ""},
+	}
+	for _, c := range cases {
+		if got := c.o.Key(); got != c.want {
+			t.Errorf("Key(%+v) = %q, want %q", c.o, got, c.want)
+		}
+	}
+}
+
+func TestObserveHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "firmware.json")
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t0 := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	ch, err := db.Observe(Observation{Serial: "A1", Name: "Gateway", Port: "COM3", Firmware: "v1.0", Time: t0})
+	if err != nil || !ch.New || ch.Previous != "" {
+		t.Fatalf("Unexpected first change %+v, %v", ch, err)
+	}
+	db.Observe(Observation{Serial: "A1", Port: "COM3", Firmware: "v1.0", Time: t0.Add(time.Hour)})
+	ch, _ = db.Observe(Observation{Serial: "A1", Port: "COM7", Firmware: "v1.1", Time: t0.Add(2 * time.Hour)})
+	if ch.New || ch.Previous != "v1.0" {
+		t.Errorf("Expected upgrade from v1.0, got %+v", ch)
+	}
+
+	// 重新打开后历史仍在
+	db, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := db.Get("sn:A1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "Gateway" || d.Port != "COM7" || d.Current != "v1.1" || len(d.Versions) != 2 {
+		t.Errorf("Unexpected device %+v", d)
+	}
+	if v := d.Versions[0]; v.Version != "v1.0" || v.Sessions != 2 || !v.LastSeen.Equal(t0.Add(time.Hour)) {
+		t.Errorf("Unexpected version record %+v", v)
+	}
+
+	if _, err := db.Observe(Observation{Firmware: "v1"}); err != ErrNoIdentity {
+		t.Errorf("Expected ErrNoIdentity, got %v", err)
+	}
+	if _, err := db.Observe(Observation{Port: "COM1"}); err != ErrNoVersion {
+		t.Errorf("Expected ErrNoVersion, got %v", err)
+	}
+}
+
+func TestListAndDelete(t *testing.T) {
+	db, _ := Open(filepath.Join(t.TempDir(), "firmware.json"))
+	t0 := time.Now()
+	db.Observe(Observation{Port: "COM1", Firmware: "1", Time: t0})
+	db.Observe(Observation{Port: "COM2", Firmware: "2", Time: t0.Add(time.Minute)})
+	list := db.List()
+	if len(list) != 2 || list[0].ID != "port:COM2" {
+		t.Errorf("Unexpected list %+v", list)
+	}
+	if err := db.Delete("port:COM2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete("port:COM2"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if len(db.List()) != 1 {
+		t.Error("Device should be deleted")
+	}
+}
@@ -0,0 +1,210 @@
+package gpsclock
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 参数
+const (
+	windowSize  = 16              // 参与中值计算的最近偏差样本数
+	minSamples  = 3               // 锁定所需的样本数
+	holdover    = 3 * time.Second // 超过该时间没有新样本视为失锁
+	pulseWindow = time.Second     // 时间语句与之前 PPS 脉冲的最大间隔
+)
+
+// 时间来源
+const (
+	SourceNone = ""
+	SourcePPS  = "pps"  // PPS 脉冲沿 + NMEA 时间，精度取决于 DCD 采样间隔
+	SourceNMEA = "nmea" // 只有 NMEA 语句到达时间，精度约数十到数百毫秒
+)
+
+// Status 驯服状态
+type Status struct {
+	Locked   bool      `json:"locked"`
+	Source   string    `json:"source"`
+	OffsetNs int64     `json:"offsetNs"` // GPS 时间减去主机时间
+	JitterNs int64     `json:"jitterNs"` // 样本偏差的四分位距的一半
+	Samples  int       `json:"samples"`
+	Pulses   uint64    `json:"pulses"`
+	LastFix  time.Time `json:"lastFix"` // 最近一次有效 GPS 时间 (UTC)
+}
+
+// Clock 用 GPS 的 NMEA 时间与 PPS 秒脉冲估计主机时钟的偏差，并据此校正其他数据的时间戳
+type Clock struct {
+	mu      sync.Mutex
+	pulse   time.Time // 最近一次 PPS 脉冲的主机时间
+	pulses  uint64
+	offsets []time.Duration
+	source  string
+	last    time.Time // 最近一个样本的主机时间
+	lastFix time.Time
+	offset  time.Duration
+	jitter  time.Duration
+}
+
+// New 创建时钟
+func New() *Clock {
+	return &Clock{}
+}
+
+// Pulse 记录 host 时刻检测到的 PPS 上升沿
+func (c *Clock) Pulse(host time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pulse = host
+	c.pulses++
+}
+
+// Sentence 处理 host 时刻收到的一行 NMEA 语句，语句中带有有效的 UTC 时间时更新偏差
+// PPS 脉冲标记整秒的开始，随后输出的 RMC/ZDA 语句给出该秒的时间
+func (c *Clock) Sentence(host time.Time, line string) {
+	gps, ok := ParseTime(line)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastFix = gps
+
+	ref, source := host, SourceNMEA
+	if !c.pulse.IsZero() && host.Sub(c.pulse) >= 0 && host.Sub(c.pulse) < pulseWindow {
+		ref, source = c.pulse, SourcePPS
+		c.pulse = time.Time{} // 一个脉冲只对应一条时间语句
+	} else if c.source == SourcePPS && host.Sub(c.last) < holdover {
+		// PPS 已锁定时忽略未配对的语句 (同一秒内的其他时间语句)
+		return
+	}
+	if source != c.source {
+		c.offsets = c.offsets[:0]
+		c.source = source
+	}
+	c.offsets = append(c.offsets, gps.Sub(ref))
+	if len(c.offsets) > windowSize {
+		c.offsets = c.offsets[len(c.offsets)-windowSize:]
+	}
+	c.last = host
+
+	sorted := append([]time.Duration(nil), c.offsets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	c.offset = sorted[len(sorted)/2]
+	c.jitter = (sorted[len(sorted)*3/4] - sorted[len(sorted)/4]) / 2
+}
+
+// lockedLocked 是否已锁定
+func (c *Clock) lockedLocked(now time.Time) bool {
+	return len(c.offsets) >= minSamples && now.Sub(c.last) < holdover
+}
+
+// Apply 返回校正后的时间；未锁定时原样返回 t
+func (c *Clock) Apply(t time.Time) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.lockedLocked(time.Now()) {
+		return t
+	}
+	return t.Add(c.offset)
+}
+
+// Status 返回当前状态
+func (c *Clock) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := Status{Pulses: c.pulses, LastFix: c.lastFix, Samples: len(c.offsets), Source: c.source}
+	s.Locked = c.lockedLocked(time.Now())
+	if s.Samples > 0 {
+		s.OffsetNs = int64(c.offset)
+		s.JitterNs = int64(c.jitter)
+	}
+	return s
+}
+
+// Reset 清空状态 (更换参考源时)
+func (c *Clock) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pulse, c.pulses, c.offsets, c.source = time.Time{}, 0, nil, SourceNone
+	c.last, c.lastFix, c.offset, c.jitter = time.Time{}, time.Time{}, 0, 0
+}
+
+// ParseTime 从 RMC (状态为 A) 或 ZDA 语句中取出 UTC 时间，校验和错误或无效定位时返回 false
+func ParseTime(line string) (time.Time, bool) {
+	body, ok := checksum(strings.TrimSpace(line))
+	if !ok {
+		return time.Time{}, false
+	}
+	f := strings.Split(body, ",")
+	if len(f[0]) < 5 {
+		return time.Time{}, false
+	}
+	switch f[0][2:] {
+	case "RMC":
+		// $xxRMC,hhmmss.ss,A,lat,N,lon,E,spd,cog,ddmmyy,...
+		if len(f) < 10 || f[2] != "A" || len(f[9]) != 6 {
+			return time.Time{}, false
+		}
+		day, e1 := strconv.Atoi(f[9][0:2])
+		month, e2 := strconv.Atoi(f[9][2:4])
+		year, e3 := strconv.Atoi(f[9][4:6])
+		if e1 != nil || e2 != nil || e3 != nil {
+			return time.Time{}, false
+		}
+		return clock(f[1], 2000+year, month, day)
+	case "ZDA":
+		// $xxZDA,hhmmss.ss,dd,mm,yyyy,zh,zm
+		if len(f) < 5 {
+			return time.Time{}, false
+		}
+		day, e1 := strconv.Atoi(f[2])
+		month, e2 := strconv.Atoi(f[3])
+		year, e3 := strconv.Atoi(f[4])
+		if e1 != nil || e2 != nil || e3 != nil || year < 2000 {
+			return time.Time{}, false
+		}
+		return clock(f[1], year, month, day)
+	}
+	return time.Time{}, false
+}
+
+// clock 解析 hhmmss[.ss]
+func clock(hms string, year, month, day int) (time.Time, bool) {
+	if len(hms) < 6 {
+		return time.Time{}, false
+	}
+	h, e1 := strconv.Atoi(hms[0:2])
+	m, e2 := strconv.Atoi(hms[2:4])
+	s, e3 := strconv.ParseFloat(hms[4:], 64)
+	if e1 != nil || e2 != nil || e3 != nil || month < 1 || month > 12 || day < 1 || day > 31 {
+		return time.Time{}, false
+	}
+	whole := int(s)
+	ns := int((s - float64(whole)) * 1e9)
+	return time.Date(year, time.Month(month), day, h, m, whole, ns, time.UTC), true
+}
+
+// checksum 校验 $...*hh 语句，返回去掉起始符和校验和的主体
+func checksum(line string) (string, bool) {
+	if len(line) < 4 || line[0] != '$' {
+		return "", false
+	}
+	star := strings.LastIndexByte(line, '*')
+	if star < 0 || len(line) < star+3 {
+		return "", false
+	}
+	want, err := strconv.ParseUint(line[star+1:star+3], 16, 8)
+	if err != nil {
+		return "", false
+	}
+	var sum byte
+	for i := 1; i < star; i++ {
+		sum ^= line[i]
+	}
+	if sum != byte(want) {
+		return "", false
+	}
+	return line[1:star], true
+}
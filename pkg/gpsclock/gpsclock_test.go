@@ -0,0 +1,85 @@
+package gpsclock
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// sentence 生成带正确校验和的语句
+func sentence(body string) string {
+	var sum byte
+	for i := 0; i < len(body); i++ {
+		sum ^= body[i]
+	}
+	return fmt.Sprintf("$%s*%02X\r\n", body, sum)
+}
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2026, 10, 15, 8, 30, 12, 0, time.UTC)
+	for _, line := range []string{
+		sentence("GPRMC,083012.00,A,3114.1234,N,12128.5678,E,0.01,,151026,,,A"),
+		sentence("GNZDA,083012.00,15,10,2026,00,00"),
+	} {
+		got, ok := ParseTime(line)
+		if !ok || !got.Equal(want) {
+			t.Errorf("ParseTime(%q) = %v, %v", line, got, ok)
+		}
+	}
+	for _, line := range []string{
+		sentence("GPRMC,083012.00,V,,,,,,,151026,,,N"), // 无效定位
+		sentence("GPGGA,083012.00,3114.1234,N,12128.5678,E,1,08,0.9,10.0,M,,,,"),
+		"$GPZDA,083012.00,15,10,2026,00,00*00",
+	} {
+		if _, ok := ParseTime(line); ok {
+			t.Errorf("ParseTime(%q) should fail", line)
+		}
+	}
+}
+
+func TestPPSDiscipline(t *testing.T) {
+	c := New()
+	// 主机时钟比 GPS 慢 2.5 秒；语句在脉冲后 120ms 左右到达
+	host := time.Now()
+	gps := time.Date(2026, 10, 15, 8, 30, 0, 0, time.UTC)
+	skew := gps.Sub(host) // GPS - 主机 (脉冲时刻)
+	for i := 0; i < 5; i++ {
+		pulse := host.Add(time.Duration(i) * time.Second)
+		c.Pulse(pulse.Add(time.Duration(i%2) * 100 * time.Microsecond)) // DCD 采样抖动
+		sec := gps.Add(time.Duration(i) * time.Second)
+		c.Sentence(pulse.Add(120*time.Millisecond), sentence(fmt.Sprintf("GPZDA,%s,15,10,2026,00,00", sec.Format("150405.00"))))
+		// 同一秒内的 RMC 不应形成新样本
+		c.Sentence(pulse.Add(130*time.Millisecond), sentence(fmt.Sprintf("GPRMC,%s,A,3114.1234,N,12128.5678,E,0.01,,151026,,,A", sec.Format("150405.00"))))
+	}
+	s := c.Status()
+	if s.Source != SourcePPS || s.Samples != 5 || s.Pulses != 5 {
+		t.Fatalf("Unexpected status %+v", s)
+	}
+	if d := time.Duration(s.OffsetNs) - skew; d < -time.Millisecond || d > time.Millisecond {
+		t.Errorf("Offset %v differs from skew %v by %v", time.Duration(s.OffsetNs), skew, d)
+	}
+}
+
+func TestNMEAOnlyAndApply(t *testing.T) {
+	c := New()
+	now := time.Now()
+	if got := c.Apply(now); !got.Equal(now) {
+		t.Error("Unlocked clock should not change timestamps")
+	}
+	gps := now.UTC().Truncate(time.Second).Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		sec := gps.Add(time.Duration(i) * time.Second)
+		c.Sentence(time.Now(), sentence(fmt.Sprintf("GPZDA,%s,%s,%04d,00,00", sec.Format("150405.00"), sec.Format("02,01"), sec.Year())))
+	}
+	s := c.Status()
+	if !s.Locked || s.Source != SourceNMEA {
+		t.Fatalf("Expected NMEA lock, got %+v", s)
+	}
+	if d := c.Apply(now).Sub(now); d < 59*time.Minute || d > 61*time.Minute {
+		t.Errorf("Apply should add roughly one hour, got %v", d)
+	}
+	c.Reset()
+	if c.Status().Samples != 0 {
+		t.Error("Reset should clear samples")
+	}
+}
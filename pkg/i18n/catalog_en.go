@@ -0,0 +1,122 @@
+package i18n
+
+// catalogEN English messages
+var catalogEN = map[string]string{
+	// Structured errors (apperr)
+	"error.OK":                "Success",
+	"error.INTERNAL":          "Internal error",
+	"error.INVALID_ARGUMENT":  "Invalid argument",
+	"error.ALREADY_CONNECTED": "Already connected",
+	"error.NOT_CONNECTED":     "Not connected",
+	"error.PORT_NOT_FOUND":    "Port not found",
+	"error.PORT_BUSY":         "Port is busy",
+	"error.PERMISSION_DENIED": "Permission denied",
+	"error.OPEN_FAILED":       "Failed to open port",
+	"error.CONNECT_FAILED":    "Connect error",
+	"error.LISTEN_FAILED":     "Listen error",
+	"error.PROBE_FAILED":      "Debug probe error",
+	"error.NO_CLIENT":         "No client connected",
+	"error.NO_REMOTE":         "No remote address set",
+	"error.WRITE_FAILED":      "Send error",
+	"error.CLOSE_FAILED":      "Error closing",
+	"error.CANCELED":          "Operation canceled",
+	"error.TIMEOUT":           "Operation timed out",
+	"error.NOT_FOUND":         "Not found",
+	"error.UNSUPPORTED":       "Not supported on this platform",
+
+	// J-Link / RTT
+	"rtt.loading_library":       "[RTT] Loading library: %s",
+	"rtt.local_load_failed":     "[RTT] Local library failed to load, trying %s",
+	"rtt.missing_core_funcs":    "RTT library loaded but core functions are missing",
+	"rtt.api_not_initialized":   "RTT API not initialized",
+	"rtt.connect_failed":        "RTT connect failed (return code: %d)",
+	"rtt.connected_waiting":     "[RTT] Connected, waiting for the chip to settle...",
+	"rtt.starting_native":       "[RTT] Starting native RTT...",
+	"rtt.native_started":        "[RTT] Native RTT started",
+	"rtt.fallback_soft":         "[RTT] Native RTT unavailable, switching to software RTT",
+	"rtt.soft_init_failed":      "Software RTT initialization failed: %v",
+	"rtt.searching_cb":          "[RTT] Searching for the RTT control block...",
+	"rtt.found_cb":              "[RTT] Found RTT control block @ 0x%08X",
+	"rtt.read_desc_failed":      "Failed to read the RTT buffer descriptor",
+	"rtt.soft_init_ok":          "[RTT] Software RTT initialized",
+	"rtt.cb_not_found":          "SEGGER RTT control block not found",
+	"rtt.offset_out_of_range":   "[RTT] Error: offset out of range (wrOff=%d, rdOff=%d, bufSize=%d)",
+	"rtt.read_len_clamped":      "[RTT] Warning: read length too large (%d bytes), limited to %d bytes",
+	"rtt.total_len_clamped":     "[RTT] Warning: total read length too large (%d bytes), limited to %d bytes",
+	"rtt.update_rdoff_failed":   "[RTT] Warning: failed to update the read offset",
+	"rtt.reinit":                "[RTT] Offset anomaly detected, reinitializing RTT...",
+	"rtt.probe_not_found":       "[RTT] Probe with serial number %d not found",
+	"rtt.probe_serial_required": "[RTT] A probe serial number is required when using several probes",
+	"rtt.layout":                "[RTT] Control block has %d up / %d down buffers",
+	"rtt.layout_mismatch":       "[RTT] Warning: RTT configuration differs from the firmware ELF: %s",
+	"rtt.elf_cb_invalid":        "[RTT] No valid control block at the ELF address 0x%08X, searching RAM",
+	"rtt.background_access":     "[RTT] Reading target memory in the background while the CPU runs",
+	"rtt.open_failed":           "Failed to open J-Link: %s",
+	"rtt.tunnel_failed":         "[RTT] Could not reach probe %d through the remote server tunnel",
+	"rtt.ip_failed":             "[RTT] Could not reach the J-Link at %s:%d",
+	"rtt.swo_unsupported":       "[RTT] This J-Link driver does not support SWO",
+
+	// Connection status
+	"app.rtt_target_reset":       "[RTT] Target may have been reset, reconnecting...",
+	"app.rtt_reinit_ok":          "[RTT] RTT reinitialized",
+	"app.rtt_reinit_failed":      "[RTT] RTT reinitialization failed: %v",
+	"app.rtt_error":              "[RTT] Error (%d in a row): %v",
+	"app.rtt_read_warning":       "[RTT] Read warning: %v",
+	"app.jlink_in_use":           "%v. Another program (Ozone, GDB Server, J-Link Commander) holds the probe: close it, or run J-Link Remote Server there and connect in tunnel mode by serial number",
+	"app.jlink_not_installed":    "%v. Install the SEGGER J-Link Software Pack or place the J-Link library next to the application",
+	"app.tcp_client_connected":   "Client connected: %s",
+	"app.udp_remote_set":         "Remote set to: %s",
+	"app.permission_hint":        "No permission to access %s. Try:\n  %s",
+	"app.remote_sent":            "[Remote] %s sent %d bytes",
+	"app.auto_login_username":    "[Auto-login] Sent username %s",
+	"app.auto_login_password":    "[Auto-login] Sent password for %s",
+	"app.auto_login_suppressed":  "[Auto-login] Too many login prompts, not responding",
+	"app.auto_login_no_password": "[Auto-login] No password stored for %s",
+	"app.scpi_error":             "[SCPI] %v",
+	"app.deeplink_invalid":       "[Link] Unrecognized link: %v",
+	"app.timesync_failed":        "[Time sync] %v",
+	"app.reconnect_waiting":      "[Reconnect] %s was lost, waiting for it to reappear...",
+	"app.reconnected":            "[Reconnect] %s reopened",
+	"app.reconnect_failed":       "[Reconnect] %s did not reappear, giving up",
+	"app.backend_panic":          "[Diagnostics] Internal error in %s was recovered; a diagnostic dump was saved to %s",
+	"app.watch_sent":             "[Watch folder] Sent %s (%d bytes)",
+	"app.watch_failed":           "[Watch folder] %s not sent: %v",
+	"app.hook_flash":             "[Build hook] Flashing %s...",
+	"app.hook_done":              "[Build hook] %s %s done",
+	"app.hook_failed":            "[Build hook] %s %s failed: %s",
+	"app.serial_reconfigured":    "%s reconfigured to %d baud",
+	"app.automation_stopped":     "[%s] Stopped: %s",
+	"app.dry_run":                "[Dry run] Skipped %s %s %s",
+	"app.port_held_by":           "%s is in use by %s",
+	"app.writes_reverted":        "Reverted %d memory writes",
+	"app.profile_applied":        "Applied device profile %s",
+
+	// Driver health
+	"driver.no_driver":              "No driver is bound to this port",
+	"driver.ftdi_bricked":           "FTDI device reports PID 0000: it was likely bricked by FTDI driver 2.11.00. Restore the PID with FT_Prog or a Linux ftdi_eeprom tool",
+	"driver.ftdi_bricking_driver":   "FTDI driver %s rewrites the PID of non-genuine chips to 0000. Upgrade the driver immediately",
+	"driver.ftdi_nongenuine_driver": "FTDI driver %s injects \"NON GENUINE DEVICE FOUND!\" into the data stream of non-genuine chips",
+	"driver.ftdi_latency":           "Latency timer is %d ms; lower it to 1-2 ms for request/response protocols",
+	"driver.prolific_eol_driver":    "Prolific driver %s does not support legacy PL2303HXA/XA chips (Code 10). Use driver 3.3.2.102 for these adapters",
+
+	// Rules engine
+	"rules.suppressed": "(%d more matches during cooldown)",
+
+	// Boot loop detection
+	"bootloop.title": "Boot loop detected",
+	"bootloop.body":  "%d boots in %ds (every %.1fs on average)\n%s",
+
+	// Setup wizard
+	"setup.permission_ok":      "Current user can read and write %s",
+	"setup.permission_denied":  "No permission to access %s",
+	"setup.permission_relogin": "Already added to group %s; log out and back in for it to take effect",
+	"setup.port_missing":       "%s does not exist; check the cable and driver",
+	"setup.driver_ok":          "Driver: %s",
+	"setup.driver_unknown":     "Driver information is not available for this port",
+	"setup.open_ok":            "Opened %s at %d baud",
+	"setup.open_failed":        "Could not open %s: %v",
+	"setup.open_in_use":        "%s is already open in this application",
+	"setup.no_data":            "No data received within %.1fs; the device may be idle",
+	"setup.garbled":            "Received %d bytes that look garbled; try another baud rate",
+	"setup.data_ok":            "Received %d bytes of readable data",
+}
@@ -0,0 +1,122 @@
+package i18n
+
+// catalogZH 中文消息
+var catalogZH = map[string]string{
+	// 结构化错误 (apperr)
+	"error.OK":                "成功",
+	"error.INTERNAL":          "内部错误",
+	"error.INVALID_ARGUMENT":  "参数无效",
+	"error.ALREADY_CONNECTED": "已经处于连接状态",
+	"error.NOT_CONNECTED":     "未连接",
+	"error.PORT_NOT_FOUND":    "串口不存在",
+	"error.PORT_BUSY":         "串口被占用",
+	"error.PERMISSION_DENIED": "没有访问权限",
+	"error.OPEN_FAILED":       "打开串口失败",
+	"error.CONNECT_FAILED":    "连接失败",
+	"error.LISTEN_FAILED":     "监听失败",
+	"error.PROBE_FAILED":      "调试探针操作失败",
+	"error.NO_CLIENT":         "没有客户端连接",
+	"error.NO_REMOTE":         "未设置远程地址",
+	"error.WRITE_FAILED":      "发送失败",
+	"error.CLOSE_FAILED":      "关闭连接失败",
+	"error.CANCELED":          "操作已取消",
+	"error.TIMEOUT":           "操作超时",
+	"error.NOT_FOUND":         "对象不存在",
+	"error.UNSUPPORTED":       "当前平台不支持该操作",
+
+	// J-Link / RTT
+	"rtt.loading_library":       "[RTT] 正在加载库: %s",
+	"rtt.local_load_failed":     "[RTT] 本地加载失败，尝试 %s",
+	"rtt.missing_core_funcs":    "RTT 库已加载但缺少核心函数",
+	"rtt.api_not_initialized":   "RTT API 未初始化",
+	"rtt.connect_failed":        "RTT 连接失败 (返回值: %d)",
+	"rtt.connected_waiting":     "[RTT] 已连接，等待芯片稳定...",
+	"rtt.starting_native":       "[RTT] 尝试启动原生 RTT...",
+	"rtt.native_started":        "[RTT] 原生 RTT 已启动",
+	"rtt.fallback_soft":         "[RTT] 原生 RTT 不可用，切换到软件 RTT",
+	"rtt.soft_init_failed":      "软件 RTT 初始化失败: %v",
+	"rtt.searching_cb":          "[RTT] 搜索 RTT 控制块...",
+	"rtt.found_cb":              "[RTT] 找到 RTT 控制块 @ 0x%08X",
+	"rtt.read_desc_failed":      "读取 RTT 描述符失败",
+	"rtt.soft_init_ok":          "[RTT] 软件 RTT 初始化成功",
+	"rtt.cb_not_found":          "未找到 SEGGER RTT 控制块",
+	"rtt.offset_out_of_range":   "[RTT] 错误：偏移量超出范围 (wrOff=%d, rdOff=%d, bufSize=%d)",
+	"rtt.read_len_clamped":      "[RTT] 警告：读取长度过大 (%d bytes)，限制为 %d bytes",
+	"rtt.total_len_clamped":     "[RTT] 警告：总读取长度过大 (%d bytes)，限制为 %d bytes",
+	"rtt.update_rdoff_failed":   "[RTT] 警告：无法更新读偏移量",
+	"rtt.reinit":                "[RTT] 检测到偏移量异常，尝试重新初始化 RTT...",
+	"rtt.probe_not_found":       "[RTT] 未找到序列号为 %d 的探针",
+	"rtt.probe_serial_required": "[RTT] 同时使用多个探针时必须指定探针序列号",
+	"rtt.layout":                "[RTT] 控制块包含 %d 个上行 / %d 个下行缓冲区",
+	"rtt.layout_mismatch":       "[RTT] 警告：RTT 配置与固件 ELF 不一致：%s",
+	"rtt.elf_cb_invalid":        "[RTT] ELF 中的地址 0x%08X 处没有有效的控制块，改为搜索内存",
+	"rtt.background_access":     "[RTT] 以后台方式读取目标内存，CPU 运行时无需暂停",
+	"rtt.open_failed":           "打开 J-Link 失败：%s",
+	"rtt.tunnel_failed":         "[RTT] 无法通过远程服务器隧道连接探针 %d",
+	"rtt.ip_failed":             "[RTT] 无法连接位于 %s:%d 的 J-Link",
+	"rtt.swo_unsupported":       "[RTT] 当前 J-Link 驱动不支持 SWO",
+
+	// 连接状态
+	"app.rtt_target_reset":       "[RTT] 检测到目标设备可能已复位，尝试重新连接...",
+	"app.rtt_reinit_ok":          "[RTT] RTT 重新初始化成功",
+	"app.rtt_reinit_failed":      "[RTT] RTT 重新初始化失败: %v",
+	"app.rtt_error":              "[RTT] 错误 (连续 %d 次): %v",
+	"app.rtt_read_warning":       "[RTT] 读取警告: %v",
+	"app.jlink_in_use":           "%v。探针正被其他程序 (Ozone、GDB Server、J-Link Commander) 占用：请关闭该程序，或在其所在机器运行 J-Link Remote Server 后按序列号以隧道方式连接",
+	"app.jlink_not_installed":    "%v。请安装 SEGGER J-Link Software Pack，或将 J-Link 驱动库放在程序所在目录",
+	"app.tcp_client_connected":   "客户端已连接: %s",
+	"app.udp_remote_set":         "远程地址已设置为: %s",
+	"app.permission_hint":        "没有 %s 的访问权限，可尝试执行:\n  %s",
+	"app.remote_sent":            "[远程] %s 发送了 %d 字节",
+	"app.auto_login_username":    "[自动登录] 已发送用户名 %s",
+	"app.auto_login_password":    "[自动登录] 已发送 %s 的密码",
+	"app.auto_login_suppressed":  "[自动登录] 登录提示过于频繁，不再回应",
+	"app.auto_login_no_password": "[自动登录] 钥匙串中没有 %s 的密码",
+	"app.scpi_error":             "[SCPI] %v",
+	"app.deeplink_invalid":       "[链接] 无法识别的链接：%v",
+	"app.timesync_failed":        "[时间同步] %v",
+	"app.reconnect_waiting":      "[重连] %s 已断开，等待设备重新出现...",
+	"app.reconnected":            "[重连] 已重新打开 %s",
+	"app.reconnect_failed":       "[重连] %s 未重新出现，已放弃",
+	"app.backend_panic":          "[诊断] %s 发生内部错误并已恢复，诊断转储已保存到 %s",
+	"app.watch_sent":             "[监视目录] 已发送 %s (%d 字节)",
+	"app.watch_failed":           "[监视目录] %s 发送失败: %v",
+	"app.hook_flash":             "[构建钩子] 正在刷写 %s...",
+	"app.hook_done":              "[构建钩子] %s %s 完成",
+	"app.hook_failed":            "[构建钩子] %s %s 失败: %s",
+	"app.serial_reconfigured":    "%s 已切换为 %d 波特率",
+	"app.automation_stopped":     "[%s] 已停止: %s",
+	"app.dry_run":                "[试运行] 已跳过 %s %s %s",
+	"app.port_held_by":           "%s 正被 %s 占用",
+	"app.writes_reverted":        "已恢复 %d 次内存写入",
+	"app.profile_applied":        "已应用设备配置 %s",
+
+	// 驱动诊断
+	"driver.no_driver":              "该串口没有绑定驱动",
+	"driver.ftdi_bricked":           "FTDI 设备的 PID 为 0000，可能已被 FTDI 2.11.00 驱动改写，可使用 FT_Prog 或 Linux 下的 ftdi_eeprom 工具恢复",
+	"driver.ftdi_bricking_driver":   "FTDI 驱动 %s 会将仿冒芯片的 PID 改写为 0000，请立即升级驱动",
+	"driver.ftdi_nongenuine_driver": "FTDI 驱动 %s 会向仿冒芯片的数据流中插入 \"NON GENUINE DEVICE FOUND!\"",
+	"driver.ftdi_latency":           "延迟定时器为 %d ms，应答式协议建议调低到 1-2 ms",
+	"driver.prolific_eol_driver":    "Prolific 驱动 %s 不支持旧版 PL2303HXA/XA 芯片 (代码 10)，此类转换器请使用 3.3.2.102 版驱动",
+
+	// 规则引擎
+	"rules.suppressed": "(冷却期间另有 %d 次匹配)",
+
+	// 启动循环检测
+	"bootloop.title": "检测到反复重启",
+	"bootloop.body":  "%[2]d 秒内启动 %[1]d 次 (平均每 %[3].1f 秒一次)\n%[4]s",
+
+	// 首次运行向导
+	"setup.permission_ok":      "当前用户可以读写 %s",
+	"setup.permission_denied":  "没有 %s 的访问权限",
+	"setup.permission_relogin": "已加入 %s 组，需注销并重新登录后生效",
+	"setup.port_missing":       "%s 不存在，请检查连线和驱动",
+	"setup.driver_ok":          "驱动: %s",
+	"setup.driver_unknown":     "无法获取该串口的驱动信息",
+	"setup.open_ok":            "已以 %[2]d 波特率打开 %[1]s",
+	"setup.open_failed":        "无法打开 %s: %v",
+	"setup.open_in_use":        "%s 已在本程序中打开",
+	"setup.no_data":            "%.1f 秒内未收到数据，设备可能处于空闲状态",
+	"setup.garbled":            "收到 %d 字节疑似乱码的数据，请尝试其他波特率",
+	"setup.data_ok":            "收到 %d 字节可读数据",
+}
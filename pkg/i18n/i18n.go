@@ -0,0 +1,91 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Lang 语言代码
+type Lang string
+
+const (
+	ZH Lang = "zh"
+	EN Lang = "en"
+)
+
+// fallback 当前语言缺少翻译时回退的语言
+const fallback = EN
+
+var (
+	mu       sync.RWMutex
+	current  = ZH
+	catalogs = map[Lang]map[string]string{
+		ZH: catalogZH,
+		EN: catalogEN,
+	}
+)
+
+// Parse 将 "zh-CN"、"en_US.UTF-8" 等形式的语言标识规范化为支持的语言
+func Parse(tag string) (Lang, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_."); i >= 0 {
+		tag = tag[:i]
+	}
+	lang := Lang(tag)
+	mu.RLock()
+	_, ok := catalogs[lang]
+	mu.RUnlock()
+	return lang, ok
+}
+
+// SetLanguage 设置后端消息使用的语言
+func SetLanguage(tag string) error {
+	lang, ok := Parse(tag)
+	if !ok {
+		return fmt.Errorf("unsupported language: %s", tag)
+	}
+	mu.Lock()
+	current = lang
+	mu.Unlock()
+	return nil
+}
+
+// Language 返回当前语言
+func Language() Lang {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Register 为指定语言追加或覆盖消息
+func Register(lang Lang, messages map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	catalog, ok := catalogs[lang]
+	if !ok {
+		catalog = make(map[string]string, len(messages))
+		catalogs[lang] = catalog
+	}
+	for k, v := range messages {
+		catalog[k] = v
+	}
+}
+
+// T 按当前语言翻译消息，args 按 fmt.Sprintf 格式化
+// 找不到翻译时依次回退到英文和 key 本身
+func T(key string, args ...interface{}) string {
+	mu.RLock()
+	msg, ok := catalogs[current][key]
+	if !ok {
+		msg, ok = catalogs[fallback][key]
+	}
+	mu.RUnlock()
+	if !ok {
+		msg = key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
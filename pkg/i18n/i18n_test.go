@@ -0,0 +1,75 @@
+package i18n
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		tag  string
+		lang Lang
+		ok   bool
+	}{
+		{"zh", ZH, true},
+		{"zh-CN", ZH, true},
+		{"en_US.UTF-8", EN, true},
+		{" EN ", EN, true},
+		{"fr-FR", "fr", false},
+	}
+	for _, tt := range tests {
+		lang, ok := Parse(tt.tag)
+		if lang != tt.lang || ok != tt.ok {
+			t.Errorf("Parse(%q) = %q, %v; expected %q, %v", tt.tag, lang, ok, tt.lang, tt.ok)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	defer SetLanguage(string(Language()))
+
+	if err := SetLanguage("en-US"); err != nil {
+		t.Fatal(err)
+	}
+	if got := T("app.udp_remote_set", "1.2.3.4:5"); got != "Remote set to: 1.2.3.4:5" {
+		t.Errorf("Unexpected English message: %q", got)
+	}
+
+	if err := SetLanguage("zh-CN"); err != nil {
+		t.Fatal(err)
+	}
+	if got := T("error.PORT_BUSY"); got != "串口被占用" {
+		t.Errorf("Unexpected Chinese message: %q", got)
+	}
+
+	if err := SetLanguage("xx"); err == nil {
+		t.Error("Expected error for unsupported language")
+	}
+	if Language() != ZH {
+		t.Errorf("Unsupported language should not change current language, got %q", Language())
+	}
+}
+
+func TestFallback(t *testing.T) {
+	defer SetLanguage(string(Language()))
+	SetLanguage("zh")
+
+	Register(EN, map[string]string{"test.only_en": "only english"})
+	defer delete(catalogEN, "test.only_en")
+	if got := T("test.only_en"); got != "only english" {
+		t.Errorf("Expected English fallback, got %q", got)
+	}
+	if got := T("test.missing"); got != "test.missing" {
+		t.Errorf("Expected key fallback, got %q", got)
+	}
+}
+
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	for key := range catalogZH {
+		if _, ok := catalogEN[key]; !ok {
+			t.Errorf("English catalog is missing %q", key)
+		}
+	}
+	for key := range catalogEN {
+		if _, ok := catalogZH[key]; !ok {
+			t.Errorf("Chinese catalog is missing %q", key)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package identify
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Evidence 识别设备时收集到的各类线索，缺失的字段保持零值即可
+type Evidence struct {
+	// USB 描述符 (来自串口枚举)
+	VID          string `json:"vid"`
+	PID          string `json:"pid"`
+	SerialNumber string `json:"serialNumber"`
+	Product      string `json:"product"`
+
+	// 设备上电/连接后输出的启动信息
+	Banner string `json:"banner"`
+
+	// AT 指令 (AT / ATI) 的应答
+	ATResponse string `json:"atResponse"`
+
+	// 通过调试探针读取的芯片 ID (如 STM32 DBGMCU_IDCODE)
+	ChipID uint32 `json:"chipId"`
+}
+
+// Fingerprint 设备指纹识别结果
+type Fingerprint struct {
+	Vendor     string   `json:"vendor"`
+	Model      string   `json:"model"`
+	Profile    string   `json:"profile"`    // 建议自动选用的配置名，未识别时为空
+	Confidence float64  `json:"confidence"` // 0 ~ 1
+	Sources    []string `json:"sources"`    // 命中的线索来源: usb / banner / at / chipid
+}
+
+// 线索来源
+const (
+	SourceUSB    = "usb"
+	SourceBanner = "banner"
+	SourceAT     = "at"
+	SourceChipID = "chipid"
+)
+
+// signature 单条识别规则
+type signature struct {
+	source  string
+	vendor  string
+	model   string
+	profile string
+	weight  float64
+	match   func(ev *Evidence) bool
+}
+
+func usbID(vid, pid string) func(ev *Evidence) bool {
+	return func(ev *Evidence) bool {
+		if !strings.EqualFold(ev.VID, vid) {
+			return false
+		}
+		return pid == "" || strings.EqualFold(ev.PID, pid)
+	}
+}
+
+func bannerRe(expr string) func(ev *Evidence) bool {
+	re := regexp.MustCompile(expr)
+	return func(ev *Evidence) bool {
+		return ev.Banner != "" && re.MatchString(ev.Banner)
+	}
+}
+
+func atRe(expr string) func(ev *Evidence) bool {
+	re := regexp.MustCompile(expr)
+	return func(ev *Evidence) bool {
+		return ev.ATResponse != "" && re.MatchString(ev.ATResponse)
+	}
+}
+
+// stm32DevID 匹配 STM32 DBGMCU_IDCODE 的 DEV_ID 字段 (低 12 位)
+func stm32DevID(ids ...uint32) func(ev *Evidence) bool {
+	return func(ev *Evidence) bool {
+		if ev.ChipID == 0 {
+			return false
+		}
+		dev := ev.ChipID & 0xFFF
+		for _, id := range ids {
+			if dev == id {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// signatures 内置识别规则表
+// USB 转串口芯片本身只能说明适配器类型，权重较低；
+// 启动信息、AT 应答、芯片 ID 更能说明目标设备，权重较高
+var signatures = []signature{
+	// USB 描述符
+	{SourceUSB, "Silicon Labs", "CP210x", "", 0.2, usbID("10C4", "EA60")},
+	{SourceUSB, "WCH", "CH340", "", 0.2, usbID("1A86", "7523")},
+	{SourceUSB, "WCH", "CH9102", "", 0.2, usbID("1A86", "55D4")},
+	{SourceUSB, "FTDI", "FT232R", "", 0.2, usbID("0403", "6001")},
+	{SourceUSB, "FTDI", "FT2232/FT4232", "", 0.2, usbID("0403", "6010")},
+	{SourceUSB, "Prolific", "PL2303", "", 0.2, usbID("067B", "2303")},
+	{SourceUSB, "Espressif", "USB-Serial/JTAG", "esp32", 0.6, usbID("303A", "1001")},
+	{SourceUSB, "STMicroelectronics", "STM32 Virtual COM", "stm32", 0.5, usbID("0483", "5740")},
+	{SourceUSB, "STMicroelectronics", "ST-LINK VCP", "stm32", 0.4, usbID("0483", "374B")},
+	{SourceUSB, "SEGGER", "J-Link VCOM", "jlink-vcom", 0.4, usbID("1366", "")},
+	{SourceUSB, "Raspberry Pi", "RP2040 MicroPython", "micropython", 0.6, usbID("2E8A", "0005")},
+	{SourceUSB, "Raspberry Pi", "RP2040", "rp2040", 0.4, usbID("2E8A", "")},
+	{SourceUSB, "Arduino", "Arduino", "arduino", 0.5, usbID("2341", "")},
+
+	// 启动信息
+	{SourceBanner, "Espressif", "ESP32", "esp32", 0.7, bannerRe(`ESP-ROM:|rst:0x[0-9a-f]+ \(|ets [A-Z][a-z]{2} +\d+ \d{4}`)},
+	{SourceBanner, "", "U-Boot", "uboot", 0.7, bannerRe(`U-Boot \d{4}\.\d{2}`)},
+	{SourceBanner, "", "Linux", "linux-console", 0.6, bannerRe(`Linux version \d+\.\d+|login:\s*$`)},
+	{SourceBanner, "Zephyr Project", "Zephyr", "zephyr", 0.7, bannerRe(`\*\*\* Booting Zephyr OS`)},
+	{SourceBanner, "", "MicroPython", "micropython", 0.7, bannerRe(`MicroPython v\d+`)},
+	{SourceBanner, "RT-Thread", "RT-Thread", "rt-thread", 0.7, bannerRe(`- RT -\s+Thread Operating System`)},
+	{SourceBanner, "Apache", "NuttX", "nuttx", 0.7, bannerRe(`NuttShell \(NSH\)`)},
+
+	// AT 指令应答
+	{SourceAT, "", "AT Modem", "at-modem", 0.4, atRe(`(?m)^\s*OK\s*$`)},
+	{SourceAT, "Espressif", "ESP-AT", "esp-at", 0.8, atRe(`AT version:`)},
+	{SourceAT, "Quectel", "Quectel Module", "quectel", 0.8, atRe(`(?i)quectel`)},
+	{SourceAT, "SIMCom", "SIMCom Module", "simcom", 0.8, atRe(`(?i)simcom`)},
+	{SourceAT, "u-blox", "u-blox Module", "ublox", 0.8, atRe(`(?i)u-blox`)},
+
+	// 调试探针读取的芯片 ID
+	{SourceChipID, "STMicroelectronics", "STM32F1", "stm32", 0.9, stm32DevID(0x410, 0x412, 0x414, 0x418, 0x420, 0x428, 0x430)},
+	{SourceChipID, "STMicroelectronics", "STM32F0", "stm32", 0.9, stm32DevID(0x440, 0x442, 0x444, 0x445, 0x448)},
+	{SourceChipID, "STMicroelectronics", "STM32F2", "stm32", 0.9, stm32DevID(0x411)},
+	{SourceChipID, "STMicroelectronics", "STM32F4", "stm32", 0.9, stm32DevID(0x413, 0x419, 0x421, 0x423, 0x431, 0x433, 0x434, 0x441, 0x458, 0x463)},
+	{SourceChipID, "STMicroelectronics", "STM32F7", "stm32", 0.9, stm32DevID(0x449, 0x451, 0x452)},
+	{SourceChipID, "STMicroelectronics", "STM32G0", "stm32", 0.9, stm32DevID(0x456, 0x460, 0x466, 0x467)},
+	{SourceChipID, "STMicroelectronics", "STM32G4", "stm32", 0.9, stm32DevID(0x468, 0x469, 0x479)},
+	{SourceChipID, "STMicroelectronics", "STM32H7", "stm32", 0.9, stm32DevID(0x450, 0x480, 0x483)},
+	{SourceChipID, "STMicroelectronics", "STM32L4", "stm32", 0.9, stm32DevID(0x415, 0x435, 0x461, 0x462, 0x464, 0x470, 0x471)},
+}
+
+// Preset 选中配置后应用到当前会话的显示与解码设置
+type Preset struct {
+	LineMode      bool     `json:"lineMode"`                // 按行组装接收数据
+	LineTimeoutMs int      `json:"lineTimeoutMs,omitempty"` // 没有换行的提示符 (如 "=> "、">>> ") 的输出超时
+	Decoders      []string `json:"decoders,omitempty"`      // 启用的解码器
+}
+
+// consolePreset 命令行与 AT 指令类设备：按行显示，提示符在空闲后输出
+var consolePreset = Preset{LineMode: true, LineTimeoutMs: 200}
+
+// presets 各配置的会话设置，未列出的配置 (如 stm32、arduino) 不改变设置
+var presets = map[string]Preset{
+	"esp32":         consolePreset,
+	"uboot":         consolePreset,
+	"linux-console": consolePreset,
+	"zephyr":        consolePreset,
+	"micropython":   consolePreset,
+	"rt-thread":     consolePreset,
+	"nuttx":         consolePreset,
+	"at-modem":      consolePreset,
+	"esp-at":        consolePreset,
+	"quectel":       consolePreset,
+	"simcom":        consolePreset,
+	"ublox":         {LineMode: true, Decoders: []string{"nmea"}},
+}
+
+// PresetFor 返回配置对应的会话设置，ok 为 false 表示该配置没有需要应用的设置
+func PresetFor(profile string) (p Preset, ok bool) {
+	p, ok = presets[profile]
+	return p, ok
+}
+
+// Identify 根据收集到的线索给出最可能的设备指纹
+// 同一 Profile 的多条命中规则权重累加，取总分最高者；
+// Vendor/Model 取该 Profile 下权重最高的规则
+func Identify(ev Evidence) Fingerprint {
+	type candidate struct {
+		score   float64
+		best    *signature
+		sources map[string]bool
+	}
+	candidates := make(map[string]*candidate)
+	var adapter *signature // 仅识别出 USB 转串口芯片时的兜底结果
+
+	for i := range signatures {
+		sig := &signatures[i]
+		if !sig.match(&ev) {
+			continue
+		}
+		if sig.profile == "" {
+			if adapter == nil || sig.weight > adapter.weight {
+				adapter = sig
+			}
+			continue
+		}
+		c := candidates[sig.profile]
+		if c == nil {
+			c = &candidate{sources: make(map[string]bool)}
+			candidates[sig.profile] = c
+		}
+		c.score += sig.weight
+		c.sources[sig.source] = true
+		if c.best == nil || sig.weight > c.best.weight {
+			c.best = sig
+		}
+	}
+
+	var bestProfile string
+	var best *candidate
+	for profile, c := range candidates {
+		// 分数相同时按名称排序，保证结果稳定
+		if best == nil || c.score > best.score || (c.score == best.score && profile < bestProfile) {
+			bestProfile, best = profile, c
+		}
+	}
+
+	if best == nil {
+		if adapter == nil {
+			return Fingerprint{Sources: []string{}}
+		}
+		return Fingerprint{
+			Vendor:     adapter.vendor,
+			Model:      adapter.model,
+			Confidence: adapter.weight,
+			Sources:    []string{adapter.source},
+		}
+	}
+
+	fp := Fingerprint{
+		Vendor:     best.best.vendor,
+		Model:      best.best.model,
+		Profile:    bestProfile,
+		Confidence: best.score,
+	}
+	if fp.Confidence > 1 {
+		fp.Confidence = 1
+	}
+	for source := range best.sources {
+		fp.Sources = append(fp.Sources, source)
+	}
+	sort.Strings(fp.Sources)
+	return fp
+}
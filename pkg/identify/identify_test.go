@@ -0,0 +1,89 @@
+package identify
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIdentifyEmptyEvidence(t *testing.T) {
+	fp := Identify(Evidence{})
+	if fp.Profile != "" || fp.Confidence != 0 {
+		t.Errorf("Expected empty fingerprint, got %+v", fp)
+	}
+}
+
+func TestIdentifyAdapterOnly(t *testing.T) {
+	fp := Identify(Evidence{VID: "1a86", PID: "7523"})
+	if fp.Model != "CH340" {
+		t.Errorf("Expected CH340 adapter, got %+v", fp)
+	}
+	if fp.Profile != "" {
+		t.Errorf("Adapter alone should not select a profile, got %q", fp.Profile)
+	}
+}
+
+func TestIdentifyCombinesSources(t *testing.T) {
+	ev := Evidence{
+		VID:    "303A",
+		PID:    "1001",
+		Banner: "ESP-ROM:esp32s3-20210327\r\nBuild:Mar 27 2021\r\n",
+	}
+	fp := Identify(ev)
+	if fp.Profile != "esp32" {
+		t.Fatalf("Expected esp32 profile, got %+v", fp)
+	}
+	if fp.Confidence != 1 {
+		t.Errorf("Expected confidence capped at 1, got %f", fp.Confidence)
+	}
+	if !reflect.DeepEqual(fp.Sources, []string{SourceBanner, SourceUSB}) {
+		t.Errorf("Unexpected sources: %v", fp.Sources)
+	}
+}
+
+func TestIdentifyBannerBeatsAdapter(t *testing.T) {
+	fp := Identify(Evidence{
+		VID:    "10C4",
+		PID:    "EA60",
+		Banner: "U-Boot 2023.04 (Apr 01 2023 - 10:00:00 +0000)",
+	})
+	if fp.Profile != "uboot" || fp.Model != "U-Boot" {
+		t.Errorf("Expected U-Boot profile, got %+v", fp)
+	}
+}
+
+func TestIdentifyATResponse(t *testing.T) {
+	fp := Identify(Evidence{ATResponse: "ATI\r\nQuectel\r\nEC200U\r\nRevision: EC200UCNAAR02A01M08\r\n\r\nOK\r\n"})
+	if fp.Profile != "quectel" {
+		t.Errorf("Expected quectel profile, got %+v", fp)
+	}
+}
+
+func TestIdentifyChipID(t *testing.T) {
+	// STM32F407: REV_ID=0x1007, DEV_ID=0x413
+	fp := Identify(Evidence{ChipID: 0x10076413})
+	if fp.Profile != "stm32" || fp.Model != "STM32F4" {
+		t.Errorf("Expected STM32F4, got %+v", fp)
+	}
+}
+
+func TestPresetFor(t *testing.T) {
+	if p, ok := PresetFor("uboot"); !ok || !p.LineMode || p.LineTimeoutMs == 0 {
+		t.Errorf("Expected console preset for uboot, got %+v %v", p, ok)
+	}
+	if p, ok := PresetFor("ublox"); !ok || len(p.Decoders) != 1 || p.Decoders[0] != "nmea" {
+		t.Errorf("Expected nmea decoder for ublox, got %+v", p)
+	}
+	if _, ok := PresetFor("stm32"); ok {
+		t.Error("stm32 should not have a preset")
+	}
+	// 识别规则中的配置都应有名称，有预设的配置都应能被识别出
+	profiles := map[string]bool{}
+	for _, sig := range signatures {
+		profiles[sig.profile] = true
+	}
+	for name := range presets {
+		if !profiles[name] {
+			t.Errorf("Preset %q has no signature", name)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package instrument
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"serial-assistant/pkg/scpi"
+)
+
+// 仪器类型
+const (
+	KindPSU = "psu"
+	KindDMM = "dmm"
+)
+
+// Profile 一类仪器的 SCPI 命令模板
+// 模板中的 {ch} 替换为通道号，{value} 替换为设定值，{state} 替换为 ON/OFF
+type Profile struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Match    string `json:"match"` // 与 *IDN? 应答匹配的正则表达式
+	Channels int    `json:"channels,omitempty"`
+
+	// 电源
+	SetVoltage  string `json:"setVoltage,omitempty"`
+	SetCurrent  string `json:"setCurrent,omitempty"`
+	Output      string `json:"output,omitempty"`
+	MeasVoltage string `json:"measVoltage,omitempty"`
+	MeasCurrent string `json:"measCurrent,omitempty"`
+
+	// 万用表：测量功能 (dcv、acv、dci、aci、res、freq 等) 到配置命令的映射
+	Functions map[string]string `json:"functions,omitempty"`
+	Read      string            `json:"read,omitempty"`
+}
+
+// dmmFunctions SCPI-99 标准的万用表配置命令
+var dmmFunctions = map[string]string{
+	"dcv":   "CONF:VOLT:DC",
+	"acv":   "CONF:VOLT:AC",
+	"dci":   "CONF:CURR:DC",
+	"aci":   "CONF:CURR:AC",
+	"res":   "CONF:RES",
+	"fres":  "CONF:FRES",
+	"freq":  "CONF:FREQ",
+	"cont":  "CONF:CONT",
+	"diode": "CONF:DIOD",
+}
+
+// Builtins 内置的常见台式电源与万用表
+var Builtins = []Profile{
+	{
+		Name: "rigol-dp800", Kind: KindPSU, Match: `(?i)^RIGOL.*,DP8\d\d`, Channels: 3,
+		SetVoltage: ":SOUR{ch}:VOLT {value}", SetCurrent: ":SOUR{ch}:CURR {value}",
+		Output: ":OUTP CH{ch},{state}", MeasVoltage: ":MEAS:VOLT? CH{ch}", MeasCurrent: ":MEAS:CURR? CH{ch}",
+	},
+	{
+		Name: "siglent-spd3303", Kind: KindPSU, Match: `(?i)^Siglent.*,SPD3303`, Channels: 2,
+		SetVoltage: "CH{ch}:VOLT {value}", SetCurrent: "CH{ch}:CURR {value}",
+		Output: "OUTP CH{ch},{state}", MeasVoltage: "MEAS:VOLT? CH{ch}", MeasCurrent: "MEAS:CURR? CH{ch}",
+	},
+	{
+		Name: "keysight-e36300", Kind: KindPSU, Match: `(?i)^(Keysight|Agilent).*,E363\d\d`, Channels: 3,
+		SetVoltage: "VOLT {value},(@{ch})", SetCurrent: "CURR {value},(@{ch})",
+		Output: "OUTP {state},(@{ch})", MeasVoltage: "MEAS:VOLT? (@{ch})", MeasCurrent: "MEAS:CURR? (@{ch})",
+	},
+	{
+		Name: "generic-psu", Kind: KindPSU, Channels: 1,
+		SetVoltage: "VOLT {value}", SetCurrent: "CURR {value}",
+		Output: "OUTP {state}", MeasVoltage: "MEAS:VOLT?", MeasCurrent: "MEAS:CURR?",
+	},
+	{Name: "keysight-3446x", Kind: KindDMM, Match: `(?i)^(Keysight|Agilent).*,344\d\d`, Functions: dmmFunctions, Read: "READ?"},
+	{Name: "siglent-sdm", Kind: KindDMM, Match: `(?i)^Siglent.*,SDM\d`, Functions: dmmFunctions, Read: "READ?"},
+	{
+		Name: "rigol-dm3000", Kind: KindDMM, Match: `(?i)^RIGOL.*,DM30`, Read: ":MEAS?",
+		Functions: map[string]string{
+			"dcv": ":FUNC:VOLT:DC", "acv": ":FUNC:VOLT:AC", "dci": ":FUNC:CURR:DC", "aci": ":FUNC:CURR:AC",
+			"res": ":FUNC:RES", "fres": ":FUNC:FRES", "freq": ":FUNC:FREQ", "cont": ":FUNC:CONT", "diode": ":FUNC:DIOD",
+		},
+	},
+	{Name: "generic-dmm", Kind: KindDMM, Functions: dmmFunctions, Read: "READ?"},
+}
+
+// 错误
+var (
+	ErrUnknownProfile = errors.New("instrument: unknown profile")
+	ErrNoMatch        = errors.New("instrument: no profile matches the instrument")
+	ErrWrongKind      = errors.New("instrument: operation not supported by this instrument")
+	ErrChannel        = errors.New("instrument: invalid channel")
+	ErrFunction       = errors.New("instrument: unknown measurement function")
+)
+
+// Find 按名称查找内置配置
+func Find(name string) (Profile, error) {
+	for _, p := range Builtins {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return Profile{}, fmt.Errorf("%w: %s", ErrUnknownProfile, name)
+}
+
+// Match 返回与 *IDN? 应答匹配的内置配置
+func Match(id scpi.Identity) (Profile, error) {
+	for _, p := range Builtins {
+		if p.Match != "" && regexp.MustCompile(p.Match).MatchString(id.Raw) {
+			return p, nil
+		}
+	}
+	return Profile{}, fmt.Errorf("%w: %s", ErrNoMatch, id.Raw)
+}
+
+// Driver 按配置驱动一台仪器
+type Driver struct {
+	Profile Profile
+	s       *scpi.Session
+}
+
+// NewDriver 创建驱动
+func NewDriver(p Profile, s *scpi.Session) *Driver {
+	return &Driver{Profile: p, s: s}
+}
+
+// render 替换命令模板中的占位符
+func render(tmpl string, ch int, value string) string {
+	return strings.NewReplacer("{ch}", strconv.Itoa(ch), "{value}", value, "{state}", value).Replace(tmpl)
+}
+
+func (d *Driver) psuCommand(tmpl string, ch int) error {
+	if d.Profile.Kind != KindPSU || tmpl == "" {
+		return ErrWrongKind
+	}
+	if ch < 1 || ch > d.Profile.Channels {
+		return fmt.Errorf("%w: %d (1-%d)", ErrChannel, ch, d.Profile.Channels)
+	}
+	return nil
+}
+
+// SetVoltage 设置电源通道的输出电压 (V)
+func (d *Driver) SetVoltage(ctx context.Context, ch int, volts float64) error {
+	if err := d.psuCommand(d.Profile.SetVoltage, ch); err != nil {
+		return err
+	}
+	return d.s.Command(ctx, render(d.Profile.SetVoltage, ch, strconv.FormatFloat(volts, 'f', -1, 64)))
+}
+
+// SetCurrent 设置电源通道的限流 (A)
+func (d *Driver) SetCurrent(ctx context.Context, ch int, amps float64) error {
+	if err := d.psuCommand(d.Profile.SetCurrent, ch); err != nil {
+		return err
+	}
+	return d.s.Command(ctx, render(d.Profile.SetCurrent, ch, strconv.FormatFloat(amps, 'f', -1, 64)))
+}
+
+// SetOutput 打开或关闭电源通道的输出
+func (d *Driver) SetOutput(ctx context.Context, ch int, on bool) error {
+	if err := d.psuCommand(d.Profile.Output, ch); err != nil {
+		return err
+	}
+	state := "OFF"
+	if on {
+		state = "ON"
+	}
+	return d.s.Command(ctx, render(d.Profile.Output, ch, state))
+}
+
+// Reading 电源通道的测量值
+type Reading struct {
+	Channel int     `json:"channel"`
+	Voltage float64 `json:"voltage"`
+	Current float64 `json:"current"`
+	Power   float64 `json:"power"`
+}
+
+// Measure 读取电源通道的输出电压与电流
+func (d *Driver) Measure(ctx context.Context, ch int) (Reading, error) {
+	if err := d.psuCommand(d.Profile.MeasVoltage, ch); err != nil {
+		return Reading{}, err
+	}
+	v, err := d.s.QueryFloat(ctx, render(d.Profile.MeasVoltage, ch, ""))
+	if err != nil {
+		return Reading{}, err
+	}
+	i, err := d.s.QueryFloat(ctx, render(d.Profile.MeasCurrent, ch, ""))
+	if err != nil {
+		return Reading{}, err
+	}
+	return Reading{Channel: ch, Voltage: v, Current: i, Power: v * i}, nil
+}
+
+// Configure 设置万用表的测量功能
+func (d *Driver) Configure(ctx context.Context, function string) error {
+	if d.Profile.Kind != KindDMM {
+		return ErrWrongKind
+	}
+	cmd, ok := d.Profile.Functions[function]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrFunction, function)
+	}
+	return d.s.Command(ctx, cmd)
+}
+
+// Read 触发一次万用表测量并返回读数
+func (d *Driver) Read(ctx context.Context) (float64, error) {
+	if d.Profile.Kind != KindDMM {
+		return 0, ErrWrongKind
+	}
+	return d.s.QueryFloat(ctx, d.Profile.Read)
+}
+
+// Measurements 返回测量记录器使用的查询：电源为各通道的电压与电流，万用表为读数
+// 序列名以 prefix 开头，如 "psu.ch1.voltage"
+func (d *Driver) Measurements(prefix string) []scpi.Measurement {
+	if d.Profile.Kind == KindDMM {
+		return []scpi.Measurement{{Name: prefix + ".reading", Query: d.Profile.Read}}
+	}
+	var list []scpi.Measurement
+	for ch := 1; ch <= d.Profile.Channels; ch++ {
+		name := fmt.Sprintf("%s.ch%d", prefix, ch)
+		list = append(list,
+			scpi.Measurement{Name: name + ".voltage", Query: render(d.Profile.MeasVoltage, ch, ""), Unit: "V"},
+			scpi.Measurement{Name: name + ".current", Query: render(d.Profile.MeasCurrent, ch, ""), Unit: "A"},
+		)
+	}
+	return list
+}
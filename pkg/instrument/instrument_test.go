@@ -0,0 +1,92 @@
+package instrument
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"serial-assistant/pkg/scpi"
+)
+
+// recorder 记录发出的命令，对查询返回固定值
+type recorder struct {
+	mu   sync.Mutex
+	s    *scpi.Session
+	sent []string
+}
+
+func newRecorder() *recorder {
+	r := &recorder{}
+	r.s = scpi.New(func(_ context.Context, p []byte) error {
+		cmd := strings.TrimSpace(string(p))
+		r.mu.Lock()
+		r.sent = append(r.sent, cmd)
+		r.mu.Unlock()
+		if strings.HasSuffix(cmd, "?") || strings.Contains(cmd, "? ") {
+			resp := "3.30\n"
+			if strings.Contains(cmd, "CURR") {
+				resp = "0.5\n"
+			}
+			go r.s.Feed([]byte(resp))
+		}
+		return nil
+	})
+	return r
+}
+
+func TestPSU(t *testing.T) {
+	p, err := Match(scpi.Identity{Raw: "RIGOL TECHNOLOGIES,DP832,DP8C1234,00.01.14"})
+	if err != nil || p.Name != "rigol-dp800" {
+		t.Fatalf("Expected rigol-dp800, got %+v (%v)", p, err)
+	}
+	r := newRecorder()
+	d := NewDriver(p, r.s)
+	ctx := context.Background()
+	d.SetVoltage(ctx, 2, 3.3)
+	d.SetCurrent(ctx, 2, 0.75)
+	d.SetOutput(ctx, 2, true)
+	reading, err := d.Measure(ctx, 2)
+	if err != nil || reading.Voltage != 3.3 || reading.Current != 0.5 || reading.Power != 1.65 {
+		t.Errorf("Unexpected reading %+v (%v)", reading, err)
+	}
+	want := []string{":SOUR2:VOLT 3.3", ":SOUR2:CURR 0.75", ":OUTP CH2,ON", ":MEAS:VOLT? CH2", ":MEAS:CURR? CH2"}
+	if strings.Join(r.sent, "|") != strings.Join(want, "|") {
+		t.Errorf("Unexpected commands %q", r.sent)
+	}
+
+	if err := d.SetVoltage(ctx, 4, 1); !errors.Is(err, ErrChannel) {
+		t.Errorf("Expected ErrChannel, got %v", err)
+	}
+	if _, err := d.Read(ctx); !errors.Is(err, ErrWrongKind) {
+		t.Errorf("Expected ErrWrongKind for a PSU read, got %v", err)
+	}
+	if m := d.Measurements("psu"); len(m) != 6 || m[2].Name != "psu.ch2.voltage" || m[3].Unit != "A" {
+		t.Errorf("Unexpected measurements %+v", m)
+	}
+}
+
+func TestDMM(t *testing.T) {
+	p, err := Find("keysight-3446x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := Match(scpi.Identity{Raw: "Keysight Technologies,34465A,MY5700,A.02.17"}); m.Name != p.Name {
+		t.Errorf("Expected the 34465A to match %s, got %s", p.Name, m.Name)
+	}
+	r := newRecorder()
+	d := NewDriver(p, r.s)
+	if err := d.Configure(context.Background(), "dcv"); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := d.Read(context.Background()); err != nil || v != 3.3 {
+		t.Errorf("Unexpected reading %v (%v)", v, err)
+	}
+	if err := d.Configure(context.Background(), "teleport"); !errors.Is(err, ErrFunction) {
+		t.Errorf("Expected ErrFunction, got %v", err)
+	}
+	if _, err := Match(scpi.Identity{Raw: "ACME,X1"}); !errors.Is(err, ErrNoMatch) {
+		t.Errorf("Expected ErrNoMatch, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package jlink
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/ebitengine/purego"
+)
+
+// 驱动日志级别
+const (
+	LogInfo  = "info"
+	LogWarn  = "warn"
+	LogError = "error"
+)
+
+// LogEntry 驱动输出的一条日志
+type LogEntry struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+}
+
+// DLLLog 驱动日志设置
+// Handler 接收驱动的日志、警告与错误输出 (含 API 调用记录)；File 非空时驱动同时写入该日志文件
+type DLLLog struct {
+	File    string
+	Handler func(LogEntry)
+}
+
+// 驱动回调只能创建有限个且不会释放，因此整个进程共用一组回调，按当前设置分发
+var (
+	dllLogHandler   atomic.Pointer[func(LogEntry)]
+	dllLogCallbacks struct {
+		once              sync.Once
+		info, warn, error uintptr
+	}
+)
+
+// SetDLLLog 设置驱动日志，须在 Connect 之前调用；nil 表示不启用
+// 多个驱动实例共用同一个日志处理函数，以最后一次连接时的设置为准
+func (jl *JLinkWrapper) SetDLLLog(cfg *DLLLog) {
+	jl.dllLog = cfg
+}
+
+// enableDLLLog 在打开探针前向驱动注册日志回调与日志文件
+func (jl *JLinkWrapper) enableDLLLog() {
+	cfg := jl.dllLog
+	if cfg == nil {
+		return
+	}
+	if cfg.Handler != nil {
+		h := cfg.Handler
+		dllLogHandler.Store(&h)
+		dllLogCallbacks.once.Do(func() {
+			dllLogCallbacks.info = purego.NewCallback(func(msg *byte) { dispatchDLLLog(LogInfo, msg) })
+			dllLogCallbacks.warn = purego.NewCallback(func(msg *byte) { dispatchDLLLog(LogWarn, msg) })
+			dllLogCallbacks.error = purego.NewCallback(func(msg *byte) { dispatchDLLLog(LogError, msg) })
+		})
+		if jl.apiEnableLog != nil {
+			jl.apiEnableLog(dllLogCallbacks.info)
+		}
+		if jl.apiSetWarnOut != nil {
+			jl.apiSetWarnOut(dllLogCallbacks.warn)
+		}
+		if jl.apiSetErrorOut != nil {
+			jl.apiSetErrorOut(dllLogCallbacks.error)
+		}
+	}
+	if cfg.File != "" && jl.apiSetLogFile != nil {
+		jl.apiSetLogFile(cfg.File)
+	}
+}
+
+// DisableDLLLog 停止分发驱动日志
+func DisableDLLLog() {
+	dllLogHandler.Store(nil)
+}
+
+// dispatchDLLLog 将驱动回调中的 C 字符串交给当前的日志处理函数
+func dispatchDLLLog(level string, msg *byte) {
+	h := dllLogHandler.Load()
+	if h == nil || msg == nil {
+		return
+	}
+	text := strings.TrimRight(cString(msg), "\r\n")
+	if text == "" {
+		return
+	}
+	(*h)(LogEntry{Time: time.Now(), Level: level, Message: text})
+}
+
+// cString 复制以 NUL 结尾的 C 字符串
+func cString(p *byte) string {
+	n := 0
+	for *(*byte)(unsafe.Add(unsafe.Pointer(p), n)) != 0 {
+		n++
+	}
+	return string(unsafe.Slice(p, n))
+}
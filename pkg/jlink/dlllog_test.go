@@ -0,0 +1,33 @@
+package jlink
+
+import (
+	"testing"
+)
+
+func TestDispatchDLLLog(t *testing.T) {
+	var got []LogEntry
+	jl := &JLinkWrapper{}
+	var file string
+	jl.apiSetLogFile = func(path string) { file = path }
+	jl.SetDLLLog(&DLLLog{File: "jlink.log", Handler: func(e LogEntry) { got = append(got, e) }})
+	jl.enableDLLLog()
+	defer DisableDLLLog()
+	if file != "jlink.log" {
+		t.Errorf("Expected log file to be set, got %q", file)
+	}
+
+	msg := []byte("T1234 JLINK_Open()\r\n\x00garbage")
+	dispatchDLLLog(LogInfo, &msg[0])
+	empty := []byte("\n\x00")
+	dispatchDLLLog(LogWarn, &empty[0])
+	dispatchDLLLog(LogError, nil)
+	if len(got) != 1 || got[0].Message != "T1234 JLINK_Open()" || got[0].Level != LogInfo {
+		t.Fatalf("Unexpected entries %+v", got)
+	}
+
+	DisableDLLLog()
+	dispatchDLLLog(LogInfo, &msg[0])
+	if len(got) != 1 {
+		t.Error("Entries should not be delivered after DisableDLLLog")
+	}
+}
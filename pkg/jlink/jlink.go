@@ -2,12 +2,16 @@ package jlink
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"os"
 	"runtime"
 	"time"
 	"unsafe"
+
+	"serial-assistant/pkg/i18n"
 )
 
 // LogCallback 日志回调函数类型
@@ -18,7 +22,7 @@ type JLinkWrapper struct {
 	libHandle uintptr
 
 	// 基础 API
-	apiOpen        func() int
+	apiOpen        func() *byte // 成功返回 NULL，失败返回错误信息
 	apiClose       func()
 	apiConnect     func() int
 	apiTIFSelect   func(int) int
@@ -26,6 +30,35 @@ type JLinkWrapper struct {
 	apiIsConnected func() bool
 	apiReadMem     func(uint32, uint32, uintptr) int
 	apiWriteMem    func(uint32, uint32, uintptr) int
+	apiSelectUSBSN func(uint32) int
+	apiSelectIP    func(string, int) int
+	apiGetHWStatus func(uintptr) int
+
+	// 延迟写入：排队到下一次内存访问时一并发出 (旧版驱动可能没有)
+	apiWriteMemDelayed func(uint32, uint32, uintptr) int
+
+	// 后台访问：ReadMemEx 通过 AHB-AP 直接读取，不暂停 CPU；IsHalted 用于判断内核是否在运行
+	apiReadMemEx func(uint32, uint32, uintptr, uint32) int
+	apiIsHalted  func() int
+	background   bool
+
+	// WriteMemory 的撤销记录 (连接期间有效)
+	writes   []MemoryWrite
+	writeSeq int
+
+	// 驱动日志：日志/警告/错误输出回调与日志文件
+	apiEnableLog   func(uintptr)
+	apiSetWarnOut  func(uintptr)
+	apiSetErrorOut func(uintptr)
+	apiSetLogFile  func(string)
+	dllLog         *DLLLog
+
+	// SWO：DWT PC 采样经 ITM 从 SWO 引脚输出，由探针缓存后读取
+	apiSWOEnableTarget  func(uint32, uint32, int, uint32) int
+	apiSWODisableTarget func(uint32) int
+	apiSWOControl       func(uint32, uintptr) int
+	apiSWORead          func(uintptr, uint32, uintptr)
+	swoBuffer           []byte
 
 	// RTT API
 	apiRTTStart func() int
@@ -36,12 +69,21 @@ type JLinkWrapper struct {
 	useSoftRTT    bool
 	rttControlBlk uint32
 	rttUpBuffer   RTTBufferDesc
+	rttDownBuffer RTTBufferDesc // 下行缓冲区 0 的地址与大小 (初始化时读取)
+	rttLayout     Layout
+	elfConfig     *ElfConfig // 固件声明的配置，设置后直接读取其中的控制块地址并校验布局
+	mismatch      []string
 
 	// 日志回调
 	logCallback LogCallback
 
 	// 读取缓冲区重用（避免频繁分配）
 	readBuffer []byte
+
+	// 多探针：指定的探针序列号 (0 表示由驱动自行选择)、连接方式及驱动副本路径
+	serial      uint32
+	target      Target
+	privatePath string
 }
 
 // RTTBufferDesc RTT 缓冲区描述符
@@ -54,11 +96,17 @@ type RTTBufferDesc struct {
 	Flags     uint32
 }
 
+// ErrOffsetOutOfBounds 软件 RTT 读到的偏移量超出缓冲区范围，通常是目标复位导致
+var ErrOffsetOutOfBounds = errors.New("RTT offset out of bounds")
+
 // RTT 读取限制常量
 const (
 	// maxRTTReadSize 限制单次 RTT 读取的最大字节数，防止在连接中断或
 	// 状态损坏时分配过大的内存缓冲区（例如当偏移量被损坏为极大值时）
 	maxRTTReadSize = 64 * 1024 // 64KB
+
+	// wholeBufferRead 回绕时不超过该大小的缓冲区整块读取 (一次往返)，否则分两段读取
+	wholeBufferRead = 4 * 1024
 )
 
 // NewJLinkWrapper 加载驱动并初始化
@@ -69,7 +117,7 @@ func NewJLinkWrapper(logCallback LogCallback) (*JLinkWrapper, error) {
 	}
 
 	if logCallback != nil {
-		logCallback(fmt.Sprintf("[RTT] 正在加载库: %s", libPath))
+		logCallback(i18n.T("rtt.loading_library", libPath))
 	}
 
 	// [修复关键点]
@@ -80,15 +128,21 @@ func NewJLinkWrapper(logCallback LogCallback) (*JLinkWrapper, error) {
 		// Linux 备用路径逻辑也改用 openLibrary
 		if runtime.GOOS == "linux" && libPath == "./libjlinkarm.so" {
 			if logCallback != nil {
-				logCallback("[RTT] 本地加载失败，尝试 /opt/SEGGER/JLink/libjlinkarm.so")
+				logCallback(i18n.T("rtt.local_load_failed", "/opt/SEGGER/JLink/libjlinkarm.so"))
 			}
-			lib, err = openLibrary("/opt/SEGGER/JLink/libjlinkarm.so")
+			libPath = "/opt/SEGGER/JLink/libjlinkarm.so"
+			lib, err = openLibrary(libPath)
 		}
 		if err != nil {
-			return nil, err
+			return nil, libraryError(libPath, err)
 		}
 	}
 
+	return newWrapper(lib, logCallback)
+}
+
+// newWrapper 从已加载的库中注册所需函数
+func newWrapper(lib uintptr, logCallback LogCallback) (*JLinkWrapper, error) {
 	jl := &JLinkWrapper{
 		libHandle:   lib,
 		logCallback: logCallback,
@@ -102,6 +156,8 @@ func NewJLinkWrapper(logCallback LogCallback) (*JLinkWrapper, error) {
 	}
 
 	register(&jl.apiOpen, "JLINK_Open")
+	register(&jl.apiSelectIP, "JLINK_SelectIP")
+	register(&jl.apiGetHWStatus, "JLINK_GetHWStatus")
 	register(&jl.apiClose, "JLINK_Close")
 	register(&jl.apiConnect, "JLINK_Connect")
 	register(&jl.apiTIFSelect, "JLINK_TIF_Select")
@@ -109,12 +165,26 @@ func NewJLinkWrapper(logCallback LogCallback) (*JLinkWrapper, error) {
 	register(&jl.apiIsConnected, "JLINK_IsConnected")
 	register(&jl.apiReadMem, "JLINK_ReadMem")
 	register(&jl.apiWriteMem, "JLINK_WriteMem")
+	register(&jl.apiWriteMemDelayed, "JLINK_WriteMemDelayed")
+	register(&jl.apiReadMemEx, "JLINK_ReadMemEx")
+	register(&jl.apiIsHalted, "JLINK_IsHalted")
+	register(&jl.apiEnableLog, "JLINK_EnableLog")
+	register(&jl.apiSetWarnOut, "JLINK_SetWarnOutHandler")
+	register(&jl.apiSetErrorOut, "JLINK_SetErrorOutHandler")
+	register(&jl.apiSetLogFile, "JLINK_SetLogFile")
+	register(&jl.apiSWOEnableTarget, "JLINK_SWO_EnableTarget")
+	register(&jl.apiSWODisableTarget, "JLINK_SWO_DisableTarget")
+	register(&jl.apiSWOControl, "JLINK_SWO_Control")
+	register(&jl.apiSWORead, "JLINK_SWO_Read")
 	register(&jl.apiRTTStart, "JLINK_RTT_Start")
 	register(&jl.apiRTTRead, "JLINK_RTT_Read")
 	register(&jl.apiRTTWrite, "JLINK_RTT_Write")
 
+	register(&jl.apiSelectUSBSN, "JLINKARM_EMU_SelectByUSBSN")
+
 	if jl.apiOpen == nil || jl.apiReadMem == nil {
-		return nil, fmt.Errorf("RTT 库已加载但缺少核心函数")
+		closeLibrary(lib)
+		return nil, errors.New(i18n.T("rtt.missing_core_funcs"))
 	}
 
 	return jl, nil
@@ -129,10 +199,22 @@ func (jl *JLinkWrapper) log(message string) {
 
 // Connect 连接芯片
 func (jl *JLinkWrapper) Connect(chipName string, speed int, iface string) error {
+	return jl.ConnectContext(context.Background(), chipName, speed, iface)
+}
+
+// ConnectContext 与 Connect 相同，但会在各步骤之间检查 ctx，被取消或超时时尽早返回
+// 注意：单次 DLL 调用本身无法中断
+func (jl *JLinkWrapper) ConnectContext(ctx context.Context, chipName string, speed int, iface string) error {
 	if jl.apiOpen == nil {
-		return fmt.Errorf("RTT API 未初始化")
+		return errors.New(i18n.T("rtt.api_not_initialized"))
+	}
+	jl.enableDLLLog()
+	if err := jl.selectTarget(); err != nil {
+		return err
+	}
+	if msg := jl.apiOpen(); msg != nil {
+		return openError(cString(msg))
 	}
-	jl.apiOpen()
 
 	if iface == "JTAG" {
 		if jl.apiTIFSelect != nil {
@@ -149,35 +231,56 @@ func (jl *JLinkWrapper) Connect(chipName string, speed int, iface string) error
 		jl.apiExecCommand(fmt.Sprintf("Device = %s", chipName), 0, 0)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if jl.apiConnect != nil {
 		if ret := jl.apiConnect(); ret < 0 {
-			return fmt.Errorf("RTT 连接失败 (返回值: %d)", ret)
+			return errors.New(i18n.T("rtt.connect_failed", ret))
 		}
 	}
 
-	jl.log("[RTT] 已连接，等待芯片稳定...")
-	time.Sleep(500 * time.Millisecond)
+	jl.log(i18n.T("rtt.connected_waiting"))
+	if err := sleepContext(ctx, 500*time.Millisecond); err != nil {
+		return err
+	}
 
 	if jl.apiRTTStart != nil && jl.apiRTTRead != nil {
-		jl.log("[RTT] 尝试启动原生 RTT...")
+		jl.log(i18n.T("rtt.starting_native"))
 		if ret := jl.apiRTTStart(); ret >= 0 {
-			jl.log("[RTT] 原生 RTT 已启动")
+			jl.log(i18n.T("rtt.native_started"))
 			jl.useSoftRTT = false
+			jl.detectBackground()
 			return nil
 		}
 	}
 
-	jl.log("[RTT] 原生 RTT 不可用，切换到软件 RTT")
+	jl.log(i18n.T("rtt.fallback_soft"))
 	var err error
 	for i := 0; i < 3; i++ {
 		if err = jl.initSoftRTT(); err == nil {
 			jl.useSoftRTT = true
+			jl.detectBackground()
 			return nil
 		}
-		time.Sleep(500 * time.Millisecond)
+		if ctxErr := sleepContext(ctx, 500*time.Millisecond); ctxErr != nil {
+			return ctxErr
+		}
 	}
 
-	return fmt.Errorf("软件 RTT 初始化失败: %v", err)
+	return errors.New(i18n.T("rtt.soft_init_failed", err))
+}
+
+// sleepContext 等待 d 或 ctx 结束
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (jl *JLinkWrapper) ReadRTT() ([]byte, error) {
@@ -209,8 +312,7 @@ func (jl *JLinkWrapper) WriteRTT(data []byte) (int, error) {
 		n := jl.apiRTTWrite(0, uintptr(unsafe.Pointer(&data[0])), uint32(len(data)))
 		return int(n), nil
 	}
-	// Soft RTT Write not implemented yet
-	return 0, nil
+	return jl.writeSoftRTT(data)
 }
 
 func (jl *JLinkWrapper) Close() {
@@ -219,54 +321,121 @@ func (jl *JLinkWrapper) Close() {
 	}
 	// 使用我们定义的 closeLibrary
 	closeLibrary(jl.libHandle)
+	if jl.privatePath != "" {
+		os.Remove(jl.privatePath)
+	}
 }
 
 // --- Soft RTT Logic ---
 
 func (jl *JLinkWrapper) initSoftRTT() error {
+	if jl.elfConfig != nil && jl.elfConfig.Addr != 0 {
+		if err := jl.loadControlBlock(jl.elfConfig.Addr); err == nil {
+			return nil
+		}
+		jl.log(i18n.T("rtt.elf_cb_invalid", jl.elfConfig.Addr))
+	}
+
 	searchStart := uint32(0x20000000)
 	searchSize := uint32(0x10000)
 	chunkSize := uint32(0x800)
-	memBuf := make([]byte, chunkSize)
+	memBuf := jl.readBuffer
+	if len(memBuf) < int(chunkSize) {
+		memBuf = make([]byte, chunkSize)
+	}
+	memBuf = memBuf[:chunkSize]
 	signature := []byte("SEGGER RTT")
 
-	jl.log("[RTT] 搜索 RTT 控制块...")
+	jl.log(i18n.T("rtt.searching_cb"))
 	for offset := uint32(0); offset < searchSize; offset += chunkSize {
 		addr := searchStart + offset
 		if jl.apiReadMem(addr, chunkSize, uintptr(unsafe.Pointer(&memBuf[0]))) < 0 {
 			continue
 		}
-		idx := bytes.Index(memBuf, signature)
-		if idx >= 0 {
-			jl.rttControlBlk = addr + uint32(idx)
-			jl.log(fmt.Sprintf("[RTT] 找到 RTT 控制块 @ 0x%08X", jl.rttControlBlk))
-			descAddr := jl.rttControlBlk + 16 + 4 + 4
-			descData := make([]byte, 24)
-			if jl.apiReadMem(descAddr, 24, uintptr(unsafe.Pointer(&descData[0]))) < 0 {
-				return fmt.Errorf("读取 RTT 描述符失败")
+		// 同一块内可能有签名字符串的残留副本，逐个尝试直到布局有效
+		for from := 0; from < len(memBuf); {
+			idx := bytes.Index(memBuf[from:], signature)
+			if idx < 0 {
+				break
 			}
-			jl.rttUpBuffer = parseBufferDesc(descData)
-			jl.log("[RTT] 软件 RTT 初始化成功")
-			return nil
+			err := jl.loadControlBlock(addr + uint32(from+idx))
+			if err == nil {
+				return nil
+			}
+			if !errors.Is(err, ErrBadControlBlock) {
+				return err
+			}
+			from += idx + len(signature)
 		}
 	}
-	return fmt.Errorf("未找到 SEGGER RTT 控制块")
+	return errors.New(i18n.T("rtt.cb_not_found"))
+}
+
+// loadControlBlock 读取 addr 处控制块的头部与第一个上行缓冲区描述符
+// 缓冲区数量从目标内存中读取，不假定固件的 RTT 配置；与 ELF 声明不一致时记录差异
+func (jl *JLinkWrapper) loadControlBlock(addr uint32) error {
+	header := scratchBuf(cbHeaderSize)
+	if jl.apiReadMem(addr, cbHeaderSize, uintptr(unsafe.Pointer(&header[0]))) < 0 {
+		return errors.New(i18n.T("rtt.read_desc_failed"))
+	}
+	if !bytes.HasPrefix(header, []byte("SEGGER RTT")) {
+		return ErrBadControlBlock
+	}
+	layout, err := parseLayout(addr, header)
+	if err != nil {
+		return err
+	}
+	jl.log(i18n.T("rtt.found_cb", addr))
+
+	descData := scratchBuf(cbDescSize)
+	if jl.apiReadMem(layout.UpDesc(0), cbDescSize, uintptr(unsafe.Pointer(&descData[0]))) < 0 {
+		return errors.New(i18n.T("rtt.read_desc_failed"))
+	}
+	jl.rttControlBlk = addr
+	jl.rttLayout = layout
+	jl.rttUpBuffer = parseBufferDesc(descData)
+	jl.rttDownBuffer = RTTBufferDesc{}
+	if layout.MaxDown > 0 && jl.apiReadMem(layout.DownDesc(0), cbDescSize, uintptr(unsafe.Pointer(&descData[0]))) >= 0 {
+		jl.rttDownBuffer = parseBufferDesc(descData)
+	}
+	jl.log(i18n.T("rtt.layout", layout.MaxUp, layout.MaxDown))
+
+	jl.mismatch = nil
+	if jl.elfConfig != nil {
+		jl.mismatch = jl.elfConfig.Mismatch(layout)
+		for _, m := range jl.mismatch {
+			jl.log(i18n.T("rtt.layout_mismatch", m))
+		}
+	}
+	jl.log(i18n.T("rtt.soft_init_ok"))
+	return nil
+}
+
+// SetElfConfig 设置固件 ELF 中声明的 RTT 配置，在 Connect 之前调用
+func (jl *JLinkWrapper) SetElfConfig(cfg ElfConfig) {
+	jl.elfConfig = &cfg
+}
+
+// Layout 返回软件 RTT 使用的控制块布局，未使用软件 RTT 时 ok 为 false
+func (jl *JLinkWrapper) Layout() (layout Layout, mismatch []string, ok bool) {
+	if !jl.useSoftRTT || jl.rttControlBlk == 0 {
+		return Layout{}, nil, false
+	}
+	return jl.rttLayout, append([]string(nil), jl.mismatch...), true
 }
 
 func (jl *JLinkWrapper) readSoftRTT() ([]byte, error) {
 	if jl.rttControlBlk == 0 {
 		return nil, nil
 	}
-	wrOffAddr := jl.rttControlBlk + 24 + 12
-	var wrOff uint32
-	if jl.apiReadMem(wrOffAddr, 4, uintptr(unsafe.Pointer(&wrOff))) < 0 {
-		return nil, fmt.Errorf("failed to read write offset")
-	}
-	rdOffAddr := jl.rttControlBlk + 24 + 16
-	var rdOff uint32
-	if jl.apiReadMem(rdOffAddr, 4, uintptr(unsafe.Pointer(&rdOff))) < 0 {
-		return nil, fmt.Errorf("failed to read read offset")
+	// WrOff 与 RdOff 在描述符中相邻，一次读出，空闲轮询只需一次内存访问
+	descAddr := jl.rttControlBlk + 24
+	offsets := scratchBuf(8)
+	if jl.readBlock(descAddr+12, 8, uintptr(unsafe.Pointer(&offsets[0]))) < 0 {
+		return nil, fmt.Errorf("failed to read RTT offsets")
 	}
+	wrOff, rdOff := binary.LittleEndian.Uint32(offsets[0:4]), binary.LittleEndian.Uint32(offsets[4:8])
+	rdOffAddr := descAddr + 16
 
 	bufBase := jl.rttUpBuffer.BufferPtr
 	bufSize := jl.rttUpBuffer.Size
@@ -274,8 +443,8 @@ func (jl *JLinkWrapper) readSoftRTT() ([]byte, error) {
 	// 关键修复：验证偏移量是否在有效范围内
 	// 如果连接中断或状态损坏，偏移量可能变得异常大
 	if wrOff >= bufSize || rdOff >= bufSize {
-		jl.log(fmt.Sprintf("[RTT] 错误：偏移量超出范围 (wrOff=%d, rdOff=%d, bufSize=%d)", wrOff, rdOff, bufSize))
-		return nil, fmt.Errorf("RTT offset out of bounds: wrOff=%d, rdOff=%d, bufSize=%d", wrOff, rdOff, bufSize)
+		jl.log(i18n.T("rtt.offset_out_of_range", wrOff, rdOff, bufSize))
+		return nil, fmt.Errorf("%w: wrOff=%d, rdOff=%d, bufSize=%d", ErrOffsetOutOfBounds, wrOff, rdOff, bufSize)
 	}
 
 	if wrOff == rdOff {
@@ -288,14 +457,13 @@ func (jl *JLinkWrapper) readSoftRTT() ([]byte, error) {
 		readLen := wrOff - rdOff
 		// 关键修复：限制读取长度，防止分配过大内存
 		if readLen > maxRTTReadSize {
-			jl.log(fmt.Sprintf("[RTT] 警告：读取长度过大 (%d bytes)，限制为 %d bytes", readLen, maxRTTReadSize))
+			jl.log(i18n.T("rtt.read_len_clamped", readLen, maxRTTReadSize))
 			readLen = maxRTTReadSize
 		}
-		chunk := make([]byte, readLen)
-		if jl.apiReadMem(bufBase+rdOff, readLen, uintptr(unsafe.Pointer(&chunk[0]))) < 0 {
+		data = make([]byte, readLen)
+		if jl.readBlock(bufBase+rdOff, readLen, uintptr(unsafe.Pointer(&data[0]))) < 0 {
 			return nil, fmt.Errorf("failed to read RTT data")
 		}
-		data = chunk
 		rdOff += readLen
 	} else {
 		// 环形缓冲区回绕情况
@@ -305,7 +473,7 @@ func (jl *JLinkWrapper) readSoftRTT() ([]byte, error) {
 
 		// 关键修复：检查总读取长度
 		if totalLen > maxRTTReadSize {
-			jl.log(fmt.Sprintf("[RTT] 警告：总读取长度过大 (%d bytes)，限制为 %d bytes", totalLen, maxRTTReadSize))
+			jl.log(i18n.T("rtt.total_len_clamped", totalLen, maxRTTReadSize))
 			// 优先读取缓冲区末尾的数据
 			if len1 > maxRTTReadSize {
 				len1 = maxRTTReadSize
@@ -315,32 +483,131 @@ func (jl *JLinkWrapper) readSoftRTT() ([]byte, error) {
 			}
 		}
 
-		if len1 > 0 {
-			chunk1 := make([]byte, len1)
-			if jl.apiReadMem(bufBase+rdOff, len1, uintptr(unsafe.Pointer(&chunk1[0]))) < 0 {
+		data = make([]byte, len1+len2)
+		if bufSize <= wholeBufferRead && len2 > 0 {
+			// 缓冲区不大时整块读出再拼接，比分两段读少一次往返
+			whole := make([]byte, bufSize)
+			if jl.readBlock(bufBase, bufSize, uintptr(unsafe.Pointer(&whole[0]))) < 0 {
+				return nil, fmt.Errorf("failed to read RTT data")
+			}
+			copy(data, whole[rdOff:rdOff+len1])
+			copy(data[len1:], whole[:len2])
+		} else {
+			if len1 > 0 && jl.readBlock(bufBase+rdOff, len1, uintptr(unsafe.Pointer(&data[0]))) < 0 {
 				return nil, fmt.Errorf("failed to read RTT data (segment 1)")
 			}
-			data = append(data, chunk1...)
-		}
-		if len2 > 0 {
-			chunk2 := make([]byte, len2)
-			if jl.apiReadMem(bufBase, len2, uintptr(unsafe.Pointer(&chunk2[0]))) < 0 {
+			if len2 > 0 && jl.readBlock(bufBase, len2, uintptr(unsafe.Pointer(&data[len1]))) < 0 {
 				return nil, fmt.Errorf("failed to read RTT data (segment 2)")
 			}
-			data = append(data, chunk2...)
 		}
 		// 更新读偏移量：len1 和 len2 是实际读取的长度（已考虑截断）
 		// 使用模运算确保在环形缓冲区中正确回绕
 		rdOff = (rdOff + len1 + len2) % bufSize
 	}
 
-	// 写回更新的读偏移量
-	if jl.apiWriteMem(rdOffAddr, 4, uintptr(unsafe.Pointer(&rdOff))) < 0 {
-		jl.log("[RTT] 警告：无法更新读偏移量")
+	// 写回更新的读偏移量 (单次对齐的 32 位写入，目标看到的 RdOff 不会是中间值)
+	// 驱动支持延迟写入时随下一次读取一并发出，省去一次往返
+	write := jl.apiWriteMem
+	if jl.apiWriteMemDelayed != nil {
+		write = jl.apiWriteMemDelayed
+	}
+	value := scratchBuf(4)
+	binary.LittleEndian.PutUint32(value, rdOff)
+	if write(rdOffAddr, 4, uintptr(unsafe.Pointer(&value[0]))) < 0 {
+		jl.log(i18n.T("rtt.update_rdoff_failed"))
 	}
 	return data, nil
 }
 
+// writeSoftRTT 写入下行缓冲区 0，返回实际写入的字节数 (缓冲区满时可能少于 len(data))
+// 下行描述符位于所有上行描述符之后，其地址取决于控制块中读出的上行缓冲区数量
+// 数据段以延迟写入排队，随最后的 WrOff 更新一次发出：目标总是先看到数据再看到新的 WrOff
+func (jl *JLinkWrapper) writeSoftRTT(data []byte) (int, error) {
+	desc := jl.rttDownBuffer
+	if jl.rttControlBlk == 0 || jl.rttLayout.MaxDown == 0 || desc.Size == 0 || jl.apiWriteMem == nil {
+		return 0, nil
+	}
+	descAddr := jl.rttLayout.DownDesc(0)
+	offsets := scratchBuf(8)
+	if jl.readBlock(descAddr+12, 8, uintptr(unsafe.Pointer(&offsets[0]))) < 0 {
+		return 0, fmt.Errorf("failed to read down buffer offsets")
+	}
+	wrOff, rdOff := binary.LittleEndian.Uint32(offsets[0:4]), binary.LittleEndian.Uint32(offsets[4:8])
+	if wrOff >= desc.Size || rdOff >= desc.Size {
+		return 0, fmt.Errorf("%w: wrOff=%d, rdOff=%d, bufSize=%d", ErrOffsetOutOfBounds, wrOff, rdOff, desc.Size)
+	}
+
+	// 环形缓冲区保留一个字节区分空与满
+	free := rdOff + desc.Size - wrOff - 1
+	if rdOff > wrOff {
+		free = rdOff - wrOff - 1
+	}
+	if uint32(len(data)) < free {
+		free = uint32(len(data))
+	}
+	if free == 0 {
+		return 0, nil
+	}
+
+	queue := jl.apiWriteMem
+	if jl.apiWriteMemDelayed != nil {
+		queue = jl.apiWriteMemDelayed
+	}
+	for written := uint32(0); written < free; {
+		n := free - written
+		if wrOff+n > desc.Size {
+			n = desc.Size - wrOff
+		}
+		if queue(desc.BufferPtr+wrOff, n, uintptr(unsafe.Pointer(&data[written]))) < 0 {
+			return 0, fmt.Errorf("failed to write RTT data")
+		}
+		written += n
+		wrOff = (wrOff + n) % desc.Size
+	}
+	value := scratchBuf(4)
+	binary.LittleEndian.PutUint32(value, wrOff)
+	if jl.apiWriteMem(descAddr+12, 4, uintptr(unsafe.Pointer(&value[0]))) < 0 {
+		return 0, fmt.Errorf("failed to update RTT write offset")
+	}
+	return int(free), nil
+}
+
+// scratchBuf 返回 n 字节的暂存区，用于描述符、偏移量等小块读写
+// 传给驱动的是裸地址，栈上的变量可能在调用过程中随栈扩容而移动，因此使用堆上的缓冲区 (禁止内联以免被分配到调用方栈上)；
+// RTT 轮询与发送在不同的 goroutine 中并发执行，每次调用单独分配，不能共用
+//
+//go:noinline
+func scratchBuf(n int) []byte {
+	return make([]byte, n)
+}
+
+// readBlock 读取软件 RTT 轮询所需的内存，失败时返回负数
+// 驱动支持 ReadMemEx 时使用后台访问，CPU 运行中也无需暂停内核；否则退回 ReadMem
+func (jl *JLinkWrapper) readBlock(addr, size uint32, buf uintptr) int {
+	if jl.apiReadMemEx != nil {
+		if n := jl.apiReadMemEx(addr, size, buf, 0); n >= 0 && uint32(n) >= size {
+			return 0
+		}
+		return -1
+	}
+	return jl.apiReadMem(addr, size, buf)
+}
+
+// detectBackground 连接后判断 RTT 轮询是否以后台方式访问运行中的目标
+// 原生 RTT 由驱动在后台读取；软件 RTT 需要驱动提供 ReadMemEx
+func (jl *JLinkWrapper) detectBackground() {
+	running := jl.apiIsHalted != nil && jl.apiIsHalted() == 0
+	jl.background = running && (!jl.useSoftRTT || jl.apiReadMemEx != nil)
+	if jl.background {
+		jl.log(i18n.T("rtt.background_access"))
+	}
+}
+
+// BackgroundAccess RTT 轮询是否在目标运行时以后台方式访问内存 (不暂停 CPU)
+func (jl *JLinkWrapper) BackgroundAccess() bool {
+	return jl.background
+}
+
 func parseBufferDesc(data []byte) RTTBufferDesc {
 	return RTTBufferDesc{
 		NamePtr:   binary.LittleEndian.Uint32(data[0:4]),
@@ -352,12 +619,63 @@ func parseBufferDesc(data []byte) RTTBufferDesc {
 	}
 }
 
+// regCPUID Cortex-M SCB CPUID 寄存器，所有 Cortex-M 内核都可读
+const regCPUID = 0xE000ED00
+
+// cpuidImplementerARM CPUID 中 Implementer 字段 (高 8 位) 为 ARM
+const cpuidImplementerARM = 0x41
+
+// chipIDAddrs 按 CPUID 的 PARTNO 字段给出可能的 STM32 DBGMCU_IDCODE 地址
+// 只有确认是对应内核后才访问，避免在其他芯片上读取未映射或有副作用的地址
+var chipIDAddrs = map[uint32][]uint32{
+	0xC20: {0x40015800},             // Cortex-M0: F0
+	0xC60: {0x40015800},             // Cortex-M0+: G0/L0
+	0xC23: {0xE0042000},             // Cortex-M3: F1/F2/L1
+	0xC24: {0xE0042000},             // Cortex-M4: F3/F4/L4/G4
+	0xC27: {0xE0042000, 0x5C001000}, // Cortex-M7: F7/H7
+}
+
+// ReadU32 读取目标内存中的一个 32 位字
+func (jl *JLinkWrapper) ReadU32(addr uint32) (uint32, error) {
+	if jl.apiReadMem == nil {
+		return 0, errors.New(i18n.T("rtt.api_not_initialized"))
+	}
+	value := scratchBuf(4)
+	if jl.apiReadMem(addr, 4, uintptr(unsafe.Pointer(&value[0]))) < 0 {
+		return 0, fmt.Errorf("failed to read memory @ 0x%08X", addr)
+	}
+	return binary.LittleEndian.Uint32(value), nil
+}
+
+// ReadChipID 先读 CPUID 确认是 ARM Cortex-M 内核，再依次尝试该内核对应的芯片 ID 寄存器，返回第一个有效值
+func (jl *JLinkWrapper) ReadChipID() (uint32, error) {
+	cpuid, err := jl.ReadU32(regCPUID)
+	if err != nil {
+		return 0, err
+	}
+	if cpuid>>24 != cpuidImplementerARM {
+		return 0, fmt.Errorf("unsupported core: CPUID 0x%08X", cpuid)
+	}
+	addrs, ok := chipIDAddrs[cpuid>>4&0xFFF]
+	if !ok {
+		return 0, fmt.Errorf("unsupported core: CPUID 0x%08X", cpuid)
+	}
+	for _, addr := range addrs {
+		id, err := jl.ReadU32(addr)
+		if err != nil || id == 0 || id == 0xFFFFFFFF || id&0xFFF == 0 {
+			continue
+		}
+		return id, nil
+	}
+	return 0, fmt.Errorf("chip ID not available")
+}
+
 // ReinitSoftRTT attempts to reinitialize software RTT (used to recover connection after STM32 reset)
 func (jl *JLinkWrapper) ReinitSoftRTT() error {
 	if !jl.useSoftRTT {
 		return fmt.Errorf("not using soft RTT")
 	}
-	jl.log("[RTT] 检测到偏移量异常，尝试重新初始化 RTT...")
+	jl.log(i18n.T("rtt.reinit"))
 	return jl.initSoftRTT()
 }
 
@@ -1,8 +1,10 @@
 package jlink
 
 import (
+	"encoding/binary"
 	"os"
 	"runtime"
+	"sync"
 	"testing"
 	"unsafe"
 )
@@ -235,3 +237,151 @@ func TestBufferReuse(t *testing.T) {
 		t.Errorf("readBuffer capacity should remain 4096, got %d", cap(jl.readBuffer))
 	}
 }
+
+// TestSoftRTTMemoryTransactions verifies the number of target memory accesses per poll
+func TestSoftRTTMemoryTransactions(t *testing.T) {
+	f := &fakeTarget{base: 0x20000000, mem: make([]byte, 0x10000)}
+	l := f.controlBlock(0x20000000, 2, 1)
+	jl := newFakeWrapper(f)
+	if err := jl.initSoftRTT(); err != nil {
+		t.Fatalf("initSoftRTT failed: %v", err)
+	}
+	var delayed int
+	jl.apiWriteMemDelayed = func(addr, size uint32, buf uintptr) int {
+		delayed++
+		return f.write(addr, size, buf)
+	}
+
+	// 空闲轮询：一次读取偏移量
+	f.reads, f.writes = 0, 0
+	if data, err := jl.ReadRTT(); err != nil || data != nil {
+		t.Fatalf("Idle poll returned %q, %v", data, err)
+	}
+	if f.reads != 1 || f.writes != 0 {
+		t.Errorf("Idle poll: expected 1 read, got %d reads %d writes", f.reads, f.writes)
+	}
+
+	// 回绕的数据：偏移量一次 + 整块数据一次，RdOff 延迟写入
+	copy(f.mem[0x8000+250:], "abcdef")
+	copy(f.mem[0x8000:], "ghij")
+	f.u32(l.UpDesc(0)+12, 4)   // WrOff
+	f.u32(l.UpDesc(0)+16, 250) // RdOff
+	f.reads, f.writes, delayed = 0, 0, 0
+	data, err := jl.ReadRTT()
+	if err != nil || string(data) != "abcdefghij" {
+		t.Fatalf("Wrapped read returned %q, %v", data, err)
+	}
+	if f.reads != 2 || delayed != 1 {
+		t.Errorf("Wrapped read: expected 2 reads and 1 delayed write, got %d reads %d delayed", f.reads, delayed)
+	}
+	if rd := binary.LittleEndian.Uint32(f.mem[l.UpDesc(0)+16-f.base:]); rd != 4 {
+		t.Errorf("Expected RdOff 4, got %d", rd)
+	}
+
+	// 写入：偏移量一次读取，数据延迟写入，WrOff 更新时一并发出
+	f.reads, f.writes, delayed = 0, 0, 0
+	if n, err := jl.WriteRTT([]byte("hi")); err != nil || n != 2 {
+		t.Fatalf("WriteRTT = %d, %v", n, err)
+	}
+	if f.reads != 1 || delayed != 1 || f.writes-delayed != 1 {
+		t.Errorf("Write: expected 1 read, 1 delayed and 1 direct write, got %d reads %d writes %d delayed", f.reads, f.writes, delayed)
+	}
+}
+
+// TestConcurrentSoftRTTReadWrite 发送与轮询并发执行时各自的偏移量互不干扰
+func TestConcurrentSoftRTTReadWrite(t *testing.T) {
+	f := &fakeTarget{base: 0x20000000, mem: make([]byte, 0x10000)}
+	l := f.controlBlock(0x20000000, 1, 1)
+	jl := newFakeWrapper(f)
+	if err := jl.initSoftRTT(); err != nil {
+		t.Fatalf("initSoftRTT failed: %v", err)
+	}
+	copy(f.mem[0x8000:], "ping")
+	f.u32(l.UpDesc(0)+12, 4)    // 上行 WrOff
+	f.u32(l.DownDesc(0)+12, 12) // 下行 WrOff/RdOff
+	f.u32(l.DownDesc(0)+16, 12)
+
+	// 轮询读出上行偏移量之后、解析之前，另一个 goroutine 完成一次发送
+	var once sync.Once
+	f.onRead = func(addr uint32) {
+		if addr != l.UpDesc(0)+12 {
+			return
+		}
+		once.Do(func() {
+			done := make(chan error)
+			go func() {
+				_, err := jl.WriteRTT([]byte("x"))
+				done <- err
+			}()
+			if err := <-done; err != nil {
+				t.Errorf("WriteRTT failed: %v", err)
+			}
+		})
+	}
+	data, err := jl.ReadRTT()
+	if err != nil || string(data) != "ping" {
+		t.Fatalf("Expected \"ping\", got %q, %v", data, err)
+	}
+	if rd := binary.LittleEndian.Uint32(f.mem[l.UpDesc(0)+16-f.base:]); rd != 4 {
+		t.Errorf("Expected up RdOff 4, got %d", rd)
+	}
+	if wr := binary.LittleEndian.Uint32(f.mem[l.DownDesc(0)+12-f.base:]); wr != 13 {
+		t.Errorf("Expected down WrOff 13, got %d", wr)
+	}
+
+	// 持续并发收发 (配合 -race 检查共享状态)
+	f.onRead = nil
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			if _, err := jl.WriteRTT([]byte("y")); err != nil {
+				t.Errorf("WriteRTT failed: %v", err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		if _, err := jl.ReadRTT(); err != nil {
+			t.Errorf("ReadRTT failed: %v", err)
+			break
+		}
+	}
+	wg.Wait()
+}
+
+// TestBackgroundAccess verifies ReadMemEx is preferred for polling and background detection
+func TestBackgroundAccess(t *testing.T) {
+	var legacy, ex int
+	jl := &JLinkWrapper{
+		useSoftRTT:  true,
+		apiReadMem:  func(addr, size uint32, buf uintptr) int { legacy++; return 0 },
+		apiIsHalted: func() int { return 0 },
+	}
+	jl.detectBackground()
+	if jl.BackgroundAccess() || jl.readBlock(0x20000000, 8, 0) < 0 || legacy != 1 {
+		t.Error("Without ReadMemEx soft RTT should use ReadMem and not report background access")
+	}
+
+	jl.apiReadMemEx = func(addr, size uint32, buf uintptr, flags uint32) int {
+		ex++
+		if size > 4 {
+			return 4 // 只读到部分数据
+		}
+		return int(size)
+	}
+	jl.detectBackground()
+	if !jl.BackgroundAccess() {
+		t.Error("Expected background access with ReadMemEx on a running core")
+	}
+	if jl.readBlock(0x20000000, 4, 0) < 0 || jl.readBlock(0x20000000, 8, 0) >= 0 || ex != 2 || legacy != 1 {
+		t.Errorf("Unexpected readBlock behaviour: ex=%d legacy=%d", ex, legacy)
+	}
+
+	jl.apiIsHalted = func() int { return 1 }
+	jl.detectBackground()
+	if jl.BackgroundAccess() {
+		t.Error("A halted core should not report background access")
+	}
+}
@@ -0,0 +1,183 @@
+package jlink
+
+import (
+	"debug/dwarf"
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// SEGGER_RTT_CB 布局：acID[16]、MaxNumUpBuffers、MaxNumDownBuffers，随后是
+// MaxNumUpBuffers 个上行描述符与 MaxNumDownBuffers 个下行描述符 (各 24 字节)
+const (
+	cbHeaderSize  = 24
+	cbDescSize    = 24
+	maxRTTBuffers = 64 // 缓冲区数量的合理上限，超过即认为找到的不是控制块
+)
+
+// rttSymbol 控制块在固件中的符号名
+const rttSymbol = "_SEGGER_RTT"
+
+// ErrBadControlBlock 控制块中的缓冲区数量不合理 (签名字符串的残留副本或内存损坏)
+var ErrBadControlBlock = errors.New("invalid RTT control block")
+
+// ErrNoRTTSymbol ELF 文件中没有 _SEGGER_RTT 符号
+var ErrNoRTTSymbol = errors.New("_SEGGER_RTT symbol not found")
+
+// Layout 从目标内存读出的控制块布局
+type Layout struct {
+	Addr    uint32 `json:"addr"`
+	MaxUp   uint32 `json:"maxUp"`
+	MaxDown uint32 `json:"maxDown"`
+}
+
+// parseLayout 解析控制块头部 (不含签名校验)
+func parseLayout(addr uint32, header []byte) (Layout, error) {
+	l := Layout{
+		Addr:    addr,
+		MaxUp:   binary.LittleEndian.Uint32(header[16:20]),
+		MaxDown: binary.LittleEndian.Uint32(header[20:24]),
+	}
+	if l.MaxUp == 0 || l.MaxUp > maxRTTBuffers || l.MaxDown > maxRTTBuffers {
+		return l, fmt.Errorf("%w: %d up / %d down buffers", ErrBadControlBlock, l.MaxUp, l.MaxDown)
+	}
+	return l, nil
+}
+
+// UpDesc 返回第 i 个上行缓冲区描述符的地址
+func (l Layout) UpDesc(i uint32) uint32 {
+	return l.Addr + cbHeaderSize + cbDescSize*i
+}
+
+// DownDesc 返回第 i 个下行缓冲区描述符的地址，位置取决于上行缓冲区的数量
+func (l Layout) DownDesc(i uint32) uint32 {
+	return l.Addr + cbHeaderSize + cbDescSize*(l.MaxUp+i)
+}
+
+// Size 控制块的字节数
+func (l Layout) Size() uint32 {
+	return cbHeaderSize + cbDescSize*(l.MaxUp+l.MaxDown)
+}
+
+// ElfConfig 固件 ELF 中声明的 RTT 配置
+// 有调试信息时 Up/Down 取自 aUp/aDown 数组的长度，否则为 0，只能按符号大小校验缓冲区总数
+type ElfConfig struct {
+	Path string `json:"path"`
+	Addr uint32 `json:"addr"`
+	Size uint32 `json:"size"`
+	Up   uint32 `json:"up"`
+	Down uint32 `json:"down"`
+}
+
+// Total 按符号大小推算的缓冲区总数
+func (c ElfConfig) Total() uint32 {
+	if c.Size < cbHeaderSize {
+		return 0
+	}
+	return (c.Size - cbHeaderSize) / cbDescSize
+}
+
+// ReadElfConfig 从固件 ELF 中读取控制块地址与声明的缓冲区数量
+func ReadElfConfig(path string) (ElfConfig, error) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return ElfConfig{}, err
+	}
+	defer f.Close()
+
+	syms, err := f.Symbols()
+	if err != nil && !errors.Is(err, elf.ErrNoSymbols) {
+		return ElfConfig{}, err
+	}
+	cfg := ElfConfig{Path: path}
+	found := false
+	for _, s := range syms {
+		if s.Name == rttSymbol {
+			cfg.Addr, cfg.Size = uint32(s.Value), uint32(s.Size)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return cfg, ErrNoRTTSymbol
+	}
+	if d, err := f.DWARF(); err == nil {
+		cfg.Up, cfg.Down = dwarfCounts(d)
+	}
+	return cfg, nil
+}
+
+// dwarfCounts 在调试信息中查找 _SEGGER_RTT 变量，返回其 aUp/aDown 数组长度
+func dwarfCounts(d *dwarf.Data) (up, down uint32) {
+	r := d.Reader()
+	for {
+		e, err := r.Next()
+		if err != nil || e == nil {
+			return 0, 0
+		}
+		if e.Tag != dwarf.TagVariable || e.Val(dwarf.AttrName) != rttSymbol {
+			continue
+		}
+		off, ok := e.Val(dwarf.AttrType).(dwarf.Offset)
+		if !ok {
+			continue
+		}
+		t, err := d.Type(off)
+		if err != nil {
+			continue
+		}
+		st, ok := underlying(t).(*dwarf.StructType)
+		if !ok {
+			continue
+		}
+		for _, field := range st.Field {
+			at, ok := underlying(field.Type).(*dwarf.ArrayType)
+			if !ok || at.Count < 0 {
+				continue
+			}
+			switch field.Name {
+			case "aUp":
+				up = uint32(at.Count)
+			case "aDown":
+				down = uint32(at.Count)
+			}
+		}
+		if up > 0 {
+			return up, down
+		}
+	}
+}
+
+// underlying 去掉 typedef 与 volatile/const 修饰
+func underlying(t dwarf.Type) dwarf.Type {
+	for {
+		switch v := t.(type) {
+		case *dwarf.TypedefType:
+			t = v.Type
+		case *dwarf.QualType:
+			t = v.Type
+		default:
+			return t
+		}
+	}
+}
+
+// Mismatch 比较目标上的控制块与 ELF 声明的配置，返回差异说明 (一致时为空)
+func (c ElfConfig) Mismatch(l Layout) []string {
+	var diffs []string
+	if c.Addr != 0 && c.Addr != l.Addr {
+		diffs = append(diffs, fmt.Sprintf("address 0x%08X, ELF declares 0x%08X", l.Addr, c.Addr))
+	}
+	if c.Up > 0 {
+		if c.Up != l.MaxUp {
+			diffs = append(diffs, fmt.Sprintf("%d up buffers, ELF declares %d", l.MaxUp, c.Up))
+		}
+		if c.Down != l.MaxDown {
+			diffs = append(diffs, fmt.Sprintf("%d down buffers, ELF declares %d", l.MaxDown, c.Down))
+		}
+	} else if total := c.Total(); total > 0 && total != l.MaxUp+l.MaxDown {
+		diffs = append(diffs, fmt.Sprintf("%d buffers, ELF symbol size allows %d", l.MaxUp+l.MaxDown, total))
+	}
+	return diffs
+}
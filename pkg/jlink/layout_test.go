@@ -0,0 +1,171 @@
+package jlink
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+// fakeTarget 模拟目标内存，供 apiReadMem/apiWriteMem 使用；与驱动一样串行化各次访问
+type fakeTarget struct {
+	mu     sync.Mutex
+	base   uint32
+	mem    []byte
+	reads  int
+	writes int
+	onRead func(addr uint32) // 每次读取完成后调用 (不持有锁)
+}
+
+// bytesAt 将驱动传入的缓冲区地址视为字节切片
+func bytesAt(p uintptr, n uint32) []byte {
+	return unsafe.Slice((*byte)(*(*unsafe.Pointer)(unsafe.Pointer(&p))), n)
+}
+
+func (f *fakeTarget) read(addr, size uint32, buf uintptr) int {
+	f.mu.Lock()
+	f.reads++
+	if addr < f.base || addr+size > f.base+uint32(len(f.mem)) {
+		f.mu.Unlock()
+		return -1
+	}
+	copy(bytesAt(buf, size), f.mem[addr-f.base:])
+	f.mu.Unlock()
+	if f.onRead != nil {
+		f.onRead(addr)
+	}
+	return 0
+}
+
+func (f *fakeTarget) write(addr, size uint32, buf uintptr) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.writes++
+	if addr < f.base || addr+size > f.base+uint32(len(f.mem)) {
+		return -1
+	}
+	copy(f.mem[addr-f.base:], bytesAt(buf, size))
+	return 0
+}
+
+func (f *fakeTarget) u32(addr, v uint32) {
+	binary.LittleEndian.PutUint32(f.mem[addr-f.base:], v)
+}
+
+// controlBlock 在 addr 处写入有 up 个上行、down 个下行缓冲区的控制块
+func (f *fakeTarget) controlBlock(addr, up, down uint32) Layout {
+	copy(f.mem[addr-f.base:], "SEGGER RTT\x00\x00\x00\x00\x00\x00")
+	f.u32(addr+16, up)
+	f.u32(addr+20, down)
+	l := Layout{Addr: addr, MaxUp: up, MaxDown: down}
+	f.u32(l.UpDesc(0)+4, 0x20008000)
+	f.u32(l.UpDesc(0)+8, 256)
+	if down > 0 {
+		f.u32(l.DownDesc(0)+4, 0x20009000)
+		f.u32(l.DownDesc(0)+8, 16)
+	}
+	return l
+}
+
+func newFakeWrapper(f *fakeTarget) *JLinkWrapper {
+	return &JLinkWrapper{apiReadMem: f.read, apiWriteMem: f.write, useSoftRTT: true, readBuffer: make([]byte, 4096)}
+}
+
+func TestParseLayoutRejectsImplausibleCounts(t *testing.T) {
+	header := make([]byte, cbHeaderSize)
+	binary.LittleEndian.PutUint32(header[16:], 3)
+	binary.LittleEndian.PutUint32(header[20:], 2)
+	l, err := parseLayout(0x20000000, header)
+	if err != nil || l.MaxUp != 3 || l.MaxDown != 2 {
+		t.Fatalf("parseLayout = %+v, %v", l, err)
+	}
+	if l.DownDesc(0) != 0x20000000+24+3*24 || l.Size() != 24+5*24 {
+		t.Errorf("Unexpected offsets: down=0x%X size=%d", l.DownDesc(0), l.Size())
+	}
+	binary.LittleEndian.PutUint32(header[16:], 0x41414141)
+	if _, err := parseLayout(0, header); !errors.Is(err, ErrBadControlBlock) {
+		t.Errorf("Expected ErrBadControlBlock, got %v", err)
+	}
+}
+
+func TestInitSoftRTTSkipsStaleSignature(t *testing.T) {
+	f := &fakeTarget{base: 0x20000000, mem: make([]byte, 0x10000)}
+	// 残留的签名字符串，后面不是有效的缓冲区数量
+	copy(f.mem[0x100:], "SEGGER RTT")
+	f.u32(0x20000100+16, 0xFFFFFFFF)
+	f.controlBlock(0x20000400, 3, 3)
+
+	jl := newFakeWrapper(f)
+	if err := jl.initSoftRTT(); err != nil {
+		t.Fatalf("initSoftRTT failed: %v", err)
+	}
+	l, mismatch, ok := jl.Layout()
+	if !ok || l.Addr != 0x20000400 || l.MaxUp != 3 || len(mismatch) != 0 {
+		t.Errorf("Unexpected layout %+v %v %v", l, mismatch, ok)
+	}
+	if jl.rttUpBuffer.Size != 256 {
+		t.Errorf("Unexpected up buffer %+v", jl.rttUpBuffer)
+	}
+}
+
+func TestElfConfigMismatch(t *testing.T) {
+	f := &fakeTarget{base: 0x20000000, mem: make([]byte, 0x20000)}
+	f.controlBlock(0x20018000, 2, 1) // 超出默认搜索范围，只能通过 ELF 地址找到
+
+	jl := newFakeWrapper(f)
+	jl.SetElfConfig(ElfConfig{Addr: 0x20018000, Up: 3, Down: 3})
+	if err := jl.initSoftRTT(); err != nil {
+		t.Fatalf("initSoftRTT failed: %v", err)
+	}
+	_, mismatch, _ := jl.Layout()
+	if len(mismatch) != 2 || !strings.Contains(mismatch[0], "2 up buffers, ELF declares 3") {
+		t.Errorf("Unexpected mismatch %q", mismatch)
+	}
+
+	sizeOnly := ElfConfig{Size: 24 + 3*24}
+	if diffs := sizeOnly.Mismatch(Layout{MaxUp: 2, MaxDown: 1}); len(diffs) != 0 {
+		t.Errorf("Matching buffer total should not be reported: %q", diffs)
+	}
+	if diffs := sizeOnly.Mismatch(Layout{MaxUp: 3, MaxDown: 3}); len(diffs) != 1 {
+		t.Errorf("Expected a buffer total mismatch, got %q", diffs)
+	}
+}
+
+func TestWriteSoftRTTUsesDownDescriptorAfterUpBuffers(t *testing.T) {
+	f := &fakeTarget{base: 0x20000000, mem: make([]byte, 0x10000)}
+	l := f.controlBlock(0x20000000, 3, 1)
+	f.u32(l.DownDesc(0)+12, 12) // 空缓冲区，WrOff 接近末尾，写入需回绕
+	f.u32(l.DownDesc(0)+16, 12)
+
+	jl := newFakeWrapper(f)
+	if err := jl.initSoftRTT(); err != nil {
+		t.Fatalf("initSoftRTT failed: %v", err)
+	}
+	n, err := jl.WriteRTT([]byte("hello world, too long"))
+	if err != nil {
+		t.Fatalf("WriteRTT failed: %v", err)
+	}
+	if n != 15 { // 16 字节缓冲区保留 1 字节
+		t.Errorf("Expected 15 bytes written, got %d", n)
+	}
+	buf := f.mem[0x9000 : 0x9000+16]
+	if string(buf[12:16]) != "hell" || string(buf[0:11]) != "o world, to" {
+		t.Errorf("Unexpected buffer content %q", buf)
+	}
+	if wr := binary.LittleEndian.Uint32(f.mem[l.DownDesc(0)+12-f.base:]); wr != 11 {
+		t.Errorf("Expected WrOff 11, got %d", wr)
+	}
+}
+
+func TestReadElfConfigWithoutSymbol(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	if _, err := ReadElfConfig(exe); err == nil {
+		t.Error("Expected an error for a binary without _SEGGER_RTT")
+	}
+}
@@ -0,0 +1,92 @@
+package jlink
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+
+	"serial-assistant/pkg/i18n"
+)
+
+// 限制
+const (
+	MaxMemoryAccess = 64 * 1024 // ReadMemory/WriteMemory 单次访问的字节数上限
+	maxUndoWrites   = 1024      // 撤销记录的条数上限，超出后丢弃最早的记录
+)
+
+// MemoryWrite 一次经探针的内存写入及写入前的原始内容
+type MemoryWrite struct {
+	Seq  int       `json:"seq"`
+	Time time.Time `json:"time"`
+	Addr uint32    `json:"addr"`
+	Old  []byte    `json:"old"`
+	New  []byte    `json:"new"`
+}
+
+// ErrMemoryRange 访问长度为 0 或超过 MaxMemoryAccess
+var ErrMemoryRange = errors.New("jlink: memory access length out of range")
+
+// ReadMemory 读取目标内存 (寄存器或 RAM)，CPU 运行时驱动会短暂暂停内核
+func (jl *JLinkWrapper) ReadMemory(addr uint32, n int) ([]byte, error) {
+	if jl.apiReadMem == nil {
+		return nil, errors.New(i18n.T("rtt.api_not_initialized"))
+	}
+	if n <= 0 || n > MaxMemoryAccess {
+		return nil, ErrMemoryRange
+	}
+	buf := make([]byte, n)
+	if jl.apiReadMem(addr, uint32(n), uintptr(unsafe.Pointer(&buf[0]))) < 0 {
+		return nil, fmt.Errorf("failed to read memory @ 0x%08X", addr)
+	}
+	return buf, nil
+}
+
+// WriteMemory 写入目标内存，写入前读出原始内容记入本次连接的撤销记录，可用 RevertWrites 恢复
+// 写入失败时也会保留记录 (目标可能已被部分写入)
+func (jl *JLinkWrapper) WriteMemory(addr uint32, data []byte) error {
+	old, err := jl.ReadMemory(addr, len(data))
+	if err != nil {
+		return fmt.Errorf("read original contents: %w", err)
+	}
+	jl.writeSeq++
+	jl.writes = append(jl.writes, MemoryWrite{
+		Seq: jl.writeSeq, Time: time.Now(), Addr: addr, Old: old, New: append([]byte(nil), data...),
+	})
+	if len(jl.writes) > maxUndoWrites {
+		jl.writes = append(jl.writes[:0], jl.writes[len(jl.writes)-maxUndoWrites:]...)
+	}
+	return jl.writeMemory(addr, data)
+}
+
+// writeMemory 写入目标内存，不记录
+func (jl *JLinkWrapper) writeMemory(addr uint32, data []byte) error {
+	if jl.apiWriteMem == nil {
+		return errors.New(i18n.T("rtt.api_not_initialized"))
+	}
+	if jl.apiWriteMem(addr, uint32(len(data)), uintptr(unsafe.Pointer(&data[0]))) < 0 {
+		return fmt.Errorf("failed to write memory @ 0x%08X", addr)
+	}
+	return nil
+}
+
+// Writes 返回本次连接以来尚未撤销的内存写入，从早到晚排列
+func (jl *JLinkWrapper) Writes() []MemoryWrite {
+	return append([]MemoryWrite(nil), jl.writes...)
+}
+
+// RevertWrites 从最近一次开始依次写回原始内容 (重叠的写入因此能恢复到最初的值)，返回恢复的条数
+// 某条写回失败时停止，未恢复的记录保留以便重试；写回不会产生新的记录
+// 注意：对写 1 清零等有副作用的寄存器，写回原值不一定能恢复原状态
+func (jl *JLinkWrapper) RevertWrites() (int, error) {
+	n := 0
+	for i := len(jl.writes) - 1; i >= 0; i-- {
+		w := jl.writes[i]
+		if err := jl.writeMemory(w.Addr, w.Old); err != nil {
+			return n, err
+		}
+		jl.writes = jl.writes[:i]
+		n++
+	}
+	return n, nil
+}
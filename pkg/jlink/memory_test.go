@@ -0,0 +1,90 @@
+package jlink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestWriteMemoryRevert(t *testing.T) {
+	f := &fakeTarget{base: 0x20000000, mem: make([]byte, 64)}
+	copy(f.mem, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+	jl := newFakeWrapper(f)
+
+	if err := jl.WriteMemory(0x20000000, []byte{0xAA, 0xBB}); err != nil {
+		t.Fatal(err)
+	}
+	if err := jl.WriteMemory(0x20000001, []byte{0xCC, 0xDD}); err != nil {
+		t.Fatal(err)
+	}
+	if err := jl.WriteMemory(0x30000000, []byte{0}); err == nil {
+		t.Error("Expected error for unmapped address")
+	}
+	if !bytes.Equal(f.mem[:4], []byte{0xAA, 0xCC, 0xDD, 4}) {
+		t.Fatalf("Unexpected memory % x", f.mem[:4])
+	}
+	w := jl.Writes()
+	if len(w) != 2 || w[1].Seq != 2 || !bytes.Equal(w[1].Old, []byte{0xBB, 3}) {
+		t.Fatalf("Unexpected records %+v", w)
+	}
+
+	n, err := jl.RevertWrites()
+	if err != nil || n != 2 {
+		t.Fatalf("Expected 2 reverted, got %d, %v", n, err)
+	}
+	if !bytes.Equal(f.mem[:4], []byte{1, 2, 3, 4}) {
+		t.Errorf("Memory not restored: % x", f.mem[:4])
+	}
+	if len(jl.Writes()) != 0 {
+		t.Error("Reverted records should be removed")
+	}
+}
+
+func TestMemoryRange(t *testing.T) {
+	jl := newFakeWrapper(&fakeTarget{base: 0, mem: make([]byte, 4)})
+	if _, err := jl.ReadMemory(0, 0); err != ErrMemoryRange {
+		t.Errorf("Expected ErrMemoryRange, got %v", err)
+	}
+	if err := jl.WriteMemory(0, make([]byte, MaxMemoryAccess+1)); err == nil {
+		t.Error("Expected error for oversized write")
+	}
+}
+
+func TestReadChipIDChecksCPUID(t *testing.T) {
+	regs := map[uint32]uint32{0x40015800: 0x10006440, 0xE0042000: 0x20036413}
+	var read []uint32
+	jl := &JLinkWrapper{apiReadMem: func(addr, size uint32, buf uintptr) int {
+		read = append(read, addr)
+		v, ok := regs[addr]
+		if !ok {
+			return -1
+		}
+		binary.LittleEndian.PutUint32(bytesAt(buf, size), v)
+		return 0
+	}}
+
+	regs[regCPUID] = 0x410CC601 // Cortex-M0+
+	if id, err := jl.ReadChipID(); err != nil || id != 0x10006440 {
+		t.Errorf("ReadChipID = 0x%X, %v", id, err)
+	}
+	if len(read) != 2 || read[1] != 0x40015800 {
+		t.Errorf("Cortex-M0+ should only read 0x40015800, read %X", read)
+	}
+
+	read = nil
+	regs[regCPUID] = 0x410FC241 // Cortex-M4
+	if id, err := jl.ReadChipID(); err != nil || id != 0x20036413 {
+		t.Errorf("ReadChipID = 0x%X, %v", id, err)
+	}
+
+	for _, cpuid := range []uint32{0x690FC240, 0x410FD210} { // 非 ARM 实现、未列出的内核 (Cortex-M33)
+		read = nil
+		regs[regCPUID] = cpuid
+		if _, err := jl.ReadChipID(); err == nil {
+			t.Errorf("CPUID 0x%08X should be rejected", cpuid)
+		}
+		if len(read) != 1 {
+			t.Errorf("CPUID 0x%08X: DBGMCU should not be read, read %X", cpuid, read)
+		}
+	}
+}
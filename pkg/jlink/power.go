@@ -0,0 +1,44 @@
+package jlink
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"serial-assistant/pkg/i18n"
+)
+
+// hwStatusSize JLINKARM_HW_STATUS 的大小：VTarget (mV, U16) 及 6 个引脚状态 (U8)
+const hwStatusSize = 8
+
+// SetTargetPower 打开或关闭探针 pin 19 的 5V 供电
+// permanent 为 true 时同时写入探针的默认设置，断电重插后仍保持 (相当于 J-Link Commander 的 "power on perm")
+func (jl *JLinkWrapper) SetTargetPower(on, permanent bool) error {
+	if jl.apiExecCommand == nil {
+		return errors.New(i18n.T("rtt.api_not_initialized"))
+	}
+	v := 0
+	if on {
+		v = 1
+	}
+	if jl.apiExecCommand(fmt.Sprintf("SupplyPower = %d", v), 0, 0) < 0 {
+		return fmt.Errorf("failed to set target power")
+	}
+	if permanent && jl.apiExecCommand(fmt.Sprintf("SupplyPowerDefault = %d", v), 0, 0) < 0 {
+		return fmt.Errorf("failed to set default target power")
+	}
+	return nil
+}
+
+// VTref 读取探针测得的目标参考电压 (mV)，0 表示目标未上电或未连接
+func (jl *JLinkWrapper) VTref() (int, error) {
+	if jl.apiGetHWStatus == nil {
+		return 0, errors.New(i18n.T("rtt.api_not_initialized"))
+	}
+	status := scratchBuf(hwStatusSize)
+	if jl.apiGetHWStatus(uintptr(unsafe.Pointer(&status[0]))) != 0 {
+		return 0, fmt.Errorf("failed to read probe hardware status")
+	}
+	return int(binary.LittleEndian.Uint16(status[0:2])), nil
+}
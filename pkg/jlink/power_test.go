@@ -0,0 +1,34 @@
+package jlink
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestSetTargetPower(t *testing.T) {
+	var cmds []string
+	jl := &JLinkWrapper{apiExecCommand: func(cmd string, _, _ int) int { cmds = append(cmds, cmd); return 0 }}
+	jl.SetTargetPower(true, true)
+	jl.SetTargetPower(false, false)
+	want := []string{"SupplyPower = 1", "SupplyPowerDefault = 1", "SupplyPower = 0"}
+	if !reflect.DeepEqual(cmds, want) {
+		t.Errorf("Unexpected commands %q", cmds)
+	}
+
+}
+
+func TestVTref(t *testing.T) {
+	jl := &JLinkWrapper{}
+	jl.apiGetHWStatus = func(buf uintptr) int {
+		binary.LittleEndian.PutUint16(bytesAt(buf, hwStatusSize), 3300)
+		return 0
+	}
+	if mv, err := jl.VTref(); err != nil || mv != 3300 {
+		t.Errorf("VTref = %d, %v", mv, err)
+	}
+	jl.apiGetHWStatus = func(uintptr) int { return -1 }
+	if _, err := jl.VTref(); err == nil {
+		t.Error("Expected error when the status read fails")
+	}
+}
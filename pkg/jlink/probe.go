@@ -0,0 +1,141 @@
+package jlink
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"serial-assistant/pkg/i18n"
+)
+
+// NewProbe 为 t 指定的探针 (USB/隧道按序列号，网络探针按地址) 加载一份独立的驱动实例
+// 驱动的连接状态是进程内全局的，同一份库只能操作一个探针，
+// 因此先把库复制为临时文件再加载，各探针的连接、RTT 读取互不干扰
+func NewProbe(t Target, logCallback LogCallback) (*JLinkWrapper, error) {
+	if t.Mode != ModeIP && t.Serial == 0 {
+		return nil, errors.New(i18n.T("rtt.probe_serial_required"))
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	libPath, err := getLibraryPath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(libPath); err != nil && runtime.GOOS == "linux" {
+		libPath = "/opt/SEGGER/JLink/libjlinkarm.so"
+	}
+
+	copyPath, err := privateCopy(libPath, os.TempDir())
+	if err != nil {
+		return nil, libraryError(libPath, err)
+	}
+	if logCallback != nil {
+		logCallback(i18n.T("rtt.loading_library", copyPath))
+	}
+	lib, err := openLibrary(copyPath)
+	if err != nil {
+		os.Remove(copyPath)
+		return nil, err
+	}
+	jl, err := newWrapper(lib, logCallback)
+	if err != nil {
+		os.Remove(copyPath)
+		return nil, err
+	}
+	if jl.apiSelectUSBSN == nil {
+		jl.Close()
+		os.Remove(copyPath)
+		return nil, errors.New(i18n.T("rtt.missing_core_funcs"))
+	}
+	jl.SetTarget(t)
+	jl.privatePath = copyPath
+	return jl, nil
+}
+
+// Serial 返回探针序列号，由驱动自行选择探针时为 0
+func (jl *JLinkWrapper) Serial() uint32 {
+	return jl.serial
+}
+
+// privateCopy 将 src 复制到 dir 下的临时文件，保留扩展名以便系统按动态库加载
+func privateCopy(src, dir string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", fmt.Errorf("failed to copy library %s: %w", src, err)
+	}
+	defer in.Close()
+
+	ext := filepath.Ext(src)
+	out, err := os.CreateTemp(dir, "jlink-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(out.Name())
+		return "", err
+	}
+	return out.Name(), nil
+}
+
+// Tagger 为某个探针的 RTT 输出按行加上标签，多个探针的输出合并显示时不会在行中间交错
+// 不完整的行留到下一次 Feed 再输出
+type Tagger struct {
+	prefix  []byte
+	pending []byte
+}
+
+// NewTagger 创建以 "[label] " 为前缀的标签器
+func NewTagger(label string) *Tagger {
+	return &Tagger{prefix: []byte("[" + label + "] ")}
+}
+
+// Feed 追加数据，返回其中已完整的行 (带前缀)；没有完整的行时返回 nil
+func (t *Tagger) Feed(data []byte) []byte {
+	t.pending = append(t.pending, data...)
+	end := bytes.LastIndexByte(t.pending, '\n')
+	if end < 0 {
+		// 防止不换行的输出无限积累
+		if len(t.pending) < maxRTTReadSize {
+			return nil
+		}
+		end = len(t.pending) - 1
+	}
+	out := t.tag(t.pending[:end+1])
+	t.pending = append(t.pending[:0], t.pending[end+1:]...)
+	return out
+}
+
+// Flush 返回剩余的不完整行 (补上换行)
+func (t *Tagger) Flush() []byte {
+	if len(t.pending) == 0 {
+		return nil
+	}
+	out := t.tag(append(t.pending, '\n'))
+	t.pending = t.pending[:0]
+	return out
+}
+
+// tag 为 lines 中的每一行加上前缀，lines 以换行结尾
+func (t *Tagger) tag(lines []byte) []byte {
+	out := make([]byte, 0, len(lines)+len(t.prefix)*(bytes.Count(lines, []byte{'\n'})+1))
+	for len(lines) > 0 {
+		i := bytes.IndexByte(lines, '\n')
+		if i < 0 {
+			i = len(lines) - 1
+		}
+		out = append(out, t.prefix...)
+		out = append(out, lines[:i+1]...)
+		lines = lines[i+1:]
+	}
+	return out
+}
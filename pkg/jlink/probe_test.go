@@ -0,0 +1,60 @@
+package jlink
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPrivateCopy(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "libjlinkarm.so")
+	os.WriteFile(src, []byte("library"), 0o644)
+
+	a, err := privateCopy(src, dir)
+	if err != nil {
+		t.Fatalf("privateCopy failed: %v", err)
+	}
+	b, _ := privateCopy(src, dir)
+	if a == b || filepath.Ext(a) != ".so" {
+		t.Errorf("Expected distinct copies with the library extension, got %s and %s", a, b)
+	}
+	if data, _ := os.ReadFile(a); string(data) != "library" {
+		t.Errorf("Copy content mismatch: %q", data)
+	}
+	if _, err := privateCopy(filepath.Join(dir, "missing.so"), dir); err == nil {
+		t.Error("Expected error for missing library")
+	}
+}
+
+func TestNewProbeRequiresSerial(t *testing.T) {
+	if _, err := NewProbe(Target{Mode: ModeUSB}, nil); err == nil {
+		t.Error("Expected error without a probe serial number")
+	}
+	if _, err := NewProbe(Target{Mode: ModeIP}, nil); err == nil {
+		t.Error("Expected error without a probe host")
+	}
+}
+
+func TestTaggerKeepsLinesWhole(t *testing.T) {
+	tg := NewTagger("mcu2")
+	if out := tg.Feed([]byte("boot")); out != nil {
+		t.Errorf("Partial line should be held back, got %q", out)
+	}
+	out := tg.Feed([]byte(" ok\nready\nx"))
+	if string(out) != "[mcu2] boot ok\n[mcu2] ready\n" {
+		t.Errorf("Unexpected tagged output %q", out)
+	}
+	if out := tg.Flush(); string(out) != "[mcu2] x\n" {
+		t.Errorf("Unexpected flush %q", out)
+	}
+	if tg.Flush() != nil {
+		t.Error("Second flush should be empty")
+	}
+
+	long := strings.Repeat("a", maxRTTReadSize)
+	if out := tg.Feed([]byte(long)); len(out) != len(long)+len("[mcu2] ") {
+		t.Errorf("Unterminated output should be released at the limit, got %d bytes", len(out))
+	}
+}
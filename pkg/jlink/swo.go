@@ -0,0 +1,167 @@
+package jlink
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"serial-assistant/pkg/i18n"
+)
+
+// Cortex-M 调试与跟踪寄存器
+const (
+	regDEMCR   = 0xE000EDFC // bit24 TRCENA：使能 DWT/ITM
+	regDWTCtrl = 0xE0001000
+	regITMTCR  = 0xE0000E80
+	regITMLAR  = 0xE0000FB0
+
+	demcrTRCENA = 1 << 24
+
+	dwtCYCCNTENA  = 1 << 0
+	dwtPOSTPRESET = 0xF << 1
+	dwtCYCTAP     = 1 << 9 // 0：每 64 个周期计数一次 POSTCNT，1：每 1024 个周期
+	dwtPCSAMPLENA = 1 << 12
+
+	itmENA     = 1 << 0
+	itmSYNCENA = 1 << 2
+	itmDWTENA  = 1 << 3
+	itmBusID   = 1 << 16
+
+	itmUnlock = 0xC5ACCE55
+
+	// JLINKARM_SWO_CMD_*
+	swoCmdStop        = 1
+	swoCmdFlush       = 2
+	swoCmdGetNumBytes = 10
+
+	swoModeUART = 0
+	swoPortMask = 1
+)
+
+// pcSampleDivider 选择最接近 interval 个 CPU 周期的采样间隔
+// 采样间隔为 (POSTPRESET+1) × (CYCTAP ? 1024 : 64)，POSTPRESET 取值 0..15
+func pcSampleDivider(interval uint32) (tap1024 bool, preset uint32, actual uint32) {
+	best := uint32(0)
+	for _, tap := range []uint32{64, 1024} {
+		for p := uint32(0); p < 16; p++ {
+			v := (p + 1) * tap
+			if best == 0 || absDiff(v, interval) < absDiff(best, interval) {
+				best, tap1024, preset = v, tap == 1024, p
+			}
+		}
+	}
+	return tap1024, preset, best
+}
+
+func absDiff(a, b uint32) uint32 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// readU32 读取一个目标寄存器
+func (jl *JLinkWrapper) readU32(addr uint32) (uint32, error) {
+	buf := scratchBuf(4)
+	if jl.apiReadMem(addr, 4, uintptr(unsafe.Pointer(&buf[0]))) < 0 {
+		return 0, fmt.Errorf("failed to read 0x%08X", addr)
+	}
+	return binary.LittleEndian.Uint32(buf), nil
+}
+
+// writeU32 写入一个目标寄存器，与 WriteMemory 一样记入撤销记录，RevertWrites 可恢复调试单元的配置
+func (jl *JLinkWrapper) writeU32(addr, value uint32) error {
+	buf := scratchBuf(4)
+	binary.LittleEndian.PutUint32(buf, value)
+	return jl.WriteMemory(addr, buf)
+}
+
+// StartPCSampling 配置 SWO 输出并开启 DWT 周期性 PC 采样
+// cpuHz 为内核时钟，swoHz 为 SWO 波特率 (0 由探针自动选择)，interval 为期望的采样间隔 (CPU 周期)；
+// 返回实际使用的采样间隔
+func (jl *JLinkWrapper) StartPCSampling(cpuHz, swoHz, interval uint32) (uint32, error) {
+	if jl.apiSWOEnableTarget == nil || jl.apiSWORead == nil || jl.apiSWOControl == nil || jl.apiWriteMem == nil {
+		return 0, errors.New(i18n.T("rtt.swo_unsupported"))
+	}
+	if cpuHz == 0 {
+		return 0, fmt.Errorf("CPU clock is required for SWO")
+	}
+
+	demcr, err := jl.readU32(regDEMCR)
+	if err != nil {
+		return 0, err
+	}
+	if err := jl.writeU32(regDEMCR, demcr|demcrTRCENA); err != nil {
+		return 0, err
+	}
+	// 由驱动配置 TPIU 的 SWO 分频与 NRZ 模式，并使能 ITM 端口
+	if r := jl.apiSWOEnableTarget(cpuHz, swoHz, swoModeUART, swoPortMask); r < 0 {
+		return 0, fmt.Errorf("failed to enable SWO on target (%d)", r)
+	}
+	if err := jl.writeU32(regITMLAR, itmUnlock); err != nil {
+		return 0, err
+	}
+	if err := jl.writeU32(regITMTCR, itmENA|itmSYNCENA|itmDWTENA|itmBusID); err != nil {
+		return 0, err
+	}
+
+	tap1024, preset, actual := pcSampleDivider(interval)
+	ctrl, err := jl.readU32(regDWTCtrl)
+	if err != nil {
+		return 0, err
+	}
+	ctrl &^= dwtPOSTPRESET | dwtCYCTAP | dwtPCSAMPLENA
+	ctrl |= dwtCYCCNTENA | preset<<1 | dwtPCSAMPLENA
+	if tap1024 {
+		ctrl |= dwtCYCTAP
+	}
+	if err := jl.writeU32(regDWTCtrl, ctrl); err != nil {
+		return 0, err
+	}
+	return actual, nil
+}
+
+// ReadSWO 取出探针已缓存的 SWO 数据，没有数据时返回空切片
+func (jl *JLinkWrapper) ReadSWO() ([]byte, error) {
+	if jl.apiSWORead == nil || jl.apiSWOControl == nil {
+		return nil, errors.New(i18n.T("rtt.swo_unsupported"))
+	}
+	avail := jl.apiSWOControl(swoCmdGetNumBytes, 0)
+	if avail < 0 {
+		return nil, fmt.Errorf("failed to query SWO buffer")
+	}
+	if avail == 0 {
+		return nil, nil
+	}
+	if jl.swoBuffer == nil {
+		jl.swoBuffer = make([]byte, 16*1024)
+	}
+	n := min(avail, len(jl.swoBuffer))
+	count := scratchBuf(4)
+	binary.LittleEndian.PutUint32(count, uint32(n))
+	jl.apiSWORead(uintptr(unsafe.Pointer(&jl.swoBuffer[0])), 0, uintptr(unsafe.Pointer(&count[0])))
+	n = int(binary.LittleEndian.Uint32(count))
+	out := append([]byte(nil), jl.swoBuffer[:n]...)
+	// 从探针缓冲区中移除已读取的数据
+	jl.apiSWOControl(swoCmdFlush, uintptr(unsafe.Pointer(&count[0])))
+	return out, nil
+}
+
+// StopPCSampling 关闭 PC 采样与 SWO 输出
+func (jl *JLinkWrapper) StopPCSampling() error {
+	if jl.apiSWOControl == nil {
+		return errors.New(i18n.T("rtt.swo_unsupported"))
+	}
+	var firstErr error
+	if ctrl, err := jl.readU32(regDWTCtrl); err != nil {
+		firstErr = err
+	} else if err := jl.writeU32(regDWTCtrl, ctrl&^dwtPCSAMPLENA); err != nil {
+		firstErr = err
+	}
+	if jl.apiSWODisableTarget != nil {
+		jl.apiSWODisableTarget(swoPortMask)
+	}
+	jl.apiSWOControl(swoCmdStop, 0)
+	return firstErr
+}
@@ -0,0 +1,66 @@
+package jlink
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestPCSampleDivider(t *testing.T) {
+	cases := []struct {
+		interval uint32
+		tap1024  bool
+		preset   uint32
+		actual   uint32
+	}{
+		{0, false, 0, 64},
+		{64, false, 0, 64},
+		{1000, false, 15, 1024},
+		{4096, true, 3, 4096},
+		{100000, true, 15, 16384},
+	}
+	for _, c := range cases {
+		tap, preset, actual := pcSampleDivider(c.interval)
+		if tap != c.tap1024 || preset != c.preset || actual != c.actual {
+			t.Errorf("pcSampleDivider(%d) = %v, %d, %d", c.interval, tap, preset, actual)
+		}
+	}
+}
+
+func TestStartPCSamplingConfiguresDWT(t *testing.T) {
+	f := &fakeTarget{base: 0xE0000000, mem: make([]byte, 0xF000)}
+	f.u32(regDWTCtrl, 0x40000000) // NUMCOMP 等只读位应保留
+	jl := newFakeWrapper(f)
+	var swoCPU uint32
+	jl.apiSWOEnableTarget = func(cpu, swo uint32, mode int, mask uint32) int { swoCPU = cpu; return 0 }
+	jl.apiSWOControl = func(uint32, uintptr) int { return 0 }
+	jl.apiSWORead = func(uintptr, uint32, uintptr) {}
+
+	actual, err := jl.StartPCSampling(72000000, 0, 4096)
+	if err != nil || actual != 4096 || swoCPU != 72000000 {
+		t.Fatalf("StartPCSampling = %d, %v (cpu %d)", actual, err, swoCPU)
+	}
+	reg := func(addr uint32) uint32 { return binary.LittleEndian.Uint32(f.mem[addr-f.base:]) }
+	if reg(regDEMCR)&demcrTRCENA == 0 {
+		t.Error("TRCENA not set")
+	}
+	if want := uint32(itmENA | itmSYNCENA | itmDWTENA | itmBusID); reg(regITMTCR) != want {
+		t.Errorf("ITM_TCR = 0x%X, want 0x%X", reg(regITMTCR), want)
+	}
+	if want := uint32(0x40000000 | dwtCYCCNTENA | 3<<1 | dwtCYCTAP | dwtPCSAMPLENA); reg(regDWTCtrl) != want {
+		t.Errorf("DWT_CTRL = 0x%X, want 0x%X", reg(regDWTCtrl), want)
+	}
+
+	if err := jl.StopPCSampling(); err != nil || reg(regDWTCtrl)&dwtPCSAMPLENA != 0 {
+		t.Errorf("StopPCSampling left DWT_CTRL = 0x%X, %v", reg(regDWTCtrl), err)
+	}
+
+	if n := len(jl.Writes()); n != 5 {
+		t.Fatalf("Register writes should be recorded, got %d", n)
+	}
+	if n, err := jl.RevertWrites(); err != nil || n != 5 {
+		t.Fatalf("RevertWrites = %d, %v", n, err)
+	}
+	if reg(regDEMCR) != 0 || reg(regITMTCR) != 0 || reg(regDWTCtrl) != 0x40000000 {
+		t.Errorf("Revert left DEMCR 0x%X, ITM_TCR 0x%X, DWT_CTRL 0x%X", reg(regDEMCR), reg(regITMTCR), reg(regDWTCtrl))
+	}
+}
@@ -0,0 +1,128 @@
+package jlink
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"serial-assistant/pkg/i18n"
+)
+
+// 探针的连接方式
+const (
+	ModeUSB    = "usb"    // 本机 USB 探针 (Serial 为 0 时由驱动自行选择)
+	ModeTunnel = "tunnel" // 经 SEGGER 远程服务器的 IP 隧道连接序列号为 Serial 的探针
+	ModeIP     = "ip"     // 按地址直连 J-Link PRO/WiFi 等网络探针或局域网内的 J-Link Remote Server
+)
+
+// defaultIPPort J-Link 网络探针与远程服务器的默认端口
+const defaultIPPort = 19020
+
+// Target 探针选择 (USB、远程服务器隧道或网络地址)
+// 其他程序 (Ozone、GDB Server) 占用探针时，可在占用方运行 J-Link Remote Server 并以隧道方式共用
+type Target struct {
+	Mode   string `json:"mode"`
+	Serial uint32 `json:"serial"`
+	Host   string `json:"host,omitempty"` // ModeIP 的主机名或 IP 地址
+	Port   int    `json:"port,omitempty"` // ModeIP 的端口，0 表示默认的 19020
+}
+
+// Validate 检查探针选择是否完整
+func (t Target) Validate() error {
+	switch t.Mode {
+	case "", ModeUSB:
+		return nil
+	case ModeTunnel:
+		if t.Serial == 0 {
+			return errors.New(i18n.T("rtt.probe_serial_required"))
+		}
+		return nil
+	case ModeIP:
+		if strings.TrimSpace(t.Host) == "" {
+			return errors.New("probe host is required")
+		}
+		if t.Port < 0 || t.Port > 65535 {
+			return fmt.Errorf("invalid probe port: %d", t.Port)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown probe mode: %s", t.Mode)
+}
+
+// ErrProbeInUse 探针已被其他程序占用
+var ErrProbeInUse = errors.New("J-Link probe is in use by another application")
+
+// ErrLibraryNotFound 找不到 J-Link 驱动库 (未安装 J-Link Software Pack)
+var ErrLibraryNotFound = errors.New("J-Link library not found")
+
+// SetTarget 设置探针选择，须在 Connect 之前调用
+func (jl *JLinkWrapper) SetTarget(t Target) {
+	jl.target = t
+	if t.Serial != 0 {
+		jl.serial = t.Serial
+	}
+}
+
+// selectTarget 在打开探针前按设置选择探针
+func (jl *JLinkWrapper) selectTarget() error {
+	switch jl.target.Mode {
+	case ModeIP:
+		if jl.apiSelectIP == nil {
+			return errors.New(i18n.T("rtt.missing_core_funcs"))
+		}
+		port := jl.target.Port
+		if port == 0 {
+			port = defaultIPPort
+		}
+		host := strings.TrimSpace(jl.target.Host)
+		if jl.apiSelectIP(host, port) != 0 {
+			return errors.New(i18n.T("rtt.ip_failed", host, port))
+		}
+		return nil
+	case ModeTunnel:
+		if jl.apiSelectIP == nil {
+			return errors.New(i18n.T("rtt.missing_core_funcs"))
+		}
+		if jl.apiSelectIP("tunnel:"+strconv.FormatUint(uint64(jl.serial), 10), 0) != 0 {
+			return errors.New(i18n.T("rtt.tunnel_failed", jl.serial))
+		}
+		return nil
+	}
+	if jl.serial != 0 && jl.apiSelectUSBSN != nil {
+		if jl.apiSelectUSBSN(jl.serial) < 0 {
+			return errors.New(i18n.T("rtt.probe_not_found", jl.serial))
+		}
+	}
+	return nil
+}
+
+// inUseHints 驱动在探针被占用时给出的错误信息片段 (小写)
+var inUseHints = []string{
+	"in use",
+	"already open",
+	"used by another",
+	"another application",
+	"could not claim",
+	"resource busy",
+}
+
+// openError 将驱动 Open 返回的错误信息转换为错误，探针被占用时包装 ErrProbeInUse
+func openError(msg string) error {
+	lower := strings.ToLower(msg)
+	for _, hint := range inUseHints {
+		if strings.Contains(lower, hint) {
+			return fmt.Errorf("%w: %s", ErrProbeInUse, msg)
+		}
+	}
+	return errors.New(i18n.T("rtt.open_failed", msg))
+}
+
+// libraryError 加载驱动库失败时区分未安装与其他原因 (架构不符、依赖缺失等)
+func libraryError(path string, err error) error {
+	if _, statErr := os.Stat(path); errors.Is(statErr, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrLibraryNotFound, path)
+	}
+	return err
+}
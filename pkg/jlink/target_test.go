@@ -0,0 +1,66 @@
+package jlink
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenErrorDetectsProbeInUse(t *testing.T) {
+	for _, msg := range []string{
+		"J-Link is already open by another application",
+		"Could not claim USB interface",
+		"The J-Link is in use by Ozone",
+	} {
+		if err := openError(msg); !errors.Is(err, ErrProbeInUse) {
+			t.Errorf("openError(%q) = %v, want ErrProbeInUse", msg, err)
+		}
+	}
+	if err := openError("Cannot connect to J-Link via USB."); errors.Is(err, ErrProbeInUse) {
+		t.Errorf("Unexpected ErrProbeInUse for %v", err)
+	}
+}
+
+func TestLibraryError(t *testing.T) {
+	cause := errors.New("dlopen failed")
+	if err := libraryError(filepath.Join(t.TempDir(), "libjlinkarm.so"), cause); !errors.Is(err, ErrLibraryNotFound) {
+		t.Errorf("Missing library should be ErrLibraryNotFound, got %v", err)
+	}
+	dir := t.TempDir()
+	if err := libraryError(dir, cause); err != cause {
+		t.Errorf("Existing library should keep the original error, got %v", err)
+	}
+}
+
+func TestSelectTarget(t *testing.T) {
+	var host string
+	var usb uint32
+	jl := &JLinkWrapper{
+		apiSelectIP:    func(h string, port int) int { host = h; return 0 },
+		apiSelectUSBSN: func(sn uint32) int { usb = sn; return 0 },
+	}
+	jl.SetTarget(Target{Mode: ModeTunnel, Serial: 600123456})
+	if err := jl.selectTarget(); err != nil || host != "tunnel:600123456" || usb != 0 {
+		t.Errorf("Tunnel selection: host=%q usb=%d err=%v", host, usb, err)
+	}
+
+	jl = &JLinkWrapper{apiSelectUSBSN: func(sn uint32) int { usb = sn; return -1 }}
+	jl.SetTarget(Target{Mode: ModeUSB, Serial: 42})
+	if err := jl.selectTarget(); err == nil || usb != 42 {
+		t.Errorf("USB selection should fail for a missing probe, usb=%d err=%v", usb, err)
+	}
+
+	var port int
+	jl = &JLinkWrapper{apiSelectIP: func(h string, p int) int { host, port = h, p; return 0 }}
+	jl.SetTarget(Target{Mode: ModeIP, Host: " 192.168.1.50 "})
+	if err := jl.selectTarget(); err != nil || host != "192.168.1.50" || port != 19020 {
+		t.Errorf("IP selection: host=%q port=%d err=%v", host, port, err)
+	}
+
+	if (Target{Mode: ModeIP}).Validate() == nil || (Target{Mode: ModeIP, Host: "x", Port: 70000}).Validate() == nil {
+		t.Error("IP targets need a host and a valid port")
+	}
+	if (Target{Mode: ModeTunnel}).Validate() == nil || (Target{Mode: "bogus"}).Validate() == nil {
+		t.Error("Invalid targets should not validate")
+	}
+}
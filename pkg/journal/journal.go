@@ -0,0 +1,143 @@
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"serial-assistant/pkg/ports"
+)
+
+// Connection 可用于恢复的连接参数
+type Connection struct {
+	Type        string             `json:"type"`
+	Port        string             `json:"port,omitempty"` // 串口名，或网络连接的端口
+	BaudRate    int                `json:"baudRate,omitempty"`
+	DataBits    int                `json:"dataBits,omitempty"`
+	StopBits    int                `json:"stopBits,omitempty"`
+	Parity      string             `json:"parity,omitempty"`
+	FlowControl string             `json:"flowControl,omitempty"` // 串口流控：none、rtscts
+	RS485       *ports.RS485Config `json:"rs485,omitempty"`
+	Host        string             `json:"host,omitempty"`
+	LocalPort   string             `json:"localPort,omitempty"`
+	Chip        string             `json:"chip,omitempty"`
+	Speed       int                `json:"speed,omitempty"`
+	Interface   string             `json:"interface,omitempty"`
+}
+
+// AutoSend 自动发送配置
+type AutoSend struct {
+	Enabled    bool   `json:"enabled"`
+	Payload    string `json:"payload"`
+	Hex        bool   `json:"hex"`
+	IntervalMs int    `json:"intervalMs"`
+}
+
+// Session 由前端维护的会话状态：日志、自动发送与尚未发出的数据
+type Session struct {
+	Logging  bool      `json:"logging"`
+	LogPath  string    `json:"logPath,omitempty"`
+	AutoSend *AutoSend `json:"autoSend,omitempty"`
+	Pending  []string  `json:"pending,omitempty"`
+}
+
+// State 日志文件的内容
+type State struct {
+	Started    time.Time   `json:"started"`
+	Updated    time.Time   `json:"updated"`
+	Clean      bool        `json:"clean"` // 正常退出时置为 true
+	Connection *Connection `json:"connection,omitempty"`
+	Session    Session     `json:"session"`
+}
+
+// Empty 状态中没有任何值得恢复的内容
+func (s *State) Empty() bool {
+	return s.Connection == nil && !s.Session.Logging &&
+		(s.Session.AutoSend == nil || !s.Session.AutoSend.Enabled) && len(s.Session.Pending) == 0
+}
+
+// Journal 持久化到磁盘的会话状态日志，每次更新都以原子替换的方式写入
+type Journal struct {
+	path  string
+	mu    sync.Mutex
+	state State
+}
+
+// Open 打开 path 处的日志并开始新的记录
+// 若上次运行未正常退出且留有可恢复的状态，则一并返回该状态，否则返回 nil
+func Open(path string) (*Journal, *State, error) {
+	var previous *State
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		var s State
+		if json.Unmarshal(data, &s) == nil && !s.Clean && !s.Empty() {
+			previous = &s
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	j := &Journal{path: path, state: State{Started: now, Updated: now}}
+	if err := j.writeLocked(); err != nil {
+		return nil, nil, err
+	}
+	return j, previous, nil
+}
+
+// Update 修改并立即持久化当前状态
+func (j *Journal) Update(fn func(*State)) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	fn(&j.state)
+	j.state.Updated = time.Now()
+	return j.writeLocked()
+}
+
+// State 返回当前状态的副本
+func (j *Journal) State() State {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	s := j.state
+	s.Session.Pending = append([]string(nil), s.Session.Pending...)
+	return s
+}
+
+// Close 标记正常退出，下次 Open 时不再提示恢复
+func (j *Journal) Close() error {
+	return j.Update(func(s *State) {
+		s.Clean = true
+	})
+}
+
+// writeLocked 先写临时文件再重命名，保证崩溃时磁盘上总是完整的旧版本或新版本
+func (j *Journal) writeLocked() error {
+	data, err := json.MarshalIndent(j.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), filepath.Base(j.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), j.path)
+}
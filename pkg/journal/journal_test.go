@@ -0,0 +1,89 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenFreshHasNoPrevious(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "session.json")
+	j, prev, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if prev != nil {
+		t.Errorf("Expected no previous state, got %+v", prev)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Journal file should exist: %v", err)
+	}
+	j.Close()
+}
+
+func TestCrashLeavesRecoverableState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	j, _, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	err = j.Update(func(s *State) {
+		s.Connection = &Connection{Type: "SERIAL", Port: "COM3", BaudRate: 115200}
+		s.Session.AutoSend = &AutoSend{Enabled: true, Payload: "AT", IntervalMs: 1000}
+		s.Session.Pending = []string{"hello"}
+	})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// 不调用 Close，模拟崩溃
+	_, prev, err := Open(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	if prev == nil || prev.Connection == nil {
+		t.Fatal("Expected recoverable state after crash")
+	}
+	if prev.Connection.Port != "COM3" || prev.Session.AutoSend.Payload != "AT" || len(prev.Session.Pending) != 1 {
+		t.Errorf("Unexpected recovered state: %+v", prev)
+	}
+
+	// 新的记录已经覆盖了旧状态，再次打开不应重复提示
+	_, prev, _ = Open(path)
+	if prev != nil {
+		t.Errorf("Recovered state should only be offered once, got %+v", prev)
+	}
+}
+
+func TestCleanShutdownIsNotRecoverable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	j, _, _ := Open(path)
+	j.Update(func(s *State) {
+		s.Connection = &Connection{Type: "TCP_CLIENT", Host: "127.0.0.1", Port: "8080"}
+	})
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	_, prev, _ := Open(path)
+	if prev != nil {
+		t.Errorf("Expected no previous state after clean shutdown, got %+v", prev)
+	}
+}
+
+func TestEmptyStateIsNotRecoverable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	Open(path)
+	_, prev, _ := Open(path)
+	if prev != nil {
+		t.Errorf("Empty state should not be offered, got %+v", prev)
+	}
+}
+
+func TestCorruptFileIsIgnored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	os.WriteFile(path, []byte("{not json"), 0o644)
+	_, prev, err := Open(path)
+	if err != nil || prev != nil {
+		t.Errorf("Corrupt journal should be ignored, got prev=%v err=%v", prev, err)
+	}
+}
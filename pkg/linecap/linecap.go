@@ -0,0 +1,145 @@
+package linecap
+
+import (
+	"sync"
+	"time"
+)
+
+// 调制解调器状态线名称
+const (
+	CTS = "CTS"
+	DSR = "DSR"
+	DCD = "DCD"
+	RI  = "RI"
+)
+
+// Names 按显示顺序排列的状态线
+var Names = []string{CTS, DSR, DCD, RI}
+
+// Levels 一次采样得到的各状态线电平
+type Levels struct {
+	CTS bool `json:"cts"`
+	DSR bool `json:"dsr"`
+	DCD bool `json:"dcd"`
+	RI  bool `json:"ri"`
+}
+
+// Get 按名称返回电平
+func (l Levels) Get(name string) bool {
+	switch name {
+	case CTS:
+		return l.CTS
+	case DSR:
+		return l.DSR
+	case DCD:
+		return l.DCD
+	case RI:
+		return l.RI
+	}
+	return false
+}
+
+// Changed 返回 cur 相对 prev 电平变化的状态线名称，按 Names 的顺序
+func Changed(prev, cur Levels) []string {
+	var out []string
+	for _, name := range Names {
+		if prev.Get(name) != cur.Get(name) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// Edge 一次电平跳变；Initial 为开始采样时的初始电平
+type Edge struct {
+	Time    time.Time `json:"time"`
+	Line    string    `json:"line"`
+	Level   bool      `json:"level"`
+	Initial bool      `json:"initial,omitempty"`
+}
+
+// DefaultCapacity 保留的跳变数
+const DefaultCapacity = 10000
+
+// Recorder 只记录电平跳变，采样间隔可以很小而不占用大量内存
+type Recorder struct {
+	mu       sync.Mutex
+	capacity int
+	edges    []Edge
+	last     Levels
+	started  bool
+	samples  uint64
+}
+
+// New 创建记录器，capacity <= 0 时使用 DefaultCapacity
+func New(capacity int) *Recorder {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Recorder{capacity: capacity}
+}
+
+// Sample 记录一次采样，返回相对上次采样的跳变；首次采样返回全部线的初始电平
+func (r *Recorder) Sample(t time.Time, l Levels) []Edge {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples++
+	var out []Edge
+	for _, name := range Names {
+		level := l.Get(name)
+		if !r.started {
+			out = append(out, Edge{Time: t, Line: name, Level: level, Initial: true})
+		} else if level != r.last.Get(name) {
+			out = append(out, Edge{Time: t, Line: name, Level: level})
+		}
+	}
+	r.started = true
+	r.last = l
+	r.edges = append(r.edges, out...)
+	if over := len(r.edges) - r.capacity; over > 0 {
+		r.edges = append(r.edges[:0], r.edges[over:]...)
+	}
+	return out
+}
+
+// Edges 返回晚于 since 的跳变 (since 为零值时返回全部)
+func (r *Recorder) Edges(since time.Time) []Edge {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Edge
+	for _, e := range r.edges {
+		if since.IsZero() || e.Time.After(since) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Status 最近一次采样的电平与采样次数
+func (r *Recorder) Status() (Levels, uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last, r.samples
+}
+
+// Reset 清空记录，下一次采样重新作为初始电平
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.edges = nil
+	r.last = Levels{}
+	r.started = false
+	r.samples = 0
+}
+
+// Values 跳变时刻在波形上依次绘制的值 (0/1)：先给出旧电平再给出新电平，按折线绘制即为数字波形
+func (e Edge) Values() []float64 {
+	level := 0.0
+	if e.Level {
+		level = 1
+	}
+	if e.Initial {
+		return []float64{level}
+	}
+	return []float64{1 - level, level}
+}
@@ -0,0 +1,62 @@
+package linecap
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecorderKeepsTransitions(t *testing.T) {
+	r := New(3)
+	t0 := time.Unix(100, 0)
+	first := r.Sample(t0, Levels{CTS: true})
+	if len(first) != 4 || !first[0].Initial || !first[0].Level || first[2].Level {
+		t.Fatalf("Unexpected initial edges %+v", first)
+	}
+	if e := r.Sample(t0.Add(time.Millisecond), Levels{CTS: true}); len(e) != 0 {
+		t.Errorf("Unchanged levels should not produce edges, got %+v", e)
+	}
+	e := r.Sample(t0.Add(2*time.Millisecond), Levels{DCD: true})
+	if len(e) != 2 || e[0].Line != CTS || e[0].Level || e[1].Line != DCD || !e[1].Level {
+		t.Errorf("Unexpected edges %+v", e)
+	}
+
+	// 容量为 3，只保留最近的跳变
+	all := r.Edges(time.Time{})
+	if len(all) != 3 || all[0].Line != RI || all[1].Line != CTS {
+		t.Errorf("Unexpected retained edges %+v", all)
+	}
+	if recent := r.Edges(t0); len(recent) != 2 {
+		t.Errorf("Expected 2 edges after t0, got %+v", recent)
+	}
+	if l, n := r.Status(); !l.DCD || n != 3 {
+		t.Errorf("Status = %+v, %d", l, n)
+	}
+
+	r.Reset()
+	if e := r.Sample(t0, Levels{}); len(e) != 4 || !e[0].Initial {
+		t.Error("Reset should make the next sample initial")
+	}
+}
+
+func TestEdgeValues(t *testing.T) {
+	if v := (Edge{Level: true}).Values(); !reflect.DeepEqual(v, []float64{0, 1}) {
+		t.Errorf("Rising edge = %v", v)
+	}
+	if v := (Edge{Level: false}).Values(); !reflect.DeepEqual(v, []float64{1, 0}) {
+		t.Errorf("Falling edge = %v", v)
+	}
+	if v := (Edge{Level: true, Initial: true}).Values(); !reflect.DeepEqual(v, []float64{1}) {
+		t.Errorf("Initial level = %v", v)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	got := Changed(Levels{CTS: true, RI: true}, Levels{CTS: true, DSR: true, DCD: true})
+	if want := []string{DSR, DCD, RI}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Changed = %v, expected %v", got, want)
+	}
+	if got := Changed(Levels{DSR: true}, Levels{DSR: true}); len(got) != 0 {
+		t.Errorf("Unchanged levels should give nothing, got %v", got)
+	}
+}
@@ -0,0 +1,135 @@
+package loopback
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"time"
+)
+
+// 测试图案
+const (
+	PatternCounter = "counter" // 0x00-0xFF 循环递增，便于看出丢字节与错位
+	PatternRandom  = "random"  // 伪随机字节，覆盖各种位组合
+	PatternASCII   = "ascii"   // 可打印字符 0x20-0x7E 循环，适合只转发文本的转换器
+)
+
+// 默认值与限制
+const (
+	DefaultLength  = 256
+	MaxLength      = 4096 // 不超过接收尾部缓存，否则回环数据无法完整取回
+	DefaultTimeout = 2 * time.Second
+	MaxTimeout     = time.Minute
+)
+
+// Config 回环测试参数
+type Config struct {
+	Pattern   string `json:"pattern"`             // counter (默认)、random、ascii
+	Length    int    `json:"length,omitempty"`    // 0 表示 DefaultLength
+	TimeoutMs int    `json:"timeoutMs,omitempty"` // 0 表示 DefaultTimeout
+	Seed      int64  `json:"seed,omitempty"`      // random 图案的种子，0 表示按当前时间
+}
+
+// ErrPattern 未知的测试图案
+var ErrPattern = errors.New("loopback: unknown pattern")
+
+// Validate 检查参数是否有效
+func (c Config) Validate() error {
+	switch c.Pattern {
+	case "", PatternCounter, PatternRandom, PatternASCII:
+	default:
+		return fmt.Errorf("%w: %s", ErrPattern, c.Pattern)
+	}
+	if c.Length < 0 || c.Length > MaxLength {
+		return fmt.Errorf("loopback: length out of range 1-%d", MaxLength)
+	}
+	if c.TimeoutMs < 0 || time.Duration(c.TimeoutMs)*time.Millisecond > MaxTimeout {
+		return fmt.Errorf("loopback: timeout out of range")
+	}
+	return nil
+}
+
+// Timeout 返回等待回环数据的时间
+func (c Config) Timeout() time.Duration {
+	if c.TimeoutMs > 0 {
+		return time.Duration(c.TimeoutMs) * time.Millisecond
+	}
+	return DefaultTimeout
+}
+
+// Data 按配置生成测试数据
+func (c Config) Data() []byte {
+	n := c.Length
+	if n == 0 {
+		n = DefaultLength
+	}
+	b := make([]byte, n)
+	switch c.Pattern {
+	case PatternRandom:
+		seed := c.Seed
+		if seed == 0 {
+			seed = time.Now().UnixNano()
+		}
+		rand.New(rand.NewSource(seed)).Read(b)
+	case PatternASCII:
+		for i := range b {
+			b[i] = byte(0x20 + i%95)
+		}
+	default:
+		for i := range b {
+			b[i] = byte(i)
+		}
+	}
+	return b
+}
+
+// Report 回环测试结果
+type Report struct {
+	Passed      bool  `json:"passed"`
+	Sent        int   `json:"sent"`
+	Received    int   `json:"received"`
+	ByteErrors  int   `json:"byteErrors"` // 内容不一致的字节数 (按位置比较)
+	BitErrors   int   `json:"bitErrors"`  // 内容不一致的位数
+	Missing     int   `json:"missing"`    // 超时仍未收到的字节数
+	Extra       int   `json:"extra"`      // 多收到的字节数
+	FirstError  int   `json:"firstError"` // 第一个错误的偏移，-1 表示没有错误
+	ElapsedUs   int64 `json:"elapsedUs"`  // 从开始发送到收齐 (或超时) 的时间
+	TimedOut    bool  `json:"timedOut"`
+	BytesPerSec int64 `json:"bytesPerSec"` // 按收到的字节数折算的回环速率
+}
+
+// Compare 逐字节比较发送与收到的数据
+// 丢失字节会使后续数据整体错位，此时 FirstError 指出开始错位的位置
+func Compare(sent, got []byte) Report {
+	r := Report{Sent: len(sent), Received: len(got), FirstError: -1}
+	n := min(len(sent), len(got))
+	for i := 0; i < n; i++ {
+		if d := sent[i] ^ got[i]; d != 0 {
+			r.ByteErrors++
+			r.BitErrors += bits.OnesCount8(d)
+			if r.FirstError < 0 {
+				r.FirstError = i
+			}
+		}
+	}
+	if len(got) < len(sent) {
+		r.Missing = len(sent) - len(got)
+	} else {
+		r.Extra = len(got) - len(sent)
+	}
+	if r.FirstError < 0 && (r.Missing > 0 || r.Extra > 0) {
+		r.FirstError = n
+	}
+	r.Passed = r.FirstError < 0
+	return r
+}
+
+// Finish 填写耗时与速率
+func (r *Report) Finish(elapsed time.Duration, timedOut bool) {
+	r.ElapsedUs = elapsed.Microseconds()
+	r.TimedOut = timedOut
+	if elapsed > 0 {
+		r.BytesPerSec = int64(float64(r.Received) / elapsed.Seconds())
+	}
+}
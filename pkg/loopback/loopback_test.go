@@ -0,0 +1,55 @@
+package loopback
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	if err := (Config{}).Validate(); err != nil {
+		t.Errorf("Default config should be valid: %v", err)
+	}
+	if err := (Config{Pattern: "walking-ones"}).Validate(); !errors.Is(err, ErrPattern) {
+		t.Errorf("Expected ErrPattern, got %v", err)
+	}
+	for _, c := range []Config{{Length: -1}, {Length: MaxLength + 1}, {TimeoutMs: -1}, {TimeoutMs: 61000}} {
+		if err := c.Validate(); err == nil {
+			t.Errorf("%+v: expected error", c)
+		}
+	}
+}
+
+func TestData(t *testing.T) {
+	if d := (Config{}).Data(); len(d) != DefaultLength || d[0] != 0 || d[255] != 0xFF {
+		t.Errorf("Unexpected counter pattern % x", d[:4])
+	}
+	if d := (Config{Pattern: PatternASCII, Length: 100}).Data(); d[0] != ' ' || d[94] != '~' || d[95] != ' ' {
+		t.Errorf("Unexpected ascii pattern %q", d)
+	}
+	a := Config{Pattern: PatternRandom, Length: 64, Seed: 7}.Data()
+	b := Config{Pattern: PatternRandom, Length: 64, Seed: 7}.Data()
+	if !bytes.Equal(a, b) {
+		t.Error("Same seed should give the same data")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	sent := []byte{1, 2, 3, 4}
+	if r := Compare(sent, []byte{1, 2, 3, 4}); !r.Passed || r.FirstError != -1 {
+		t.Errorf("Expected pass, got %+v", r)
+	}
+	r := Compare(sent, []byte{1, 3, 3})
+	if r.Passed || r.ByteErrors != 1 || r.BitErrors != 1 || r.Missing != 1 || r.FirstError != 1 {
+		t.Errorf("Unexpected report %+v", r)
+	}
+	r = Compare(sent, []byte{1, 2, 3, 4, 0})
+	if r.Passed || r.Extra != 1 || r.FirstError != 4 {
+		t.Errorf("Unexpected report %+v", r)
+	}
+	r.Finish(500*time.Millisecond, false)
+	if r.BytesPerSec != 10 || r.ElapsedUs != 500000 {
+		t.Errorf("Unexpected timing %+v", r)
+	}
+}
@@ -0,0 +1,302 @@
+package mailreport
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"serial-assistant/pkg/framestats"
+	"serial-assistant/pkg/rules"
+)
+
+// 加密方式
+const (
+	SecurityStartTLS = "starttls" // 明文连接后升级 (通常为 587 端口)
+	SecurityTLS      = "tls"      // 直接 TLS (通常为 465 端口)
+	SecurityNone     = "none"
+)
+
+// Config SMTP 与报告设置
+type Config struct {
+	Enabled     bool     `json:"enabled"` // 会话结束时自动发送
+	Host        string   `json:"host"`
+	Port        int      `json:"port"`
+	Security    string   `json:"security"`
+	Username    string   `json:"username,omitempty"`
+	Password    string   `json:"password,omitempty"` // 由调用方保存在钥匙串中，旧版本的配置文件可能含有
+	PasswordSet bool     `json:"passwordSet"`        // 仅用于返回给前端，不保存
+	From        string   `json:"from"`
+	To          []string `json:"to"`
+}
+
+// Validate 检查必需的设置
+func (c *Config) Validate() error {
+	switch {
+	case c.Host == "":
+		return errors.New("mailreport: missing SMTP host")
+	case c.From == "":
+		return errors.New("mailreport: missing sender")
+	case len(c.To) == 0:
+		return errors.New("mailreport: missing recipients")
+	}
+	switch c.Security {
+	case "", SecurityStartTLS, SecurityTLS, SecurityNone:
+	default:
+		return fmt.Errorf("mailreport: unknown security %q", c.Security)
+	}
+	return nil
+}
+
+func (c *Config) port() int {
+	switch {
+	case c.Port > 0:
+		return c.Port
+	case c.Security == SecurityTLS:
+		return 465
+	case c.Security == SecurityNone:
+		return 25
+	}
+	return 587
+}
+
+// Load 读取配置，文件不存在时返回零值
+func Load(path string) (Config, error) {
+	var c Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+// Save 保存配置 (仅当前用户可读)
+func Save(path string, c Config) error {
+	c.PasswordSet = false
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Summary 一次会话的报告内容
+type Summary struct {
+	Connection  string                 `json:"connection"`
+	Title       string                 `json:"title,omitempty"`     // 从开机信息中提取的会话标题
+	Device      map[string]string      `json:"device,omitempty"`    // 设备信息，如固件版本
+	Variables   map[string]string      `json:"variables,omitempty"` // 会话变量，如提取的序列号
+	Started     time.Time              `json:"started"`
+	Ended       time.Time              `json:"ended"`
+	RxBytes     uint64                 `json:"rxBytes"`
+	TxBytes     uint64                 `json:"txBytes"`
+	Frames      []framestats.TypeStats `json:"frames,omitempty"`
+	Alerts      []rules.Match          `json:"alerts,omitempty"`
+	Excerpt     []byte                 `json:"-"` // 作为附件的接收日志片段
+	ExcerptName string                 `json:"-"`
+}
+
+// writeFields 按键排序写出一组字段，为空时不写
+func writeFields(b *strings.Builder, title string, fields map[string]string) {
+	if len(fields) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s:\n", title)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(b, "  %-10s %s\n", k+":", fields[k])
+	}
+}
+
+// Subject 邮件标题
+func (s *Summary) Subject() string {
+	status := "OK"
+	if len(s.Alerts) > 0 {
+		status = fmt.Sprintf("%d alerts", len(s.Alerts))
+	}
+	if s.Title != "" {
+		return fmt.Sprintf("[Serial Mate] %s (%s) session report: %s", s.Title, s.Connection, status)
+	}
+	return fmt.Sprintf("[Serial Mate] %s session report: %s", s.Connection, status)
+}
+
+// Body 纯文本正文
+func (s *Summary) Body() string {
+	var b strings.Builder
+	if s.Title != "" {
+		fmt.Fprintf(&b, "Session:    %s\n", s.Title)
+	}
+	fmt.Fprintf(&b, "Connection: %s\n", s.Connection)
+	fmt.Fprintf(&b, "Started:    %s\n", s.Started.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Ended:      %s (%s)\n", s.Ended.Format(time.RFC3339), s.Ended.Sub(s.Started).Round(time.Second))
+	fmt.Fprintf(&b, "Received:   %d bytes\n", s.RxBytes)
+	fmt.Fprintf(&b, "Sent:       %d bytes\n", s.TxBytes)
+
+	writeFields(&b, "Device", s.Device)
+	writeFields(&b, "Variables", s.Variables)
+
+	if len(s.Frames) > 0 {
+		b.WriteString("\nFrames:\n")
+		for _, f := range s.Frames {
+			name := f.Protocol
+			if f.Type != "" {
+				name += " " + f.Type
+			}
+			fmt.Fprintf(&b, "  %-24s %8d frames %10d bytes %6.2f%% errors\n", name, f.Count, f.Bytes, f.ErrorRate*100)
+		}
+	}
+
+	b.WriteString("\nAlerts:\n")
+	if len(s.Alerts) == 0 {
+		b.WriteString("  none\n")
+	}
+	for _, a := range s.Alerts {
+		fmt.Fprintf(&b, "  %s  %s: %s\n", a.Time.Format("15:04:05.000"), a.RuleName, a.Line)
+	}
+	return b.String()
+}
+
+// Compose 生成 MIME 邮件，日志片段作为附件
+func Compose(c Config, s Summary) ([]byte, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", c.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(c.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mimeHeader(s.Subject()))
+	fmt.Fprintf(&buf, "Date: %s\r\n", s.Ended.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", w.Boundary())
+
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	writeBase64(part, []byte(s.Body()))
+
+	if len(s.Excerpt) > 0 {
+		name := s.ExcerptName
+		if name == "" {
+			name = "excerpt.log"
+		}
+		part, err = w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {"text/plain; charset=utf-8"},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", name)},
+		})
+		if err != nil {
+			return nil, err
+		}
+		writeBase64(part, s.Excerpt)
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mimeHeader 对非 ASCII 标题进行 RFC 2047 编码
+func mimeHeader(s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return "=?utf-8?b?" + base64.StdEncoding.EncodeToString([]byte(s)) + "?="
+		}
+	}
+	return s
+}
+
+// writeBase64 按每行 76 字符写入 base64
+func writeBase64(w interface{ Write([]byte) (int, error) }, data []byte) {
+	enc := base64.StdEncoding.EncodeToString(data)
+	for len(enc) > 76 {
+		w.Write([]byte(enc[:76] + "\r\n"))
+		enc = enc[76:]
+	}
+	w.Write([]byte(enc + "\r\n"))
+}
+
+// Send 通过 SMTP 发送邮件
+func Send(ctx context.Context, c Config, msg []byte) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(c.Host, strconv.Itoa(c.port()))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	tlsConfig := &tls.Config{ServerName: c.Host}
+	if c.Security == SecurityTLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	client, err := smtp.NewClient(conn, c.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if c.Security == "" || c.Security == SecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("mailreport: server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if c.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.Username, c.Password, c.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(c.From); err != nil {
+		return err
+	}
+	for _, to := range c.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
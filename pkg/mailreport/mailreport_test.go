@@ -0,0 +1,165 @@
+package mailreport
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/framestats"
+	"serial-assistant/pkg/rules"
+)
+
+func summary() Summary {
+	t0 := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	return Summary{
+		Connection: "SERIAL COM3",
+		Started:    t0,
+		Ended:      t0.Add(90 * time.Minute),
+		RxBytes:    1024,
+		TxBytes:    16,
+		Frames:     []framestats.TypeStats{{Protocol: "modbus", Type: "3", Counter: framestats.Counter{Count: 10, Bytes: 80}}},
+		Alerts:     []rules.Match{{RuleName: "panic", Line: "panic: oops", Time: t0.Add(time.Hour)}},
+		Excerpt:    []byte("boot\npanic: oops\n"),
+	}
+}
+
+func TestCompose(t *testing.T) {
+	c := Config{From: "rig@example.com", To: []string{"team@example.com", "me@example.com"}}
+	raw, err := Compose(c, summary())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("Invalid message: %v", err)
+	}
+	if !strings.Contains(msg.Header.Get("Subject"), "1 alerts") {
+		t.Errorf("Unexpected subject %q", msg.Header.Get("Subject"))
+	}
+	_, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []string
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, p.FileName())
+	}
+	if len(parts) != 2 || parts[1] != "excerpt.log" {
+		t.Errorf("Expected body and attachment, got %v", parts)
+	}
+}
+
+func TestBody(t *testing.T) {
+	s := summary()
+	body := s.Body()
+	for _, want := range []string{"SERIAL COM3", "1h30m0s", "modbus 3", "panic: oops"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestTitleAndDevice(t *testing.T) {
+	s := summary()
+	s.Title = "Gateway v1.4"
+	s.Device = map[string]string{"firmware": "v1.4", "device": "Gateway"}
+	s.Variables = map[string]string{"serial": "SN-0042"}
+	if !strings.Contains(s.Subject(), "Gateway v1.4 (SERIAL COM3)") {
+		t.Errorf("Unexpected subject %q", s.Subject())
+	}
+	body := s.Body()
+	for _, want := range []string{"Session:    Gateway v1.4", "firmware:  v1.4", "Variables:\n  serial:    SN-0042"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestValidateAndSave(t *testing.T) {
+	if (&Config{}).Validate() == nil {
+		t.Error("Empty config should be invalid")
+	}
+	path := filepath.Join(t.TempDir(), "email.json")
+	want := Config{Host: "smtp.example.com", From: "a@example.com", To: []string{"b@example.com"}, Password: "x", PasswordSet: true}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil || got.Host != want.Host || got.Password != "x" || got.PasswordSet {
+		t.Errorf("Unexpected config %+v %v", got, err)
+	}
+}
+
+// TestSendPlain 用最小的 SMTP 服务器验证对话流程
+func TestSendPlain(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	got := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		conn.Write([]byte("220 test\r\n"))
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					conn.Write([]byte("250 ok\r\n"))
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+			switch strings.ToUpper(strings.Fields(line)[0]) {
+			case "EHLO", "HELO":
+				conn.Write([]byte("250 test\r\n"))
+			case "DATA":
+				inData = true
+				conn.Write([]byte("354 go\r\n"))
+			case "QUIT":
+				conn.Write([]byte("221 bye\r\n"))
+				got <- data.String()
+				return
+			default:
+				conn.Write([]byte("250 ok\r\n"))
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	c := Config{Host: host, Security: SecurityNone, From: "a@example.com", To: []string{"b@example.com"}}
+	c.Port, _ = net.LookupPort("tcp", port)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := Send(ctx, c, []byte("Subject: hi\r\n\r\nbody\r\n")); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if msg := <-got; !strings.Contains(msg, "body") {
+		t.Errorf("Server did not receive the message: %q", msg)
+	}
+}
@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Notifier 带限速的桌面通知
+// 超出频率的通知被合并，下一条通知的正文会注明期间省略的条数
+type Notifier struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time
+	dropped  int
+	send     func(ctx context.Context, title, body string) error
+}
+
+// New 创建通知器，两条通知的最小间隔为 interval
+func New(interval time.Duration) *Notifier {
+	return &Notifier{interval: interval, send: Send}
+}
+
+// Notify 发送通知；距上一条不足最小间隔时丢弃并返回 false
+func (n *Notifier) Notify(ctx context.Context, title, body string) (bool, error) {
+	n.mu.Lock()
+	now := time.Now()
+	if !n.last.IsZero() && now.Sub(n.last) < n.interval {
+		n.dropped++
+		n.mu.Unlock()
+		return false, nil
+	}
+	dropped := n.dropped
+	n.last = now
+	n.dropped = 0
+	n.mu.Unlock()
+
+	if dropped > 0 {
+		body = body + "\n" + droppedSuffix(dropped)
+	}
+	return true, n.send(ctx, title, body)
+}
+
+func droppedSuffix(n int) string {
+	if n == 1 {
+		return "(+1 more notification suppressed)"
+	}
+	return "(+" + strconv.Itoa(n) + " more notifications suppressed)"
+}
@@ -0,0 +1,47 @@
+//go:build darwin
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Send 通过 osascript 显示通知中心通知
+func Send(ctx context.Context, title, body string) error {
+	script := fmt.Sprintf("display notification %s with title %s", appleString(body), appleString(title))
+	return run(ctx, "osascript", "-e", script)
+}
+
+// defaultSound 系统提示音
+const defaultSound = "/System/Library/Sounds/Ping.aiff"
+
+// PlaySound 通过 afplay 播放声音文件，path 为空时播放系统提示音
+func PlaySound(ctx context.Context, path string) error {
+	if path == "" {
+		path = defaultSound
+	}
+	return run(ctx, "afplay", path)
+}
+
+// Speak 通过 say 朗读文本
+func Speak(ctx context.Context, text string) error {
+	return run(ctx, "say", text)
+}
+
+func run(ctx context.Context, name string, args ...string) error {
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// appleString 转义为 AppleScript 字符串字面量
+func appleString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,53 @@
+//go:build !windows && !darwin
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Send 通过 notify-send (libnotify) 显示桌面通知
+func Send(ctx context.Context, title, body string) error {
+	out, err := exec.CommandContext(ctx, "notify-send", "--app-name=Serial Mate", title, body).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("notify-send: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// defaultSound freedesktop 声音主题中的提示音
+const defaultSound = "/usr/share/sounds/freedesktop/stereo/bell.oga"
+
+// PlaySound 播放声音文件，path 为空时播放系统提示音
+// 依次尝试 paplay (PulseAudio/PipeWire) 与 aplay (ALSA，仅支持 WAV)
+func PlaySound(ctx context.Context, path string) error {
+	if path == "" {
+		path = defaultSound
+	}
+	return firstAvailable(ctx, [][]string{{"paplay", path}, {"aplay", "-q", path}})
+}
+
+// Speak 朗读文本，依次尝试 spd-say (speech-dispatcher) 与 espeak
+func Speak(ctx context.Context, text string) error {
+	return firstAvailable(ctx, [][]string{{"spd-say", "--wait", text}, {"espeak", text}})
+}
+
+// firstAvailable 执行第一个已安装的命令
+func firstAvailable(ctx context.Context, commands [][]string) error {
+	var names []string
+	for _, c := range commands {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			names = append(names, c[0])
+			continue
+		}
+		out, err := exec.CommandContext(ctx, c[0], c[1:]...).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%s: %w: %s", c[0], err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+	return fmt.Errorf("notify: none of %s is installed", strings.Join(names, ", "))
+}
@@ -0,0 +1,19 @@
+//go:build !windows && !darwin
+
+package notify
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFirstAvailable(t *testing.T) {
+	err := firstAvailable(context.Background(), [][]string{{"serial-mate-missing-a"}, {"serial-mate-missing-b"}})
+	if err == nil || !strings.Contains(err.Error(), "serial-mate-missing-a, serial-mate-missing-b") {
+		t.Errorf("Expected a missing-command error, got %v", err)
+	}
+	if err := firstAvailable(context.Background(), [][]string{{"serial-mate-missing"}, {"true"}}); err != nil {
+		t.Errorf("Expected fallback to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifierRateLimit(t *testing.T) {
+	var bodies []string
+	n := New(time.Hour)
+	n.send = func(_ context.Context, _, body string) error {
+		bodies = append(bodies, body)
+		return nil
+	}
+	if ok, _ := n.Notify(context.Background(), "t", "first"); !ok {
+		t.Fatal("First notification should be sent")
+	}
+	for i := 0; i < 3; i++ {
+		if ok, _ := n.Notify(context.Background(), "t", "flood"); ok {
+			t.Fatal("Notifications within the interval should be dropped")
+		}
+	}
+	// 模拟间隔已过
+	n.last = time.Now().Add(-2 * time.Hour)
+	n.Notify(context.Background(), "t", "later")
+	if len(bodies) != 2 || !strings.Contains(bodies[1], "+3 more") {
+		t.Errorf("Expected dropped count in the next body, got %q", bodies)
+	}
+}
@@ -0,0 +1,62 @@
+//go:build windows
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// toastScript 通过 WinRT 显示 Toast 通知；标题与正文经环境变量传入，避免转义问题
+const toastScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode($env:NOTIFY_TITLE)) | Out-Null
+$text.Item(1).AppendChild($template.CreateTextNode($env:NOTIFY_BODY)) | Out-Null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($template)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('Serial Mate').Show($toast)
+`
+
+// soundScript 播放 WAV 文件，未指定文件时播放系统提示音
+const soundScript = `
+if ($env:NOTIFY_SOUND) { (New-Object System.Media.SoundPlayer $env:NOTIFY_SOUND).PlaySync() }
+else { [System.Media.SystemSounds]::Exclamation.Play(); Start-Sleep -Milliseconds 500 }
+`
+
+// speakScript 通过 System.Speech 朗读文本
+const speakScript = `
+Add-Type -AssemblyName System.Speech
+(New-Object System.Speech.Synthesis.SpeechSynthesizer).Speak($env:NOTIFY_TEXT)
+`
+
+// Send 通过 PowerShell 调用 WinRT 显示 Toast 通知
+func Send(ctx context.Context, title, body string) error {
+	return powershell(ctx, toastScript, "NOTIFY_TITLE="+title, "NOTIFY_BODY="+body)
+}
+
+// PlaySound 播放 WAV 文件，path 为空时播放系统提示音
+func PlaySound(ctx context.Context, path string) error {
+	return powershell(ctx, soundScript, "NOTIFY_SOUND="+path)
+}
+
+// Speak 朗读文本
+func Speak(ctx context.Context, text string) error {
+	return powershell(ctx, speakScript, "NOTIFY_TEXT="+text)
+}
+
+// powershell 在隐藏窗口中执行脚本，参数经环境变量传入
+func powershell(ctx context.Context, script string, env ...string) error {
+	cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", script)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("powershell: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// State 操作状态
+type State string
+
+const (
+	StateRunning  State = "running"
+	StateDone     State = "done"
+	StateFailed   State = "failed"
+	StateCanceled State = "canceled"
+)
+
+// progressInterval 两次进度通知的最小间隔，避免高频回调淹没前端
+const progressInterval = 100 * time.Millisecond
+
+// Info 操作的对外描述
+type Info struct {
+	ID      string    `json:"id"`
+	Kind    string    `json:"kind"`
+	Started time.Time `json:"started"`
+}
+
+// Progress 统一的进度通知格式 ("operation-progress" 事件)
+// Percent 为 -1 表示进度未知
+type Progress struct {
+	ID      string  `json:"id"`
+	Kind    string  `json:"kind"`
+	Phase   string  `json:"phase"`
+	Percent float64 `json:"percent"`
+	Done    int64   `json:"done"`
+	Total   int64   `json:"total"`
+	State   State   `json:"state"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Op 一个可取消的长耗时操作
+type Op struct {
+	ID      string
+	Kind    string
+	Started time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	m      *Manager
+
+	mu         sync.Mutex
+	progress   Progress
+	reporting  bool // 上报过进度的操作才会发送通知
+	lastNotify time.Time
+	err        error
+}
+
+// Context 返回操作的 ctx，操作被取消或超时后结束
+func (op *Op) Context() context.Context {
+	return op.ctx
+}
+
+// Report 上报进度；total <= 0 时进度未知
+// 阶段变化或完成时立即通知，其余按 progressInterval 节流
+func (op *Op) Report(phase string, done, total int64) {
+	op.mu.Lock()
+	phaseChanged := op.progress.Phase != phase
+	op.progress.Phase = phase
+	op.progress.Done = done
+	op.progress.Total = total
+	if total > 0 {
+		op.progress.Percent = float64(done) / float64(total) * 100
+	} else {
+		op.progress.Percent = -1
+	}
+	op.reporting = true
+
+	now := time.Now()
+	notify := phaseChanged || (total > 0 && done >= total) || now.Sub(op.lastNotify) >= progressInterval
+	if notify {
+		op.lastNotify = now
+	}
+	snapshot := op.progress
+	op.mu.Unlock()
+
+	if notify {
+		op.m.notify(snapshot)
+	}
+}
+
+// Fail 记录操作失败的原因，在 Finish 时随最终状态一起通知
+func (op *Op) Fail(err error) {
+	op.mu.Lock()
+	op.err = err
+	op.mu.Unlock()
+}
+
+// Manager 管理进行中的操作，按 ID 取消
+type Manager struct {
+	mu       sync.Mutex
+	ops      map[string]*Op
+	seq      uint64
+	listener func(Progress)
+}
+
+// NewManager 创建操作管理器，listener 接收进度通知 (可为 nil)
+func NewManager(listener func(Progress)) *Manager {
+	return &Manager{ops: make(map[string]*Op), listener: listener}
+}
+
+func (m *Manager) notify(p Progress) {
+	if m.listener != nil {
+		m.listener(p)
+	}
+}
+
+// Start 登记一个新操作；timeout 为 0 表示不设超时
+func (m *Manager) Start(parent context.Context, kind string, timeout time.Duration) *Op {
+	if parent == nil {
+		parent = context.Background()
+	}
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(parent)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.seq++
+	op := &Op{
+		ID:      fmt.Sprintf("%s-%d", kind, m.seq),
+		Kind:    kind,
+		Started: time.Now(),
+		ctx:     ctx,
+		cancel:  cancel,
+		m:       m,
+	}
+	op.progress = Progress{ID: op.ID, Kind: kind, Percent: -1, State: StateRunning}
+	m.ops[op.ID] = op
+	return op
+}
+
+// Finish 结束操作并释放其 ctx，可重复调用
+// 上报过进度的操作会发送一次最终状态通知
+func (m *Manager) Finish(op *Op) {
+	m.mu.Lock()
+	_, active := m.ops[op.ID]
+	delete(m.ops, op.ID)
+	m.mu.Unlock()
+
+	op.mu.Lock()
+	p := op.progress
+	switch {
+	case op.err != nil && errors.Is(op.err, context.Canceled):
+		p.State = StateCanceled
+		p.Error = op.err.Error()
+	case op.err != nil:
+		p.State = StateFailed
+		p.Error = op.err.Error()
+	default:
+		p.State = StateDone
+		if p.Total > 0 {
+			p.Percent = 100
+		}
+	}
+	reporting := op.reporting
+	op.mu.Unlock()
+
+	op.cancel()
+	if active && reporting {
+		m.notify(p)
+	}
+}
+
+// Cancel 取消指定操作，操作不存在时返回 false
+func (m *Manager) Cancel(id string) bool {
+	m.mu.Lock()
+	op, ok := m.ops[id]
+	m.mu.Unlock()
+	if !ok {
+		return false
+	}
+	op.cancel()
+	return true
+}
+
+// CancelAll 取消所有进行中的操作
+func (m *Manager) CancelAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, op := range m.ops {
+		op.cancel()
+	}
+}
+
+// CancelKind 取消指定类型的全部操作，返回取消的数量
+func (m *Manager) CancelKind(kind string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, op := range m.ops {
+		if op.Kind == kind {
+			op.cancel()
+			n++
+		}
+	}
+	return n
+}
+
+// List 返回进行中的操作，按开始时间排序
+func (m *Manager) List() []Info {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := make([]Info, 0, len(m.ops))
+	for _, op := range m.ops {
+		list = append(list, Info{ID: op.ID, Kind: op.Kind, Started: op.Started})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Started.Before(list[j].Started)
+	})
+	return list
+}
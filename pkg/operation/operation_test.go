@@ -0,0 +1,135 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStartAndFinish(t *testing.T) {
+	m := NewManager(nil)
+	op := m.Start(context.Background(), "open-serial", 0)
+	if len(m.List()) != 1 || m.List()[0].ID != op.ID {
+		t.Fatalf("Expected operation to be listed, got %+v", m.List())
+	}
+
+	m.Finish(op)
+	if len(m.List()) != 0 {
+		t.Errorf("Expected no operations after finish, got %+v", m.List())
+	}
+	if op.Context().Err() == nil {
+		t.Error("Finished operation context should be done")
+	}
+	m.Finish(op)
+}
+
+func TestCancel(t *testing.T) {
+	m := NewManager(nil)
+	op := m.Start(context.Background(), "send", 0)
+
+	if m.Cancel("unknown") {
+		t.Error("Cancel of unknown operation should return false")
+	}
+	if !m.Cancel(op.ID) {
+		t.Fatal("Cancel should find the operation")
+	}
+	select {
+	case <-op.Context().Done():
+	case <-time.After(time.Second):
+		t.Fatal("Operation context was not canceled")
+	}
+	if op.Context().Err() != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", op.Context().Err())
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	m := NewManager(nil)
+	op := m.Start(context.Background(), "open-tcp", 10*time.Millisecond)
+	defer m.Finish(op)
+
+	<-op.Context().Done()
+	if op.Context().Err() != context.DeadlineExceeded {
+		t.Errorf("Expected deadline exceeded, got %v", op.Context().Err())
+	}
+}
+
+func TestCancelAllAndUniqueIDs(t *testing.T) {
+	m := NewManager(nil)
+	a := m.Start(context.Background(), "send", 0)
+	b := m.Start(context.Background(), "send", 0)
+	if a.ID == b.ID {
+		t.Fatalf("Operation IDs should be unique, both are %s", a.ID)
+	}
+
+	m.CancelAll()
+	if a.Context().Err() == nil || b.Context().Err() == nil {
+		t.Error("CancelAll should cancel every operation")
+	}
+}
+
+func TestCancelKind(t *testing.T) {
+	m := NewManager(nil)
+	a := m.Start(context.Background(), "schedule", 0)
+	b := m.Start(context.Background(), "send", 0)
+	if n := m.CancelKind("schedule"); n != 1 {
+		t.Errorf("Expected 1 canceled operation, got %d", n)
+	}
+	if a.Context().Err() == nil || b.Context().Err() != nil {
+		t.Error("CancelKind should only cancel operations of that kind")
+	}
+}
+
+func TestProgressNotifications(t *testing.T) {
+	var events []Progress
+	m := NewManager(func(p Progress) { events = append(events, p) })
+
+	quiet := m.Start(context.Background(), "send", 0)
+	m.Finish(quiet)
+	if len(events) != 0 {
+		t.Fatalf("Operations without progress should not notify, got %+v", events)
+	}
+
+	op := m.Start(context.Background(), "update", 0)
+	op.Report("download", 0, 200)
+	op.Report("download", 50, 200) // 节流，不通知
+	op.Report("download", 200, 200)
+	m.Finish(op)
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 notifications, got %d: %+v", len(events), events)
+	}
+	if events[1].Percent != 100 || events[1].State != StateRunning {
+		t.Errorf("Unexpected completion progress: %+v", events[1])
+	}
+	last := events[2]
+	if last.State != StateDone || last.ID != op.ID || last.Kind != "update" {
+		t.Errorf("Unexpected final progress: %+v", last)
+	}
+}
+
+func TestFinishReportsFailureAndCancel(t *testing.T) {
+	var last Progress
+	m := NewManager(func(p Progress) { last = p })
+
+	failed := m.Start(context.Background(), "export", 0)
+	failed.Report("write", 1, 0)
+	if last.Percent != -1 {
+		t.Errorf("Unknown total should report percent -1, got %f", last.Percent)
+	}
+	failed.Fail(errors.New("disk full"))
+	m.Finish(failed)
+	if last.State != StateFailed || last.Error != "disk full" {
+		t.Errorf("Expected failed state, got %+v", last)
+	}
+
+	canceled := m.Start(context.Background(), "export", 0)
+	canceled.Report("write", 1, 10)
+	m.Cancel(canceled.ID)
+	canceled.Fail(canceled.Context().Err())
+	m.Finish(canceled)
+	if last.State != StateCanceled {
+		t.Errorf("Expected canceled state, got %+v", last)
+	}
+}
@@ -0,0 +1,317 @@
+package packetdef
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"serial-assistant/pkg/decoder"
+)
+
+// maxFrame 帧长度上限，防止错误的长度字段吞掉整个数据流
+const maxFrame = 4096
+
+// LengthField 帧中表示长度的字段，帧总长度 = 字段值 + Adjust
+type LengthField struct {
+	Offset int    `json:"offset"`
+	Type   string `json:"type"` // u8、u16、u32
+	Adjust int    `json:"adjust"`
+}
+
+// Field 帧中的一个字段
+type Field struct {
+	Name   string `json:"name"`
+	Offset int    `json:"offset"`
+	Type   string `json:"type"`             // u8..u64、i8..i64、f32、f64、bytes、string
+	Size   int    `json:"size,omitempty"`   // bytes/string 的长度，0 表示到帧尾
+	Endian string `json:"endian,omitempty"` // 覆盖 Def.Endian
+
+	// Enum 整数值对应的名称，键可以是十进制或 0x 十六进制，如 {"0": "IDLE", "1": "RUNNING"}
+	Enum map[string]string `json:"enum,omitempty"`
+	// Flags 各标志位的名称，整数值按位解释为 "RUNNING|OVERTEMP"
+	Flags []Flag `json:"flags,omitempty"`
+
+	enum map[int64]string
+}
+
+// Def 一种自定义帧格式的定义，通常从 JSON 文件加载
+//
+//	{
+//	  "name": "sensor",
+//	  "sync": "AA55",
+//	  "lengthField": {"offset": 2, "type": "u8", "adjust": 4},
+//	  "fields": [
+//	    {"name": "temp", "offset": 3, "type": "i16"},
+//	    {"name": "status", "offset": 5, "type": "u8", "flags": [{"bit": 0, "name": "RUNNING"}, {"bit": 3, "name": "OVERTEMP"}]}
+//	  ]
+//	}
+type Def struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Sync        string       `json:"sync"`             // 帧头，十六进制
+	Endian      string       `json:"endian,omitempty"` // big (默认) 或 little
+	Length      int          `json:"length,omitempty"` // 固定帧长
+	LengthField *LengthField `json:"lengthField,omitempty"`
+	Fields      []Field      `json:"fields"`
+
+	sync []byte
+}
+
+// typeSizes 数值类型的字节数
+var typeSizes = map[string]int{
+	"u8": 1, "i8": 1, "u16": 2, "i16": 2, "u32": 4, "i32": 4, "u64": 8, "i64": 8, "f32": 4, "f64": 8,
+}
+
+// Validate 检查定义并解析帧头
+func (d *Def) Validate() error {
+	if d.Name == "" {
+		return errors.New("packetdef: missing name")
+	}
+	sync, err := hex.DecodeString(strings.ReplaceAll(d.Sync, " ", ""))
+	if err != nil || len(sync) == 0 {
+		return fmt.Errorf("packetdef %s: invalid sync %q", d.Name, d.Sync)
+	}
+	d.sync = sync
+	if d.Endian != "" && d.Endian != "big" && d.Endian != "little" {
+		return fmt.Errorf("packetdef %s: invalid endian %q", d.Name, d.Endian)
+	}
+	if (d.Length > 0) == (d.LengthField != nil) {
+		return fmt.Errorf("packetdef %s: exactly one of length and lengthField is required", d.Name)
+	}
+	if d.Length > maxFrame {
+		return fmt.Errorf("packetdef %s: length %d exceeds %d", d.Name, d.Length, maxFrame)
+	}
+	if lf := d.LengthField; lf != nil {
+		if lf.Type != "u8" && lf.Type != "u16" && lf.Type != "u32" {
+			return fmt.Errorf("packetdef %s: invalid length field type %q", d.Name, lf.Type)
+		}
+	}
+	for i := range d.Fields {
+		f := &d.Fields[i]
+		if f.Name == "" || f.Offset < 0 {
+			return fmt.Errorf("packetdef %s: invalid field %+v", d.Name, *f)
+		}
+		if _, ok := typeSizes[f.Type]; !ok && f.Type != "bytes" && f.Type != "string" {
+			return fmt.Errorf("packetdef %s: field %s has unknown type %q", d.Name, f.Name, f.Type)
+		}
+		if err := f.compileSymbols(); err != nil {
+			return fmt.Errorf("packetdef %s: field %s: %w", d.Name, f.Name, err)
+		}
+	}
+	return nil
+}
+
+// Load 从 JSON 文件加载定义，文件内容可以是单个定义或定义数组
+func Load(path string) ([]*Def, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var defs []*Def
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(data, &defs)
+	} else {
+		var def Def
+		err = json.Unmarshal(data, &def)
+		defs = []*Def{&def}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("packetdef: %s: %w", path, err)
+	}
+	for _, d := range defs {
+		if err := d.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return defs, nil
+}
+
+// Register 将定义注册为解码器
+func Register(d *Def) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	desc := d.Description
+	if desc == "" {
+		desc = "Custom frame " + d.Name
+	}
+	decoder.Register(decoder.Info{Name: d.Name, Description: desc, Source: "packetdef"}, func() decoder.Decoder {
+		return &Decoder{def: d}
+	})
+	return nil
+}
+
+// Decoder 按定义成帧与解析字段的解码器
+type Decoder struct {
+	def *Def
+	buf []byte
+}
+
+func (p *Decoder) Name() string { return p.def.Name }
+
+// Detect 统计以帧头开始且首尾相接的帧
+func (p *Decoder) Detect(sample []byte) float64 {
+	i := bytes.Index(sample, p.def.sync)
+	if i < 0 {
+		return 0
+	}
+	frames := 0
+	for i < len(sample) {
+		n := p.def.frameLength(sample[i:])
+		if n <= 0 || i+n > len(sample) || !bytes.HasPrefix(sample[i:], p.def.sync) {
+			break
+		}
+		frames++
+		i += n
+	}
+	switch {
+	case frames >= 3:
+		return 0.9
+	case frames == 2:
+		return 0.6
+	}
+	return 0
+}
+
+func (p *Decoder) Decode(t time.Time, data []byte, emit func(decoder.Frame)) {
+	p.buf = append(p.buf, data...)
+	for {
+		i := bytes.Index(p.buf, p.def.sync)
+		if i < 0 {
+			// 保留可能是帧头前缀的末尾字节
+			keep := len(p.def.sync) - 1
+			if len(p.buf) > keep {
+				p.buf = p.buf[:copy(p.buf, p.buf[len(p.buf)-keep:])]
+			}
+			return
+		}
+		p.buf = p.buf[:copy(p.buf, p.buf[i:])]
+		n := p.def.frameLength(p.buf)
+		if n == 0 {
+			// 长度非法，跳过这个帧头
+			p.buf = p.buf[:copy(p.buf, p.buf[1:])]
+			continue
+		}
+		if n < 0 || n > len(p.buf) {
+			return
+		}
+		emit(p.def.parse(t, append([]byte(nil), p.buf[:n]...)))
+		p.buf = p.buf[:copy(p.buf, p.buf[n:])]
+	}
+}
+
+func (d *Def) order(endian string) binary.ByteOrder {
+	if endian == "" {
+		endian = d.Endian
+	}
+	if endian == "little" {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
+
+// frameLength 返回 b 开头的帧长度，0 表示长度非法，-1 表示数据不足
+func (d *Def) frameLength(b []byte) int {
+	if d.Length > 0 {
+		return d.Length
+	}
+	lf := d.LengthField
+	size := typeSizes[lf.Type]
+	if len(b) < lf.Offset+size {
+		return -1
+	}
+	v, _ := readUint(b[lf.Offset:], size, d.order(""))
+	n := int(v) + lf.Adjust
+	if n < len(d.sync) || n < lf.Offset+size || n > maxFrame {
+		return 0
+	}
+	return n
+}
+
+func readUint(b []byte, size int, order binary.ByteOrder) (uint64, bool) {
+	if len(b) < size {
+		return 0, false
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), true
+	case 2:
+		return uint64(order.Uint16(b)), true
+	case 4:
+		return uint64(order.Uint32(b)), true
+	case 8:
+		return order.Uint64(b), true
+	}
+	return 0, false
+}
+
+func (d *Def) parse(t time.Time, raw []byte) decoder.Frame {
+	f := decoder.Frame{Protocol: d.Name, Time: t, Raw: raw}
+	var summary []string
+	for _, field := range d.Fields {
+		v, ok := field.value(raw, d.order(field.Endian))
+		if !ok {
+			f.Error = fmt.Sprintf("field %s out of range", field.Name)
+			continue
+		}
+		name := d.Name + "." + field.Name
+		f.Fields = append(f.Fields, decoder.Field{Name: name, Value: v})
+		if symbol, ok := field.symbol(v); ok {
+			f.Fields = append(f.Fields, decoder.Field{Name: name + ".name", Value: symbol})
+			f.Fields = append(f.Fields, field.flagFields(name, v)...)
+			v = symbol
+		}
+		summary = append(summary, fmt.Sprintf("%s=%v", field.Name, v))
+	}
+	f.Summary = strings.Join(summary, " ")
+	return f
+}
+
+// value 读取字段值，越界时返回 false
+func (f *Field) value(raw []byte, order binary.ByteOrder) (interface{}, bool) {
+	if f.Offset > len(raw) {
+		return nil, false
+	}
+	b := raw[f.Offset:]
+	switch f.Type {
+	case "bytes", "string":
+		if f.Size > 0 {
+			if f.Size > len(b) {
+				return nil, false
+			}
+			b = b[:f.Size]
+		}
+		if f.Type == "string" {
+			return string(bytes.TrimRight(b, "\x00")), true
+		}
+		return hex.EncodeToString(b), true
+	}
+	size := typeSizes[f.Type]
+	u, ok := readUint(b, size, order)
+	if !ok {
+		return nil, false
+	}
+	switch f.Type {
+	case "f32":
+		return float64(math.Float32frombits(uint32(u))), true
+	case "f64":
+		return math.Float64frombits(u), true
+	case "i8":
+		return int64(int8(u)), true
+	case "i16":
+		return int64(int16(u)), true
+	case "i32":
+		return int64(int32(u)), true
+	case "i64", "u64":
+		// 超过 int64 范围的 u64 按补码保存，与其余整数字段保持同一类型
+		return int64(u), true
+	}
+	return int64(u), true
+}
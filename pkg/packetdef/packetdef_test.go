@@ -0,0 +1,130 @@
+package packetdef
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/decoder"
+)
+
+const sensorDef = `{
+  "name": "sensor",
+  "sync": "AA55",
+  "lengthField": {"offset": 2, "type": "u8", "adjust": 3},
+  "fields": [
+    {"name": "temp", "offset": 3, "type": "i16"},
+    {"name": "humidity", "offset": 5, "type": "u16", "endian": "little"},
+    {"name": "tag", "offset": 7, "type": "string"}
+  ]
+}`
+
+func TestLoadAndDecode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sensor.json")
+	os.WriteFile(path, []byte(sensorDef), 0o644)
+	defs, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := Register(defs[0]); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	d, err := decoder.New("sensor")
+	if err != nil {
+		t.Fatalf("Definition should be registered as a decoder: %v", err)
+	}
+
+	frame := []byte{0xAA, 0x55, 6, 0xFF, 0x38, 0x2C, 0x01, 'a', 'b'}
+	var frames []decoder.Frame
+	emit := func(f decoder.Frame) { frames = append(frames, f) }
+	d.Decode(time.Now(), append([]byte{0x00, 0xAA}, frame[:4]...), emit)
+	d.Decode(time.Now(), frame[4:], emit)
+	if len(frames) != 1 {
+		t.Fatalf("Expected one frame, got %+v", frames)
+	}
+	f := frames[0]
+	if v, _ := f.Get("sensor.temp"); v != int64(-200) {
+		t.Errorf("Unexpected temp %v", v)
+	}
+	if v, _ := f.Get("sensor.humidity"); v != int64(300) {
+		t.Errorf("Unexpected humidity %v", v)
+	}
+	if v, _ := f.Get("sensor.tag"); v != "ab" {
+		t.Errorf("Unexpected tag %v", v)
+	}
+
+	stream := append(append(append([]byte{}, frame...), frame...), frame...)
+	if d.Detect(stream) < 0.8 {
+		t.Errorf("Expected high confidence for chained frames")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	bad := []Def{
+		{Sync: "AA", Length: 4},
+		{Name: "x", Sync: "zz", Length: 4},
+		{Name: "x", Sync: "AA"},
+		{Name: "x", Sync: "AA", Length: 4, Fields: []Field{{Name: "a", Type: "u128"}}},
+	}
+	for _, d := range bad {
+		if d.Validate() == nil {
+			t.Errorf("Expected %+v to be rejected", d)
+		}
+	}
+}
+
+func TestEnumAndFlags(t *testing.T) {
+	d := &Def{
+		Name:   "plc",
+		Sync:   "7E",
+		Length: 3,
+		Fields: []Field{
+			{Name: "mode", Offset: 1, Type: "u8", Enum: map[string]string{"0": "IDLE", "0x01": "RUNNING"}},
+			{Name: "status", Offset: 2, Type: "u8", Flags: []Flag{{Bit: 0, Name: "RUNNING"}, {Bit: 3, Name: "OVERTEMP"}}},
+		},
+	}
+	if err := d.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	f := d.parse(time.Now(), []byte{0x7E, 0x01, 0x09})
+	if v, _ := f.Get("plc.mode.name"); v != "RUNNING" {
+		t.Errorf("Unexpected mode name %v", v)
+	}
+	if v, _ := f.Get("plc.status"); v != int64(9) {
+		t.Errorf("Raw value should be kept, got %v", v)
+	}
+	if v, _ := f.Get("plc.status.name"); v != "RUNNING|OVERTEMP" {
+		t.Errorf("Unexpected status name %v", v)
+	}
+	if v, _ := f.Get("plc.status.OVERTEMP"); v != true {
+		t.Errorf("Expected OVERTEMP flag field, got %v", v)
+	}
+	if f.Summary != "mode=RUNNING status=RUNNING|OVERTEMP" {
+		t.Errorf("Unexpected summary %q", f.Summary)
+	}
+
+	f = d.parse(time.Now(), []byte{0x7E, 0x05, 0x82})
+	if v, _ := f.Get("plc.mode.name"); v != "UNKNOWN(5)" {
+		t.Errorf("Unexpected unknown enum %v", v)
+	}
+	if v, _ := f.Get("plc.status.name"); v != "0x82" {
+		t.Errorf("Unnamed bits should be kept, got %v", v)
+	}
+}
+
+func TestInvalidSymbols(t *testing.T) {
+	bad := []Field{
+		{Name: "a", Type: "f32", Enum: map[string]string{"1": "ONE"}},
+		{Name: "a", Type: "u8", Enum: map[string]string{"x": "ONE"}},
+		{Name: "a", Type: "u8", Flags: []Flag{{Bit: 8, Name: "HIGH"}}},
+		{Name: "a", Type: "u8", Enum: map[string]string{"1": "ONE"}, Flags: []Flag{{Bit: 0, Name: "B"}}},
+	}
+	for _, f := range bad {
+		d := Def{Name: "x", Sync: "AA", Length: 2, Fields: []Field{f}}
+		if d.Validate() == nil {
+			t.Errorf("Expected %+v to be rejected", f)
+		}
+	}
+}
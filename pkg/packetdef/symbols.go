@@ -0,0 +1,86 @@
+package packetdef
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"serial-assistant/pkg/decoder"
+)
+
+// Flag 一个标志位
+type Flag struct {
+	Bit  uint   `json:"bit"`
+	Name string `json:"name"`
+}
+
+// compileSymbols 解析枚举的键并检查标志位
+func (f *Field) compileSymbols() error {
+	if len(f.Enum) == 0 && len(f.Flags) == 0 {
+		return nil
+	}
+	if len(f.Enum) > 0 && len(f.Flags) > 0 {
+		return errors.New("enum and flags are mutually exclusive")
+	}
+	size, ok := typeSizes[f.Type]
+	if !ok || f.Type == "f32" || f.Type == "f64" {
+		return fmt.Errorf("enum/flags require an integer type, got %s", f.Type)
+	}
+	f.enum = make(map[int64]string, len(f.Enum))
+	for k, name := range f.Enum {
+		v, err := strconv.ParseInt(k, 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid enum value %q", k)
+		}
+		f.enum[v] = name
+	}
+	for _, flag := range f.Flags {
+		if flag.Name == "" || flag.Bit >= uint(size*8) {
+			return fmt.Errorf("invalid flag %+v", flag)
+		}
+	}
+	return nil
+}
+
+// symbol 返回整数值的符号名；字段没有定义枚举或标志位时返回 false
+func (f *Field) symbol(v interface{}) (string, bool) {
+	n, ok := v.(int64)
+	if !ok || f.enum == nil {
+		return "", false
+	}
+	if len(f.Flags) == 0 {
+		if name, ok := f.enum[n]; ok {
+			return name, true
+		}
+		return fmt.Sprintf("UNKNOWN(%d)", n), true
+	}
+
+	var names []string
+	rest := uint64(n)
+	for _, flag := range f.Flags {
+		mask := uint64(1) << flag.Bit
+		if rest&mask != 0 {
+			names = append(names, flag.Name)
+			rest &^= mask
+		}
+	}
+	// 未命名的位以十六进制保留，避免静默丢失信息
+	if rest != 0 {
+		names = append(names, fmt.Sprintf("0x%X", rest))
+	}
+	if len(names) == 0 {
+		return "0", true
+	}
+	return strings.Join(names, "|"), true
+}
+
+// flagFields 为每个标志位生成布尔字段 (如 sensor.status.OVERTEMP)，便于过滤
+func (f *Field) flagFields(name string, v interface{}) []decoder.Field {
+	n, _ := v.(int64)
+	fields := make([]decoder.Field, len(f.Flags))
+	for i, flag := range f.Flags {
+		fields[i] = decoder.Field{Name: name + "." + flag.Name, Value: uint64(n)&(1<<flag.Bit) != 0}
+	}
+	return fields
+}
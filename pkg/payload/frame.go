@@ -0,0 +1,164 @@
+package payload
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FrameSpec 十六进制内容中帧字段的计算方式，按片段 (宏) 配置
+//
+// 帧字段：
+//
+//	{len} {len+N} {len-N}  帧体长度 (可加减修正)
+//	{sum}                  按 Checksum 指定的算法计算的校验
+//	{sum8} {neg8} {xor8}   8 位累加和、累加和的补码、异或
+//	{sum16} {crc16}        16 位累加和、Modbus CRC-16 (低字节在前)
+//
+// 帧体默认从第一个 {len} 之后开始 (没有 {len} 时从 BodyStart 开始)，到第一个校验字段之前结束
+type FrameSpec struct {
+	BodyStart int    `json:"bodyStart,omitempty"` // 帧体起始偏移，0 表示自动
+	LenWidth  int    `json:"lenWidth,omitempty"`  // {len} 的字节数 (1-4)，默认 1
+	LenAdjust int    `json:"lenAdjust,omitempty"` // 加到长度上的修正值
+	Endian    string `json:"endian,omitempty"`    // 多字节字段的字节序：big (默认) 或 little；crc16 固定低字节在前
+	Checksum  string `json:"checksum,omitempty"`  // {sum} 的算法，默认 sum8
+	SumFrom   string `json:"sumFrom,omitempty"`   // 校验范围起点：body (默认)、len (含长度字段) 或 start (帧首)
+}
+
+// field 帧字段在输出中的位置
+type field struct {
+	name   string
+	adjust int
+	pos    int
+	width  int
+}
+
+// checksumWidth 各校验算法的字节数
+var checksumWidth = map[string]int{
+	"sum8":  1,
+	"neg8":  1,
+	"xor8":  1,
+	"sum16": 2,
+	"crc16": 2,
+}
+
+// parseField 解析 "len+2"、"sum" 等字段表达式
+func parseField(expr string, spec FrameSpec) (field, error) {
+	expr = strings.ReplaceAll(expr, " ", "")
+	name, adjust := expr, 0
+	if i := strings.IndexAny(expr, "+-"); i > 0 {
+		n, err := strconv.Atoi(expr[i:])
+		if err != nil {
+			return field{}, fmt.Errorf("%w: {%s}", ErrUnknown, expr)
+		}
+		name, adjust = expr[:i], n
+	}
+	if name == "len" {
+		w := spec.LenWidth
+		if w == 0 {
+			w = 1
+		}
+		if w < 1 || w > 4 {
+			return field{}, fmt.Errorf("payload: length width must be 1-4, got %d", w)
+		}
+		return field{name: name, adjust: adjust, width: w}, nil
+	}
+	if name == "sum" {
+		name = spec.Checksum
+		if name == "" {
+			name = "sum8"
+		}
+	}
+	w, ok := checksumWidth[name]
+	if !ok || adjust != 0 {
+		return field{}, fmt.Errorf("%w: {%s}", ErrUnknown, expr)
+	}
+	return field{name: name, width: w}, nil
+}
+
+// resolve 计算并填入帧字段
+func resolve(out []byte, fields []field, spec FrameSpec) error {
+	lenPos := -1
+	start := spec.BodyStart
+	for _, f := range fields {
+		if f.name == "len" {
+			lenPos = f.pos
+			if start == 0 {
+				start = f.pos + f.width
+			}
+			break
+		}
+	}
+	end := len(out)
+	for _, f := range fields {
+		if f.name != "len" && f.pos >= start {
+			end = f.pos
+			break
+		}
+	}
+	if start > end {
+		return fmt.Errorf("payload: frame body starts at %d after its end %d", start, end)
+	}
+
+	order := binary.ByteOrder(binary.BigEndian)
+	if spec.Endian == "little" {
+		order = binary.LittleEndian
+	}
+	for _, f := range fields {
+		if f.name != "len" {
+			continue
+		}
+		n := end - start + spec.LenAdjust + f.adjust
+		if n < 0 || uint64(n) >= 1<<(8*f.width) {
+			return fmt.Errorf("payload: length %d does not fit in %d byte(s)", n, f.width)
+		}
+		b := putUint(uint64(n), f.width)
+		if order == binary.ByteOrder(binary.LittleEndian) {
+			for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+				b[i], b[j] = b[j], b[i]
+			}
+		}
+		copy(out[f.pos:], b)
+	}
+
+	from := start
+	switch spec.SumFrom {
+	case "", "body":
+	case "len":
+		if lenPos >= 0 {
+			from = lenPos
+		}
+	case "start":
+		from = 0
+	default:
+		return fmt.Errorf("payload: unknown checksum start %q", spec.SumFrom)
+	}
+	data := out[from:end]
+	for _, f := range fields {
+		switch f.name {
+		case "sum8", "neg8", "sum16":
+			var sum uint64
+			for _, c := range data {
+				sum += uint64(c)
+			}
+			switch f.name {
+			case "sum8":
+				out[f.pos] = byte(sum)
+			case "neg8":
+				out[f.pos] = byte(-sum)
+			default:
+				order.PutUint16(out[f.pos:], uint16(sum))
+			}
+		case "xor8":
+			var x byte
+			for _, c := range data {
+				x ^= c
+			}
+			out[f.pos] = x
+		case "crc16":
+			binary.LittleEndian.PutUint16(out[f.pos:], CRC16(data))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package payload
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"serial-assistant/pkg/convert"
+)
+
+// 占位符语法 {{name}} 或 {{name(args)}}，支持：
+//
+//	timestamp      Unix 秒；文本模式为十进制，十六进制模式为 4 字节大端
+//	counter[(n)]   每次发送递增的计数；十六进制模式为 n 字节大端 (默认 1)
+//	random(a,b)    [a, b] 间的随机整数；十六进制模式按 b 的大小取 1/2/4 字节
+//	crc16[(data)]  Modbus CRC-16 (低字节在前)；data 省略时对占位符之前的全部字节计算
+//	env.VAR        环境变量 VAR 的值 (原样插入)
+//	var.NAME       会话变量 NAME 的值 (原样插入，见 SetVars)
+//	time.FIELD     当前本地时间的字段，utc.FIELD 为 UTC 时间 (见 time.go)，用于向设备下发时间
+//
+// 十六进制模式下还可使用单花括号的帧字段 {len}、{sum} 等，发送时按 FrameSpec 计算 (见 frame.go)
+const (
+	openDelim  = "{{"
+	closeDelim = "}}"
+)
+
+// 展开错误
+var (
+	ErrUnterminated = errors.New("payload: unterminated placeholder")
+	ErrUnknown      = errors.New("payload: unknown placeholder")
+)
+
+// Expander 展开发送内容中的占位符，计数器在多次发送之间保持
+type Expander struct {
+	mu      sync.Mutex
+	counter uint64
+	rand    *rand.Rand
+
+	now       func() time.Time
+	getenv    func(string) (string, bool)
+	lookupVar func(string) (string, bool)
+}
+
+// New 创建展开器
+func New() *Expander {
+	return &Expander{
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		now:    time.Now,
+		getenv: os.LookupEnv,
+	}
+}
+
+// Expand 展开 s 并推进计数器；hex 为 true 时占位符之外的内容按十六进制解析
+// spec 为帧字段的计算方式，零值使用默认设置
+func (e *Expander) Expand(s string, hex bool, spec FrameSpec) ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out, used, err := e.expandLocked(s, hex, e.counter, spec)
+	if err == nil && used {
+		e.counter++
+	}
+	return out, err
+}
+
+// Preview 展开 s 但不推进计数器，用于发送前预览
+func (e *Expander) Preview(s string, hex bool, spec FrameSpec) ([]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out, _, err := e.expandLocked(s, hex, e.counter, spec)
+	return out, err
+}
+
+// SetVars 设置 {{var.NAME}} 的取值函数，nil 表示不支持会话变量
+func (e *Expander) SetVars(lookup func(name string) (string, bool)) {
+	e.mu.Lock()
+	e.lookupVar = lookup
+	e.mu.Unlock()
+}
+
+// ResetCounter 将计数器归零
+func (e *Expander) ResetCounter() {
+	e.mu.Lock()
+	e.counter = 0
+	e.mu.Unlock()
+}
+
+// Has 内容中是否包含占位符
+func Has(s string) bool {
+	return strings.Contains(s, openDelim)
+}
+
+// expandLocked 返回展开结果及是否用到了计数器
+func (e *Expander) expandLocked(s string, hex bool, counter uint64, spec FrameSpec) ([]byte, bool, error) {
+	var out []byte
+	var fields []field
+	used := false
+	for {
+		i := strings.Index(s, openDelim)
+		if i < 0 {
+			break
+		}
+		var err error
+		out, err = appendLiteral(out, s[:i], hex, &fields, spec)
+		if err != nil {
+			return nil, false, err
+		}
+		j := strings.Index(s[i:], closeDelim)
+		if j < 0 {
+			return nil, false, fmt.Errorf("%w: %q", ErrUnterminated, s[i:])
+		}
+		expr := strings.TrimSpace(s[i+len(openDelim) : i+j])
+		s = s[i+j+len(closeDelim):]
+
+		name, args := split(expr)
+		var b []byte
+		switch {
+		case name == "timestamp" && args == nil:
+			ts := e.now().Unix()
+			if hex {
+				b = binary.BigEndian.AppendUint32(nil, uint32(ts))
+			} else {
+				b = []byte(strconv.FormatInt(ts, 10))
+			}
+		case name == "counter":
+			used = true
+			b, err = counterBytes(counter, args, hex)
+		case name == "random":
+			b, err = e.random(args, hex)
+		case name == "crc16":
+			data := out
+			if len(args) > 0 {
+				data, err = parseLiteral(strings.Join(args, ","), hex)
+			}
+			b = binary.LittleEndian.AppendUint16(nil, CRC16(data))
+		case strings.HasPrefix(name, "time.") && args == nil:
+			b, err = timeField(e.now(), name[len("time."):], hex)
+		case strings.HasPrefix(name, "utc.") && args == nil:
+			b, err = timeField(e.now().UTC(), name[len("utc."):], hex)
+		case strings.HasPrefix(name, "env.") && args == nil:
+			v, ok := e.getenv(name[len("env."):])
+			if !ok {
+				return nil, false, fmt.Errorf("payload: environment variable %s is not set", name[len("env."):])
+			}
+			b = []byte(v)
+		case strings.HasPrefix(name, "var.") && args == nil && e.lookupVar != nil:
+			v, ok := e.lookupVar(name[len("var."):])
+			if !ok {
+				return nil, false, fmt.Errorf("payload: session variable %s is not set", name[len("var."):])
+			}
+			b = []byte(v)
+		default:
+			return nil, false, fmt.Errorf("%w: {{%s}}", ErrUnknown, expr)
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("payload: {{%s}}: %w", expr, err)
+		}
+		out = append(out, b...)
+	}
+	out, err := appendLiteral(out, s, hex, &fields, spec)
+	if err == nil && len(fields) > 0 {
+		err = resolve(out, fields, spec)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return out, used, nil
+}
+
+// split 拆分 "name(a, b)" 为名称与参数，没有括号时参数为 nil
+func split(expr string) (string, []string) {
+	i := strings.IndexByte(expr, '(')
+	if i < 0 || !strings.HasSuffix(expr, ")") {
+		return expr, nil
+	}
+	args := []string{}
+	if inner := strings.TrimSpace(expr[i+1 : len(expr)-1]); inner != "" {
+		for _, a := range strings.Split(inner, ",") {
+			args = append(args, strings.TrimSpace(a))
+		}
+	}
+	return strings.TrimSpace(expr[:i]), args
+}
+
+// appendLiteral 将占位符之外的内容追加到 out；十六进制模式下帧字段先以 0 占位，最后由 resolve 填入
+func appendLiteral(out []byte, s string, hex bool, fields *[]field, spec FrameSpec) ([]byte, error) {
+	for hex {
+		i := strings.IndexByte(s, '{')
+		if i < 0 {
+			break
+		}
+		b, err := parseLiteral(s[:i], true)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b...)
+		j := strings.IndexByte(s[i:], '}')
+		if j < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrUnterminated, s[i:])
+		}
+		f, err := parseField(s[i+1:i+j], spec)
+		if err != nil {
+			return nil, err
+		}
+		f.pos = len(out)
+		out = append(out, make([]byte, f.width)...)
+		*fields = append(*fields, f)
+		s = s[i+j+1:]
+	}
+	b, err := parseLiteral(s, hex)
+	if err != nil {
+		return nil, err
+	}
+	return append(out, b...), nil
+}
+
+// parseLiteral 解析不含占位符的内容
+func parseLiteral(s string, hex bool) ([]byte, error) {
+	if !hex {
+		return []byte(s), nil
+	}
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	return convert.ParseHex(s)
+}
+
+func counterBytes(n uint64, args []string, hex bool) ([]byte, error) {
+	if !hex {
+		return []byte(strconv.FormatUint(n, 10)), nil
+	}
+	width := 1
+	if len(args) == 1 {
+		w, err := strconv.Atoi(args[0])
+		if err != nil || w < 1 || w > 8 {
+			return nil, fmt.Errorf("width must be 1-8, got %q", args[0])
+		}
+		width = w
+	} else if len(args) > 1 {
+		return nil, errors.New("expects at most one argument")
+	}
+	return putUint(n, width), nil
+}
+
+func (e *Expander) random(args []string, hex bool) ([]byte, error) {
+	if len(args) != 2 {
+		return nil, errors.New("expects two arguments")
+	}
+	lo, err1 := strconv.ParseInt(args[0], 0, 64)
+	hi, err2 := strconv.ParseInt(args[1], 0, 64)
+	if err1 != nil || err2 != nil || lo < 0 || hi < lo || hi > 0xFFFFFFFF {
+		return nil, fmt.Errorf("invalid range %s..%s", args[0], args[1])
+	}
+	v := lo + e.rand.Int63n(hi-lo+1)
+	if !hex {
+		return []byte(strconv.FormatInt(v, 10)), nil
+	}
+	width := 4
+	switch {
+	case hi <= 0xFF:
+		width = 1
+	case hi <= 0xFFFF:
+		width = 2
+	}
+	return putUint(uint64(v), width), nil
+}
+
+// putUint 以 width 字节大端写入 v 的低位
+func putUint(v uint64, width int) []byte {
+	b := binary.BigEndian.AppendUint64(nil, v)
+	return b[8-width:]
+}
+
+// CRC16 Modbus CRC-16 (多项式 0xA001，初值 0xFFFF)
+func CRC16(b []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, c := range b {
+		crc ^= uint16(c)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
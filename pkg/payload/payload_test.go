@@ -0,0 +1,159 @@
+package payload
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func fixed() *Expander {
+	e := New()
+	e.now = func() time.Time { return time.Unix(0x01020304, 0) }
+	e.getenv = func(k string) (string, bool) {
+		if k == "DEVICE" {
+			return "dev1", true
+		}
+		return "", false
+	}
+	return e
+}
+
+func TestExpandHex(t *testing.T) {
+	e := fixed()
+	cases := map[string][]byte{
+		"01 03 00 00 00 01 {{crc16}}":     {0x01, 0x03, 0x00, 0x00, 0x00, 0x01, 0x84, 0x0A},
+		"AA {{crc16(01 03 00 00 00 01)}}": {0xAA, 0x84, 0x0A},
+		"{{timestamp}}":                   {0x01, 0x02, 0x03, 0x04},
+		"55 {{counter(2)}}":               {0x55, 0x00, 0x00},
+	}
+	for in, want := range cases {
+		got, err := e.Preview(in, true, FrameSpec{})
+		if err != nil || !bytes.Equal(got, want) {
+			t.Errorf("%q: got % X (%v), want % X", in, got, err, want)
+		}
+	}
+}
+
+func TestExpandText(t *testing.T) {
+	e := fixed()
+	got, err := e.Expand("AT+ID={{env.DEVICE}},{{counter}},{{timestamp}}\r\n", false, FrameSpec{})
+	if err != nil || string(got) != "AT+ID=dev1,0,16909060\r\n" {
+		t.Errorf("Unexpected expansion %q (%v)", got, err)
+	}
+	got, _ = e.Expand("{{counter}}", false, FrameSpec{})
+	if string(got) != "1" {
+		t.Errorf("Counter should advance after Expand, got %q", got)
+	}
+	e.Preview("{{counter}}", false, FrameSpec{})
+	if got, _ := e.Expand("{{counter}}", false, FrameSpec{}); string(got) != "2" {
+		t.Errorf("Preview should not advance the counter, got %q", got)
+	}
+	e.ResetCounter()
+	if got, _ := e.Preview("{{ counter }}", false, FrameSpec{}); string(got) != "0" {
+		t.Errorf("Expected counter reset, got %q", got)
+	}
+}
+
+func TestSessionVars(t *testing.T) {
+	e := fixed()
+	if _, err := e.Expand("{{var.serial}}", false, FrameSpec{}); !errors.Is(err, ErrUnknown) {
+		t.Errorf("Without SetVars var.* should be unknown, got %v", err)
+	}
+	e.SetVars(func(name string) (string, bool) {
+		if name == "serial" {
+			return "SN42", true
+		}
+		return "", false
+	})
+	got, err := e.Expand("SET ID {{var.serial}}\r", false, FrameSpec{})
+	if err != nil || string(got) != "SET ID SN42\r" {
+		t.Errorf("Expand = %q, %v", got, err)
+	}
+	if _, err := e.Expand("{{var.missing}}", false, FrameSpec{}); err == nil {
+		t.Error("Expected error for unset variable")
+	}
+}
+
+func TestTimeFields(t *testing.T) {
+	e := fixed()
+	zone := time.FixedZone("CST", 8*3600)
+	e.now = func() time.Time { return time.Date(2026, 10, 15, 9, 5, 7, 250e6, zone) }
+
+	got, err := e.Preview(`AT+CCLK="{{time.yy}}/{{time.month}}/{{time.day}},{{time.hour}}:{{time.minute}}:{{time.second}}{{time.tzq}}"`, false, FrameSpec{})
+	if err != nil || string(got) != `AT+CCLK="26/10/15,09:05:07+32"` {
+		t.Errorf("Unexpected AT+CCLK expansion %q (%v)", got, err)
+	}
+	got, _ = e.Preview("{{utc.iso}} {{time.tz}} {{time.ms}} {{time.weekday}}", false, FrameSpec{})
+	if string(got) != "2026-10-15T01:05:07Z +08:00 250 4" {
+		t.Errorf("Unexpected expansion %q", got)
+	}
+	got, err = e.Preview("A5 {{time.year}} {{time.month}} {{time.day}} {{utc.hour}}", true, FrameSpec{})
+	if want := []byte{0xA5, 0x07, 0xEA, 10, 15, 1}; err != nil || !bytes.Equal(got, want) {
+		t.Errorf("Got % X (%v), want % X", got, err, want)
+	}
+	if _, err := e.Preview("{{time.fortnight}}", false, FrameSpec{}); err == nil {
+		t.Error("Unknown time field should fail")
+	}
+}
+
+func TestRandomRange(t *testing.T) {
+	e := New()
+	for i := 0; i < 100; i++ {
+		b, err := e.Expand("{{random(10,12)}}", true, FrameSpec{})
+		if err != nil || len(b) != 1 || b[0] < 10 || b[0] > 12 {
+			t.Fatalf("Out of range: % X (%v)", b, err)
+		}
+	}
+	if b, _ := e.Expand("{{random(0,1000)}}", true, FrameSpec{}); len(b) != 2 {
+		t.Errorf("Expected two bytes for a 16-bit range, got % X", b)
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	e := fixed()
+	for _, in := range []string{"{{nope}}", "AA {{crc16", "{{env.MISSING}}", "{{random(5)}}", "ZZ {{counter}}"} {
+		if _, err := e.Expand(in, true, FrameSpec{}); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+	if _, err := e.Expand("{{nope}}", false, FrameSpec{}); !errors.Is(err, ErrUnknown) {
+		t.Errorf("Expected ErrUnknown, got %v", err)
+	}
+}
+
+func TestFrameFields(t *testing.T) {
+	e := New()
+	cases := []struct {
+		in   string
+		spec FrameSpec
+		want []byte
+	}{
+		// 帧体为 {len} 之后、{sum} 之前的 01 02 03
+		{"AA 55 {len} 01 02 03 {sum}", FrameSpec{}, []byte{0xAA, 0x55, 0x03, 0x01, 0x02, 0x03, 0x06}},
+		{"AA {len+1} 01 02 {xor8}", FrameSpec{}, []byte{0xAA, 0x03, 0x01, 0x02, 0x03}},
+		{"AA {len} 10 20 {sum}", FrameSpec{LenWidth: 2, Endian: "little", Checksum: "neg8", SumFrom: "len"},
+			[]byte{0xAA, 0x02, 0x00, 0x10, 0x20, 0xCE}},
+		{"01 03 00 00 00 01 {crc16}", FrameSpec{}, []byte{0x01, 0x03, 0x00, 0x00, 0x00, 0x01, 0x84, 0x0A}},
+		{"68 {{counter}} 01 {sum16}", FrameSpec{BodyStart: 1}, []byte{0x68, 0x00, 0x01, 0x00, 0x01}},
+	}
+	for _, c := range cases {
+		got, err := e.Preview(c.in, true, c.spec)
+		if err != nil || !bytes.Equal(got, c.want) {
+			t.Errorf("%q: got % X (%v), want % X", c.in, got, err, c.want)
+		}
+	}
+
+	for _, in := range []string{"AA {len} {bogus}", "AA {len", "{sum+1}"} {
+		if _, err := e.Preview(in, true, FrameSpec{}); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+	if _, err := e.Preview("{len} 01", true, FrameSpec{LenAdjust: 300}); err == nil {
+		t.Error("Expected an overflow error for a one-byte length")
+	}
+	// 文本模式下单花括号保持原样
+	if got, _ := e.Preview("{len}", false, FrameSpec{}); string(got) != "{len}" {
+		t.Errorf("Text mode should not expand frame fields, got %q", got)
+	}
+}
@@ -0,0 +1,71 @@
+package payload
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// timeField 展开 {{time.FIELD}} (本地时间) 与 {{utc.FIELD}} (UTC)
+//
+//	unix / unixms          Unix 秒 / 毫秒；十六进制模式为 4 / 8 字节大端
+//	iso                    RFC 3339 (含时区)
+//	year yy month day      文本模式补零到 4/2 位；十六进制模式 year 为 2 字节，其余 1 字节
+//	hour minute second ms  同上，ms 文本模式 3 位、十六进制模式 2 字节
+//	weekday                0 = 星期日
+//	tz                     时区偏移 "+08:00"
+//	tzq                    以 15 分钟为单位的时区偏移 "+32" (3GPP AT+CCLK 格式)
+func timeField(t time.Time, field string, hex bool) ([]byte, error) {
+	pad := func(v, width int) []byte {
+		if hex {
+			if width == 4 || width == 3 {
+				return binary.BigEndian.AppendUint16(nil, uint16(v))
+			}
+			return []byte{byte(v)}
+		}
+		return []byte(fmt.Sprintf("%0*d", width, v))
+	}
+	_, offset := t.Zone()
+	switch field {
+	case "unix":
+		if hex {
+			return binary.BigEndian.AppendUint32(nil, uint32(t.Unix())), nil
+		}
+		return []byte(strconv.FormatInt(t.Unix(), 10)), nil
+	case "unixms":
+		if hex {
+			return binary.BigEndian.AppendUint64(nil, uint64(t.UnixMilli())), nil
+		}
+		return []byte(strconv.FormatInt(t.UnixMilli(), 10)), nil
+	case "iso":
+		return []byte(t.Format(time.RFC3339)), nil
+	case "year":
+		return pad(t.Year(), 4), nil
+	case "yy":
+		return pad(t.Year()%100, 2), nil
+	case "month":
+		return pad(int(t.Month()), 2), nil
+	case "day":
+		return pad(t.Day(), 2), nil
+	case "hour":
+		return pad(t.Hour(), 2), nil
+	case "minute":
+		return pad(t.Minute(), 2), nil
+	case "second":
+		return pad(t.Second(), 2), nil
+	case "ms":
+		return pad(t.Nanosecond()/int(time.Millisecond), 3), nil
+	case "weekday":
+		return pad(int(t.Weekday()), 1), nil
+	case "tz":
+		return []byte(t.Format("-07:00")), nil
+	case "tzq":
+		sign := byte('+')
+		if offset < 0 {
+			sign, offset = '-', -offset
+		}
+		return append([]byte{sign}, fmt.Sprintf("%02d", offset/(15*60))...), nil
+	}
+	return nil, fmt.Errorf("unknown time field %q", field)
+}
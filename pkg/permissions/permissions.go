@@ -0,0 +1,104 @@
+package permissions
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Report 串口访问权限诊断结果
+type Report struct {
+	Port       string `json:"port"`
+	Exists     bool   `json:"exists"`
+	Accessible bool   `json:"accessible"` // 当前进程可读写该设备
+
+	// 设备文件所属的组 (如 dialout、uucp) 及当前用户与其关系
+	Group        string `json:"group,omitempty"`
+	UserInGroup  bool   `json:"userInGroup"`  // 组配置中已包含当前用户
+	NeedsRelogin bool   `json:"needsRelogin"` // 已加入组但当前登录会话尚未生效
+
+	// 建议用户执行的命令，按推荐顺序排列
+	Commands []string `json:"commands,omitempty"`
+
+	// 可安装的 udev 规则 (仅 Linux 的 USB 设备)
+	UdevRule string `json:"udevRule,omitempty"`
+}
+
+// defaultGroups 各平台常见的串口设备组，设备文件的组无法读取时使用
+var defaultGroups = map[string]string{
+	"linux":  "dialout",
+	"darwin": "uucp",
+}
+
+// guidance 根据诊断结果生成建议执行的命令
+func guidance(r *Report, goos string) []string {
+	if r.Accessible || !r.Exists {
+		return nil
+	}
+	group := r.Group
+	if group == "" {
+		group = defaultGroups[goos]
+	}
+
+	var cmds []string
+	if r.NeedsRelogin {
+		// 只需重新登录，另外给出一个无需注销的临时办法
+		cmds = append(cmds, "newgrp "+group)
+	} else if group != "" && group != "root" && group != "wheel" {
+		switch goos {
+		case "darwin":
+			cmds = append(cmds, fmt.Sprintf("sudo dseditgroup -o edit -a \"$USER\" -t user %s", group))
+		default:
+			cmds = append(cmds, fmt.Sprintf("sudo usermod -aG %s \"$USER\"", group))
+		}
+	}
+	// 立即生效但重新插拔后失效的临时办法
+	cmds = append(cmds, "sudo chmod a+rw "+r.Port)
+	return cmds
+}
+
+// usbIDPattern USB VID/PID 格式 (4 位十六进制)
+var usbIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{4}$`)
+
+// UdevRule 生成授予 group 读写指定 USB 串口设备权限的 udev 规则
+// uaccess 标签使本地登录的用户无需加入组也可访问
+func UdevRule(vid, pid, group string) (string, error) {
+	if !usbIDPattern.MatchString(vid) || !usbIDPattern.MatchString(pid) {
+		return "", fmt.Errorf("invalid USB id %q:%q", vid, pid)
+	}
+	if group == "" {
+		group = defaultGroups["linux"]
+	}
+	return fmt.Sprintf(
+		"SUBSYSTEM==\"tty\", ATTRS{idVendor}==\"%s\", ATTRS{idProduct}==\"%s\", MODE=\"0660\", GROUP=\"%s\", TAG+=\"uaccess\"\n",
+		strings.ToLower(vid), strings.ToLower(pid), group), nil
+}
+
+// udevRulePath 规则文件路径，每个 VID/PID 一个文件，重复安装时覆盖
+func udevRulePath(vid, pid string) string {
+	return fmt.Sprintf("/etc/udev/rules.d/99-serial-mate-%s-%s.rules", strings.ToLower(vid), strings.ToLower(pid))
+}
+
+// InstallUdevRule 通过 pkexec 以管理员权限安装 udev 规则并重新触发设备事件
+// 会弹出系统的认证对话框，可通过 ctx 取消
+func InstallUdevRule(ctx context.Context, vid, pid, group string) error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("udev rules are only supported on linux")
+	}
+	rule, err := UdevRule(vid, pid, group)
+	if err != nil {
+		return err
+	}
+	// 规则内容通过 stdin 传入，命令行中只出现校验过的 VID/PID
+	script := fmt.Sprintf("cat > %s && udevadm control --reload-rules && udevadm trigger --subsystem-match=tty",
+		udevRulePath(vid, pid))
+	cmd := exec.CommandContext(ctx, "pkexec", "sh", "-c", script)
+	cmd.Stdin = strings.NewReader(rule)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("install udev rule: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package permissions
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestUdevRule(t *testing.T) {
+	rule, err := UdevRule("1A86", "7523", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`ATTRS{idVendor}=="1a86"`, `ATTRS{idProduct}=="7523"`, `GROUP="dialout"`, `TAG+="uaccess"`} {
+		if !strings.Contains(rule, want) {
+			t.Errorf("Rule %q missing %q", rule, want)
+		}
+	}
+}
+
+func TestUdevRuleRejectsInvalidIDs(t *testing.T) {
+	for _, id := range []string{"", "12345", "1a8g", "1a\"6"} {
+		if _, err := UdevRule(id, "7523", "dialout"); err == nil {
+			t.Errorf("Expected error for vid %q", id)
+		}
+	}
+}
+
+func TestGuidance(t *testing.T) {
+	r := &Report{Port: "/dev/ttyUSB0", Exists: true, Group: "uucp"}
+	cmds := guidance(r, "linux")
+	if len(cmds) != 2 || !strings.Contains(cmds[0], "usermod -aG uucp") {
+		t.Errorf("Unexpected linux guidance: %v", cmds)
+	}
+
+	r.NeedsRelogin = true
+	if cmds := guidance(r, "linux"); cmds[0] != "newgrp uucp" {
+		t.Errorf("Expected newgrp hint, got %v", cmds)
+	}
+
+	r = &Report{Port: "/dev/cu.usbserial", Exists: true}
+	if cmds := guidance(r, "darwin"); !strings.Contains(cmds[0], "dseditgroup") || !strings.Contains(cmds[0], "uucp") {
+		t.Errorf("Unexpected darwin guidance: %v", cmds)
+	}
+
+	r.Accessible = true
+	if cmds := guidance(r, "darwin"); cmds != nil {
+		t.Errorf("Accessible port should need no guidance, got %v", cmds)
+	}
+}
+
+func TestDiagnose(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no file permissions on windows")
+	}
+	if r := Diagnose("/dev/does-not-exist-serial"); r.Exists || r.Commands != nil {
+		t.Errorf("Missing port should not exist: %+v", r)
+	}
+
+	path := filepath.Join(t.TempDir(), "tty")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r := Diagnose(path)
+	if !r.Exists || !r.Accessible || r.Commands != nil {
+		t.Errorf("Own file should be accessible: %+v", r)
+	}
+}
@@ -0,0 +1,68 @@
+//go:build !windows
+
+package permissions
+
+import (
+	"os"
+	"os/user"
+	"runtime"
+	"strconv"
+	"syscall"
+)
+
+// Diagnose 检查当前进程能否读写串口设备，并分析缺少的组权限
+func Diagnose(port string) Report {
+	r := Report{Port: port}
+
+	info, err := os.Stat(port)
+	if err != nil {
+		return r
+	}
+	r.Exists = true
+	r.Accessible = syscall.Access(port, 0x2|0x4) == nil // W_OK|R_OK
+
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		gid := strconv.FormatUint(uint64(st.Gid), 10)
+		if g, err := user.LookupGroupId(gid); err == nil {
+			r.Group = g.Name
+		}
+		r.UserInGroup, r.NeedsRelogin = membership(gid)
+	}
+
+	r.Commands = guidance(&r, runtime.GOOS)
+	return r
+}
+
+// membership 返回组配置中是否包含当前用户，以及是否需要重新登录才能生效
+func membership(gid string) (configured, needsRelogin bool) {
+	inSession := false
+	if n, err := strconv.Atoi(gid); err == nil {
+		if groups, err := os.Getgroups(); err == nil {
+			for _, g := range groups {
+				if g == n {
+					inSession = true
+					break
+				}
+			}
+		}
+		if os.Getegid() == n {
+			inSession = true
+		}
+	}
+
+	u, err := user.Current()
+	if err != nil {
+		return inSession, false
+	}
+	ids, err := u.GroupIds()
+	if err != nil {
+		return inSession, false
+	}
+	for _, id := range ids {
+		if id == gid {
+			configured = true
+			break
+		}
+	}
+	return configured || inSession, configured && !inSession
+}
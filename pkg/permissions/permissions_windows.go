@@ -0,0 +1,8 @@
+//go:build windows
+
+package permissions
+
+// Diagnose Windows 下串口没有文件权限的概念，无法打开通常是被其他程序占用
+func Diagnose(port string) Report {
+	return Report{Port: port, Exists: true, Accessible: true}
+}
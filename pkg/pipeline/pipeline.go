@@ -0,0 +1,171 @@
+package pipeline
+
+import (
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"serial-assistant/pkg/eventbus"
+)
+
+// StageStats 一个处理阶段的统计
+type StageStats struct {
+	Session   string  `json:"session"`
+	Name      string  `json:"name"`
+	QueueLen  int     `json:"queueLen"` // 当前排队的事件数
+	QueueCap  int     `json:"queueCap"`
+	Dropped   uint64  `json:"dropped"` // 因队列已满丢弃的事件数
+	Processed uint64  `json:"processed"`
+	BusyMs    int64   `json:"busyMs"`    // 处理事件累计耗时
+	Busy      float64 `json:"busy"`      // 处理耗时占运行时间的比例 (0~1)，接近 1 说明该阶段是瓶颈
+	AvgMicros float64 `json:"avgMicros"` // 单个事件平均处理耗时
+	Panics    uint64  `json:"panics"`    // 处理事件时发生并已恢复的 panic 次数
+}
+
+// Stage 订阅事件总线的一个处理阶段，独占一个 goroutine 和一个有界队列
+// Go 无法按 goroutine 统计 CPU 时间，这里以处理事件的耗时近似
+type Stage struct {
+	session string
+	name    string
+	sub     *eventbus.Subscription
+	started time.Time
+
+	processed atomic.Uint64
+	busy      atomic.Int64 // 纳秒
+	panics    atomic.Uint64
+	registry  *Registry
+}
+
+// Begin 开始处理一个事件，返回的函数在处理结束时调用
+func (s *Stage) Begin() func() {
+	start := time.Now()
+	return func() {
+		s.busy.Add(int64(time.Since(start)))
+		s.processed.Add(1)
+	}
+}
+
+// Run 循环处理队列中的事件直到订阅关闭，处理单个事件时的 panic 会被恢复并报告
+func (s *Stage) Run(handle func(eventbus.Event)) {
+	for ev := range s.sub.C {
+		done := s.Begin()
+		s.Guard(func() { handle(ev) })
+		done()
+	}
+}
+
+// Guard 执行 fn，恢复其中的 panic 并交给注册表的 PanicHandler，阶段继续处理后续事件
+// 供通过 C 自行循环的阶段包裹单个事件的处理
+func (s *Stage) Guard(fn func()) {
+	defer func() {
+		if v := recover(); v != nil {
+			s.panics.Add(1)
+			if s.registry != nil {
+				s.registry.panicked(s.name, v, debug.Stack())
+			}
+		}
+	}()
+	fn()
+}
+
+// C 返回事件通道，供需要同时等待其他信号 (如定时器) 的阶段自行循环
+func (s *Stage) C() <-chan eventbus.Event {
+	return s.sub.C
+}
+
+// Stats 返回统计信息
+func (s *Stage) Stats() StageStats {
+	st := StageStats{
+		Session:   s.session,
+		Name:      s.name,
+		QueueLen:  s.sub.Len(),
+		QueueCap:  s.sub.Cap(),
+		Dropped:   s.sub.Dropped(),
+		Processed: s.processed.Load(),
+		Panics:    s.panics.Load(),
+	}
+	busy := time.Duration(s.busy.Load())
+	st.BusyMs = busy.Milliseconds()
+	if elapsed := time.Since(s.started); elapsed > 0 {
+		st.Busy = float64(busy) / float64(elapsed)
+	}
+	if st.Processed > 0 {
+		st.AvgMicros = float64(busy.Microseconds()) / float64(st.Processed)
+	}
+	return st
+}
+
+// PanicHandler 处理阶段中恢复的 panic，stack 为发生 panic 的 goroutine 栈
+type PanicHandler func(stage string, v any, stack []byte)
+
+// Registry 管理各会话的处理阶段
+type Registry struct {
+	mu      sync.Mutex
+	stages  []*Stage
+	onPanic PanicHandler
+}
+
+// SetPanicHandler 设置阶段中发生 panic 时的回调
+func (r *Registry) SetPanicHandler(fn PanicHandler) {
+	r.mu.Lock()
+	r.onPanic = fn
+	r.mu.Unlock()
+}
+
+// panicked 调用 PanicHandler
+func (r *Registry) panicked(stage string, v any, stack []byte) {
+	r.mu.Lock()
+	fn := r.onPanic
+	r.mu.Unlock()
+	if fn != nil {
+		fn(stage, v, stack)
+	}
+}
+
+// NewRegistry 创建注册表
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Add 为会话注册一个处理阶段
+func (r *Registry) Add(session, name string, sub *eventbus.Subscription) *Stage {
+	s := &Stage{session: session, name: name, sub: sub, started: time.Now(), registry: r}
+	r.mu.Lock()
+	r.stages = append(r.stages, s)
+	r.mu.Unlock()
+	return s
+}
+
+// Remove 注销会话的所有处理阶段 (会话结束时调用)
+func (r *Registry) Remove(session string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.stages[:0]
+	for _, s := range r.stages {
+		if s.session != session {
+			kept = append(kept, s)
+		}
+	}
+	r.stages = kept
+}
+
+// Stats 返回所有阶段的统计，按会话和名称排序
+func (r *Registry) Stats() []StageStats {
+	r.mu.Lock()
+	stages := append([]*Stage(nil), r.stages...)
+	r.mu.Unlock()
+
+	list := make([]StageStats, 0, len(stages))
+	for _, s := range stages {
+		list = append(list, s.Stats())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Session != list[j].Session {
+			return list[i].Session < list[j].Session
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
@@ -0,0 +1,103 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/eventbus"
+)
+
+func TestStageStats(t *testing.T) {
+	bus := eventbus.New()
+	r := NewRegistry()
+	fast := r.Add("main", "fast", bus.Subscribe(16, "data"))
+	slow := r.Add("main", "slow", bus.Subscribe(2, "data"))
+
+	done := make(chan struct{})
+	go func() {
+		fast.Run(func(eventbus.Event) {})
+		close(done)
+	}()
+
+	for i := 0; i < 5; i++ {
+		bus.Publish("data", i)
+	}
+	// slow 阶段未处理事件：队列满后其余事件被丢弃
+	st := slow.Stats()
+	if st.QueueLen != 2 || st.QueueCap != 2 || st.Dropped != 3 || st.Processed != 0 {
+		t.Errorf("Unexpected slow stage stats %+v", st)
+	}
+
+	bus.Close()
+	<-done
+	if st := fast.Stats(); st.Processed != 5 || st.Dropped != 0 || st.QueueLen != 0 {
+		t.Errorf("Unexpected fast stage stats %+v", st)
+	}
+}
+
+func TestBeginMeasuresBusyTime(t *testing.T) {
+	r := NewRegistry()
+	s := r.Add("main", "stage", eventbus.New().Subscribe(1))
+	done := s.Begin()
+	time.Sleep(20 * time.Millisecond)
+	done()
+
+	st := s.Stats()
+	if st.Processed != 1 || st.BusyMs < 20 || st.Busy <= 0 || st.AvgMicros < 20000 {
+		t.Errorf("Unexpected stats %+v", st)
+	}
+}
+
+func TestRegistryRemoveSession(t *testing.T) {
+	bus := eventbus.New()
+	r := NewRegistry()
+	r.Add("b", "capture", bus.Subscribe(1))
+	r.Add("a", "frontend", bus.Subscribe(1))
+	r.Add("b", "decoder", bus.Subscribe(1))
+
+	list := r.Stats()
+	if len(list) != 3 || list[0].Session != "a" || list[1].Name != "capture" {
+		t.Errorf("Unexpected order %+v", list)
+	}
+	r.Remove("b")
+	if list := r.Stats(); len(list) != 1 || list[0].Session != "a" {
+		t.Errorf("Expected only session a to remain, got %+v", list)
+	}
+}
+
+func TestRunRecoversPanics(t *testing.T) {
+	bus := eventbus.New()
+	r := NewRegistry()
+	var stage string
+	var value any
+	r.SetPanicHandler(func(name string, v any, stack []byte) {
+		stage, value = name, v
+		if len(stack) == 0 {
+			t.Error("Expected a stack trace")
+		}
+	})
+	s := r.Add("main", "decoder", bus.Subscribe(16, "data"))
+	handled := 0
+	done := make(chan struct{})
+	go func() {
+		s.Run(func(ev eventbus.Event) {
+			if ev.Payload == 1 {
+				panic("boom")
+			}
+			handled++
+		})
+		close(done)
+	}()
+	for i := 0; i < 3; i++ {
+		bus.Publish("data", i)
+	}
+	bus.Close()
+	<-done
+
+	if handled != 2 || stage != "decoder" || value != "boom" {
+		t.Errorf("handled=%d stage=%q value=%v", handled, stage, value)
+	}
+	if st := s.Stats(); st.Panics != 1 || st.Processed != 3 {
+		t.Errorf("Unexpected stats %+v", st)
+	}
+}
@@ -0,0 +1,57 @@
+package plc
+
+import (
+	"time"
+
+	"serial-assistant/pkg/decoder"
+)
+
+// frameDecoder 按协议的帧边界切分数据并描述每一帧
+type frameDecoder struct {
+	p   Protocol
+	buf []byte
+}
+
+func (d *frameDecoder) Name() string { return d.p.Name() }
+
+// Detect 按校验通过的帧覆盖的字节比例计算可信度
+func (d *frameDecoder) Detect(sample []byte) float64 {
+	matched := 0
+	for rest := sample; len(rest) > 0; {
+		skip, n := d.p.Split(rest)
+		if n == 0 {
+			break
+		}
+		if _, _, err := d.p.Describe(rest[skip : skip+n]); err == nil {
+			matched += n
+		}
+		rest = rest[skip+n:]
+	}
+	if len(sample) == 0 {
+		return 0
+	}
+	return float64(matched) / float64(len(sample))
+}
+
+func (d *frameDecoder) Decode(t time.Time, data []byte, emit func(decoder.Frame)) {
+	d.buf = append(d.buf, data...)
+	for {
+		skip, n := d.p.Split(d.buf)
+		if n == 0 {
+			d.buf = d.buf[:copy(d.buf, d.buf[skip:])]
+			if len(d.buf) > maxBuffer {
+				d.buf = d.buf[:0]
+			}
+			return
+		}
+		raw := append([]byte(nil), d.buf[skip:skip+n]...)
+		d.buf = d.buf[skip+n:]
+		f := decoder.Frame{Protocol: d.p.Name(), Time: t, Raw: raw}
+		summary, fields, err := d.p.Describe(raw)
+		f.Summary, f.Fields = summary, fields
+		if err != nil {
+			f.Error = err.Error()
+		}
+		emit(f)
+	}
+}
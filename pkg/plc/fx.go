@@ -0,0 +1,173 @@
+package plc
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"serial-assistant/pkg/decoder"
+)
+
+// 控制字符
+const (
+	stx = 0x02
+	etx = 0x03
+	enq = 0x05
+	ack = 0x06
+	nak = 0x15
+)
+
+// fx 三菱 FX 系列编程口协议
+// 读：STX + '0' + 地址 (4 位十六进制) + 字节数 (2 位十六进制) + ETX + 和校验
+// 写：STX + '1' + 地址 + 字节数 + 数据 + ETX + 和校验，应答 ACK 或 NAK
+// 和校验为 STX 之后到 ETX (含) 所有字节之和的低 8 位，字数据先低字节后高字节
+type fx struct{}
+
+// fxMaxCount 单帧可读写的字数 (64 字节)
+const fxMaxCount = 32
+
+// fxAreas 区域的起始地址、寄存器数量与首个寄存器编号
+var fxAreas = map[string][]struct{ base, first, count int }{
+	"D": {{0x1000, 0, 8000}, {0x0e00, 8000, 256}},
+	"T": {{0x0800, 0, 256}},
+	"C": {{0x0a00, 0, 200}},
+}
+
+func (fx) Name() string { return "melsec_fx" }
+
+func (fx) Areas() []string { return []string{"D", "T", "C"} }
+
+// address 返回寄存器的字节地址
+func (f fx) address(area string, addr, count int) (int, error) {
+	ranges, ok := fxAreas[area]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownArea, area)
+	}
+	for _, r := range ranges {
+		if addr >= r.first && addr < r.first+r.count {
+			if err := checkRange(addr, count, r.first+r.count-1, fxMaxCount); err != nil {
+				return 0, err
+			}
+			return r.base + 2*(addr-r.first), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s%d", ErrRange, area, addr)
+}
+
+func (f fx) frame(body string) []byte {
+	return append([]byte{stx}, fmt.Sprintf("%s\x03%02X", body, sum8([]byte(body+"\x03")))...)
+}
+
+func (f fx) ReadRequest(_ int, area string, addr, count int) ([]byte, error) {
+	a, err := f.address(area, addr, count)
+	if err != nil {
+		return nil, err
+	}
+	return f.frame(fmt.Sprintf("0%04X%02X", a, 2*count)), nil
+}
+
+func (f fx) WriteRequest(_ int, area string, addr int, values []uint16) ([]byte, error) {
+	a, err := f.address(area, addr, len(values))
+	if err != nil {
+		return nil, err
+	}
+	return f.frame(fmt.Sprintf("1%04X%02X%s", a, 2*len(values), formatWords(values, true))), nil
+}
+
+func (fx) Split(buf []byte) (int, int) {
+	start := bytes.IndexAny(buf, "\x02\x05\x06\x15")
+	if start < 0 {
+		return len(buf), 0
+	}
+	if buf[start] != stx {
+		return start, 1
+	}
+	end := bytes.IndexByte(buf[start:], etx)
+	if end < 0 || start+end+3 > len(buf) {
+		return start, 0
+	}
+	return start, end + 3
+}
+
+// body 校验和校验并返回 STX 与 ETX 之间的内容
+func (f fx) body(frame []byte) (string, error) {
+	if len(frame) < 4 || frame[0] != stx || frame[len(frame)-3] != etx {
+		return "", ErrMalformed
+	}
+	return string(frame[1 : len(frame)-3]), checkHex(string(frame[len(frame)-2:]), sum8(frame[1:len(frame)-2]))
+}
+
+func (f fx) ParseRead(frame []byte, count int) ([]uint16, error) {
+	if len(frame) == 1 && frame[0] == nak {
+		return nil, &DeviceError{Protocol: f.Name(), Code: "NAK"}
+	}
+	data, err := f.body(frame)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 4*count {
+		return nil, fmt.Errorf("%w: %d characters", ErrMalformed, len(data))
+	}
+	return parseWords(data, true)
+}
+
+func (f fx) ParseWrite(frame []byte) error {
+	switch {
+	case len(frame) == 1 && frame[0] == ack:
+		return nil
+	case len(frame) == 1 && frame[0] == nak:
+		return &DeviceError{Protocol: f.Name(), Code: "NAK"}
+	}
+	return ErrMalformed
+}
+
+func (f fx) Describe(frame []byte) (string, []decoder.Field, error) {
+	if len(frame) == 1 {
+		kind := map[byte]string{enq: "enq", ack: "ack", nak: "nak"}[frame[0]]
+		return kind, []decoder.Field{{Name: "melsec_fx.type", Value: kind}}, nil
+	}
+	data, err := f.body(frame)
+	if data == "" {
+		return "", nil, ErrMalformed
+	}
+	// 请求为命令字符加偶数个十六进制字符，长度为奇数；读应答只有数据，长度为偶数
+	if len(data)%2 == 0 {
+		summary := "data " + data
+		if values, werr := parseWords(data, true); werr == nil {
+			summary = "data " + wordsSummary(values)
+		}
+		return summary, []decoder.Field{
+			{Name: "melsec_fx.type", Value: "response"},
+			{Name: "melsec_fx.bytes", Value: int64(len(data) / 2)},
+		}, err
+	}
+	fields := []decoder.Field{
+		{Name: "melsec_fx.type", Value: "request"},
+		{Name: "melsec_fx.command", Value: data[:1]},
+	}
+	summary := "command " + data
+	if len(data) >= 7 && (data[0] == '0' || data[0] == '1') {
+		addr, _ := strconv.ParseUint(data[1:5], 16, 16)
+		n, _ := strconv.ParseUint(data[5:7], 16, 8)
+		fields = append(fields,
+			decoder.Field{Name: "melsec_fx.address", Value: int64(addr)},
+			decoder.Field{Name: "melsec_fx.bytes", Value: int64(n)})
+		summary = fmt.Sprintf("read %04X x%d", addr, n)
+		if data[0] == '1' {
+			summary = fmt.Sprintf("write %04X x%d", addr, n)
+			if values, werr := parseWords(data[7:], true); werr == nil {
+				summary += " = " + wordsSummary(values)
+			}
+		}
+	}
+	return summary, fields, err
+}
+
+// sum8 按字节求和取低 8 位
+func sum8(p []byte) byte {
+	var s byte
+	for _, b := range p {
+		s += b
+	}
+	return s
+}
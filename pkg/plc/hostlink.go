@@ -0,0 +1,169 @@
+package plc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"serial-assistant/pkg/decoder"
+)
+
+// hostlink 欧姆龙 Host Link (C 模式)
+// 帧：@ + 单元号 (2 位十进制) + 头代码 + 数据 + FCS + * + CR
+// 应答在头代码后带 2 位结束码，00 表示成功；FCS 为 @ 到数据末尾所有字符的异或，字数据高字节在前
+type hostlink struct{}
+
+// hostlinkMaxCount 单帧可读写的字数 (单帧最长 131 个字符)
+const hostlinkMaxCount = 29
+
+// hostlinkHeaders 区域对应的读、写头代码
+var hostlinkHeaders = map[string][2]string{
+	"CIO": {"RR", "WR"},
+	"LR":  {"RL", "WL"},
+	"HR":  {"RH", "WH"},
+	"AR":  {"RJ", "WJ"},
+	"DM":  {"RD", "WD"},
+}
+
+func (hostlink) Name() string { return "hostlink" }
+
+func (hostlink) Areas() []string { return []string{"CIO", "LR", "HR", "AR", "DM"} }
+
+func (h hostlink) request(unit int, header, data string) []byte {
+	body := fmt.Sprintf("@%02d%s%s", unit, header, data)
+	return []byte(fmt.Sprintf("%s%02X*\r", body, xorChecksum([]byte(body))))
+}
+
+func (h hostlink) header(area string, write bool, unit, addr, count int) (string, error) {
+	headers, ok := hostlinkHeaders[area]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownArea, area)
+	}
+	if unit < 0 || unit > 31 {
+		return "", fmt.Errorf("%w: unit %d", ErrRange, unit)
+	}
+	if err := checkRange(addr, count, 9999, hostlinkMaxCount); err != nil {
+		return "", err
+	}
+	if write {
+		return headers[1], nil
+	}
+	return headers[0], nil
+}
+
+func (h hostlink) ReadRequest(unit int, area string, addr, count int) ([]byte, error) {
+	header, err := h.header(area, false, unit, addr, count)
+	if err != nil {
+		return nil, err
+	}
+	return h.request(unit, header, fmt.Sprintf("%04d%04d", addr, count)), nil
+}
+
+func (h hostlink) WriteRequest(unit int, area string, addr int, values []uint16) ([]byte, error) {
+	header, err := h.header(area, true, unit, addr, len(values))
+	if err != nil {
+		return nil, err
+	}
+	return h.request(unit, header, fmt.Sprintf("%04d%s", addr, formatWords(values, false))), nil
+}
+
+func (hostlink) Split(buf []byte) (int, int) {
+	start := bytes.IndexByte(buf, '@')
+	if start < 0 {
+		return len(buf), 0
+	}
+	end := bytes.Index(buf[start:], []byte("*\r"))
+	if end < 0 {
+		return start, 0
+	}
+	return start, end + 2
+}
+
+// split 校验 FCS 并返回单元号、头代码与数据
+func (h hostlink) split(frame []byte) (int, string, string, error) {
+	s := string(frame)
+	if len(s) < 9 || s[0] != '@' || s[len(s)-2:] != "*\r" {
+		return 0, "", "", ErrMalformed
+	}
+	unit, err := strconv.Atoi(s[1:3])
+	if err != nil {
+		return 0, "", "", ErrMalformed
+	}
+	data := s[5 : len(s)-4]
+	return unit, s[3:5], data, checkHex(s[len(s)-4:len(s)-2], xorChecksum(frame[:len(frame)-4]))
+}
+
+// response 解析应答的结束码
+func (h hostlink) response(frame []byte, header string) (string, error) {
+	_, got, data, err := h.split(frame)
+	if err != nil {
+		return "", err
+	}
+	if got != header || len(data) < 2 {
+		return "", fmt.Errorf("%w: %s", ErrMalformed, got)
+	}
+	if code := data[:2]; code != "00" {
+		return "", &DeviceError{Protocol: h.Name(), Code: code}
+	}
+	return data[2:], nil
+}
+
+func (h hostlink) ParseRead(frame []byte, count int) ([]uint16, error) {
+	if len(frame) < 5 {
+		return nil, ErrMalformed
+	}
+	data, err := h.response(frame, string(frame[3:5]))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 4*count {
+		return nil, fmt.Errorf("%w: %d characters", ErrMalformed, len(data))
+	}
+	return parseWords(data, false)
+}
+
+func (h hostlink) ParseWrite(frame []byte) error {
+	if len(frame) < 5 {
+		return ErrMalformed
+	}
+	_, err := h.response(frame, string(frame[3:5]))
+	return err
+}
+
+func (h hostlink) Describe(frame []byte) (string, []decoder.Field, error) {
+	unit, header, data, err := h.split(frame)
+	if errors.Is(err, ErrMalformed) {
+		return "", nil, err
+	}
+	fields := []decoder.Field{
+		{Name: "hostlink.unit", Value: int64(unit)},
+		{Name: "hostlink.header", Value: header},
+	}
+	// 请求的数据为 4 位地址加若干 4 位字段，应答多出 2 位结束码
+	if len(data)%4 == 2 {
+		code := data[:2]
+		fields = append(fields,
+			decoder.Field{Name: "hostlink.type", Value: "response"},
+			decoder.Field{Name: "hostlink.end_code", Value: code})
+		summary := fmt.Sprintf("@%02d %s end %s", unit, header, code)
+		if values, werr := parseWords(data[2:], false); code == "00" && header[0] == 'R' && werr == nil {
+			summary = fmt.Sprintf("@%02d %s %s", unit, header, wordsSummary(values))
+		}
+		return summary, fields, err
+	}
+	fields = append(fields, decoder.Field{Name: "hostlink.type", Value: "request"})
+	summary := fmt.Sprintf("@%02d %s %s", unit, header, data)
+	if len(data) >= 4 {
+		addr, _ := strconv.Atoi(data[:4])
+		fields = append(fields, decoder.Field{Name: "hostlink.address", Value: int64(addr)})
+		if header[0] == 'R' && len(data) == 8 {
+			count, _ := strconv.Atoi(data[4:])
+			fields = append(fields, decoder.Field{Name: "hostlink.count", Value: int64(count)})
+			summary = fmt.Sprintf("@%02d %s %d x%d", unit, header, addr, count)
+		} else if values, werr := parseWords(data[4:], false); header[0] == 'W' && werr == nil {
+			summary = fmt.Sprintf("@%02d %s %d = %s", unit, header, addr, wordsSummary(values))
+		}
+	}
+	return summary, fields, err
+}
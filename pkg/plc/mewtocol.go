@@ -0,0 +1,166 @@
+package plc
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"serial-assistant/pkg/decoder"
+)
+
+// mewtocol 松下 Mewtocol-COM
+// 请求：% + 站号 (2 位十进制) + # + 命令 + 数据 + BCC + CR
+// 应答：% + 站号 + $ + 命令 + 数据 + BCC + CR；错误应答以 ! 代替 $，后跟 2 位错误码
+// BCC 为 % 到数据末尾所有字符的异或，字数据先低字节后高字节
+type mewtocol struct{}
+
+// mewtocolMaxCount 单帧可读写的字数
+const mewtocolMaxCount = 24
+
+func (mewtocol) Name() string { return "mewtocol" }
+
+func (mewtocol) Areas() []string { return []string{"D", "L", "F"} }
+
+func (m mewtocol) request(station int, cmd, data string) []byte {
+	body := fmt.Sprintf("%%%02d#%s%s", station, cmd, data)
+	return []byte(fmt.Sprintf("%s%02X\r", body, xorChecksum([]byte(body))))
+}
+
+func (m mewtocol) check(area string, station, addr, count int) error {
+	if !contains(m.Areas(), area) {
+		return fmt.Errorf("%w: %s", ErrUnknownArea, area)
+	}
+	if station < 0 || station > 99 {
+		return fmt.Errorf("%w: station %d", ErrRange, station)
+	}
+	return checkRange(addr, count, 99999, mewtocolMaxCount)
+}
+
+func (m mewtocol) ReadRequest(station int, area string, addr, count int) ([]byte, error) {
+	if err := m.check(area, station, addr, count); err != nil {
+		return nil, err
+	}
+	return m.request(station, "RD", fmt.Sprintf("%s%05d%05d", area, addr, addr+count-1)), nil
+}
+
+func (m mewtocol) WriteRequest(station int, area string, addr int, values []uint16) ([]byte, error) {
+	if err := m.check(area, station, addr, len(values)); err != nil {
+		return nil, err
+	}
+	return m.request(station, "WD", fmt.Sprintf("%s%05d%05d%s", area, addr, addr+len(values)-1, formatWords(values, true))), nil
+}
+
+func (mewtocol) Split(buf []byte) (int, int) {
+	start := bytes.IndexByte(buf, '%')
+	if start < 0 {
+		return len(buf), 0
+	}
+	end := bytes.IndexByte(buf[start:], '\r')
+	if end < 0 {
+		return start, 0
+	}
+	return start, end + 1
+}
+
+// parse 校验应答并返回命令与数据
+func (m mewtocol) parse(frame []byte) (string, string, error) {
+	s := string(frame)
+	if len(s) < 7 || s[0] != '%' || s[len(s)-1] != '\r' {
+		return "", "", ErrMalformed
+	}
+	if bcc := s[len(s)-3 : len(s)-1]; bcc != "**" {
+		if err := checkHex(bcc, xorChecksum(frame[:len(frame)-3])); err != nil {
+			return "", "", err
+		}
+	}
+	body := s[4 : len(s)-3]
+	switch s[3] {
+	case '!':
+		return "", "", &DeviceError{Protocol: m.Name(), Code: body}
+	case '$':
+		if len(body) < 2 {
+			return "", "", ErrMalformed
+		}
+		return body[:2], body[2:], nil
+	}
+	return "", "", ErrMalformed
+}
+
+func (m mewtocol) ParseRead(frame []byte, count int) ([]uint16, error) {
+	cmd, data, err := m.parse(frame)
+	if err != nil {
+		return nil, err
+	}
+	if cmd != "RD" || len(data) != 4*count {
+		return nil, fmt.Errorf("%w: %s with %d characters", ErrMalformed, cmd, len(data))
+	}
+	return parseWords(data, true)
+}
+
+func (m mewtocol) ParseWrite(frame []byte) error {
+	cmd, _, err := m.parse(frame)
+	if err == nil && cmd != "WD" {
+		err = fmt.Errorf("%w: %s", ErrMalformed, cmd)
+	}
+	return err
+}
+
+func (m mewtocol) Describe(frame []byte) (string, []decoder.Field, error) {
+	s := string(frame)
+	if len(s) < 7 {
+		return "", nil, ErrMalformed
+	}
+	station, _ := strconv.Atoi(s[1:3])
+	fields := []decoder.Field{{Name: "mewtocol.station", Value: int64(station)}}
+	var err error
+	if bcc := s[len(s)-3 : len(s)-1]; bcc != "**" {
+		err = checkHex(bcc, xorChecksum(frame[:len(frame)-3]))
+	}
+	body := s[4 : len(s)-3]
+	switch s[3] {
+	case '#':
+		if len(body) < 2 {
+			return "", nil, ErrMalformed
+		}
+		cmd, data := body[:2], body[2:]
+		fields = append(fields, decoder.Field{Name: "mewtocol.type", Value: "request"}, decoder.Field{Name: "mewtocol.command", Value: cmd})
+		summary := fmt.Sprintf("#%02d %s %s", station, cmd, data)
+		if (cmd == "RD" || cmd == "WD") && len(data) >= 11 {
+			start, _ := strconv.Atoi(data[1:6])
+			end, _ := strconv.Atoi(data[6:11])
+			fields = append(fields,
+				decoder.Field{Name: "mewtocol.area", Value: data[:1]},
+				decoder.Field{Name: "mewtocol.address", Value: int64(start)},
+				decoder.Field{Name: "mewtocol.count", Value: int64(end - start + 1)})
+			summary = fmt.Sprintf("#%02d %s %s%d..%d", station, cmd, data[:1], start, end)
+			if values, werr := parseWords(data[11:], true); cmd == "WD" && werr == nil {
+				summary += " = " + wordsSummary(values)
+			}
+		}
+		return summary, fields, err
+	case '$':
+		if len(body) < 2 {
+			return "", nil, ErrMalformed
+		}
+		cmd, data := body[:2], body[2:]
+		fields = append(fields, decoder.Field{Name: "mewtocol.type", Value: "response"}, decoder.Field{Name: "mewtocol.command", Value: cmd})
+		summary := fmt.Sprintf("$%02d %s ok", station, cmd)
+		if values, werr := parseWords(data, true); cmd == "RD" && werr == nil {
+			summary = fmt.Sprintf("$%02d RD %s", station, wordsSummary(values))
+		}
+		return summary, fields, err
+	case '!':
+		fields = append(fields, decoder.Field{Name: "mewtocol.type", Value: "error"}, decoder.Field{Name: "mewtocol.error", Value: body})
+		return fmt.Sprintf("!%02d error %s", station, body), fields, err
+	}
+	return "", nil, ErrMalformed
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package plc
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"serial-assistant/pkg/decoder"
+)
+
+// modbusASCII Modbus ASCII 主站
+// 区域 holding 使用功能码 03 读、16 写，input 只能以功能码 04 读
+type modbusASCII struct{}
+
+// Modbus 单帧可读写的寄存器数
+const (
+	modbusMaxRead  = 125
+	modbusMaxWrite = 123
+)
+
+func (modbusASCII) Name() string { return "modbus_ascii" }
+
+func (modbusASCII) Areas() []string { return []string{"holding", "input"} }
+
+func (m modbusASCII) checkStation(station int) error {
+	if station < 1 || station > 247 {
+		return fmt.Errorf("%w: station %d", ErrRange, station)
+	}
+	return nil
+}
+
+func (m modbusASCII) ReadRequest(station int, area string, addr, count int) ([]byte, error) {
+	fn := map[string]byte{"holding": 0x03, "input": 0x04}[area]
+	if fn == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownArea, area)
+	}
+	if err := m.checkStation(station); err != nil {
+		return nil, err
+	}
+	if err := checkRange(addr, count, 0xffff, modbusMaxRead); err != nil {
+		return nil, err
+	}
+	return decoder.ModbusASCIIEncode([]byte{byte(station), fn, byte(addr >> 8), byte(addr), byte(count >> 8), byte(count)}), nil
+}
+
+func (m modbusASCII) WriteRequest(station int, area string, addr int, values []uint16) ([]byte, error) {
+	if area != "holding" {
+		return nil, fmt.Errorf("%w: %s is read-only", ErrUnknownArea, area)
+	}
+	if err := m.checkStation(station); err != nil {
+		return nil, err
+	}
+	if err := checkRange(addr, len(values), 0xffff, modbusMaxWrite); err != nil {
+		return nil, err
+	}
+	n := len(values)
+	adu := []byte{byte(station), 0x10, byte(addr >> 8), byte(addr), byte(n >> 8), byte(n), byte(2 * n)}
+	for _, v := range values {
+		adu = append(adu, byte(v>>8), byte(v))
+	}
+	return decoder.ModbusASCIIEncode(adu), nil
+}
+
+func (modbusASCII) Split(buf []byte) (int, int) {
+	start := bytes.IndexByte(buf, ':')
+	if start < 0 {
+		return len(buf), 0
+	}
+	end := bytes.Index(buf[start:], []byte("\r\n"))
+	if end < 0 {
+		return start, 0
+	}
+	return start, end + 2
+}
+
+// pdu 校验应答并返回功能码与 PDU 数据，异常应答转换为 DeviceError
+func (m modbusASCII) pdu(frame []byte) (byte, []byte, error) {
+	adu, err := decoder.ModbusASCIIDecode(frame)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: %v", ErrChecksum, err)
+	}
+	fn := adu[1]
+	if fn&0x80 != 0 {
+		if len(adu) < 3 {
+			return 0, nil, ErrMalformed
+		}
+		return 0, nil, &DeviceError{Protocol: m.Name(), Code: fmt.Sprintf("%02X", adu[2])}
+	}
+	return fn, adu[2:], nil
+}
+
+func (m modbusASCII) ParseRead(frame []byte, count int) ([]uint16, error) {
+	fn, pdu, err := m.pdu(frame)
+	if err != nil {
+		return nil, err
+	}
+	if (fn != 0x03 && fn != 0x04) || len(pdu) != 1+2*count || int(pdu[0]) != 2*count {
+		return nil, fmt.Errorf("%w: function %d with %d data bytes", ErrMalformed, fn, len(pdu))
+	}
+	values := make([]uint16, count)
+	for i := range values {
+		values[i] = uint16(pdu[1+2*i])<<8 | uint16(pdu[2+2*i])
+	}
+	return values, nil
+}
+
+func (m modbusASCII) ParseWrite(frame []byte) error {
+	fn, _, err := m.pdu(frame)
+	if err == nil && fn != 0x10 {
+		err = fmt.Errorf("%w: function %d", ErrMalformed, fn)
+	}
+	return err
+}
+
+// Describe 借用 decoder 包的 Modbus ASCII 解码器
+func (m modbusASCII) Describe(frame []byte) (string, []decoder.Field, error) {
+	d, err := decoder.New(m.Name())
+	if err != nil {
+		return "", nil, err
+	}
+	var f decoder.Frame
+	d.Decode(time.Now(), frame, func(got decoder.Frame) { f = got })
+	if f.Error != "" {
+		return f.Summary, f.Fields, fmt.Errorf("%w: %s", ErrChecksum, f.Error)
+	}
+	return f.Summary, f.Fields, nil
+}
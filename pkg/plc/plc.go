@@ -0,0 +1,255 @@
+package plc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"serial-assistant/pkg/decoder"
+)
+
+// maxBuffer 等待应答时缓冲的数据上限
+const maxBuffer = 4096
+
+// 错误
+var (
+	ErrUnknownProtocol = errors.New("plc: unknown protocol")
+	ErrUnknownArea     = errors.New("plc: unknown memory area")
+	ErrRange           = errors.New("plc: address or count out of range")
+	ErrChecksum        = errors.New("plc: checksum mismatch")
+	ErrMalformed       = errors.New("plc: malformed response")
+)
+
+// DeviceError PLC 返回的错误应答
+type DeviceError struct {
+	Protocol string
+	Code     string
+}
+
+func (e *DeviceError) Error() string {
+	return fmt.Sprintf("plc: %s error %s", e.Protocol, e.Code)
+}
+
+// Protocol 一种 PLC 串口协议的帧编码与解析
+type Protocol interface {
+	// Name 协议名，同时作为解码器名与字段名前缀
+	Name() string
+	// Areas 支持的字寄存器区域，如 "D"、"DM"
+	Areas() []string
+	// ReadRequest 编码读取 count 个字寄存器的请求
+	ReadRequest(station int, area string, addr, count int) ([]byte, error)
+	// WriteRequest 编码写入字寄存器的请求
+	WriteRequest(station int, area string, addr int, values []uint16) ([]byte, error)
+	// Split 在 buf 中查找一个完整帧，返回帧前的无效字节数与帧长度；数据不足时 n 为 0
+	Split(buf []byte) (skip, n int)
+	// ParseRead 解析读取请求的应答
+	ParseRead(frame []byte, count int) ([]uint16, error)
+	// ParseWrite 解析写入请求的应答
+	ParseWrite(frame []byte) error
+	// Describe 描述一帧 (请求或应答) 用于协议解码
+	Describe(frame []byte) (summary string, fields []decoder.Field, err error)
+}
+
+var protocols = map[string]Protocol{}
+
+func register(p Protocol) {
+	protocols[p.Name()] = p
+	decoder.Register(decoder.Info{Name: p.Name(), Description: description[p.Name()], Source: "builtin"},
+		func() decoder.Decoder { return &frameDecoder{p: p} })
+}
+
+// description 解码器列表中显示的说明
+var description = map[string]string{
+	"mewtocol":  "Panasonic Mewtocol-COM",
+	"hostlink":  "Omron Host Link (C-mode)",
+	"melsec_fx": "Mitsubishi MELSEC FX programming port",
+}
+
+func init() {
+	register(mewtocol{})
+	register(hostlink{})
+	register(fx{})
+	// Modbus ASCII 的解码器由 decoder 包提供，这里只注册主站协议
+	protocols[modbusASCII{}.Name()] = modbusASCII{}
+}
+
+// Lookup 按名称返回协议
+func Lookup(name string) (Protocol, error) {
+	p, ok := protocols[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProtocol, name)
+	}
+	return p, nil
+}
+
+// Names 返回所有协议名
+func Names() []string {
+	names := make([]string, 0, len(protocols))
+	for name := range protocols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Master 在串口连接上作为主站发送请求并等待应答
+// 请求按顺序执行；没有请求在等待时收到的数据被忽略
+type Master struct {
+	write func(ctx context.Context, p []byte) error
+
+	mu      sync.Mutex // 串行化请求
+	bufMu   sync.Mutex
+	waiting bool
+	buf     []byte
+	notify  chan struct{}
+}
+
+// NewMaster 创建主站，write 向 PLC 写入数据
+func NewMaster(write func(ctx context.Context, p []byte) error) *Master {
+	return &Master{write: write, notify: make(chan struct{}, 1)}
+}
+
+// Feed 处理接收到的数据
+func (m *Master) Feed(data []byte) {
+	m.bufMu.Lock()
+	defer m.bufMu.Unlock()
+	if !m.waiting || len(m.buf)+len(data) > maxBuffer {
+		return
+	}
+	m.buf = append(m.buf, data...)
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Read 读取 count 个字寄存器
+func (m *Master) Read(ctx context.Context, p Protocol, station int, area string, addr, count int) ([]uint16, error) {
+	req, err := p.ReadRequest(station, area, addr, count)
+	if err != nil {
+		return nil, err
+	}
+	frame, err := m.transact(ctx, p, req)
+	if err != nil {
+		return nil, err
+	}
+	return p.ParseRead(frame, count)
+}
+
+// Write 写入字寄存器
+func (m *Master) Write(ctx context.Context, p Protocol, station int, area string, addr int, values []uint16) error {
+	req, err := p.WriteRequest(station, area, addr, values)
+	if err != nil {
+		return err
+	}
+	frame, err := m.transact(ctx, p, req)
+	if err != nil {
+		return err
+	}
+	return p.ParseWrite(frame)
+}
+
+// transact 发送请求并等待一个完整的应答帧
+func (m *Master) transact(ctx context.Context, p Protocol, req []byte) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.bufMu.Lock()
+	m.waiting, m.buf = true, m.buf[:0]
+	m.bufMu.Unlock()
+	defer func() {
+		m.bufMu.Lock()
+		m.waiting = false
+		m.bufMu.Unlock()
+	}()
+
+	if err := m.write(ctx, req); err != nil {
+		return nil, err
+	}
+	for {
+		m.bufMu.Lock()
+		skip, n := p.Split(m.buf)
+		if n > 0 {
+			frame := append([]byte(nil), m.buf[skip:skip+n]...)
+			m.bufMu.Unlock()
+			return frame, nil
+		}
+		m.buf = m.buf[:copy(m.buf, m.buf[skip:])]
+		m.bufMu.Unlock()
+
+		select {
+		case <-m.notify:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// checkRange 检查地址与数量
+func checkRange(addr, count, maxAddr, maxCount int) error {
+	if addr < 0 || count < 1 || count > maxCount || addr+count-1 > maxAddr {
+		return fmt.Errorf("%w: %d+%d", ErrRange, addr, count)
+	}
+	return nil
+}
+
+// xorChecksum 按字节异或
+func xorChecksum(p []byte) byte {
+	var x byte
+	for _, b := range p {
+		x ^= b
+	}
+	return x
+}
+
+// formatWords 将字编码为十六进制 ASCII，lowFirst 时每个字先低字节后高字节
+func formatWords(values []uint16, lowFirst bool) string {
+	var sb strings.Builder
+	for _, v := range values {
+		if lowFirst {
+			v = v<<8 | v>>8
+		}
+		fmt.Fprintf(&sb, "%04X", v)
+	}
+	return sb.String()
+}
+
+// parseWords 解析 formatWords 编码的数据
+func parseWords(s string, lowFirst bool) ([]uint16, error) {
+	if len(s)%4 != 0 {
+		return nil, fmt.Errorf("%w: data length %d", ErrMalformed, len(s))
+	}
+	values := make([]uint16, 0, len(s)/4)
+	for i := 0; i < len(s); i += 4 {
+		v, err := strconv.ParseUint(s[i:i+4], 16, 16)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrMalformed, s[i:i+4])
+		}
+		if lowFirst {
+			v = v<<8&0xff00 | v>>8
+		}
+		values = append(values, uint16(v))
+	}
+	return values, nil
+}
+
+// wordsSummary 以十进制列出字寄存器的值
+func wordsSummary(values []uint16) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(int(v))
+	}
+	return strings.Join(parts, " ")
+}
+
+// checkHex 比较帧中的两位十六进制校验值
+func checkHex(got string, want byte) error {
+	if got == fmt.Sprintf("%02X", want) {
+		return nil
+	}
+	return fmt.Errorf("%w: got %s, want %02X", ErrChecksum, got, want)
+}
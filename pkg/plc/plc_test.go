@@ -0,0 +1,171 @@
+package plc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/decoder"
+)
+
+// withFCS 为 Mewtocol/Host Link 帧体追加异或校验与结尾
+func withFCS(body, tail string) []byte {
+	return []byte(fmt.Sprintf("%s%02X%s", body, xorChecksum([]byte(body)), tail))
+}
+
+func TestMewtocol(t *testing.T) {
+	p, _ := Lookup("mewtocol")
+	req, err := p.ReadRequest(1, "D", 100, 2)
+	if err != nil || string(req) != string(withFCS("%01#RDD0010000101", "\r")) {
+		t.Errorf("Unexpected request %q (%v)", req, err)
+	}
+	values, err := p.ParseRead(withFCS("%01$RD34120100", "\r"), 2)
+	if err != nil || values[0] != 0x1234 || values[1] != 1 {
+		t.Errorf("Unexpected values %v (%v)", values, err)
+	}
+	req, _ = p.WriteRequest(1, "D", 0, []uint16{0x0a0b})
+	if string(req[:21]) != "%01#WDD00000000000B0A" {
+		t.Errorf("Unexpected write request %q", req)
+	}
+	var de *DeviceError
+	if _, err := p.ParseRead(withFCS("%01!61", "\r"), 1); !errors.As(err, &de) || de.Code != "61" {
+		t.Errorf("Expected device error 61, got %v", err)
+	}
+	if _, err := p.ParseRead([]byte("%01$RD3412FF\r"), 1); !errors.Is(err, ErrChecksum) {
+		t.Errorf("Expected a checksum error, got %v", err)
+	}
+	if _, err := p.ReadRequest(1, "X", 0, 1); !errors.Is(err, ErrUnknownArea) {
+		t.Errorf("Expected ErrUnknownArea, got %v", err)
+	}
+}
+
+func TestHostlink(t *testing.T) {
+	p, _ := Lookup("hostlink")
+	req, _ := p.ReadRequest(0, "DM", 100, 2)
+	if string(req) != string(withFCS("@00RD01000002", "*\r")) {
+		t.Errorf("Unexpected request %q", req)
+	}
+	values, err := p.ParseRead(withFCS("@00RD0012340001", "*\r"), 2)
+	if err != nil || values[0] != 0x1234 || values[1] != 1 {
+		t.Errorf("Unexpected values %v (%v)", values, err)
+	}
+	if err := p.ParseWrite(withFCS("@00WD00", "*\r")); err != nil {
+		t.Errorf("Unexpected write error %v", err)
+	}
+	var de *DeviceError
+	if err := p.ParseWrite(withFCS("@00WD13", "*\r")); !errors.As(err, &de) || de.Code != "13" {
+		t.Errorf("Expected end code 13, got %v", err)
+	}
+	if _, err := p.ReadRequest(0, "DM", 0, 30); !errors.Is(err, ErrRange) {
+		t.Errorf("Expected ErrRange, got %v", err)
+	}
+}
+
+func TestFX(t *testing.T) {
+	p, _ := Lookup("melsec_fx")
+	req, _ := p.ReadRequest(0, "D", 123, 1)
+	// D123 的字节地址为 0x1000 + 2*123 = 0x10F6
+	if want := "\x02010F602\x03"; string(req[:len(req)-2]) != want {
+		t.Errorf("Unexpected request %q", req)
+	}
+	if string(req[len(req)-2:]) != fmt.Sprintf("%02X", sum8(req[1:len(req)-2])) {
+		t.Errorf("Unexpected checksum in %q", req)
+	}
+	req, _ = p.ReadRequest(0, "D", 8000, 1)
+	if string(req[1:6]) != "00E00" {
+		t.Errorf("Expected D8000 at 0x0E00, got %q", req)
+	}
+	resp := append([]byte{stx}, fmt.Sprintf("3412\x03%02X", sum8([]byte("3412\x03")))...)
+	values, err := p.ParseRead(resp, 1)
+	if err != nil || values[0] != 0x1234 {
+		t.Errorf("Unexpected values %v (%v)", values, err)
+	}
+	if err := p.ParseWrite([]byte{nak}); err == nil {
+		t.Error("Expected an error for NAK")
+	}
+}
+
+func TestMaster(t *testing.T) {
+	p, _ := Lookup("hostlink")
+	var m *Master
+	m = NewMaster(func(_ context.Context, req []byte) error {
+		// 应答分两段到达，前面带有噪声
+		resp := withFCS("@00RD000042", "*\r")
+		go func() {
+			m.Feed(append([]byte{0xff}, resp[:5]...))
+			m.Feed(resp[5:])
+		}()
+		return nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	values, err := m.Read(ctx, p, 0, "DM", 0, 1)
+	if err != nil || len(values) != 1 || values[0] != 0x42 {
+		t.Errorf("Unexpected values %v (%v)", values, err)
+	}
+
+	silent := NewMaster(func(context.Context, []byte) error { return nil })
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := silent.Read(ctx, p, 0, "DM", 0, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+}
+
+func TestDecoders(t *testing.T) {
+	cases := []struct {
+		name    string
+		data    []byte
+		summary string
+	}{
+		{"mewtocol", withFCS("%01#RDD0010000101", "\r"), "#01 RD D100..101"},
+		{"mewtocol", withFCS("%01$RD34120100", "\r"), "$01 RD 4660 1"},
+		{"hostlink", withFCS("@00RD01000002", "*\r"), "@00 RD 100 x2"},
+		{"hostlink", withFCS("@00RD0012340001", "*\r"), "@00 RD 4660 1"},
+		{"melsec_fx", []byte{ack}, "ack"},
+	}
+	for _, c := range cases {
+		d, err := decoder.New(c.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Detect(c.data) < 0.9 {
+			t.Errorf("%s: expected a confident detection of %q", c.name, c.data)
+		}
+		var frames []decoder.Frame
+		half := len(c.data) / 2
+		d.Decode(time.Now(), c.data[:half], func(f decoder.Frame) { frames = append(frames, f) })
+		d.Decode(time.Now(), c.data[half:], func(f decoder.Frame) { frames = append(frames, f) })
+		if len(frames) != 1 || frames[0].Summary != c.summary || frames[0].Error != "" {
+			t.Errorf("%s: unexpected frames %+v", c.name, frames)
+		}
+	}
+}
+
+func TestModbusASCII(t *testing.T) {
+	p, _ := Lookup("modbus_ascii")
+	req, err := p.ReadRequest(1, "holding", 0x6B, 3)
+	if err != nil || string(req) != ":0103006B00038E\r\n" {
+		t.Errorf("Unexpected request %q (%v)", req, err)
+	}
+	values, err := p.ParseRead([]byte(":010306022B0000006465\r\n"), 3)
+	if err != nil || values[0] != 0x022B || values[2] != 100 {
+		t.Errorf("Unexpected values %v (%v)", values, err)
+	}
+	var de *DeviceError
+	if _, err := p.ParseRead([]byte(":0183027A\r\n"), 1); !errors.As(err, &de) || de.Code != "02" {
+		t.Errorf("Expected exception 02, got %v", err)
+	}
+	if _, err := p.WriteRequest(1, "input", 0, []uint16{1}); !errors.Is(err, ErrUnknownArea) {
+		t.Errorf("Expected input registers to be read-only, got %v", err)
+	}
+	req, _ = p.WriteRequest(1, "holding", 1, []uint16{0x000A, 0x0102})
+	if string(req) != ":01100001000204000A0102DB\r\n" {
+		t.Errorf("Unexpected write request %q", req)
+	}
+	if err := p.ParseWrite([]byte(":011000010002EC\r\n")); err != nil {
+		t.Errorf("Unexpected write error %v", err)
+	}
+}
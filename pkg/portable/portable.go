@@ -0,0 +1,125 @@
+// Package portable 便携模式：配置、会话日志等数据保存在程序旁的目录中，
+// 便于实验室电脑和 U 盘部署；通过 --portable 参数或程序旁已有数据目录启用
+package portable
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Flag 启用便携模式的命令行参数
+const Flag = "--portable"
+
+// HasFlag 参数中是否包含 Flag
+func HasFlag(args []string) bool {
+	for _, a := range args {
+		if a == Flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Dir 返回可执行文件 exe 旁名为 name 的数据目录
+// macOS 的可执行文件位于 .app 包内，数据目录放在 .app 所在的目录
+func Dir(exe, name string) string {
+	dir := filepath.Dir(exe)
+	if i := strings.Index(dir, ".app"+string(filepath.Separator)+"Contents"); i >= 0 {
+		dir = filepath.Dir(dir[:i+len(".app")])
+	}
+	return filepath.Join(dir, name)
+}
+
+// Detect 判断是否使用便携模式：参数中有 Flag，或 exe 旁已存在数据目录
+func Detect(exe string, args []string, name string) (string, bool) {
+	dir := Dir(exe, name)
+	if HasFlag(args) {
+		return dir, true
+	}
+	if st, err := os.Stat(dir); err == nil && st.IsDir() {
+		return dir, true
+	}
+	return dir, false
+}
+
+// Result 迁移结果
+type Result struct {
+	Files   int   `json:"files"`
+	Bytes   int64 `json:"bytes"`
+	Skipped int   `json:"skipped"` // 目标中已存在而未覆盖的文件
+}
+
+// ErrSameDir 源目录与目标目录相同
+var ErrSameDir = errors.New("portable: source and destination are the same directory")
+
+// Migrate 将 src 下的全部文件复制到 dst，保留目录结构，目标中已存在的文件不覆盖
+// src 不存在时视为没有数据；源文件保持不变
+func Migrate(src, dst string) (Result, error) {
+	var res Result
+	if filepath.Clean(src) == filepath.Clean(dst) {
+		return res, ErrSameDir
+	}
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return res, err
+	}
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == src {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0o755)
+		case !d.Type().IsRegular():
+			return nil
+		}
+		if _, err := os.Lstat(target); err == nil {
+			res.Skipped++
+			return nil
+		}
+		n, err := copyFile(path, target)
+		if err != nil {
+			return err
+		}
+		res.Files++
+		res.Bytes += n
+		return nil
+	})
+	return res, err
+}
+
+// copyFile 复制文件内容与权限
+func copyFile(src, dst string) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	st, err := in.Stat()
+	if err != nil {
+		return 0, err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, st.Mode().Perm())
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(dst)
+	}
+	return n, err
+}
@@ -0,0 +1,65 @@
+package portable
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDir(t *testing.T) {
+	root := t.TempDir()
+	if got := Dir(filepath.Join(root, "serial-mate.exe"), "data"); got != filepath.Join(root, "data") {
+		t.Errorf("Dir = %s", got)
+	}
+	if runtime.GOOS != "windows" {
+		exe := filepath.Join(root, "serial-mate.app", "Contents", "MacOS", "serial-mate")
+		if got := Dir(exe, "data"); got != filepath.Join(root, "data") {
+			t.Errorf("App bundle Dir = %s", got)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	exe := filepath.Join(root, "serial-mate")
+	if _, ok := Detect(exe, nil, "data"); ok {
+		t.Error("Portable mode should be off without flag or directory")
+	}
+	if dir, ok := Detect(exe, []string{"-x", Flag}, "data"); !ok || dir != filepath.Join(root, "data") {
+		t.Errorf("Flag should enable portable mode, got %s %v", dir, ok)
+	}
+	os.Mkdir(filepath.Join(root, "data"), 0o755)
+	if _, ok := Detect(exe, nil, "data"); !ok {
+		t.Error("Existing directory should enable portable mode")
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	src, dst := t.TempDir(), t.TempDir()
+	os.MkdirAll(filepath.Join(src, "captures"), 0o755)
+	os.WriteFile(filepath.Join(src, "rules.json"), []byte("[]"), 0o644)
+	os.WriteFile(filepath.Join(src, "captures", "a.smcap"), []byte("12345"), 0o644)
+	os.WriteFile(filepath.Join(dst, "rules.json"), []byte("keep"), 0o644)
+
+	res, err := Migrate(src, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Files != 1 || res.Bytes != 5 || res.Skipped != 1 {
+		t.Errorf("Unexpected result %+v", res)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "rules.json")); string(data) != "keep" {
+		t.Errorf("Existing file was overwritten: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "captures", "a.smcap")); err != nil {
+		t.Error(err)
+	}
+
+	if res, err := Migrate(filepath.Join(src, "missing"), dst); err != nil || res.Files != 0 {
+		t.Errorf("Missing source should migrate nothing, got %+v, %v", res, err)
+	}
+	if _, err := Migrate(src, src); err != ErrSameDir {
+		t.Errorf("Expected ErrSameDir, got %v", err)
+	}
+}
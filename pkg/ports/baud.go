@@ -0,0 +1,76 @@
+package ports
+
+import (
+	"errors"
+	"fmt"
+
+	"go.bug.st/serial"
+)
+
+// 波特率范围
+const (
+	MinBaudRate = 50
+	MaxBaudRate = 20000000
+)
+
+// StandardBaudRates 常用的标准波特率，其余速率需要驱动支持任意波特率
+var StandardBaudRates = []int{
+	300, 600, 1200, 2400, 4800, 9600, 19200, 38400, 57600,
+	115200, 230400, 460800, 500000, 576000, 921600, 1000000,
+	1152000, 1500000, 2000000, 2500000, 3000000, 3500000, 4000000,
+}
+
+// ErrBaudUnsupported 当前平台或端口无法设置任意波特率
+var ErrBaudUnsupported = errors.New("custom baud rate is not supported on this port")
+
+// baudTolerance 驱动实际设置的速率与请求速率允许的相对误差
+const baudTolerance = 0.02
+
+// IsStandardBaudRate 是否为标准波特率
+func IsStandardBaudRate(baud int) bool {
+	for _, b := range StandardBaudRates {
+		if b == baud {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateBaudRate 检查波特率是否在支持的范围内
+func ValidateBaudRate(baud int) error {
+	if baud < MinBaudRate || baud > MaxBaudRate {
+		return fmt.Errorf("baud rate %d out of range %d-%d", baud, MinBaudRate, MaxBaudRate)
+	}
+	return nil
+}
+
+// SetBaudRate 直接设置串口的波特率，可为任意速率
+// Linux 使用 termios2 的 BOTHER，macOS 使用 IOSSIOSPEED，Windows 直接写入 DCB
+func SetBaudRate(port serial.Port, baud int) error {
+	if err := ValidateBaudRate(baud); err != nil {
+		return err
+	}
+	h, ok := portHandle(port)
+	if !ok {
+		return ErrBaudUnsupported
+	}
+	return setBaudRate(h, baud)
+}
+
+// BaudRate 读取驱动实际使用的波特率
+func BaudRate(port serial.Port) (int, error) {
+	h, ok := portHandle(port)
+	if !ok {
+		return 0, ErrBaudUnsupported
+	}
+	return getBaudRate(h)
+}
+
+// BaudRateMatches 实际速率与请求速率的误差是否在允许范围内
+func BaudRateMatches(want, got int) bool {
+	diff := float64(want - got)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= float64(want)*baudTolerance
+}
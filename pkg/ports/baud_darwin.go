@@ -0,0 +1,22 @@
+//go:build darwin
+
+package ports
+
+import "golang.org/x/sys/unix"
+
+// iossiospeed IOSSIOSPEED ioctl，IoctlSetPointerInt 传入 32 位整数，参数长度按 4 字节编码
+const iossiospeed = 0x80045402
+
+// setBaudRate 通过 IOSSIOSPEED 设置任意波特率
+func setBaudRate(h uintptr, baud int) error {
+	return unix.IoctlSetPointerInt(int(h), iossiospeed, baud)
+}
+
+// getBaudRate 读取 termios 中的输出速率
+func getBaudRate(h uintptr) (int, error) {
+	t, err := unix.IoctlGetTermios(int(h), unix.TIOCGETA)
+	if err != nil {
+		return 0, err
+	}
+	return int(t.Ospeed), nil
+}
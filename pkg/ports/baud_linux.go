@@ -0,0 +1,27 @@
+//go:build linux && !ppc64 && !ppc64le
+
+package ports
+
+import "golang.org/x/sys/unix"
+
+// setBaudRate 通过 TCSETS2 以 BOTHER 方式设置任意波特率
+func setBaudRate(h uintptr, baud int) error {
+	t, err := unix.IoctlGetTermios(int(h), unix.TCGETS2)
+	if err != nil {
+		return err
+	}
+	t.Cflag &^= unix.CBAUD | unix.CIBAUD
+	t.Cflag |= unix.BOTHER
+	t.Ispeed = uint32(baud)
+	t.Ospeed = uint32(baud)
+	return unix.IoctlSetTermios(int(h), unix.TCSETS2, t)
+}
+
+// getBaudRate 通过 TCGETS2 读取实际输出速率
+func getBaudRate(h uintptr) (int, error) {
+	t, err := unix.IoctlGetTermios(int(h), unix.TCGETS2)
+	if err != nil {
+		return 0, err
+	}
+	return int(t.Ospeed), nil
+}
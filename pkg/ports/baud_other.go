@@ -0,0 +1,13 @@
+//go:build !(linux && !ppc64 && !ppc64le) && !darwin && !windows
+
+package ports
+
+// setBaudRate 其他平台暂不支持任意波特率
+func setBaudRate(h uintptr, baud int) error {
+	return ErrBaudUnsupported
+}
+
+// getBaudRate 其他平台无法读取实际速率
+func getBaudRate(h uintptr) (int, error) {
+	return 0, ErrBaudUnsupported
+}
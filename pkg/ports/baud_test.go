@@ -0,0 +1,45 @@
+package ports
+
+import (
+	"testing"
+
+	"go.bug.st/serial"
+)
+
+func TestValidateBaudRate(t *testing.T) {
+	for baud, ok := range map[int]bool{
+		0: false, -9600: false, 49: false, 50: true, 14400: true,
+		250000: true, 921600: true, 3000000: true, MaxBaudRate + 1: false,
+	} {
+		if err := ValidateBaudRate(baud); (err == nil) != ok {
+			t.Errorf("ValidateBaudRate(%d) = %v", baud, err)
+		}
+	}
+}
+
+func TestIsStandardBaudRate(t *testing.T) {
+	if !IsStandardBaudRate(115200) || !IsStandardBaudRate(921600) {
+		t.Error("115200 and 921600 should be standard")
+	}
+	if IsStandardBaudRate(14400) || IsStandardBaudRate(250000) {
+		t.Error("14400 and 250000 should be custom")
+	}
+}
+
+func TestBaudRateMatches(t *testing.T) {
+	if !BaudRateMatches(250000, 250000) || !BaudRateMatches(250000, 248000) {
+		t.Error("Rates within tolerance should match")
+	}
+	if BaudRateMatches(250000, 230400) {
+		t.Error("230400 should not match 250000")
+	}
+}
+
+func TestSetBaudRateWithoutHandle(t *testing.T) {
+	if err := SetBaudRate(struct{ serial.Port }{}, 250000); err != ErrBaudUnsupported {
+		t.Errorf("Expected ErrBaudUnsupported, got %v", err)
+	}
+	if err := SetBaudRate(struct{ serial.Port }{}, 0); err == nil {
+		t.Error("Expected range error")
+	}
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package ports
+
+import "golang.org/x/sys/windows"
+
+// setBaudRate 写入 DCB.BaudRate，驱动不支持时 SetCommState 返回错误
+func setBaudRate(h uintptr, baud int) error {
+	var dcb windows.DCB
+	if err := windows.GetCommState(windows.Handle(h), &dcb); err != nil {
+		return err
+	}
+	dcb.BaudRate = uint32(baud)
+	return windows.SetCommState(windows.Handle(h), &dcb)
+}
+
+// getBaudRate 读取 DCB.BaudRate
+func getBaudRate(h uintptr) (int, error) {
+	var dcb windows.DCB
+	if err := windows.GetCommState(windows.Handle(h), &dcb); err != nil {
+		return 0, err
+	}
+	return int(dcb.BaudRate), nil
+}
@@ -0,0 +1,48 @@
+package ports
+
+import (
+	"errors"
+	"reflect"
+
+	"go.bug.st/serial"
+)
+
+// 流控方式，对应 journal.Connection.FlowControl
+const (
+	FlowNone   = "none"   // 无流控
+	FlowRTSCTS = "rtscts" // RTS/CTS 硬件流控
+)
+
+// ErrFlowUnsupported 当前平台或端口不支持设置硬件流控
+var ErrFlowUnsupported = errors.New("hardware flow control is not supported on this port")
+
+// ValidFlow 流控名称是否有效 (空串等同 FlowNone)
+func ValidFlow(flow string) bool {
+	return flow == "" || flow == FlowNone || flow == FlowRTSCTS
+}
+
+// SetFlowControl 开启或关闭串口的 RTS/CTS 硬件流控
+// go.bug.st/serial 打开串口时总是关闭硬件流控且不提供设置接口，这里取出端口的系统句柄直接设置
+func SetFlowControl(port serial.Port, rtscts bool) error {
+	h, ok := portHandle(port)
+	if !ok {
+		return ErrFlowUnsupported
+	}
+	return setFlowControl(h, rtscts)
+}
+
+// portHandle 取出 go.bug.st/serial 端口的系统句柄 (unixPort.handle 为 fd，windowsPort.handle 为 HANDLE)
+func portHandle(port serial.Port) (uintptr, bool) {
+	v := reflect.ValueOf(port)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return 0, false
+	}
+	f := v.Elem().FieldByName("handle")
+	switch f.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return uintptr(f.Int()), true
+	case reflect.Uint, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return uintptr(f.Uint()), true
+	}
+	return 0, false
+}
@@ -0,0 +1,19 @@
+//go:build darwin
+
+package ports
+
+import "golang.org/x/sys/unix"
+
+// setFlowControl 设置 termios 的 CCTS_OFLOW | CRTS_IFLOW 标志
+func setFlowControl(h uintptr, rtscts bool) error {
+	t, err := unix.IoctlGetTermios(int(h), unix.TIOCGETA)
+	if err != nil {
+		return err
+	}
+	if rtscts {
+		t.Cflag |= unix.CRTSCTS
+	} else {
+		t.Cflag &^= unix.CRTSCTS
+	}
+	return unix.IoctlSetTermios(int(h), unix.TIOCSETA, t)
+}
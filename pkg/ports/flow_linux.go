@@ -0,0 +1,19 @@
+//go:build linux
+
+package ports
+
+import "golang.org/x/sys/unix"
+
+// setFlowControl 设置 termios 的 CRTSCTS 标志
+func setFlowControl(h uintptr, rtscts bool) error {
+	t, err := unix.IoctlGetTermios(int(h), unix.TCGETS)
+	if err != nil {
+		return err
+	}
+	if rtscts {
+		t.Cflag |= unix.CRTSCTS
+	} else {
+		t.Cflag &^= unix.CRTSCTS
+	}
+	return unix.IoctlSetTermios(int(h), unix.TCSETS, t)
+}
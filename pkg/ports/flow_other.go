@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !windows
+
+package ports
+
+// setFlowControl 其他平台暂不支持
+func setFlowControl(h uintptr, rtscts bool) error {
+	return ErrFlowUnsupported
+}
@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"testing"
+
+	"go.bug.st/serial"
+)
+
+// fakePort 与 go.bug.st/serial 内部端口类型结构相同的替身
+type fakePort struct {
+	serial.Port
+	handle uintptr
+}
+
+func TestPortHandle(t *testing.T) {
+	if h, ok := portHandle(&fakePort{handle: 42}); !ok || h != 42 {
+		t.Errorf("Expected handle 42, got %d, %v", h, ok)
+	}
+	if _, ok := portHandle(nil); ok {
+		t.Error("nil port should have no handle")
+	}
+	if err := SetFlowControl(struct{ serial.Port }{}, true); err != ErrFlowUnsupported {
+		t.Errorf("Expected ErrFlowUnsupported, got %v", err)
+	}
+}
+
+func TestValidFlow(t *testing.T) {
+	for flow, want := range map[string]bool{"": true, FlowNone: true, FlowRTSCTS: true, "xonxoff": false} {
+		if ValidFlow(flow) != want {
+			t.Errorf("ValidFlow(%q) = %v", flow, !want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package ports
+
+import "golang.org/x/sys/windows"
+
+// DCB.Flags 中与硬件流控相关的位
+const (
+	dcbOutxCtsFlow  = 0x00000004 // 发送前检查 CTS
+	dcbRtsControl   = 0x00003000 // RTS 控制方式掩码
+	dcbRtsEnable    = 0x00001000
+	dcbRtsHandshake = 0x00002000 // 接收缓冲区将满时由驱动拉低 RTS
+)
+
+// setFlowControl 设置 DCB 的 fOutxCtsFlow 与 fRtsControl
+func setFlowControl(h uintptr, rtscts bool) error {
+	var dcb windows.DCB
+	if err := windows.GetCommState(windows.Handle(h), &dcb); err != nil {
+		return err
+	}
+	dcb.Flags &^= dcbOutxCtsFlow | dcbRtsControl
+	if rtscts {
+		dcb.Flags |= dcbOutxCtsFlow | dcbRtsHandshake
+	} else {
+		dcb.Flags |= dcbRtsEnable
+	}
+	return windows.SetCommState(windows.Handle(h), &dcb)
+}
@@ -0,0 +1,77 @@
+package ports
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Holder 打开了串口的进程
+type Holder struct {
+	PID     int    `json:"pid"`
+	Name    string `json:"name"`
+	Command string `json:"command,omitempty"` // 完整命令行 (能读取时)
+}
+
+// String 返回 "名称 (PID n)"
+func (h Holder) String() string {
+	return fmt.Sprintf("%s (PID %d)", h.Name, h.PID)
+}
+
+var (
+	// ErrHoldersUnsupported 当前系统无法查询占用串口的进程 (如 Windows 未安装 Sysinternals handle)
+	ErrHoldersUnsupported = errors.New("ports: cannot look up processes holding the port")
+	// ErrHandleEULA Windows 上已找到 Sysinternals handle 但用户尚未接受其许可协议；
+	// 不代替用户接受，用户在命令行运行一次 handle.exe 并同意后即可使用
+	ErrHandleEULA = errors.New("ports: Sysinternals handle license not accepted; run handle.exe once to accept it")
+)
+
+// parseLsof 解析 lsof -F pc 的输出：每个进程一行 p<PID> 与一行 c<命令名>
+func parseLsof(out []byte) []Holder {
+	var list []Holder
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case 'p':
+			if pid, err := strconv.Atoi(line[1:]); err == nil {
+				list = append(list, Holder{PID: pid})
+			}
+		case 'c':
+			if n := len(list); n > 0 && list[n-1].Name == "" {
+				list[n-1].Name = line[1:]
+			}
+		}
+	}
+	return list
+}
+
+// handleLine handle.exe 输出中的一行："putty.exe  pid: 5432  type: File  2C4: \Device\Serial0"
+var handleLine = regexp.MustCompile(`^\s*(.+?)\s+pid:\s*(\d+)\s`)
+
+// parseHandle 解析 Sysinternals handle.exe 的输出，同一进程的多个句柄只计一次
+func parseHandle(out []byte) []Holder {
+	var list []Holder
+	seen := map[int]bool{}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		m := handleLine.FindStringSubmatch(sc.Text())
+		if m == nil {
+			continue
+		}
+		pid, err := strconv.Atoi(m[2])
+		if err != nil || seen[pid] {
+			continue
+		}
+		seen[pid] = true
+		list = append(list, Holder{PID: pid, Name: strings.TrimSpace(m[1])})
+	}
+	return list
+}
@@ -0,0 +1,32 @@
+//go:build darwin
+
+package ports
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// Holders 通过 lsof 查找打开了串口设备的其他进程
+func Holders(ctx context.Context, port string) ([]Holder, error) {
+	out, err := exec.CommandContext(ctx, "lsof", "-w", "-F", "pc", "--", port).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(out) == 0 {
+		return nil, nil // 没有进程打开该文件时 lsof 以 1 退出
+	}
+	if err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, ErrHoldersUnsupported
+		}
+		return nil, err
+	}
+	var list []Holder
+	for _, h := range parseLsof(out) {
+		if h.PID != os.Getpid() {
+			list = append(list, h)
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,66 @@
+//go:build linux
+
+package ports
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// procRoot procfs 挂载点
+const procRoot = "/proc"
+
+// Holders 查找打开了串口设备的其他进程 (与 lsof 相同，扫描 /proc/<pid>/fd)
+// 只能看到当前用户有权限查看的进程，root 以外的用户看不到其他用户的进程
+func Holders(ctx context.Context, port string) ([]Holder, error) {
+	dev, err := filepath.EvalSymlinks(port)
+	if err != nil {
+		return nil, err
+	}
+	return procHolders(ctx, procRoot, dev, os.Getpid())
+}
+
+// procHolders 在 root 下查找 fd 指向 dev 的进程，跳过 self
+func procHolders(ctx context.Context, root, dev string, self int) ([]Holder, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	var list []Holder
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || pid == self {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return list, err
+		}
+		dir := filepath.Join(root, e.Name())
+		fds, err := os.ReadDir(filepath.Join(dir, "fd"))
+		if err != nil {
+			continue // 进程已退出或无权查看
+		}
+		for _, fd := range fds {
+			if target, err := os.Readlink(filepath.Join(dir, "fd", fd.Name())); err == nil && target == dev {
+				list = append(list, procHolder(dir, pid))
+				break
+			}
+		}
+	}
+	return list, nil
+}
+
+// procHolder 读取进程名与命令行
+func procHolder(dir string, pid int) Holder {
+	h := Holder{PID: pid}
+	if b, err := os.ReadFile(filepath.Join(dir, "comm")); err == nil {
+		h.Name = strings.TrimSpace(string(b))
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		h.Command = strings.TrimSpace(strings.ReplaceAll(string(b), "\x00", " "))
+	}
+	return h
+}
@@ -0,0 +1,38 @@
+package ports
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProcHolders(t *testing.T) {
+	root := t.TempDir()
+	proc := func(pid, comm, cmdline string, fds map[string]string) {
+		dir := filepath.Join(root, pid)
+		if err := os.MkdirAll(filepath.Join(dir, "fd"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		os.WriteFile(filepath.Join(dir, "comm"), []byte(comm+"\n"), 0o644)
+		os.WriteFile(filepath.Join(dir, "cmdline"), []byte(cmdline), 0o644)
+		for fd, target := range fds {
+			if err := os.Symlink(target, filepath.Join(dir, "fd", fd)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	proc("100", "screen", "screen\x00/dev/ttyUSB0\x00115200\x00", map[string]string{"0": "/dev/pts/1", "3": "/dev/ttyUSB0"})
+	proc("200", "bash", "bash\x00", map[string]string{"0": "/dev/pts/1"})
+	proc("300", "self", "self\x00", map[string]string{"5": "/dev/ttyUSB0"})
+	os.MkdirAll(filepath.Join(root, "sys"), 0o755)
+
+	list, err := procHolders(context.Background(), root, "/dev/ttyUSB0", 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Holder{PID: 100, Name: "screen", Command: "screen /dev/ttyUSB0 115200"}
+	if len(list) != 1 || list[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, list)
+	}
+}
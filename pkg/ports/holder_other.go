@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !windows
+
+package ports
+
+import "context"
+
+// Holders 其他平台暂不支持
+func Holders(ctx context.Context, port string) ([]Holder, error) {
+	return nil, ErrHoldersUnsupported
+}
@@ -0,0 +1,25 @@
+package ports
+
+import "testing"
+
+func TestParseLsof(t *testing.T) {
+	out := []byte("p412\ncscreen\nf3\np977\ncminicom\nf5\nf6\n")
+	list := parseLsof(out)
+	if len(list) != 2 || list[0] != (Holder{PID: 412, Name: "screen"}) || list[1] != (Holder{PID: 977, Name: "minicom"}) {
+		t.Errorf("Unexpected holders %+v", list)
+	}
+	if list[0].String() != "screen (PID 412)" {
+		t.Errorf("Unexpected string %q", list[0].String())
+	}
+}
+
+func TestParseHandle(t *testing.T) {
+	out := []byte("putty.exe          pid: 5432   type: File           2C4: \\Device\\Serial0\r\n" +
+		"putty.exe          pid: 5432   type: File           2D0: \\Device\\Serial0\r\n" +
+		"Arduino IDE.exe    pid: 77     type: File            14: \\Device\\Serial0\r\n" +
+		"No matching handles found.\r\n")
+	list := parseHandle(out)
+	if len(list) != 2 || list[0] != (Holder{PID: 5432, Name: "putty.exe"}) || list[1] != (Holder{PID: 77, Name: "Arduino IDE.exe"}) {
+		t.Errorf("Unexpected holders %+v", list)
+	}
+}
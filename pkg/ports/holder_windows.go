@@ -0,0 +1,88 @@
+//go:build windows
+
+package ports
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// handleTools Sysinternals handle 的可执行文件名，需在 PATH 中
+var handleTools = []string{"handle64.exe", "handle.exe"}
+
+// handleEULAKey 用户接受 Sysinternals handle 许可协议后写入的注册表项 (值 EulaAccepted = 1)
+const handleEULAKey = `Software\Sysinternals\Handle`
+
+// Holders 查询打开了串口设备对象 (如 \Device\Serial0、\Device\VCP0) 的进程
+// Windows 没有公开的接口按设备查询句柄，依赖 Sysinternals handle；未安装时返回 ErrHoldersUnsupported，
+// 用户尚未接受其许可协议时返回 ErrHandleEULA
+func Holders(ctx context.Context, port string) ([]Holder, error) {
+	device, err := dosDevice(strings.TrimPrefix(port, `\\.\`))
+	if err != nil {
+		return nil, err
+	}
+	tool := ""
+	for _, name := range handleTools {
+		if path, err := exec.LookPath(name); err == nil {
+			tool = path
+			break
+		}
+	}
+	if tool == "" {
+		return nil, ErrHoldersUnsupported
+	}
+	if !handleEULAAccepted() {
+		return nil, ErrHandleEULA
+	}
+	cmd := exec.CommandContext(ctx, tool, "-nobanner", "-a", device)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) { // 没有匹配的句柄时 handle 以非零值退出
+		return nil, err
+	}
+	var list []Holder
+	for _, h := range parseHandle(out) {
+		if h.PID != os.Getpid() {
+			list = append(list, h)
+		}
+	}
+	return list, nil
+}
+
+// handleEULAAccepted 当前用户 (或管理员通过策略为本机) 是否已接受 handle 的许可协议
+func handleEULAAccepted() bool {
+	for _, root := range []registry.Key{registry.CURRENT_USER, registry.LOCAL_MACHINE} {
+		key, err := registry.OpenKey(root, handleEULAKey, registry.QUERY_VALUE)
+		if err != nil {
+			continue
+		}
+		v, _, err := key.GetIntegerValue("EulaAccepted")
+		key.Close()
+		if err == nil && v == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// dosDevice 返回 COM 口对应的 NT 设备名
+func dosDevice(name string) (string, error) {
+	p, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return "", err
+	}
+	buf := make([]uint16, windows.MAX_PATH)
+	n, err := windows.QueryDosDevice(p, &buf[0], uint32(len(buf)))
+	if err != nil {
+		return "", err
+	}
+	return windows.UTF16ToString(buf[:n]), nil
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package ports
+
+// InstanceID 设备实例仅存在于 Windows
+func InstanceID(port string) string {
+	return ""
+}
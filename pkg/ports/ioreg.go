@@ -0,0 +1,169 @@
+package ports
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ioregNode ioreg 输出中的一个对象及其属性
+type ioregNode struct {
+	class  string
+	depth  int
+	parent *ioregNode
+	props  map[string]string
+}
+
+// usbInfo 从 IOKit 中读到的串口信息
+type usbInfo struct {
+	Product      string
+	Manufacturer string
+	SerialNumber string
+	VID          string
+	PID          string
+	LocationID   string
+}
+
+// parseIoreg 解析 `ioreg -p IOService -l -w0` 的输出，返回 设备路径 -> USB 信息
+// 串口对象 (IOSerialBSDClient) 的 USB 属性位于其祖先节点 (IOUSBHostDevice) 上
+// cu 与 tty 设备共享同一条信息
+func parseIoreg(output string) map[string]usbInfo {
+	var (
+		nodes   []*ioregNode
+		current *ioregNode
+		stack   []*ioregNode
+	)
+	sc := bufio.NewScanner(strings.NewReader(output))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.Index(line, "+-o "); i >= 0 {
+			node := &ioregNode{depth: i / 2, props: make(map[string]string)}
+			if c := strings.Index(line, "<class "); c >= 0 {
+				class := line[c+len("<class "):]
+				if end := strings.IndexAny(class, ",>"); end >= 0 {
+					class = class[:end]
+				}
+				node.class = class
+			}
+			for len(stack) > 0 && stack[len(stack)-1].depth >= node.depth {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) > 0 {
+				node.parent = stack[len(stack)-1]
+			}
+			stack = append(stack, node)
+			nodes = append(nodes, node)
+			current = node
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		// 属性行: |   "key" = value
+		trimmed := strings.TrimLeft(line, " |")
+		if !strings.HasPrefix(trimmed, `"`) {
+			continue
+		}
+		end := strings.Index(trimmed[1:], `"`)
+		if end < 0 {
+			continue
+		}
+		key := trimmed[1 : end+1]
+		rest := strings.TrimSpace(trimmed[end+2:])
+		if !strings.HasPrefix(rest, "=") {
+			continue
+		}
+		current.props[key] = strings.Trim(strings.TrimSpace(rest[1:]), `"`)
+	}
+
+	result := make(map[string]usbInfo)
+	for _, n := range nodes {
+		if n.class != "IOSerialBSDClient" {
+			continue
+		}
+		var info usbInfo
+		for p := n.parent; p != nil; p = p.parent {
+			if _, ok := p.props["idVendor"]; ok {
+				info = usbInfoFromProps(p.props)
+				break
+			}
+		}
+		for _, key := range []string{"IOCalloutDevice", "IODialinDevice"} {
+			if dev := n.props[key]; dev != "" {
+				result[dev] = info
+			}
+		}
+	}
+	return result
+}
+
+// usbInfoFromProps 读取 USB 设备节点的属性，兼容新旧两套属性名
+func usbInfoFromProps(props map[string]string) usbInfo {
+	first := func(keys ...string) string {
+		for _, k := range keys {
+			if v := props[k]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	return usbInfo{
+		Product:      first("kUSBProductString", "USB Product Name"),
+		Manufacturer: first("kUSBVendorString", "USB Vendor Name"),
+		SerialNumber: first("kUSBSerialNumberString", "USB Serial Number"),
+		VID:          hex16(props["idVendor"]),
+		PID:          hex16(props["idProduct"]),
+		LocationID:   hex32(props["locationID"]),
+	}
+}
+
+func hex16(v string) string {
+	n, err := strconv.ParseUint(v, 0, 16)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%04X", n)
+}
+
+func hex32(v string) string {
+	n, err := strconv.ParseUint(v, 0, 32)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("0x%08X", n)
+}
+
+// applyDarwin 用 IOKit 信息补全串口，并将 /dev/tty.* 标为不推荐：
+// tty 设备在打开时会等待 DCD 信号，对大多数 USB 串口应使用对应的 /dev/cu.*
+func applyDarwin(list []Port, infos map[string]usbInfo) []Port {
+	for i := range list {
+		p := &list[i]
+		if info, ok := infos[p.Name]; ok {
+			if p.FriendlyName == "" {
+				p.FriendlyName = info.Product
+			}
+			if p.Manufacturer == "" {
+				p.Manufacturer = info.Manufacturer
+			}
+			if p.VID == "" && info.VID != "" {
+				p.IsUSB = true
+				p.VID, p.PID = info.VID, info.PID
+			}
+			if p.SerialNumber == "" {
+				p.SerialNumber = info.SerialNumber
+			}
+			p.LocationID = info.LocationID
+		}
+		switch {
+		case strings.HasPrefix(p.Name, "/dev/cu."):
+			p.Kind = "cu"
+		case strings.HasPrefix(p.Name, "/dev/tty."):
+			p.Kind = "tty"
+			p.Recommended = false
+			p.Alternative = "/dev/cu." + strings.TrimPrefix(p.Name, "/dev/tty.")
+		}
+	}
+	return list
+}
@@ -0,0 +1,49 @@
+package ports
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseIoreg(t *testing.T) {
+	data, err := os.ReadFile("testdata/ioreg.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	infos := parseIoreg(string(data))
+
+	cu, ok := infos["/dev/cu.usbserial-1110"]
+	if !ok {
+		t.Fatalf("USB serial port not found in %+v", infos)
+	}
+	want := usbInfo{Product: "USB Serial", Manufacturer: "QinHeng Electronics", VID: "1A86", PID: "7523", LocationID: "0x01100000"}
+	if cu != want {
+		t.Errorf("Unexpected info %+v, expected %+v", cu, want)
+	}
+	if infos["/dev/tty.usbserial-1110"] != cu {
+		t.Error("tty and cu devices should share the same info")
+	}
+
+	if bt, ok := infos["/dev/cu.Bluetooth-Incoming-Port"]; !ok || bt.VID != "" {
+		t.Errorf("Non-USB port should be listed without USB info: %+v", bt)
+	}
+}
+
+func TestApplyDarwin(t *testing.T) {
+	list := []Port{
+		{Name: "/dev/cu.usbserial-1110", Recommended: true},
+		{Name: "/dev/tty.usbserial-1110", Recommended: true},
+	}
+	infos := map[string]usbInfo{
+		"/dev/cu.usbserial-1110":  {Product: "USB Serial", VID: "1A86", PID: "7523", LocationID: "0x01100000"},
+		"/dev/tty.usbserial-1110": {Product: "USB Serial", VID: "1A86", PID: "7523", LocationID: "0x01100000"},
+	}
+	list = applyDarwin(list, infos)
+
+	if p := list[0]; p.Kind != "cu" || !p.Recommended || !p.IsUSB || p.FriendlyName != "USB Serial" || p.LocationID != "0x01100000" {
+		t.Errorf("Unexpected cu port: %+v", p)
+	}
+	if p := list[1]; p.Kind != "tty" || p.Recommended || p.Alternative != "/dev/cu.usbserial-1110" {
+		t.Errorf("Unexpected tty port: %+v", p)
+	}
+}
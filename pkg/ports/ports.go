@@ -0,0 +1,200 @@
+package ports
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.bug.st/serial/enumerator"
+)
+
+// Port 枚举到的串口及其状态
+type Port struct {
+	Name         string `json:"name"`         // 显示及传给 OpenSerial 的名称，如 COM10、/dev/ttyUSB0
+	Path         string `json:"path"`         // 设备路径，如 \\.\COM10
+	FriendlyName string `json:"friendlyName"` // 系统中的友好名称，如 "USB-SERIAL CH340 (COM10)"
+	IsUSB        bool   `json:"isUsb"`
+	VID          string `json:"vid,omitempty"`
+	PID          string `json:"pid,omitempty"`
+	SerialNumber string `json:"serialNumber,omitempty"`
+	Manufacturer string `json:"manufacturer,omitempty"` // USB 厂商字符串 (iManufacturer)
+	Product      string `json:"product,omitempty"`      // USB 产品字符串 (iProduct)
+
+	// 界面显示名称：同型号 (VID:PID 相同) 的多个适配器附带序列号或 USB 位置以便区分
+	Label string `json:"label"`
+
+	// USB 物理位置，同一物理接口不变：macOS 为 locationID，Linux 为 sysfs 接口路径 (如 1-1.2:1.0)，
+	// Windows 为 LocationInformation (如 Port_#0002.Hub_#0001)
+	LocationID string `json:"locationId,omitempty"`
+	// macOS: 设备类型 (cu/tty)
+	Kind string `json:"kind,omitempty"`
+
+	// 是否推荐使用该设备；不推荐时 Alternative 给出应改用的设备
+	Recommended bool   `json:"recommended"`
+	Alternative string `json:"alternative,omitempty"`
+
+	// 状态标志
+	Busy   bool `json:"busy"`   // 已被其他程序独占打开
+	Hidden bool `json:"hidden"` // 设备管理器枚举不到，仅存在于系统串口表中 (如被独占的虚拟串口)
+}
+
+// List 枚举系统中的串口，按名称自然排序 (COM2 排在 COM10 之前)
+func List() ([]Port, error) {
+	list, err := enumerate()
+	if err != nil {
+		return nil, err
+	}
+	list = platformPorts(list)
+	Sort(list)
+	labelPorts(list)
+	return list, nil
+}
+
+// labelPorts 生成显示名称：友好名称 (不含串口名时附上串口名)，
+// 有多个同型号适配器时再附上序列号，序列号缺失或重复 (如 CH340 没有序列号) 时附上 USB 位置
+func labelPorts(list []Port) {
+	models := map[string]int{}
+	serials := map[string]int{}
+	for _, p := range list {
+		if p.IsUSB {
+			models[p.VID+":"+p.PID]++
+			serials[p.VID+":"+p.PID+":"+p.SerialNumber]++
+		}
+	}
+	for i := range list {
+		p := &list[i]
+		label := p.FriendlyName
+		if label == "" {
+			label = p.Product
+		}
+		switch {
+		case label == "":
+			label = p.Name
+		case !strings.Contains(label, p.Name):
+			label += " (" + p.Name + ")"
+		}
+		if p.IsUSB && models[p.VID+":"+p.PID] > 1 {
+			switch {
+			case p.SerialNumber != "" && serials[p.VID+":"+p.PID+":"+p.SerialNumber] == 1:
+				label += " SN " + p.SerialNumber
+			case p.LocationID != "":
+				label += " @ " + p.LocationID
+			}
+		}
+		p.Label = label
+	}
+}
+
+// enumerate 读取设备枚举器中的串口及 USB 描述符，不检测占用等状态
+func enumerate() ([]Port, error) {
+	details, err := enumerator.GetDetailedPortsList()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Port, 0, len(details))
+	for _, d := range details {
+		list = append(list, Port{
+			Name:         d.Name,
+			Path:         DevicePath(d.Name),
+			FriendlyName: d.Product,
+			IsUSB:        d.IsUSB,
+			VID:          d.VID,
+			PID:          d.PID,
+			SerialNumber: d.SerialNumber,
+			Recommended:  true,
+		})
+	}
+	return list, nil
+}
+
+// Names 返回串口名称列表
+func Names(list []Port) []string {
+	names := make([]string, len(list))
+	for i, p := range list {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// DevicePath 返回打开串口时使用的设备路径
+// Windows 下 COM10 及以上必须使用 \\.\COMn 形式，统一对所有 COM 口加上前缀
+func DevicePath(name string) string {
+	if isCOMName(name) {
+		return `\\.\` + strings.ToUpper(name)
+	}
+	return name
+}
+
+// isCOMName 判断是否为 Windows 串口名 (COMn)
+func isCOMName(name string) bool {
+	if len(name) < 4 || !strings.EqualFold(name[:3], "COM") {
+		return false
+	}
+	_, err := strconv.Atoi(name[3:])
+	return err == nil
+}
+
+// Sort 按名称自然排序
+func Sort(list []Port) {
+	sort.SliceStable(list, func(i, j int) bool {
+		return lessName(list[i].Name, list[j].Name)
+	})
+}
+
+// lessName 比较名称，末尾数字按数值比较
+func lessName(a, b string) bool {
+	pa, na := splitNumber(a)
+	pb, nb := splitNumber(b)
+	if pa != pb {
+		return pa < pb
+	}
+	if na != nb {
+		return na < nb
+	}
+	return a < b
+}
+
+// splitNumber 将名称拆分为前缀和末尾的数字，没有数字时返回 -1
+func splitNumber(name string) (string, int) {
+	i := len(name)
+	for i > 0 && name[i-1] >= '0' && name[i-1] <= '9' {
+		i--
+	}
+	n, err := strconv.Atoi(name[i:])
+	if err != nil {
+		return name, -1
+	}
+	return name[:i], n
+}
+
+// mergeRegistered 将系统串口表中存在、但设备枚举中缺失的串口标记为 Hidden 追加到列表，
+// 并用 friendly 补全缺失的友好名称
+func mergeRegistered(list []Port, registered []string, friendly map[string]string) []Port {
+	known := make(map[string]bool, len(list))
+	for _, p := range list {
+		known[strings.ToUpper(p.Name)] = true
+	}
+	for _, name := range registered {
+		if known[strings.ToUpper(name)] {
+			continue
+		}
+		known[strings.ToUpper(name)] = true
+		list = append(list, Port{Name: name, Path: DevicePath(name), Hidden: true, Recommended: true})
+	}
+	for i := range list {
+		if list[i].FriendlyName == "" {
+			list[i].FriendlyName = friendly[strings.ToUpper(list[i].Name)]
+		}
+	}
+	return list
+}
+
+// infString 去掉注册表字符串中的 INF 引用前缀，如 "@oem12.inf,%ftdi%;FTDI" -> "FTDI"
+func infString(v string) string {
+	if strings.HasPrefix(v, "@") {
+		if i := strings.LastIndex(v, ";"); i >= 0 {
+			return v[i+1:]
+		}
+	}
+	return v
+}
@@ -0,0 +1,14 @@
+//go:build darwin
+
+package ports
+
+import "os/exec"
+
+// platformPorts 通过 ioreg 读取 IOKit 属性补全 USB 信息，并区分 cu/tty 设备
+func platformPorts(list []Port) []Port {
+	out, err := exec.Command("ioreg", "-p", "IOService", "-l", "-w0").Output()
+	if err != nil {
+		return applyDarwin(list, nil)
+	}
+	return applyDarwin(list, parseIoreg(string(out)))
+}
@@ -0,0 +1,47 @@
+//go:build linux
+
+package ports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// platformPorts 从 sysfs 补全 USB 厂商、产品字符串及接口位置
+func platformPorts(list []Port) []Port {
+	return applySysfs(sysfsRoot, list)
+}
+
+func applySysfs(root string, list []Port) []Port {
+	for i := range list {
+		p := &list[i]
+		dev, iface, err := usbDeviceDir(root, p.Name)
+		if err != nil {
+			continue
+		}
+		if p.Manufacturer == "" {
+			p.Manufacturer = readSysfsString(filepath.Join(dev, "manufacturer"))
+		}
+		if p.Product == "" {
+			p.Product = readSysfsString(filepath.Join(dev, "product"))
+		}
+		if p.SerialNumber == "" {
+			p.SerialNumber = readSysfsString(filepath.Join(dev, "serial"))
+		}
+		if iface != "" {
+			p.LocationID = filepath.Base(iface)
+		} else {
+			p.LocationID = filepath.Base(dev)
+		}
+	}
+	return list
+}
+
+func readSysfsString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplySysfs(t *testing.T) {
+	root := t.TempDir()
+	usbDev := filepath.Join(root, "devices", "pci0000:00", "usb1", "1-1.2")
+	iface := filepath.Join(usbDev, "1-1.2:1.1", "ttyUSB1")
+	if err := os.MkdirAll(iface, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range map[string]string{
+		"busnum": "1\n", "devnum": "5\n", "manufacturer": "FTDI\n", "product": "Dual RS232-HS\n", "serial": "FT12AB\n",
+	} {
+		os.WriteFile(filepath.Join(usbDev, name), []byte(value), 0o644)
+	}
+	ttyDir := filepath.Join(root, "class", "tty", "ttyUSB1")
+	os.MkdirAll(ttyDir, 0o755)
+	if err := os.Symlink(iface, filepath.Join(ttyDir, "device")); err != nil {
+		t.Fatal(err)
+	}
+
+	list := applySysfs(root, []Port{{Name: "/dev/ttyUSB1"}, {Name: "/dev/ttyS0"}})
+	p := list[0]
+	if p.Manufacturer != "FTDI" || p.Product != "Dual RS232-HS" || p.SerialNumber != "FT12AB" || p.LocationID != "1-1.2:1.1" {
+		t.Errorf("Unexpected port %+v", p)
+	}
+	if list[1].LocationID != "" {
+		t.Errorf("Non-USB port should be unchanged, got %+v", list[1])
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !windows && !darwin && !linux
+
+package ports
+
+// platformPorts 其他平台无需额外处理
+func platformPorts(list []Port) []Port {
+	return list
+}
@@ -0,0 +1,83 @@
+package ports
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortNatural(t *testing.T) {
+	list := []Port{{Name: "COM10"}, {Name: "COM2"}, {Name: "/dev/ttyUSB10"}, {Name: "COM1"}, {Name: "/dev/ttyUSB9"}}
+	Sort(list)
+	got := Names(list)
+	want := []string{"/dev/ttyUSB9", "/dev/ttyUSB10", "COM1", "COM2", "COM10"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sort = %v, expected %v", got, want)
+	}
+}
+
+func TestDevicePath(t *testing.T) {
+	tests := map[string]string{
+		"COM3":         `\\.\COM3`,
+		"com12":        `\\.\COM12`,
+		"/dev/ttyACM0": "/dev/ttyACM0",
+		"COMX":         "COMX",
+	}
+	for name, want := range tests {
+		if got := DevicePath(name); got != want {
+			t.Errorf("DevicePath(%q) = %q, expected %q", name, got, want)
+		}
+	}
+}
+
+func TestMergeRegistered(t *testing.T) {
+	list := []Port{{Name: "COM3", FriendlyName: "USB-SERIAL CH340 (COM3)"}, {Name: "COM4"}}
+	list = mergeRegistered(list, []string{"COM3", "com4", "COM12"}, map[string]string{
+		"COM4":  "Standard Serial over Bluetooth link (COM4)",
+		"COM12": "com0com - serial port emulator (COM12)",
+		"COM3":  "ignored",
+	})
+	if len(list) != 3 {
+		t.Fatalf("Expected 3 ports, got %+v", list)
+	}
+	if list[0].FriendlyName != "USB-SERIAL CH340 (COM3)" || list[0].Hidden {
+		t.Errorf("Existing port should be unchanged: %+v", list[0])
+	}
+	if list[1].FriendlyName != "Standard Serial over Bluetooth link (COM4)" {
+		t.Errorf("Friendly name should be filled in: %+v", list[1])
+	}
+	if p := list[2]; p.Name != "COM12" || !p.Hidden || p.Path != `\\.\COM12` || p.FriendlyName == "" {
+		t.Errorf("Unexpected hidden port: %+v", p)
+	}
+}
+
+func TestLabelPorts(t *testing.T) {
+	list := []Port{
+		{Name: "COM3", FriendlyName: "USB-SERIAL CH340 (COM3)", IsUSB: true, VID: "1A86", PID: "7523", LocationID: "Port_#0001.Hub_#0002"},
+		{Name: "COM4", FriendlyName: "USB-SERIAL CH340 (COM4)", IsUSB: true, VID: "1A86", PID: "7523", LocationID: "Port_#0003.Hub_#0002"},
+		{Name: "/dev/ttyUSB0", Product: "FT232R USB UART", IsUSB: true, VID: "0403", PID: "6001", SerialNumber: "A50285BI"},
+		{Name: "/dev/ttyUSB1", Product: "FT232R USB UART", IsUSB: true, VID: "0403", PID: "6001", SerialNumber: "A9XKZ1QN"},
+		{Name: "/dev/ttyS0"},
+	}
+	labelPorts(list)
+	want := []string{
+		"USB-SERIAL CH340 (COM3) @ Port_#0001.Hub_#0002",
+		"USB-SERIAL CH340 (COM4) @ Port_#0003.Hub_#0002",
+		"FT232R USB UART (/dev/ttyUSB0) SN A50285BI",
+		"FT232R USB UART (/dev/ttyUSB1) SN A9XKZ1QN",
+		"/dev/ttyS0",
+	}
+	for i, p := range list {
+		if p.Label != want[i] {
+			t.Errorf("Label[%d] = %q, want %q", i, p.Label, want[i])
+		}
+	}
+}
+
+func TestInfString(t *testing.T) {
+	if got := infString("@oem12.inf,%ftdi%;FTDI"); got != "FTDI" {
+		t.Errorf("infString = %q", got)
+	}
+	if got := infString("wch.cn"); got != "wch.cn" {
+		t.Errorf("infString = %q", got)
+	}
+}
@@ -0,0 +1,142 @@
+//go:build windows
+
+package ports
+
+import (
+	"strings"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+)
+
+// platformPorts 补充设备枚举遗漏的串口、友好名称和占用状态
+func platformPorts(list []Port) []Port {
+	instances := deviceInstances()
+	list = mergeRegistered(list, registeredPorts(), friendlyNames(instances))
+	for i := range list {
+		p := &list[i]
+		p.Busy = isBusy(p.Path)
+		if inst, ok := instances[strings.ToUpper(p.Name)]; ok {
+			if p.Manufacturer == "" {
+				p.Manufacturer = inst.Manufacturer
+			}
+			p.LocationID = inst.Location
+		}
+	}
+	return list
+}
+
+// registeredPorts 读取 HARDWARE\DEVICEMAP\SERIALCOMM 中登记的全部串口
+// 被其他程序独占或驱动未正常注册到设备类的串口也会出现在这里
+func registeredPorts() []string {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `HARDWARE\DEVICEMAP\SERIALCOMM`, registry.QUERY_VALUE)
+	if err != nil {
+		return nil
+	}
+	defer key.Close()
+
+	values, err := key.ReadValueNames(0)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(values))
+	for _, v := range values {
+		if name, _, err := key.GetStringValue(v); err == nil && isCOMName(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// deviceInstance 串口对应的设备实例
+type deviceInstance struct {
+	FriendlyName string
+	InstanceID   string // 如 USB\VID_1A86&PID_7523\5&1A2B3C4D&0&2
+	Manufacturer string // Mfg，已去掉 INF 引用前缀
+	Location     string // LocationInformation，如 Port_#0002.Hub_#0001
+}
+
+// deviceInstances 扫描 SYSTEM\CurrentControlSet\Enum，建立 串口名 -> 设备实例 的映射
+// 设备实例位于 Enum\<总线>\<设备>\<实例>，串口名在实例的 Device Parameters\PortName 中
+func deviceInstances() map[string]deviceInstance {
+	instances := make(map[string]deviceInstance)
+	root, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Enum`, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return instances
+	}
+	defer root.Close()
+
+	buses, _ := root.ReadSubKeyNames(0)
+	for _, bus := range buses {
+		devices := subKeys(root, bus)
+		for _, device := range devices {
+			for _, instance := range subKeys(root, bus+`\`+device) {
+				path := bus + `\` + device + `\` + instance
+				port := stringValue(root, path+`\Device Parameters`, "PortName")
+				if !isCOMName(port) {
+					continue
+				}
+				instances[strings.ToUpper(port)] = deviceInstance{
+					FriendlyName: stringValue(root, path, "FriendlyName"),
+					InstanceID:   path,
+					Manufacturer: infString(stringValue(root, path, "Mfg")),
+					Location:     stringValue(root, path, "LocationInformation"),
+				}
+			}
+		}
+	}
+	return instances
+}
+
+// InstanceID 返回串口对应的设备实例路径 (相对于 SYSTEM\CurrentControlSet\Enum)，找不到时返回空字符串
+func InstanceID(port string) string {
+	return deviceInstances()[strings.ToUpper(port)].InstanceID
+}
+
+// friendlyNames 串口名 -> FriendlyName
+func friendlyNames(instances map[string]deviceInstance) map[string]string {
+	names := make(map[string]string)
+	for port, inst := range instances {
+		if inst.FriendlyName != "" {
+			names[port] = inst.FriendlyName
+		}
+	}
+	return names
+}
+
+func subKeys(root registry.Key, path string) []string {
+	key, err := registry.OpenKey(root, path, registry.ENUMERATE_SUB_KEYS)
+	if err != nil {
+		return nil
+	}
+	defer key.Close()
+	names, _ := key.ReadSubKeyNames(0)
+	return names
+}
+
+func stringValue(root registry.Key, path, name string) string {
+	key, err := registry.OpenKey(root, path, registry.QUERY_VALUE)
+	if err != nil {
+		return ""
+	}
+	defer key.Close()
+	value, _, err := key.GetStringValue(name)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// isBusy 以独占方式尝试打开串口，ERROR_ACCESS_DENIED 表示已被其他程序占用
+func isBusy(path string) bool {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return false
+	}
+	h, err := windows.CreateFile(p, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil, windows.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		return err == windows.ERROR_ACCESS_DENIED
+	}
+	windows.CloseHandle(h)
+	return false
+}
@@ -0,0 +1,10 @@
+package ports
+
+import "errors"
+
+var (
+	// ErrResetUnsupported 当前平台无法复位 USB 设备
+	ErrResetUnsupported = errors.New("usb device reset is not supported on this platform")
+	// ErrNotUSB 串口不是 USB 设备 (或找不到对应的 USB 设备)
+	ErrNotUSB = errors.New("port is not backed by a usb device")
+)
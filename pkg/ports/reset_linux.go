@@ -0,0 +1,83 @@
+//go:build linux
+
+package ports
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// usbdevfsReset USBDEVFS_RESET = _IO('U', 20)
+const usbdevfsReset = 0x5514
+
+// sysfsRoot sysfs 挂载点
+const sysfsRoot = "/sys"
+
+// Reset 对串口背后的 USB 设备执行总线复位，设备会断开并重新枚举
+// 需要对 /dev/bus/usb/BBB/DDD 有写权限 (通常需要 root 或 udev 规则)
+func Reset(ctx context.Context, port string) error {
+	node, err := usbDeviceNode(sysfsRoot, port)
+	if err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(node, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), usbdevfsReset, 0); errno != 0 {
+		return &os.PathError{Op: "USBDEVFS_RESET", Path: node, Err: errno}
+	}
+	return nil
+}
+
+// usbDeviceNode 通过 sysfs 找到 tty 对应的 USB 设备节点 (/dev/bus/usb/BBB/DDD)
+func usbDeviceNode(root, port string) (string, error) {
+	dir, _, err := usbDeviceDir(root, port)
+	if err != nil {
+		return "", err
+	}
+	bus, _ := readSysfsInt(filepath.Join(dir, "busnum"))
+	num, _ := readSysfsInt(filepath.Join(dir, "devnum"))
+	return fmt.Sprintf("/dev/bus/usb/%03d/%03d", bus, num), nil
+}
+
+// usbDeviceDir 返回 tty 所属的 USB 设备目录及 USB 接口目录 (不存在时为空)
+// /sys/class/tty/ttyUSB0/device 指向 USB 接口或其下的端口，向上查找带 busnum/devnum 的 USB 设备目录
+func usbDeviceDir(root, port string) (dev, iface string, err error) {
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	target, err := filepath.EvalSymlinks(filepath.Join(root, "class", "tty", filepath.Base(port), "device"))
+	if err != nil {
+		return "", "", ErrNotUSB
+	}
+	for dir := target; strings.HasPrefix(dir, root) && dir != root; dir = filepath.Dir(dir) {
+		if iface == "" && strings.Contains(filepath.Base(dir), ":") {
+			iface = dir
+		}
+		_, err1 := readSysfsInt(filepath.Join(dir, "busnum"))
+		_, err2 := readSysfsInt(filepath.Join(dir, "devnum"))
+		if err1 == nil && err2 == nil {
+			return dir, iface, nil
+		}
+	}
+	return "", "", ErrNotUSB
+}
+
+func readSysfsInt(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUSBDeviceNode(t *testing.T) {
+	root := t.TempDir()
+	usbDev := filepath.Join(root, "devices", "pci0000:00", "usb1", "1-2")
+	iface := filepath.Join(usbDev, "1-2:1.0", "ttyUSB0")
+	if err := os.MkdirAll(iface, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(usbDev, "busnum"), []byte("1\n"), 0o644)
+	os.WriteFile(filepath.Join(usbDev, "devnum"), []byte("12\n"), 0o644)
+
+	ttyDir := filepath.Join(root, "class", "tty", "ttyUSB0")
+	os.MkdirAll(ttyDir, 0o755)
+	if err := os.Symlink(iface, filepath.Join(ttyDir, "device")); err != nil {
+		t.Fatal(err)
+	}
+
+	node, err := usbDeviceNode(root, "/dev/ttyUSB0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node != "/dev/bus/usb/001/012" {
+		t.Errorf("Unexpected device node %q", node)
+	}
+
+	if _, err := usbDeviceNode(root, "/dev/ttyS0"); err != ErrNotUSB {
+		t.Errorf("Expected ErrNotUSB for a non-USB port, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !windows
+
+package ports
+
+import "context"
+
+// Reset macOS 等平台不允许用户态程序复位 USB 设备
+func Reset(ctx context.Context, port string) error {
+	return ErrResetUnsupported
+}
@@ -0,0 +1,24 @@
+//go:build windows
+
+package ports
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Reset 通过 pnputil 重启串口对应的设备实例，驱动会重新加载并重新枚举串口
+// 需要管理员权限，pnputil /restart-device 要求 Windows 10 2004 及以上
+func Reset(ctx context.Context, port string) error {
+	inst, ok := deviceInstances()[strings.ToUpper(port)]
+	if !ok {
+		return ErrNotUSB
+	}
+	out, err := exec.CommandContext(ctx, "pnputil", "/restart-device", inst.InstanceID).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("pnputil: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
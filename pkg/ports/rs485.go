@@ -0,0 +1,102 @@
+package ports
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.bug.st/serial"
+)
+
+// RS-485 收发方向的控制方式
+const (
+	RS485Auto   = "auto"   // 优先使用驱动的 RS-485 模式，不支持时由软件切换 RTS
+	RS485Native = "native" // 驱动控制 (Linux TIOCSRS485，Windows RTS_CONTROL_TOGGLE)
+	RS485RTS    = "rts"    // 每次发送前后由软件切换 RTS
+)
+
+// MaxRS485Delay RTS 切换前后延时的上限 (微秒)
+const MaxRS485Delay = 100000
+
+// RS485Config 半双工 RS-485 适配器的收发方向控制：发送期间将 RTS 置为发送电平，发送完毕后恢复为接收
+type RS485Config struct {
+	Mode          string `json:"mode"`          // auto (默认)、native、rts
+	ActiveLow     bool   `json:"activeLow"`     // 发送期间 RTS 为低电平 (默认高电平)
+	DelayBeforeUs int    `json:"delayBeforeUs"` // 切换到发送后、开始发送前的等待
+	DelayAfterUs  int    `json:"delayAfterUs"`  // 发送完毕后、切回接收前的等待
+}
+
+// ErrRS485Unsupported 当前平台或驱动不支持驱动控制的 RS-485 模式
+var ErrRS485Unsupported = errors.New("native RS-485 mode is not supported on this port")
+
+// Validate 检查配置是否有效
+func (c RS485Config) Validate() error {
+	switch c.Mode {
+	case "", RS485Auto, RS485Native, RS485RTS:
+	default:
+		return fmt.Errorf("rs485: unknown mode %q", c.Mode)
+	}
+	if c.DelayBeforeUs < 0 || c.DelayBeforeUs > MaxRS485Delay || c.DelayAfterUs < 0 || c.DelayAfterUs > MaxRS485Delay {
+		return fmt.Errorf("rs485: delay out of range 0-%dus", MaxRS485Delay)
+	}
+	return nil
+}
+
+// EnableRS485 为串口开启 RS-485 方向控制，返回实际使用的方式 (RS485Native 或 RS485RTS)
+// 使用 RS485RTS 时调用方须以 RS485Write 发送数据
+func EnableRS485(port serial.Port, c RS485Config) (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	if c.Mode != RS485RTS {
+		err := ErrRS485Unsupported
+		if h, ok := portHandle(port); ok {
+			err = setRS485(h, &c)
+		}
+		if err == nil {
+			return RS485Native, nil
+		}
+		if c.Mode == RS485Native {
+			return "", err
+		}
+	}
+	if err := port.SetRTS(c.ActiveLow); err != nil { // 空闲时处于接收状态
+		return "", err
+	}
+	return RS485RTS, nil
+}
+
+// DisableRS485 关闭驱动控制的 RS-485 模式 (软件切换不需要关闭)
+func DisableRS485(port serial.Port) error {
+	h, ok := portHandle(port)
+	if !ok {
+		return ErrRS485Unsupported
+	}
+	return setRS485(h, nil)
+}
+
+// RS485Write 由软件切换 RTS 发送数据：切换到发送、等待 DelayBeforeUs、写入并等待发送完毕、
+// 再等待 DelayAfterUs 后切回接收；无论写入是否成功都会切回接收
+func RS485Write(port serial.Port, c RS485Config, p []byte) (n int, err error) {
+	if err := port.SetRTS(!c.ActiveLow); err != nil {
+		return 0, err
+	}
+	defer func() {
+		if rerr := port.SetRTS(c.ActiveLow); err == nil {
+			err = rerr
+		}
+	}()
+	if c.DelayBeforeUs > 0 {
+		time.Sleep(time.Duration(c.DelayBeforeUs) * time.Microsecond)
+	}
+	if n, err = port.Write(p); err != nil {
+		return n, err
+	}
+	if err = port.Drain(); err != nil {
+		return n, err
+	}
+	if c.DelayAfterUs > 0 {
+		time.Sleep(time.Duration(c.DelayAfterUs) * time.Microsecond)
+	}
+	return n, nil
+}
@@ -0,0 +1,48 @@
+//go:build linux
+
+package ports
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// serial_rs485.flags
+const (
+	serRS485Enabled      = 1 << 0
+	serRS485RTSOnSend    = 1 << 1
+	serRS485RTSAfterSend = 1 << 2
+)
+
+// serialRS485 对应内核的 struct serial_rs485，延时以毫秒为单位
+type serialRS485 struct {
+	Flags              uint32
+	DelayRTSBeforeSend uint32
+	DelayRTSAfterSend  uint32
+	Padding            [5]uint32
+}
+
+// setRS485 通过 TIOCSRS485 开启 (c 非 nil) 或关闭驱动的 RS-485 模式
+// 只有部分 UART 驱动 (如 8250、imx、atmel、部分 USB 转换器) 支持，不支持时返回 ENOTTY
+func setRS485(h uintptr, c *RS485Config) error {
+	var r serialRS485
+	if c != nil {
+		r.Flags = serRS485Enabled
+		if c.ActiveLow {
+			r.Flags |= serRS485RTSAfterSend
+		} else {
+			r.Flags |= serRS485RTSOnSend
+		}
+		r.DelayRTSBeforeSend = uint32((c.DelayBeforeUs + 999) / 1000)
+		r.DelayRTSAfterSend = uint32((c.DelayAfterUs + 999) / 1000)
+	}
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, h, unix.TIOCSRS485, uintptr(unsafe.Pointer(&r)))
+	switch errno {
+	case 0:
+		return nil
+	case unix.ENOTTY, unix.EINVAL:
+		return ErrRS485Unsupported
+	}
+	return errno
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !windows
+
+package ports
+
+// setRS485 其他平台没有驱动控制的 RS-485 模式
+func setRS485(h uintptr, c *RS485Config) error {
+	return ErrRS485Unsupported
+}
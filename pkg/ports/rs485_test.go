@@ -0,0 +1,107 @@
+package ports
+
+import (
+	"errors"
+	"testing"
+
+	"go.bug.st/serial"
+)
+
+// recordPort 记录 RTS 切换与写入顺序的串口替身 (没有 handle 字段，驱动模式不可用)
+type recordPort struct {
+	serial.Port
+	calls    []string
+	writeErr error
+}
+
+func (p *recordPort) SetRTS(rts bool) error {
+	if rts {
+		p.calls = append(p.calls, "rts+")
+	} else {
+		p.calls = append(p.calls, "rts-")
+	}
+	return nil
+}
+
+func (p *recordPort) Write(b []byte) (int, error) {
+	p.calls = append(p.calls, "write:"+string(b))
+	if p.writeErr != nil {
+		return 0, p.writeErr
+	}
+	return len(b), nil
+}
+
+func (p *recordPort) Drain() error {
+	p.calls = append(p.calls, "drain")
+	return nil
+}
+
+func equalCalls(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRS485Validate(t *testing.T) {
+	valid := []RS485Config{{}, {Mode: RS485Auto}, {Mode: RS485Native}, {Mode: RS485RTS, DelayBeforeUs: 500, DelayAfterUs: MaxRS485Delay}}
+	for _, c := range valid {
+		if err := c.Validate(); err != nil {
+			t.Errorf("%+v: unexpected error %v", c, err)
+		}
+	}
+	invalid := []RS485Config{{Mode: "toggle"}, {DelayBeforeUs: -1}, {DelayAfterUs: MaxRS485Delay + 1}}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Errorf("%+v: expected error", c)
+		}
+	}
+}
+
+func TestEnableRS485Fallback(t *testing.T) {
+	p := &recordPort{}
+	mode, err := EnableRS485(p, RS485Config{})
+	if err != nil || mode != RS485RTS {
+		t.Fatalf("Expected fallback to rts, got %q, %v", mode, err)
+	}
+	if !equalCalls(p.calls, []string{"rts-"}) {
+		t.Errorf("Expected RTS idle low, got %v", p.calls)
+	}
+	if _, err := EnableRS485(&recordPort{}, RS485Config{Mode: RS485Native}); err != ErrRS485Unsupported {
+		t.Errorf("Expected ErrRS485Unsupported, got %v", err)
+	}
+	if err := DisableRS485(&recordPort{}); err != ErrRS485Unsupported {
+		t.Errorf("Expected ErrRS485Unsupported, got %v", err)
+	}
+}
+
+func TestRS485Write(t *testing.T) {
+	p := &recordPort{}
+	n, err := RS485Write(p, RS485Config{Mode: RS485RTS}, []byte("ab"))
+	if err != nil || n != 2 {
+		t.Fatalf("Expected 2 bytes written, got %d, %v", n, err)
+	}
+	if want := []string{"rts+", "write:ab", "drain", "rts-"}; !equalCalls(p.calls, want) {
+		t.Errorf("Expected %v, got %v", want, p.calls)
+	}
+
+	p = &recordPort{}
+	RS485Write(p, RS485Config{Mode: RS485RTS, ActiveLow: true}, []byte("x"))
+	if want := []string{"rts-", "write:x", "drain", "rts+"}; !equalCalls(p.calls, want) {
+		t.Errorf("Expected %v, got %v", want, p.calls)
+	}
+
+	// 写入失败时也要切回接收
+	p = &recordPort{writeErr: errors.New("io")}
+	if _, err := RS485Write(p, RS485Config{}, []byte("x")); err == nil {
+		t.Error("Expected write error")
+	}
+	if want := []string{"rts+", "write:x", "rts-"}; !equalCalls(p.calls, want) {
+		t.Errorf("Expected %v, got %v", want, p.calls)
+	}
+}
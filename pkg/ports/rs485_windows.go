@@ -0,0 +1,27 @@
+//go:build windows
+
+package ports
+
+import "golang.org/x/sys/windows"
+
+// dcbRtsToggle 有数据待发送时由驱动拉高 RTS，发送完毕后拉低
+const dcbRtsToggle = 0x00003000
+
+// setRS485 设置 DCB 的 fRtsControl 为 RTS_CONTROL_TOGGLE (c 为 nil 时恢复为 RTS_CONTROL_ENABLE)
+// 驱动只支持发送期间高电平且不支持延时，其余配置返回 ErrRS485Unsupported 以便改用软件切换
+func setRS485(h uintptr, c *RS485Config) error {
+	if c != nil && (c.ActiveLow || c.DelayBeforeUs > 0 || c.DelayAfterUs > 0) {
+		return ErrRS485Unsupported
+	}
+	var dcb windows.DCB
+	if err := windows.GetCommState(windows.Handle(h), &dcb); err != nil {
+		return err
+	}
+	dcb.Flags &^= dcbOutxCtsFlow | dcbRtsControl
+	if c != nil {
+		dcb.Flags |= dcbRtsToggle
+	} else {
+		dcb.Flags |= dcbRtsEnable
+	}
+	return windows.SetCommState(windows.Handle(h), &dcb)
+}
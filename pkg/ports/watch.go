@@ -0,0 +1,82 @@
+package ports
+
+import (
+	"sync"
+	"time"
+)
+
+// Snapshot 轻量枚举串口，不打开串口检测占用状态，适合周期轮询
+func Snapshot() ([]Port, error) {
+	list, err := enumerate()
+	if err != nil {
+		return nil, err
+	}
+	Sort(list)
+	labelPorts(list)
+	return list, nil
+}
+
+// Diff 按名称比较两次枚举结果，返回新增与移除的串口
+func Diff(prev, cur []Port) (added, removed []Port) {
+	before := make(map[string]bool, len(prev))
+	for _, p := range prev {
+		before[p.Name] = true
+	}
+	now := make(map[string]bool, len(cur))
+	for _, p := range cur {
+		now[p.Name] = true
+		if !before[p.Name] {
+			added = append(added, p)
+		}
+	}
+	for _, p := range prev {
+		if !now[p.Name] {
+			removed = append(removed, p)
+		}
+	}
+	return added, removed
+}
+
+// Watcher 周期枚举串口并报告插拔
+type Watcher struct {
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// Watch 每隔 interval 调用 enumerate，与上一次结果比较后在有变化时调用 onChange
+// 首次枚举只作为基准，不报告；枚举失败时跳过本次
+func Watch(interval time.Duration, enumerate func() ([]Port, error), onChange func(added, removed []Port)) *Watcher {
+	w := &Watcher{stop: make(chan struct{}), done: make(chan struct{})}
+	go func() {
+		defer close(w.done)
+		prev, err := enumerate()
+		ok := err == nil
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			}
+			cur, err := enumerate()
+			if err != nil {
+				continue
+			}
+			if ok {
+				if added, removed := Diff(prev, cur); len(added) > 0 || len(removed) > 0 {
+					onChange(added, removed)
+				}
+			}
+			prev, ok = cur, true
+		}
+	}()
+	return w
+}
+
+// Stop 停止轮询并等待后台 goroutine 退出，可重复调用
+func (w *Watcher) Stop() {
+	w.once.Do(func() { close(w.stop) })
+	<-w.done
+}
@@ -0,0 +1,64 @@
+package ports
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	prev := []Port{{Name: "COM1"}, {Name: "COM3"}}
+	cur := []Port{{Name: "COM1"}, {Name: "COM4"}}
+	added, removed := Diff(prev, cur)
+	if len(added) != 1 || added[0].Name != "COM4" || len(removed) != 1 || removed[0].Name != "COM3" {
+		t.Errorf("added=%v removed=%v", added, removed)
+	}
+	if added, removed := Diff(cur, cur); added != nil || removed != nil {
+		t.Error("Identical lists should have no changes")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	snapshots := [][]Port{
+		{{Name: "COM1"}},
+		nil, // 枚举失败
+		{{Name: "COM1"}, {Name: "COM7"}},
+		{{Name: "COM7"}},
+	}
+	var mu sync.Mutex
+	calls := 0
+	enumerate := func() ([]Port, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		i := calls
+		calls++
+		if i >= len(snapshots) {
+			i = len(snapshots) - 1
+		}
+		if snapshots[i] == nil {
+			return nil, errors.New("busy")
+		}
+		return snapshots[i], nil
+	}
+	changes := make(chan [2][]Port, 4)
+	w := Watch(time.Millisecond, enumerate, func(added, removed []Port) {
+		changes <- [2][]Port{added, removed}
+	})
+	defer w.Stop()
+
+	c := <-changes
+	if len(c[0]) != 1 || c[0][0].Name != "COM7" || len(c[1]) != 0 {
+		t.Errorf("Expected COM7 added, got %v", c)
+	}
+	c = <-changes
+	if len(c[0]) != 0 || len(c[1]) != 1 || c[1][0].Name != "COM1" {
+		t.Errorf("Expected COM1 removed, got %v", c)
+	}
+	w.Stop()
+	select {
+	case c := <-changes:
+		t.Errorf("Unexpected change %v", c)
+	default:
+	}
+}
@@ -0,0 +1,67 @@
+package powermeter
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseINALine 解析 INA226/INA219 转接板输出的一行读数
+// 支持 "v=3.301 i=12.5mA" 形式的键值 (键 v/vbus/voltage、i/current，值可带 V、mV、A、mA、uA 单位)，
+// 或按顺序给出电压 (V)、电流 (A) 的 "3.301,0.0125"；字段以逗号、分号或空白分隔
+func ParseINALine(line string) (Reading, bool) {
+	fields := strings.FieldsFunc(line, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+	var r Reading
+	var haveI bool
+	var bare []float64
+	for _, f := range fields {
+		key, val, ok := strings.Cut(f, "=")
+		if !ok {
+			key, val, ok = strings.Cut(f, ":")
+		}
+		if !ok {
+			v, ok := parseUnit(f)
+			if !ok {
+				return Reading{}, false
+			}
+			bare = append(bare, v)
+			continue
+		}
+		v, ok := parseUnit(val)
+		if !ok {
+			return Reading{}, false
+		}
+		switch strings.ToLower(key) {
+		case "v", "vbus", "voltage", "bus":
+			r.Voltage = v
+		case "i", "current":
+			r.Current, haveI = v, true
+		}
+	}
+	if !haveI && len(bare) == 2 {
+		r.Voltage, r.Current, haveI = bare[0], bare[1], true
+	}
+	return r, haveI
+}
+
+// parseUnit 解析带可选单位后缀的数值，换算为 V 或 A
+func parseUnit(s string) (float64, bool) {
+	scale := 1.0
+	lower := strings.ToLower(s)
+	for _, u := range []struct {
+		suffix string
+		scale  float64
+	}{{"ma", 1e-3}, {"ua", 1e-6}, {"µa", 1e-6}, {"mv", 1e-3}, {"a", 1}, {"v", 1}} {
+		if strings.HasSuffix(lower, u.suffix) {
+			lower, scale = strings.TrimSuffix(lower, u.suffix), u.scale
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(lower, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v * scale, true
+}
@@ -0,0 +1,91 @@
+package powermeter
+
+import "time"
+
+// Kind 功率计类型
+type Kind string
+
+const (
+	KindPPK2   Kind = "ppk2"   // Nordic Power Profiler Kit II (USB CDC)
+	KindINA226 Kind = "ina226" // 经串口输出读数的 INA226/INA219 转接板
+)
+
+// Reading 一个电流/电压读数，Time 为主机时间，与控制台输出同一时间基准
+type Reading struct {
+	Time    time.Time `json:"time"`
+	Current float64   `json:"current"` // A
+	Voltage float64   `json:"voltage"` // V，0 表示未知
+}
+
+// Power 功率 (W)
+func (r Reading) Power() float64 {
+	return r.Current * r.Voltage
+}
+
+// Averager 将高速采样按固定时间窗平均，降低绘图序列的点数
+type Averager struct {
+	Window time.Duration // <= 0 表示不平均，每个读数直接输出
+
+	start time.Time
+	n     int
+	sumI  float64
+	sumV  float64
+	sumT  int64
+}
+
+// Add 加入一个读数；r 超出当前时间窗时返回上一个时间窗的平均值
+func (a *Averager) Add(r Reading) (Reading, bool) {
+	if a.Window <= 0 {
+		return r, true
+	}
+	var out Reading
+	ok := false
+	if a.n > 0 && r.Time.Sub(a.start) >= a.Window {
+		out, ok = a.flush(), true
+	}
+	if a.n == 0 {
+		a.start = r.Time
+	}
+	a.n++
+	a.sumI += r.Current
+	a.sumV += r.Voltage
+	a.sumT += r.Time.Sub(a.start).Nanoseconds()
+	return out, ok
+}
+
+// flush 返回当前时间窗的平均值并清空；时间取窗内读数的平均时刻
+func (a *Averager) flush() Reading {
+	n := float64(a.n)
+	r := Reading{
+		Time:    a.start.Add(time.Duration(a.sumT / int64(a.n))),
+		Current: a.sumI / n,
+		Voltage: a.sumV / n,
+	}
+	a.n, a.sumI, a.sumV, a.sumT = 0, 0, 0, 0
+	return r
+}
+
+// maxGap 相邻读数间隔超过此值时不积分 (设备暂停或数据中断)
+const maxGap = time.Second
+
+// Energy 按梯形法累计电荷与能量
+type Energy struct {
+	Charge  float64 `json:"charge"` // C
+	Energy  float64 `json:"energy"` // J
+	Samples uint64  `json:"samples"`
+	Last    Reading `json:"last"`
+}
+
+// Add 累计一个读数
+func (e *Energy) Add(r Reading) {
+	if e.Samples > 0 {
+		dt := r.Time.Sub(e.Last.Time)
+		if dt > 0 && dt <= maxGap {
+			s := dt.Seconds()
+			e.Charge += (r.Current + e.Last.Current) / 2 * s
+			e.Energy += (r.Power() + e.Last.Power()) / 2 * s
+		}
+	}
+	e.Samples++
+	e.Last = r
+}
@@ -0,0 +1,110 @@
+package powermeter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestAveragerAndEnergy(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	avg := Averager{Window: 10 * time.Millisecond}
+	var out []Reading
+	for i := 0; i < 30; i++ {
+		r := Reading{Time: t0.Add(time.Duration(i) * time.Millisecond), Current: float64(i / 10), Voltage: 3}
+		if a, ok := avg.Add(r); ok {
+			out = append(out, a)
+		}
+	}
+	if len(out) != 2 || out[0].Current != 0 || out[1].Current != 1 || !out[1].Time.Equal(t0.Add(14500*time.Microsecond)) {
+		t.Fatalf("Unexpected averages %+v", out)
+	}
+
+	var e Energy
+	e.Add(Reading{Time: t0, Current: 0.1, Voltage: 3})
+	e.Add(Reading{Time: t0.Add(time.Second), Current: 0.3, Voltage: 3})
+	e.Add(Reading{Time: t0.Add(5 * time.Second), Current: 1, Voltage: 3}) // 间隔过大，不积分
+	if !near(e.Charge, 0.2) || !near(e.Energy, 0.6) || e.Samples != 3 {
+		t.Errorf("Unexpected energy %+v", e)
+	}
+}
+
+func TestPPK2Decode(t *testing.T) {
+	cal, err := ParsePPK2Metadata("Calibrated: 1\nR0: 1000.0\nR1: 100.0\nR2: 10.0\nR3: 1.0\nR4: 0.05\nO0: 10\nVDD: 3300\nHW: 1234\n")
+	if err != nil || cal.R[1] != 100 || cal.O[0] != 10 || cal.VDD != 3300 {
+		t.Fatalf("ParsePPK2Metadata = %+v, %v", cal, err)
+	}
+	if _, err := ParsePPK2Metadata("HW: 1\n"); err == nil {
+		t.Error("Metadata without calibration should fail")
+	}
+
+	var data []byte
+	for _, w := range []uint32{1000 | 1<<14 | 5<<18, 2000 | 0<<14} {
+		data = binary.LittleEndian.AppendUint32(data, w)
+	}
+	d := PPK2Decoder{Cal: cal}
+	got := d.Decode(data[:5])
+	got = append(got, d.Decode(data[5:])...)
+	want := []float64{4000 * ppkADCMult / 100, (8000 - 10) * ppkADCMult / 1000}
+	if len(got) != 2 || !near(got[0], want[0]) || !near(got[1], want[1]) {
+		t.Errorf("Decode = %v, want %v", got, want)
+	}
+
+	ts := PPK2Timestamps(time.Unix(10, 0), 3)
+	if !ts(2).Equal(time.Unix(10, 0)) || !ts(0).Equal(time.Unix(10, 0).Add(-20*time.Microsecond)) {
+		t.Error("Timestamps should end at the arrival time")
+	}
+}
+
+// fakePPK2 应答元数据并记录收到的命令
+type fakePPK2 struct {
+	bytes.Buffer
+	sent []byte
+}
+
+func (f *fakePPK2) Write(p []byte) (int, error) {
+	f.sent = append(f.sent, p...)
+	if p[0] == ppkGetMetadata {
+		f.WriteString("R0: 1000\nR1: 100\nR2: 10\nR3: 1\nR4: 0.05\nVDD: 1800\nEND\n")
+	}
+	return len(p), nil
+}
+
+func TestPPK2StartSourceMode(t *testing.T) {
+	f := &fakePPK2{}
+	cal, err := PPK2Start(f, 3300)
+	if err != nil || cal.VDD != 3300 {
+		t.Fatalf("PPK2Start = %+v, %v", cal, err)
+	}
+	want := []byte{ppkGetMetadata, ppkSetPowerMode, ppkModeSource, ppkRegulatorSet, 0x0C, 0xE4, ppkDeviceRunningSet, 1, ppkAverageStart}
+	if !bytes.Equal(f.sent, want) {
+		t.Errorf("Sent % X, want % X", f.sent, want)
+	}
+}
+
+func TestParseINALine(t *testing.T) {
+	cases := []struct {
+		line string
+		v, i float64
+		ok   bool
+	}{
+		{"v=3.301 i=12.5mA", 3.301, 0.0125, true},
+		{"Vbus:3300mV, current:250uA", 3.3, 0.00025, true},
+		{"3.3,0.02", 3.3, 0.02, true},
+		{"i=1.5", 0, 1.5, true},
+		{"INA226 ready", 0, 0, false},
+		{"v=3.3", 0, 0, false},
+	}
+	for _, c := range cases {
+		r, ok := ParseINALine(c.line)
+		if ok != c.ok || (ok && (!near(r.Voltage, c.v) || !near(r.Current, c.i))) {
+			t.Errorf("ParseINALine(%q) = %+v, %v", c.line, r, ok)
+		}
+	}
+}
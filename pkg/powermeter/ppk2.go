@@ -0,0 +1,169 @@
+package powermeter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PPK2 命令字节
+const (
+	ppkAverageStart     = 0x06
+	ppkAverageStop      = 0x07
+	ppkDeviceRunningSet = 0x0C
+	ppkRegulatorSet     = 0x0D
+	ppkSetPowerMode     = 0x11
+	ppkGetMetadata      = 0x19
+
+	ppkModeAmpere = 0x01
+	ppkModeSource = 0x02
+)
+
+// PPK2SampleRate PPK2 每秒输出的采样数
+const PPK2SampleRate = 100000
+
+// ppkADCMult ADC 计数到电压的换算系数
+const ppkADCMult = 1.8 / 163840
+
+// PPK2Calibration 设备元数据中的各量程分流电阻 (R0..R4) 与零点偏移 (O0..O4)
+type PPK2Calibration struct {
+	R   [5]float64 `json:"r"`
+	O   [5]float64 `json:"o"`
+	VDD int        `json:"vdd"` // 设备当前的输出电压 (mV)
+}
+
+// DefaultPPK2Calibration 未校准设备使用的标称值
+var DefaultPPK2Calibration = PPK2Calibration{R: [5]float64{1031.64, 101.65, 10.15, 0.94, 0.043}}
+
+// ParsePPK2Metadata 解析 GetMetadata 的应答 ("R0: 1003.37" 等行，以 "END" 结束)
+func ParsePPK2Metadata(text string) (PPK2Calibration, error) {
+	cal := DefaultPPK2Calibration
+	found := false
+	for _, line := range strings.Split(text, "\n") {
+		key, val, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil {
+			continue
+		}
+		switch {
+		case key == "VDD":
+			cal.VDD = int(v)
+		case len(key) == 2 && (key[0] == 'R' || key[0] == 'O') && key[1] >= '0' && key[1] <= '4':
+			if key[0] == 'R' {
+				cal.R[key[1]-'0'] = v
+			} else {
+				cal.O[key[1]-'0'] = v
+			}
+			found = true
+		}
+	}
+	if !found {
+		return cal, fmt.Errorf("no calibration in PPK2 metadata")
+	}
+	return cal, nil
+}
+
+// PPK2Decoder 将 PPK2 输出的 4 字节采样字转换为电流，采样字可跨越多次 Decode
+// 采样字：bit0-13 ADC 值，bit14-16 量程，bit18-23 计数器，bit24-31 数字通道
+type PPK2Decoder struct {
+	Cal     PPK2Calibration
+	partial []byte
+}
+
+// Decode 返回 data 中完整采样字对应的电流 (A)
+func (d *PPK2Decoder) Decode(data []byte) []float64 {
+	if len(d.partial) > 0 {
+		data = append(d.partial, data...)
+		d.partial = nil
+	}
+	n := len(data) / 4
+	out := make([]float64, 0, n)
+	for i := 0; i < n; i++ {
+		w := binary.LittleEndian.Uint32(data[i*4:])
+		adc := float64(w&0x3FFF) * 4
+		rng := (w >> 14) & 0x7
+		if rng > 4 {
+			continue
+		}
+		out = append(out, (adc-d.Cal.O[rng])*(ppkADCMult/d.Cal.R[rng]))
+	}
+	if rest := data[n*4:]; len(rest) > 0 {
+		d.partial = append([]byte(nil), rest...)
+	}
+	return out
+}
+
+// PPK2Timestamps 为一批在 arrived 时刻收到的 n 个采样按采样率倒推时间
+func PPK2Timestamps(arrived time.Time, n int) func(i int) time.Time {
+	const period = time.Second / PPK2SampleRate
+	return func(i int) time.Time {
+		return arrived.Add(-time.Duration(n-1-i) * period)
+	}
+}
+
+// PPK2Start 读取校准数据并开始测量
+// sourceMv > 0 时以源表模式向被测设备供电 (该电压同时用于计算功率)，否则为电流表模式
+func PPK2Start(rw io.ReadWriter, sourceMv int) (PPK2Calibration, error) {
+	if _, err := rw.Write([]byte{ppkGetMetadata}); err != nil {
+		return PPK2Calibration{}, err
+	}
+	meta, err := readUntil(rw, "END", 5*time.Second)
+	if err != nil {
+		return PPK2Calibration{}, err
+	}
+	cal, err := ParsePPK2Metadata(meta)
+	if err != nil {
+		return cal, err
+	}
+
+	cmds := [][]byte{{ppkSetPowerMode, ppkModeAmpere}}
+	if sourceMv > 0 {
+		cmds = [][]byte{
+			{ppkSetPowerMode, ppkModeSource},
+			{ppkRegulatorSet, byte(sourceMv >> 8), byte(sourceMv)},
+			{ppkDeviceRunningSet, 1},
+		}
+		cal.VDD = sourceMv
+	}
+	cmds = append(cmds, []byte{ppkAverageStart})
+	for _, c := range cmds {
+		if _, err := rw.Write(c); err != nil {
+			return cal, err
+		}
+	}
+	return cal, nil
+}
+
+// PPK2Stop 停止测量
+func PPK2Stop(w io.Writer) error {
+	_, err := w.Write([]byte{ppkAverageStop})
+	return err
+}
+
+// readUntil 读取直到出现 marker 或超时 (依赖串口的读超时返回)
+func readUntil(r io.Reader, marker string, timeout time.Duration) (string, error) {
+	var buf bytes.Buffer
+	chunk := make([]byte, 256)
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		n, err := r.Read(chunk)
+		buf.Write(chunk[:n])
+		if i := bytes.Index(buf.Bytes(), []byte(marker)); i >= 0 {
+			return buf.String()[:i], nil
+		}
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		if n == 0 && err == io.EOF {
+			break
+		}
+	}
+	return "", fmt.Errorf("timed out waiting for %q", marker)
+}
@@ -0,0 +1,74 @@
+package profiler
+
+// ITM/DWT 数据流中的 PC 采样解析
+// 硬件源包：头字节 bit2 为 1，bit[7:3] 为鉴别 ID，bit[1:0] 为负载长度 (1、2、4 字节)；
+// PC 采样的 ID 为 2，4 字节负载为采样到的 PC，1 字节负载 (值为 0) 表示内核处于睡眠
+
+const (
+	idPCSample = 2
+	hdrSync    = 0x00
+	hdrOverflw = 0x70
+)
+
+// Decoder 逐字节解析 SWO 输出的 ITM 数据流，包可跨越多次 Feed
+type Decoder struct {
+	state   int // 0 等待头字节，1 读取负载，2 跳过带延续位的包
+	hdr     byte
+	need    int
+	payload []byte
+
+	Overflows uint64 // 收到的溢出包数 (SWO 带宽不足时出现，采样会丢失)
+}
+
+// Feed 解析 data，对每个 PC 采样调用 pc(addr, sleep)；sleep 为 true 时 addr 无意义
+func (d *Decoder) Feed(data []byte, pc func(addr uint32, sleep bool)) {
+	for _, b := range data {
+		switch d.state {
+		case 1:
+			d.payload = append(d.payload, b)
+			if len(d.payload) == d.need {
+				d.state = 0
+				d.emit(pc)
+			}
+			continue
+		case 2:
+			if b&0x80 == 0 {
+				d.state = 0
+			}
+			continue
+		}
+
+		switch {
+		case b == hdrSync, b == 0x80:
+			// 同步包由若干 0x00 与结尾的 0x80 组成，逐字节忽略即可
+		case b == hdrOverflw:
+			d.Overflows++
+		case b&0x03 != 0:
+			// 软件 (stimulus) 或硬件源包
+			d.hdr = b
+			d.need = [4]int{0, 1, 2, 4}[b&0x03]
+			d.payload = d.payload[:0]
+			d.state = 1
+		default:
+			// 时间戳、扩展等协议包：bit7 为延续位
+			if b&0x80 != 0 {
+				d.state = 2
+			}
+		}
+	}
+}
+
+// emit 处理一个完整的源包
+func (d *Decoder) emit(pc func(addr uint32, sleep bool)) {
+	if d.hdr&0x04 == 0 || d.hdr>>3 != idPCSample {
+		return // 软件输出或其他硬件事件
+	}
+	if d.need == 1 {
+		pc(0, true)
+		return
+	}
+	if d.need == 4 {
+		p := d.payload
+		pc(uint32(p[0])|uint32(p[1])<<8|uint32(p[2])<<16|uint32(p[3])<<24, false)
+	}
+}
@@ -0,0 +1,104 @@
+package profiler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Entry 报告中的一个函数
+type Entry struct {
+	Name    string  `json:"name"`
+	Addr    uint32  `json:"addr"`
+	Samples uint64  `json:"samples"`
+	Percent float64 `json:"percent"`
+}
+
+// Report 采样统计
+type Report struct {
+	Samples   uint64  `json:"samples"`   // 全部采样 (含睡眠)
+	Sleep     uint64  `json:"sleep"`     // 内核睡眠的采样
+	Unknown   uint64  `json:"unknown"`   // 不属于任何已知函数的采样
+	Overflows uint64  `json:"overflows"` // SWO 溢出次数，非零说明有采样丢失，应降低采样率
+	Top       []Entry `json:"top"`
+}
+
+// Profile 累计 PC 采样，按函数统计最热点
+type Profile struct {
+	mu      sync.Mutex
+	dec     Decoder
+	syms    *Symbols
+	pcs     map[uint32]uint64
+	samples uint64
+	sleep   uint64
+}
+
+// New 创建统计，syms 为 nil 时报告按地址列出
+func New(syms *Symbols) *Profile {
+	return &Profile{syms: syms, pcs: make(map[uint32]uint64)}
+}
+
+// Feed 解析 SWO 数据并累计其中的 PC 采样
+func (p *Profile) Feed(data []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dec.Feed(data, func(addr uint32, sleep bool) {
+		p.samples++
+		if sleep {
+			p.sleep++
+			return
+		}
+		p.pcs[addr&^1]++
+	})
+}
+
+// Reset 清空已累计的采样
+func (p *Profile) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dec = Decoder{}
+	p.pcs = make(map[uint32]uint64)
+	p.samples, p.sleep = 0, 0
+}
+
+// Report 返回采样最多的 top 个函数 (top <= 0 表示全部)
+func (p *Profile) Report(top int) Report {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r := Report{Samples: p.samples, Sleep: p.sleep, Overflows: p.dec.Overflows}
+
+	byFunc := make(map[string]*Entry)
+	for pc, n := range p.pcs {
+		name, addr := fmt.Sprintf("0x%08X", pc), pc
+		if p.syms != nil {
+			sym, ok := p.syms.Lookup(pc)
+			if !ok {
+				r.Unknown += n
+				continue
+			}
+			name, addr = sym.Name, sym.Addr
+		}
+		e := byFunc[name]
+		if e == nil {
+			e = &Entry{Name: name, Addr: addr}
+			byFunc[name] = e
+		}
+		e.Samples += n
+	}
+	for _, e := range byFunc {
+		if r.Samples > 0 {
+			e.Percent = float64(e.Samples) * 100 / float64(r.Samples)
+		}
+		r.Top = append(r.Top, *e)
+	}
+	sort.Slice(r.Top, func(i, j int) bool {
+		if r.Top[i].Samples != r.Top[j].Samples {
+			return r.Top[i].Samples > r.Top[j].Samples
+		}
+		return r.Top[i].Addr < r.Top[j].Addr
+	})
+	if top > 0 && len(r.Top) > top {
+		r.Top = r.Top[:top]
+	}
+	return r
+}
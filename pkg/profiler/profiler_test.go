@@ -0,0 +1,139 @@
+package profiler
+
+import (
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// pcPacket 编码一个 4 字节的 PC 采样包
+func pcPacket(pc uint32) []byte {
+	return []byte{0x17, byte(pc), byte(pc >> 8), byte(pc >> 16), byte(pc >> 24)}
+}
+
+func TestDecoderExtractsPCSamples(t *testing.T) {
+	var stream []byte
+	stream = append(stream, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80) // 同步
+	stream = append(stream, pcPacket(0x08000101)...)
+	stream = append(stream, 0x01, 'A')                    // ITM 端口 0 的 1 字节软件输出
+	stream = append(stream, 0xC0, 0x85, 0x01)             // 带延续位的本地时间戳
+	stream = append(stream, 0x15, 0x00)                   // 睡眠采样
+	stream = append(stream, 0x70)                         // 溢出
+	stream = append(stream, 0x0F, 0x01, 0x02, 0x03, 0x04) // ID 1 的硬件事件 (非 PC 采样)
+	stream = append(stream, pcPacket(0x08000200)...)
+
+	var d Decoder
+	var pcs []uint32
+	sleeps := 0
+	// 逐字节输入，验证跨 Feed 的包
+	for i := range stream {
+		d.Feed(stream[i:i+1], func(addr uint32, sleep bool) {
+			if sleep {
+				sleeps++
+				return
+			}
+			pcs = append(pcs, addr)
+		})
+	}
+	if !reflect.DeepEqual(pcs, []uint32{0x08000101, 0x08000200}) || sleeps != 1 || d.Overflows != 1 {
+		t.Errorf("pcs=%X sleeps=%d overflows=%d", pcs, sleeps, d.Overflows)
+	}
+}
+
+func TestProfileReport(t *testing.T) {
+	syms := NewSymbols([]Symbol{
+		{Name: "main", Addr: 0x08000101, Size: 0x100},
+		{Name: "spin", Addr: 0x08000201},
+		{Name: "isr", Addr: 0x08001001, Size: 0x10},
+	})
+	p := New(syms)
+	var data []byte
+	for i := 0; i < 6; i++ {
+		data = append(data, pcPacket(0x08000210+uint32(i)*2)...)
+	}
+	for i := 0; i < 3; i++ {
+		data = append(data, pcPacket(0x08000120)...)
+	}
+	data = append(data, pcPacket(0x08001100)...) // 超出 isr 的大小，不计入任何函数
+	data = append(data, 0x15, 0x00)
+	p.Feed(data)
+
+	r := p.Report(1)
+	if r.Samples != 11 || r.Sleep != 1 || r.Unknown != 1 {
+		t.Errorf("Unexpected totals %+v", r)
+	}
+	if len(r.Top) != 1 || r.Top[0].Name != "spin" || r.Top[0].Samples != 6 {
+		t.Fatalf("Unexpected top %+v", r.Top)
+	}
+	all := p.Report(0)
+	names := []string{}
+	for _, e := range all.Top {
+		names = append(names, e.Name)
+	}
+	if strings.Join(names, ",") != "spin,main" || all.Unknown != 1 {
+		t.Errorf("Unexpected report %v unknown=%d", names, all.Unknown)
+	}
+
+	p.Reset()
+	if p.Report(0).Samples != 0 {
+		t.Error("Reset should clear samples")
+	}
+}
+
+func TestLoadSymbolsFromELF(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("test binary is not ELF")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	syms, err := LoadSymbols(exe)
+	if err != nil {
+		t.Skip(err) // 去除了符号表的构建
+	}
+	pc, _, _, _ := runtime.Caller(0)
+	sym, ok := syms.Lookup(uint32(pc))
+	if uint64(uint32(pc)) != uint64(pc) {
+		t.Skip("address does not fit in 32 bits")
+	}
+	if !ok || !strings.HasSuffix(sym.Name, "TestLoadSymbolsFromELF") {
+		t.Errorf("Lookup(0x%X) = %+v, %v", pc, sym, ok)
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	syms := NewSymbols([]Symbol{
+		{Name: "main", Addr: 0x08000101, Size: 0x100},
+		{Name: "Reset_Handler", Addr: 0x08000101, Size: 0x100}, // 别名
+		{Name: "unused", Addr: 0x08000301, Size: 0x10},
+		{Name: "spin", Addr: 0x08000201, Size: 0x20},
+	})
+	p := New(syms)
+	p.Feed(append(pcPacket(0x08000110), pcPacket(0x08000204)...))
+
+	c, ok := p.Coverage()
+	if !ok || c.Functions != 3 || c.Executed != 2 || c.Samples != 2 {
+		t.Fatalf("Unexpected coverage %+v", c)
+	}
+	if c.List[2].Name != "unused" || c.List[2].Samples != 0 {
+		t.Errorf("Unexpected list %+v", c.List)
+	}
+
+	var sb strings.Builder
+	if err := c.WriteLCOV(&sb, "fw.elf"); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+	for _, want := range []string{"SF:fw.elf\n", "FNDA:0,unused\n", "FNDA:1,spin\n", "FNF:3\nFNH:2\nend_of_record\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("LCOV output missing %q:\n%s", want, out)
+		}
+	}
+
+	if _, ok := New(nil).Coverage(); ok {
+		t.Error("Coverage without symbols should report false")
+	}
+}
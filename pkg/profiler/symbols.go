@@ -0,0 +1,73 @@
+package profiler
+
+import (
+	"debug/elf"
+	"fmt"
+	"sort"
+)
+
+// Symbol ELF 中的一个函数
+type Symbol struct {
+	Name string `json:"name"`
+	Addr uint32 `json:"addr"`
+	Size uint32 `json:"size"`
+}
+
+// Symbols 按地址排序的函数表
+type Symbols struct {
+	list []Symbol
+}
+
+// NewSymbols 由函数列表创建函数表，Thumb 函数地址的最低位会被清除
+func NewSymbols(list []Symbol) *Symbols {
+	s := &Symbols{list: make([]Symbol, 0, len(list))}
+	for _, sym := range list {
+		sym.Addr &^= 1
+		s.list = append(s.list, sym)
+	}
+	sort.Slice(s.list, func(i, j int) bool { return s.list[i].Addr < s.list[j].Addr })
+	return s
+}
+
+// LoadSymbols 读取 ELF 符号表中的函数
+func LoadSymbols(path string) (*Symbols, error) {
+	f, err := elf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	syms, err := f.Symbols()
+	if err != nil {
+		return nil, err
+	}
+	var list []Symbol
+	for _, s := range syms {
+		if elf.ST_TYPE(s.Info) != elf.STT_FUNC || s.Value == 0 || s.Name == "" {
+			continue
+		}
+		list = append(list, Symbol{Name: s.Name, Addr: uint32(s.Value), Size: uint32(s.Size)})
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("no function symbols in %s", path)
+	}
+	return NewSymbols(list), nil
+}
+
+// Len 函数个数
+func (s *Symbols) Len() int {
+	return len(s.list)
+}
+
+// Lookup 返回包含 addr 的函数；没有大小信息的函数延伸到下一个函数之前
+func (s *Symbols) Lookup(addr uint32) (Symbol, bool) {
+	addr &^= 1
+	i := sort.Search(len(s.list), func(i int) bool { return s.list[i].Addr > addr }) - 1
+	if i < 0 {
+		return Symbol{}, false
+	}
+	sym := s.list[i]
+	if sym.Size > 0 && addr >= sym.Addr+sym.Size {
+		return Symbol{}, false
+	}
+	return sym, true
+}
@@ -0,0 +1,131 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Stats 显示限速统计 (字节数均为累计值)
+type Stats struct {
+	MaxUpdatesPerSecond int    `json:"maxUpdatesPerSecond"` // 0 表示不限速
+	MaxBytesPerUpdate   int    `json:"maxBytesPerUpdate"`   // 0 表示不限制
+	Updates             uint64 `json:"updates"`
+	EmittedBytes        uint64 `json:"emittedBytes"`
+	DroppedBytes        uint64 `json:"droppedBytes"` // 为保持界面流畅而未显示的字节 (仍会完整记录)
+}
+
+// Coalescer 将高频到达的数据合并后按固定频率交给 emit，
+// 每次合并的数据超过 maxBytes 时丢弃较旧的部分并计数
+type Coalescer struct {
+	emit func([]byte)
+
+	mu        sync.Mutex
+	interval  time.Duration
+	rate      int
+	maxBytes  int
+	buf       []byte
+	scheduled bool
+	last      time.Time
+	stats     Stats
+
+	emitMu sync.Mutex // 保证 emit 按顺序、不并发调用
+}
+
+// New 创建合并器，updatesPerSecond <= 0 时直接透传
+func New(updatesPerSecond, maxBytes int, emit func([]byte)) *Coalescer {
+	c := &Coalescer{emit: emit}
+	c.SetRate(updatesPerSecond, maxBytes)
+	return c
+}
+
+// SetRate 修改每秒最多的更新次数及每次更新的最大字节数
+func (c *Coalescer) SetRate(updatesPerSecond, maxBytes int) {
+	if updatesPerSecond < 0 {
+		updatesPerSecond = 0
+	}
+	if maxBytes < 0 {
+		maxBytes = 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rate = updatesPerSecond
+	c.maxBytes = maxBytes
+	c.interval = 0
+	if updatesPerSecond > 0 {
+		c.interval = time.Second / time.Duration(updatesPerSecond)
+	}
+}
+
+// Add 加入一段数据；data 不会被保留，调用方可以复用
+func (c *Coalescer) Add(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	c.mu.Lock()
+	if c.interval <= 0 && len(c.buf) == 0 {
+		chunk := make([]byte, len(data))
+		copy(chunk, data)
+		c.stats.Updates++
+		c.stats.EmittedBytes += uint64(len(data))
+		c.mu.Unlock()
+		c.send(chunk)
+		return
+	}
+
+	c.buf = append(c.buf, data...)
+	if over := len(c.buf) - c.maxBytes; c.maxBytes > 0 && over > 0 {
+		n := copy(c.buf, c.buf[over:])
+		c.buf = c.buf[:n]
+		c.stats.DroppedBytes += uint64(over)
+	}
+	if !c.scheduled {
+		c.scheduled = true
+		wait := c.interval - time.Since(c.last)
+		if wait < 0 {
+			wait = 0
+		}
+		time.AfterFunc(wait, c.Flush)
+	}
+	c.mu.Unlock()
+}
+
+// Flush 立即交出已合并的数据
+func (c *Coalescer) Flush() {
+	c.mu.Lock()
+	data := c.buf
+	c.buf = nil
+	c.scheduled = false
+	c.last = time.Now()
+	if len(data) > 0 {
+		c.stats.Updates++
+		c.stats.EmittedBytes += uint64(len(data))
+	}
+	c.mu.Unlock()
+
+	if len(data) > 0 {
+		c.send(data)
+	}
+}
+
+func (c *Coalescer) send(data []byte) {
+	c.emitMu.Lock()
+	defer c.emitMu.Unlock()
+	c.emit(data)
+}
+
+// Stats 返回统计信息
+func (c *Coalescer) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.MaxUpdatesPerSecond = c.rate
+	s.MaxBytesPerUpdate = c.maxBytes
+	return s
+}
+
+// ResetStats 清零累计统计
+func (c *Coalescer) ResetStats() {
+	c.mu.Lock()
+	c.stats = Stats{}
+	c.mu.Unlock()
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	chunks [][]byte
+}
+
+func (r *recorder) emit(data []byte) {
+	r.mu.Lock()
+	r.chunks = append(r.chunks, data)
+	r.mu.Unlock()
+}
+
+func (r *recorder) snapshot() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]byte(nil), r.chunks...)
+}
+
+func TestPassThroughWhenUnlimited(t *testing.T) {
+	var r recorder
+	c := New(0, 0, r.emit)
+	buf := []byte("abc")
+	c.Add(buf)
+	buf[0] = 'x'
+	c.Add([]byte("def"))
+
+	chunks := r.snapshot()
+	if len(chunks) != 2 || string(chunks[0]) != "abc" || string(chunks[1]) != "def" {
+		t.Errorf("Unexpected chunks %q", chunks)
+	}
+}
+
+func TestCoalescesWithinInterval(t *testing.T) {
+	var r recorder
+	c := New(10, 0, r.emit)
+	for i := 0; i < 100; i++ {
+		c.Add([]byte{byte(i)})
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	chunks := r.snapshot()
+	if len(chunks) != 1 || len(chunks[0]) != 100 {
+		t.Fatalf("Expected a single coalesced update, got %d chunks", len(chunks))
+	}
+	if s := c.Stats(); s.Updates != 1 || s.EmittedBytes != 100 || s.DroppedBytes != 0 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+}
+
+func TestDropsOldestBeyondBudget(t *testing.T) {
+	var r recorder
+	c := New(10, 4, r.emit)
+	c.Add([]byte("0123"))
+	c.Add([]byte("456789"))
+	c.Flush()
+
+	chunks := r.snapshot()
+	if len(chunks) != 1 || !bytes.Equal(chunks[0], []byte("6789")) {
+		t.Fatalf("Expected newest bytes to be kept, got %q", chunks)
+	}
+	if s := c.Stats(); s.DroppedBytes != 6 || s.EmittedBytes != 4 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+}
+
+func TestRespectsUpdateRate(t *testing.T) {
+	var r recorder
+	c := New(20, 0, r.emit) // 50ms
+	deadline := time.Now().Add(220 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		c.Add([]byte("x"))
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	if n := len(r.snapshot()); n < 3 || n > 7 {
+		t.Errorf("Expected about 5 updates, got %d", n)
+	}
+}
@@ -0,0 +1,145 @@
+// Package reconnect 设备拔出后自动重连：轮询等待串口重新出现并以原参数重新打开
+package reconnect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 轮询间隔的范围及默认值
+const (
+	DefaultInterval = time.Second
+	MinInterval     = 100 * time.Millisecond
+	MaxInterval     = time.Minute
+)
+
+// Policy 自动重连配置
+type Policy struct {
+	Enabled    bool `json:"enabled"`
+	IntervalMs int  `json:"intervalMs"` // 轮询间隔，0 为 DefaultInterval
+	TimeoutSec int  `json:"timeoutSec"` // 放弃等待的时间，0 表示一直等待
+}
+
+// Validate 检查配置是否有效
+func (p Policy) Validate() error {
+	if p.IntervalMs != 0 {
+		d := time.Duration(p.IntervalMs) * time.Millisecond
+		if d < MinInterval || d > MaxInterval {
+			return fmt.Errorf("reconnect: interval %dms out of range %v-%v", p.IntervalMs, MinInterval, MaxInterval)
+		}
+	}
+	if p.TimeoutSec < 0 {
+		return fmt.Errorf("reconnect: negative timeout %d", p.TimeoutSec)
+	}
+	return nil
+}
+
+// Interval 返回轮询间隔
+func (p Policy) Interval() time.Duration {
+	if p.IntervalMs <= 0 {
+		return DefaultInterval
+	}
+	return time.Duration(p.IntervalMs) * time.Millisecond
+}
+
+// State 重连进度
+type State string
+
+const (
+	StateWaiting   State = "waiting"   // 等待设备重新出现
+	StateOpening   State = "opening"   // 设备已出现，正在打开
+	StateConnected State = "connected" // 已重新连接
+	StateFailed    State = "failed"    // 超时放弃
+	StateCanceled  State = "canceled"  // 被取消或已有其他连接
+)
+
+// Progress 重连进度事件
+type Progress struct {
+	State     State  `json:"state"`
+	Attempt   int    `json:"attempt"`
+	ElapsedMs int64  `json:"elapsedMs"`
+	Error     string `json:"error,omitempty"` // 最近一次打开失败的原因
+}
+
+var (
+	// ErrAbort 由 open 返回 (可包装)，表示不应继续重连，例如用户已建立了其他连接
+	ErrAbort = errors.New("reconnect: aborted")
+	// ErrTimeout 超过 Policy.TimeoutSec 仍未重连
+	ErrTimeout = errors.New("reconnect: timed out waiting for device")
+)
+
+// Run 每隔 Policy.Interval 检查设备是否出现，出现后调用 open，直到成功、超时或 ctx 取消
+// 每次状态变化都通过 report 通知
+func Run(ctx context.Context, p Policy, present func() bool, open func() error, report func(Progress)) error {
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	var lastErr string
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			report(Progress{State: StateCanceled, Attempt: attempt - 1, ElapsedMs: time.Since(start).Milliseconds(), Error: lastErr})
+			return ctx.Err()
+		case <-timer.C:
+		}
+		pr := Progress{State: StateWaiting, Attempt: attempt, Error: lastErr}
+		if present() {
+			pr.State = StateOpening
+			pr.ElapsedMs = time.Since(start).Milliseconds()
+			report(pr)
+			err := open()
+			pr.ElapsedMs = time.Since(start).Milliseconds()
+			switch {
+			case err == nil:
+				pr.State, pr.Error = StateConnected, ""
+				report(pr)
+				return nil
+			case errors.Is(err, ErrAbort):
+				pr.State, pr.Error = StateCanceled, err.Error()
+				report(pr)
+				return err
+			}
+			lastErr = err.Error()
+			pr.State, pr.Error = StateWaiting, lastErr
+		}
+		pr.ElapsedMs = time.Since(start).Milliseconds()
+		if p.TimeoutSec > 0 && time.Since(start) >= time.Duration(p.TimeoutSec)*time.Second {
+			pr.State = StateFailed
+			report(pr)
+			return ErrTimeout
+		}
+		report(pr)
+		timer.Reset(p.Interval())
+	}
+}
+
+// Load 读取配置，文件不存在时返回默认配置 (关闭)
+func Load(path string) (Policy, error) {
+	var p Policy
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return p, err
+	}
+	err = json.Unmarshal(data, &p)
+	return p, err
+}
+
+// Save 保存配置
+func Save(path string, p Policy) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
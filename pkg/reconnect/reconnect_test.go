@@ -0,0 +1,99 @@
+package reconnect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func fast() Policy { return Policy{Enabled: true, IntervalMs: 1} }
+
+func TestRunReconnects(t *testing.T) {
+	polls, opens := 0, 0
+	var states []State
+	err := Run(context.Background(), fast(),
+		func() bool { polls++; return polls >= 3 },
+		func() error {
+			opens++
+			if opens == 1 {
+				return errors.New("permission denied")
+			}
+			return nil
+		},
+		func(p Progress) { states = append(states, p.State) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []State{StateWaiting, StateWaiting, StateOpening, StateWaiting, StateOpening, StateConnected}
+	if fmt.Sprint(states) != fmt.Sprint(want) {
+		t.Errorf("States = %v, want %v", states, want)
+	}
+}
+
+func TestRunAbort(t *testing.T) {
+	var last Progress
+	err := Run(context.Background(), fast(),
+		func() bool { return true },
+		func() error { return fmt.Errorf("already connected: %w", ErrAbort) },
+		func(p Progress) { last = p })
+	if !errors.Is(err, ErrAbort) || last.State != StateCanceled {
+		t.Errorf("Expected abort, got %v, %+v", err, last)
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var last Progress
+	done := make(chan error)
+	go func() {
+		done <- Run(ctx, fast(), func() bool { return false }, func() error { return nil }, func(p Progress) { last = p })
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) || last.State != StateCanceled {
+		t.Errorf("Expected cancel, got %v, %+v", err, last)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	start := time.Now()
+	var last Progress
+	err := Run(context.Background(), Policy{IntervalMs: 200, TimeoutSec: 1}, func() bool { return false }, func() error { return nil }, func(pr Progress) { last = pr })
+	if err != ErrTimeout || last.State != StateFailed {
+		t.Errorf("Expected timeout, got %v, %+v", err, last)
+	}
+	if time.Since(start) < time.Second {
+		t.Error("Gave up before the timeout")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, p := range []Policy{{IntervalMs: 10}, {IntervalMs: 120000}, {TimeoutSec: -1}} {
+		if p.Validate() == nil {
+			t.Errorf("Expected %+v to be invalid", p)
+		}
+	}
+	if err := (Policy{Enabled: true, IntervalMs: 500, TimeoutSec: 60}).Validate(); err != nil {
+		t.Error(err)
+	}
+	if (Policy{}).Interval() != DefaultInterval {
+		t.Error("Zero interval should use the default")
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reconnect.json")
+	if p, err := Load(path); err != nil || p.Enabled {
+		t.Fatalf("Default should be disabled, got %+v, %v", p, err)
+	}
+	want := Policy{Enabled: true, IntervalMs: 500, TimeoutSec: 30}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Load(path); err != nil || got != want {
+		t.Errorf("Load = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,198 @@
+// Package redact 导出与共享会话时按正则规则遮盖密码、密钥等敏感内容，本地原始抓包不受影响
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// DefaultMask 规则未指定替换内容时使用的遮盖文本
+const DefaultMask = "******"
+
+// maxPending 流式脱敏时缓冲的不完整行的最大长度，超出后按已有内容脱敏输出
+const maxPending = 4096
+
+// Rule 一条脱敏规则，匹配 Pattern 的内容替换为 Mask
+type Rule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"` // 正则表达式 (RE2)，如 (?i)(psk|password)\s*[=:]\s*\S+
+	Mask    string `json:"mask"`    // 替换内容，可用 $1、${name} 引用分组；为空时使用 DefaultMask
+}
+
+// Config 脱敏设置
+type Config struct {
+	Enabled bool   `json:"enabled"`
+	Rules   []Rule `json:"rules"`
+}
+
+// Validate 检查所有规则能否编译
+func (c Config) Validate() error {
+	_, err := New(c.Rules)
+	return err
+}
+
+// Load 读取脱敏设置，文件不存在时返回空设置
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Config{Rules: []Rule{}}, nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+	var c Config
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+// Save 保存脱敏设置
+func Save(path string, c Config) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Redactor 编译后的规则集，可并发使用；nil 表示不脱敏
+type Redactor struct {
+	res   []*regexp.Regexp
+	masks [][]byte
+}
+
+// New 编译规则，没有规则时返回 nil
+func New(rules []Rule) (*Redactor, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	r := &Redactor{}
+	for i, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d (%s): %w", i+1, rule.Name, err)
+		}
+		if rule.Pattern == "" || re.Match(nil) {
+			return nil, fmt.Errorf("rule %d (%s): pattern matches empty text", i+1, rule.Name)
+		}
+		mask := rule.Mask
+		if mask == "" {
+			mask = DefaultMask
+		}
+		r.res = append(r.res, re)
+		r.masks = append(r.masks, []byte(mask))
+	}
+	return r, nil
+}
+
+// Apply 依次应用所有规则，没有匹配时返回原切片
+func (r *Redactor) Apply(b []byte) []byte {
+	if r == nil {
+		return b
+	}
+	for i, re := range r.res {
+		if re.Match(b) {
+			b = re.ReplaceAll(b, r.masks[i])
+		}
+	}
+	return b
+}
+
+// Stream 按行对一个方向的数据流脱敏，避免敏感内容被拆在两次读取之间而漏掉
+// 完整的行立即输出；不完整的行 (如提示符) 在空闲 idle 后或超过 maxPending 时输出。可并发使用
+type Stream struct {
+	mu    sync.Mutex
+	r     *Redactor
+	idle  time.Duration
+	emit  func(t time.Time, data []byte)
+	buf   []byte
+	start time.Time // 当前缓冲行第一个字节的到达时间
+	timer *time.Timer
+	gen   uint64 // 每次缓冲变化递增，使过期的定时器失效
+}
+
+// NewStream 创建脱敏流，idle 为 0 表示不完整的行只在 Flush 或超长时输出
+func NewStream(r *Redactor, idle time.Duration, emit func(t time.Time, data []byte)) *Stream {
+	return &Stream{r: r, idle: idle, emit: emit}
+}
+
+// Write 加入在 t 时刻到达的数据，输出其中脱敏后的完整行
+func (s *Stream) Write(t time.Time, data []byte) {
+	s.mu.Lock()
+	if len(s.buf) == 0 {
+		s.start = t
+	}
+	s.buf = append(s.buf, data...)
+	start := s.start
+	var out []byte
+	if i := bytes.LastIndexByte(s.buf, '\n'); i >= 0 {
+		out = s.take(i + 1)
+		s.start = t // 剩余部分来自本次数据
+	} else if len(s.buf) >= maxPending {
+		out = s.take(len(s.buf))
+	}
+	s.gen++
+	if len(s.buf) > 0 && s.idle > 0 {
+		s.arm(s.gen)
+	}
+	s.mu.Unlock()
+
+	if len(out) > 0 {
+		s.emit(start, out)
+	}
+}
+
+// Flush 立即输出缓冲中的不完整行
+func (s *Stream) Flush() {
+	s.mu.Lock()
+	s.gen++
+	out, start := s.take(len(s.buf)), s.start
+	s.mu.Unlock()
+	if len(out) > 0 {
+		s.emit(start, out)
+	}
+}
+
+// Reset 丢弃缓冲的数据
+func (s *Stream) Reset() {
+	s.mu.Lock()
+	s.gen++
+	s.buf = s.buf[:0]
+	s.mu.Unlock()
+}
+
+// arm 在空闲超时后输出不完整的行，期间有新数据到达则作废
+func (s *Stream) arm(gen uint64) {
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.timer = time.AfterFunc(s.idle, func() {
+		s.mu.Lock()
+		if s.gen != gen || len(s.buf) == 0 {
+			s.mu.Unlock()
+			return
+		}
+		out, start := s.take(len(s.buf)), s.start
+		s.mu.Unlock()
+		s.emit(start, out)
+	})
+}
+
+// take 取出缓冲的前 n 字节并脱敏，调用方需持有锁
+func (s *Stream) take(n int) []byte {
+	if n == 0 {
+		return nil
+	}
+	out := s.r.Apply(bytes.Clone(s.buf[:n]))
+	s.buf = append(s.buf[:0], s.buf[n:]...)
+	return out
+}
@@ -0,0 +1,92 @@
+package redact
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var wifi = Rule{Name: "wifi", Pattern: `(?i)(psk|password)(\s*[=:]\s*)\S+`, Mask: "${1}${2}***"}
+
+func TestApply(t *testing.T) {
+	r, err := New([]Rule{wifi, {Name: "key", Pattern: `[0-9a-f]{32}`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(r.Apply([]byte("PSK = hunter2 key 00112233445566778899aabbccddeeff\r\n")))
+	if got != "PSK = *** key "+DefaultMask+"\r\n" {
+		t.Errorf("Unexpected redaction %q", got)
+	}
+	var none *Redactor
+	if string(none.Apply([]byte("x"))) != "x" {
+		t.Error("nil Redactor should not change data")
+	}
+	if r, err := New(nil); r != nil || err != nil {
+		t.Errorf("No rules should give nil, got %v, %v", r, err)
+	}
+}
+
+func TestNewRejectsBadRules(t *testing.T) {
+	for _, rule := range []Rule{{Pattern: "("}, {Pattern: ""}, {Pattern: "a*"}} {
+		if _, err := New([]Rule{rule}); err == nil {
+			t.Errorf("Expected error for %q", rule.Pattern)
+		}
+	}
+}
+
+type collector struct {
+	mu  sync.Mutex
+	out []string
+}
+
+func (c *collector) emit(t time.Time, data []byte) {
+	c.mu.Lock()
+	c.out = append(c.out, string(data))
+	c.mu.Unlock()
+}
+
+func (c *collector) get() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.out...)
+}
+
+func TestStreamAcrossChunks(t *testing.T) {
+	r, _ := New([]Rule{wifi})
+	var c collector
+	s := NewStream(r, 0, c.emit)
+	now := time.Now()
+	s.Write(now, []byte("boot ok\npass"))
+	s.Write(now, []byte("word: hun"))
+	s.Write(now, []byte("ter2\nlogin: "))
+	s.Flush()
+	if got := strings.Join(c.get(), "|"); got != "boot ok\n|password: ***\n|login: " {
+		t.Errorf("Unexpected output %q", got)
+	}
+}
+
+func TestStreamIdleFlush(t *testing.T) {
+	var c collector
+	s := NewStream(nil, 20*time.Millisecond, c.emit)
+	s.Write(time.Now(), []byte("login: "))
+	time.Sleep(80 * time.Millisecond)
+	if got := c.get(); len(got) != 1 || got[0] != "login: " {
+		t.Errorf("Partial line should be flushed after idle, got %q", got)
+	}
+}
+
+func TestConfigLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redaction.json")
+	if c, err := Load(path); err != nil || c.Enabled || len(c.Rules) != 0 {
+		t.Errorf("Unexpected default %+v, %v", c, err)
+	}
+	want := Config{Enabled: true, Rules: []Rule{wifi}}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := Load(path); err != nil || !c.Enabled || c.Rules[0] != wifi {
+		t.Errorf("Unexpected config %+v, %v", c, err)
+	}
+}
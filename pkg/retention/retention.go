@@ -0,0 +1,185 @@
+// Package retention 抓包文件的保留期限与磁盘配额：计算需要清理的文件并统计占用
+// 加书签的会话与正在写入的文件不会被清理
+package retention
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Policy 保留策略，各项为 0 表示不限制
+type Policy struct {
+	MaxAgeDays int   `json:"maxAgeDays"` // 超过天数 (按最后写入时间) 的会话自动删除
+	QuotaMB    int64 `json:"quotaMb"`    // 总占用超过配额时从最旧的会话开始删除
+}
+
+// MaxAge 返回保留期限
+func (p Policy) MaxAge() time.Duration {
+	return time.Duration(p.MaxAgeDays) * 24 * time.Hour
+}
+
+// QuotaBytes 返回配额字节数
+func (p Policy) QuotaBytes() int64 {
+	return p.QuotaMB << 20
+}
+
+// ErrInvalidPolicy 策略参数为负数
+var ErrInvalidPolicy = errors.New("retention: negative limit")
+
+// Validate 检查策略
+func (p Policy) Validate() error {
+	if p.MaxAgeDays < 0 || p.QuotaMB < 0 {
+		return ErrInvalidPolicy
+	}
+	return nil
+}
+
+// File 一个受管理的文件
+type File struct {
+	ID        string    `json:"id"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	Modified  time.Time `json:"modified"`
+	Protected bool      `json:"protected"` // 已加书签
+	Active    bool      `json:"active"`    // 正在写入
+}
+
+// Reason 文件被清理的原因
+const (
+	ReasonExpired = "expired" // 超过保留期限
+	ReasonQuota   = "quota"   // 超出磁盘配额
+)
+
+// Removal 计划删除的文件
+type Removal struct {
+	File
+	Reason string `json:"reason"`
+}
+
+// Plan 按策略计算需要删除的文件：先删除过期的，再从最旧的开始删除直到不超过配额
+// 受保护和正在写入的文件计入占用但不会被删除，因此它们本身超出配额时结果仍可能超额
+func Plan(files []File, p Policy, now time.Time) []Removal {
+	sorted := append([]File(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Modified.Before(sorted[j].Modified) })
+
+	var total int64
+	for _, f := range sorted {
+		total += f.Size
+	}
+	var out []Removal
+	removed := make([]bool, len(sorted))
+	if age := p.MaxAge(); age > 0 {
+		for i, f := range sorted {
+			if deletable(f) && now.Sub(f.Modified) > age {
+				out = append(out, Removal{File: f, Reason: ReasonExpired})
+				removed[i] = true
+				total -= f.Size
+			}
+		}
+	}
+	if quota := p.QuotaBytes(); quota > 0 {
+		for i, f := range sorted {
+			if total <= quota {
+				break
+			}
+			if !removed[i] && deletable(f) {
+				out = append(out, Removal{File: f, Reason: ReasonQuota})
+				total -= f.Size
+			}
+		}
+	}
+	return out
+}
+
+func deletable(f File) bool {
+	return !f.Protected && !f.Active
+}
+
+// Usage 磁盘占用统计
+type Usage struct {
+	Files          int       `json:"files"`
+	Bytes          int64     `json:"bytes"`
+	ProtectedFiles int       `json:"protectedFiles"`
+	ProtectedBytes int64     `json:"protectedBytes"`
+	QuotaBytes     int64     `json:"quotaBytes"` // 0 表示不限
+	Oldest         time.Time `json:"oldest"`
+}
+
+// Summarize 统计占用
+func Summarize(files []File, p Policy) Usage {
+	u := Usage{Files: len(files), QuotaBytes: p.QuotaBytes()}
+	for _, f := range files {
+		u.Bytes += f.Size
+		if f.Protected {
+			u.ProtectedFiles++
+			u.ProtectedBytes += f.Size
+		}
+		if u.Oldest.IsZero() || f.Modified.Before(u.Oldest) {
+			u.Oldest = f.Modified
+		}
+	}
+	return u
+}
+
+// Load 读取策略，文件不存在时返回不限制
+func Load(path string) (Policy, error) {
+	var p Policy
+	err := loadJSON(path, &p)
+	return p, err
+}
+
+// Save 保存策略
+func Save(path string, p Policy) error {
+	return saveJSON(path, p)
+}
+
+// LoadBookmarks 读取加书签的会话 ID，文件不存在时返回空集合
+func LoadBookmarks(path string) (map[string]bool, error) {
+	var ids []string
+	if err := loadJSON(path, &ids); err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set, nil
+}
+
+// SaveBookmarks 保存加书签的会话 ID
+func SaveBookmarks(path string, set map[string]bool) error {
+	ids := []string{}
+	for id, ok := range set {
+		if ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return saveJSON(path, ids)
+}
+
+func loadJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func saveJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,74 @@
+package retention
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ids(list []Removal) []string {
+	out := []string{}
+	for _, r := range list {
+		out = append(out, r.ID+":"+r.Reason)
+	}
+	return out
+}
+
+func TestPlan(t *testing.T) {
+	now := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	files := []File{
+		{ID: "new", Size: 3 << 20, Modified: now.Add(-time.Hour), Active: true},
+		{ID: "old", Size: 1 << 20, Modified: now.Add(-40 * day)},
+		{ID: "kept", Size: 4 << 20, Modified: now.Add(-50 * day), Protected: true},
+		{ID: "mid", Size: 2 << 20, Modified: now.Add(-10 * day)},
+		{ID: "recent", Size: 2 << 20, Modified: now.Add(-2 * day)},
+	}
+	got := ids(Plan(files, Policy{MaxAgeDays: 30, QuotaMB: 10}, now))
+	if want := []string{"old:expired", "mid:quota"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Plan = %v, expected %v", got, want)
+	}
+	if got := Plan(files, Policy{}, now); len(got) != 0 {
+		t.Errorf("No limits should delete nothing, got %v", ids(got))
+	}
+	// 受保护和正在写入的文件本身超出配额时不删除它们
+	got = ids(Plan(files, Policy{QuotaMB: 1}, now))
+	if want := []string{"old:quota", "mid:quota", "recent:quota"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Plan = %v, expected %v", got, want)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Now()
+	u := Summarize([]File{
+		{Size: 10, Modified: now},
+		{Size: 5, Modified: now.Add(-time.Hour), Protected: true},
+	}, Policy{QuotaMB: 1})
+	if u.Files != 2 || u.Bytes != 15 || u.ProtectedFiles != 1 || u.ProtectedBytes != 5 || u.QuotaBytes != 1<<20 || !u.Oldest.Equal(now.Add(-time.Hour)) {
+		t.Errorf("Unexpected usage %+v", u)
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	dir := t.TempDir()
+	if p, err := Load(filepath.Join(dir, "missing.json")); err != nil || p != (Policy{}) {
+		t.Errorf("Unexpected default %+v, %v", p, err)
+	}
+	if err := Save(filepath.Join(dir, "p.json"), Policy{MaxAgeDays: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := Load(filepath.Join(dir, "p.json")); p.MaxAgeDays != 7 {
+		t.Errorf("Unexpected policy %+v", p)
+	}
+
+	path := filepath.Join(dir, "bookmarks.json")
+	SaveBookmarks(path, map[string]bool{"b": true, "a": true, "x": false})
+	set, err := LoadBookmarks(path)
+	if err != nil || !reflect.DeepEqual(set, map[string]bool{"a": true, "b": true}) {
+		t.Errorf("Unexpected bookmarks %v, %v", set, err)
+	}
+	if (Policy{QuotaMB: -1}).Validate() == nil {
+		t.Error("Negative quota should be rejected")
+	}
+}
@@ -0,0 +1,348 @@
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLineLength 超过该长度仍未遇到换行时按一行处理
+const maxLineLength = 4096
+
+// DefaultCooldown 规则未设置冷却时间时，两次触发动作的最小间隔
+const DefaultCooldown = 5 * time.Second
+
+// Action 规则命中后执行的动作，Params 的含义由动作类型决定
+type Action struct {
+	Type   string            `json:"type"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// Rule 对接收文本逐行匹配的规则
+type Rule struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Pattern       string   `json:"pattern"`
+	Regex         bool     `json:"regex"`
+	CaseSensitive bool     `json:"caseSensitive"`
+	Enabled       bool     `json:"enabled"`
+	CooldownMs    int      `json:"cooldownMs,omitempty"` // 0 表示使用 DefaultCooldown，负数表示不限制
+	Actions       []Action `json:"actions"`
+}
+
+// Match 一次命中
+type Match struct {
+	RuleID     string    `json:"ruleId"`
+	RuleName   string    `json:"ruleName"`
+	Line       string    `json:"line"`
+	Groups     []string  `json:"groups,omitempty"` // 正则的捕获组
+	Time       time.Time `json:"time"`
+	Suppressed int       `json:"suppressed"` // 上次执行动作后因冷却而未执行的命中次数
+}
+
+// Handler 执行一种动作
+type Handler func(ctx context.Context, m Match, a Action) error
+
+type compiled struct {
+	Rule
+	re         *regexp.Regexp
+	needle     string
+	lastFired  time.Time
+	suppressed int
+}
+
+// Engine 规则引擎：将接收数据切分成行，依次匹配规则并执行动作，可并发使用
+type Engine struct {
+	mu       sync.Mutex
+	rules    []*compiled
+	handlers map[string]Handler
+	line     []byte
+	onError  func(Match, Action, error)
+	observe  func(Match)
+	watches  map[*watch]struct{}
+	ctx      context.Context
+}
+
+// Condition 停止条件，用于在设备异常时中止自动化 (定时发送序列、命令批处理、自动发送)
+type Condition struct {
+	Patterns []Rule // 接收文本命中任一模式即满足，只使用 Pattern、Regex 与 CaseSensitive，不执行动作也不受冷却限制
+	Alert    bool   // 任一规则触发 (未被冷却抑制) 即满足
+}
+
+// watch 一个已注册的停止条件
+type watch struct {
+	patterns []*compiled
+	alert    bool
+	fn       func(m Match, alert bool)
+}
+
+// New 创建规则引擎，ctx 结束后不再执行动作；onError 接收动作执行失败的错误 (可为 nil)
+func New(ctx context.Context, onError func(Match, Action, error)) *Engine {
+	return &Engine{handlers: map[string]Handler{}, watches: map[*watch]struct{}{}, onError: onError, ctx: ctx}
+}
+
+// Handle 注册动作类型的处理函数
+func (e *Engine) Handle(actionType string, h Handler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.handlers[actionType] = h
+}
+
+// Observe 设置每次触发 (未被冷却抑制) 时的回调，在执行动作之前调用
+func (e *Engine) Observe(fn func(Match)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.observe = fn
+}
+
+// Watch 注册停止条件，条件满足时调用一次 fn 并自动注销；alert 表示由规则触发，
+// 此时 m 为该规则的命中，否则 m.RuleID 与 m.RuleName 取自命中的模式。返回的函数用于提前注销
+func (e *Engine) Watch(c Condition, fn func(m Match, alert bool)) (cancel func(), err error) {
+	w := &watch{alert: c.Alert, fn: fn}
+	for _, p := range c.Patterns {
+		p.Enabled = true
+		cp, err := compile(p)
+		if err != nil {
+			return nil, err
+		}
+		w.patterns = append(w.patterns, cp)
+	}
+	e.mu.Lock()
+	e.watches[w] = struct{}{}
+	e.mu.Unlock()
+	return func() {
+		e.mu.Lock()
+		delete(e.watches, w)
+		e.mu.Unlock()
+	}, nil
+}
+
+// ErrUnknownAction 规则使用了未注册的动作类型
+var ErrUnknownAction = errors.New("rules: unknown action")
+
+// SetRules 替换全部规则，任一规则无效时保持原规则不变
+func (e *Engine) SetRules(rules []Rule) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	list := make([]*compiled, 0, len(rules))
+	for _, r := range rules {
+		c, err := compile(r)
+		if err != nil {
+			return err
+		}
+		for _, a := range r.Actions {
+			if _, ok := e.handlers[a.Type]; !ok {
+				return fmt.Errorf("%w: %s (rule %s)", ErrUnknownAction, a.Type, r.Name)
+			}
+		}
+		list = append(list, c)
+	}
+	e.rules = list
+	return nil
+}
+
+// Rules 返回当前规则
+func (e *Engine) Rules() []Rule {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	rules := make([]Rule, len(e.rules))
+	for i, c := range e.rules {
+		rules[i] = c.Rule
+	}
+	return rules
+}
+
+func compile(r Rule) (*compiled, error) {
+	if r.Pattern == "" {
+		return nil, fmt.Errorf("rules: rule %q has an empty pattern", r.Name)
+	}
+	c := &compiled{Rule: r}
+	if r.Regex {
+		expr := r.Pattern
+		if !r.CaseSensitive {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("rules: rule %q: %w", r.Name, err)
+		}
+		c.re = re
+	} else {
+		c.needle = r.Pattern
+		if !r.CaseSensitive {
+			c.needle = strings.ToLower(c.needle)
+		}
+	}
+	return c, nil
+}
+
+// match 判断一行是否命中，返回正则捕获组
+func (c *compiled) match(line string) ([]string, bool) {
+	if c.re != nil {
+		m := c.re.FindStringSubmatch(line)
+		if m == nil {
+			return nil, false
+		}
+		return m[1:], true
+	}
+	if !c.CaseSensitive {
+		line = strings.ToLower(line)
+	}
+	return nil, strings.Contains(line, c.needle)
+}
+
+// cooldown 返回规则的冷却时间
+func (c *compiled) cooldown() time.Duration {
+	switch {
+	case c.CooldownMs < 0:
+		return 0
+	case c.CooldownMs == 0:
+		return DefaultCooldown
+	}
+	return time.Duration(c.CooldownMs) * time.Millisecond
+}
+
+// Feed 处理一段接收数据，t 为到达时间
+func (e *Engine) Feed(t time.Time, data []byte) {
+	e.mu.Lock()
+	var pending []func()
+	e.line = append(e.line, data...)
+	for {
+		i := bytes.IndexByte(e.line, '\n')
+		if i < 0 {
+			if len(e.line) < maxLineLength {
+				break
+			}
+			i = len(e.line) - 1
+		}
+		line := strings.TrimRight(string(e.line[:i+1]), "\r\n")
+		e.line = e.line[:copy(e.line, e.line[i+1:])]
+		pending = append(pending, e.matchLocked(t, line)...)
+	}
+	e.mu.Unlock()
+
+	// 动作可能较慢 (网络请求等)，在锁外执行
+	for _, run := range pending {
+		run()
+	}
+}
+
+// matchLocked 匹配一行，返回需要执行的动作；满足的停止条件排在动作之前，以便尽快中止自动化
+func (e *Engine) matchLocked(t time.Time, line string) []func() {
+	var pending []func()
+	var fired *Match
+	for _, c := range e.rules {
+		if !c.Enabled {
+			continue
+		}
+		groups, ok := c.match(line)
+		if !ok {
+			continue
+		}
+		if cd := c.cooldown(); cd > 0 && !c.lastFired.IsZero() && t.Sub(c.lastFired) < cd {
+			c.suppressed++
+			continue
+		}
+		m := Match{RuleID: c.ID, RuleName: c.Name, Line: line, Groups: groups, Time: t, Suppressed: c.suppressed}
+		c.lastFired = t
+		c.suppressed = 0
+		if fired == nil {
+			fired = &m
+		}
+		if e.observe != nil {
+			observe := e.observe
+			pending = append(pending, func() { observe(m) })
+		}
+		for _, a := range c.Actions {
+			h := e.handlers[a.Type]
+			a := a
+			pending = append(pending, func() {
+				if e.ctx.Err() != nil {
+					return
+				}
+				if err := h(e.ctx, m, a); err != nil && e.onError != nil {
+					e.onError(m, a, err)
+				}
+			})
+		}
+	}
+	if len(e.watches) > 0 {
+		pending = append(e.watchLocked(t, line, fired), pending...)
+	}
+	return pending
+}
+
+// watchLocked 检查停止条件，返回满足条件的回调并注销这些条件
+func (e *Engine) watchLocked(t time.Time, line string, fired *Match) []func() {
+	var pending []func()
+	for w := range e.watches {
+		var m Match
+		alert := false
+		if w.alert && fired != nil {
+			m, alert = *fired, true
+		} else {
+			hit := false
+			for _, p := range w.patterns {
+				if groups, ok := p.match(line); ok {
+					m = Match{RuleID: p.ID, RuleName: p.Name, Line: line, Groups: groups, Time: t}
+					hit = true
+					break
+				}
+			}
+			if !hit {
+				continue
+			}
+		}
+		delete(e.watches, w)
+		fn := w.fn
+		pending = append(pending, func() { fn(m, alert) })
+	}
+	return pending
+}
+
+// Reset 丢弃未完成的行 (如重新连接后)
+func (e *Engine) Reset() {
+	e.mu.Lock()
+	e.line = e.line[:0]
+	e.mu.Unlock()
+}
+
+// Load 从 JSON 文件读取规则，文件不存在时返回空列表
+func Load(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("rules: %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Save 将规则写入 JSON 文件
+func Save(path string, rules []Rule) error {
+	data, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
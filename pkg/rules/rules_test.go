@@ -0,0 +1,142 @@
+package rules
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newEngine(t *testing.T, rules ...Rule) (*Engine, *[]Match) {
+	t.Helper()
+	var matches []Match
+	e := New(context.Background(), nil)
+	e.Handle("record", func(_ context.Context, m Match, _ Action) error {
+		matches = append(matches, m)
+		return nil
+	})
+	if err := e.SetRules(rules); err != nil {
+		t.Fatalf("SetRules failed: %v", err)
+	}
+	return e, &matches
+}
+
+func TestSubstringAndRegex(t *testing.T) {
+	e, matches := newEngine(t,
+		Rule{ID: "1", Name: "error", Pattern: "error", Enabled: true, CooldownMs: -1, Actions: []Action{{Type: "record"}}},
+		Rule{ID: "2", Name: "panic", Pattern: `panic: (\w+)`, Regex: true, CaseSensitive: true, Enabled: true, CooldownMs: -1, Actions: []Action{{Type: "record"}}},
+		Rule{ID: "3", Name: "off", Pattern: "boot", Enabled: false, Actions: []Action{{Type: "record"}}},
+	)
+	now := time.Now()
+	e.Feed(now, []byte("boot\r\nE: ERROR in wi"))
+	e.Feed(now, []byte("fi\npanic: oops\nPANIC: no\n"))
+
+	if len(*matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %+v", *matches)
+	}
+	if m := (*matches)[0]; m.RuleID != "1" || m.Line != "E: ERROR in wifi" {
+		t.Errorf("Unexpected substring match %+v", m)
+	}
+	if m := (*matches)[1]; m.RuleID != "2" || len(m.Groups) != 1 || m.Groups[0] != "oops" {
+		t.Errorf("Unexpected regex match %+v", m)
+	}
+}
+
+func TestCooldownCountsSuppressed(t *testing.T) {
+	e, matches := newEngine(t, Rule{ID: "1", Pattern: "err", Enabled: true, CooldownMs: 1000, Actions: []Action{{Type: "record"}}})
+	t0 := time.Unix(0, 0)
+	e.Feed(t0, []byte("err\n"))
+	e.Feed(t0.Add(100*time.Millisecond), []byte("err\nerr\n"))
+	e.Feed(t0.Add(1500*time.Millisecond), []byte("err\n"))
+	if len(*matches) != 2 {
+		t.Fatalf("Expected 2 actions, got %d", len(*matches))
+	}
+	if (*matches)[1].Suppressed != 2 {
+		t.Errorf("Expected 2 suppressed matches, got %d", (*matches)[1].Suppressed)
+	}
+}
+
+func TestObserveSeesFiredMatches(t *testing.T) {
+	e, _ := newEngine(t, Rule{ID: "1", Pattern: "err", Enabled: true, Actions: []Action{{Type: "record"}}})
+	var observed []Match
+	e.Observe(func(m Match) { observed = append(observed, m) })
+	t0 := time.Unix(0, 0)
+	e.Feed(t0, []byte("err\nerr\n"))
+	if len(observed) != 1 || observed[0].RuleID != "1" {
+		t.Errorf("Expected one observed match, got %+v", observed)
+	}
+}
+
+func TestWatchFiresOnce(t *testing.T) {
+	e, _ := newEngine(t, Rule{ID: "1", Name: "fault", Pattern: "fault", Enabled: true, Actions: []Action{{Type: "record"}}})
+	type hit struct {
+		m     Match
+		alert bool
+	}
+	var hits []hit
+	record := func(m Match, alert bool) { hits = append(hits, hit{m, alert}) }
+
+	if _, err := e.Watch(Condition{Patterns: []Rule{{ID: "pattern", Pattern: "(", Regex: true}}}, record); err == nil {
+		t.Error("Expected invalid pattern to be rejected")
+	}
+	cancel, err := e.Watch(Condition{Patterns: []Rule{{ID: "pattern", Pattern: `^DONE (\d+)`, Regex: true}}}, record)
+	if err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	e.Watch(Condition{Alert: true}, record)
+	cancelled, _ := e.Watch(Condition{Patterns: []Rule{{ID: "never", Pattern: "DONE"}}}, record)
+	cancelled()
+
+	now := time.Now()
+	e.Feed(now, []byte("ok\nDONE 7\nDONE 8\nbus fault\nfault\n"))
+	if len(hits) != 2 {
+		t.Fatalf("Expected 2 hits, got %+v", hits)
+	}
+	if h := hits[0]; h.alert || h.m.RuleID != "pattern" || h.m.Groups[0] != "7" {
+		t.Errorf("Unexpected pattern hit %+v", h)
+	}
+	if h := hits[1]; !h.alert || h.m.RuleID != "1" || h.m.Line != "bus fault" {
+		t.Errorf("Unexpected alert hit %+v", h)
+	}
+	cancel() // 已注销的条件可以重复注销
+}
+
+func TestSetRulesValidates(t *testing.T) {
+	e, _ := newEngine(t)
+	if err := e.SetRules([]Rule{{Name: "x", Pattern: "a", Actions: []Action{{Type: "nope"}}}}); !errors.Is(err, ErrUnknownAction) {
+		t.Errorf("Expected ErrUnknownAction, got %v", err)
+	}
+	if err := e.SetRules([]Rule{{Name: "x", Pattern: "(", Regex: true}}); err == nil {
+		t.Error("Expected invalid regex to be rejected")
+	}
+	if err := e.SetRules([]Rule{{Name: "x"}}); err == nil {
+		t.Error("Expected empty pattern to be rejected")
+	}
+}
+
+func TestActionErrorsReported(t *testing.T) {
+	var got error
+	e := New(context.Background(), func(_ Match, _ Action, err error) { got = err })
+	e.Handle("fail", func(context.Context, Match, Action) error { return errors.New("boom") })
+	e.SetRules([]Rule{{Pattern: "x", Enabled: true, Actions: []Action{{Type: "fail"}}}})
+	e.Feed(time.Now(), []byte("x\n"))
+	if got == nil || got.Error() != "boom" {
+		t.Errorf("Expected action error to be reported, got %v", got)
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if rules, err := Load(path); err != nil || rules != nil {
+		t.Fatalf("Missing file should load as empty, got %v %v", rules, err)
+	}
+	want := []Rule{{ID: "1", Name: "error", Pattern: "ERROR", Enabled: true, Actions: []Action{{Type: "notify"}}}}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil || len(got) != 1 || got[0].Pattern != "ERROR" || got[0].Actions[0].Type != "notify" {
+		t.Errorf("Unexpected rules %+v %v", got, err)
+	}
+}
@@ -0,0 +1,160 @@
+package sanitize
+
+import (
+	"bytes"
+	"sync"
+	"unicode/utf8"
+)
+
+// bom UTF-8 字节序标记
+var bom = []byte{0xEF, 0xBB, 0xBF}
+
+// DefaultPlaceholder 替换不可打印字符的默认占位符
+const DefaultPlaceholder = "\uFFFD"
+
+// Options 接收数据的清理选项，在文本解码前应用
+type Options struct {
+	StripNUL            bool   `json:"stripNul"`            // 删除 0x00
+	StripBOM            bool   `json:"stripBom"`            // 删除 UTF-8 BOM (EF BB BF)
+	ReplaceNonPrintable bool   `json:"replaceNonPrintable"` // 将控制字符与非法 UTF-8 替换为占位符 (保留 \t \n \r 与 ANSI 转义的 ESC)
+	Placeholder         string `json:"placeholder"`         // 占位符，空为 U+FFFD；可以是空串以外的任意文本
+}
+
+// enabled 是否有任一选项打开
+func (o Options) enabled() bool {
+	return o.StripNUL || o.StripBOM || o.ReplaceNonPrintable
+}
+
+// Stats 清理计数
+type Stats struct {
+	NULs     uint64 `json:"nuls"`
+	BOMs     uint64 `json:"boms"`
+	Replaced uint64 `json:"replaced"`
+}
+
+// Sanitizer 按选项清理接收数据；跨数据块的 BOM 与多字节字符会暂存到下一块再处理
+type Sanitizer struct {
+	mu      sync.Mutex
+	opts    Options
+	pending []byte
+	stats   Stats
+}
+
+// New 创建清理器，默认不做任何处理
+func New() *Sanitizer {
+	return &Sanitizer{}
+}
+
+// SetOptions 设置清理选项
+func (s *Sanitizer) SetOptions(o Options) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.opts = o
+}
+
+// Options 返回当前选项
+func (s *Sanitizer) Options() Options {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.opts
+}
+
+// Stats 返回清理计数
+func (s *Sanitizer) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// ResetStats 清零计数
+func (s *Sanitizer) ResetStats() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats = Stats{}
+}
+
+// Reset 丢弃暂存的不完整字节 (新连接开始时调用)
+func (s *Sanitizer) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = nil
+}
+
+// Apply 返回清理后的数据；未开启任何选项时原样返回 data
+func (s *Sanitizer) Apply(data []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o := s.opts
+	if !o.enabled() {
+		if len(s.pending) == 0 {
+			return data
+		}
+		data = append(s.pending, data...)
+		s.pending = nil
+		return data
+	}
+	if len(s.pending) > 0 {
+		data = append(s.pending, data...)
+		s.pending = nil
+	}
+	placeholder := o.Placeholder
+	if placeholder == "" {
+		placeholder = DefaultPlaceholder
+	}
+
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); {
+		b := data[i]
+		rest := data[i:]
+		switch {
+		case o.StripBOM && b == bom[0] && bytes.HasPrefix(rest, bom):
+			s.stats.BOMs++
+			i += len(bom)
+			continue
+		case o.StripBOM && b == bom[0] && len(rest) < len(bom) && bytes.HasPrefix(bom, rest):
+			s.pending = append([]byte(nil), rest...)
+			return out
+		case o.StripNUL && b == 0:
+			s.stats.NULs++
+			i++
+			continue
+		case !o.ReplaceNonPrintable:
+			out = append(out, b)
+			i++
+			continue
+		case b < utf8.RuneSelf:
+			if printable(b) {
+				out = append(out, b)
+			} else {
+				out = append(out, placeholder...)
+				s.stats.Replaced++
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRune(rest)
+		if r == utf8.RuneError && size <= 1 && !utf8.FullRune(rest) {
+			s.pending = append([]byte(nil), rest...)
+			return out
+		}
+		if r == utf8.RuneError && size <= 1 || (r >= 0x80 && r <= 0x9F) {
+			// 非法 UTF-8 或 C1 控制字符
+			out = append(out, placeholder...)
+			s.stats.Replaced++
+		} else {
+			out = append(out, rest[:size]...)
+		}
+		i += size
+	}
+	return out
+}
+
+// printable ASCII 字节是否按原样显示
+func printable(b byte) bool {
+	switch b {
+	case '\t', '\n', '\r', 0x1B:
+		return true
+	}
+	return b >= 0x20 && b != 0x7F
+}
@@ -0,0 +1,60 @@
+package sanitize
+
+import "testing"
+
+func TestDisabledPassesThrough(t *testing.T) {
+	s := New()
+	in := []byte("a\x00\xEF\xBB\xBFb\x01")
+	if out := s.Apply(in); string(out) != string(in) {
+		t.Errorf("Apply = %q", out)
+	}
+}
+
+func TestStripNULAndBOM(t *testing.T) {
+	s := New()
+	s.SetOptions(Options{StripNUL: true, StripBOM: true})
+	got := string(s.Apply([]byte("\x00\x00\xEF\xBB\xBFboot\x00\x01\xEF")))
+	got += string(s.Apply([]byte("\xBB\xBFok\xEFx")))
+	if got != "boot\x01ok\xEFx" {
+		t.Errorf("Unexpected output %q", got)
+	}
+	if st := s.Stats(); st.NULs != 3 || st.BOMs != 2 || st.Replaced != 0 {
+		t.Errorf("Unexpected stats %+v", st)
+	}
+	s.ResetStats()
+	if s.Stats() != (Stats{}) {
+		t.Error("ResetStats should clear counts")
+	}
+}
+
+func TestReplaceNonPrintable(t *testing.T) {
+	s := New()
+	s.SetOptions(Options{ReplaceNonPrintable: true, Placeholder: "."})
+	// 中文字符跨两块到达，应完整保留
+	got := string(s.Apply([]byte("a\x01\tb\x1b[0m\xFF\xE4\xB8")))
+	got += string(s.Apply([]byte("\xAD\x7F\xC2\x85\r\n\x00")))
+	if got != "a.\tb\x1b[0m.中..\r\n." {
+		t.Errorf("Unexpected output %q", got)
+	}
+	if st := s.Stats(); st.Replaced != 5 {
+		t.Errorf("Unexpected stats %+v", st)
+	}
+
+	// 未设置占位符时使用 U+FFFD
+	s.SetOptions(Options{ReplaceNonPrintable: true})
+	if out := string(s.Apply([]byte{0x02})); out != DefaultPlaceholder {
+		t.Errorf("Default placeholder = %q", out)
+	}
+}
+
+func TestResetDropsPending(t *testing.T) {
+	s := New()
+	s.SetOptions(Options{ReplaceNonPrintable: true})
+	if out := s.Apply([]byte("x\xE4")); string(out) != "x" {
+		t.Fatalf("Apply = %q", out)
+	}
+	s.Reset()
+	if out := s.Apply([]byte("y")); string(out) != "y" {
+		t.Errorf("Pending bytes should be dropped on Reset, got %q", out)
+	}
+}
@@ -0,0 +1,352 @@
+package scale
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"serial-assistant/pkg/decoder"
+)
+
+// 输出格式
+const (
+	FormatAD     = "ad"     // A&D 标准格式："ST,+00123.45  g"
+	FormatToledo = "toledo" // Mettler Toledo 连续输出：STX + 3 个状态字节 + 6 位重量 + 6 位皮重 + CR
+	FormatSICS   = "sics"   // Mettler Toledo MT-SICS："S S     100.00 g"
+	FormatRegex  = "regex"  // 自定义正则表达式
+)
+
+// maxLine 超过该长度仍未遇到换行时丢弃
+const maxLine = 256
+
+// Profile 一种电子秤的输出格式及换算设置，通常从 JSON 文件加载
+type Profile struct {
+	Name        string `json:"name"` // 解码器名，也是字段名前缀
+	Description string `json:"description,omitempty"`
+	Format      string `json:"format"`
+	// Pattern regex 格式的正则表达式，命名分组 weight 必需，unit、status 可选
+	Pattern string `json:"pattern,omitempty"`
+	// StableStatus regex 格式中表示稳定的 status 取值；为空时所有读数视为稳定
+	StableStatus []string `json:"stableStatus,omitempty"`
+	Unit         string   `json:"unit,omitempty"`   // 数据中没有单位时使用的单位
+	Factor       float64  `json:"factor,omitempty"` // 读数乘以该系数 (如 0.001 将 g 换算为 kg)，0 表示不换算
+
+	re *regexp.Regexp
+}
+
+// Builtins 内置的电子秤格式
+var Builtins = []Profile{
+	{Name: "scale_ad", Description: "A&D standard format weighing scales", Format: FormatAD},
+	{Name: "scale_toledo", Description: "Mettler Toledo continuous output", Format: FormatToledo},
+	{Name: "scale_sics", Description: "Mettler Toledo MT-SICS", Format: FormatSICS},
+}
+
+func init() {
+	for _, p := range Builtins {
+		if err := Register(p); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// validName 名称会作为过滤表达式中的字段名前缀
+var validName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Validate 检查配置并编译正则表达式
+func (p *Profile) Validate() error {
+	if !validName.MatchString(p.Name) {
+		return fmt.Errorf("scale: invalid profile name %q", p.Name)
+	}
+	switch p.Format {
+	case FormatAD, FormatToledo, FormatSICS:
+	case FormatRegex:
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return fmt.Errorf("scale %s: %w", p.Name, err)
+		}
+		if re.SubexpIndex("weight") < 0 {
+			return fmt.Errorf("scale %s: pattern needs a (?P<weight>...) group", p.Name)
+		}
+		p.re = re
+	default:
+		return fmt.Errorf("scale %s: unknown format %q", p.Name, p.Format)
+	}
+	return nil
+}
+
+// Load 从 JSON 文件加载配置，文件内容可以是单个配置或配置数组
+func Load(path string) ([]Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	var list []Profile
+	if len(data) > 0 && data[0] == '{' {
+		var p Profile
+		err = json.Unmarshal(data, &p)
+		list = []Profile{p}
+	} else {
+		err = json.Unmarshal(data, &list)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scale: %s: %w", path, err)
+	}
+	return list, nil
+}
+
+// Register 将配置注册为解码器
+func Register(p Profile) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	desc := p.Description
+	if desc == "" {
+		desc = "Weighing scale " + p.Name
+	}
+	decoder.Register(decoder.Info{Name: p.Name, Description: desc, Source: "scale"}, func() decoder.Decoder {
+		return &Decoder{profile: p}
+	})
+	return nil
+}
+
+// Reading 一次称重读数
+type Reading struct {
+	Weight   float64
+	Unit     string
+	Stable   bool
+	Overload bool
+	Net      bool   // 净重 (已去皮)
+	Status   string // 格式中的原始状态，如 "ST"
+}
+
+// Decoder 按配置解析称重读数的解码器
+type Decoder struct {
+	profile Profile
+	buf     []byte
+}
+
+func (d *Decoder) Name() string { return d.profile.Name }
+
+// Detect 统计能解析为读数的行的比例
+func (d *Decoder) Detect(sample []byte) float64 {
+	lines, ok := 0, 0
+	for _, line := range bytes.FieldsFunc(sample, func(r rune) bool { return r == '\r' || r == '\n' }) {
+		lines++
+		if _, err := d.profile.parse(line); err == nil {
+			ok++
+		}
+	}
+	if lines < 2 {
+		return 0
+	}
+	return 0.85 * float64(ok) / float64(lines)
+}
+
+func (d *Decoder) Decode(t time.Time, data []byte, emit func(decoder.Frame)) {
+	for _, c := range data {
+		if c == '\r' || c == '\n' {
+			if len(d.buf) > 0 {
+				emit(d.frame(t, d.buf))
+				d.buf = d.buf[:0]
+			}
+			continue
+		}
+		if len(d.buf) < maxLine {
+			d.buf = append(d.buf, c)
+		}
+	}
+}
+
+func (d *Decoder) frame(t time.Time, line []byte) decoder.Frame {
+	f := decoder.Frame{Protocol: d.profile.Name, Time: t, Raw: append([]byte(nil), line...)}
+	r, err := d.profile.parse(line)
+	if err != nil {
+		f.Error = err.Error()
+		f.Summary = strings.TrimSpace(string(line))
+		return f
+	}
+	prefix := d.profile.Name + "."
+	f.Fields = []decoder.Field{
+		{Name: prefix + "weight", Value: r.Weight},
+		{Name: prefix + "unit", Value: r.Unit},
+		{Name: prefix + "stable", Value: r.Stable},
+		{Name: prefix + "overload", Value: r.Overload},
+		{Name: prefix + "net", Value: r.Net},
+		{Name: prefix + "status", Value: r.Status},
+	}
+	state := "unstable"
+	switch {
+	case r.Overload:
+		state = "overload"
+	case r.Stable:
+		state = "stable"
+	}
+	f.Summary = strings.TrimSpace(strconv.FormatFloat(r.Weight, 'f', -1, 64)+" "+r.Unit) + " (" + state + ")"
+	return f
+}
+
+// parse 按格式解析一行 (Toledo 为一帧，不含 CR)
+func (p *Profile) parse(line []byte) (Reading, error) {
+	var r Reading
+	var err error
+	switch p.Format {
+	case FormatAD:
+		r, err = parseAD(string(line))
+	case FormatToledo:
+		r, err = parseToledo(line)
+	case FormatSICS:
+		r, err = parseSICS(string(line))
+	case FormatRegex:
+		r, err = p.parseRegex(string(line))
+	}
+	if err != nil {
+		return r, err
+	}
+	if r.Unit == "" {
+		r.Unit = p.Unit
+	}
+	if p.Factor != 0 {
+		r.Weight *= p.Factor
+	}
+	return r, nil
+}
+
+// splitUnit 将 "+00123.45  g" 拆分为数值与单位
+func splitUnit(s string) (float64, string, error) {
+	s = strings.TrimSpace(s)
+	i := strings.LastIndexAny(s, "0123456789.")
+	if i < 0 {
+		return 0, "", fmt.Errorf("no weight in %q", s)
+	}
+	num := strings.ReplaceAll(s[:i+1], " ", "")
+	w, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid weight %q", num)
+	}
+	return w, strings.TrimSpace(s[i+1:]), nil
+}
+
+// parseAD A&D 格式：ST 稳定、US 不稳定、OL 超载、QT 计数模式
+func parseAD(line string) (Reading, error) {
+	if len(line) < 4 || line[2] != ',' {
+		return Reading{}, fmt.Errorf("not an A&D reading")
+	}
+	r := Reading{Status: line[:2]}
+	switch r.Status {
+	case "ST", "QT", "WT":
+		r.Stable = true
+	case "US":
+	case "OL":
+		r.Overload = true
+		return r, nil
+	default:
+		return Reading{}, fmt.Errorf("unknown A&D header %q", r.Status)
+	}
+	w, unit, err := splitUnit(line[3:])
+	if err != nil {
+		return Reading{}, err
+	}
+	r.Weight, r.Unit = w, unit
+	return r, nil
+}
+
+// parseSICS MT-SICS 响应："S S" 稳定、"S D" 动态、"S +" 超载、"S -" 欠载
+func parseSICS(line string) (Reading, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || fields[0] != "S" && fields[0] != "SI" && fields[0] != "SIR" {
+		return Reading{}, fmt.Errorf("not an MT-SICS weight response")
+	}
+	r := Reading{Status: fields[1]}
+	switch r.Status {
+	case "S":
+		r.Stable = true
+	case "D":
+	case "+", "-":
+		r.Overload = true
+		return r, nil
+	default:
+		return Reading{}, fmt.Errorf("MT-SICS status %q", r.Status)
+	}
+	if len(fields) < 3 {
+		return Reading{}, fmt.Errorf("MT-SICS response without weight")
+	}
+	w, unit, err := splitUnit(strings.Join(fields[2:], " "))
+	if err != nil {
+		return Reading{}, err
+	}
+	r.Weight, r.Unit = w, unit
+	return r, nil
+}
+
+// toledoDecimals 状态字节 A 低 3 位对应的小数位 (负数表示末尾补零)
+var toledoDecimals = [8]int{-2, -1, 0, 1, 2, 3, 4, 5}
+
+// parseToledo 连续输出帧，帧前可能残留上一帧的校验字节
+func parseToledo(frame []byte) (Reading, error) {
+	i := bytes.LastIndexByte(frame, 0x02)
+	if i < 0 || len(frame)-i < 16 {
+		return Reading{}, fmt.Errorf("not a Toledo frame")
+	}
+	frame = frame[i+1:]
+	a, b := frame[0], frame[1]
+	digits := string(frame[3:9])
+	n, err := strconv.ParseUint(strings.TrimSpace(digits), 10, 32)
+	if err != nil {
+		return Reading{}, fmt.Errorf("invalid Toledo weight %q", digits)
+	}
+	w := float64(n)
+	if d := toledoDecimals[a&0x07]; d > 0 {
+		w /= math.Pow10(d)
+	} else {
+		w *= math.Pow10(-d)
+	}
+	r := Reading{
+		Weight:   w,
+		Unit:     "lb",
+		Net:      b&0x01 != 0,
+		Overload: b&0x04 != 0,
+		Stable:   b&0x08 == 0,
+		Status:   fmt.Sprintf("%02X%02X%02X", a, b, frame[2]),
+	}
+	if b&0x02 != 0 {
+		r.Weight = -r.Weight
+	}
+	if b&0x10 != 0 {
+		r.Unit = "kg"
+	}
+	return r, nil
+}
+
+func (p *Profile) parseRegex(line string) (Reading, error) {
+	m := p.re.FindStringSubmatch(line)
+	if m == nil {
+		return Reading{}, fmt.Errorf("no match")
+	}
+	group := func(name string) string {
+		if i := p.re.SubexpIndex(name); i >= 0 {
+			return m[i]
+		}
+		return ""
+	}
+	w, unit, err := splitUnit(group("weight"))
+	if err != nil {
+		return Reading{}, err
+	}
+	r := Reading{Weight: w, Unit: group("unit"), Status: group("status"), Stable: len(p.StableStatus) == 0}
+	if r.Unit == "" {
+		r.Unit = unit
+	}
+	for _, s := range p.StableStatus {
+		if strings.EqualFold(s, strings.TrimSpace(r.Status)) {
+			r.Stable = true
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,106 @@
+package scale
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/decoder"
+)
+
+func decode(t *testing.T, name string, data ...string) []decoder.Frame {
+	t.Helper()
+	d, err := decoder.New(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var frames []decoder.Frame
+	for _, chunk := range data {
+		d.Decode(time.Unix(0, 0), []byte(chunk), func(f decoder.Frame) { frames = append(frames, f) })
+	}
+	return frames
+}
+
+func get(t *testing.T, f decoder.Frame, name string) interface{} {
+	t.Helper()
+	v, ok := f.Get(name)
+	if !ok {
+		t.Fatalf("Frame %+v has no field %s", f, name)
+	}
+	return v
+}
+
+func TestAD(t *testing.T) {
+	frames := decode(t, "scale_ad", "ST,+00123.45  g\r\nUS,-0001.2", "0 kg\r\nOL,+9999999E+19\r\n")
+	if len(frames) != 3 {
+		t.Fatalf("Expected 3 frames, got %+v", frames)
+	}
+	if get(t, frames[0], "scale_ad.weight") != 123.45 || get(t, frames[0], "scale_ad.unit") != "g" || get(t, frames[0], "scale_ad.stable") != true {
+		t.Errorf("Unexpected stable reading %+v", frames[0])
+	}
+	if get(t, frames[1], "scale_ad.weight") != -1.2 || get(t, frames[1], "scale_ad.stable") != false {
+		t.Errorf("Unexpected unstable reading %+v", frames[1])
+	}
+	if get(t, frames[2], "scale_ad.overload") != true {
+		t.Errorf("Expected overload, got %+v", frames[2])
+	}
+}
+
+func TestToledo(t *testing.T) {
+	// A=0x34 (两位小数)，B=0x3A (负数、动态、kg)，校验字节跟在 CR 之后
+	frames := decode(t, "scale_toledo", "\x024:0001234000000\r\x55\x024  001234000000\r")
+	if len(frames) != 2 {
+		t.Fatalf("Expected 2 frames, got %+v", frames)
+	}
+	if get(t, frames[0], "scale_toledo.weight") != -12.34 || get(t, frames[0], "scale_toledo.unit") != "kg" ||
+		get(t, frames[0], "scale_toledo.stable") != false {
+		t.Errorf("Unexpected reading %+v", frames[0])
+	}
+	if get(t, frames[1], "scale_toledo.weight") != 12.34 || get(t, frames[1], "scale_toledo.unit") != "lb" ||
+		get(t, frames[1], "scale_toledo.stable") != true {
+		t.Errorf("Unexpected reading %+v", frames[1])
+	}
+}
+
+func TestSICS(t *testing.T) {
+	frames := decode(t, "scale_sics", "S S     100.00 g\r\nS D      99.98 g\r\nS +\r\nES\r\n")
+	if len(frames) != 4 {
+		t.Fatalf("Expected 4 frames, got %+v", frames)
+	}
+	if get(t, frames[0], "scale_sics.weight") != 100.0 || get(t, frames[1], "scale_sics.stable") != false {
+		t.Errorf("Unexpected readings %+v", frames[:2])
+	}
+	if get(t, frames[2], "scale_sics.overload") != true || frames[3].Error == "" {
+		t.Errorf("Expected overload and an error frame, got %+v", frames[2:])
+	}
+}
+
+func TestCustomProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scales.json")
+	os.WriteFile(path, []byte(`{"name":"bench","format":"regex","pattern":"^(?P<status>[A-Z]{2}) (?P<weight>[-+ 0-9.]+)$","stableStatus":["ST"],"unit":"kg","factor":0.001}`), 0o644)
+	list, err := Load(path)
+	if err != nil || len(list) != 1 {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := Register(list[0]); err != nil {
+		t.Fatal(err)
+	}
+	frames := decode(t, "bench", "ST  1500\nUS 10\n")
+	if get(t, frames[0], "bench.weight") != 1.5 || get(t, frames[0], "bench.unit") != "kg" || get(t, frames[0], "bench.stable") != true {
+		t.Errorf("Unexpected reading %+v", frames[0])
+	}
+	if get(t, frames[1], "bench.stable") != false {
+		t.Errorf("Expected unstable reading, got %+v", frames[1])
+	}
+
+	for _, p := range []Profile{
+		{Name: "bad-name", Format: FormatAD},
+		{Name: "x", Format: "nope"},
+		{Name: "x", Format: FormatRegex, Pattern: "(?P<w>.*)"},
+	} {
+		if err := Register(p); err == nil {
+			t.Errorf("Expected %+v to be rejected", p)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package scpi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"serial-assistant/pkg/series"
+)
+
+// Measurement 测量记录器定期执行的一条查询
+type Measurement struct {
+	Name  string `json:"name"`  // 序列名
+	Query string `json:"query"` // 如 "MEAS:VOLT?"
+	Unit  string `json:"unit,omitempty"`
+}
+
+// LoggerConfig 测量记录器配置
+type LoggerConfig struct {
+	Measurements []Measurement `json:"measurements"`
+	IntervalMs   int           `json:"intervalMs"`
+	PollErrors   bool          `json:"pollErrors"` // 每轮测量后读取错误队列
+}
+
+// MinInterval 两轮测量的最小间隔
+const MinInterval = 50 * time.Millisecond
+
+// Validate 检查配置
+func (c *LoggerConfig) Validate() error {
+	if len(c.Measurements) == 0 {
+		return fmt.Errorf("scpi: no measurements configured")
+	}
+	for _, m := range c.Measurements {
+		if m.Name == "" || m.Query == "" {
+			return fmt.Errorf("scpi: measurement needs a name and a query: %+v", m)
+		}
+	}
+	if time.Duration(c.IntervalMs)*time.Millisecond < MinInterval {
+		return fmt.Errorf("scpi: interval must be at least %v", MinInterval)
+	}
+	return nil
+}
+
+// Log 按配置轮询测量值直到 ctx 结束；每个读数交给 emit，单次查询失败交给 onError 后继续
+// queryTimeout 为单条查询的超时
+func Log(ctx context.Context, s *Session, cfg LoggerConfig, queryTimeout time.Duration, emit func(series.Sample), onError func(error)) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(time.Duration(cfg.IntervalMs) * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		for _, m := range cfg.Measurements {
+			qctx, cancel := context.WithTimeout(ctx, queryTimeout)
+			v, err := s.QueryFloat(qctx, m.Query)
+			cancel()
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				onError(fmt.Errorf("%s: %w", m.Query, err))
+				continue
+			}
+			emit(series.Sample{Series: m.Name, Time: time.Now(), Value: v, Unit: m.Unit})
+		}
+		if cfg.PollErrors {
+			qctx, cancel := context.WithTimeout(ctx, queryTimeout)
+			errs, err := s.Errors(qctx)
+			cancel()
+			if err != nil && ctx.Err() == nil {
+				onError(fmt.Errorf("SYST:ERR?: %w", err))
+			}
+			for _, e := range errs {
+				onError(fmt.Errorf("instrument error %d: %s", e.Code, e.Message))
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
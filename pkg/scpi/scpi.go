@@ -0,0 +1,236 @@
+package scpi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 默认的命令与应答结束符
+const DefaultTerminator = "\n"
+
+// maxResponse 单条应答的长度上限 (波形等二进制块不在支持范围内)
+const maxResponse = 64 * 1024
+
+// 查询错误
+var (
+	ErrTooLong   = errors.New("scpi: response too long")
+	ErrNotNumber = errors.New("scpi: response is not a number")
+)
+
+// Session 在串口连接上配对 SCPI 查询与应答
+// 查询按顺序执行；没有查询在等待时收到的数据被忽略
+type Session struct {
+	write func(ctx context.Context, p []byte) error
+
+	mu         sync.Mutex // 串行化查询
+	termMu     sync.Mutex
+	writeTerm  string
+	readTerm   string
+	waiting    bool
+	partial    []byte
+	responses  chan string
+	overflowed bool
+}
+
+// New 创建会话，write 向仪器写入数据
+func New(write func(ctx context.Context, p []byte) error) *Session {
+	return &Session{
+		write:     write,
+		writeTerm: DefaultTerminator,
+		readTerm:  DefaultTerminator,
+		responses: make(chan string, 1),
+	}
+}
+
+// SetTerminators 设置命令结束符与应答结束符 (如 "\r\n")，为空时使用 "\n"
+func (s *Session) SetTerminators(write, read string) {
+	if write == "" {
+		write = DefaultTerminator
+	}
+	if read == "" {
+		read = DefaultTerminator
+	}
+	s.termMu.Lock()
+	defer s.termMu.Unlock()
+	s.writeTerm, s.readTerm = write, read
+	s.partial = s.partial[:0]
+}
+
+// Terminators 返回命令结束符与应答结束符
+func (s *Session) Terminators() (string, string) {
+	s.termMu.Lock()
+	defer s.termMu.Unlock()
+	return s.writeTerm, s.readTerm
+}
+
+// Feed 处理接收到的数据
+func (s *Session) Feed(data []byte) {
+	s.termMu.Lock()
+	defer s.termMu.Unlock()
+	if !s.waiting {
+		return
+	}
+	s.partial = append(s.partial, data...)
+	i := bytes.Index(s.partial, []byte(s.readTerm))
+	if i < 0 {
+		if len(s.partial) > maxResponse {
+			s.overflowed = true
+			s.waiting = false
+			s.partial = s.partial[:0]
+			s.responses <- ""
+		}
+		return
+	}
+	resp := strings.TrimRight(string(s.partial[:i]), "\r\n")
+	s.partial = s.partial[:0]
+	s.waiting = false
+	s.responses <- resp
+}
+
+// Command 发送不需要应答的命令
+func (s *Session) Command(ctx context.Context, cmd string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	term, _ := s.Terminators()
+	return s.write(ctx, []byte(cmd+term))
+}
+
+// Query 发送查询并等待一行应答
+func (s *Session) Query(ctx context.Context, q string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.termMu.Lock()
+	s.waiting = true
+	s.overflowed = false
+	s.partial = s.partial[:0]
+	term := s.writeTerm
+	s.termMu.Unlock()
+
+	if err := s.write(ctx, []byte(q+term)); err != nil {
+		s.cancel()
+		return "", err
+	}
+	select {
+	case resp := <-s.responses:
+		s.termMu.Lock()
+		overflowed := s.overflowed
+		s.termMu.Unlock()
+		if overflowed {
+			return "", ErrTooLong
+		}
+		return resp, nil
+	case <-ctx.Done():
+		s.cancel()
+		return "", ctx.Err()
+	}
+}
+
+// cancel 放弃等待中的应答
+func (s *Session) cancel() {
+	s.termMu.Lock()
+	defer s.termMu.Unlock()
+	s.waiting = false
+	s.partial = s.partial[:0]
+	// Feed 可能已在取消前放入应答
+	select {
+	case <-s.responses:
+	default:
+	}
+}
+
+// QueryFloat 发送查询并将应答的第一个字段解析为数值
+func (s *Session) QueryFloat(ctx context.Context, q string) (float64, error) {
+	resp, err := s.Query(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	return ParseFloat(resp)
+}
+
+// ParseFloat 解析数值应答，多值应答 (逗号分隔) 取第一个
+func ParseFloat(resp string) (float64, error) {
+	field := strings.TrimSpace(strings.SplitN(resp, ",", 2)[0])
+	v, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrNotNumber, resp)
+	}
+	return v, nil
+}
+
+// Identity *IDN? 应答中的仪器信息
+type Identity struct {
+	Manufacturer string `json:"manufacturer"`
+	Model        string `json:"model"`
+	Serial       string `json:"serial"`
+	Firmware     string `json:"firmware"`
+	Raw          string `json:"raw"`
+}
+
+// Identify 查询 *IDN? 并解析为 厂商,型号,序列号,固件版本
+func (s *Session) Identify(ctx context.Context) (Identity, error) {
+	resp, err := s.Query(ctx, "*IDN?")
+	if err != nil {
+		return Identity{}, err
+	}
+	id := Identity{Raw: resp}
+	parts := strings.SplitN(resp, ",", 4)
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		switch i {
+		case 0:
+			id.Manufacturer = p
+		case 1:
+			id.Model = p
+		case 2:
+			id.Serial = p
+		case 3:
+			id.Firmware = p
+		}
+	}
+	return id, nil
+}
+
+// Error 仪器错误队列中的一条错误
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// maxErrors 一次最多读取的错误数，防止不按规范应答的仪器造成死循环
+const maxErrors = 32
+
+// Errors 反复查询 SYST:ERR? 直到错误队列为空，返回读出的错误
+func (s *Session) Errors(ctx context.Context) ([]Error, error) {
+	list := []Error{}
+	for i := 0; i < maxErrors; i++ {
+		resp, err := s.Query(ctx, "SYST:ERR?")
+		if err != nil {
+			return list, err
+		}
+		e, err := ParseError(resp)
+		if err != nil {
+			return list, err
+		}
+		if e.Code == 0 {
+			break
+		}
+		list = append(list, e)
+	}
+	return list, nil
+}
+
+// ParseError 解析 `-113,"Undefined header"` 形式的错误应答
+func ParseError(resp string) (Error, error) {
+	code, msg, _ := strings.Cut(resp, ",")
+	n, err := strconv.Atoi(strings.TrimSpace(code))
+	if err != nil {
+		return Error{}, fmt.Errorf("scpi: invalid error response %q", resp)
+	}
+	return Error{Code: n, Message: strings.Trim(strings.TrimSpace(msg), `"`)}, nil
+}
@@ -0,0 +1,117 @@
+package scpi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/series"
+)
+
+// fakeInstrument 按查询返回预设应答，应答分两段送达以检验拼接
+type fakeInstrument struct {
+	mu      sync.Mutex
+	s       *Session
+	answers map[string][]string
+	sent    []string
+}
+
+func newFake(answers map[string][]string) *fakeInstrument {
+	f := &fakeInstrument{answers: answers}
+	f.s = New(f.write)
+	return f
+}
+
+func (f *fakeInstrument) write(_ context.Context, p []byte) error {
+	cmd := strings.TrimRight(string(p), "\r\n")
+	f.mu.Lock()
+	f.sent = append(f.sent, cmd)
+	queue := f.answers[cmd]
+	var resp string
+	ok := len(queue) > 0
+	if ok {
+		resp = queue[0]
+		if len(queue) > 1 {
+			f.answers[cmd] = queue[1:]
+		}
+	}
+	f.mu.Unlock()
+	if ok {
+		go func() {
+			full := resp + "\r\n"
+			f.s.Feed([]byte(full[:len(full)/2]))
+			f.s.Feed([]byte(full[len(full)/2:]))
+		}()
+	}
+	return nil
+}
+
+func TestIdentifyAndErrors(t *testing.T) {
+	f := newFake(map[string][]string{
+		"*IDN?":     {"RIGOL TECHNOLOGIES,DP832,DP8C123,00.01.14"},
+		"SYST:ERR?": {`-113,"Undefined header"`, `-222,"Data out of range"`, `0,"No error"`},
+	})
+	ctx := context.Background()
+	id, err := f.s.Identify(ctx)
+	if err != nil || id.Manufacturer != "RIGOL TECHNOLOGIES" || id.Model != "DP832" || id.Firmware != "00.01.14" {
+		t.Fatalf("Unexpected identity %+v (%v)", id, err)
+	}
+	errs, err := f.s.Errors(ctx)
+	if err != nil || len(errs) != 2 || errs[0].Code != -113 || errs[1].Message != "Data out of range" {
+		t.Errorf("Unexpected errors %+v (%v)", errs, err)
+	}
+}
+
+func TestQueryTimeoutAndUnsolicitedData(t *testing.T) {
+	f := newFake(map[string][]string{"MEAS:VOLT?": {"+1.2345E+00"}})
+	// 没有查询在等待时的数据被忽略
+	f.s.Feed([]byte("garbage\n"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := f.s.Query(ctx, "SILENT?"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+	v, err := f.s.QueryFloat(context.Background(), "MEAS:VOLT?")
+	if err != nil || v != 1.2345 {
+		t.Errorf("Expected 1.2345, got %v (%v)", v, err)
+	}
+	if _, err := ParseFloat("OVLD"); !errors.Is(err, ErrNotNumber) {
+		t.Errorf("Expected ErrNotNumber, got %v", err)
+	}
+}
+
+func TestLogger(t *testing.T) {
+	f := newFake(map[string][]string{
+		"MEAS:VOLT?": {"5.01", "5.02"},
+		"MEAS:CURR?": {"0.250,0.1"},
+		"SYST:ERR?":  {`0,"No error"`},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	samples := make(chan series.Sample, 8)
+	cfg := LoggerConfig{
+		Measurements: []Measurement{{Name: "vout", Query: "MEAS:VOLT?", Unit: "V"}, {Name: "iout", Query: "MEAS:CURR?", Unit: "A"}},
+		IntervalMs:   50,
+		PollErrors:   true,
+	}
+	done := make(chan error)
+	go func() {
+		done <- Log(ctx, f.s, cfg, time.Second, func(s series.Sample) { samples <- s }, func(err error) { t.Errorf("Unexpected error: %v", err) })
+	}()
+	v := <-samples
+	i := <-samples
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancellation, got %v", err)
+	}
+	if v.Series != "vout" || v.Value != 5.01 || i.Series != "iout" || i.Value != 0.25 || i.Unit != "A" {
+		t.Errorf("Unexpected samples %+v %+v", v, i)
+	}
+
+	if err := (&LoggerConfig{IntervalMs: 10, Measurements: cfg.Measurements}).Validate(); err == nil {
+		t.Error("Expected a too-short interval to be rejected")
+	}
+}
@@ -0,0 +1,136 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Service 在系统钥匙串中登记的服务名，所有条目都归在该服务下
+const Service = "serial-assistant"
+
+var (
+	ErrNotFound    = errors.New("secrets: not found")
+	ErrUnavailable = errors.New("secrets: no system keychain available")
+	ErrInvalidKey  = errors.New("secrets: invalid key")
+)
+
+// Store 按名称保存密码、令牌等敏感信息
+type Store interface {
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+// ValidKey 名称非空、不超过 128 字节且只含可打印 ASCII 字符
+func ValidKey(key string) bool {
+	if key == "" || len(key) > 128 {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7E {
+			return false
+		}
+	}
+	return strings.TrimSpace(key) == key
+}
+
+// System 返回系统钥匙串：Windows 凭据管理器、macOS 钥匙串或 Linux 的 Secret Service (libsecret)
+func System() Store {
+	return checked{systemStore{}}
+}
+
+// checked 在访问后端前校验名称
+type checked struct {
+	s Store
+}
+
+func (c checked) Get(key string) (string, error) {
+	if !ValidKey(key) {
+		return "", ErrInvalidKey
+	}
+	return c.s.Get(key)
+}
+
+func (c checked) Set(key, value string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	return c.s.Set(key, value)
+}
+
+func (c checked) Delete(key string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+	return c.s.Delete(key)
+}
+
+// Memory 只保存在内存中的 Store，用于测试及没有系统钥匙串时的临时保存
+type Memory struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+// NewMemory 创建内存 Store
+func NewMemory() *Memory {
+	return &Memory{values: make(map[string]string)}
+}
+
+func (m *Memory) Get(key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.values[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *Memory) Set(key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+	return nil
+}
+
+func (m *Memory) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.values[key]; !ok {
+		return ErrNotFound
+	}
+	delete(m.values, key)
+	return nil
+}
+
+// RefPrefix 配置中引用钥匙串条目的前缀，如 {"token": "secret:telegram/bot"}
+const RefPrefix = "secret:"
+
+// Resolve 返回 params 的副本，其中 "secret:<名称>" 形式的值替换为钥匙串中的内容
+// 配置文件只保存引用，明文只在使用时读取
+func Resolve(s Store, params map[string]string) (map[string]string, error) {
+	var out map[string]string
+	for k, v := range params {
+		name, ok := strings.CutPrefix(v, RefPrefix)
+		if !ok {
+			continue
+		}
+		value, err := s.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		if out == nil {
+			out = make(map[string]string, len(params))
+			for k2, v2 := range params {
+				out[k2] = v2
+			}
+		}
+		out[k] = value
+	}
+	if out == nil {
+		return params, nil
+	}
+	return out, nil
+}
@@ -0,0 +1,62 @@
+//go:build darwin
+
+package secrets
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// securityNotFound security 工具在条目不存在时的退出码
+const securityNotFound = 44
+
+// encodedPrefix 值以 base64 保存，避免引号、换行等字符破坏 security 的命令行
+const encodedPrefix = "base64:"
+
+// systemStore 保存在登录钥匙串的普通密码中 (服务为 Service，账户为 key)，通过系统自带的 security 工具访问
+type systemStore struct{}
+
+func securityError(err error) error {
+	var exit *exec.ExitError
+	if errors.As(err, &exit) && exit.ExitCode() == securityNotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (systemStore) Get(key string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", Service, "-a", key, "-w").Output()
+	if err != nil {
+		return "", securityError(err)
+	}
+	v := strings.TrimSuffix(string(out), "\n")
+	if enc, ok := strings.CutPrefix(v, encodedPrefix); ok {
+		b, err := base64.StdEncoding.DecodeString(enc)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+	return v, nil
+}
+
+func (systemStore) Set(key, value string) error {
+	// 通过标准输入传递命令，密码不出现在进程参数中
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %q -a %q -w %q\n",
+		Service, key, encodedPrefix+base64.StdEncoding.EncodeToString([]byte(value))))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (systemStore) Delete(key string) error {
+	if err := exec.Command("security", "delete-generic-password", "-s", Service, "-a", key).Run(); err != nil {
+		return securityError(err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+//go:build linux
+
+package secrets
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Secret Service (freedesktop.org) 的 D-Bus 接口，GNOME Keyring、KWallet 等均实现了该接口
+const (
+	ssName       = "org.freedesktop.secrets"
+	ssPath       = "/org/freedesktop/secrets"
+	ssDefault    = "/org/freedesktop/secrets/aliases/default"
+	ssService    = "org.freedesktop.Secret.Service"
+	ssCollection = "org.freedesktop.Secret.Collection"
+	ssItem       = "org.freedesktop.Secret.Item"
+	ssPrompt     = "org.freedesktop.Secret.Prompt"
+)
+
+// ssSecret 对应 Secret Service 的 Secret 结构 (oayays)
+type ssSecret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// systemStore 保存在 Secret Service 的默认集合中，以 service、key 两个属性查找
+type systemStore struct{}
+
+// ssConn 连接会话总线并打开不加密的会话 (仅在本机 D-Bus 上传输)
+func ssConn() (*dbus.Conn, dbus.BusObject, dbus.ObjectPath, error) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	svc := conn.Object(ssName, ssPath)
+	var out dbus.Variant
+	var session dbus.ObjectPath
+	if err := svc.Call(ssService+".OpenSession", 0, "plain", dbus.MakeVariant("")).Store(&out, &session); err != nil {
+		return nil, nil, "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return conn, svc, session, nil
+}
+
+func attributes(key string) map[string]string {
+	return map[string]string{"service": Service, "key": key}
+}
+
+// prompt 执行解锁、删除等操作要求的用户确认，等待用户完成
+func prompt(conn *dbus.Conn, path dbus.ObjectPath) error {
+	if path == "/" {
+		return nil
+	}
+	if err := conn.AddMatchSignal(dbus.WithMatchObjectPath(path), dbus.WithMatchInterface(ssPrompt)); err != nil {
+		return err
+	}
+	defer conn.RemoveMatchSignal(dbus.WithMatchObjectPath(path), dbus.WithMatchInterface(ssPrompt))
+	signals := make(chan *dbus.Signal, 4)
+	conn.Signal(signals)
+	defer conn.RemoveSignal(signals)
+	if err := conn.Object(ssName, path).Call(ssPrompt+".Prompt", 0, "").Err; err != nil {
+		return err
+	}
+	for sig := range signals {
+		if sig.Path != path || sig.Name != ssPrompt+".Completed" || len(sig.Body) == 0 {
+			continue
+		}
+		if dismissed, _ := sig.Body[0].(bool); dismissed {
+			return errors.New("secrets: keychain prompt dismissed")
+		}
+		return nil
+	}
+	return errors.New("secrets: keychain connection closed")
+}
+
+// find 返回匹配 key 的条目，必要时先解锁
+func find(conn *dbus.Conn, svc dbus.BusObject, key string) (dbus.ObjectPath, error) {
+	var unlocked, locked []dbus.ObjectPath
+	if err := svc.Call(ssService+".SearchItems", 0, attributes(key)).Store(&unlocked, &locked); err != nil {
+		return "", err
+	}
+	if len(unlocked) > 0 {
+		return unlocked[0], nil
+	}
+	if len(locked) == 0 {
+		return "", ErrNotFound
+	}
+	var done []dbus.ObjectPath
+	var p dbus.ObjectPath
+	if err := svc.Call(ssService+".Unlock", 0, locked[:1]).Store(&done, &p); err != nil {
+		return "", err
+	}
+	if err := prompt(conn, p); err != nil {
+		return "", err
+	}
+	return locked[0], nil
+}
+
+func (systemStore) Get(key string) (string, error) {
+	conn, svc, session, err := ssConn()
+	if err != nil {
+		return "", err
+	}
+	item, err := find(conn, svc, key)
+	if err != nil {
+		return "", err
+	}
+	var s ssSecret
+	if err := conn.Object(ssName, item).Call(ssItem+".GetSecret", 0, session).Store(&s); err != nil {
+		return "", err
+	}
+	return string(s.Value), nil
+}
+
+func (systemStore) Set(key, value string) error {
+	conn, _, session, err := ssConn()
+	if err != nil {
+		return err
+	}
+	props := map[string]dbus.Variant{
+		ssItem + ".Label":      dbus.MakeVariant(Service + ": " + key),
+		ssItem + ".Attributes": dbus.MakeVariant(attributes(key)),
+	}
+	secret := ssSecret{Session: session, Value: []byte(value), ContentType: "text/plain; charset=utf8"}
+	var item, p dbus.ObjectPath
+	if err := conn.Object(ssName, ssDefault).Call(ssCollection+".CreateItem", 0, props, secret, true).Store(&item, &p); err != nil {
+		return err
+	}
+	return prompt(conn, p)
+}
+
+func (systemStore) Delete(key string) error {
+	conn, svc, _, err := ssConn()
+	if err != nil {
+		return err
+	}
+	item, err := find(conn, svc, key)
+	if err != nil {
+		return err
+	}
+	var p dbus.ObjectPath
+	if err := conn.Object(ssName, item).Call(ssItem+".Delete", 0).Store(&p); err != nil {
+		return err
+	}
+	return prompt(conn, p)
+}
@@ -0,0 +1,10 @@
+//go:build !windows && !darwin && !linux
+
+package secrets
+
+// systemStore 其他平台没有支持的系统钥匙串
+type systemStore struct{}
+
+func (systemStore) Get(string) (string, error) { return "", ErrUnavailable }
+func (systemStore) Set(string, string) error   { return ErrUnavailable }
+func (systemStore) Delete(string) error        { return ErrUnavailable }
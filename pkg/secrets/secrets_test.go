@@ -0,0 +1,57 @@
+package secrets
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"login/root":    true,
+		"smtp.password": true,
+		"":              false,
+		" padded":       false,
+		"bad\nkey":      false,
+		"密码":            false,
+	} {
+		if got := ValidKey(key); got != want {
+			t.Errorf("ValidKey(%q) = %v", key, got)
+		}
+	}
+	if _, err := (checked{NewMemory()}).Get(""); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Expected ErrInvalidKey, got %v", err)
+	}
+}
+
+func TestMemory(t *testing.T) {
+	m := NewMemory()
+	if _, err := m.Get("a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	m.Set("a", "secret")
+	if v, err := m.Get("a"); err != nil || v != "secret" {
+		t.Errorf("Get = %q, %v", v, err)
+	}
+	if err := m.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Delete("a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	m := NewMemory()
+	m.Set("telegram/bot", "123:abc")
+	params := map[string]string{"token": "secret:telegram/bot", "chatId": "42"}
+	out, err := Resolve(m, params)
+	if err != nil || out["token"] != "123:abc" || out["chatId"] != "42" {
+		t.Fatalf("Resolve = %v, %v", out, err)
+	}
+	if params["token"] != "secret:telegram/bot" {
+		t.Error("Resolve should not modify the input")
+	}
+	if _, err := Resolve(m, map[string]string{"token": "secret:missing"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package series
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultCapacity 每个序列保留的采样点数
+const DefaultCapacity = 10000
+
+// Sample 时间序列中的一个采样点
+type Sample struct {
+	Series string    `json:"series"`
+	Time   time.Time `json:"time"`
+	Value  float64   `json:"value"`
+	Unit   string    `json:"unit,omitempty"`
+}
+
+// Info 一个序列的概况
+type Info struct {
+	Name  string    `json:"name"`
+	Unit  string    `json:"unit,omitempty"`
+	Count int       `json:"count"`
+	First time.Time `json:"first"`
+	Last  time.Time `json:"last"`
+	Value float64   `json:"value"` // 最新值
+}
+
+// ring 固定容量的环形缓冲区
+type ring struct {
+	buf   []Sample
+	start int
+	n     int
+}
+
+func (r *ring) add(s Sample) {
+	if r.n < len(r.buf) {
+		r.buf[(r.start+r.n)%len(r.buf)] = s
+		r.n++
+		return
+	}
+	r.buf[r.start] = s
+	r.start = (r.start + 1) % len(r.buf)
+}
+
+func (r *ring) at(i int) Sample {
+	return r.buf[(r.start+i)%len(r.buf)]
+}
+
+// Store 按名称保存各序列最近的采样点，供绘图与导出使用
+type Store struct {
+	mu       sync.RWMutex
+	capacity int
+	series   map[string]*ring
+}
+
+// New 创建存储，capacity <= 0 时使用 DefaultCapacity
+func New(capacity int) *Store {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Store{capacity: capacity, series: map[string]*ring{}}
+}
+
+// Add 追加采样点，超出容量时丢弃最旧的点
+func (s *Store) Add(sample Sample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.series[sample.Series]
+	if !ok {
+		r = &ring{buf: make([]Sample, s.capacity)}
+		s.series[sample.Series] = r
+	}
+	r.add(sample)
+}
+
+// List 返回所有序列的概况，按名称排序
+func (s *Store) List() []Info {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]Info, 0, len(s.series))
+	for name, r := range s.series {
+		if r.n == 0 {
+			continue
+		}
+		last := r.at(r.n - 1)
+		list = append(list, Info{Name: name, Unit: last.Unit, Count: r.n, First: r.at(0).Time, Last: last.Time, Value: last.Value})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Get 返回序列中时间晚于 since 的采样点 (since 为零值时返回全部)
+func (s *Store) Get(name string, since time.Time) []Sample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := []Sample{}
+	r, ok := s.series[name]
+	if !ok {
+		return out
+	}
+	// 采样点按到达顺序保存，时间基本有序，二分查找起点
+	i := sort.Search(r.n, func(i int) bool { return r.at(i).Time.After(since) })
+	for ; i < r.n; i++ {
+		out = append(out, r.at(i))
+	}
+	return out
+}
+
+// Clear 清空指定序列，name 为空时清空全部
+func (s *Store) Clear(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if name == "" {
+		s.series = map[string]*ring{}
+		return
+	}
+	delete(s.series, name)
+}
@@ -0,0 +1,37 @@
+package series
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreKeepsLatest(t *testing.T) {
+	s := New(3)
+	t0 := time.Unix(100, 0)
+	for i := 0; i < 5; i++ {
+		s.Add(Sample{Series: "vout", Time: t0.Add(time.Duration(i) * time.Second), Value: float64(i), Unit: "V"})
+	}
+	s.Add(Sample{Series: "iout", Time: t0, Value: 0.5, Unit: "A"})
+
+	all := s.Get("vout", time.Time{})
+	if len(all) != 3 || all[0].Value != 2 || all[2].Value != 4 {
+		t.Errorf("Expected the latest three samples, got %+v", all)
+	}
+	if recent := s.Get("vout", t0.Add(3*time.Second)); len(recent) != 1 || recent[0].Value != 4 {
+		t.Errorf("Expected samples after t0+3s, got %+v", recent)
+	}
+
+	list := s.List()
+	if len(list) != 2 || list[0].Name != "iout" || list[1].Count != 3 || list[1].Value != 4 || list[1].Unit != "V" {
+		t.Errorf("Unexpected list %+v", list)
+	}
+
+	s.Clear("vout")
+	if len(s.Get("vout", time.Time{})) != 0 || len(s.List()) != 1 {
+		t.Error("Clear should remove only the named series")
+	}
+	s.Clear("")
+	if len(s.List()) != 0 {
+		t.Error("Clear(\"\") should remove all series")
+	}
+}
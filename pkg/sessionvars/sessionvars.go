@@ -0,0 +1,197 @@
+// Package sessionvars 会话变量：由用户设置或由规则从设备输出中提取 (如序列号)，
+// 在发送模板、命令脚本与会话报告中以 {{var.NAME}} 引用，新连接建立时清空
+package sessionvars
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prefix 模板中引用会话变量的占位符前缀，如 {{var.serial}}
+const Prefix = "var."
+
+// MaxValueLength 变量值的最大长度
+const MaxValueLength = 4096
+
+// 来源
+const (
+	SourceManual = "manual"
+	SourceRule   = "rule" // 规则提取，Var.Source 为 "rule:<规则名>"
+)
+
+// Var 一个会话变量
+type Var struct {
+	Name    string    `json:"name"`
+	Value   string    `json:"value"`
+	Source  string    `json:"source"`
+	Updated time.Time `json:"updated"`
+}
+
+// 错误
+var (
+	ErrInvalidName = errors.New("sessionvars: invalid variable name")
+	ErrTooLong     = errors.New("sessionvars: value too long")
+	ErrNotSet      = errors.New("sessionvars: variable is not set")
+)
+
+var nameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]{0,63}$`)
+
+// ValidName 变量名是否有效：字母或下划线开头，由字母、数字、_ . - 组成，最长 64 个字符
+func ValidName(name string) bool {
+	return nameRe.MatchString(name)
+}
+
+// Store 会话变量表，可并发使用
+type Store struct {
+	mu       sync.Mutex
+	vars     map[string]Var
+	onChange func()
+	now      func() time.Time
+}
+
+// New 创建空的变量表
+func New() *Store {
+	return &Store{vars: map[string]Var{}, now: time.Now}
+}
+
+// OnChange 设置变量变化后的回调 (在锁外调用)
+func (s *Store) OnChange(fn func()) {
+	s.mu.Lock()
+	s.onChange = fn
+	s.mu.Unlock()
+}
+
+// Set 设置变量
+func (s *Store) Set(name, value, source string) error {
+	if !ValidName(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	if len(value) > MaxValueLength {
+		return fmt.Errorf("%w: %s", ErrTooLong, name)
+	}
+	s.mu.Lock()
+	old, existed := s.vars[name]
+	s.vars[name] = Var{Name: name, Value: value, Source: source, Updated: s.now()}
+	fn := s.onChange
+	s.mu.Unlock()
+	if fn != nil && (!existed || old.Value != value || old.Source != source) {
+		fn()
+	}
+	return nil
+}
+
+// Get 返回变量的值
+func (s *Store) Get(name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.vars[name]
+	return v.Value, ok
+}
+
+// Delete 删除变量，变量不存在时返回 false
+func (s *Store) Delete(name string) bool {
+	s.mu.Lock()
+	_, ok := s.vars[name]
+	delete(s.vars, name)
+	fn := s.onChange
+	s.mu.Unlock()
+	if ok && fn != nil {
+		fn()
+	}
+	return ok
+}
+
+// Clear 删除全部变量
+func (s *Store) Clear() {
+	s.mu.Lock()
+	n := len(s.vars)
+	s.vars = map[string]Var{}
+	fn := s.onChange
+	s.mu.Unlock()
+	if n > 0 && fn != nil {
+		fn()
+	}
+}
+
+// List 返回全部变量，按名称排序
+func (s *Store) List() []Var {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]Var, 0, len(s.vars))
+	for _, v := range s.vars {
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Values 返回变量名到值的副本
+func (s *Store) Values() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := make(map[string]string, len(s.vars))
+	for k, v := range s.vars {
+		m[k] = v.Value
+	}
+	return m
+}
+
+// Expand 将 s 中的 {{var.NAME}} 替换为变量的值，其他内容 (含其他占位符) 原样保留
+// 引用了未设置的变量时返回 ErrNotSet
+func (s *Store) Expand(text string) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.Index(text, "{{")
+		if i < 0 {
+			break
+		}
+		j := strings.Index(text[i:], "}}")
+		if j < 0 {
+			break
+		}
+		expr := strings.TrimSpace(text[i+2 : i+j])
+		b.WriteString(text[:i])
+		if name, ok := strings.CutPrefix(expr, Prefix); ok {
+			v, ok := s.Get(name)
+			if !ok {
+				return "", fmt.Errorf("%w: %s", ErrNotSet, name)
+			}
+			b.WriteString(v)
+		} else {
+			b.WriteString(text[i : i+j+2])
+		}
+		text = text[i+j+2:]
+	}
+	b.WriteString(text)
+	return b.String(), nil
+}
+
+var groupRe = regexp.MustCompile(`\$(\d)`)
+
+// Capture 按模板从规则命中的行中取值：$0 为整行，$1-$9 为正则捕获组
+// 模板为空时取第一个捕获组，没有捕获组时取整行；两端空白被去除
+func Capture(tmpl, line string, groups []string) string {
+	if tmpl == "" {
+		if len(groups) > 0 {
+			return strings.TrimSpace(groups[0])
+		}
+		return strings.TrimSpace(line)
+	}
+	v := groupRe.ReplaceAllStringFunc(tmpl, func(m string) string {
+		n, _ := strconv.Atoi(m[1:])
+		switch {
+		case n == 0:
+			return line
+		case n <= len(groups):
+			return groups[n-1]
+		}
+		return ""
+	})
+	return strings.TrimSpace(v)
+}
@@ -0,0 +1,79 @@
+package sessionvars
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	s := New()
+	changes := 0
+	s.OnChange(func() { changes++ })
+
+	if err := s.Set("serial", "SN-0042", SourceManual); err != nil {
+		t.Fatal(err)
+	}
+	s.Set("serial", "SN-0042", SourceManual) // 未变化，不通知
+	s.Set("fw.version", "1.2.3", SourceRule+":banner")
+	if v, ok := s.Get("serial"); !ok || v != "SN-0042" {
+		t.Errorf("Get = %q, %v", v, ok)
+	}
+	if list := s.List(); len(list) != 2 || list[0].Name != "fw.version" || list[0].Source != "rule:banner" {
+		t.Errorf("Unexpected list %+v", list)
+	}
+	if !s.Delete("serial") || s.Delete("serial") {
+		t.Error("Delete should report whether the variable existed")
+	}
+	s.Clear()
+	if len(s.Values()) != 0 {
+		t.Error("Clear should remove all variables")
+	}
+	if changes != 4 {
+		t.Errorf("changes = %d, want 4", changes)
+	}
+
+	for _, name := range []string{"", "1abc", "a b", "a{b}"} {
+		if err := s.Set(name, "x", SourceManual); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Set(%q) = %v", name, err)
+		}
+	}
+	if err := s.Set("big", string(make([]byte, MaxValueLength+1)), SourceManual); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Expected ErrTooLong, got %v", err)
+	}
+}
+
+func TestExpand(t *testing.T) {
+	s := New()
+	s.Set("serial", "SN-0042", SourceManual)
+	got, err := s.Expand("flash {{ var.serial }} at {{timestamp}} {{var.serial}}")
+	if err != nil || got != "flash SN-0042 at {{timestamp}} SN-0042" {
+		t.Errorf("Expand = %q, %v", got, err)
+	}
+	if got, _ := s.Expand("no {{ placeholders"); got != "no {{ placeholders" {
+		t.Errorf("Unterminated placeholder should be kept, got %q", got)
+	}
+	if _, err := s.Expand("{{var.missing}}"); !errors.Is(err, ErrNotSet) {
+		t.Errorf("Expected ErrNotSet, got %v", err)
+	}
+}
+
+func TestCapture(t *testing.T) {
+	line := "Serial: AB12 rev C"
+	groups := []string{"AB12", "C"}
+	cases := []struct {
+		tmpl   string
+		groups []string
+		want   string
+	}{
+		{"", groups, "AB12"},
+		{"", nil, line},
+		{"$1-$2", groups, "AB12-C"},
+		{"[$0]", groups, "[" + line + "]"},
+		{"$5", groups, ""},
+	}
+	for _, c := range cases {
+		if got := Capture(c.tmpl, line, c.groups); got != c.want {
+			t.Errorf("Capture(%q) = %q, want %q", c.tmpl, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Package setup 首次运行向导：推荐设备与串口参数、检查权限和驱动、保存向导结果
+package setup
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"serial-assistant/pkg/driverhealth"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/identify"
+	"serial-assistant/pkg/permissions"
+	"serial-assistant/pkg/ports"
+)
+
+// Settings 向导得出的连接参数，字段含义与 OpenSerialWithOptions 相同
+type Settings struct {
+	Port        string `json:"port"`
+	Profile     string `json:"profile,omitempty"` // identify 识别出的配置名
+	BaudRate    int    `json:"baudRate"`
+	DataBits    int    `json:"dataBits"`
+	StopBits    int    `json:"stopBits"`
+	Parity      string `json:"parity"`
+	FlowControl string `json:"flowControl,omitempty"`
+}
+
+// profileBaud 各配置的常用波特率，未列出的为 115200
+var profileBaud = map[string]int{
+	"arduino": 9600,
+	"ublox":   9600,
+}
+
+// Suggest 按识别出的配置给出串口参数，均为 8N1 无流控
+func Suggest(port, profile string) Settings {
+	baud, ok := profileBaud[profile]
+	if !ok {
+		baud = 115200
+	}
+	return Settings{
+		Port: port, Profile: profile, BaudRate: baud,
+		DataBits: 8, StopBits: 1, Parity: "None", FlowControl: ports.FlowNone,
+	}
+}
+
+// Device 向导中列出的候选设备
+type Device struct {
+	Port        ports.Port           `json:"port"`
+	Fingerprint identify.Fingerprint `json:"fingerprint"`
+	Suggested   Settings             `json:"suggested"`
+	Score       float64              `json:"score"` // 越大越可能是用户要连接的设备
+}
+
+// Rank 根据 USB 描述符识别串口并排序，最可能的设备在前
+// 板载串口、被占用或不推荐使用的设备排在后面
+func Rank(list []ports.Port) []Device {
+	devices := make([]Device, 0, len(list))
+	for _, p := range list {
+		fp := identify.Identify(identify.Evidence{
+			VID: p.VID, PID: p.PID, SerialNumber: p.SerialNumber, Product: p.FriendlyName,
+		})
+		score := fp.Confidence
+		if p.IsUSB {
+			score += 1
+		}
+		if !p.Recommended {
+			score -= 1
+		}
+		if p.Busy {
+			score -= 2
+		}
+		if p.Hidden {
+			score -= 0.5
+		}
+		devices = append(devices, Device{Port: p, Fingerprint: fp, Suggested: Suggest(p.Name, fp.Profile), Score: score})
+	}
+	sort.SliceStable(devices, func(i, j int) bool { return devices[i].Score > devices[j].Score })
+	return devices
+}
+
+// Status 检查结果
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// 检查项
+const (
+	CheckPermission = "permission"
+	CheckDriver     = "driver"
+	CheckOpen       = "open"
+	CheckData       = "data"
+)
+
+// Check 一项检查的结果
+type Check struct {
+	ID      string   `json:"id"`
+	Status  Status   `json:"status"`
+	Message string   `json:"message"`
+	Hints   []string `json:"hints,omitempty"` // 建议执行的命令或操作
+}
+
+// Report 对所选串口的检查报告
+type Report struct {
+	Settings Settings `json:"settings"`
+	Checks   []Check  `json:"checks"`
+	Ready    bool     `json:"ready"` // 没有失败的检查项
+}
+
+// Add 追加检查项并更新 Ready
+func (r *Report) Add(c Check) {
+	r.Checks = append(r.Checks, c)
+	r.Ready = true
+	for _, c := range r.Checks {
+		if c.Status == StatusFail {
+			r.Ready = false
+		}
+	}
+}
+
+// PermissionCheck 由权限诊断生成检查项
+func PermissionCheck(r permissions.Report) Check {
+	c := Check{ID: CheckPermission, Status: StatusPass, Message: i18n.T("setup.permission_ok", r.Port)}
+	switch {
+	case !r.Exists:
+		c.Status, c.Message = StatusFail, i18n.T("setup.port_missing", r.Port)
+	case r.Accessible:
+	case r.NeedsRelogin:
+		c.Status, c.Message = StatusFail, i18n.T("setup.permission_relogin", r.Group)
+	default:
+		c.Status, c.Message, c.Hints = StatusFail, i18n.T("setup.permission_denied", r.Port), r.Commands
+	}
+	return c
+}
+
+// DriverCheck 由驱动诊断生成检查项，状态与说明取最严重的问题
+func DriverCheck(r driverhealth.Report) Check {
+	name := r.Driver.Name
+	if name == "" {
+		name = r.Chip
+	}
+	c := Check{ID: CheckDriver, Status: StatusPass, Message: i18n.T("setup.driver_ok", name)}
+	if name == "" {
+		c.Status, c.Message = StatusSkip, i18n.T("setup.driver_unknown")
+	}
+	rank := map[driverhealth.Severity]int{driverhealth.SeverityWarning: 1, driverhealth.SeverityError: 2}
+	worst := 0
+	for _, issue := range r.Issues {
+		c.Hints = append(c.Hints, issue.Message)
+		if rank[issue.Severity] > worst {
+			worst = rank[issue.Severity]
+			c.Message = issue.Message
+		}
+	}
+	switch worst {
+	case 1:
+		c.Status = StatusWarn
+	case 2:
+		c.Status = StatusFail
+	}
+	return c
+}
+
+// DataCheck 根据试打开期间收到的数据判断波特率是否合适
+func DataCheck(data []byte, wait time.Duration) Check {
+	switch {
+	case len(data) == 0:
+		return Check{ID: CheckData, Status: StatusSkip, Message: i18n.T("setup.no_data", wait.Seconds())}
+	case LooksGarbled(data):
+		return Check{ID: CheckData, Status: StatusWarn, Message: i18n.T("setup.garbled", len(data))}
+	}
+	return Check{ID: CheckData, Status: StatusPass, Message: i18n.T("setup.data_ok", len(data))}
+}
+
+// garbledMinLen 少于该长度的数据不足以判断是否乱码
+const garbledMinLen = 16
+
+// LooksGarbled 数据中可打印字符比例过低，通常说明波特率不匹配
+func LooksGarbled(data []byte) bool {
+	if len(data) < garbledMinLen {
+		return false
+	}
+	printable, total := 0, 0
+	for len(data) > 0 {
+		r, n := utf8.DecodeRune(data)
+		data = data[n:]
+		total++
+		switch {
+		case r == utf8.RuneError:
+		case r == '\r' || r == '\n' || r == '\t' || r == 0x1b:
+			printable++
+		case r >= 0x20 && r != 0x7f:
+			printable++
+		}
+	}
+	return printable*10 < total*7
+}
+
+// State 向导状态，保存在应用数据目录
+type State struct {
+	Completed   bool      `json:"completed"`
+	CompletedAt time.Time `json:"completedAt,omitempty"`
+	Settings    Settings  `json:"settings"`
+}
+
+// Load 读取向导状态，文件不存在时返回未完成状态 (即首次运行)
+func Load(path string) (State, error) {
+	var s State
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// Save 保存向导状态
+func Save(path string, s State) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
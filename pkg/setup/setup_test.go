@@ -0,0 +1,101 @@
+package setup
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/driverhealth"
+	"serial-assistant/pkg/permissions"
+	"serial-assistant/pkg/ports"
+)
+
+func TestSuggest(t *testing.T) {
+	if s := Suggest("COM3", "arduino"); s.BaudRate != 9600 || s.DataBits != 8 || s.Parity != "None" {
+		t.Errorf("Unexpected arduino settings %+v", s)
+	}
+	if s := Suggest("COM3", ""); s.BaudRate != 115200 || s.Port != "COM3" {
+		t.Errorf("Unexpected default settings %+v", s)
+	}
+}
+
+func TestRank(t *testing.T) {
+	list := []ports.Port{
+		{Name: "/dev/ttyS0", Recommended: true},
+		{Name: "/dev/ttyUSB0", IsUSB: true, VID: "1A86", PID: "7523", Recommended: true, Busy: true},
+		{Name: "/dev/ttyACM0", IsUSB: true, VID: "303A", PID: "1001", Recommended: true},
+	}
+	devices := Rank(list)
+	if devices[0].Port.Name != "/dev/ttyACM0" || devices[0].Suggested.Profile != "esp32" {
+		t.Errorf("Expected ESP32 first, got %+v", devices[0])
+	}
+	if devices[2].Port.Name != "/dev/ttyUSB0" {
+		t.Errorf("Busy port should be last, got %s", devices[2].Port.Name)
+	}
+}
+
+func TestPermissionCheck(t *testing.T) {
+	if c := PermissionCheck(permissions.Report{Port: "p", Exists: true, Accessible: true}); c.Status != StatusPass {
+		t.Errorf("Expected pass, got %+v", c)
+	}
+	c := PermissionCheck(permissions.Report{Port: "p", Exists: true, Commands: []string{"sudo usermod"}})
+	if c.Status != StatusFail || len(c.Hints) != 1 {
+		t.Errorf("Expected fail with hint, got %+v", c)
+	}
+	if c := PermissionCheck(permissions.Report{Port: "p"}); c.Status != StatusFail {
+		t.Errorf("Missing port should fail, got %+v", c)
+	}
+}
+
+func TestDriverCheck(t *testing.T) {
+	r := driverhealth.Report{Chip: "FT232R", Issues: []driverhealth.Issue{
+		{Severity: driverhealth.SeverityWarning, Message: "latency"},
+		{Severity: driverhealth.SeverityError, Message: "bricked"},
+	}}
+	if c := DriverCheck(r); c.Status != StatusFail || c.Message != "bricked" || len(c.Hints) != 2 {
+		t.Errorf("Unexpected check %+v", c)
+	}
+	if c := DriverCheck(driverhealth.Report{}); c.Status != StatusSkip {
+		t.Errorf("Unknown driver should be skipped, got %+v", c)
+	}
+}
+
+func TestLooksGarbled(t *testing.T) {
+	if LooksGarbled([]byte("ESP-ROM:esp32s3-20210327\r\nBuild:Mar 27 2021\r\n")) {
+		t.Error("Boot log should not look garbled")
+	}
+	if !LooksGarbled([]byte{0x80, 0xfe, 0x00, 0x13, 0xf8, 0x9c, 0x00, 0xe0, 0xfe, 0x11, 0x80, 0x00, 0xf0, 0x1f, 0xff, 0x02}) {
+		t.Error("Random bytes should look garbled")
+	}
+	if c := DataCheck(nil, time.Second); c.Status != StatusSkip {
+		t.Errorf("No data should be skipped, got %+v", c)
+	}
+}
+
+func TestReport(t *testing.T) {
+	var r Report
+	r.Add(Check{ID: CheckPermission, Status: StatusPass})
+	r.Add(Check{ID: CheckDriver, Status: StatusWarn})
+	if !r.Ready {
+		t.Error("Warnings should not block setup")
+	}
+	r.Add(Check{ID: CheckOpen, Status: StatusFail})
+	if r.Ready {
+		t.Error("Failed check should block setup")
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "setup.json")
+	if s, err := Load(path); err != nil || s.Completed {
+		t.Fatalf("Missing file should be first run, got %+v, %v", s, err)
+	}
+	want := State{Completed: true, CompletedAt: time.Unix(1700000000, 0).UTC(), Settings: Suggest("COM3", "stm32")}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil || got != want {
+		t.Errorf("Load = %+v, %v", got, err)
+	}
+}
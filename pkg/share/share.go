@@ -0,0 +1,548 @@
+package share
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// 推送给观看者的消息类型
+const (
+	TypeRx      = "rx"
+	TypeTx      = "tx"
+	TypeSys     = "sys"
+	TypeControl = "control" // Text 为 granted、revoked 或 denied
+	TypeError   = "error"
+)
+
+// 观看者发来的请求类型
+const (
+	RequestControl = "request-control"
+	ReleaseControl = "release-control"
+	RequestSend    = "send"
+)
+
+// Message 推送给观看者的一条消息
+type Message struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Data []byte    `json:"data,omitempty"` // JSON 中为 base64
+	Text string    `json:"text,omitempty"`
+}
+
+// Request 观看者发来的请求
+type Request struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"` // 申请控制权时的自称
+	Data []byte `json:"data,omitempty"`
+}
+
+// 限制
+const (
+	historySize     = 512 // 新观看者连接时补发的最近消息数
+	clientQueueSize = 1024
+	writeTimeout    = 10 * time.Second
+)
+
+//go:embed static/index.html
+var indexHTML []byte
+
+// PythonClient 供 Jupyter 等使用的 Python 客户端，服务器在 /serial_assistant.py 提供下载
+//
+//go:embed static/serial_assistant.py
+var PythonClient []byte
+
+// Viewer 一个已连接的观看者
+type Viewer struct {
+	ID        string    `json:"id"`
+	Remote    string    `json:"remote"`
+	Connected time.Time `json:"connected"`
+	Dropped   uint64    `json:"dropped"` // 因网络慢而丢弃的消息数
+	Name      string    `json:"name,omitempty"`
+	Requested bool      `json:"requested"`       // 正在申请控制权
+	Control   bool      `json:"control"`         // 拥有发送权限
+	KeyID     string    `json:"keyId,omitempty"` // 使用 API 密钥连接时的密钥 ID
+	ReadOnly  bool      `json:"readOnly"`        // 只读密钥，不能申请控制权
+}
+
+// keyControl 通过 control 范围的密钥获得控制权，不受 Grant/Revoke 影响
+func (v *Viewer) keyControl() bool {
+	return v.KeyID != "" && !v.ReadOnly
+}
+
+// Principal 通过 API 密钥或一次性令牌认证的调用方
+type Principal struct {
+	KeyID    string
+	Name     string
+	Control  bool // control 范围，连接后直接拥有发送权限
+	ReadOnly bool // 只读，不能申请控制权 (read 范围的密钥总是只读)
+}
+
+// KeyFunc 校验 API 密钥明文
+type KeyFunc func(secret string) (Principal, bool)
+
+// Label 用于日志中标识观看者，如 "alice (192.168.1.5:51234)"
+func (v *Viewer) Label() string {
+	if v.Name == "" {
+		return v.Remote
+	}
+	return v.Name + " (" + v.Remote + ")"
+}
+
+type client struct {
+	Viewer
+	send    chan Message
+	dropped atomic.Uint64
+	conn    *websocket.Conn
+}
+
+// Handlers 控制权相关的回调，均在观看者的读取协程中调用
+type Handlers struct {
+	// OnRequest 观看者申请控制权，主机可调用 Grant 授予
+	OnRequest func(v Viewer)
+	// OnSend 拥有控制权的观看者发送数据，返回的错误会回复给该观看者
+	OnSend func(v Viewer, data []byte) error
+	// OnCommand 每个请求处理完毕后调用 (用于审计)，err 为拒绝或失败的原因
+	OnCommand func(v Viewer, req Request, err error)
+}
+
+// 回复给观看者的拒绝原因
+var (
+	errNoControl = errors.New("no control")
+	errReadOnly  = errors.New("read-only key")
+)
+
+// Server 会话共享服务器：内嵌网页 + WebSocket 推送，访问需携带令牌
+// 默认只读；主机可将发送权限授予一个申请控制权的观看者
+type Server struct {
+	token    string
+	upgrader websocket.Upgrader
+	handlers Handlers
+	keys     KeyFunc
+
+	mu      sync.Mutex
+	clients map[string]*client
+	history []Message
+	nextID  int
+	oneTime map[string]oneTimeToken
+
+	srv *http.Server
+	ln  net.Listener
+}
+
+// oneTimeToken 只能用于建立一次 WebSocket 连接的令牌
+type oneTimeToken struct {
+	principal Principal
+	expires   time.Time
+}
+
+// ErrRunning 服务器已在运行
+var ErrRunning = errors.New("share: already running")
+
+// NewToken 生成随机访问令牌
+func NewToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// New 创建服务器，token 为访问令牌
+func New(token string) *Server {
+	s := &Server{token: token, clients: map[string]*client{}, oneTime: map[string]oneTimeToken{}}
+	// 令牌已经保证了访问权限，允许任意来源的页面连接
+	s.upgrader.CheckOrigin = func(*http.Request) bool { return true }
+	return s
+}
+
+// SetHandlers 设置控制权回调
+func (s *Server) SetHandlers(h Handlers) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = h
+}
+
+// SetKeyFunc 允许使用 API 密钥代替会话令牌连接，nil 表示只接受会话令牌
+func (s *Server) SetKeyFunc(f KeyFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = f
+}
+
+// 授权错误
+var (
+	ErrNoViewer = errors.New("share: no such viewer")
+	ErrReadOnly = errors.New("share: viewer is read-only")
+)
+
+// Grant 将发送权限授予观看者，同一时间只有一个观看者拥有控制权
+func (s *Server) Grant(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.clients[id]
+	if !ok {
+		return ErrNoViewer
+	}
+	if c.ReadOnly {
+		return ErrReadOnly
+	}
+	for _, other := range s.clients {
+		if other != c && other.Control && !other.keyControl() {
+			other.Control = false
+			s.notifyLocked(other, Message{Type: TypeControl, Text: "revoked"})
+		}
+	}
+	c.Control = true
+	c.Requested = false
+	s.notifyLocked(c, Message{Type: TypeControl, Text: "granted"})
+	return nil
+}
+
+// Revoke 收回所有观看者的发送权限并拒绝未处理的申请
+// 通过 control 密钥获得的权限不受影响，需用 DisconnectKey 断开
+func (s *Server) Revoke() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.clients {
+		switch {
+		case c.keyControl():
+		case c.Control:
+			c.Control = false
+			s.notifyLocked(c, Message{Type: TypeControl, Text: "revoked"})
+		case c.Requested:
+			c.Requested = false
+			s.notifyLocked(c, Message{Type: TypeControl, Text: "denied"})
+		}
+	}
+}
+
+// DisconnectKey 断开使用指定 API 密钥的所有观看者 (密钥被吊销后调用)
+func (s *Server) DisconnectKey(keyID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range s.clients {
+		if c.KeyID == keyID {
+			c.conn.Close()
+		}
+	}
+}
+
+// notifyLocked 向单个观看者发送消息，队列已满时丢弃
+func (s *Server) notifyLocked(c *client, m Message) {
+	if m.Time.IsZero() {
+		m.Time = time.Now()
+	}
+	select {
+	case c.send <- m:
+	default:
+		c.dropped.Add(1)
+	}
+}
+
+// Token 返回访问令牌
+func (s *Server) Token() string {
+	return s.token
+}
+
+// IssueOneTimeToken 生成只能建立一次 WebSocket 连接的令牌，ttl 内未使用则失效
+func (s *Server) IssueOneTimeToken(p Principal, ttl time.Duration) string {
+	token := NewToken()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for t, ot := range s.oneTime {
+		if now.After(ot.expires) {
+			delete(s.oneTime, t)
+		}
+	}
+	s.oneTime[token] = oneTimeToken{principal: p, expires: now.Add(ttl)}
+	return token
+}
+
+// takeOneTime 校验并作废一次性令牌
+func (s *Server) takeOneTime(token string) (Principal, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ot, ok := s.oneTime[token]
+	if !ok {
+		return Principal{}, false
+	}
+	delete(s.oneTime, token)
+	return ot.principal, time.Now().Before(ot.expires)
+}
+
+// Start 在 addr (如 ":8765") 上开始监听，返回实际监听的地址
+func (s *Server) Start(addr string) (net.Addr, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.srv != nil {
+		return nil, ErrRunning
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.serveIndex)
+	mux.HandleFunc("/ws", s.serveWS)
+	mux.HandleFunc("/serial_assistant.py", servePythonClient)
+	s.ln = ln
+	s.srv = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go s.srv.Serve(ln)
+	return ln.Addr(), nil
+}
+
+// Stop 关闭服务器并断开所有观看者
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	srv := s.srv
+	s.srv = nil
+	clients := s.clients
+	s.clients = map[string]*client{}
+	s.mu.Unlock()
+	if srv == nil {
+		return nil
+	}
+	for _, c := range clients {
+		c.conn.Close()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return srv.Shutdown(ctx)
+}
+
+// Running 服务器是否在运行
+func (s *Server) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.srv != nil
+}
+
+// Broadcast 推送消息给所有观看者；观看者的队列已满时丢弃该消息
+func (s *Server) Broadcast(m Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.srv == nil {
+		return
+	}
+	s.history = append(s.history, m)
+	if len(s.history) > historySize {
+		s.history = append(s.history[:0], s.history[len(s.history)-historySize:]...)
+	}
+	for _, c := range s.clients {
+		select {
+		case c.send <- m:
+		default:
+			c.dropped.Add(1)
+		}
+	}
+}
+
+// Viewers 返回已连接的观看者
+func (s *Server) Viewers() []Viewer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]Viewer, 0, len(s.clients))
+	for _, c := range s.clients {
+		v := c.Viewer
+		v.Dropped = c.dropped.Load()
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Connected.Before(list[j].Connected)
+	})
+	return list
+}
+
+// requestToken 返回查询参数 token 或 Authorization: Bearer 中的令牌
+func requestToken(r *http.Request) string {
+	if h := r.Header.Get("Authorization"); len(h) > 7 && h[:7] == "Bearer " {
+		return h[7:]
+	}
+	return r.URL.Query().Get("token")
+}
+
+// authorized 检查请求中的会话令牌或 API 密钥
+// 使用会话令牌时返回零值 Principal
+func (s *Server) authorized(r *http.Request) (Principal, bool) {
+	token := requestToken(r)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1 {
+		return Principal{}, true
+	}
+	s.mu.Lock()
+	keys := s.keys
+	s.mu.Unlock()
+	if keys == nil || token == "" {
+		return Principal{}, false
+	}
+	return keys(token)
+}
+
+func (s *Server) serveIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	if _, ok := s.authorized(r); !ok {
+		http.Error(w, "invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(indexHTML)
+}
+
+// servePythonClient 提供 Python 客户端下载，客户端本身不含令牌，无需认证
+func servePythonClient(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/x-python; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="serial_assistant.py"`)
+	w.Write(PythonClient)
+}
+
+func (s *Server) serveWS(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.authorized(r)
+	if !ok {
+		p, ok = s.takeOneTime(requestToken(r))
+	}
+	if !ok {
+		http.Error(w, "invalid or missing token", http.StatusUnauthorized)
+		return
+	}
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.srv == nil {
+		s.mu.Unlock()
+		conn.Close()
+		return
+	}
+	s.nextID++
+	c := &client{
+		Viewer: Viewer{
+			ID:        strconv.Itoa(s.nextID),
+			Remote:    r.RemoteAddr,
+			Connected: time.Now(),
+			Name:      p.Name,
+			KeyID:     p.KeyID,
+			ReadOnly:  p.ReadOnly || (p.KeyID != "" && !p.Control),
+			Control:   p.Control,
+		},
+		send: make(chan Message, clientQueueSize),
+		conn: conn,
+	}
+	for _, m := range s.history {
+		c.send <- m
+	}
+	if c.Control {
+		c.send <- Message{Type: TypeControl, Time: time.Now(), Text: "granted"}
+	}
+	s.clients[c.ID] = c
+	s.mu.Unlock()
+
+	go s.writeLoop(c)
+	s.readLoop(c)
+}
+
+// readLoop 处理观看者的请求直到断开
+func (s *Server) readLoop(c *client) {
+	defer s.remove(c)
+	for {
+		var req Request
+		if err := c.conn.ReadJSON(&req); err != nil {
+			var syntax *json.SyntaxError
+			var typ *json.UnmarshalTypeError
+			if errors.As(err, &syntax) || errors.As(err, &typ) {
+				// 格式错误的请求忽略，连接继续
+				continue
+			}
+			return
+		}
+		s.handle(c, req)
+	}
+}
+
+// handle 处理一个请求，处理结果交给 OnCommand 记录
+func (s *Server) handle(c *client, req Request) {
+	s.mu.Lock()
+	h := s.handlers
+	var err error
+	switch req.Type {
+	case RequestControl:
+		if c.ReadOnly {
+			err = errReadOnly
+			break
+		}
+		if req.Name != "" && c.KeyID == "" {
+			c.Name = req.Name
+		}
+		already := c.Control
+		c.Requested = !already
+		v := c.Viewer
+		s.mu.Unlock()
+		if !already && h.OnRequest != nil {
+			h.OnRequest(v)
+		}
+		if h.OnCommand != nil {
+			h.OnCommand(v, req, nil)
+		}
+		return
+	case ReleaseControl:
+		c.Control = false
+		c.Requested = false
+		s.notifyLocked(c, Message{Type: TypeControl, Text: "revoked"})
+	case RequestSend:
+		if !c.Control || h.OnSend == nil {
+			err = errNoControl
+			break
+		}
+		v := c.Viewer
+		s.mu.Unlock()
+		err = h.OnSend(v, req.Data)
+		s.mu.Lock()
+	default:
+		err = errors.New("unknown request " + req.Type)
+	}
+	if err != nil {
+		s.notifyLocked(c, Message{Type: TypeError, Text: err.Error()})
+	}
+	v := c.Viewer
+	s.mu.Unlock()
+	if h.OnCommand != nil {
+		h.OnCommand(v, req, err)
+	}
+}
+
+func (s *Server) writeLoop(c *client) {
+	for m := range c.send {
+		c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := c.conn.WriteJSON(m); err != nil {
+			c.conn.Close()
+			return
+		}
+	}
+}
+
+func (s *Server) remove(c *client) {
+	s.mu.Lock()
+	if s.clients[c.ID] == c {
+		delete(s.clients, c.ID)
+	}
+	s.mu.Unlock()
+	c.conn.Close()
+	close(c.send)
+}
@@ -0,0 +1,263 @@
+package share
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func start(t *testing.T) (*Server, string) {
+	t.Helper()
+	s := New("secret")
+	addr, err := s.Start("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(func() { s.Stop() })
+	return s, addr.String()
+}
+
+func TestTokenRequired(t *testing.T) {
+	_, addr := start(t)
+	resp, err := http.Get("http://" + addr + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get("http://" + addr + "/?token=secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "WebSocket") {
+		t.Errorf("Expected the viewer page, got %d", resp.StatusCode)
+	}
+
+	if _, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/ws?token=wrong", nil); err == nil {
+		t.Error("WebSocket with a wrong token should be rejected")
+	}
+}
+
+func TestViewerReceivesHistoryAndLive(t *testing.T) {
+	s, addr := start(t)
+	s.Broadcast(Message{Type: TypeRx, Data: []byte("before\n")})
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/ws?token=secret", nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var m Message
+	if err := conn.ReadJSON(&m); err != nil || string(m.Data) != "before\n" {
+		t.Fatalf("Expected history first, got %+v %v", m, err)
+	}
+	s.Broadcast(Message{Type: TypeTx, Data: []byte("AT\r\n")})
+	if err := conn.ReadJSON(&m); err != nil || m.Type != TypeTx || string(m.Data) != "AT\r\n" {
+		t.Fatalf("Expected live message, got %+v %v", m, err)
+	}
+	if v := s.Viewers(); len(v) != 1 {
+		t.Errorf("Expected one viewer, got %+v", v)
+	}
+
+	s.Stop()
+	if _, _, err := conn.ReadMessage(); err == nil {
+		t.Error("Viewer should be disconnected on Stop")
+	}
+	if s.Running() {
+		t.Error("Server should not be running after Stop")
+	}
+}
+
+func dial(t *testing.T, addr string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/ws?token=secret", nil)
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+// expect 读取直到出现指定类型的消息
+func expect(t *testing.T, conn *websocket.Conn, typ string) Message {
+	t.Helper()
+	for {
+		var m Message
+		if err := conn.ReadJSON(&m); err != nil {
+			t.Fatalf("Waiting for %s: %v", typ, err)
+		}
+		if m.Type == typ {
+			return m
+		}
+	}
+}
+
+func TestControlHandoff(t *testing.T) {
+	s, addr := start(t)
+	requested := make(chan Viewer, 1)
+	sent := make(chan string, 1)
+	s.SetHandlers(Handlers{
+		OnRequest: func(v Viewer) { requested <- v },
+		OnSend: func(v Viewer, data []byte) error {
+			sent <- v.Name + ":" + string(data)
+			return nil
+		},
+	})
+	conn := dial(t, addr)
+
+	// 未授权时不能发送
+	conn.WriteJSON(Request{Type: RequestSend, Data: []byte("x")})
+	if m := expect(t, conn, TypeError); m.Text != "no control" {
+		t.Errorf("Expected no-control error, got %+v", m)
+	}
+
+	conn.WriteJSON(Request{Type: RequestControl, Name: "alice"})
+	var v Viewer
+	select {
+	case v = <-requested:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Host was not asked for control")
+	}
+	if v.Name != "alice" || v.Label() == "" {
+		t.Errorf("Unexpected viewer %+v", v)
+	}
+	if err := s.Grant(v.ID); err != nil {
+		t.Fatal(err)
+	}
+	if m := expect(t, conn, TypeControl); m.Text != "granted" {
+		t.Errorf("Expected granted, got %+v", m)
+	}
+
+	conn.WriteJSON(Request{Type: RequestSend, Data: []byte("AT\r\n")})
+	select {
+	case got := <-sent:
+		if got != "alice:AT\r\n" {
+			t.Errorf("Unexpected send %q", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Send was not forwarded")
+	}
+
+	s.Revoke()
+	if m := expect(t, conn, TypeControl); m.Text != "revoked" {
+		t.Errorf("Expected revoked, got %+v", m)
+	}
+	if err := s.Grant("nope"); err != ErrNoViewer {
+		t.Errorf("Expected ErrNoViewer, got %v", err)
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	s, addr := start(t)
+	s.SetKeyFunc(func(secret string) (Principal, bool) {
+		switch secret {
+		case "sm_read":
+			return Principal{KeyID: "r", Name: "dashboard"}, true
+		case "sm_ctl":
+			return Principal{KeyID: "c", Name: "ci", Control: true}, true
+		}
+		return Principal{}, false
+	})
+	type command struct {
+		who, action string
+		err         error
+	}
+	commands := make(chan command, 4)
+	s.SetHandlers(Handlers{
+		OnSend: func(Viewer, []byte) error { return nil },
+		OnCommand: func(v Viewer, req Request, err error) {
+			commands <- command{v.Name, req.Type, err}
+		},
+	})
+
+	dialKey := func(secret string) *websocket.Conn {
+		header := http.Header{"Authorization": {"Bearer " + secret}}
+		conn, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/ws", header)
+		if err != nil {
+			t.Fatalf("Dial with %s failed: %v", secret, err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		return conn
+	}
+	if _, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/ws?token=sm_bogus", nil); err == nil {
+		t.Error("Unknown key should be rejected")
+	}
+
+	ro := dialKey("sm_read")
+	ro.WriteJSON(Request{Type: RequestControl, Name: "mallory"})
+	if m := expect(t, ro, TypeError); m.Text != "read-only key" {
+		t.Errorf("Expected read-only error, got %+v", m)
+	}
+	if c := <-commands; c.who != "dashboard" || c.err == nil {
+		t.Errorf("Expected a denied command from the key name, got %+v", c)
+	}
+
+	ctl := dialKey("sm_ctl")
+	if m := expect(t, ctl, TypeControl); m.Text != "granted" {
+		t.Errorf("Control key should be granted on connect, got %+v", m)
+	}
+	s.Revoke()
+	ctl.WriteJSON(Request{Type: RequestSend, Data: []byte("x")})
+	if c := <-commands; c.who != "ci" || c.action != RequestSend || c.err != nil {
+		t.Errorf("Control key should keep sending after Revoke, got %+v", c)
+	}
+
+	for _, v := range s.Viewers() {
+		if v.KeyID == "r" {
+			if err := s.Grant(v.ID); err != ErrReadOnly {
+				t.Errorf("Expected ErrReadOnly, got %v", err)
+			}
+		}
+	}
+	s.DisconnectKey("c")
+	if _, _, err := ctl.ReadMessage(); err == nil {
+		t.Error("Expected the control key connection to be closed")
+	}
+}
+
+func TestOneTimeToken(t *testing.T) {
+	s, addr := start(t)
+	token := s.IssueOneTimeToken(Principal{Name: "notebook", ReadOnly: true}, time.Minute)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/ws?token="+token, nil)
+	if err != nil {
+		t.Fatalf("Dial with one-time token failed: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	conn.WriteJSON(Request{Type: RequestControl})
+	if m := expect(t, conn, TypeError); m.Text != "read-only key" {
+		t.Errorf("Expected a read-only error, got %+v", m)
+	}
+	if _, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/ws?token="+token, nil); err == nil {
+		t.Error("One-time token should not be accepted twice")
+	}
+
+	expired := s.IssueOneTimeToken(Principal{}, -time.Second)
+	if _, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/ws?token="+expired, nil); err == nil {
+		t.Error("Expired one-time token should be rejected")
+	}
+
+	resp, err := http.Get("http://" + addr + "/serial_assistant.py")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "class Client") {
+		t.Errorf("Expected the Python client, got %d bytes", len(body))
+	}
+}
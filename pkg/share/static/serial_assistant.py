@@ -0,0 +1,154 @@
+"""Client for a Serial Assistant shared session.
+
+Connects to the session-sharing WebSocket of a running Serial Assistant and
+yields received/sent data, e.g. from a Jupyter notebook:
+
+    from serial_assistant import Client
+    with Client("ws://localhost:8765/ws", "<token>") as c:
+        for line in c.lines(timeout=10):
+            print(line)
+
+Requires the ``websocket-client`` package (``pip install websocket-client``).
+``to_dataframe`` additionally requires pandas.
+"""
+
+import base64
+import json
+import time
+from collections import namedtuple
+from datetime import datetime, timezone
+
+import websocket
+
+__all__ = ["Client", "Message", "ControlError", "to_dataframe"]
+
+Message = namedtuple("Message", "type time data text")
+Message.__doc__ = """A message pushed by the server.
+
+type is "rx", "tx", "sys", "control" or "error"; data holds the raw bytes of
+rx/tx messages and text the text of the others.
+"""
+
+
+class ControlError(Exception):
+    """The server rejected a request (no control, read-only token...)."""
+
+
+def _parse_time(value):
+    # Go encodes nanoseconds, datetime accepts at most microseconds.
+    if not value:
+        return None
+    if value.endswith("Z"):
+        value = value[:-1] + "+00:00"
+    head, sep, tail = value.partition(".")
+    if sep:
+        digits = ""
+        while tail and tail[0].isdigit():
+            digits, tail = digits + tail[0], tail[1:]
+        value = head + "." + digits[:6].ljust(6, "0") + tail
+    return datetime.fromisoformat(value).astimezone(timezone.utc)
+
+
+def _parse(raw):
+    m = json.loads(raw)
+    data = base64.b64decode(m["data"]) if m.get("data") else b""
+    return Message(m.get("type", ""), _parse_time(m.get("time")), data, m.get("text", ""))
+
+
+class Client:
+    """A connection to a shared session.
+
+    url is the WebSocket address (ws://host:port/ws) and token the session
+    token, an API key or a one-time notebook token. Messages buffered by the
+    server (the recent history) are delivered first, then live traffic.
+    """
+
+    def __init__(self, url, token, timeout=10):
+        self._ws = websocket.create_connection(
+            url, timeout=timeout, header=["Authorization: Bearer " + token]
+        )
+        self.control = False
+
+    def __enter__(self):
+        return self
+
+    def __exit__(self, *exc):
+        self.close()
+
+    def close(self):
+        self._ws.close()
+
+    def recv(self, timeout=None):
+        """Return the next message, or None when timeout (seconds) expires."""
+        self._ws.settimeout(timeout)
+        try:
+            m = _parse(self._ws.recv())
+        except websocket.WebSocketTimeoutException:
+            return None
+        if m.type == "control":
+            self.control = m.text == "granted"
+        return m
+
+    def messages(self, types=("rx", "tx", "sys"), timeout=None):
+        """Yield messages of the given types until timeout seconds pass
+        without any message (forever when timeout is None)."""
+        while True:
+            m = self.recv(timeout)
+            if m is None:
+                return
+            if m.type == "error":
+                raise ControlError(m.text)
+            if m.type in types:
+                yield m
+
+    def read(self, size=None, timeout=1.0):
+        """Collect received bytes until size bytes arrived or timeout seconds
+        pass without new data."""
+        buf = bytearray()
+        for m in self.messages(("rx",), timeout):
+            buf += m.data
+            if size is not None and len(buf) >= size:
+                break
+        return bytes(buf)
+
+    def lines(self, timeout=None, encoding="utf-8", newline=b"\n"):
+        """Yield received data split into decoded lines (without the line end)."""
+        pending = b""
+        for m in self.messages(("rx",), timeout):
+            pending += m.data
+            *complete, pending = pending.split(newline)
+            for line in complete:
+                yield line.rstrip(b"\r").decode(encoding, errors="replace")
+
+    def request_control(self, name="notebook", timeout=60):
+        """Ask the host for control and wait until it is granted."""
+        self._ws.send(json.dumps({"type": "request-control", "name": name}))
+        deadline = time.monotonic() + timeout
+        while not self.control:
+            remaining = deadline - time.monotonic()
+            if remaining <= 0:
+                raise ControlError("control not granted")
+            m = self.recv(remaining)
+            if m is not None and m.type == "error":
+                raise ControlError(m.text)
+            if m is not None and m.type == "control" and m.text == "denied":
+                raise ControlError("control denied")
+
+    def release_control(self):
+        self._ws.send(json.dumps({"type": "release-control"}))
+        self.control = False
+
+    def send(self, data):
+        """Send bytes (or str, encoded as UTF-8) to the device. Requires
+        control; errors are reported by the next messages() call."""
+        if isinstance(data, str):
+            data = data.encode("utf-8")
+        payload = base64.b64encode(data).decode("ascii")
+        self._ws.send(json.dumps({"type": "send", "data": payload}))
+
+
+def to_dataframe(messages):
+    """Convert messages into a pandas DataFrame with time, type, data and text columns."""
+    import pandas as pd
+
+    return pd.DataFrame([m._asdict() for m in messages], columns=Message._fields)
@@ -0,0 +1,121 @@
+package snippets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoGit 系统中找不到 git
+var ErrNoGit = errors.New("snippets: git is not installed")
+
+// Sync 将 url 处的仓库克隆或更新到 dir，并读取其中的片段
+// dir 中已有其他仓库的克隆时先删除；仓库中每个 .json 文件为一个片段或片段数组
+func Sync(ctx context.Context, url, dir string) ([]Snippet, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, ErrNoGit
+	}
+	if url == "" {
+		return nil, errors.New("snippets: repository url is required")
+	}
+	// 只在 dir 本身是克隆时询问 origin，避免 git 向上找到外层仓库
+	origin := ""
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		origin, _ = git(ctx, dir, "remote", "get-url", "origin")
+	}
+	if origin != url {
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+			return nil, err
+		}
+		if _, err := git(ctx, "", "clone", "--depth", "1", "--", url, dir); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := git(ctx, dir, "fetch", "--depth", "1", "origin"); err != nil {
+			return nil, err
+		}
+		if _, err := git(ctx, dir, "reset", "--hard", "FETCH_HEAD"); err != nil {
+			return nil, err
+		}
+	}
+	return ReadDir(dir)
+}
+
+// ReadDir 读取目录 (不含 .git) 中所有 .json 文件的片段，来源为文件的相对路径
+func ReadDir(dir string) ([]Snippet, error) {
+	var all []Snippet
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".json" {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		list, err := readFile(path)
+		if err == nil {
+			list, err = normalize(filepath.ToSlash(rel), list)
+		}
+		if err != nil {
+			return err
+		}
+		all = append(all, list...)
+		return nil
+	})
+	return all, err
+}
+
+// readFile 读取一个片段文件，内容可以是单个对象或数组
+func readFile(path string) ([]Snippet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	var list []Snippet
+	if len(data) > 0 && data[0] == '{' {
+		var s Snippet
+		err = json.Unmarshal(data, &s)
+		list = []Snippet{s}
+	} else {
+		err = json.Unmarshal(data, &list)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("snippets: %s: %w", path, err)
+	}
+	return list, nil
+}
+
+// git 在 dir 中运行 git 命令，返回去掉首尾空白的标准输出
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// 不弹出凭据输入，私有仓库需预先配置凭据助手或 SSH 密钥
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,216 @@
+package snippets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"serial-assistant/pkg/payload"
+)
+
+// LocalSource 本地保存的代码片段的来源
+const LocalSource = "local"
+
+// Snippet 一个命名的发送内容 (设备命令、脚本等)
+type Snippet struct {
+	ID          string   `json:"id"` // 来源 + "/" + 名称，加载时生成
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Payload     string   `json:"payload"`
+	Hex         bool     `json:"hex"`
+	Tags        []string `json:"tags,omitempty"`
+	Source      string   `json:"source"` // "local" 或仓库中的相对路径
+	// Frame 十六进制内容中 {len}、{sum} 等帧字段的计算方式，nil 使用默认设置
+	Frame *payload.FrameSpec `json:"frame,omitempty"`
+}
+
+// Config 本地片段与同步的仓库地址，保存为 JSON
+type Config struct {
+	Repo     string    `json:"repo,omitempty"`
+	Snippets []Snippet `json:"snippets"`
+}
+
+// ErrNotFound 片段不存在
+var ErrNotFound = errors.New("snippets: not found")
+
+// Library 本地片段与从仓库同步的片段
+type Library struct {
+	mu     sync.RWMutex
+	local  []Snippet
+	remote []Snippet
+}
+
+// New 创建空的片段库
+func New() *Library {
+	return &Library{}
+}
+
+// SetLocal 替换本地片段，名称为空或重复时返回错误
+func (l *Library) SetLocal(list []Snippet) error {
+	list, err := normalize(LocalSource, list)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.local = list
+	return nil
+}
+
+// Local 返回本地片段
+func (l *Library) Local() []Snippet {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]Snippet{}, l.local...)
+}
+
+// SetRemote 替换从仓库同步的片段
+func (l *Library) SetRemote(list []Snippet) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.remote = list
+}
+
+// All 返回全部片段，本地片段在前
+func (l *Library) All() []Snippet {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append(append([]Snippet{}, l.local...), l.remote...)
+}
+
+// Get 按 ID 查找片段
+func (l *Library) Get(id string) (Snippet, error) {
+	for _, s := range l.All() {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return Snippet{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// Search 返回名称、描述或内容包含 query (不区分大小写) 且带有全部 tags 的片段
+// 名称匹配的排在前面
+func (l *Library) Search(query string, tags []string) []Snippet {
+	query = strings.ToLower(strings.TrimSpace(query))
+	type hit struct {
+		s    Snippet
+		rank int
+	}
+	var hits []hit
+	for _, s := range l.All() {
+		if !hasTags(s, tags) {
+			continue
+		}
+		rank := 0
+		if query != "" {
+			switch {
+			case strings.Contains(strings.ToLower(s.Name), query):
+				rank = 0
+			case strings.Contains(strings.ToLower(s.Description), query) || tagged(s, query):
+				rank = 1
+			case strings.Contains(strings.ToLower(s.Payload), query):
+				rank = 2
+			default:
+				continue
+			}
+		}
+		hits = append(hits, hit{s, rank})
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].rank < hits[j].rank })
+	list := make([]Snippet, len(hits))
+	for i, h := range hits {
+		list[i] = h.s
+	}
+	return list
+}
+
+// Tags 返回所有片段使用的标签 (已排序、去重)
+func (l *Library) Tags() []string {
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, s := range l.All() {
+		for _, t := range s.Tags {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func hasTags(s Snippet, tags []string) bool {
+	for _, want := range tags {
+		if !tagged(s, strings.ToLower(want)) {
+			return false
+		}
+	}
+	return true
+}
+
+func tagged(s Snippet, tag string) bool {
+	for _, t := range s.Tags {
+		if strings.ToLower(t) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize 设置来源与 ID，检查名称
+func normalize(source string, list []Snippet) ([]Snippet, error) {
+	out := make([]Snippet, 0, len(list))
+	seen := map[string]bool{}
+	for _, s := range list {
+		s.Name = strings.TrimSpace(s.Name)
+		if s.Name == "" {
+			return nil, fmt.Errorf("snippets: %s: snippet without a name", source)
+		}
+		s.Source = source
+		s.ID = source + "/" + s.Name
+		if seen[s.ID] {
+			return nil, fmt.Errorf("snippets: %s: duplicate name %q", source, s.Name)
+		}
+		seen[s.ID] = true
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// Load 读取配置文件，不存在时返回空配置
+func Load(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("snippets: %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save 写入配置文件
+func Save(path string, cfg Config) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,92 @@
+package snippets
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	l := New()
+	err := l.SetLocal([]Snippet{
+		{Name: "reset", Payload: "AT+RST\r\n", Tags: []string{"esp8266"}},
+		{Name: "version", Description: "firmware reset reason", Payload: "AT+GMR\r\n", Tags: []string{"esp8266", "info"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.SetRemote([]Snippet{{ID: "lib.json/ping", Name: "ping", Payload: "reset-ping", Source: "lib.json"}})
+
+	got := l.Search("reset", nil)
+	if len(got) != 3 || got[0].Name != "reset" || got[1].Name != "version" || got[2].Name != "ping" {
+		t.Errorf("Unexpected ranking %+v", got)
+	}
+	if got := l.Search("", []string{"INFO"}); len(got) != 1 || got[0].Name != "version" {
+		t.Errorf("Expected tag filter to match version, got %+v", got)
+	}
+	if s, err := l.Get("local/reset"); err != nil || s.Payload != "AT+RST\r\n" {
+		t.Errorf("Get failed: %+v %v", s, err)
+	}
+	if _, err := l.Get("nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if tags := l.Tags(); len(tags) != 2 || tags[0] != "esp8266" {
+		t.Errorf("Unexpected tags %v", tags)
+	}
+	if err := l.SetLocal([]Snippet{{Name: "a"}, {Name: "a"}}); err == nil {
+		t.Error("Duplicate names should be rejected")
+	}
+}
+
+func TestSyncFromGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	os.MkdirAll(filepath.Join(repo, "modem"), 0o755)
+	os.WriteFile(filepath.Join(repo, "modem", "at.json"), []byte(`[{"name":"attention","payload":"AT\r\n","tags":["modem"]}]`), 0o644)
+	run("add", ".")
+	run("commit", "-qm", "one")
+
+	dir := filepath.Join(t.TempDir(), "clone")
+	list, err := Sync(context.Background(), repo, dir)
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != "modem/at.json/attention" {
+		t.Fatalf("Unexpected snippets %+v", list)
+	}
+
+	os.WriteFile(filepath.Join(repo, "ping.json"), []byte(`{"name":"ping","payload":"55AA","hex":true}`), 0o644)
+	run("add", ".")
+	run("commit", "-qm", "two")
+	list, err = Sync(context.Background(), repo, dir)
+	if err != nil || len(list) != 2 {
+		t.Fatalf("Expected the update to be pulled, got %+v (%v)", list, err)
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snippets.json")
+	if cfg, err := Load(path); err != nil || cfg.Repo != "" {
+		t.Fatalf("Missing file should load empty, got %+v %v", cfg, err)
+	}
+	Save(path, Config{Repo: "https://example.com/lib.git", Snippets: []Snippet{{Name: "x"}}})
+	cfg, err := Load(path)
+	if err != nil || cfg.Repo == "" || len(cfg.Snippets) != 1 {
+		t.Errorf("Round trip failed: %+v %v", cfg, err)
+	}
+}
@@ -0,0 +1,282 @@
+package soak
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 限制
+const (
+	MinInterval  = time.Second
+	maxSnapshots = 10000 // 超出后丢弃最早的快照，汇总仍包含全部区间
+)
+
+// Counters 累计计数；计数器因重新连接等原因被清零时按从零重新累计处理
+type Counters struct {
+	RxBytes     uint64 `json:"rxBytes"`
+	TxBytes     uint64 `json:"txBytes"`
+	Frames      uint64 `json:"frames"`
+	FrameErrors uint64 `json:"frameErrors"`
+	Reboots     uint64 `json:"reboots"`
+	Crashes     uint64 `json:"crashes"`
+	Alerts      uint64 `json:"alerts"`
+	Disconnects uint64 `json:"disconnects"`
+}
+
+// Sample 一次采样
+type Sample struct {
+	Counters
+	Connected bool
+}
+
+// Snapshot 一个采样区间内的统计
+type Snapshot struct {
+	Time      time.Time `json:"time"`
+	Elapsed   int64     `json:"elapsedSec"`
+	Delta     Counters  `json:"delta"`  // 区间内的增量
+	RxRate    float64   `json:"rxRate"` // 字节/秒
+	TxRate    float64   `json:"txRate"`
+	Connected bool      `json:"connected"`
+}
+
+// Summary 一次浸泡测试的汇总
+type Summary struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name,omitempty"`
+	Started   time.Time  `json:"started"`
+	Ended     time.Time  `json:"ended"`
+	Planned   int64      `json:"plannedSec"`
+	Completed bool       `json:"completed"` // 运行满计划时长 (未被提前停止)
+	Totals    Counters   `json:"totals"`
+	RxRateMin float64    `json:"rxRateMin"`
+	RxRateAvg float64    `json:"rxRateAvg"`
+	RxRateMax float64    `json:"rxRateMax"`
+	Stalls    int        `json:"stalls"`    // 连接正常但没有收到数据的区间数
+	OfflineMs int64      `json:"offlineMs"` // 处于断开状态的累计时长
+	Healthy   bool       `json:"healthy"`   // 完成且没有重启、崩溃、告警、断开与停顿
+	Snapshots []Snapshot `json:"snapshots,omitempty"`
+}
+
+// Run 运行 duration 时长，每 interval 调用 sample 采样并回调 onSnapshot；ctx 取消时提前结束
+// 结束时返回汇总 (提前结束时 Completed 为 false)
+func Run(ctx context.Context, name string, duration, interval time.Duration, sample func() Sample, onSnapshot func(Snapshot)) Summary {
+	start := time.Now()
+	s := Summary{
+		ID:      start.Format("20060102-150405"),
+		Name:    name,
+		Started: start,
+		Planned: int64(duration / time.Second),
+	}
+	prev := sample()
+	prevTime := start
+	deadline := time.NewTimer(duration)
+	defer deadline.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var rxSum float64
+	var intervals int
+	take := func(now time.Time) {
+		cur := sample()
+		elapsed := now.Sub(prevTime)
+		secs := elapsed.Seconds()
+		if secs <= 0 {
+			return
+		}
+		snap := Snapshot{
+			Time:      now,
+			Elapsed:   int64(now.Sub(start) / time.Second),
+			Delta:     delta(prev.Counters, cur.Counters),
+			Connected: cur.Connected,
+		}
+		snap.RxRate = float64(snap.Delta.RxBytes) / secs
+		snap.TxRate = float64(snap.Delta.TxBytes) / secs
+		prev, prevTime = cur, now
+
+		s.Totals = add(s.Totals, snap.Delta)
+		if intervals == 0 || snap.RxRate < s.RxRateMin {
+			s.RxRateMin = snap.RxRate
+		}
+		if snap.RxRate > s.RxRateMax {
+			s.RxRateMax = snap.RxRate
+		}
+		rxSum += snap.RxRate
+		intervals++
+		if !snap.Connected {
+			s.OfflineMs += elapsed.Milliseconds()
+		} else if snap.Delta.RxBytes == 0 {
+			s.Stalls++
+		}
+		s.Snapshots = append(s.Snapshots, snap)
+		if len(s.Snapshots) > maxSnapshots {
+			s.Snapshots = s.Snapshots[len(s.Snapshots)-maxSnapshots:]
+		}
+		if onSnapshot != nil {
+			onSnapshot(snap)
+		}
+	}
+
+loop:
+	for {
+		select {
+		case now := <-ticker.C:
+			take(now)
+		case now := <-deadline.C:
+			take(now)
+			s.Completed = true
+			break loop
+		case <-ctx.Done():
+			take(time.Now())
+			break loop
+		}
+	}
+	s.Ended = time.Now()
+	if intervals > 0 {
+		s.RxRateAvg = rxSum / float64(intervals)
+	}
+	t := s.Totals
+	s.Healthy = s.Completed && t.Reboots == 0 && t.Crashes == 0 && t.Alerts == 0 && t.Disconnects == 0 &&
+		t.FrameErrors == 0 && s.Stalls == 0 && s.OfflineMs == 0
+	return s
+}
+
+func delta(prev, cur Counters) Counters {
+	d := func(p, c uint64) uint64 {
+		if c < p {
+			return c
+		}
+		return c - p
+	}
+	return Counters{
+		RxBytes:     d(prev.RxBytes, cur.RxBytes),
+		TxBytes:     d(prev.TxBytes, cur.TxBytes),
+		Frames:      d(prev.Frames, cur.Frames),
+		FrameErrors: d(prev.FrameErrors, cur.FrameErrors),
+		Reboots:     d(prev.Reboots, cur.Reboots),
+		Crashes:     d(prev.Crashes, cur.Crashes),
+		Alerts:      d(prev.Alerts, cur.Alerts),
+		Disconnects: d(prev.Disconnects, cur.Disconnects),
+	}
+}
+
+func add(a, b Counters) Counters {
+	return Counters{
+		RxBytes:     a.RxBytes + b.RxBytes,
+		TxBytes:     a.TxBytes + b.TxBytes,
+		Frames:      a.Frames + b.Frames,
+		FrameErrors: a.FrameErrors + b.FrameErrors,
+		Reboots:     a.Reboots + b.Reboots,
+		Crashes:     a.Crashes + b.Crashes,
+		Alerts:      a.Alerts + b.Alerts,
+		Disconnects: a.Disconnects + b.Disconnects,
+	}
+}
+
+// Text 纯文本的运行报告
+func (s *Summary) Text() string {
+	var b strings.Builder
+	status := "HEALTHY"
+	switch {
+	case !s.Completed:
+		status = "STOPPED EARLY"
+	case !s.Healthy:
+		status = "ISSUES FOUND"
+	}
+	title := s.ID
+	if s.Name != "" {
+		title = s.Name + " (" + s.ID + ")"
+	}
+	fmt.Fprintf(&b, "Soak test %s: %s\n", title, status)
+	fmt.Fprintf(&b, "Started:      %s\n", s.Started.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Ended:        %s (%s of %s planned)\n", s.Ended.Format(time.RFC3339),
+		s.Ended.Sub(s.Started).Round(time.Second), time.Duration(s.Planned)*time.Second)
+	fmt.Fprintf(&b, "Received:     %d bytes (min %.1f / avg %.1f / max %.1f B/s)\n", s.Totals.RxBytes, s.RxRateMin, s.RxRateAvg, s.RxRateMax)
+	fmt.Fprintf(&b, "Sent:         %d bytes\n", s.Totals.TxBytes)
+	fmt.Fprintf(&b, "Frames:       %d (%d errors)\n", s.Totals.Frames, s.Totals.FrameErrors)
+	fmt.Fprintf(&b, "Reboots:      %d\n", s.Totals.Reboots)
+	fmt.Fprintf(&b, "Crashes:      %d\n", s.Totals.Crashes)
+	fmt.Fprintf(&b, "Alarms:       %d\n", s.Totals.Alerts)
+	fmt.Fprintf(&b, "Disconnects:  %d (%s offline)\n", s.Totals.Disconnects, (time.Duration(s.OfflineMs) * time.Millisecond).Round(time.Second))
+	fmt.Fprintf(&b, "Stalls:       %d intervals without data\n", s.Stalls)
+
+	b.WriteString("\nTime      RX B/s     TX B/s   Frames  Errors  Reboots  Crashes  Alarms\n")
+	for _, sn := range s.Snapshots {
+		mark := ""
+		if !sn.Connected {
+			mark = "  offline"
+		}
+		fmt.Fprintf(&b, "%-8s %7.1f %10.1f %8d %7d %8d %8d %7d%s\n",
+			(time.Duration(sn.Elapsed) * time.Second).String(), sn.RxRate, sn.TxRate,
+			sn.Delta.Frames, sn.Delta.FrameErrors, sn.Delta.Reboots, sn.Delta.Crashes, sn.Delta.Alerts, mark)
+	}
+	return b.String()
+}
+
+// Save 将汇总保存为 <ID>.json 与纯文本报告 <ID>.txt
+func Save(dir string, s Summary) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, s.ID+".json"), data, 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, s.ID+".txt"), []byte(s.Text()), 0o644)
+}
+
+// ErrNotFound 报告不存在
+var ErrNotFound = errors.New("soak: no such report")
+
+// Load 读取一次运行的汇总
+func Load(dir, id string) (Summary, error) {
+	var s Summary
+	if id == "" || filepath.Base(id) != id {
+		return s, ErrNotFound
+	}
+	data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return s, ErrNotFound
+	}
+	if err != nil {
+		return s, err
+	}
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// List 列出保存的运行汇总 (不含快照)，从新到旧排序；目录不存在时返回空列表
+func List(dir string) ([]Summary, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Summary{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	list := []Summary{}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		s, err := Load(dir, strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		s.Snapshots = nil
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Started.After(list[j].Started)
+	})
+	return list, nil
+}
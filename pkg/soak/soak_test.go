@@ -0,0 +1,83 @@
+package soak
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSource 每次采样接收 100 字节，第 3 次采样时计数器被清零 (模拟重新连接) 并记一次重启
+type fakeSource struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (f *fakeSource) sample() Sample {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.n++
+	s := Sample{Connected: true}
+	s.RxBytes = uint64(f.n * 100)
+	if f.n >= 3 {
+		s.RxBytes = uint64((f.n - 2) * 100)
+		s.Reboots = 1
+	}
+	return s
+}
+
+func TestRunCompletes(t *testing.T) {
+	src := &fakeSource{}
+	var snaps []Snapshot
+	s := Run(context.Background(), "overnight", 130*time.Millisecond, 20*time.Millisecond, src.sample, func(sn Snapshot) {
+		snaps = append(snaps, sn)
+	})
+	if !s.Completed || len(snaps) == 0 || len(s.Snapshots) != len(snaps) {
+		t.Fatalf("Unexpected summary %+v", s)
+	}
+	if s.Totals.Reboots != 1 || s.Healthy {
+		t.Errorf("Expected one reboot and an unhealthy run, got %+v", s.Totals)
+	}
+	if want := uint64(len(snaps)) * 100; s.Totals.RxBytes != want {
+		t.Errorf("Counter resets should count from zero: got %d bytes, want %d", s.Totals.RxBytes, want)
+	}
+	if s.RxRateMax <= 0 || s.RxRateMin > s.RxRateAvg || s.RxRateAvg > s.RxRateMax {
+		t.Errorf("Inconsistent rates %+v", s)
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	s := Run(ctx, "", time.Hour, 10*time.Millisecond, func() Sample { return Sample{} }, nil)
+	if s.Completed || s.Healthy {
+		t.Errorf("Canceled run should not be complete: %+v", s)
+	}
+	if s.OfflineMs == 0 || s.Stalls != 0 {
+		t.Errorf("Disconnected intervals should count as offline, not stalls: %+v", s)
+	}
+}
+
+func TestSaveLoadList(t *testing.T) {
+	dir := t.TempDir()
+	s := Summary{ID: "20261015-080000", Name: "rack 3", Started: time.Now(), Ended: time.Now(), Completed: true, Healthy: true,
+		Snapshots: []Snapshot{{Elapsed: 60, RxRate: 10, Connected: true}}}
+	if err := Save(dir, s); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(dir, s.ID)
+	if err != nil || got.Name != "rack 3" || len(got.Snapshots) != 1 {
+		t.Fatalf("Unexpected loaded summary %+v, %v", got, err)
+	}
+	list, err := List(dir)
+	if err != nil || len(list) != 1 || list[0].Snapshots != nil {
+		t.Errorf("Unexpected list %+v, %v", list, err)
+	}
+	if _, err := Load(dir, "../x"); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if text := s.Text(); !strings.Contains(text, "rack 3 (20261015-080000): HEALTHY") {
+		t.Errorf("Unexpected report:\n%s", text)
+	}
+}
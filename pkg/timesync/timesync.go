@@ -0,0 +1,124 @@
+package timesync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MinInterval 定时同步的最小间隔
+const MinInterval = 10 * time.Second
+
+// Preset 常用的时间下发格式，模板使用发送内容的占位符 (如 {{time.hour}})
+type Preset struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Template    string `json:"template"`
+	Hex         bool   `json:"hex"`
+}
+
+// Presets 内置格式
+var Presets = []Preset{
+	{
+		Name:        "at-cclk",
+		Description: "3GPP AT+CCLK (cellular modules)",
+		Template:    `AT+CCLK="{{time.yy}}/{{time.month}}/{{time.day}},{{time.hour}}:{{time.minute}}:{{time.second}}{{time.tzq}}"` + "\r\n",
+	},
+	{
+		Name:        "scpi",
+		Description: "SCPI :SYSTem:DATE / :SYSTem:TIME",
+		Template:    ":SYST:DATE {{time.year}},{{time.month}},{{time.day}};:SYST:TIME {{time.hour}},{{time.minute}},{{time.second}}\n",
+	},
+	{
+		Name:        "iso8601",
+		Description: "Text command with an RFC 3339 timestamp",
+		Template:    "time {{time.iso}}\r\n",
+	},
+	{
+		Name:        "unix",
+		Description: "Text command with Unix seconds (UTC)",
+		Template:    "date -s @{{time.unix}}\n",
+	},
+	{
+		Name:        "binary-unix",
+		Description: "Binary frame: A5 5A, 4-byte Unix seconds (big endian), CRC-16",
+		Template:    "A5 5A {{time.unix}} {{crc16}}",
+		Hex:         true,
+	},
+}
+
+// Config 时间同步设置
+type Config struct {
+	OnConnect   bool   `json:"onConnect"`   // 连接建立后立即发送
+	IntervalSec int    `json:"intervalSec"` // 定时发送间隔，0 表示不定时发送
+	Preset      string `json:"preset"`      // 内置格式名，为空时使用 Template
+	Template    string `json:"template,omitempty"`
+	Hex         bool   `json:"hex,omitempty"`
+}
+
+var (
+	ErrUnknownPreset = errors.New("timesync: unknown preset")
+	ErrNoTemplate    = errors.New("timesync: no preset or template")
+)
+
+// Payload 返回要展开发送的模板及其是否为十六进制
+func (c Config) Payload() (string, bool, error) {
+	if c.Preset == "" {
+		if c.Template == "" {
+			return "", false, ErrNoTemplate
+		}
+		return c.Template, c.Hex, nil
+	}
+	for _, p := range Presets {
+		if p.Name == c.Preset {
+			return p.Template, p.Hex, nil
+		}
+	}
+	return "", false, fmt.Errorf("%w: %q", ErrUnknownPreset, c.Preset)
+}
+
+// Interval 返回定时发送间隔，0 表示不定时发送
+func (c Config) Interval() time.Duration {
+	return time.Duration(c.IntervalSec) * time.Second
+}
+
+// Validate 检查设置 (模板中的占位符由调用方展开时检查)
+func (c Config) Validate() error {
+	if c.IntervalSec < 0 || (c.IntervalSec > 0 && c.Interval() < MinInterval) {
+		return fmt.Errorf("timesync: interval must be 0 or at least %v", MinInterval)
+	}
+	if !c.OnConnect && c.IntervalSec == 0 {
+		return nil
+	}
+	_, _, err := c.Payload()
+	return err
+}
+
+// Load 读取设置，文件不存在时返回零值 (不自动发送)
+func Load(path string) (Config, error) {
+	var c Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+// Save 保存设置
+func Save(path string, c Config) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
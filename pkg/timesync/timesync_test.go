@@ -0,0 +1,62 @@
+package timesync
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/payload"
+)
+
+func TestPresetsExpand(t *testing.T) {
+	e := payload.New()
+	for _, p := range Presets {
+		if _, err := e.Preview(p.Template, p.Hex, payload.FrameSpec{}); err != nil {
+			t.Errorf("Preset %s does not expand: %v", p.Name, err)
+		}
+	}
+}
+
+func TestPayload(t *testing.T) {
+	tmpl, hex, err := Config{Preset: "binary-unix"}.Payload()
+	if err != nil || !hex || tmpl == "" {
+		t.Errorf("Unexpected preset payload %q %v %v", tmpl, hex, err)
+	}
+	tmpl, _, err = Config{Template: "T{{time.unix}}\n"}.Payload()
+	if err != nil || tmpl != "T{{time.unix}}\n" {
+		t.Errorf("Unexpected custom payload %q %v", tmpl, err)
+	}
+	if _, _, err := (Config{Preset: "nope"}).Payload(); !errors.Is(err, ErrUnknownPreset) {
+		t.Errorf("Expected ErrUnknownPreset, got %v", err)
+	}
+	if _, _, err := (Config{}).Payload(); err != ErrNoTemplate {
+		t.Errorf("Expected ErrNoTemplate, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (Config{}).Validate(); err != nil {
+		t.Errorf("Disabled config should be valid: %v", err)
+	}
+	if err := (Config{OnConnect: true}).Validate(); err == nil {
+		t.Error("Enabled config without a template should be invalid")
+	}
+	if err := (Config{IntervalSec: 1, Preset: "scpi"}).Validate(); err == nil {
+		t.Error("Too short interval should be invalid")
+	}
+	if c := (Config{IntervalSec: 60}); c.Interval() != time.Minute {
+		t.Errorf("Unexpected interval %v", c.Interval())
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timesync.json")
+	if c, err := Load(path); err != nil || c.OnConnect {
+		t.Fatalf("Missing file should load the zero config, got %+v %v", c, err)
+	}
+	Save(path, Config{OnConnect: true, Preset: "at-cclk"})
+	if c, _ := Load(path); !c.OnConnect || c.Preset != "at-cclk" {
+		t.Errorf("Config not saved: %+v", c)
+	}
+}
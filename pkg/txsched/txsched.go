@@ -0,0 +1,82 @@
+package txsched
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// spinWindow 距离计划时间小于该值时改为忙等，弥补定时器约 1ms 的唤醒误差
+const spinWindow = 2 * time.Millisecond
+
+// Entry 一帧计划发送的数据，At 为相对开始时刻的偏移
+type Entry struct {
+	At   time.Duration
+	Data []byte
+}
+
+// Result 一帧的实际发送情况
+type Result struct {
+	Index     int    `json:"index"`
+	PlannedUs int64  `json:"plannedUs"` // 计划偏移 (微秒)
+	ActualUs  int64  `json:"actualUs"`  // 实际开始写入的偏移 (微秒)
+	LateUs    int64  `json:"lateUs"`    // 实际晚于计划的时间 (微秒)
+	Error     string `json:"error,omitempty"`
+}
+
+// ErrUnordered 计划时间没有按先后排列
+var ErrUnordered = errors.New("txsched: entries must be in chronological order")
+
+// Run 按计划时间依次调用 send，时间基于单调时钟，不受系统时间调整影响
+// 某一帧发送失败不会中止后续的帧；ctx 结束时返回已完成部分的结果与 ctx 的错误
+func Run(ctx context.Context, entries []Entry, send func([]byte) error, report func(Result)) ([]Result, error) {
+	for i := 1; i < len(entries); i++ {
+		if entries[i].At < entries[i-1].At {
+			return nil, fmt.Errorf("%w: entry %d", ErrUnordered, i)
+		}
+	}
+	results := make([]Result, 0, len(entries))
+	start := time.Now()
+	for i, e := range entries {
+		if err := SleepUntil(ctx, start.Add(e.At)); err != nil {
+			return results, err
+		}
+		actual := time.Since(start)
+		r := Result{
+			Index:     i,
+			PlannedUs: e.At.Microseconds(),
+			ActualUs:  actual.Microseconds(),
+			LateUs:    (actual - e.At).Microseconds(),
+		}
+		if err := send(e.Data); err != nil {
+			r.Error = err.Error()
+		}
+		results = append(results, r)
+		if report != nil {
+			report(r)
+		}
+	}
+	return results, nil
+}
+
+// SleepUntil 等待到 deadline：先用定时器睡到 deadline 前 spinWindow，再让出调度忙等到点
+func SleepUntil(ctx context.Context, deadline time.Time) error {
+	if d := time.Until(deadline) - spinWindow; d > 0 {
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	for time.Now().Before(deadline) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		runtime.Gosched()
+	}
+	return nil
+}
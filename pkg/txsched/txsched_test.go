@@ -0,0 +1,59 @@
+package txsched
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunKeepsSchedule(t *testing.T) {
+	entries := []Entry{
+		{At: 0, Data: []byte("a")},
+		{At: 5 * time.Millisecond, Data: []byte("b")},
+		{At: 12 * time.Millisecond, Data: []byte("c")},
+	}
+	var sent []string
+	var reported int
+	start := time.Now()
+	results, err := Run(context.Background(), entries, func(b []byte) error {
+		sent = append(sent, string(b))
+		if string(b) == "b" {
+			return errors.New("busy")
+		}
+		return nil
+	}, func(Result) { reported++ })
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 12*time.Millisecond {
+		t.Errorf("Schedule finished too early: %v", elapsed)
+	}
+	if len(sent) != 3 || reported != 3 || len(results) != 3 {
+		t.Fatalf("Expected 3 frames sent and reported, got %v / %d", sent, reported)
+	}
+	for _, r := range results {
+		if r.LateUs < 0 {
+			t.Errorf("Frame %d sent before its planned time: %+v", r.Index, r)
+		}
+	}
+	if results[1].Error != "busy" || results[2].Error != "" {
+		t.Errorf("Send errors should be recorded per frame: %+v", results)
+	}
+}
+
+func TestRunRejectsUnordered(t *testing.T) {
+	_, err := Run(context.Background(), []Entry{{At: time.Second}, {At: 0}}, func([]byte) error { return nil }, nil)
+	if !errors.Is(err, ErrUnordered) {
+		t.Errorf("Expected ErrUnordered, got %v", err)
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	results, err := Run(ctx, []Entry{{At: 0}, {At: time.Hour}}, func([]byte) error { return nil }, nil)
+	if !errors.Is(err, context.DeadlineExceeded) || len(results) != 1 {
+		t.Errorf("Expected one result and a deadline error, got %d, %v", len(results), err)
+	}
+}
@@ -0,0 +1,185 @@
+package updater
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Update channels
+const (
+	ChannelStable = "stable" // latest non pre-release
+	ChannelBeta   = "beta"   // newest release including pre-releases
+)
+
+// SignatureExt is appended to an asset name to find its detached signature asset.
+// The signature is the base64 encoded ed25519 signature of the asset file.
+const SignatureExt = ".sig"
+
+// maxSignatureSize limits the signature download
+const maxSignatureSize = 4 << 10
+
+// SigningKey is the base64 encoded ed25519 public key release assets are signed with.
+// Release builds set it with -ldflags "-X serial-assistant/pkg/updater.SigningKey=...";
+// when empty (development builds) downloaded updates are never installed.
+var SigningKey string
+
+var (
+	// ErrUnknownChannel is returned for channels other than stable and beta
+	ErrUnknownChannel = errors.New("unknown update channel")
+	// ErrNoSigningKey is returned by builds without a SigningKey, which cannot verify updates
+	ErrNoSigningKey = errors.New("no update signing key configured")
+	// ErrUnsigned is returned when the release has no signature
+	ErrUnsigned = errors.New("update is not signed")
+	// ErrBadSignature is returned when the downloaded file does not match its signature
+	ErrBadSignature = errors.New("update signature verification failed")
+)
+
+// ValidChannel reports whether channel is a known update channel
+func ValidChannel(channel string) bool {
+	return channel == ChannelStable || channel == ChannelBeta
+}
+
+// CheckChannelContext checks for updates on the given channel
+func CheckChannelContext(ctx context.Context, currentVersion, channel string) (*UpdateInfo, error) {
+	var release Release
+	switch channel {
+	case ChannelStable:
+		if err := fetchJSON(ctx, fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", GitHubRepo), &release); err != nil {
+			return nil, err
+		}
+	case ChannelBeta:
+		var releases []Release
+		if err := fetchJSON(ctx, fmt.Sprintf("https://api.github.com/repos/%s/releases?per_page=20", GitHubRepo), &releases); err != nil {
+			return nil, err
+		}
+		r, ok := newestRelease(releases)
+		if !ok {
+			return nil, fmt.Errorf("no releases found")
+		}
+		release = r
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownChannel, channel)
+	}
+	return infoFor(release, currentVersion, channel)
+}
+
+// newestRelease returns the highest versioned release that is not a draft
+func newestRelease(releases []Release) (Release, bool) {
+	var best Release
+	found := false
+	for _, r := range releases {
+		if r.Draft {
+			continue
+		}
+		if !found || compareVersions(r.TagName, best.TagName) > 0 {
+			best, found = r, true
+		}
+	}
+	return best, found
+}
+
+// signingKey decodes SigningKey, returning ErrNoSigningKey when none is configured
+func signingKey() (ed25519.PublicKey, error) {
+	if SigningKey == "" {
+		return nil, ErrNoSigningKey
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(SigningKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid signing key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// VerifyFile checks path against a base64 encoded ed25519 signature using key
+func VerifyFile(path string, signature []byte, key ed25519.PublicKey) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return ErrBadSignature
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(key, data, sig) {
+		return ErrBadSignature
+	}
+	return nil
+}
+
+// VerifyDownloadContext verifies a downloaded update against the signature at signatureURL.
+// It fails closed: a missing SigningKey or a missing signature is an error.
+func VerifyDownloadContext(ctx context.Context, path, signatureURL string) error {
+	key, err := signingKey()
+	if err != nil {
+		return err
+	}
+	if signatureURL == "" {
+		return ErrUnsigned
+	}
+	client := &http.Client{Timeout: CheckTimeout}
+	req, err := http.NewRequestWithContext(ctx, "GET", signatureURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create signature request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download signature: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrUnsigned
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("signature download failed with status: %d", resp.StatusCode)
+	}
+	sig, err := io.ReadAll(io.LimitReader(resp.Body, maxSignatureSize))
+	if err != nil {
+		return fmt.Errorf("failed to read signature: %w", err)
+	}
+	return VerifyFile(path, sig, key)
+}
+
+// Settings are the persisted updater preferences
+type Settings struct {
+	Channel string `json:"channel"`
+}
+
+// LoadSettings reads settings from path, defaulting to the stable channel
+func LoadSettings(path string) (Settings, error) {
+	s := Settings{Channel: ChannelStable}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Settings{Channel: ChannelStable}, err
+	}
+	if !ValidChannel(s.Channel) {
+		s.Channel = ChannelStable
+	}
+	return s, nil
+}
+
+// SaveSettings writes settings to path
+func SaveSettings(path string, s Settings) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
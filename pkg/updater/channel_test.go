@@ -0,0 +1,126 @@
+package updater
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewestRelease(t *testing.T) {
+	releases := []Release{
+		{TagName: "v1.4.0", Draft: true},
+		{TagName: "v1.3.0-beta.2", Prerelease: true},
+		{TagName: "v1.2.9"},
+		{TagName: "v1.3.0-beta.1", Prerelease: true},
+	}
+	r, ok := newestRelease(releases)
+	if !ok || r.TagName != "v1.3.0-beta.2" {
+		t.Errorf("Expected v1.3.0-beta.2, got %q", r.TagName)
+	}
+	if _, ok := newestRelease(nil); ok {
+		t.Error("Empty list should have no newest release")
+	}
+}
+
+func TestInfoForSignature(t *testing.T) {
+	var r Release
+	r.TagName = "v9.0.0"
+	r.Assets = append(r.Assets,
+		struct {
+			Name               string `json:"name"`
+			BrowserDownloadURL string `json:"browser_download_url"`
+			Size               int64  `json:"size"`
+		}{getAssetName(), "https://example.com/bin", 10},
+		struct {
+			Name               string `json:"name"`
+			BrowserDownloadURL string `json:"browser_download_url"`
+			Size               int64  `json:"size"`
+		}{getAssetName() + SignatureExt, "https://example.com/bin.sig", 88},
+	)
+	info, err := infoFor(r, "v1.0.0", ChannelBeta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Available || info.DownloadURL != "https://example.com/bin" || info.SignatureURL != "https://example.com/bin.sig" || info.Channel != ChannelBeta {
+		t.Errorf("Unexpected info %+v", info)
+	}
+}
+
+func signedFile(t *testing.T) (string, ed25519.PrivateKey, ed25519.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "update")
+	os.WriteFile(path, []byte("new binary"), 0o644)
+	return path, priv, pub
+}
+
+func TestVerifyFile(t *testing.T) {
+	path, priv, pub := signedFile(t)
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte("new binary")))
+	if err := VerifyFile(path, []byte(sig+"\n"), pub); err != nil {
+		t.Errorf("Valid signature rejected: %v", err)
+	}
+	bad := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte("old binary")))
+	if err := VerifyFile(path, []byte(bad), pub); err != ErrBadSignature {
+		t.Errorf("Expected ErrBadSignature, got %v", err)
+	}
+	if err := VerifyFile(path, []byte("garbage"), pub); err != ErrBadSignature {
+		t.Errorf("Expected ErrBadSignature, got %v", err)
+	}
+}
+
+func TestVerifyDownload(t *testing.T) {
+	path, priv, pub := signedFile(t)
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte("new binary")))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bin.sig" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(sig))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	old := SigningKey
+	defer func() { SigningKey = old }()
+
+	SigningKey = ""
+	if err := VerifyDownloadContext(ctx, path, srv.URL+"/bin.sig"); !errors.Is(err, ErrNoSigningKey) {
+		t.Errorf("Without a signing key verification should fail, got %v", err)
+	}
+
+	SigningKey = base64.StdEncoding.EncodeToString(pub)
+	if err := VerifyDownloadContext(ctx, path, srv.URL+"/bin.sig"); err != nil {
+		t.Errorf("Valid signature rejected: %v", err)
+	}
+	if err := VerifyDownloadContext(ctx, path, ""); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("Expected ErrUnsigned, got %v", err)
+	}
+	if err := VerifyDownloadContext(ctx, path, srv.URL+"/missing.sig"); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("Expected ErrUnsigned for a missing signature, got %v", err)
+	}
+}
+
+func TestSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "update.json")
+	if s, err := LoadSettings(path); err != nil || s.Channel != ChannelStable {
+		t.Errorf("Unexpected default %+v, %v", s, err)
+	}
+	if err := SaveSettings(path, Settings{Channel: ChannelBeta}); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := LoadSettings(path); s.Channel != ChannelBeta {
+		t.Errorf("Unexpected settings %+v", s)
+	}
+}
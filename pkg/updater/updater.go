@@ -1,6 +1,7 @@
 package updater
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -32,6 +33,8 @@ type Release struct {
 		Size               int64  `json:"size"`
 	} `json:"assets"`
 	PublishedAt time.Time `json:"published_at"`
+	Prerelease  bool      `json:"prerelease"`
+	Draft       bool      `json:"draft"`
 }
 
 // UpdateInfo contains information about an available update
@@ -42,16 +45,27 @@ type UpdateInfo struct {
 	ReleaseNotes   string `json:"releaseNotes"`
 	DownloadURL    string `json:"downloadUrl"`
 	AssetSize      int64  `json:"assetSize"`
+	SignatureURL   string `json:"signatureUrl,omitempty"` // detached ed25519 signature of the asset
+	Channel        string `json:"channel"`
+	Prerelease     bool   `json:"prerelease"`
 }
 
 // CheckForUpdates checks if a new version is available on GitHub
 func CheckForUpdates(currentVersion string) (*UpdateInfo, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", GitHubRepo)
+	return CheckForUpdatesContext(context.Background(), currentVersion)
+}
+
+// CheckForUpdatesContext is like CheckForUpdates but can be canceled through ctx
+func CheckForUpdatesContext(ctx context.Context, currentVersion string) (*UpdateInfo, error) {
+	return CheckChannelContext(ctx, currentVersion, ChannelStable)
+}
 
+// fetchJSON GETs a GitHub API url and decodes the JSON response into v
+func fetchJSON(ctx context.Context, url string, v any) error {
 	client := &http.Client{Timeout: CheckTimeout}
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set user agent to avoid rate limiting
@@ -59,36 +73,43 @@ func CheckForUpdates(currentVersion string) (*UpdateInfo, error) {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+		return fmt.Errorf("failed to fetch releases: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	var release Release
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-		return nil, fmt.Errorf("failed to decode release: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode release: %w", err)
 	}
+	return nil
+}
 
+// infoFor describes release relative to currentVersion
+func infoFor(release Release, currentVersion, channel string) (*UpdateInfo, error) {
 	info := &UpdateInfo{
 		CurrentVersion: currentVersion,
 		LatestVersion:  release.TagName,
 		ReleaseNotes:   release.Body,
+		Channel:        channel,
+		Prerelease:     release.Prerelease,
 	}
 
-	// Compare versions (simple string comparison, assuming semver format v1.2.3)
+	// Compare versions (semver format v1.2.3, pre-releases like v1.3.0-beta.1 sort before v1.3.0)
 	if compareVersions(release.TagName, currentVersion) > 0 {
 		info.Available = true
 
-		// Find the appropriate asset for the current platform
+		// Find the appropriate asset and its signature for the current platform
 		assetName := getAssetName()
 		for _, asset := range release.Assets {
-			if asset.Name == assetName {
+			switch asset.Name {
+			case assetName:
 				info.DownloadURL = asset.BrowserDownloadURL
 				info.AssetSize = asset.Size
-				break
+			case assetName + SignatureExt:
+				info.SignatureURL = asset.BrowserDownloadURL
 			}
 		}
 
@@ -102,8 +123,13 @@ func CheckForUpdates(currentVersion string) (*UpdateInfo, error) {
 
 // DownloadUpdate downloads the update file
 func DownloadUpdate(downloadURL string, progressCallback func(downloaded, total int64)) (string, error) {
+	return DownloadUpdateContext(context.Background(), downloadURL, progressCallback)
+}
+
+// DownloadUpdateContext is like DownloadUpdate but can be canceled through ctx
+func DownloadUpdateContext(ctx context.Context, downloadURL string, progressCallback func(downloaded, total int64)) (string, error) {
 	client := &http.Client{Timeout: 5 * time.Minute}
-	req, err := http.NewRequest("GET", downloadURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", downloadURL, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create download request: %w", err)
 	}
@@ -247,7 +273,8 @@ func getAssetName() string {
 	}
 }
 
-// compareVersions compares two version strings (v1.2.3 format)
+// compareVersions compares two version strings (v1.2.3 format, optionally with a
+// pre-release suffix such as v1.3.0-beta.2)
 // Returns: -1 if v1 < v2, 0 if v1 == v2, 1 if v1 > v2
 // Note: Invalid version parts are treated as 0 for comparison purposes
 func compareVersions(v1, v2 string) int {
@@ -255,9 +282,28 @@ func compareVersions(v1, v2 string) int {
 	v1 = strings.TrimPrefix(v1, "v")
 	v2 = strings.TrimPrefix(v2, "v")
 
-	parts1 := strings.Split(v1, ".")
-	parts2 := strings.Split(v2, ".")
+	// Split off the pre-release part
+	v1, pre1, _ := strings.Cut(v1, "-")
+	v2, pre2, _ := strings.Cut(v2, "-")
+
+	if c := compareParts(strings.Split(v1, "."), strings.Split(v2, ".")); c != 0 {
+		return c
+	}
+
+	// A release is newer than any of its pre-releases
+	switch {
+	case pre1 == pre2:
+		return 0
+	case pre1 == "":
+		return 1
+	case pre2 == "":
+		return -1
+	}
+	return compareParts(strings.Split(pre1, "."), strings.Split(pre2, "."))
+}
 
+// compareParts compares dot-separated version parts, numerically when both parts are numbers
+func compareParts(parts1, parts2 []string) int {
 	maxLen := len(parts1)
 	if len(parts2) > maxLen {
 		maxLen = len(parts2)
@@ -273,10 +319,17 @@ func compareVersions(v1, v2 string) int {
 
 	for i := 0; i < maxLen; i++ {
 		n1, err1 := strconv.Atoi(parts1[i])
+		n2, err2 := strconv.Atoi(parts2[i])
+		if err1 != nil && err2 != nil {
+			// Both are identifiers such as "beta" or "rc"
+			if c := strings.Compare(parts1[i], parts2[i]); c != 0 {
+				return c
+			}
+			continue
+		}
 		if err1 != nil {
 			n1 = 0 // Treat invalid parts as 0
 		}
-		n2, err2 := strconv.Atoi(parts2[i])
 		if err2 != nil {
 			n2 = 0 // Treat invalid parts as 0
 		}
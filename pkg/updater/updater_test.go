@@ -21,6 +21,11 @@ func TestCompareVersions(t *testing.T) {
 		{"1.2.3", "1.2.4", -1},
 		{"v1.2", "v1.2.0", 0},
 		{"v1.2.3.4", "v1.2.3.5", -1},
+		{"v1.3.0-beta.1", "v1.3.0", -1},
+		{"v1.3.0", "v1.3.0-rc.1", 1},
+		{"v1.3.0-beta.2", "v1.3.0-beta.10", -1},
+		{"v1.3.0-rc.1", "v1.3.0-beta.3", 1},
+		{"v1.3.0-beta.1", "v1.2.9", 1},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,192 @@
+// Package usage 本地使用统计：打开的会话、使用的功能及错误次数
+// 统计只保存在本机，不会自动发送，用户可导出后附在问题报告中
+package usage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// saveDelay 记录后延迟保存的时间，合并短时间内的多次记录
+const saveDelay = 30 * time.Second
+
+// Stats 累计的使用统计
+type Stats struct {
+	Disabled bool           `json:"disabled"` // 用户关闭了统计
+	Since    time.Time      `json:"since"`    // 开始统计 (或上次清空) 的时间
+	Launches int            `json:"launches"`
+	Sessions map[string]int `json:"sessions"` // 按连接类型统计的打开次数
+	Features map[string]int `json:"features"` // 按功能统计的使用次数
+	Errors   map[string]int `json:"errors"`   // 按错误码统计的次数
+}
+
+// Recorder 统计记录器，方法对 nil 安全
+type Recorder struct {
+	path string
+
+	mu      sync.Mutex
+	stats   Stats
+	pending *time.Timer
+}
+
+// Open 读取 path 处已有的统计，文件不存在时从零开始
+func Open(path string) (*Recorder, error) {
+	r := &Recorder{path: path}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, &r.stats); err != nil {
+			return nil, err
+		}
+	}
+	r.init(time.Now())
+	return r, nil
+}
+
+// init 补全空的统计字段
+func (r *Recorder) init(now time.Time) {
+	if r.stats.Since.IsZero() {
+		r.stats.Since = now
+	}
+	if r.stats.Sessions == nil {
+		r.stats.Sessions = map[string]int{}
+	}
+	if r.stats.Features == nil {
+		r.stats.Features = map[string]int{}
+	}
+	if r.stats.Errors == nil {
+		r.stats.Errors = map[string]int{}
+	}
+}
+
+// Launch 记录一次启动
+func (r *Recorder) Launch() {
+	r.record(func(s *Stats) { s.Launches++ })
+}
+
+// Session 记录打开一次 kind 类型的连接
+func (r *Recorder) Session(kind string) {
+	r.record(func(s *Stats) { s.Sessions[kind]++ })
+}
+
+// Feature 记录使用一次功能
+func (r *Recorder) Feature(name string) {
+	r.record(func(s *Stats) { s.Features[name]++ })
+}
+
+// Error 记录一次错误
+func (r *Recorder) Error(code string) {
+	r.record(func(s *Stats) { s.Errors[code]++ })
+}
+
+// record 更新统计并安排延迟保存，关闭统计时忽略
+func (r *Recorder) record(fn func(*Stats)) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stats.Disabled {
+		return
+	}
+	fn(&r.stats)
+	if r.pending == nil {
+		r.pending = time.AfterFunc(saveDelay, func() { r.Flush() })
+	}
+}
+
+// Snapshot 返回统计的副本
+func (r *Recorder) Snapshot() Stats {
+	if r == nil {
+		return Stats{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.stats
+	s.Sessions = clone(r.stats.Sessions)
+	s.Features = clone(r.stats.Features)
+	s.Errors = clone(r.stats.Errors)
+	return s
+}
+
+// SetEnabled 开启或关闭统计，关闭时清空已有数据
+func (r *Recorder) SetEnabled(enabled bool) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	if enabled == !r.stats.Disabled {
+		r.mu.Unlock()
+		return nil
+	}
+	r.stats = Stats{Disabled: !enabled}
+	r.init(time.Now())
+	r.mu.Unlock()
+	return r.Flush()
+}
+
+// Reset 清空统计，保留开关状态
+func (r *Recorder) Reset() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	r.stats = Stats{Disabled: r.stats.Disabled}
+	r.init(time.Now())
+	r.mu.Unlock()
+	return r.Flush()
+}
+
+// Flush 立即保存统计
+func (r *Recorder) Flush() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	if r.pending != nil {
+		r.pending.Stop()
+		r.pending = nil
+	}
+	data, err := json.MarshalIndent(r.stats, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
+
+// Report 导出的统计报告，附带版本与系统信息便于排查问题
+type Report struct {
+	Generated time.Time `json:"generated"`
+	Version   string    `json:"version"`
+	OS        string    `json:"os"`
+	Arch      string    `json:"arch"`
+	Stats
+}
+
+// Export 将报告以缩进 JSON 写入 path
+func Export(path string, rep Report) error {
+	data, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func clone(m map[string]int) map[string]int {
+	c := make(map[string]int, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,90 @@
+package usage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	r, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Launch()
+	r.Session("serial")
+	r.Session("serial")
+	r.Feature("export")
+	r.Error("PORT_BUSY")
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	r2, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := r2.Snapshot()
+	if s.Launches != 1 || s.Sessions["serial"] != 2 || s.Features["export"] != 1 || s.Errors["PORT_BUSY"] != 1 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+	if s.Since.IsZero() {
+		t.Error("Since should be set")
+	}
+}
+
+func TestSnapshotIsCopy(t *testing.T) {
+	r, _ := Open(filepath.Join(t.TempDir(), "usage.json"))
+	r.Feature("a")
+	s := r.Snapshot()
+	s.Features["a"] = 100
+	if r.Snapshot().Features["a"] != 1 {
+		t.Error("Snapshot should not alias recorder state")
+	}
+}
+
+func TestDisable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	r, _ := Open(path)
+	r.Feature("a")
+	if err := r.SetEnabled(false); err != nil {
+		t.Fatal(err)
+	}
+	r.Feature("b")
+	s := r.Snapshot()
+	if !s.Disabled || len(s.Features) != 0 {
+		t.Errorf("Disabling should clear and stop recording, got %+v", s)
+	}
+	r2, _ := Open(path)
+	if !r2.Snapshot().Disabled {
+		t.Error("Disabled state should persist")
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.Launch()
+	r.Error("x")
+	if err := r.Flush(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	r, _ := Open(filepath.Join(t.TempDir(), "usage.json"))
+	r.Session("tcp-client")
+	if err := Export(path, Report{Version: "v1", OS: "linux", Stats: r.Snapshot()}); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["version"] != "v1" || m["sessions"].(map[string]any)["tcp-client"] != float64(1) {
+		t.Errorf("Unexpected export %s", data)
+	}
+}
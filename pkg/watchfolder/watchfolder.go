@@ -0,0 +1,324 @@
+// Package watchfolder 监视目录，新出现 (或被重新写入) 的文件写入完成后自动发送，
+// 用于 "把 firmware.bin 放进目录即刷写" 这类与构建系统配合的流程
+package watchfolder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 轮询间隔、稳定时间及分块大小的范围与默认值
+const (
+	DefaultInterval  = time.Second
+	MinInterval      = 100 * time.Millisecond
+	MaxInterval      = time.Minute
+	DefaultSettle    = time.Second
+	DefaultChunkSize = 1024
+	MaxChunkSize     = 1 << 20
+)
+
+// Protocol 文件的发送方式
+type Protocol string
+
+// ProtocolRaw 原样发送文件内容
+const ProtocolRaw Protocol = "raw"
+
+// AfterSend 发送成功后如何处理文件
+type AfterSend string
+
+const (
+	AfterKeep   AfterSend = "keep"   // 保留，文件再次改变时重新发送
+	AfterMove   AfterSend = "move"   // 移入目录下的 SentDir
+	AfterDelete AfterSend = "delete" // 删除
+)
+
+// SentDir AfterMove 时已发送文件移入的子目录
+const SentDir = "sent"
+
+// Config 监视目录配置
+type Config struct {
+	Enabled      bool      `json:"enabled"`
+	Dir          string    `json:"dir"`
+	Pattern      string    `json:"pattern"`      // 文件名通配符，如 "*.bin"，为空时匹配全部文件
+	Protocol     Protocol  `json:"protocol"`     // 为空时为 ProtocolRaw
+	ChunkSize    int       `json:"chunkSize"`    // 每次写入的字节数，0 为 DefaultChunkSize
+	ChunkDelayMs int       `json:"chunkDelayMs"` // 两块之间的间隔，给设备留出处理时间
+	SettleMs     int       `json:"settleMs"`     // 文件大小与修改时间保持不变多久后视为写入完成，0 为 DefaultSettle
+	IntervalMs   int       `json:"intervalMs"`   // 轮询间隔，0 为 DefaultInterval
+	After        AfterSend `json:"after"`        // 为空时为 AfterKeep
+}
+
+// ErrProtocol 不支持的发送方式
+var ErrProtocol = errors.New("watchfolder: unsupported protocol")
+
+// Validate 检查配置是否有效，启用时目录必须存在
+func (c Config) Validate() error {
+	if c.Pattern != "" {
+		if _, err := filepath.Match(c.Pattern, ""); err != nil {
+			return fmt.Errorf("watchfolder: bad pattern %q: %w", c.Pattern, err)
+		}
+	}
+	switch c.Protocol {
+	case "", ProtocolRaw:
+	default:
+		return fmt.Errorf("%w: %s", ErrProtocol, c.Protocol)
+	}
+	switch c.After {
+	case "", AfterKeep, AfterMove, AfterDelete:
+	default:
+		return fmt.Errorf("watchfolder: unknown after-send action %q", c.After)
+	}
+	if c.ChunkSize < 0 || c.ChunkSize > MaxChunkSize {
+		return fmt.Errorf("watchfolder: chunk size %d out of range 1-%d", c.ChunkSize, MaxChunkSize)
+	}
+	if c.ChunkDelayMs < 0 || c.SettleMs < 0 {
+		return errors.New("watchfolder: negative delay")
+	}
+	if c.IntervalMs != 0 {
+		d := time.Duration(c.IntervalMs) * time.Millisecond
+		if d < MinInterval || d > MaxInterval {
+			return fmt.Errorf("watchfolder: interval %dms out of range %v-%v", c.IntervalMs, MinInterval, MaxInterval)
+		}
+	}
+	if c.Enabled {
+		st, err := os.Stat(c.Dir)
+		if err != nil {
+			return fmt.Errorf("watchfolder: %w", err)
+		}
+		if !st.IsDir() {
+			return fmt.Errorf("watchfolder: %s is not a directory", c.Dir)
+		}
+	}
+	return nil
+}
+
+// Interval 返回轮询间隔
+func (c Config) Interval() time.Duration {
+	if c.IntervalMs <= 0 {
+		return DefaultInterval
+	}
+	return time.Duration(c.IntervalMs) * time.Millisecond
+}
+
+// Settle 返回判定文件写入完成所需的稳定时间
+func (c Config) Settle() time.Duration {
+	if c.SettleMs <= 0 {
+		return DefaultSettle
+	}
+	return time.Duration(c.SettleMs) * time.Millisecond
+}
+
+// Chunk 返回每次写入的字节数
+func (c Config) Chunk() int {
+	if c.ChunkSize <= 0 {
+		return DefaultChunkSize
+	}
+	return c.ChunkSize
+}
+
+// File 目录中的一个文件
+type File struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// List 列出目录中匹配 pattern 的普通文件，忽略隐藏文件及常见的临时文件
+func List(dir, pattern string) ([]File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []File
+	for _, e := range entries {
+		name := e.Name()
+		if !e.Type().IsRegular() || temporary(name) {
+			continue
+		}
+		if pattern != "" {
+			if ok, _ := filepath.Match(pattern, name); !ok {
+				continue
+			}
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue // 列目录后被删除
+		}
+		files = append(files, File{Name: name, Size: info.Size(), ModTime: info.ModTime()})
+	}
+	return files, nil
+}
+
+// temporary 判断是否为编辑器或下载中的临时文件
+func temporary(name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+		return true
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".tmp", ".part", ".crdownload", ".swp":
+		return true
+	}
+	return false
+}
+
+// fileState Tracker 记录的文件状态
+type fileState struct {
+	size    int64
+	modTime time.Time
+	since   time.Time // 最近一次变化被观察到的时刻
+	done    bool      // 当前内容已处理 (已发送或开始监视时已存在)
+}
+
+// Tracker 根据连续几次列目录的结果判断哪些文件是新的且已写入完成
+type Tracker struct {
+	settle time.Duration
+	files  map[string]*fileState
+}
+
+// NewTracker 创建 Tracker，文件大小与修改时间保持 settle 不变后才视为写入完成
+func NewTracker(settle time.Duration) *Tracker {
+	return &Tracker{settle: settle, files: map[string]*fileState{}}
+}
+
+// Prime 记录开始监视时已存在的文件，这些文件只有再次改变才会被发送
+func (t *Tracker) Prime(files []File) {
+	for _, f := range files {
+		t.files[f.Name] = &fileState{size: f.Size, modTime: f.ModTime, done: true}
+	}
+}
+
+// Update 用最新的列目录结果更新状态，返回可以发送的文件名 (按名称排序)
+// 返回的文件视为已处理，之后只有内容再次改变才会重新返回
+func (t *Tracker) Update(now time.Time, files []File) []string {
+	present := make(map[string]bool, len(files))
+	var ready []string
+	for _, f := range files {
+		present[f.Name] = true
+		s, ok := t.files[f.Name]
+		if !ok {
+			t.files[f.Name] = &fileState{size: f.Size, modTime: f.ModTime, since: now}
+			continue
+		}
+		if s.size != f.Size || !s.modTime.Equal(f.ModTime) {
+			*s = fileState{size: f.Size, modTime: f.ModTime, since: now}
+			continue
+		}
+		if !s.done && now.Sub(s.since) >= t.settle {
+			s.done = true
+			ready = append(ready, f.Name)
+		}
+	}
+	for name := range t.files {
+		if !present[name] {
+			delete(t.files, name)
+		}
+	}
+	sort.Strings(ready)
+	return ready
+}
+
+// Send 按配置的发送方式发送目录中的文件，见 SendFile
+func Send(ctx context.Context, c Config, name string, write func([]byte) error, progress func(done, total int64)) error {
+	return SendFile(ctx, filepath.Join(c.Dir, name), c.Chunk(), time.Duration(c.ChunkDelayMs)*time.Millisecond, write, progress)
+}
+
+// SendFile 将文件内容按 chunk 字节分块依次交给 write，两块之间等待 delay，每写入一块通过 progress 报告进度
+// 在 ctx 取消时停止，write 返回的错误原样返回
+func SendFile(ctx context.Context, path string, chunk int, delay time.Duration, write func([]byte) error, progress func(done, total int64)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if chunk <= 0 {
+		chunk = DefaultChunkSize
+	}
+	total := st.Size()
+	buf := make([]byte, chunk)
+	var done int64
+	for {
+		n, err := io.ReadFull(f, buf)
+		if n > 0 {
+			if done > 0 && delay > 0 {
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := write(buf[:n]); err != nil {
+				return err
+			}
+			done += int64(n)
+			progress(done, total)
+		}
+		switch {
+		case err == io.EOF || err == io.ErrUnexpectedEOF:
+			return nil
+		case err != nil:
+			return err
+		}
+	}
+}
+
+// Finish 按配置处理已发送的文件
+func Finish(c Config, name string) error {
+	path := filepath.Join(c.Dir, name)
+	switch c.After {
+	case AfterDelete:
+		return os.Remove(path)
+	case AfterMove:
+		dir := filepath.Join(c.Dir, SentDir)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		dst := filepath.Join(dir, name)
+		if _, err := os.Stat(dst); err == nil {
+			ext := filepath.Ext(name)
+			dst = filepath.Join(dir, strings.TrimSuffix(name, ext)+time.Now().Format("-20060102-150405")+ext)
+		}
+		return os.Rename(path, dst)
+	}
+	return nil
+}
+
+// Load 读取配置，文件不存在时返回默认配置 (关闭)
+func Load(path string) (Config, error) {
+	var c Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+// Save 保存配置
+func Save(path string, c Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
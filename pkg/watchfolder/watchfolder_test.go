@@ -0,0 +1,166 @@
+package watchfolder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	if err := (Config{Enabled: true, Dir: dir, Pattern: "*.bin"}).Validate(); err != nil {
+		t.Fatal(err)
+	}
+	bad := []Config{
+		{Pattern: "["},
+		{Protocol: "xmodem"},
+		{After: "archive"},
+		{ChunkSize: MaxChunkSize + 1},
+		{IntervalMs: 1},
+		{Enabled: true, Dir: filepath.Join(dir, "missing")},
+	}
+	for _, c := range bad {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Expected error for %+v", c)
+		}
+	}
+	if err := (Config{Protocol: "xmodem"}).Validate(); !errors.Is(err, ErrProtocol) {
+		t.Errorf("Expected ErrProtocol, got %v", err)
+	}
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"app.bin", "boot.hex", ".hidden.bin", "app.bin.part", "old.bin~"} {
+		os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644)
+	}
+	os.Mkdir(filepath.Join(dir, "sub.bin"), 0o755)
+	files, err := List(dir, "*.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name != "app.bin" {
+		t.Errorf("Unexpected files %+v", files)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	tr := NewTracker(time.Second)
+	tr.Prime([]File{{Name: "old.bin", Size: 1, ModTime: t0}})
+
+	files := []File{{Name: "old.bin", Size: 1, ModTime: t0}, {Name: "new.bin", Size: 10, ModTime: t0}}
+	if got := tr.Update(t0, files); len(got) != 0 {
+		t.Fatalf("New file should wait to settle, got %v", got)
+	}
+	// 仍在写入
+	files[1].Size = 20
+	if got := tr.Update(t0.Add(900*time.Millisecond), files); len(got) != 0 {
+		t.Fatalf("Growing file should not be ready, got %v", got)
+	}
+	if got := tr.Update(t0.Add(1500*time.Millisecond), files); len(got) != 0 {
+		t.Fatalf("File changed 600ms ago should not be ready, got %v", got)
+	}
+	if got := tr.Update(t0.Add(2*time.Second), files); len(got) != 1 || got[0] != "new.bin" {
+		t.Fatalf("Expected new.bin to be ready, got %v", got)
+	}
+	if got := tr.Update(t0.Add(5*time.Second), files); len(got) != 0 {
+		t.Fatalf("Sent file should not be returned again, got %v", got)
+	}
+	// 构建系统覆盖已存在的文件
+	files[0].ModTime = t0.Add(6 * time.Second)
+	tr.Update(t0.Add(6*time.Second), files)
+	if got := tr.Update(t0.Add(7*time.Second), files); len(got) != 1 || got[0] != "old.bin" {
+		t.Fatalf("Rewritten file should be sent, got %v", got)
+	}
+	// 删除后重新出现
+	tr.Update(t0.Add(8*time.Second), files[:1])
+	tr.Update(t0.Add(9*time.Second), files)
+	if got := tr.Update(t0.Add(10*time.Second), files); len(got) != 1 || got[0] != "new.bin" {
+		t.Fatalf("Re-added file should be sent, got %v", got)
+	}
+}
+
+func TestSend(t *testing.T) {
+	dir := t.TempDir()
+	data := bytes.Repeat([]byte("0123456789"), 25)
+	os.WriteFile(filepath.Join(dir, "fw.bin"), data, 0o644)
+	c := Config{Dir: dir, ChunkSize: 100}
+
+	var out bytes.Buffer
+	var chunks []int
+	var last int64
+	err := Send(context.Background(), c, "fw.bin", func(b []byte) error {
+		chunks = append(chunks, len(b))
+		out.Write(b)
+		return nil
+	}, func(done, total int64) {
+		if total != int64(len(data)) {
+			t.Errorf("total = %d", total)
+		}
+		last = done
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out.Bytes(), data) || len(chunks) != 3 || chunks[2] != 50 || last != 250 {
+		t.Errorf("Unexpected send: chunks %v, last %d", chunks, last)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Send(ctx, c, "fw.bin", func([]byte) error { return nil }, func(int64, int64) {}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestFinish(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string) { os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o644) }
+
+	write("a.bin")
+	if err := Finish(Config{Dir: dir}, "a.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.bin")); err != nil {
+		t.Error("Keep should leave the file")
+	}
+
+	if err := Finish(Config{Dir: dir, After: AfterMove}, "a.bin"); err != nil {
+		t.Fatal(err)
+	}
+	write("a.bin")
+	if err := Finish(Config{Dir: dir, After: AfterMove}, "a.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if sent, _ := os.ReadDir(filepath.Join(dir, SentDir)); len(sent) != 2 {
+		t.Errorf("Expected 2 moved files, got %d", len(sent))
+	}
+
+	write("b.bin")
+	if err := Finish(Config{Dir: dir, After: AfterDelete}, "b.bin"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.bin")); !os.IsNotExist(err) {
+		t.Error("Delete should remove the file")
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg", "watch.json")
+	c, err := Load(path)
+	if err != nil || c.Enabled {
+		t.Fatalf("Load missing = %+v, %v", c, err)
+	}
+	want := Config{Enabled: true, Dir: "/tmp", Pattern: "*.bin", After: AfterMove}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Load(path); err != nil || got != want {
+		t.Errorf("Load = %+v, %v", got, err)
+	}
+}
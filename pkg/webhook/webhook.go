@@ -0,0 +1,173 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"serial-assistant/pkg/rules"
+)
+
+// Timeout 单次请求的超时
+const Timeout = 15 * time.Second
+
+// telegramAPI Telegram Bot API 地址 (测试时替换)
+var telegramAPI = "https://api.telegram.org"
+
+// 默认消息模板，模板中可使用 rules.Match 的字段以及 .Host (本机名)
+const (
+	defaultSlackTemplate    = "*{{.RuleName}}* on {{.Host}}\n```{{.Line}}```"
+	defaultTelegramTemplate = "{{.RuleName}} on {{.Host}}\n{{.Line}}"
+)
+
+// ErrMissingParam 动作缺少必需的参数
+var ErrMissingParam = errors.New("webhook: missing parameter")
+
+// templateData 模板的数据
+type templateData struct {
+	rules.Match
+	Host string `json:"host"`
+}
+
+// hostname 返回本机名，用于在消息中区分不同的测试机
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return name
+}
+
+// render 渲染 params["template"]，为空时使用 fallback
+func render(m rules.Match, params map[string]string, fallback string) (string, error) {
+	text := params["template"]
+	if text == "" {
+		text = fallback
+	}
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("webhook: template: %w", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, templateData{Match: m, Host: hostname()}); err != nil {
+		return "", fmt.Errorf("webhook: template: %w", err)
+	}
+	return b.String(), nil
+}
+
+func require(params map[string]string, names ...string) error {
+	for _, name := range names {
+		if params[name] == "" {
+			return fmt.Errorf("%w: %s", ErrMissingParam, name)
+		}
+	}
+	return nil
+}
+
+// post 发送请求，非 2xx 响应视为失败
+func post(ctx context.Context, client *http.Client, method, url, contentType string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook: %s %s: %s: %s", method, redact(url), resp.Status, strings.TrimSpace(string(msg)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// redact 去掉 URL 中的路径 (Slack/Telegram 的密钥位于路径中)，用于错误信息
+func redact(url string) string {
+	if i := strings.Index(url, "://"); i >= 0 {
+		if j := strings.IndexByte(url[i+3:], '/'); j >= 0 {
+			return url[:i+3+j] + "/…"
+		}
+	}
+	return url
+}
+
+// Generic 通用 Webhook 动作
+// 参数：url (必需)、method (默认 POST)、template (默认为命中信息的 JSON)、contentType
+func Generic(client *http.Client) rules.Handler {
+	return func(ctx context.Context, m rules.Match, a rules.Action) error {
+		if err := require(a.Params, "url"); err != nil {
+			return err
+		}
+		method := a.Params["method"]
+		if method == "" {
+			method = http.MethodPost
+		}
+		contentType := a.Params["contentType"]
+		var body []byte
+		if a.Params["template"] == "" {
+			var err error
+			if body, err = json.Marshal(templateData{Match: m, Host: hostname()}); err != nil {
+				return err
+			}
+			if contentType == "" {
+				contentType = "application/json"
+			}
+		} else {
+			text, err := render(m, a.Params, "")
+			if err != nil {
+				return err
+			}
+			body = []byte(text)
+			if contentType == "" {
+				contentType = "text/plain; charset=utf-8"
+			}
+		}
+		return post(ctx, client, method, a.Params["url"], contentType, body)
+	}
+}
+
+// Slack 通过 Slack Incoming Webhook 发送消息
+// 参数：url (必需)、template
+func Slack(client *http.Client) rules.Handler {
+	return func(ctx context.Context, m rules.Match, a rules.Action) error {
+		if err := require(a.Params, "url"); err != nil {
+			return err
+		}
+		text, err := render(m, a.Params, defaultSlackTemplate)
+		if err != nil {
+			return err
+		}
+		body, _ := json.Marshal(map[string]string{"text": text})
+		return post(ctx, client, http.MethodPost, a.Params["url"], "application/json", body)
+	}
+}
+
+// Telegram 通过 Telegram Bot 发送消息
+// 参数：token、chatId (必需)、template
+func Telegram(client *http.Client) rules.Handler {
+	return func(ctx context.Context, m rules.Match, a rules.Action) error {
+		if err := require(a.Params, "token", "chatId"); err != nil {
+			return err
+		}
+		text, err := render(m, a.Params, defaultTelegramTemplate)
+		if err != nil {
+			return err
+		}
+		body, _ := json.Marshal(map[string]string{"chat_id": a.Params["chatId"], "text": text})
+		url := telegramAPI + "/bot" + a.Params["token"] + "/sendMessage"
+		return post(ctx, client, http.MethodPost, url, "application/json", body)
+	}
+}
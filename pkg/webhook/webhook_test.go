@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/rules"
+)
+
+var match = rules.Match{RuleID: "1", RuleName: "panic", Line: "panic: oops", Time: time.Unix(0, 0)}
+
+// capture 启动记录请求的测试服务器
+func capture(t *testing.T, status int) (*httptest.Server, *[]*http.Request, *[]string) {
+	t.Helper()
+	var reqs []*http.Request
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		reqs = append(reqs, r)
+		bodies = append(bodies, string(b))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &reqs, &bodies
+}
+
+func TestGenericDefaultsToJSON(t *testing.T) {
+	srv, reqs, bodies := capture(t, http.StatusOK)
+	err := Generic(srv.Client())(context.Background(), match, rules.Action{Type: "webhook", Params: map[string]string{"url": srv.URL}})
+	if err != nil {
+		t.Fatalf("Webhook failed: %v", err)
+	}
+	var got map[string]interface{}
+	json.Unmarshal([]byte((*bodies)[0]), &got)
+	if got["line"] != "panic: oops" || got["host"] == "" {
+		t.Errorf("Unexpected body %s", (*bodies)[0])
+	}
+	if ct := (*reqs)[0].Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Unexpected content type %q", ct)
+	}
+}
+
+func TestGenericTemplateAndError(t *testing.T) {
+	srv, _, bodies := capture(t, http.StatusInternalServerError)
+	err := Generic(srv.Client())(context.Background(), match, rules.Action{Params: map[string]string{
+		"url":      srv.URL + "/hook/secret",
+		"template": "{{.RuleName}}: {{.Line}}",
+	}})
+	if err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("Expected a redacted error, got %v", err)
+	}
+	if (*bodies)[0] != "panic: panic: oops" {
+		t.Errorf("Unexpected body %q", (*bodies)[0])
+	}
+}
+
+func TestSlackAndTelegram(t *testing.T) {
+	srv, reqs, bodies := capture(t, http.StatusOK)
+	if err := Slack(srv.Client())(context.Background(), match, rules.Action{Params: map[string]string{"url": srv.URL}}); err != nil {
+		t.Fatal(err)
+	}
+	var slack map[string]string
+	json.Unmarshal([]byte((*bodies)[0]), &slack)
+	if !strings.Contains(slack["text"], "*panic*") {
+		t.Errorf("Unexpected Slack text %q", slack["text"])
+	}
+
+	telegramAPI = srv.URL
+	err := Telegram(srv.Client())(context.Background(), match, rules.Action{Params: map[string]string{"token": "T", "chatId": "42", "template": "{{.Line}}"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if (*reqs)[1].URL.Path != "/botT/sendMessage" {
+		t.Errorf("Unexpected Telegram path %s", (*reqs)[1].URL.Path)
+	}
+	var tg map[string]string
+	json.Unmarshal([]byte((*bodies)[1]), &tg)
+	if tg["chat_id"] != "42" || tg["text"] != "panic: oops" {
+		t.Errorf("Unexpected Telegram body %v", tg)
+	}
+}
+
+func TestMissingParams(t *testing.T) {
+	if err := Telegram(http.DefaultClient)(context.Background(), match, rules.Action{Params: map[string]string{"token": "T"}}); !errors.Is(err, ErrMissingParam) {
+		t.Errorf("Expected ErrMissingParam, got %v", err)
+	}
+}
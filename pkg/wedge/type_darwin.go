@@ -0,0 +1,35 @@
+//go:build darwin
+
+package wedge
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// typeScript 通过 System Events 输入文本，按键码 36 为回车、48 为 Tab
+// 首次使用时需在"辅助功能"中授权
+const typeScript = `on run argv
+	tell application "System Events"
+		keystroke (item 1 of argv)
+		if (item 2 of argv) is not "" then key code ((item 2 of argv) as integer)
+	end tell
+end run`
+
+// Type 模拟键盘输入 text 并按下 suffix 对应的键
+func Type(ctx context.Context, text, suffix string) error {
+	key := ""
+	switch suffix {
+	case SuffixEnter:
+		key = "36"
+	case SuffixTab:
+		key = "48"
+	}
+	out, err := exec.CommandContext(ctx, "osascript", "-e", typeScript, text, key).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("osascript: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+//go:build !windows && !darwin
+
+package wedge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Type 模拟键盘输入 text 并按下 suffix 对应的键
+// Wayland 会话优先使用 wtype，否则使用 xdotool (X11 或 XWayland 窗口)
+func Type(ctx context.Context, text, suffix string) error {
+	// wtype 会把以 "-" 开头的文本当作选项，这种行交给 xdotool
+	if os.Getenv("WAYLAND_DISPLAY") != "" && !strings.HasPrefix(text, "-") {
+		if _, err := exec.LookPath("wtype"); err == nil {
+			args := []string{text}
+			if key := keyName(suffix, "Return", "Tab"); key != "" {
+				args = append(args, "-k", key)
+			}
+			return run(ctx, "wtype", args...)
+		}
+	}
+	if _, err := exec.LookPath("xdotool"); err != nil {
+		return errors.New("wedge: neither xdotool nor wtype is installed")
+	}
+	if err := run(ctx, "xdotool", "type", "--clearmodifiers", "--", text); err != nil {
+		return err
+	}
+	if key := keyName(suffix, "Return", "Tab"); key != "" {
+		return run(ctx, "xdotool", "key", "--clearmodifiers", key)
+	}
+	return nil
+}
+
+// keyName 返回 suffix 对应的按键名，none 返回空
+func keyName(suffix, enter, tab string) string {
+	switch suffix {
+	case SuffixEnter:
+		return enter
+	case SuffixTab:
+		return tab
+	}
+	return ""
+}
+
+func run(ctx context.Context, name string, args ...string) error {
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
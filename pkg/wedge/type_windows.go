@@ -0,0 +1,38 @@
+//go:build windows
+
+package wedge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// typeScript 通过 SendKeys 输入文本；文本经环境变量传入，SendKeys 的特殊字符用花括号转义
+const typeScript = `
+Add-Type -AssemblyName System.Windows.Forms
+$text = [regex]::Replace($env:WEDGE_TEXT, '[+^%~(){}\[\]]', '{$0}')
+[System.Windows.Forms.SendKeys]::SendWait($text + $env:WEDGE_KEY)
+`
+
+// Type 模拟键盘输入 text 并按下 suffix 对应的键
+func Type(ctx context.Context, text, suffix string) error {
+	key := ""
+	switch suffix {
+	case SuffixEnter:
+		key = "{ENTER}"
+	case SuffixTab:
+		key = "{TAB}"
+	}
+	cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", typeScript)
+	cmd.Env = append(os.Environ(), "WEDGE_TEXT="+text, "WEDGE_KEY="+key)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("powershell: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
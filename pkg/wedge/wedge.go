@@ -0,0 +1,161 @@
+package wedge
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// 每行之后按下的键
+const (
+	SuffixEnter = "enter"
+	SuffixTab   = "tab"
+	SuffixNone  = "none"
+)
+
+// 限制
+const (
+	maxLineLength = 4096
+	queueSize     = 256 // 等待输入的行数，超出时丢弃
+	typeTimeout   = 10 * time.Second
+)
+
+// Config 键盘楔模式配置
+type Config struct {
+	Enabled bool   `json:"enabled"`
+	Filter  string `json:"filter,omitempty"` // 正则表达式：只输入匹配的行，有捕获组时只输入第一个捕获组
+	Suffix  string `json:"suffix,omitempty"` // enter (默认)、tab 或 none
+}
+
+// Wedge 将接收到的数据按行模拟为键盘输入，送到当前获得焦点的窗口
+// 扫码枪、电子秤等串口设备借此向其他应用输入数据
+type Wedge struct {
+	mu   sync.Mutex
+	cfg  Config
+	re   *regexp.Regexp
+	line []byte
+
+	queue   chan string
+	typ     func(ctx context.Context, text, suffix string) error
+	onError func(error)
+	done    chan struct{}
+}
+
+// New 创建键盘楔并启动输入协程，onError 接收输入失败的错误
+func New(onError func(error)) *Wedge {
+	w := &Wedge{
+		cfg:     Config{Suffix: SuffixEnter},
+		queue:   make(chan string, queueSize),
+		typ:     Type,
+		onError: onError,
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// SetConfig 更新配置，过滤表达式或按键无效时返回错误
+func (w *Wedge) SetConfig(cfg Config) error {
+	if cfg.Suffix == "" {
+		cfg.Suffix = SuffixEnter
+	}
+	if cfg.Suffix != SuffixEnter && cfg.Suffix != SuffixTab && cfg.Suffix != SuffixNone {
+		return fmt.Errorf("wedge: unknown suffix %q", cfg.Suffix)
+	}
+	var re *regexp.Regexp
+	if cfg.Filter != "" {
+		var err error
+		if re, err = regexp.Compile(cfg.Filter); err != nil {
+			return fmt.Errorf("wedge: filter: %w", err)
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.cfg = cfg
+	w.re = re
+	w.line = w.line[:0]
+	return nil
+}
+
+// Config 返回当前配置
+func (w *Wedge) Config() Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.cfg
+}
+
+// Feed 处理接收到的数据，未启用时忽略
+func (w *Wedge) Feed(data []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.cfg.Enabled {
+		return
+	}
+	for _, c := range data {
+		switch {
+		case c == '\r' || c == '\n':
+			w.flushLocked()
+		case c < 0x20 || c == 0x7f:
+			// 其他控制字符无法作为按键输入，丢弃
+		case len(w.line) < maxLineLength:
+			w.line = append(w.line, c)
+		}
+	}
+}
+
+// flushLocked 将当前行按过滤条件放入输入队列
+func (w *Wedge) flushLocked() {
+	line := string(w.line)
+	w.line = w.line[:0]
+	if line == "" {
+		return
+	}
+	if w.re != nil {
+		m := w.re.FindStringSubmatch(line)
+		if m == nil {
+			return
+		}
+		if len(m) > 1 {
+			line = m[1]
+		}
+	}
+	select {
+	case w.queue <- line:
+	default:
+		w.report(fmt.Errorf("wedge: typing is too slow, dropped %q", line))
+	}
+}
+
+// Close 停止输入协程
+func (w *Wedge) Close() {
+	close(w.queue)
+	<-w.done
+}
+
+func (w *Wedge) run() {
+	defer close(w.done)
+	for text := range w.queue {
+		w.mu.Lock()
+		suffix := w.cfg.Suffix
+		enabled := w.cfg.Enabled
+		w.mu.Unlock()
+		if !enabled {
+			// 关闭后不再输入队列中剩余的行
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), typeTimeout)
+		err := w.typ(ctx, text, suffix)
+		cancel()
+		if err != nil {
+			w.report(err)
+		}
+	}
+}
+
+func (w *Wedge) report(err error) {
+	if w.onError != nil {
+		w.onError(err)
+	}
+}
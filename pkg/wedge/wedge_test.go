@@ -0,0 +1,74 @@
+package wedge
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type typed struct{ text, suffix string }
+
+func newTest(t *testing.T) (*Wedge, chan typed) {
+	out := make(chan typed, 16)
+	w := New(func(err error) { t.Errorf("Unexpected error: %v", err) })
+	w.typ = func(_ context.Context, text, suffix string) error {
+		out <- typed{text, suffix}
+		return nil
+	}
+	t.Cleanup(w.Close)
+	return w, out
+}
+
+func next(t *testing.T, out chan typed) typed {
+	t.Helper()
+	select {
+	case v := <-out:
+		return v
+	case <-time.After(2 * time.Second):
+		t.Fatal("Nothing was typed")
+		return typed{}
+	}
+}
+
+func TestTypesLinesWithFilter(t *testing.T) {
+	w, out := newTest(t)
+	w.Feed([]byte("ignored while disabled\n"))
+	if err := w.SetConfig(Config{Enabled: true, Filter: `^ST,GS,\s*([\d.]+)kg`, Suffix: SuffixTab}); err != nil {
+		t.Fatal(err)
+	}
+	w.Feed([]byte("ST,GS,  12.50kg\r\nUS,GS, 3.0kg\r\nST,G"))
+	w.Feed([]byte("S, 0.75kg\r\n"))
+	if v := next(t, out); v != (typed{"12.50", SuffixTab}) {
+		t.Errorf("Unexpected keystrokes %+v", v)
+	}
+	if v := next(t, out); v.text != "0.75" {
+		t.Errorf("Line split across reads should be joined, got %+v", v)
+	}
+	select {
+	case v := <-out:
+		t.Errorf("Unexpected extra keystrokes %+v", v)
+	default:
+	}
+}
+
+func TestControlCharactersDropped(t *testing.T) {
+	w, out := newTest(t)
+	w.SetConfig(Config{Enabled: true})
+	w.Feed([]byte("\x02ABC123\x03\r\n\r\n"))
+	if v := next(t, out); v != (typed{"ABC123", SuffixEnter}) {
+		t.Errorf("Unexpected keystrokes %+v", v)
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	w, _ := newTest(t)
+	if err := w.SetConfig(Config{Filter: "("}); err == nil {
+		t.Error("Expected an invalid filter error")
+	}
+	if err := w.SetConfig(Config{Suffix: "space"}); err == nil {
+		t.Error("Expected an unknown suffix error")
+	}
+	if w.Config().Suffix != SuffixEnter {
+		t.Error("Failed SetConfig should keep the previous config")
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/pipeline"
+	"serial-assistant/pkg/plc"
+)
+
+// plcTimeout 等待 PLC 应答的最长时间
+const plcTimeout = time.Second
+
+// PlcProtocol 支持的 PLC 协议及其寄存器区域
+type PlcProtocol struct {
+	Name  string   `json:"name"`
+	Areas []string `json:"areas"`
+}
+
+// PlcRequest 一次寄存器读写的目标
+type PlcRequest struct {
+	Protocol string `json:"protocol"` // mewtocol、hostlink、melsec_fx 或 modbus_ascii
+	Station  int    `json:"station"`  // 站号/单元号，melsec_fx 忽略
+	Area     string `json:"area"`
+	Address  int    `json:"address"`
+	Count    int    `json:"count"` // 仅用于读取
+}
+
+// feedPlc 将接收数据交给 PLC 主站配对应答
+func (a *App) feedPlc(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		buf, ok := ev.Payload.(*bufpool.Buffer)
+		if !ok {
+			return
+		}
+		a.plc.Feed(buf.B)
+		buf.Release()
+	})
+}
+
+// plcError 将主站错误转换为结构化错误
+func plcError(ctx context.Context, err error) *apperr.Error {
+	switch {
+	case errors.Is(err, plc.ErrUnknownProtocol), errors.Is(err, plc.ErrUnknownArea), errors.Is(err, plc.ErrRange):
+		return apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	return scpiError(ctx, err)
+}
+
+// ListPlcProtocols 返回支持的 PLC 协议
+func (a *App) ListPlcProtocols() []PlcProtocol {
+	var list []PlcProtocol
+	for _, name := range plc.Names() {
+		p, _ := plc.Lookup(name)
+		list = append(list, PlcProtocol{Name: name, Areas: p.Areas()})
+	}
+	return list
+}
+
+// PlcRead 作为主站读取 PLC 的字寄存器
+func (a *App) PlcRead(req PlcRequest) ([]uint16, error) {
+	p, err := plc.Lookup(req.Protocol)
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	op := a.startOperation("plc", plcTimeout)
+	defer a.ops.Finish(op)
+	values, err := a.plc.Read(op.Context(), p, req.Station, req.Area, req.Address, req.Count)
+	if err != nil {
+		return nil, plcError(op.Context(), err)
+	}
+	return values, nil
+}
+
+// PlcWrite 作为主站写入 PLC 的字寄存器，每个值需在 0~65535 范围内
+func (a *App) PlcWrite(req PlcRequest, values []int) apperr.Result {
+	p, err := plc.Lookup(req.Protocol)
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	words := make([]uint16, len(values))
+	for i, v := range values {
+		if v < 0 || v > 0xffff {
+			return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, fmt.Sprintf("value %d out of range", v)))
+		}
+		words[i] = uint16(v)
+	}
+	if a.skipDryRun("plc-write", req.Protocol, fmt.Sprintf("station %d %s%d = %v", req.Station, req.Area, req.Address, words)) {
+		return apperr.OK()
+	}
+	op := a.startOperation("plc", plcTimeout)
+	defer a.ops.Finish(op)
+	if err := a.plc.Write(op.Context(), p, req.Station, req.Area, req.Address, words); err != nil {
+		return apperr.FromError(plcError(op.Context(), err))
+	}
+	return apperr.OK()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/portable"
+)
+
+// PortableStatus 便携模式状态
+type PortableStatus struct {
+	Portable     bool   `json:"portable"`
+	DataDir      string `json:"dataDir"`      // 当前使用的数据目录
+	PortableDir  string `json:"portableDir"`  // 程序旁的数据目录
+	InstalledDir string `json:"installedDir"` // 用户配置目录下的数据目录
+	Forced       bool   `json:"forced"`       // 由 --portable 参数启用，无法通过迁移关闭
+}
+
+// GetPortableStatus 返回当前是否为便携模式以及两种模式的数据目录
+func (a *App) GetPortableStatus() PortableStatus {
+	dir := appDataDir()
+	pdir, _ := portableDataDir()
+	return PortableStatus{
+		Portable:     portableOn,
+		DataDir:      dir,
+		PortableDir:  pdir,
+		InstalledDir: installedDataDir(),
+		Forced:       portable.HasFlag(os.Args[1:]),
+	}
+}
+
+// SetPortableMode 在便携模式与安装模式之间迁移数据，重启后生效
+// 启用时把配置、会话日志与抓包复制到程序旁的数据目录；
+// 关闭时复制回用户配置目录，并将程序旁的目录改名保留，源数据不会删除
+func (a *App) SetPortableMode(enabled bool) apperr.Result {
+	st := a.GetPortableStatus()
+	if st.Portable == enabled {
+		return apperr.OK()
+	}
+	if st.PortableDir == "" {
+		return apperr.FromError(apperr.New(apperr.CodeUnsupported, "executable path unknown"))
+	}
+	if !enabled && st.Forced {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, "started with "+portable.Flag))
+	}
+	a.mutex.Lock()
+	connected := a.isConnected
+	a.mutex.Unlock()
+	if connected {
+		return apperr.FromError(apperr.New(apperr.CodeAlreadyConnected, ""))
+	}
+
+	src, dst := st.InstalledDir, st.PortableDir
+	if !enabled {
+		src, dst = st.PortableDir, st.InstalledDir
+	}
+	res, err := portable.Migrate(src, dst)
+	if err != nil {
+		code := apperr.CodeInternal
+		if errors.Is(err, os.ErrPermission) {
+			code = apperr.CodePermissionDenied
+		}
+		return apperr.FromError(apperr.Wrap(code, err))
+	}
+	if !enabled {
+		// 程序旁存在数据目录即视为便携模式，改名后下次启动使用用户配置目录
+		bak := fmt.Sprintf("%s.migrated-%s", st.PortableDir, time.Now().Format("20060102-150405"))
+		if err := os.Rename(st.PortableDir, bak); err != nil {
+			return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+		}
+	}
+	a.log("portable").Info("data migrated", "dest", dst, "files", res.Files, "bytes", res.Bytes, "skipped", res.Skipped)
+	return apperr.OK()
+}
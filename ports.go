@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/driverhealth"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/ports"
+)
+
+// usbResetTimeout 复位 USB 设备的超时
+const usbResetTimeout = 30 * time.Second
+
+// holderTimeout 查找占用串口的进程的超时
+const holderTimeout = 3 * time.Second
+
+// ListPorts 返回串口的详细信息：显示名称、USB VID/PID、序列号、厂商与产品字符串、物理位置及占用/隐藏等状态标志
+// 同型号的多个适配器可通过 Label 中的序列号或 USB 位置区分
+func (a *App) ListPorts() ([]ports.Port, error) {
+	return ports.List()
+}
+
+// ResetUsbDevice 复位串口背后的 USB 设备使其重新枚举，用于恢复卡死的 CP210x/CH340 等转换器
+// 若当前正打开该串口会先关闭；Linux 需要 USB 设备节点的写权限，Windows 需要管理员权限，macOS 不支持
+func (a *App) ResetUsbDevice(portName string) apperr.Result {
+	if a.skipDryRun("usb-reset", portName, "") {
+		return apperr.OK()
+	}
+	a.mutex.Lock()
+	open := a.isConnected && a.connType == TypeSerial && a.serialPortName == portName
+	a.mutex.Unlock()
+	if open {
+		a.Close()
+	}
+
+	op := a.startOperation("usb-reset", usbResetTimeout)
+	defer a.ops.Finish(op)
+
+	err := ports.Reset(op.Context(), portName)
+	switch {
+	case err == nil:
+		return apperr.OK()
+	case op.Context().Err() != nil:
+		return apperr.FromError(contextError(op.Context()))
+	case errors.Is(err, ports.ErrResetUnsupported):
+		return apperr.FromError(apperr.Wrap(apperr.CodeUnsupported, err))
+	case errors.Is(err, ports.ErrNotUSB):
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	case errors.Is(err, os.ErrPermission):
+		return apperr.FromError(apperr.Wrap(apperr.CodePermissionDenied, err))
+	}
+	return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+}
+
+// GetPortHolders 返回打开了串口的其他进程 (Linux 扫描 /proc，macOS 使用 lsof，Windows 需要 Sysinternals handle)
+// Windows 上未安装 handle 或用户尚未运行一次 handle.exe 接受其许可协议时返回 UNSUPPORTED，错误说明中注明原因
+func (a *App) GetPortHolders(portName string) ([]ports.Holder, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), holderTimeout)
+	defer cancel()
+	list, err := ports.Holders(ctx, portName)
+	switch {
+	case errors.Is(err, ports.ErrHoldersUnsupported), errors.Is(err, ports.ErrHandleEULA):
+		return nil, apperr.Wrap(apperr.CodeUnsupported, err)
+	case errors.Is(err, os.ErrNotExist):
+		return nil, apperr.Wrap(apperr.CodePortNotFound, err)
+	case err != nil:
+		return nil, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return list, nil
+}
+
+// explainPortBusy 串口被占用而无法打开时查找占用的进程，找到时用其替换错误说明中的原始错误信息
+func (a *App) explainPortBusy(portName string, e *apperr.Error) {
+	ctx, cancel := context.WithTimeout(context.Background(), holderTimeout)
+	defer cancel()
+	list, err := ports.Holders(ctx, portName)
+	if err != nil {
+		a.log("serial").Debug("port holders not found", "port", portName, "err", err)
+		return
+	}
+	if len(list) == 0 {
+		return
+	}
+	names := make([]string, len(list))
+	for i, h := range list {
+		names[i] = h.String()
+	}
+	e.Details = i18n.T("app.port_held_by", portName, strings.Join(names, ", "))
+	a.log("serial").Info("port busy", "port", portName, "holders", e.Details)
+}
+
+// GetDriverHealth 诊断串口的转换芯片、驱动版本、延迟定时器及已知的问题驱动
+func (a *App) GetDriverHealth(portName string) driverhealth.Report {
+	var vid, pid string
+	if p := usbPortDetails(portName); p != nil {
+		vid, pid = p.VID, p.PID
+	}
+	return driverhealth.Diagnose(portName, vid, pid)
+}
+
+// portWatchInterval 检测串口插拔的轮询间隔
+const portWatchInterval = time.Second
+
+// startPortWatch 在后台检测串口插拔，发送 "port-added" / "port-removed" 事件
+func (a *App) startPortWatch() {
+	w := ports.Watch(portWatchInterval, ports.Snapshot, func(added, removed []ports.Port) {
+		for _, p := range removed {
+			a.bus.Publish(EventPortRemoved, p)
+		}
+		for _, p := range added {
+			a.bus.Publish(EventPortAdded, p)
+		}
+	})
+	a.portWatchMutex.Lock()
+	a.portWatcher = w
+	a.portWatchMutex.Unlock()
+}
+
+// stopPortWatch 停止检测串口插拔
+func (a *App) stopPortWatch() {
+	a.portWatchMutex.Lock()
+	w := a.portWatcher
+	a.portWatcher = nil
+	a.portWatchMutex.Unlock()
+	if w != nil {
+		w.Stop()
+	}
+}
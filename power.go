@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/jlink"
+	"serial-assistant/pkg/rules"
+)
+
+// defaultPowerOff 断电重启时默认的断电时长
+const defaultPowerOff = time.Second
+
+// withProbe 在持有相应锁时对探针执行 fn；label 为空表示主 RTT 连接，否则为附加探针
+func (a *App) withProbe(label string, fn func(jl *jlink.JLinkWrapper) error) error {
+	if label == "" {
+		a.mutex.Lock()
+		defer a.mutex.Unlock()
+		if !a.isConnected || a.connType != TypeJLink || a.jlinkConn == nil {
+			return apperr.New(apperr.CodeNotConnected, "")
+		}
+		return fn(a.jlinkConn)
+	}
+	a.probesMutex.Lock()
+	p, ok := a.probes[label]
+	a.probesMutex.Unlock()
+	if !ok {
+		return apperr.New(apperr.CodeNotFound, label)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.jl == nil {
+		return apperr.New(apperr.CodeNotConnected, label)
+	}
+	return fn(p.jl)
+}
+
+// setProbePower 打开或关闭探针向目标的 5V 供电
+func (a *App) setProbePower(label string, on, permanent bool) error {
+	if a.dryRun.Load() {
+		target, state := label, "off"
+		if target == "" {
+			target = mainSession
+		}
+		if on {
+			state = "on"
+		}
+		if permanent {
+			state += " perm"
+		}
+		a.skipDryRun("probe-power", target, state)
+		return nil
+	}
+	return a.withProbe(label, func(jl *jlink.JLinkWrapper) error {
+		if err := jl.SetTargetPower(on, permanent); err != nil {
+			return apperr.Wrap(apperr.CodeProbeFailed, err)
+		}
+		return nil
+	})
+}
+
+// powerCycle 断电 off 后重新上电；等待期间不占用连接锁，被取消时也会恢复供电
+func (a *App) powerCycle(ctx context.Context, label string, off time.Duration) error {
+	if off <= 0 {
+		off = defaultPowerOff
+	}
+	if err := a.setProbePower(label, false, false); err != nil {
+		return err
+	}
+	t := time.NewTimer(off)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+		a.setProbePower(label, true, false)
+		return contextError(ctx)
+	}
+	return a.setProbePower(label, true, false)
+}
+
+// SetProbePower 打开或关闭探针 (label 为空表示主 RTT 连接) 向目标的 5V 供电
+// permanent 同时写入探针的默认设置 (J-Link Commander 的 "power on/off perm")
+func (a *App) SetProbePower(label string, on, permanent bool) apperr.Result {
+	return apperr.FromError(a.setProbePower(label, on, permanent))
+}
+
+// PowerCycleProbe 对由探针供电的目标断电重启，可通过 Cancel 取消 (取消时立即恢复供电)
+func (a *App) PowerCycleProbe(label string, offMs int) apperr.Result {
+	op := a.startOperation("power-cycle", 0)
+	defer a.ops.Finish(op)
+	return apperr.FromError(a.powerCycle(op.Context(), label, time.Duration(offMs)*time.Millisecond))
+}
+
+// GetProbeVTref 返回探针测得的目标参考电压 (mV)，0 表示目标未上电
+func (a *App) GetProbeVTref(label string) (int, error) {
+	var mv int
+	err := a.withProbe(label, func(jl *jlink.JLinkWrapper) error {
+		v, err := jl.VTref()
+		if err != nil {
+			return apperr.Wrap(apperr.CodeProbeFailed, err)
+		}
+		mv = v
+		return nil
+	})
+	return mv, err
+}
+
+// probePowerAction 规则动作：控制探针供电
+// 参数 state 为 on、off 或 cycle (缺省)，probe 为附加探针的标签 (缺省为主连接)，
+// offMs 为 cycle 的断电时长，permanent 为 true 时写入探针默认设置
+func (a *App) probePowerAction(ctx context.Context, m rules.Match, action rules.Action) error {
+	label := action.Params["probe"]
+	permanent := action.Params["permanent"] == "true"
+	switch action.Params["state"] {
+	case "on":
+		return a.setProbePower(label, true, permanent)
+	case "off":
+		return a.setProbePower(label, false, permanent)
+	}
+	offMs, _ := strconv.Atoi(action.Params["offMs"])
+	return a.powerCycle(ctx, label, time.Duration(offMs)*time.Millisecond)
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/jlink"
+)
+
+// rttProbe 与主连接并行读取的附加 RTT 探针
+type rttProbe struct {
+	info   RttProbeInfo
+	mu     sync.Mutex // 保护 jl：读取循环与供电控制等操作不同时调用驱动，循环退出后置为 nil
+	jl     *jlink.JLinkWrapper
+	tagger *jlink.Tagger
+	stop   chan struct{}
+}
+
+// RttProbeInfo 附加探针的信息
+type RttProbeInfo struct {
+	Label     string       `json:"label"`
+	Target    jlink.Target `json:"target"`
+	Chip      string       `json:"chip"`
+	Speed     int          `json:"speed"`
+	Interface string       `json:"interface"`
+	Opened    time.Time    `json:"opened"`
+
+	// BackgroundAccess RTT 轮询在目标运行时以后台方式读取内存，不暂停 CPU
+	BackgroundAccess bool `json:"backgroundAccess"`
+}
+
+// OpenRttProbe 打开一个附加的 J-Link 探针 (USB/隧道按序列号，网络探针按地址) 并开始读取 RTT
+// 各探针使用独立的驱动实例，输出按行加上 "[label] " 前缀后并入接收数据，
+// 与主连接的数据一起显示、抓包与匹配规则，便于调试多 MCU 产品
+func (a *App) OpenRttProbe(label string, target jlink.Target, chip string, speed int, iface string) apperr.Result {
+	if label == "" || chip == "" {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, label))
+	}
+	if err := target.Validate(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	a.probesMutex.Lock()
+	_, exists := a.probes[label]
+	a.probesMutex.Unlock()
+	if exists {
+		return apperr.FromError(apperr.New(apperr.CodeAlreadyConnected, label))
+	}
+
+	logCallback := func(message string) {
+		a.bus.Publish(EventSysMsg, "["+label+"] "+message)
+	}
+	jl, err := jlink.NewProbe(target, logCallback)
+	if err != nil {
+		return apperr.FromError(jlinkError(err))
+	}
+	a.applyRttElf(jl)
+	a.applyDLLLog(jl)
+
+	op := a.startOperation("open-probe", jlinkConnectTimeout)
+	defer a.ops.Finish(op)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- jl.ConnectContext(op.Context(), chip, speed, iface)
+	}()
+	select {
+	case err = <-done:
+		if err != nil {
+			jl.Close()
+			if op.Context().Err() != nil {
+				return apperr.FromError(contextError(op.Context()))
+			}
+			return apperr.FromError(jlinkError(err))
+		}
+	case <-op.Context().Done():
+		go func() {
+			<-done
+			jl.Close()
+		}()
+		return apperr.FromError(contextError(op.Context()))
+	}
+
+	p := &rttProbe{
+		info: RttProbeInfo{
+			Label: label, Target: target, Chip: chip, Speed: speed, Interface: iface, Opened: time.Now(),
+			BackgroundAccess: jl.BackgroundAccess(),
+		},
+		jl:     jl,
+		tagger: jlink.NewTagger(label),
+		stop:   make(chan struct{}),
+	}
+	a.probesMutex.Lock()
+	if _, exists := a.probes[label]; exists {
+		a.probesMutex.Unlock()
+		jl.Close()
+		return apperr.FromError(apperr.New(apperr.CodeAlreadyConnected, label))
+	}
+	if a.probes == nil {
+		a.probes = make(map[string]*rttProbe)
+	}
+	a.probes[label] = p
+	a.probesMutex.Unlock()
+
+	go a.probeReadLoop(p)
+	return apperr.OK()
+}
+
+// CloseRttProbe 关闭附加探针
+func (a *App) CloseRttProbe(label string) apperr.Result {
+	a.probesMutex.Lock()
+	p, ok := a.probes[label]
+	delete(a.probes, label)
+	a.probesMutex.Unlock()
+	if !ok {
+		return apperr.FromError(apperr.New(apperr.CodeNotFound, label))
+	}
+	close(p.stop) // 读取循环退出时释放驱动
+	return apperr.OK()
+}
+
+// ListRttProbes 返回已打开的附加探针 (按标签排序)
+func (a *App) ListRttProbes() ([]RttProbeInfo, error) {
+	a.probesMutex.Lock()
+	defer a.probesMutex.Unlock()
+	list := make([]RttProbeInfo, 0, len(a.probes))
+	for _, p := range a.probes {
+		list = append(list, p.info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Label < list[j].Label })
+	return list, nil
+}
+
+// closeRttProbes 关闭全部附加探针 (退出时)
+func (a *App) closeRttProbes() {
+	a.probesMutex.Lock()
+	probes := a.probes
+	a.probes = nil
+	a.probesMutex.Unlock()
+	for _, p := range probes {
+		close(p.stop)
+	}
+}
+
+// probeReadLoop 轮询附加探针的 RTT，错误处理与主连接的 jlinkReadLoop 一致
+// 驱动只在本循环中使用，退出时由本循环关闭
+func (a *App) probeReadLoop(p *rttProbe) {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	defer func() {
+		p.mu.Lock()
+		p.jl.Close()
+		p.jl = nil
+		p.mu.Unlock()
+	}()
+
+	publish := func(data []byte) {
+		if len(data) > 0 {
+			a.publishRx(bufpool.Wrap(data))
+		}
+	}
+	defer func() { publish(p.tagger.Flush()) }()
+
+	consecutiveErrors := 0
+	const maxConsecutiveErrors = 10
+	sysMsg := func(msg string) {
+		a.bus.Publish(EventSysMsg, "["+p.info.Label+"] "+msg)
+	}
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			data, err := p.jl.ReadRTT()
+			p.mu.Unlock()
+			if err != nil {
+				consecutiveErrors++
+				if consecutiveErrors == 1 && errors.Is(err, jlink.ErrOffsetOutOfBounds) {
+					sysMsg(i18n.T("app.rtt_target_reset"))
+					p.mu.Lock()
+					reinitErr := p.jl.ReinitSoftRTT()
+					p.mu.Unlock()
+					if reinitErr == nil {
+						sysMsg(i18n.T("app.rtt_reinit_ok"))
+						consecutiveErrors = 0
+						continue
+					} else {
+						sysMsg(i18n.T("app.rtt_reinit_failed", reinitErr))
+					}
+				}
+				if consecutiveErrors >= maxConsecutiveErrors {
+					a.bus.Publish(EventSerialError, "["+p.info.Label+"] "+i18n.T("app.rtt_error", consecutiveErrors, err))
+					a.probesMutex.Lock()
+					if a.probes[p.info.Label] == p {
+						delete(a.probes, p.info.Label)
+					}
+					a.probesMutex.Unlock()
+					return
+				}
+				if consecutiveErrors == 1 {
+					sysMsg(i18n.T("app.rtt_read_warning", err))
+				}
+				continue
+			}
+			consecutiveErrors = 0
+			if len(data) > 0 {
+				a.bridgeFromTarget(p.info.Label, data)
+			}
+			publish(p.tagger.Feed(data))
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/jlink"
+	"serial-assistant/pkg/profiler"
+)
+
+// profPollInterval 读取探针 SWO 缓冲区的间隔
+const profPollInterval = 20 * time.Millisecond
+
+// ProfilerConfig PC 采样设置
+type ProfilerConfig struct {
+	Probe      string `json:"probe"`      // 附加探针的标签，空表示主 RTT 连接
+	CpuHz      uint32 `json:"cpuHz"`      // 目标内核时钟
+	SwoHz      uint32 `json:"swoHz"`      // SWO 波特率，0 由探针选择
+	Interval   uint32 `json:"interval"`   // 采样间隔 (CPU 周期)，0 为 4096
+	DurationMs int    `json:"durationMs"` // 采样时长，0 表示直到 StopProfiler
+	Elf        string `json:"elf"`        // 用于符号化的固件 ELF，空时使用 SetRttElf 设置的文件
+}
+
+// ProfileStatus 采样状态及最热点函数
+type ProfileStatus struct {
+	Running  bool   `json:"running"`
+	Probe    string `json:"probe"`
+	Interval uint32 `json:"interval"` // 实际采样间隔 (CPU 周期)
+	Elf      string `json:"elf"`      // 空表示未符号化，报告按 PC 地址列出
+	Symbols  int    `json:"symbols"`
+	profiler.Report
+}
+
+// StartProfiler 通过 SWO 开启 DWT 周期性 PC 采样，持续统计目标各函数占用的 CPU 时间
+// 开启采样需要写入 DEMCR、ITM 与 DWT_CTRL 寄存器，试运行模式下只记录将要写入的寄存器，不开始采样
+func (a *App) StartProfiler(cfg ProfilerConfig) apperr.Result {
+	if cfg.CpuHz == 0 {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, "cpuHz"))
+	}
+	if cfg.Interval == 0 {
+		cfg.Interval = 4096
+	}
+	elf := cfg.Elf
+	if elf == "" {
+		a.rttElfMutex.Lock()
+		if a.rttElf != nil {
+			elf = a.rttElf.Path
+		}
+		a.rttElfMutex.Unlock()
+	}
+	var syms *profiler.Symbols
+	if elf != "" {
+		s, err := profiler.LoadSymbols(elf)
+		if err != nil {
+			return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+		}
+		syms = s
+	}
+
+	a.profMutex.Lock()
+	defer a.profMutex.Unlock()
+	if a.profStop != nil {
+		return apperr.FromError(apperr.New(apperr.CodeAlreadyConnected, a.profStatus.Probe))
+	}
+	if a.skipDryRun("memory-write", profTarget(cfg.Probe), fmt.Sprintf("DEMCR, ITM_TCR, DWT_CTRL (PC sampling every %d cycles)", cfg.Interval)) {
+		return apperr.OK()
+	}
+	var interval uint32
+	err := a.withProbe(cfg.Probe, func(jl *jlink.JLinkWrapper) error {
+		v, err := jl.StartPCSampling(cfg.CpuHz, cfg.SwoHz, cfg.Interval)
+		if err != nil {
+			return apperr.Wrap(apperr.CodeProbeFailed, err)
+		}
+		interval = v
+		return nil
+	})
+	if err != nil {
+		return apperr.FromError(err)
+	}
+
+	prof := profiler.New(syms)
+	stop, done := make(chan struct{}), make(chan struct{})
+	a.prof, a.profStop, a.profDone = prof, stop, done
+	a.profStatus = ProfileStatus{Running: true, Probe: cfg.Probe, Interval: interval, Elf: elf}
+	if syms != nil {
+		a.profStatus.Symbols = syms.Len()
+	}
+	go a.profileLoop(cfg.Probe, time.Duration(cfg.DurationMs)*time.Millisecond, prof, stop, done)
+	return apperr.OK()
+}
+
+// profileLoop 周期性取出 SWO 数据交给统计，停止、到时或探针断开时关闭采样
+func (a *App) profileLoop(probe string, duration time.Duration, prof *profiler.Profile, stop, done chan struct{}) {
+	defer close(done)
+	var deadline <-chan time.Time
+	if duration > 0 {
+		t := time.NewTimer(duration)
+		defer t.Stop()
+		deadline = t.C
+	}
+	ticker := time.NewTicker(profPollInterval)
+	defer ticker.Stop()
+
+	read := func() error {
+		return a.withProbe(probe, func(jl *jlink.JLinkWrapper) error {
+			data, err := jl.ReadSWO()
+			if len(data) > 0 {
+				prof.Feed(data)
+			}
+			return err
+		})
+	}
+loop:
+	for {
+		select {
+		case <-stop:
+			break loop
+		case <-deadline:
+			break loop
+		case <-ticker.C:
+			if read() != nil {
+				break loop
+			}
+		}
+	}
+	read()
+	if !a.skipDryRun("memory-write", profTarget(probe), "DWT_CTRL (PC sampling off)") {
+		a.withProbe(probe, func(jl *jlink.JLinkWrapper) error { return jl.StopPCSampling() })
+	}
+
+	a.profMutex.Lock()
+	if a.profStop == stop {
+		a.profStop = nil
+		a.profStatus.Running = false
+	}
+	a.profMutex.Unlock()
+}
+
+// StopProfiler 停止 PC 采样，已收集的统计保留到下一次 StartProfiler
+// 关闭采样需要写入 DWT_CTRL，试运行模式下只停止读取 SWO 并记录将要写入的寄存器
+func (a *App) StopProfiler() apperr.Result {
+	a.profMutex.Lock()
+	stop, done := a.profStop, a.profDone
+	if stop != nil {
+		a.profStop = nil
+		a.profStatus.Running = false
+		close(stop)
+	}
+	a.profMutex.Unlock()
+	if stop == nil {
+		return apperr.OK()
+	}
+	<-done
+	return apperr.OK()
+}
+
+// profTarget 返回试运行记录中探针的名称
+func profTarget(probe string) string {
+	if probe == "" {
+		return mainSession
+	}
+	return probe
+}
+
+// GetProfile 返回采样状态及占用 CPU 最多的 top 个函数 (top <= 0 表示全部)
+func (a *App) GetProfile(top int) (ProfileStatus, error) {
+	a.profMutex.Lock()
+	defer a.profMutex.Unlock()
+	status := a.profStatus
+	if a.prof != nil {
+		status.Report = a.prof.Report(top)
+	}
+	return status, nil
+}
+
+// coverage 返回当前统计的函数覆盖率；没有采样或未加载 ELF 符号时返回 NotFound
+func (a *App) coverage() (profiler.Coverage, string, error) {
+	a.profMutex.Lock()
+	prof, elf := a.prof, a.profStatus.Elf
+	a.profMutex.Unlock()
+	if prof == nil {
+		return profiler.Coverage{}, "", apperr.New(apperr.CodeNotFound, "")
+	}
+	c, ok := prof.Coverage()
+	if !ok {
+		return profiler.Coverage{}, "", apperr.New(apperr.CodeNotFound, "elf")
+	}
+	return c, elf, nil
+}
+
+// GetCoverage 返回最近一次 PC 采样的函数覆盖率：测试序列运行期间开启采样，结束后查看哪些函数被执行过
+// 基于统计采样，是粗略的覆盖率；短小、很少执行的函数可能需要更小的采样间隔才能被采到
+func (a *App) GetCoverage() (profiler.Coverage, error) {
+	c, _, err := a.coverage()
+	return c, err
+}
+
+// ExportCoverage 将函数覆盖率导出为 lcov 跟踪文件 (.info)，可交给 genhtml 或 CI 的覆盖率汇总
+func (a *App) ExportCoverage(destPath string) apperr.Result {
+	c, elf, err := a.coverage()
+	if err != nil {
+		return apperr.FromError(err)
+	}
+	out, err := os.Create(destPath)
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	err = c.WriteLCOV(out, filepath.Base(elf))
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/profiler"
+)
+
+func TestProfilerDryRun(t *testing.T) {
+	a := NewApp()
+	sub := a.bus.Subscribe(8, EventDryRun)
+	defer sub.Close()
+	a.SetDryRun(true)
+
+	if r := a.StartProfiler(ProfilerConfig{CpuHz: 72000000}); !r.OK {
+		t.Fatalf("StartProfiler = %+v", r)
+	}
+	if st, _ := a.GetProfile(0); st.Running {
+		t.Error("Profiler should not start in dry-run mode")
+	}
+	expectDryRun(t, sub.C, "DEMCR")
+
+	// 采样期间开启试运行：停止时不写回 DWT_CTRL
+	stop, done := make(chan struct{}), make(chan struct{})
+	go a.profileLoop("probe", 0, profiler.New(nil), stop, done)
+	<-done
+	expectDryRun(t, sub.C, "DWT_CTRL")
+}
+
+func expectDryRun(t *testing.T, c <-chan eventbus.Event, detail string) {
+	t.Helper()
+	select {
+	case ev := <-c:
+		entry, ok := ev.Payload.(DryRunEntry)
+		if !ok || entry.Operation != "memory-write" || !strings.Contains(entry.Detail, detail) {
+			t.Errorf("Expected a dry-run memory write of %s, got %+v", detail, ev.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("No dry-run event for %s", detail)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/journal"
+	"serial-assistant/pkg/ports"
+	"serial-assistant/pkg/reconnect"
+)
+
+// reconnectFile 自动重连配置文件名 (位于应用数据目录)
+const reconnectFile = "reconnect.json"
+
+// ReconnectStatus 自动重连进度，随 EventReconnect 发送
+type ReconnectStatus struct {
+	Port string `json:"port"`
+	reconnect.Progress
+}
+
+// loadReconnect 读取自动重连配置
+func (a *App) loadReconnect() {
+	p, err := reconnect.Load(filepath.Join(appDataDir(), reconnectFile))
+	if err != nil {
+		a.log("reconnect").Warn("reconnect policy not loaded", "err", err)
+	}
+	a.reconnectMutex.Lock()
+	a.reconnectPolicy = p
+	a.reconnectMutex.Unlock()
+}
+
+// GetAutoReconnect 返回自动重连配置
+func (a *App) GetAutoReconnect() reconnect.Policy {
+	a.reconnectMutex.Lock()
+	defer a.reconnectMutex.Unlock()
+	return a.reconnectPolicy
+}
+
+// SetAutoReconnect 设置并保存自动重连配置；关闭时停止正在进行的重连
+func (a *App) SetAutoReconnect(p reconnect.Policy) apperr.Result {
+	if err := p.Validate(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	if err := reconnect.Save(filepath.Join(appDataDir(), reconnectFile), p); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	a.reconnectMutex.Lock()
+	a.reconnectPolicy = p
+	a.reconnectMutex.Unlock()
+	if !p.Enabled {
+		a.cancelReconnect()
+	}
+	return apperr.OK()
+}
+
+// CancelReconnect 停止等待设备重新出现
+func (a *App) CancelReconnect() apperr.Result {
+	if !a.cancelReconnect() {
+		return apperr.FromError(apperr.New(apperr.CodeNotFound, "reconnect"))
+	}
+	return apperr.OK()
+}
+
+// cancelReconnect 停止正在进行的重连，返回是否有重连在进行
+func (a *App) cancelReconnect() bool {
+	a.reconnectMutex.Lock()
+	defer a.reconnectMutex.Unlock()
+	if a.reconnectCancel == nil {
+		return false
+	}
+	a.reconnectCancel()
+	a.reconnectCancel = nil
+	return true
+}
+
+// reconnectTarget 读取错误导致连接断开时，若开启了自动重连则返回需要重连的串口参数
+// 须在 Close 之前调用，Close 会清除会话状态中的连接
+func (a *App) reconnectTarget() *journal.Connection {
+	if !a.GetAutoReconnect().Enabled {
+		return nil
+	}
+	a.mutex.Lock()
+	serialConn := a.isConnected && a.connType == TypeSerial
+	a.mutex.Unlock()
+	if !serialConn {
+		return nil
+	}
+	return a.currentConnection()
+}
+
+// startReconnect 在后台等待串口重新出现并以原参数重新打开
+func (a *App) startReconnect(conn *journal.Connection) {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.reconnectMutex.Lock()
+	if a.reconnectCancel != nil {
+		a.reconnectCancel()
+	}
+	a.reconnectCancel = cancel
+	policy := a.reconnectPolicy
+	a.reconnectMutex.Unlock()
+
+	a.bus.Publish(EventSysMsg, i18n.T("app.reconnect_waiting", conn.Port))
+	go func() {
+		defer cancel()
+		err := reconnect.Run(ctx, policy,
+			func() bool { return portPresent(conn.Port) },
+			func() error { return a.reopen(conn) },
+			func(p reconnect.Progress) {
+				a.bus.Publish(EventReconnect, ReconnectStatus{Port: conn.Port, Progress: p})
+			})
+		switch {
+		case err == nil:
+			a.bus.Publish(EventSysMsg, i18n.T("app.reconnected", conn.Port))
+		case err == reconnect.ErrTimeout:
+			a.bus.Publish(EventSysMsg, i18n.T("app.reconnect_failed", conn.Port))
+		}
+		a.reconnectMutex.Lock()
+		if ctx.Err() == nil {
+			a.reconnectCancel = nil
+		}
+		a.reconnectMutex.Unlock()
+	}()
+}
+
+// reopen 以记录的参数重新打开串口，已有其他连接时放弃重连
+func (a *App) reopen(conn *journal.Connection) error {
+	res := a.openConnection(conn)
+	switch {
+	case res.OK:
+		return nil
+	case res.Code == apperr.CodeAlreadyConnected:
+		return fmt.Errorf("%w: %s", reconnect.ErrAbort, res.Message)
+	case res.Details != "":
+		return fmt.Errorf("%s: %s", res.Message, res.Details)
+	}
+	return fmt.Errorf("%s", res.Message)
+}
+
+// portPresent 串口是否已重新枚举到 (轻量枚举，不打开其他串口)
+func portPresent(name string) bool {
+	list, err := ports.Snapshot()
+	if err != nil {
+		return false
+	}
+	for _, p := range list {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
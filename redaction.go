@@ -0,0 +1,142 @@
+package main
+
+import (
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/capture"
+	"serial-assistant/pkg/redact"
+	"serial-assistant/pkg/share"
+)
+
+// redactionFile 脱敏设置文件名 (位于应用数据目录)
+const redactionFile = "redaction.json"
+
+// shareRedactIdle 共享会话中不完整的行 (如提示符) 脱敏后推送前等待的空闲时间
+const shareRedactIdle = 200 * time.Millisecond
+
+// loadRedaction 读取脱敏设置
+func (a *App) loadRedaction() {
+	cfg, err := redact.Load(filepath.Join(appDataDir(), redactionFile))
+	if err == nil {
+		err = a.applyRedaction(cfg)
+	}
+	if err != nil {
+		a.log("redaction").Warn("redaction rules not loaded", "err", err)
+	}
+}
+
+// applyRedaction 编译规则并替换共享会话使用的脱敏流
+func (a *App) applyRedaction(cfg redact.Config) error {
+	r, err := redact.New(cfg.Rules)
+	if err != nil {
+		return err
+	}
+	a.redactMutex.Lock()
+	defer a.redactMutex.Unlock()
+	for _, s := range a.shareRedact {
+		if s != nil {
+			s.Flush()
+		}
+	}
+	a.redactCfg = cfg
+	a.redactor = r
+	a.shareRedact = [2]*redact.Stream{}
+	if cfg.Enabled && r != nil {
+		a.shareRedact = [2]*redact.Stream{
+			redact.NewStream(r, shareRedactIdle, a.broadcastRedacted(share.TypeRx)),
+			redact.NewStream(r, shareRedactIdle, a.broadcastRedacted(share.TypeTx)),
+		}
+	}
+	return nil
+}
+
+// activeRedactor 返回启用的规则集，未启用时返回 nil
+func (a *App) activeRedactor() *redact.Redactor {
+	a.redactMutex.Lock()
+	defer a.redactMutex.Unlock()
+	if !a.redactCfg.Enabled {
+		return nil
+	}
+	return a.redactor
+}
+
+// shareStream 返回共享会话中某方向的脱敏流，未启用时返回 nil
+func (a *App) shareStream(dir capture.Direction) *redact.Stream {
+	a.redactMutex.Lock()
+	defer a.redactMutex.Unlock()
+	return a.shareRedact[dir&1]
+}
+
+// broadcastRedacted 将脱敏后的数据推送给观看者
+func (a *App) broadcastRedacted(typ string) func(time.Time, []byte) {
+	return func(t time.Time, data []byte) {
+		a.shareMutex.Lock()
+		srv := a.share
+		a.shareMutex.Unlock()
+		if srv != nil {
+			srv.Broadcast(share.Message{Type: typ, Time: t, Data: data})
+		}
+	}
+}
+
+// redactFilter 导出抓包时按行脱敏，收发方向分别缓冲
+type redactFilter struct {
+	streams [2]*redact.Stream
+	out     []byte
+}
+
+func newRedactFilter(r *redact.Redactor) *redactFilter {
+	f := &redactFilter{}
+	for i := range f.streams {
+		f.streams[i] = redact.NewStream(r, 0, func(_ time.Time, data []byte) {
+			f.out = append(f.out, data...)
+		})
+	}
+	return f
+}
+
+func (f *redactFilter) Write(dir capture.Direction, data []byte) []byte {
+	f.out = f.out[:0]
+	f.streams[dir&1].Write(time.Time{}, data)
+	return f.out
+}
+
+func (f *redactFilter) Flush(dir capture.Direction) []byte {
+	f.out = f.out[:0]
+	f.streams[dir&1].Flush()
+	return f.out
+}
+
+// GetRedaction 返回脱敏设置
+func (a *App) GetRedaction() redact.Config {
+	a.redactMutex.Lock()
+	defer a.redactMutex.Unlock()
+	return a.redactCfg
+}
+
+// SetRedaction 设置并保存脱敏规则 (正则 → 遮盖文本)
+// 启用后导出抓包、共享会话与邮件报告中的匹配内容会被遮盖，本地原始抓包保持不变
+func (a *App) SetRedaction(cfg redact.Config) apperr.Result {
+	if err := cfg.Validate(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	if cfg.Rules == nil {
+		cfg.Rules = []redact.Rule{}
+	}
+	if err := redact.Save(filepath.Join(appDataDir(), redactionFile), cfg); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	a.applyRedaction(cfg)
+	return apperr.OK()
+}
+
+// PreviewRedaction 用给定规则处理示例文本，便于编辑规则时预览效果
+func (a *App) PreviewRedaction(rules []redact.Rule, text string) (string, error) {
+	r, err := redact.New(rules)
+	if err != nil {
+		return "", apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	return string(r.Apply([]byte(text))), nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/banner"
+	"serial-assistant/pkg/journal"
+	"serial-assistant/pkg/mailreport"
+	"serial-assistant/pkg/rules"
+	"serial-assistant/pkg/secrets"
+)
+
+// emailConfigFile 邮件报告配置文件名 (位于应用数据目录)
+const emailConfigFile = "email.json"
+
+// 报告的限制
+const (
+	maxReportAlerts = 200
+	reportTimeout   = time.Minute
+)
+
+// beginReport 记录新会话的起始状态
+func (a *App) beginReport(conn *journal.Connection) {
+	a.reportMutex.Lock()
+	defer a.reportMutex.Unlock()
+	a.reportStart = time.Now()
+	a.reportConn = conn.Type + " " + connectionTarget(conn)
+	a.reportMeta = banner.Metadata{}
+	a.reportRxMark = a.rxMark()
+	a.reportTx = 0
+	a.reportAlerts = nil
+}
+
+// recordAlert 记录规则触发，用于会话报告
+func (a *App) recordAlert(m rules.Match) {
+	a.alertTotal.Add(1)
+	a.reportMutex.Lock()
+	defer a.reportMutex.Unlock()
+	if len(a.reportAlerts) < maxReportAlerts {
+		a.reportAlerts = append(a.reportAlerts, m)
+	}
+}
+
+// countReportTx 累计本会话发送的字节数
+func (a *App) countReportTx(n int) {
+	a.reportMutex.Lock()
+	a.reportTx += uint64(n)
+	a.reportMutex.Unlock()
+	a.txTotal.Add(uint64(n))
+}
+
+// sessionSummary 汇总当前会话；尚未建立过连接时返回 false
+func (a *App) sessionSummary() (mailreport.Summary, bool) {
+	a.reportMutex.Lock()
+	defer a.reportMutex.Unlock()
+	if a.reportStart.IsZero() {
+		return mailreport.Summary{}, false
+	}
+	s := mailreport.Summary{
+		Connection:  a.reportConn,
+		Title:       a.reportMeta.Title,
+		Device:      a.reportMeta.Fields,
+		Variables:   a.vars.Values(),
+		Started:     a.reportStart,
+		Ended:       time.Now(),
+		RxBytes:     a.rxMark() - a.reportRxMark,
+		TxBytes:     a.reportTx,
+		Frames:      a.frameStats.Types(),
+		Alerts:      append([]rules.Match(nil), a.reportAlerts...),
+		Excerpt:     a.activeRedactor().Apply(a.rxSince(a.reportRxMark)),
+		ExcerptName: a.reportStart.Format("20060102-150405") + "-tail.log",
+	}
+	return s, true
+}
+
+// loadEmailConfig 读取邮件报告配置并从钥匙串取出 SMTP 密码
+// 旧版本保存在配置文件中的密码会迁移到钥匙串，迁移成功后从文件中删除
+func (a *App) loadEmailConfig() (mailreport.Config, error) {
+	path := filepath.Join(appDataDir(), emailConfigFile)
+	cfg, err := mailreport.Load(path)
+	if err != nil {
+		return cfg, err
+	}
+	if cfg.Password != "" {
+		if a.secrets.Set(smtpPasswordKey, cfg.Password) == nil {
+			stored := cfg
+			stored.Password = ""
+			mailreport.Save(path, stored)
+		}
+		return cfg, nil
+	}
+	if pw, err := a.secrets.Get(smtpPasswordKey); err == nil {
+		cfg.Password = pw
+	}
+	return cfg, nil
+}
+
+// endReport 会话结束时按配置在后台发送邮件报告
+func (a *App) endReport() {
+	cfg, err := a.loadEmailConfig()
+	if err != nil || !cfg.Enabled {
+		return
+	}
+	s, ok := a.sessionSummary()
+	if !ok {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+		defer cancel()
+		if err := sendReport(ctx, cfg, s); err != nil {
+			a.log("report").Warn("session report not sent", "err", err)
+		}
+	}()
+}
+
+func sendReport(ctx context.Context, cfg mailreport.Config, s mailreport.Summary) error {
+	msg, err := mailreport.Compose(cfg, s)
+	if err != nil {
+		return err
+	}
+	return mailreport.Send(ctx, cfg, msg)
+}
+
+// GetEmailConfig 返回邮件报告配置，密码不返回，仅以 passwordSet 表示是否已设置
+func (a *App) GetEmailConfig() (mailreport.Config, error) {
+	cfg, err := a.loadEmailConfig()
+	if err != nil {
+		return cfg, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	cfg.PasswordSet = cfg.Password != ""
+	cfg.Password = ""
+	return cfg, nil
+}
+
+// SetEmailConfig 保存邮件报告配置，SMTP 密码保存在系统钥匙串中
+// password 为空且 passwordSet 为 true 时保留原密码，passwordSet 为 false 时删除密码
+func (a *App) SetEmailConfig(cfg mailreport.Config) apperr.Result {
+	if cfg.Enabled {
+		if err := cfg.Validate(); err != nil {
+			return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+		}
+	}
+	switch {
+	case cfg.Password != "":
+		if err := a.secrets.Set(smtpPasswordKey, cfg.Password); err != nil {
+			return apperr.FromError(secretError(err))
+		}
+	case !cfg.PasswordSet:
+		if err := a.secrets.Delete(smtpPasswordKey); err != nil && !errors.Is(err, secrets.ErrNotFound) {
+			return apperr.FromError(secretError(err))
+		}
+	}
+	cfg.Password = ""
+	if err := mailreport.Save(filepath.Join(appDataDir(), emailConfigFile), cfg); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// SendSessionReport 立即发送当前会话的报告 (如测试序列完成时)，不要求开启自动发送
+func (a *App) SendSessionReport() apperr.Result {
+	cfg, err := a.loadEmailConfig()
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	if err := cfg.Validate(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	s, ok := a.sessionSummary()
+	if !ok {
+		return apperr.FromError(apperr.New(apperr.CodeNotFound, "no session"))
+	}
+
+	op := a.startOperation("email-report", reportTimeout)
+	defer a.ops.Finish(op)
+	if err := sendReport(op.Context(), cfg, s); err != nil {
+		op.Fail(err)
+		if op.Context().Err() != nil {
+			return apperr.FromError(contextError(op.Context()))
+		}
+		return apperr.FromError(apperr.Wrap(apperr.CodeConnectFailed, err))
+	}
+	return apperr.OK()
+}
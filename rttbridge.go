@@ -0,0 +1,100 @@
+package main
+
+import (
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/bridge"
+	"serial-assistant/pkg/jlink"
+
+	"go.bug.st/serial"
+)
+
+// RttBridgeStatus RTT 与串口桥接的状态
+type RttBridgeStatus struct {
+	Active bool         `json:"active"`
+	Probe  string       `json:"probe"` // 附加探针的标签，空表示主 RTT 连接
+	Port   string       `json:"port"`
+	Stats  bridge.Stats `json:"stats"`
+}
+
+// StartRttBridge 在 RTT 通道 0 与串口之间双向转发数据
+// probe 为附加探针的标签 (空表示主 RTT 连接)；portName 通常是虚拟串口对的一端，
+// 外部烧录工具、终端等只会说串口的程序打开另一端即可访问仅有 RTT 的目标
+func (a *App) StartRttBridge(probe, portName string, baudRate int) apperr.Result {
+	if portName == "" {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, portName))
+	}
+	if err := a.withProbe(probe, func(*jlink.JLinkWrapper) error { return nil }); err != nil {
+		return apperr.FromError(err)
+	}
+
+	a.bridgeMutex.Lock()
+	defer a.bridgeMutex.Unlock()
+	if a.bridge != nil {
+		return apperr.FromError(apperr.New(apperr.CodeAlreadyConnected, a.bridgePort))
+	}
+	if baudRate <= 0 {
+		baudRate = 115200
+	}
+	port, err := a.openSerialContext(portName, &serial.Mode{BaudRate: baudRate, DataBits: 8, Parity: serial.NoParity, StopBits: serial.OneStopBit})
+	if err != nil {
+		return apperr.FromError(err)
+	}
+
+	toTarget := func(data []byte) (int, error) {
+		var n int
+		err := a.withProbe(probe, func(jl *jlink.JLinkWrapper) error {
+			var werr error
+			n, werr = jl.WriteRTT(data)
+			return werr
+		})
+		return n, err
+	}
+	b := bridge.New(port, toTarget)
+	a.bridge, a.bridgeProbe, a.bridgePort = b, probe, portName
+	go func() {
+		<-b.Done()
+		b.Wait()
+		a.bridgeMutex.Lock()
+		if a.bridge == b {
+			a.bridge = nil
+		}
+		a.bridgeMutex.Unlock()
+	}()
+	return apperr.OK()
+}
+
+// StopRttBridge 停止桥接并关闭串口
+func (a *App) StopRttBridge() apperr.Result {
+	a.bridgeMutex.Lock()
+	b := a.bridge
+	a.bridge = nil
+	a.bridgeMutex.Unlock()
+	if b == nil {
+		return apperr.OK()
+	}
+	if err := b.Close(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeCloseFailed, err))
+	}
+	return apperr.OK()
+}
+
+// GetRttBridgeStatus 返回桥接状态与转发统计
+func (a *App) GetRttBridgeStatus() (RttBridgeStatus, error) {
+	a.bridgeMutex.Lock()
+	defer a.bridgeMutex.Unlock()
+	if a.bridge == nil {
+		return RttBridgeStatus{}, nil
+	}
+	return RttBridgeStatus{Active: true, Probe: a.bridgeProbe, Port: a.bridgePort, Stats: a.bridge.Stats()}, nil
+}
+
+// bridgeFromTarget 将探针读到的 RTT 数据交给桥接 (在 RTT 读取循环中调用，不阻塞)
+func (a *App) bridgeFromTarget(probe string, data []byte) {
+	a.bridgeMutex.Lock()
+	b := a.bridge
+	match := b != nil && a.bridgeProbe == probe
+	a.bridgeMutex.Unlock()
+	if match {
+		b.FromTarget(data)
+	}
+}
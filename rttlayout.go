@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/jlink"
+)
+
+// RttLayoutStatus 当前 RTT 连接的控制块布局及与固件 ELF 的差异
+type RttLayoutStatus struct {
+	Soft       bool             `json:"soft"`       // 使用软件 RTT (原生 RTT 由驱动自行解析控制块)
+	Background bool             `json:"background"` // 以后台方式访问运行中的目标
+	Layout     jlink.Layout     `json:"layout"`
+	Elf        *jlink.ElfConfig `json:"elf,omitempty"`
+	Mismatch   []string         `json:"mismatch"`
+}
+
+// SetRttElf 读取固件 ELF 中声明的 RTT 配置，之后的 RTT 连接 (含附加探针) 直接按其中的
+// 控制块地址查找，并校验目标上的缓冲区数量；固件修改 RTT 配置后不再悄然读错
+func (a *App) SetRttElf(path string) (jlink.ElfConfig, error) {
+	cfg, err := jlink.ReadElfConfig(path)
+	if errors.Is(err, jlink.ErrNoRTTSymbol) {
+		return jlink.ElfConfig{}, apperr.Wrap(apperr.CodeNotFound, err)
+	}
+	if err != nil {
+		return jlink.ElfConfig{}, apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	a.rttElfMutex.Lock()
+	a.rttElf = &cfg
+	a.rttElfMutex.Unlock()
+	return cfg, nil
+}
+
+// ClearRttElf 不再使用固件 ELF，恢复在内存中搜索控制块
+func (a *App) ClearRttElf() apperr.Result {
+	a.rttElfMutex.Lock()
+	a.rttElf = nil
+	a.rttElfMutex.Unlock()
+	return apperr.OK()
+}
+
+// GetRttLayout 返回主 RTT 连接的控制块布局
+func (a *App) GetRttLayout() (RttLayoutStatus, error) {
+	a.rttElfMutex.Lock()
+	elf := a.rttElf
+	a.rttElfMutex.Unlock()
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if !a.isConnected || a.connType != TypeJLink || a.jlinkConn == nil {
+		return RttLayoutStatus{}, apperr.New(apperr.CodeNotConnected, "")
+	}
+	layout, mismatch, soft := a.jlinkConn.Layout()
+	return RttLayoutStatus{
+		Soft: soft, Background: a.jlinkConn.BackgroundAccess(),
+		Layout: layout, Elf: elf, Mismatch: mismatch,
+	}, nil
+}
+
+// applyRttElf 将已设置的固件配置交给新建的驱动实例
+func (a *App) applyRttElf(jl *jlink.JLinkWrapper) {
+	a.rttElfMutex.Lock()
+	defer a.rttElfMutex.Unlock()
+	if a.rttElf != nil {
+		jl.SetElfConfig(*a.rttElf)
+	}
+}
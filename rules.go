@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/notify"
+	"serial-assistant/pkg/pipeline"
+	"serial-assistant/pkg/rules"
+	"serial-assistant/pkg/webhook"
+)
+
+// rulesFile 规则文件名 (位于应用数据目录)
+const rulesFile = "rules.json"
+
+// notifyInterval 桌面通知的最小间隔，与各规则自身的冷却时间叠加
+const notifyInterval = 3 * time.Second
+
+// newRulesEngine 创建规则引擎并注册内置动作
+func (a *App) newRulesEngine() *rules.Engine {
+	e := rules.New(context.Background(), func(m rules.Match, action rules.Action, err error) {
+		a.log("rules").Warn("rule action failed", "rule", m.RuleName, "action", action.Type, "err", err)
+	})
+	e.Observe(a.recordAlert)
+	e.Handle("notify", a.notifyAction)
+	e.Handle("sound", soundAction)
+	e.Handle("speak", speakAction)
+	e.Handle("probe-power", a.probePowerAction)
+	e.Handle("set-var", a.setVarAction)
+	client := &http.Client{}
+	e.Handle("webhook", a.withSecrets(webhook.Generic(client)))
+	e.Handle("slack", a.withSecrets(webhook.Slack(client)))
+	e.Handle("telegram", a.withSecrets(webhook.Telegram(client)))
+	return e
+}
+
+// notifyAction 显示系统通知，窗口最小化时也能提醒用户
+// 参数 title 为通知标题，缺省为规则名
+func (a *App) notifyAction(ctx context.Context, m rules.Match, action rules.Action) error {
+	title := action.Params["title"]
+	if title == "" {
+		title = m.RuleName
+	}
+	body := m.Line
+	if m.Suppressed > 0 {
+		body += "\n" + i18n.T("rules.suppressed", m.Suppressed)
+	}
+	_, err := a.notifier.Notify(ctx, title, body)
+	return err
+}
+
+// soundAction 播放提示音，参数 file 为声音文件 (缺省为系统提示音)
+func soundAction(ctx context.Context, m rules.Match, action rules.Action) error {
+	return notify.PlaySound(ctx, action.Params["file"])
+}
+
+// speakAction 朗读提示，用于操作员不在屏幕前的实验室环境
+// 参数 text 为朗读内容，缺省为规则名
+func speakAction(ctx context.Context, m rules.Match, action rules.Action) error {
+	text := action.Params["text"]
+	if text == "" {
+		text = m.RuleName
+	}
+	return notify.Speak(ctx, text)
+}
+
+// applyRules 将接收数据交给规则引擎
+func (a *App) applyRules(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		buf, ok := ev.Payload.(*bufpool.Buffer)
+		if !ok {
+			return
+		}
+		a.rules.Feed(ev.Time, buf.B)
+		buf.Release()
+	})
+}
+
+// loadRules 启动时加载保存的规则
+func (a *App) loadRules() {
+	list, err := rules.Load(filepath.Join(appDataDir(), rulesFile))
+	if err == nil {
+		err = a.rules.SetRules(list)
+	}
+	if err != nil {
+		a.log("rules").Warn("rules not loaded", "err", err)
+	}
+}
+
+// GetRules 返回当前的规则
+func (a *App) GetRules() []rules.Rule {
+	return a.rules.Rules()
+}
+
+// SetRules 替换并保存全部规则
+func (a *App) SetRules(list []rules.Rule) apperr.Result {
+	if err := a.rules.SetRules(list); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	if err := rules.Save(filepath.Join(appDataDir(), rulesFile), list); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
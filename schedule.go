@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/convert"
+	"serial-assistant/pkg/txsched"
+)
+
+// ScheduledFrame 计划在指定时刻发送的一帧
+type ScheduledFrame struct {
+	OffsetUs int64  `json:"offsetUs"` // 相对开始时刻的偏移 (微秒)
+	Data     string `json:"data"`
+	Hex      bool   `json:"hex"` // Data 为十六进制字符串
+}
+
+// ScheduleTransmission 按单调时钟在精确的时刻依次发送各帧 (误差约 ±1ms)，用于复现对时序敏感的总线序列
+// 调用会阻塞到全部发送完成，期间可通过 Cancel 取消；返回每帧的计划与实际发送时刻
+func (a *App) ScheduleTransmission(frames []ScheduledFrame) ([]txsched.Result, error) {
+	return a.ScheduleTransmissionUntil(frames, StopConditions{})
+}
+
+// ScheduleTransmissionUntil 与 ScheduleTransmission 相同，但在满足停止条件 (如设备报错或重启) 时中止序列，
+// 返回已发送部分的结果与 CANCELED 错误
+func (a *App) ScheduleTransmissionUntil(frames []ScheduledFrame, stop StopConditions) ([]txsched.Result, error) {
+	entries := make([]txsched.Entry, len(frames))
+	for i, f := range frames {
+		data := []byte(f.Data)
+		if f.Hex {
+			var err error
+			if data, err = convert.ParseHex(f.Data); err != nil {
+				return nil, apperr.Wrap(apperr.CodeInvalidArgument, err)
+			}
+		}
+		entries[i] = txsched.Entry{At: time.Duration(f.OffsetUs) * time.Microsecond, Data: data}
+	}
+
+	op := a.startOperation("schedule", 0)
+	defer a.ops.Finish(op)
+	ctx := op.Context()
+	done, err := a.watchStop("schedule", &stop, func() { a.ops.Cancel(op.ID) })
+	if err != nil {
+		return nil, err
+	}
+	results, err := txsched.Run(ctx, entries, func(b []byte) error {
+		return a.writeContext(ctx, b)
+	}, func(r txsched.Result) {
+		op.Report("send", int64(r.Index+1), int64(len(entries)))
+	})
+	stopped := done()
+	switch {
+	case errors.Is(err, txsched.ErrUnordered):
+		return nil, apperr.Wrap(apperr.CodeInvalidArgument, err)
+	case err != nil:
+		op.Fail(err)
+		if stopped != nil {
+			return results, stopped.err()
+		}
+		return results, contextError(ctx)
+	}
+	return results, nil
+}
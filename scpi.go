@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/pipeline"
+	"serial-assistant/pkg/scpi"
+	"serial-assistant/pkg/series"
+)
+
+// scpiQueryTimeout 等待 SCPI 应答的最长时间
+const scpiQueryTimeout = 2 * time.Second
+
+// feedScpi 将接收数据交给 SCPI 会话配对应答
+func (a *App) feedScpi(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		buf, ok := ev.Payload.(*bufpool.Buffer)
+		if !ok {
+			return
+		}
+		a.scpi.Feed(buf.B)
+		buf.Release()
+	})
+}
+
+// scpiError 将查询错误转换为结构化错误
+func scpiError(ctx context.Context, err error) *apperr.Error {
+	var e *apperr.Error
+	switch {
+	case errors.As(err, &e):
+		return e
+	case ctx.Err() != nil:
+		return contextError(ctx)
+	}
+	return apperr.Wrap(apperr.CodeProbeFailed, err)
+}
+
+// identifyInstrument 开启自动识别时，连接后查询 *IDN? 并发布 "instrument-identified" 事件
+func (a *App) identifyInstrument() {
+	a.scpiMutex.Lock()
+	enabled := a.scpiAutoIdentify
+	a.scpiMutex.Unlock()
+	if !enabled {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), scpiQueryTimeout)
+		defer cancel()
+		if id, err := a.scpi.Identify(ctx); err == nil {
+			a.bus.Publish(EventInstrumentIdentified, id)
+		}
+	}()
+}
+
+// SetScpiAutoIdentify 开启后每次连接都会发送 *IDN? 识别仪器，对非 SCPI 设备请勿开启
+func (a *App) SetScpiAutoIdentify(enabled bool) {
+	a.scpiMutex.Lock()
+	a.scpiAutoIdentify = enabled
+	a.scpiMutex.Unlock()
+}
+
+// SetScpiTerminators 设置 SCPI 命令与应答的结束符，为空时使用 "\n"
+func (a *App) SetScpiTerminators(write string, read string) {
+	a.scpi.SetTerminators(write, read)
+}
+
+// ScpiCommand 发送不需要应答的 SCPI 命令 (如 "VOLT 3.3")
+func (a *App) ScpiCommand(cmd string) apperr.Result {
+	op := a.startOperation("scpi", scpiQueryTimeout)
+	defer a.ops.Finish(op)
+	if err := a.scpi.Command(op.Context(), cmd); err != nil {
+		return apperr.FromError(scpiError(op.Context(), err))
+	}
+	return apperr.OK()
+}
+
+// ScpiQuery 发送 SCPI 查询并返回一行应答
+func (a *App) ScpiQuery(query string) (string, error) {
+	op := a.startOperation("scpi", scpiQueryTimeout)
+	defer a.ops.Finish(op)
+	resp, err := a.scpi.Query(op.Context(), query)
+	if err != nil {
+		return "", scpiError(op.Context(), err)
+	}
+	return resp, nil
+}
+
+// ScpiIdentify 查询 *IDN? 识别仪器
+func (a *App) ScpiIdentify() (scpi.Identity, error) {
+	op := a.startOperation("scpi", scpiQueryTimeout)
+	defer a.ops.Finish(op)
+	id, err := a.scpi.Identify(op.Context())
+	if err != nil {
+		return scpi.Identity{}, scpiError(op.Context(), err)
+	}
+	return id, nil
+}
+
+// ScpiErrors 读空仪器的错误队列 (SYST:ERR?)
+func (a *App) ScpiErrors() ([]scpi.Error, error) {
+	op := a.startOperation("scpi", 4*scpiQueryTimeout)
+	defer a.ops.Finish(op)
+	list, err := a.scpi.Errors(op.Context())
+	if err != nil {
+		return list, scpiError(op.Context(), err)
+	}
+	return list, nil
+}
+
+// StartScpiLogger 在后台按间隔轮询配置的 SCPI 查询，读数以 "series-sample" 事件发布
+// 已有记录器在运行时先停止；可用 StopScpiLogger 或 Cancel 停止
+func (a *App) StartScpiLogger(cfg scpi.LoggerConfig) apperr.Result {
+	if err := cfg.Validate(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	a.StopScpiLogger()
+
+	op := a.startOperation("scpi-logger", 0)
+	a.scpiMutex.Lock()
+	a.scpiLogger = op
+	a.scpiMutex.Unlock()
+	go func() {
+		defer a.ops.Finish(op)
+		scpi.Log(op.Context(), a.scpi, cfg, scpiQueryTimeout,
+			func(s series.Sample) { a.bus.Publish(EventSeriesSample, s) },
+			func(err error) { a.bus.Publish(EventSysMsg, i18n.T("app.scpi_error", err)) })
+	}()
+	return apperr.OK()
+}
+
+// StopScpiLogger 停止 SCPI 测量记录器
+func (a *App) StopScpiLogger() apperr.Result {
+	a.scpiMutex.Lock()
+	op := a.scpiLogger
+	a.scpiLogger = nil
+	a.scpiMutex.Unlock()
+	if op != nil {
+		a.ops.Cancel(op.ID)
+	}
+	return apperr.OK()
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/rules"
+	"serial-assistant/pkg/secrets"
+)
+
+// smtpPasswordKey SMTP 密码在钥匙串中的名称
+const smtpPasswordKey = "smtp/password"
+
+// secretError 将钥匙串错误转换为结构化错误
+func secretError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, secrets.ErrNotFound):
+		return apperr.Wrap(apperr.CodeNotFound, err)
+	case errors.Is(err, secrets.ErrInvalidKey):
+		return apperr.Wrap(apperr.CodeInvalidArgument, err)
+	case errors.Is(err, secrets.ErrUnavailable):
+		return apperr.Wrap(apperr.CodeUnsupported, err)
+	}
+	return apperr.Wrap(apperr.CodeInternal, err)
+}
+
+// SetSecret 将 API 令牌、设备凭据等保存到系统钥匙串 (Windows 凭据管理器、macOS 钥匙串、libsecret)
+// 规则动作参数写成 "secret:<name>" 即可引用，配置文件中不出现明文
+func (a *App) SetSecret(name, value string) apperr.Result {
+	return apperr.FromError(secretError(a.secrets.Set(name, value)))
+}
+
+// DeleteSecret 从系统钥匙串删除条目，条目不存在时也返回成功
+func (a *App) DeleteSecret(name string) apperr.Result {
+	err := a.secrets.Delete(name)
+	if errors.Is(err, secrets.ErrNotFound) {
+		return apperr.OK()
+	}
+	return apperr.FromError(secretError(err))
+}
+
+// HasSecret 返回钥匙串中是否有该条目 (不返回内容)
+func (a *App) HasSecret(name string) (bool, error) {
+	_, err := a.secrets.Get(name)
+	if errors.Is(err, secrets.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, secretError(err)
+	}
+	return true, nil
+}
+
+// withSecrets 执行规则动作前将 "secret:<name>" 形式的参数替换为钥匙串中的内容
+func (a *App) withSecrets(h rules.Handler) rules.Handler {
+	return func(ctx context.Context, m rules.Match, action rules.Action) error {
+		params, err := secrets.Resolve(a.secrets, action.Params)
+		if err != nil {
+			return err
+		}
+		action.Params = params
+		return h(ctx, m, action)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"time"
+
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/pipeline"
+	"serial-assistant/pkg/series"
+)
+
+// recordSeries 保存 "series-sample" 事件中的采样点，前端同时收到同一事件用于实时绘图
+func (a *App) recordSeries(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		if s, ok := ev.Payload.(series.Sample); ok {
+			a.series.Add(s)
+		}
+	})
+}
+
+// publishSample 发布一个当前时刻的采样点
+func (a *App) publishSample(name string, value float64, unit string) {
+	a.bus.Publish(EventSeriesSample, series.Sample{Series: name, Time: time.Now(), Value: value, Unit: unit})
+}
+
+// ListSeries 返回所有时间序列的概况
+func (a *App) ListSeries() []series.Info {
+	return a.series.List()
+}
+
+// GetSeries 返回序列中晚于 sinceMs (Unix 毫秒，0 表示全部) 的采样点
+func (a *App) GetSeries(name string, sinceMs int64) []series.Sample {
+	var since time.Time
+	if sinceMs > 0 {
+		since = time.UnixMilli(sinceMs)
+	}
+	return a.series.Get(name, since)
+}
+
+// ClearSeries 清空指定序列，name 为空时清空全部
+func (a *App) ClearSeries(name string) {
+	a.series.Clear(name)
+}
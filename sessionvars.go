@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/journal"
+	"serial-assistant/pkg/rules"
+	"serial-assistant/pkg/sessionvars"
+)
+
+// newSessionVars 创建会话变量表，供发送模板 ({{var.NAME}}) 使用，变化时发布 "session-vars" 事件
+func (a *App) newSessionVars() *sessionvars.Store {
+	s := sessionvars.New()
+	s.OnChange(func() {
+		a.bus.Publish(EventSessionVars, s.List())
+	})
+	a.payloads.SetVars(s.Get)
+	return s
+}
+
+// resetSessionVars 连接到与上次不同的对象时清空会话变量；自动重连或重新打开同一串口时保留
+func (a *App) resetSessionVars(conn *journal.Connection) {
+	target := conn.Type + " " + connectionTarget(conn)
+	a.varsMutex.Lock()
+	changed := a.varsTarget != target
+	a.varsTarget = target
+	a.varsMutex.Unlock()
+	if changed {
+		a.vars.Clear()
+	}
+}
+
+// setVarAction 规则动作：从命中的行中提取值保存为会话变量
+// 参数 name 为变量名；value 为取值模板，$0 为整行，$1-$9 为捕获组，缺省取第一个捕获组 (没有时取整行)
+func (a *App) setVarAction(ctx context.Context, m rules.Match, action rules.Action) error {
+	return a.vars.Set(action.Params["name"], sessionvars.Capture(action.Params["value"], m.Line, m.Groups), sessionvars.SourceRule+":"+m.RuleName)
+}
+
+// expandVars 展开命令中的 {{var.NAME}}，其他内容原样保留
+func (a *App) expandVars(text string) (string, error) {
+	s, err := a.vars.Expand(text)
+	if err != nil {
+		return "", apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	return s, nil
+}
+
+// GetSessionVars 返回当前会话的变量，按名称排序
+func (a *App) GetSessionVars() []sessionvars.Var {
+	return a.vars.List()
+}
+
+// SetSessionVar 设置会话变量，可在发送内容、命令与会话报告中以 {{var.NAME}} 引用
+// 连接到其他设备时自动清空
+func (a *App) SetSessionVar(name string, value string) apperr.Result {
+	if err := a.vars.Set(name, value, sessionvars.SourceManual); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	return apperr.OK()
+}
+
+// DeleteSessionVar 删除会话变量
+func (a *App) DeleteSessionVar(name string) apperr.Result {
+	if !a.vars.Delete(name) {
+		return apperr.FromError(apperr.New(apperr.CodeNotFound, name))
+	}
+	return apperr.OK()
+}
+
+// ClearSessionVars 删除全部会话变量
+func (a *App) ClearSessionVars() apperr.Result {
+	a.vars.Clear()
+	return apperr.OK()
+}
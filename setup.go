@@ -0,0 +1,130 @@
+package main
+
+import (
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/permissions"
+	"serial-assistant/pkg/ports"
+	"serial-assistant/pkg/setup"
+)
+
+// setupFile 首次运行向导状态文件名 (位于应用数据目录)
+const setupFile = "setup.json"
+
+// setupListen 试打开串口后等待设备输出的时间
+const setupListen = 1500 * time.Millisecond
+
+// GetSetupState 返回向导状态，Completed 为 false 时前端应显示首次运行向导
+func (a *App) GetSetupState() (setup.State, error) {
+	s, err := setup.Load(filepath.Join(appDataDir(), setupFile))
+	if err != nil {
+		return s, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return s, nil
+}
+
+// DetectSetupDevices 枚举并识别已连接的串口设备，最可能的设备在前，并附带建议的串口参数
+func (a *App) DetectSetupDevices() ([]setup.Device, error) {
+	list, err := ports.List()
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return setup.Rank(list), nil
+}
+
+// TestSetupPort 检查所选串口的访问权限与驱动，试打开并短暂监听设备输出以判断波特率是否合适
+// 只接收不发送；该串口已在本程序中打开时不再试打开
+func (a *App) TestSetupPort(s setup.Settings) (setup.Report, error) {
+	if err := ports.ValidateBaudRate(s.BaudRate); err != nil {
+		return setup.Report{}, apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	if !ports.ValidFlow(s.FlowControl) {
+		return setup.Report{}, apperr.New(apperr.CodeInvalidArgument, "flowControl: "+s.FlowControl)
+	}
+	r := setup.Report{Settings: s, Checks: []setup.Check{}}
+	perm := a.DiagnosePortAccess(s.Port)
+	r.Add(setup.PermissionCheck(perm))
+	r.Add(setup.DriverCheck(a.GetDriverHealth(s.Port)))
+	if !perm.Exists {
+		return r, nil
+	}
+
+	a.mutex.Lock()
+	inUse := a.isConnected && a.connType == TypeSerial && a.serialPortName == s.Port
+	a.mutex.Unlock()
+	if inUse {
+		r.Add(setup.Check{ID: setup.CheckOpen, Status: setup.StatusPass, Message: i18n.T("setup.open_in_use", s.Port)})
+		return r, nil
+	}
+	data, err := a.testOpen(s)
+	if err != nil {
+		c := setup.Check{ID: setup.CheckOpen, Status: setup.StatusFail, Message: i18n.T("setup.open_failed", s.Port, err)}
+		if apperr.CodeOf(err) == apperr.CodePermissionDenied {
+			c.Hints = permissions.Diagnose(s.Port).Commands
+		}
+		r.Add(c)
+		return r, nil
+	}
+	r.Add(setup.Check{ID: setup.CheckOpen, Status: setup.StatusPass, Message: i18n.T("setup.open_ok", s.Port, s.BaudRate)})
+	r.Add(setup.DataCheck(data, setupListen))
+	return r, nil
+}
+
+// testOpen 按参数打开串口并在 setupListen 内收集设备输出，随后关闭
+func (a *App) testOpen(s setup.Settings) ([]byte, error) {
+	mode, custom := serialMode(s.BaudRate, s.DataBits, s.StopBits, s.Parity)
+	port, err := a.openSerialContext(s.Port, mode)
+	if err != nil {
+		return nil, err
+	}
+	defer port.Close()
+	if custom {
+		if err := applyCustomBaudRate(port, s.BaudRate); err != nil {
+			return nil, err
+		}
+	}
+	if s.FlowControl == ports.FlowRTSCTS {
+		if err := ports.SetFlowControl(port, true); err != nil {
+			return nil, apperr.Wrap(apperr.CodeUnsupported, err)
+		}
+	}
+
+	var data []byte
+	buf := make([]byte, 1024)
+	deadline := time.Now().Add(setupListen)
+	for {
+		left := time.Until(deadline)
+		if left <= 0 || len(data) >= 4096 {
+			return data, nil
+		}
+		port.SetReadTimeout(left)
+		n, err := port.Read(buf)
+		if err != nil {
+			return data, nil
+		}
+		data = append(data, buf[:n]...)
+	}
+}
+
+// CompleteSetup 保存向导结果，之后启动不再显示向导
+func (a *App) CompleteSetup(s setup.Settings) apperr.Result {
+	if err := ports.ValidateBaudRate(s.BaudRate); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	st := setup.State{Completed: true, CompletedAt: time.Now(), Settings: s}
+	if err := setup.Save(filepath.Join(appDataDir(), setupFile), st); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// ResetSetup 清除向导结果，下次启动重新显示向导
+func (a *App) ResetSetup() apperr.Result {
+	if err := setup.Save(filepath.Join(appDataDir(), setupFile), setup.State{}); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
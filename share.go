@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/audit"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/capture"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/pipeline"
+	"serial-assistant/pkg/share"
+)
+
+// defaultSharePort 未指定端口时共享服务器监听的端口
+const defaultSharePort = 8765
+
+// ShareInfo 会话共享的状态
+type ShareInfo struct {
+	Running bool           `json:"running"`
+	URLs    []string       `json:"urls"` // 可发给同事的访问地址 (已包含令牌)
+	Token   string         `json:"token"`
+	Viewers []share.Viewer `json:"viewers"`
+}
+
+// forwardToViewers 将收发数据与系统消息推送给共享会话的观看者
+func (a *App) forwardToViewers(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		a.shareMutex.Lock()
+		srv := a.share
+		a.shareMutex.Unlock()
+
+		switch p := ev.Payload.(type) {
+		case *bufpool.Buffer:
+			if srv != nil {
+				typ, dir := share.TypeRx, capture.RX
+				if ev.Topic == EventDataSent {
+					typ, dir = share.TypeTx, capture.TX
+				}
+				if s := a.shareStream(dir); s != nil {
+					s.Write(ev.Time, p.B)
+				} else {
+					srv.Broadcast(share.Message{Type: typ, Time: ev.Time, Data: append([]byte(nil), p.B...)})
+				}
+			}
+			p.Release()
+		case string:
+			if srv != nil {
+				text := string(a.activeRedactor().Apply([]byte(p)))
+				srv.Broadcast(share.Message{Type: share.TypeSys, Time: ev.Time, Text: text})
+			}
+		}
+	})
+}
+
+// StartSharing 启动会话共享服务器，同事用浏览器打开返回的地址即可实时查看收发数据
+// 观看者默认只读，可申请控制权 ("control-requested" 事件)，由主机通过 GrantControl 授予
+// 也可使用 API 密钥 (CreateAPIKey) 连接，每个远程命令都记入审计日志
+// port 为 0 时使用默认端口 8765
+func (a *App) StartSharing(port int) (ShareInfo, error) {
+	if port < 0 || port > 65535 {
+		return ShareInfo{}, apperr.New(apperr.CodeInvalidArgument, strconv.Itoa(port))
+	}
+	if port == 0 {
+		port = defaultSharePort
+	}
+	a.shareMutex.Lock()
+	defer a.shareMutex.Unlock()
+	if a.share != nil {
+		return a.shareInfoLocked(), nil
+	}
+	srv := share.New(share.NewToken())
+	srv.SetHandlers(share.Handlers{
+		OnRequest: func(v share.Viewer) {
+			a.bus.Publish(EventControlRequested, v)
+		},
+		OnSend:    a.remoteSend,
+		OnCommand: a.auditCommand,
+	})
+	srv.SetKeyFunc(a.authenticateKey)
+	addr, err := srv.Start(fmt.Sprintf(":%d", port))
+	if err != nil {
+		return ShareInfo{}, apperr.Wrap(apperr.CodeListenFailed, err)
+	}
+	if log, err := audit.Open(filepath.Join(captureDir(), auditFile)); err == nil {
+		a.audit = log
+	} else {
+		a.log("share").Warn("audit log not opened", "err", err)
+	}
+	a.share = srv
+	a.sharePort = addr.(*net.TCPAddr).Port
+	return a.shareInfoLocked(), nil
+}
+
+// StopSharing 停止会话共享并断开所有观看者
+func (a *App) StopSharing() apperr.Result {
+	a.shareMutex.Lock()
+	srv := a.share
+	log := a.audit
+	a.share = nil
+	a.audit = nil
+	a.shareMutex.Unlock()
+	if srv != nil {
+		srv.Stop()
+	}
+	if log != nil {
+		log.Close()
+	}
+	return apperr.OK()
+}
+
+// remoteSend 发送拥有控制权的观看者提交的数据，每一帧都注明发送者并记录
+// 应用日志会进入诊断包，只记录长度，不记录未经脱敏的内容
+func (a *App) remoteSend(v share.Viewer, data []byte) error {
+	op := a.startOperation("remote-send", sendTimeout)
+	defer a.ops.Finish(op)
+	if err := a.writeContext(op.Context(), data); err != nil {
+		return err
+	}
+	msg := i18n.T("app.remote_sent", v.Label(), len(data))
+	a.log("share").Info(msg, "viewer", v.ID, "bytes", len(data))
+	a.bus.Publish(EventSysMsg, msg)
+	return nil
+}
+
+// GrantControl 将发送权限授予申请控制权的观看者 (同一时间只有一个)
+func (a *App) GrantControl(viewerID string) apperr.Result {
+	a.shareMutex.Lock()
+	srv := a.share
+	a.shareMutex.Unlock()
+	if srv == nil {
+		return apperr.FromError(apperr.New(apperr.CodeNotFound, "sharing is not running"))
+	}
+	if err := srv.Grant(viewerID); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeNotFound, err))
+	}
+	return apperr.OK()
+}
+
+// RevokeControl 收回观看者的发送权限并拒绝未处理的申请
+func (a *App) RevokeControl() apperr.Result {
+	a.shareMutex.Lock()
+	srv := a.share
+	a.shareMutex.Unlock()
+	if srv != nil {
+		srv.Revoke()
+	}
+	return apperr.OK()
+}
+
+// GetShareStatus 返回会话共享的状态与当前观看者
+func (a *App) GetShareStatus() ShareInfo {
+	a.shareMutex.Lock()
+	defer a.shareMutex.Unlock()
+	return a.shareInfoLocked()
+}
+
+func (a *App) shareInfoLocked() ShareInfo {
+	if a.share == nil {
+		return ShareInfo{}
+	}
+	info := ShareInfo{Running: true, Token: a.share.Token(), Viewers: a.share.Viewers()}
+	for _, ip := range localAddresses() {
+		info.URLs = append(info.URLs, fmt.Sprintf("http://%s/?token=%s", net.JoinHostPort(ip, strconv.Itoa(a.sharePort)), info.Token))
+	}
+	return info
+}
+
+// localAddresses 返回本机非回环的 IPv4 地址，没有时返回 localhost
+func localAddresses() []string {
+	var ips []string
+	addrs, _ := net.InterfaceAddrs()
+	for _, addr := range addrs {
+		if n, ok := addr.(*net.IPNet); ok && !n.IP.IsLoopback() && n.IP.To4() != nil {
+			ips = append(ips, n.IP.String())
+		}
+	}
+	if len(ips) == 0 {
+		ips = append(ips, "localhost")
+	}
+	return ips
+}
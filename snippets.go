@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/snippets"
+)
+
+// snippetsFile 本地片段与仓库地址的保存文件 (位于应用数据目录)
+const snippetsFile = "snippets.json"
+
+// snippetSyncTimeout 从仓库同步片段的超时
+const snippetSyncTimeout = 2 * time.Minute
+
+// snippetRepoDir 同步仓库的本地克隆目录
+func snippetRepoDir() string {
+	return filepath.Join(appDataDir(), "snippets-repo")
+}
+
+// loadSnippets 启动时加载本地片段及上次同步的仓库内容 (不联网)
+func (a *App) loadSnippets() {
+	cfg, err := snippets.Load(filepath.Join(appDataDir(), snippetsFile))
+	if err == nil {
+		err = a.snippets.SetLocal(cfg.Snippets)
+	}
+	if err == nil && cfg.Repo != "" {
+		var list []snippets.Snippet
+		if list, err = snippets.ReadDir(snippetRepoDir()); err == nil {
+			a.snippets.SetRemote(list)
+		}
+	}
+	if err != nil {
+		a.log("snippets").Warn("snippets not loaded", "err", err)
+	}
+}
+
+// GetSnippets 返回全部片段，本地片段在前
+func (a *App) GetSnippets() []snippets.Snippet {
+	return a.snippets.All()
+}
+
+// SaveSnippets 替换并保存本地片段
+func (a *App) SaveSnippets(list []snippets.Snippet) apperr.Result {
+	if err := a.snippets.SetLocal(list); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	path := filepath.Join(appDataDir(), snippetsFile)
+	cfg, err := snippets.Load(path)
+	if err == nil {
+		cfg.Snippets = a.snippets.Local()
+		err = snippets.Save(path, cfg)
+	}
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// GetSnippetRepo 返回同步片段的仓库地址，未配置时为空
+func (a *App) GetSnippetRepo() string {
+	cfg, _ := snippets.Load(filepath.Join(appDataDir(), snippetsFile))
+	return cfg.Repo
+}
+
+// SyncSnippets 从 git 仓库克隆或更新团队共享的片段库
+// url 为空时使用上次配置的仓库；同步成功后保存仓库地址
+func (a *App) SyncSnippets(url string) apperr.Result {
+	path := filepath.Join(appDataDir(), snippetsFile)
+	cfg, err := snippets.Load(path)
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	if url == "" {
+		url = cfg.Repo
+	}
+	if url == "" {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, "no repository configured"))
+	}
+
+	op := a.startOperation("snippet-sync", snippetSyncTimeout)
+	defer a.ops.Finish(op)
+	list, err := snippets.Sync(op.Context(), url, snippetRepoDir())
+	if err != nil {
+		op.Fail(err)
+		switch {
+		case op.Context().Err() != nil:
+			return apperr.FromError(contextError(op.Context()))
+		case errors.Is(err, snippets.ErrNoGit):
+			return apperr.FromError(apperr.Wrap(apperr.CodeUnsupported, err))
+		}
+		return apperr.FromError(apperr.Wrap(apperr.CodeConnectFailed, err))
+	}
+	a.snippets.SetRemote(list)
+	if cfg.Repo != url {
+		cfg.Repo = url
+		if err := snippets.Save(path, cfg); err != nil {
+			return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+		}
+	}
+	return apperr.OK()
+}
+
+// SearchSnippets 按关键字与标签搜索片段，名称匹配的排在前面
+func (a *App) SearchSnippets(query string, tags []string) []snippets.Snippet {
+	return a.snippets.Search(query, tags)
+}
+
+// GetSnippetTags 返回片段使用的全部标签
+func (a *App) GetSnippetTags() []string {
+	return a.snippets.Tags()
+}
+
+// InsertSnippet 返回要插入发送框的片段 (内容与是否为十六进制)
+func (a *App) InsertSnippet(id string) (snippets.Snippet, error) {
+	s, err := a.snippets.Get(id)
+	if err != nil {
+		return snippets.Snippet{}, apperr.Wrap(apperr.CodeNotFound, err)
+	}
+	return s, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/soak"
+)
+
+// soakDirName 浸泡测试报告目录 (位于应用数据目录)
+const soakDirName = "soak"
+
+// SoakConfig 浸泡测试设置
+type SoakConfig struct {
+	Name        string `json:"name"`
+	DurationMin int    `json:"durationMin"` // 运行时长 (分钟)
+	IntervalSec int    `json:"intervalSec"` // 快照间隔 (秒)
+}
+
+// soakSample 采样跨会话累计的健康计数
+func (a *App) soakSample() soak.Sample {
+	var s soak.Sample
+	s.RxBytes = a.rxMark()
+	s.TxBytes = a.txTotal.Load()
+	for _, t := range a.frameStats.Types() {
+		s.Frames += t.Count
+		s.FrameErrors += t.Errors
+	}
+	s.Reboots = a.bootLoop.Boots()
+	s.Crashes = a.crashTotal.Load()
+	s.Alerts = a.alertTotal.Load()
+	s.Disconnects = a.disconnectTotal.Load()
+	a.mutex.Lock()
+	s.Connected = a.isConnected
+	a.mutex.Unlock()
+	return s
+}
+
+// StartSoakTest 开始浸泡测试：运行 durationMin 分钟，每 intervalSec 秒记录吞吐量、帧错误、重启、崩溃与告警，
+// 快照通过 "soak-snapshot" 事件发送；结束时保存报告并发送 "soak-finished" 事件。
+// 测试与连接相互独立，期间断开重连会计入报告
+func (a *App) StartSoakTest(cfg SoakConfig) apperr.Result {
+	duration := time.Duration(cfg.DurationMin) * time.Minute
+	interval := time.Duration(cfg.IntervalSec) * time.Second
+	if duration <= 0 || interval < soak.MinInterval || interval > duration {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, "invalid soak duration or interval"))
+	}
+
+	a.soakMutex.Lock()
+	defer a.soakMutex.Unlock()
+	if a.soakOp != nil {
+		return apperr.FromError(apperr.New(apperr.CodeInvalidArgument, "soak test already running"))
+	}
+	op := a.startOperation("soak", 0)
+	a.soakOp = op
+	go func() {
+		defer a.ops.Finish(op)
+		s := soak.Run(op.Context(), cfg.Name, duration, interval, a.soakSample, func(sn soak.Snapshot) {
+			op.Report("soak", sn.Elapsed, int64(duration/time.Second))
+			a.bus.Publish(EventSoakSnapshot, sn)
+		})
+		a.soakMutex.Lock()
+		a.soakOp = nil
+		a.soakMutex.Unlock()
+		if err := soak.Save(filepath.Join(appDataDir(), soakDirName), s); err != nil {
+			op.Fail(err)
+		}
+		a.bus.Publish(EventSoakFinished, s)
+	}()
+	return apperr.OK()
+}
+
+// StopSoakTest 提前结束浸泡测试，已采集的数据仍会生成报告
+func (a *App) StopSoakTest() apperr.Result {
+	a.soakMutex.Lock()
+	op := a.soakOp
+	a.soakMutex.Unlock()
+	if op == nil {
+		return apperr.FromError(apperr.New(apperr.CodeNotFound, "soak"))
+	}
+	a.ops.Cancel(op.ID)
+	return apperr.OK()
+}
+
+// ListSoakReports 列出保存的浸泡测试汇总 (不含快照)，从新到旧排序
+func (a *App) ListSoakReports() ([]soak.Summary, error) {
+	list, err := soak.List(filepath.Join(appDataDir(), soakDirName))
+	if err != nil {
+		return nil, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return list, nil
+}
+
+// GetSoakReport 返回一次浸泡测试的完整汇总，包括各区间的快照
+func (a *App) GetSoakReport(id string) (soak.Summary, error) {
+	s, err := soak.Load(filepath.Join(appDataDir(), soakDirName), id)
+	switch {
+	case errors.Is(err, soak.ErrNotFound):
+		return s, apperr.New(apperr.CodeNotFound, id)
+	case err != nil:
+		return s, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return s, nil
+}
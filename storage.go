@@ -0,0 +1,167 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/capture"
+	"serial-assistant/pkg/retention"
+)
+
+// retentionFile 保留策略文件名 (位于应用数据目录)
+const retentionFile = "retention.json"
+
+// bookmarksFile 加书签的会话列表文件名 (位于抓包目录)
+const bookmarksFile = "bookmarks.json"
+
+// CleanupResult 一次清理的结果
+type CleanupResult struct {
+	Removed []retention.Removal `json:"removed"`
+	Freed   int64               `json:"freed"`
+}
+
+// loadStorage 读取保留策略与书签，并在后台按策略清理一次
+func (a *App) loadStorage() {
+	policy, err := retention.Load(filepath.Join(appDataDir(), retentionFile))
+	if err != nil {
+		a.log("storage").Warn("retention policy not loaded", "err", err)
+	}
+	marks, err := retention.LoadBookmarks(filepath.Join(captureDir(), bookmarksFile))
+	if err != nil {
+		a.log("storage").Warn("bookmarks not loaded", "err", err)
+		marks = map[string]bool{}
+	}
+	a.storageMutex.Lock()
+	a.retention = policy
+	a.bookmarks = marks
+	a.storageMutex.Unlock()
+	go a.cleanupStorage()
+}
+
+// storageFiles 列出受保留策略管理的抓包文件
+func (a *App) storageFiles() ([]retention.File, error) {
+	list, err := capture.List(captureDir())
+	if err != nil {
+		return nil, err
+	}
+	a.captureMutex.Lock()
+	active := a.captureID
+	a.captureMutex.Unlock()
+	a.storageMutex.Lock()
+	defer a.storageMutex.Unlock()
+	files := make([]retention.File, 0, len(list))
+	for _, info := range list {
+		f := retention.File{
+			ID: info.ID, Path: info.Path, Size: info.Size, Modified: info.Meta.Started,
+			Protected: a.bookmarks[info.ID], Active: info.ID == active,
+		}
+		if st, err := os.Stat(info.Path); err == nil {
+			f.Size, f.Modified = st.Size(), st.ModTime()
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// cleanupStorage 按保留策略删除过期或超出配额的抓包
+func (a *App) cleanupStorage() (CleanupResult, error) {
+	res := CleanupResult{Removed: []retention.Removal{}}
+	files, err := a.storageFiles()
+	if err != nil {
+		return res, err
+	}
+	a.storageMutex.Lock()
+	policy := a.retention
+	a.storageMutex.Unlock()
+	for _, r := range retention.Plan(files, policy, time.Now()) {
+		a.closeCaptureView(r.ID)
+		if err := os.Remove(r.Path); err != nil {
+			a.log("storage").Warn("capture not removed", "capture", r.ID, "err", err)
+			continue
+		}
+		res.Removed = append(res.Removed, r)
+		res.Freed += r.Size
+	}
+	if len(res.Removed) > 0 {
+		a.log("storage").Info("retention cleanup", "removed", len(res.Removed), "freed", res.Freed)
+	}
+	return res, nil
+}
+
+// GetStoragePolicy 返回抓包的保留期限与磁盘配额
+func (a *App) GetStoragePolicy() retention.Policy {
+	a.storageMutex.Lock()
+	defer a.storageMutex.Unlock()
+	return a.retention
+}
+
+// SetStoragePolicy 设置并保存保留策略，立即按新策略清理一次
+// 加书签的会话与正在写入的抓包不会被删除
+func (a *App) SetStoragePolicy(p retention.Policy) apperr.Result {
+	if err := p.Validate(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	if err := retention.Save(filepath.Join(appDataDir(), retentionFile), p); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	a.storageMutex.Lock()
+	a.retention = p
+	a.storageMutex.Unlock()
+	if _, err := a.cleanupStorage(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// GetStorageUsage 返回抓包的磁盘占用
+func (a *App) GetStorageUsage() (retention.Usage, error) {
+	files, err := a.storageFiles()
+	if err != nil {
+		return retention.Usage{}, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return retention.Summarize(files, a.GetStoragePolicy()), nil
+}
+
+// CleanupStorage 立即按保留策略清理，返回删除的抓包
+func (a *App) CleanupStorage() (CleanupResult, error) {
+	res, err := a.cleanupStorage()
+	if err != nil {
+		return res, apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return res, nil
+}
+
+// SetCaptureBookmark 为抓包加上或取消书签，加书签的会话不会被自动清理
+func (a *App) SetCaptureBookmark(id string, bookmarked bool) apperr.Result {
+	if _, e := a.captureInfo(id); e != nil {
+		return apperr.FromError(e)
+	}
+	return apperr.FromError(a.setBookmark(id, bookmarked))
+}
+
+// setBookmark 修改并保存书签
+func (a *App) setBookmark(id string, bookmarked bool) error {
+	a.storageMutex.Lock()
+	defer a.storageMutex.Unlock()
+	if a.bookmarks[id] == bookmarked {
+		return nil
+	}
+	if bookmarked {
+		a.bookmarks[id] = true
+	} else {
+		delete(a.bookmarks, id)
+	}
+	if err := retention.SaveBookmarks(filepath.Join(captureDir(), bookmarksFile), a.bookmarks); err != nil {
+		return apperr.Wrap(apperr.CodeInternal, err)
+	}
+	return nil
+}
+
+// isBookmarked 抓包是否加了书签
+func (a *App) isBookmarked(id string) bool {
+	a.storageMutex.Lock()
+	defer a.storageMutex.Unlock()
+	return a.bookmarks[id]
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/payload"
+	"serial-assistant/pkg/timesync"
+)
+
+// timeSyncFile 时间同步设置文件名 (位于应用数据目录)
+const timeSyncFile = "timesync.json"
+
+// loadTimeSync 启动时加载时间同步设置并按需开始定时发送
+func (a *App) loadTimeSync() {
+	cfg, err := timesync.Load(filepath.Join(appDataDir(), timeSyncFile))
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		a.log("timesync").Warn("time sync config not loaded", "err", err)
+		return
+	}
+	a.applyTimeSync(cfg)
+}
+
+// applyTimeSync 替换设置并重新开始定时发送
+func (a *App) applyTimeSync(cfg timesync.Config) {
+	a.timeSyncMutex.Lock()
+	defer a.timeSyncMutex.Unlock()
+	a.timeSync = cfg
+	if a.timeSyncOp != nil {
+		a.ops.Cancel(a.timeSyncOp.ID)
+		a.timeSyncOp = nil
+	}
+	if cfg.Interval() <= 0 {
+		return
+	}
+	op := a.startOperation("time-sync", 0)
+	a.timeSyncOp = op
+	go func() {
+		defer a.ops.Finish(op)
+		ticker := time.NewTicker(cfg.Interval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-op.Context().Done():
+				return
+			case <-ticker.C:
+				a.mutex.Lock()
+				connected := a.isConnected
+				a.mutex.Unlock()
+				if connected {
+					a.sendTime(op.Context(), cfg)
+				}
+			}
+		}
+	}()
+}
+
+// syncTimeOnConnect 连接建立后按设置下发时间 (调用方持有连接锁，在后台发送)
+func (a *App) syncTimeOnConnect() {
+	a.timeSyncMutex.Lock()
+	cfg := a.timeSync
+	a.timeSyncMutex.Unlock()
+	if !cfg.OnConnect {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+		a.sendTime(ctx, cfg)
+	}()
+}
+
+// sendTime 展开模板并发送，失败时发送系统消息
+func (a *App) sendTime(ctx context.Context, cfg timesync.Config) error {
+	tmpl, hex, err := cfg.Payload()
+	if err == nil {
+		var b []byte
+		if b, err = a.payloads.Preview(tmpl, hex, payload.FrameSpec{}); err == nil {
+			err = a.writeContext(ctx, b)
+		}
+	}
+	if err != nil {
+		a.bus.Publish(EventSysMsg, i18n.T("app.timesync_failed", err))
+	}
+	return err
+}
+
+// GetTimeSyncPresets 返回内置的时间下发格式
+func (a *App) GetTimeSyncPresets() []timesync.Preset {
+	return timesync.Presets
+}
+
+// GetTimeSyncConfig 返回时间同步设置
+func (a *App) GetTimeSyncConfig() timesync.Config {
+	a.timeSyncMutex.Lock()
+	defer a.timeSyncMutex.Unlock()
+	return a.timeSync
+}
+
+// SetTimeSyncConfig 设置并保存时间同步：连接后立即和/或每 intervalSec 秒向设备发送主机时间，
+// 格式为内置格式或使用 {{time.*}} / {{utc.*}} 占位符的自定义模板
+func (a *App) SetTimeSyncConfig(cfg timesync.Config) apperr.Result {
+	if err := cfg.Validate(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	if tmpl, hex, err := cfg.Payload(); err == nil {
+		if _, err := a.payloads.Preview(tmpl, hex, payload.FrameSpec{}); err != nil {
+			return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+		}
+	}
+	if err := timesync.Save(filepath.Join(appDataDir(), timeSyncFile), cfg); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	a.applyTimeSync(cfg)
+	return apperr.OK()
+}
+
+// SyncTime 立即按当前设置向设备发送主机时间
+func (a *App) SyncTime() apperr.Result {
+	cfg := a.GetTimeSyncConfig()
+	if _, _, err := cfg.Payload(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	op := a.startOperation("send", sendTimeout)
+	defer a.ops.Finish(op)
+	return apperr.FromError(a.sendTime(op.Context(), cfg))
+}
+
+// PreviewTimeSync 预览按设置 cfg 此刻会发送的内容
+func (a *App) PreviewTimeSync(cfg timesync.Config) (PayloadPreview, error) {
+	tmpl, hex, err := cfg.Payload()
+	if err != nil {
+		return PayloadPreview{}, apperr.Wrap(apperr.CodeInvalidArgument, err)
+	}
+	return a.previewExpanded(tmpl, hex, payload.FrameSpec{})
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/updater"
+)
+
+// updateSettingsFile 更新设置文件名 (位于应用数据目录)
+const updateSettingsFile = "update.json"
+
+// GetUpdateChannel 返回更新通道：stable (正式版) 或 beta (包含预发布版)
+func (a *App) GetUpdateChannel() string {
+	s, _ := updater.LoadSettings(filepath.Join(appDataDir(), updateSettingsFile))
+	return s.Channel
+}
+
+// SetUpdateChannel 设置并保存更新通道
+func (a *App) SetUpdateChannel(channel string) apperr.Result {
+	if !updater.ValidChannel(channel) {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, updater.ErrUnknownChannel))
+	}
+	if err := updater.SaveSettings(filepath.Join(appDataDir(), updateSettingsFile), updater.Settings{Channel: channel}); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// UpdateNow 检查当前通道，有新版本时下载、校验签名并替换程序后重启；已是最新版本时返回 NOT_FOUND
+func (a *App) UpdateNow() apperr.Result {
+	info, err := a.CheckForUpdates()
+	if err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeConnectFailed, err))
+	}
+	if !info.Available {
+		return apperr.FromError(apperr.New(apperr.CodeNotFound, "up to date: "+info.CurrentVersion))
+	}
+	if err := a.installUpdate(info.DownloadURL, info.SignatureURL); err != nil {
+		code := apperr.CodeInternal
+		switch {
+		case errors.Is(err, updater.ErrNoSigningKey):
+			code = apperr.CodeUnsupported
+		case errors.Is(err, updater.ErrBadSignature) || errors.Is(err, updater.ErrUnsigned):
+			code = apperr.CodePermissionDenied
+		}
+		return apperr.FromError(apperr.Wrap(code, err))
+	}
+	return apperr.OK()
+}
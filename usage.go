@@ -0,0 +1,70 @@
+package main
+
+import (
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/usage"
+)
+
+// usageFile 本地使用统计文件名 (位于应用数据目录)
+const usageFile = "usage.json"
+
+// loadUsage 打开本地使用统计并记录本次启动，返回给前端的错误按错误码计数
+func (a *App) loadUsage() {
+	r, err := usage.Open(filepath.Join(appDataDir(), usageFile))
+	if err != nil {
+		a.log("usage").Warn("usage stats not loaded", "err", err)
+		return
+	}
+	a.usage = r
+	r.Launch()
+	apperr.Observe(func(code apperr.Code) { r.Error(string(code)) })
+}
+
+// closeUsage 退出时保存统计
+func (a *App) closeUsage() {
+	apperr.Observe(nil)
+	if err := a.usage.Flush(); err != nil {
+		a.log("usage").Warn("usage stats not saved", "err", err)
+	}
+}
+
+// GetUsageStats 返回本地使用统计 (打开的会话、使用的功能、错误次数)
+// 统计只保存在本机，不会自动发送
+func (a *App) GetUsageStats() usage.Stats {
+	return a.usage.Snapshot()
+}
+
+// SetUsageStatsEnabled 开启或关闭本地使用统计，关闭时清空已有统计
+func (a *App) SetUsageStatsEnabled(enabled bool) apperr.Result {
+	if err := a.usage.SetEnabled(enabled); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// ResetUsageStats 清空本地使用统计
+func (a *App) ResetUsageStats() apperr.Result {
+	if err := a.usage.Reset(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	return apperr.OK()
+}
+
+// ExportUsageStats 将使用统计连同版本与系统信息导出为 JSON，供用户附在问题报告中
+func (a *App) ExportUsageStats(destPath string) apperr.Result {
+	rep := usage.Report{
+		Generated: time.Now(),
+		Version:   Version,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Stats:     a.usage.Snapshot(),
+	}
+	if err := usage.Export(destPath, rep); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	return apperr.OK()
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/i18n"
+	"serial-assistant/pkg/watchfolder"
+)
+
+// watchFolderFile 监视目录配置文件名 (位于应用数据目录)
+const watchFolderFile = "watchfolder.json"
+
+// WatchFolderEvent 监视目录自动发送的进度，随 EventWatchFolder 发送
+type WatchFolderEvent struct {
+	File  string `json:"file"`
+	State string `json:"state"` // sending、sent、failed
+	Size  int64  `json:"size"`
+	Error string `json:"error,omitempty"`
+}
+
+// loadWatchFolder 读取监视目录配置，启用时开始监视
+func (a *App) loadWatchFolder() {
+	c, err := watchfolder.Load(filepath.Join(appDataDir(), watchFolderFile))
+	if err != nil {
+		a.log("watchfolder").Warn("watch folder config not loaded", "err", err)
+	}
+	a.watchMutex.Lock()
+	a.watchCfg = c
+	a.watchMutex.Unlock()
+	if c.Enabled {
+		if err := c.Validate(); err != nil {
+			a.log("watchfolder").Warn("watch folder not started", "dir", c.Dir, "err", err)
+			return
+		}
+		a.startWatchFolder(c)
+	}
+}
+
+// GetWatchFolder 返回监视目录配置
+func (a *App) GetWatchFolder() watchfolder.Config {
+	a.watchMutex.Lock()
+	defer a.watchMutex.Unlock()
+	return a.watchCfg
+}
+
+// SetWatchFolder 设置并保存监视目录配置：启用后目录中新出现或被重新写入的文件在写入完成后
+// 自动发送到当前连接 (开始监视时已存在的文件不发送)，进度通过 "watch-folder" 事件通知
+func (a *App) SetWatchFolder(c watchfolder.Config) apperr.Result {
+	if err := c.Validate(); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	if err := watchfolder.Save(filepath.Join(appDataDir(), watchFolderFile), c); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInternal, err))
+	}
+	a.stopWatchFolder()
+	a.watchMutex.Lock()
+	a.watchCfg = c
+	a.watchMutex.Unlock()
+	if c.Enabled {
+		a.startWatchFolder(c)
+	}
+	return apperr.OK()
+}
+
+// startWatchFolder 在后台轮询监视目录
+func (a *App) startWatchFolder(c watchfolder.Config) {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.watchMutex.Lock()
+	a.watchCancel = cancel
+	a.watchMutex.Unlock()
+	go a.watchFolder(ctx, c)
+}
+
+// stopWatchFolder 停止监视目录，正在发送的文件随之取消
+func (a *App) stopWatchFolder() {
+	a.watchMutex.Lock()
+	defer a.watchMutex.Unlock()
+	if a.watchCancel != nil {
+		a.watchCancel()
+		a.watchCancel = nil
+	}
+}
+
+// watchFolder 轮询目录，把写入完成的文件依次发送
+func (a *App) watchFolder(ctx context.Context, c watchfolder.Config) {
+	defer a.recoverPanic("watch-folder", nil)
+	tracker := watchfolder.NewTracker(c.Settle())
+	if files, err := watchfolder.List(c.Dir, c.Pattern); err == nil {
+		tracker.Prime(files)
+	} else {
+		a.log("watchfolder").Warn("watch folder not readable", "dir", c.Dir, "err", err)
+	}
+	a.log("watchfolder").Info("watching folder", "dir", c.Dir, "pattern", c.Pattern)
+
+	ticker := time.NewTicker(c.Interval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			files, err := watchfolder.List(c.Dir, c.Pattern)
+			if err != nil {
+				continue // 目录暂时不可用 (如网络盘断开)，恢复后继续
+			}
+			for _, name := range tracker.Update(now, files) {
+				if ctx.Err() != nil {
+					return
+				}
+				a.sendWatchedFile(ctx, c, name)
+			}
+		}
+	}
+}
+
+// sendWatchedFile 发送监视目录中的一个文件，成功后按配置保留、移动或删除
+// 试运行模式下只记录将要发送的文件，文件保持原样
+func (a *App) sendWatchedFile(ctx context.Context, c watchfolder.Config, name string) {
+	var size int64
+	if st, err := os.Stat(filepath.Join(c.Dir, name)); err == nil {
+		size = st.Size()
+	}
+	if a.skipDryRun("flash", name, fmt.Sprintf("%d bytes", size)) {
+		return
+	}
+	ev := WatchFolderEvent{File: name, State: "sending"}
+	a.bus.Publish(EventWatchFolder, ev)
+
+	op := a.startOperation("watch-send", 0)
+	stop := context.AfterFunc(ctx, func() { a.ops.Cancel(op.ID) })
+	err := watchfolder.Send(op.Context(), c, name, func(b []byte) error {
+		return a.writeContext(op.Context(), b)
+	}, func(done, total int64) {
+		ev.Size = total
+		op.Report("send", done, total)
+	})
+	if err == nil {
+		err = watchfolder.Finish(c, name)
+	}
+	if err != nil {
+		op.Fail(err)
+	}
+	stop()
+	a.ops.Finish(op)
+
+	if err != nil {
+		ev.State, ev.Error = "failed", err.Error()
+		a.log("watchfolder").Warn("file not sent", "file", name, "err", err)
+		a.bus.Publish(EventSysMsg, i18n.T("app.watch_failed", name, err))
+	} else {
+		ev.State = "sent"
+		a.log("watchfolder").Info("file sent", "file", name, "bytes", ev.Size)
+		a.bus.Publish(EventSysMsg, i18n.T("app.watch_sent", name, ev.Size))
+	}
+	a.bus.Publish(EventWatchFolder, ev)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"serial-assistant/pkg/watchfolder"
+)
+
+func TestSendWatchedFileDryRun(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "app.bin"), []byte("firmware"), 0o644)
+	c := watchfolder.Config{Dir: dir, After: watchfolder.AfterDelete}
+
+	a := NewApp()
+	sub := a.bus.Subscribe(8, EventDryRun, EventWatchFolder)
+	defer sub.Close()
+	a.SetDryRun(true)
+	a.sendWatchedFile(context.Background(), c, "app.bin")
+
+	select {
+	case ev := <-sub.C:
+		entry, ok := ev.Payload.(DryRunEntry)
+		if !ok || entry.Operation != "flash" || entry.Target != "app.bin" || entry.Detail != "8 bytes" {
+			t.Fatalf("Expected a dry-run flash entry, got %s %+v", ev.Topic, ev.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("No dry-run event published")
+	}
+	select {
+	case ev := <-sub.C:
+		t.Errorf("File should not be sent in dry-run mode, got %s %+v", ev.Topic, ev.Payload)
+	default:
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app.bin")); err != nil {
+		t.Errorf("File should be left in place: %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"serial-assistant/pkg/apperr"
+	"serial-assistant/pkg/bufpool"
+	"serial-assistant/pkg/eventbus"
+	"serial-assistant/pkg/pipeline"
+	"serial-assistant/pkg/wedge"
+)
+
+// newWedge 创建键盘楔，输入失败时记录日志
+func (a *App) newWedge() *wedge.Wedge {
+	return wedge.New(func(err error) {
+		a.log("wedge").Warn("keyboard input failed", "err", err)
+	})
+}
+
+// feedWedge 将接收数据交给键盘楔
+func (a *App) feedWedge(stage *pipeline.Stage) {
+	stage.Run(func(ev eventbus.Event) {
+		buf, ok := ev.Payload.(*bufpool.Buffer)
+		if !ok {
+			return
+		}
+		a.wedge.Feed(buf.B)
+		buf.Release()
+	})
+}
+
+// GetWedgeConfig 返回键盘楔模式的配置
+func (a *App) GetWedgeConfig() wedge.Config {
+	return a.wedge.Config()
+}
+
+// SetWedgeConfig 设置键盘楔模式：启用后接收到的每一行 (可按正则过滤) 都会作为键盘输入送到当前焦点窗口
+// Linux 需要 xdotool (X11) 或 wtype (Wayland)，macOS 需要在"辅助功能"中授权
+func (a *App) SetWedgeConfig(cfg wedge.Config) apperr.Result {
+	if err := a.wedge.SetConfig(cfg); err != nil {
+		return apperr.FromError(apperr.Wrap(apperr.CodeInvalidArgument, err))
+	}
+	return apperr.OK()
+}